@@ -0,0 +1,15 @@
+// Package synkronusv1 holds the Go messages and gRPC clients generated from
+// synkronus.proto, the versioned gRPC API served by 'synkronus serve
+// --grpc-address'.
+//
+// A client authenticates with the server's API token:
+//
+//	conn, err := grpc.NewClient("localhost:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	client := synkronusv1.NewStorageServiceClient(conn)
+//	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+//	buckets, err := client.ListBuckets(ctx, &synkronusv1.ListBucketsRequest{})
+package synkronusv1
+
+// Regenerate with buf (https://buf.build) and the protoc-gen-go and
+// protoc-gen-go-grpc plugins on $PATH.
+//go:generate sh -c "cd ../.. && buf generate"
@@ -0,0 +1,1804 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: synkronus/v1/synkronus.proto
+
+// The synkronus service layer over gRPC, served by 'synkronus serve
+// --grpc-address' next to the REST API. Calls must send the server's token in
+// the "authorization" metadata as "Bearer <token>".
+
+package synkronusv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type JobStatus int32
+
+const (
+	JobStatus_JOB_STATUS_UNSPECIFIED JobStatus = 0
+	JobStatus_JOB_STATUS_RUNNING     JobStatus = 1
+	JobStatus_JOB_STATUS_SUCCEEDED   JobStatus = 2
+	JobStatus_JOB_STATUS_FAILED      JobStatus = 3
+)
+
+// Enum value maps for JobStatus.
+var (
+	JobStatus_name = map[int32]string{
+		0: "JOB_STATUS_UNSPECIFIED",
+		1: "JOB_STATUS_RUNNING",
+		2: "JOB_STATUS_SUCCEEDED",
+		3: "JOB_STATUS_FAILED",
+	}
+	JobStatus_value = map[string]int32{
+		"JOB_STATUS_UNSPECIFIED": 0,
+		"JOB_STATUS_RUNNING":     1,
+		"JOB_STATUS_SUCCEEDED":   2,
+		"JOB_STATUS_FAILED":      3,
+	}
+)
+
+func (x JobStatus) Enum() *JobStatus {
+	p := new(JobStatus)
+	*p = x
+	return p
+}
+
+func (x JobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_synkronus_v1_synkronus_proto_enumTypes[0].Descriptor()
+}
+
+func (JobStatus) Type() protoreflect.EnumType {
+	return &file_synkronus_v1_synkronus_proto_enumTypes[0]
+}
+
+func (x JobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobStatus.Descriptor instead.
+func (JobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{0}
+}
+
+// A provider that failed in a multi-provider listing; the results of the
+// others are still returned.
+type ProviderError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderError) Reset() {
+	*x = ProviderError{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderError) ProtoMessage() {}
+
+func (x *ProviderError) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderError.ProtoReflect.Descriptor instead.
+func (*ProviderError) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProviderError) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListBucketsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Provider names such as "gcp" or "aws"; empty means all configured
+	Providers     []string `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBucketsRequest) Reset() {
+	*x = ListBucketsRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBucketsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsRequest) ProtoMessage() {}
+
+func (x *ListBucketsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsRequest.ProtoReflect.Descriptor instead.
+func (*ListBucketsRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListBucketsRequest) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+type ListBucketsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*Bucket              `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Errors        []*ProviderError       `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBucketsResponse) Reset() {
+	*x = ListBucketsResponse{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBucketsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBucketsResponse) ProtoMessage() {}
+
+func (x *ListBucketsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBucketsResponse.ProtoReflect.Descriptor instead.
+func (*ListBucketsResponse) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListBucketsResponse) GetBuckets() []*Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *ListBucketsResponse) GetErrors() []*ProviderError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type DescribeBucketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeBucketRequest) Reset() {
+	*x = DescribeBucketRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeBucketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeBucketRequest) ProtoMessage() {}
+
+func (x *DescribeBucketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeBucketRequest.ProtoReflect.Descriptor instead.
+func (*DescribeBucketRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DescribeBucketRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *DescribeBucketRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+type Bucket struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Provider     string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Location     string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	LocationType string                 `protobuf:"bytes,4,opt,name=location_type,json=locationType,proto3" json:"location_type,omitempty"`
+	StorageClass string                 `protobuf:"bytes,5,opt,name=storage_class,json=storageClass,proto3" json:"storage_class,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// -1 when the usage is unknown
+	UsageBytes    int64             `protobuf:"varint,8,opt,name=usage_bytes,json=usageBytes,proto3" json:"usage_bytes,omitempty"`
+	RequesterPays bool              `protobuf:"varint,9,opt,name=requester_pays,json=requesterPays,proto3" json:"requester_pays,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// GCP only: the project the bucket was listed from
+	Project string `protobuf:"bytes,11,opt,name=project,proto3" json:"project,omitempty"`
+	// AWS only: the account the bucket was listed from via an assumed role
+	Account string `protobuf:"bytes,12,opt,name=account,proto3" json:"account,omitempty"`
+	// Set by DescribeBucket; unset when the provider did not report it
+	VersioningEnabled        *bool  `protobuf:"varint,13,opt,name=versioning_enabled,json=versioningEnabled,proto3,oneof" json:"versioning_enabled,omitempty"`
+	UniformBucketLevelAccess *bool  `protobuf:"varint,14,opt,name=uniform_bucket_level_access,json=uniformBucketLevelAccess,proto3,oneof" json:"uniform_bucket_level_access,omitempty"`
+	PublicAccessPrevention   string `protobuf:"bytes,15,opt,name=public_access_prevention,json=publicAccessPrevention,proto3" json:"public_access_prevention,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Bucket) Reset() {
+	*x = Bucket{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket) ProtoMessage() {}
+
+func (x *Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket.ProtoReflect.Descriptor instead.
+func (*Bucket) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Bucket) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Bucket) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Bucket) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Bucket) GetLocationType() string {
+	if x != nil {
+		return x.LocationType
+	}
+	return ""
+}
+
+func (x *Bucket) GetStorageClass() string {
+	if x != nil {
+		return x.StorageClass
+	}
+	return ""
+}
+
+func (x *Bucket) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Bucket) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Bucket) GetUsageBytes() int64 {
+	if x != nil {
+		return x.UsageBytes
+	}
+	return 0
+}
+
+func (x *Bucket) GetRequesterPays() bool {
+	if x != nil {
+		return x.RequesterPays
+	}
+	return false
+}
+
+func (x *Bucket) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Bucket) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Bucket) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *Bucket) GetVersioningEnabled() bool {
+	if x != nil && x.VersioningEnabled != nil {
+		return *x.VersioningEnabled
+	}
+	return false
+}
+
+func (x *Bucket) GetUniformBucketLevelAccess() bool {
+	if x != nil && x.UniformBucketLevelAccess != nil {
+		return *x.UniformBucketLevelAccess
+	}
+	return false
+}
+
+func (x *Bucket) GetPublicAccessPrevention() string {
+	if x != nil {
+		return x.PublicAccessPrevention
+	}
+	return ""
+}
+
+type ListObjectsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Prefix        string                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListObjectsRequest) Reset() {
+	*x = ListObjectsRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListObjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListObjectsRequest) ProtoMessage() {}
+
+func (x *ListObjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListObjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListObjectsRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListObjectsRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ListObjectsRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *ListObjectsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type ListObjectsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Bucket         string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Prefix         string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Objects        []*Object              `protobuf:"bytes,3,rep,name=objects,proto3" json:"objects,omitempty"`
+	CommonPrefixes []string               `protobuf:"bytes,4,rep,name=common_prefixes,json=commonPrefixes,proto3" json:"common_prefixes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListObjectsResponse) Reset() {
+	*x = ListObjectsResponse{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListObjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListObjectsResponse) ProtoMessage() {}
+
+func (x *ListObjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListObjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListObjectsResponse) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListObjectsResponse) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *ListObjectsResponse) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ListObjectsResponse) GetObjects() []*Object {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *ListObjectsResponse) GetCommonPrefixes() []string {
+	if x != nil {
+		return x.CommonPrefixes
+	}
+	return nil
+}
+
+type DescribeObjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Key           string                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeObjectRequest) Reset() {
+	*x = DescribeObjectRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeObjectRequest) ProtoMessage() {}
+
+func (x *DescribeObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeObjectRequest.ProtoReflect.Descriptor instead.
+func (*DescribeObjectRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DescribeObjectRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *DescribeObjectRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *DescribeObjectRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type Object struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Key          string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Bucket       string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Provider     string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Size         int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	StorageClass string                 `protobuf:"bytes,5,opt,name=storage_class,json=storageClass,proto3" json:"storage_class,omitempty"`
+	LastModified *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Etag         string                 `protobuf:"bytes,7,opt,name=etag,proto3" json:"etag,omitempty"`
+	ContentType  string                 `protobuf:"bytes,8,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// Base64-encoded MD5 checksum, when the provider reports one
+	Md5Hash string `protobuf:"bytes,9,opt,name=md5_hash,json=md5Hash,proto3" json:"md5_hash,omitempty"`
+	// GCP only
+	Generation int64 `protobuf:"varint,10,opt,name=generation,proto3" json:"generation,omitempty"`
+	// AWS only
+	VersionId     string            `protobuf:"bytes,11,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	Metadata      map[string]string `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Object) Reset() {
+	*x = Object{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Object) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Object) ProtoMessage() {}
+
+func (x *Object) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Object.ProtoReflect.Descriptor instead.
+func (*Object) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Object) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Object) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *Object) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Object) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Object) GetStorageClass() string {
+	if x != nil {
+		return x.StorageClass
+	}
+	return ""
+}
+
+func (x *Object) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+func (x *Object) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *Object) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Object) GetMd5Hash() string {
+	if x != nil {
+		return x.Md5Hash
+	}
+	return ""
+}
+
+func (x *Object) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *Object) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *Object) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ListInstancesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Provider names such as "gcp"; empty means all configured
+	Providers     []string `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstancesRequest) Reset() {
+	*x = ListInstancesRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstancesRequest) ProtoMessage() {}
+
+func (x *ListInstancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstancesRequest.ProtoReflect.Descriptor instead.
+func (*ListInstancesRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListInstancesRequest) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+type ListInstancesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instances     []*Instance            `protobuf:"bytes,1,rep,name=instances,proto3" json:"instances,omitempty"`
+	Errors        []*ProviderError       `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstancesResponse) Reset() {
+	*x = ListInstancesResponse{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstancesResponse) ProtoMessage() {}
+
+func (x *ListInstancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstancesResponse.ProtoReflect.Descriptor instead.
+func (*ListInstancesResponse) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListInstancesResponse) GetInstances() []*Instance {
+	if x != nil {
+		return x.Instances
+	}
+	return nil
+}
+
+func (x *ListInstancesResponse) GetErrors() []*ProviderError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type DescribeInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Instance      string                 `protobuf:"bytes,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeInstanceRequest) Reset() {
+	*x = DescribeInstanceRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeInstanceRequest) ProtoMessage() {}
+
+func (x *DescribeInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeInstanceRequest.ProtoReflect.Descriptor instead.
+func (*DescribeInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DescribeInstanceRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *DescribeInstanceRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+type Instance struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Provider        string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Region          string                 `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	DatabaseVersion string                 `protobuf:"bytes,4,opt,name=database_version,json=databaseVersion,proto3" json:"database_version,omitempty"`
+	Tier            string                 `protobuf:"bytes,5,opt,name=tier,proto3" json:"tier,omitempty"`
+	State           string                 `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	PrimaryAddress  string                 `protobuf:"bytes,7,opt,name=primary_address,json=primaryAddress,proto3" json:"primary_address,omitempty"`
+	StorageSizeGb   int64                  `protobuf:"varint,8,opt,name=storage_size_gb,json=storageSizeGb,proto3" json:"storage_size_gb,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// GCP only
+	Project string `protobuf:"bytes,10,opt,name=project,proto3" json:"project,omitempty"`
+	// GCP only
+	ConnectionName string            `protobuf:"bytes,11,opt,name=connection_name,json=connectionName,proto3" json:"connection_name,omitempty"`
+	Labels         map[string]string `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Instance) Reset() {
+	*x = Instance{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Instance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Instance) ProtoMessage() {}
+
+func (x *Instance) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Instance.ProtoReflect.Descriptor instead.
+func (*Instance) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Instance) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Instance) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Instance) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Instance) GetDatabaseVersion() string {
+	if x != nil {
+		return x.DatabaseVersion
+	}
+	return ""
+}
+
+func (x *Instance) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *Instance) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Instance) GetPrimaryAddress() string {
+	if x != nil {
+		return x.PrimaryAddress
+	}
+	return ""
+}
+
+func (x *Instance) GetStorageSizeGb() int64 {
+	if x != nil {
+		return x.StorageSizeGb
+	}
+	return 0
+}
+
+func (x *Instance) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Instance) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Instance) GetConnectionName() string {
+	if x != nil {
+		return x.ConnectionName
+	}
+	return ""
+}
+
+func (x *Instance) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// One side of a sync: the objects under prefix in a bucket.
+type SyncTarget struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Bucket        string                 `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Prefix        string                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncTarget) Reset() {
+	*x = SyncTarget{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncTarget) ProtoMessage() {}
+
+func (x *SyncTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncTarget.ProtoReflect.Descriptor instead.
+func (*SyncTarget) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SyncTarget) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *SyncTarget) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *SyncTarget) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type StartSyncRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      *SyncTarget            `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination *SyncTarget            `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	// Glob patterns matched against the keys relative to the source prefix, or
+	// against their last element when a pattern has no "/"
+	Include       []string `protobuf:"bytes,3,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude       []string `protobuf:"bytes,4,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartSyncRequest) Reset() {
+	*x = StartSyncRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSyncRequest) ProtoMessage() {}
+
+func (x *StartSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartSyncRequest.ProtoReflect.Descriptor instead.
+func (*StartSyncRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StartSyncRequest) GetSource() *SyncTarget {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *StartSyncRequest) GetDestination() *SyncTarget {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
+func (x *StartSyncRequest) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *StartSyncRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+type SyncFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncFailure) Reset() {
+	*x = SyncFailure{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncFailure) ProtoMessage() {}
+
+func (x *SyncFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncFailure.ProtoReflect.Descriptor instead.
+func (*SyncFailure) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SyncFailure) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SyncFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SyncResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Copied        int64                  `protobuf:"varint,1,opt,name=copied,proto3" json:"copied,omitempty"`
+	Skipped       int64                  `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Bytes         int64                  `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Failed        []*SyncFailure         `protobuf:"bytes,4,rep,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncResult) Reset() {
+	*x = SyncResult{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncResult) ProtoMessage() {}
+
+func (x *SyncResult) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncResult.ProtoReflect.Descriptor instead.
+func (*SyncResult) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SyncResult) GetCopied() int64 {
+	if x != nil {
+		return x.Copied
+	}
+	return 0
+}
+
+func (x *SyncResult) GetSkipped() int64 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *SyncResult) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *SyncResult) GetFailed() []*SyncFailure {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+type Job struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type   string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Status JobStatus              `protobuf:"varint,3,opt,name=status,proto3,enum=synkronus.v1.JobStatus" json:"status,omitempty"`
+	Sync   *StartSyncRequest      `protobuf:"bytes,4,opt,name=sync,proto3" json:"sync,omitempty"`
+	// Set once the job has finished
+	Result     *SyncResult            `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	Error      string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	StartedAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	// The scheduled job that started this run, if any
+	Schedule      string `protobuf:"bytes,9,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Job) GetStatus() JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return JobStatus_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *Job) GetSync() *StartSyncRequest {
+	if x != nil {
+		return x.Sync
+	}
+	return nil
+}
+
+func (x *Job) GetResult() *SyncResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Job) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *Job) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+type GetJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{19}
+}
+
+type ListJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*Job                 `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_synkronus_v1_synkronus_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_synkronus_v1_synkronus_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListJobsResponse) GetJobs() []*Job {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+var File_synkronus_v1_synkronus_proto protoreflect.FileDescriptor
+
+const file_synkronus_v1_synkronus_proto_rawDesc = "" +
+	"\n" +
+	"\x1csynkronus/v1/synkronus.proto\x12\fsynkronus.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"A\n" +
+	"\rProviderError\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"2\n" +
+	"\x12ListBucketsRequest\x12\x1c\n" +
+	"\tproviders\x18\x01 \x03(\tR\tproviders\"z\n" +
+	"\x13ListBucketsResponse\x12.\n" +
+	"\abuckets\x18\x01 \x03(\v2\x14.synkronus.v1.BucketR\abuckets\x123\n" +
+	"\x06errors\x18\x02 \x03(\v2\x1b.synkronus.v1.ProviderErrorR\x06errors\"K\n" +
+	"\x15DescribeBucketRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\"\xee\x05\n" +
+	"\x06Bucket\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12#\n" +
+	"\rlocation_type\x18\x04 \x01(\tR\flocationType\x12#\n" +
+	"\rstorage_class\x18\x05 \x01(\tR\fstorageClass\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\vusage_bytes\x18\b \x01(\x03R\n" +
+	"usageBytes\x12%\n" +
+	"\x0erequester_pays\x18\t \x01(\bR\rrequesterPays\x128\n" +
+	"\x06labels\x18\n" +
+	" \x03(\v2 .synkronus.v1.Bucket.LabelsEntryR\x06labels\x12\x18\n" +
+	"\aproject\x18\v \x01(\tR\aproject\x12\x18\n" +
+	"\aaccount\x18\f \x01(\tR\aaccount\x122\n" +
+	"\x12versioning_enabled\x18\r \x01(\bH\x00R\x11versioningEnabled\x88\x01\x01\x12B\n" +
+	"\x1buniform_bucket_level_access\x18\x0e \x01(\bH\x01R\x18uniformBucketLevelAccess\x88\x01\x01\x128\n" +
+	"\x18public_access_prevention\x18\x0f \x01(\tR\x16publicAccessPrevention\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x15\n" +
+	"\x13_versioning_enabledB\x1e\n" +
+	"\x1c_uniform_bucket_level_access\"`\n" +
+	"\x12ListObjectsRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\"\x9e\x01\n" +
+	"\x13ListObjectsResponse\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x12.\n" +
+	"\aobjects\x18\x03 \x03(\v2\x14.synkronus.v1.ObjectR\aobjects\x12'\n" +
+	"\x0fcommon_prefixes\x18\x04 \x03(\tR\x0ecommonPrefixes\"]\n" +
+	"\x15DescribeObjectRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\"\xd6\x03\n" +
+	"\x06Object\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12#\n" +
+	"\rstorage_class\x18\x05 \x01(\tR\fstorageClass\x12?\n" +
+	"\rlast_modified\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\x12\x12\n" +
+	"\x04etag\x18\a \x01(\tR\x04etag\x12!\n" +
+	"\fcontent_type\x18\b \x01(\tR\vcontentType\x12\x19\n" +
+	"\bmd5_hash\x18\t \x01(\tR\amd5Hash\x12\x1e\n" +
+	"\n" +
+	"generation\x18\n" +
+	" \x01(\x03R\n" +
+	"generation\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\v \x01(\tR\tversionId\x12>\n" +
+	"\bmetadata\x18\f \x03(\v2\".synkronus.v1.Object.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"4\n" +
+	"\x14ListInstancesRequest\x12\x1c\n" +
+	"\tproviders\x18\x01 \x03(\tR\tproviders\"\x82\x01\n" +
+	"\x15ListInstancesResponse\x124\n" +
+	"\tinstances\x18\x01 \x03(\v2\x16.synkronus.v1.InstanceR\tinstances\x123\n" +
+	"\x06errors\x18\x02 \x03(\v2\x1b.synkronus.v1.ProviderErrorR\x06errors\"Q\n" +
+	"\x17DescribeInstanceRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x1a\n" +
+	"\binstance\x18\x02 \x01(\tR\binstance\"\xed\x03\n" +
+	"\bInstance\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06region\x18\x03 \x01(\tR\x06region\x12)\n" +
+	"\x10database_version\x18\x04 \x01(\tR\x0fdatabaseVersion\x12\x12\n" +
+	"\x04tier\x18\x05 \x01(\tR\x04tier\x12\x14\n" +
+	"\x05state\x18\x06 \x01(\tR\x05state\x12'\n" +
+	"\x0fprimary_address\x18\a \x01(\tR\x0eprimaryAddress\x12&\n" +
+	"\x0fstorage_size_gb\x18\b \x01(\x03R\rstorageSizeGb\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x18\n" +
+	"\aproject\x18\n" +
+	" \x01(\tR\aproject\x12'\n" +
+	"\x0fconnection_name\x18\v \x01(\tR\x0econnectionName\x12:\n" +
+	"\x06labels\x18\f \x03(\v2\".synkronus.v1.Instance.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"X\n" +
+	"\n" +
+	"SyncTarget\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06bucket\x18\x02 \x01(\tR\x06bucket\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\"\xb4\x01\n" +
+	"\x10StartSyncRequest\x120\n" +
+	"\x06source\x18\x01 \x01(\v2\x18.synkronus.v1.SyncTargetR\x06source\x12:\n" +
+	"\vdestination\x18\x02 \x01(\v2\x18.synkronus.v1.SyncTargetR\vdestination\x12\x18\n" +
+	"\ainclude\x18\x03 \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\x04 \x03(\tR\aexclude\"5\n" +
+	"\vSyncFailure\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x87\x01\n" +
+	"\n" +
+	"SyncResult\x12\x16\n" +
+	"\x06copied\x18\x01 \x01(\x03R\x06copied\x12\x18\n" +
+	"\askipped\x18\x02 \x01(\x03R\askipped\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x03R\x05bytes\x121\n" +
+	"\x06failed\x18\x04 \x03(\v2\x19.synkronus.v1.SyncFailureR\x06failed\"\xea\x02\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12/\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x17.synkronus.v1.JobStatusR\x06status\x122\n" +
+	"\x04sync\x18\x04 \x01(\v2\x1e.synkronus.v1.StartSyncRequestR\x04sync\x120\n" +
+	"\x06result\x18\x05 \x01(\v2\x18.synkronus.v1.SyncResultR\x06result\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"started_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12\x1a\n" +
+	"\bschedule\x18\t \x01(\tR\bschedule\"\x1f\n" +
+	"\rGetJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x11\n" +
+	"\x0fListJobsRequest\"9\n" +
+	"\x10ListJobsResponse\x12%\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x11.synkronus.v1.JobR\x04jobs*p\n" +
+	"\tJobStatus\x12\x1a\n" +
+	"\x16JOB_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12JOB_STATUS_RUNNING\x10\x01\x12\x18\n" +
+	"\x14JOB_STATUS_SUCCEEDED\x10\x02\x12\x15\n" +
+	"\x11JOB_STATUS_FAILED\x10\x032\xd2\x02\n" +
+	"\x0eStorageService\x12R\n" +
+	"\vListBuckets\x12 .synkronus.v1.ListBucketsRequest\x1a!.synkronus.v1.ListBucketsResponse\x12K\n" +
+	"\x0eDescribeBucket\x12#.synkronus.v1.DescribeBucketRequest\x1a\x14.synkronus.v1.Bucket\x12R\n" +
+	"\vListObjects\x12 .synkronus.v1.ListObjectsRequest\x1a!.synkronus.v1.ListObjectsResponse\x12K\n" +
+	"\x0eDescribeObject\x12#.synkronus.v1.DescribeObjectRequest\x1a\x14.synkronus.v1.Object2\xb9\x01\n" +
+	"\n" +
+	"SqlService\x12X\n" +
+	"\rListInstances\x12\".synkronus.v1.ListInstancesRequest\x1a#.synkronus.v1.ListInstancesResponse\x12Q\n" +
+	"\x10DescribeInstance\x12%.synkronus.v1.DescribeInstanceRequest\x1a\x16.synkronus.v1.Instance2\xd1\x01\n" +
+	"\n" +
+	"JobService\x12>\n" +
+	"\tStartSync\x12\x1e.synkronus.v1.StartSyncRequest\x1a\x11.synkronus.v1.Job\x128\n" +
+	"\x06GetJob\x12\x1b.synkronus.v1.GetJobRequest\x1a\x11.synkronus.v1.Job\x12I\n" +
+	"\bListJobs\x12\x1d.synkronus.v1.ListJobsRequest\x1a\x1e.synkronus.v1.ListJobsResponseB<Z:github.com/jkleinne/synkronus/api/synkronus/v1;synkronusv1b\x06proto3"
+
+var (
+	file_synkronus_v1_synkronus_proto_rawDescOnce sync.Once
+	file_synkronus_v1_synkronus_proto_rawDescData []byte
+)
+
+func file_synkronus_v1_synkronus_proto_rawDescGZIP() []byte {
+	file_synkronus_v1_synkronus_proto_rawDescOnce.Do(func() {
+		file_synkronus_v1_synkronus_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_synkronus_v1_synkronus_proto_rawDesc), len(file_synkronus_v1_synkronus_proto_rawDesc)))
+	})
+	return file_synkronus_v1_synkronus_proto_rawDescData
+}
+
+var file_synkronus_v1_synkronus_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_synkronus_v1_synkronus_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_synkronus_v1_synkronus_proto_goTypes = []any{
+	(JobStatus)(0),                  // 0: synkronus.v1.JobStatus
+	(*ProviderError)(nil),           // 1: synkronus.v1.ProviderError
+	(*ListBucketsRequest)(nil),      // 2: synkronus.v1.ListBucketsRequest
+	(*ListBucketsResponse)(nil),     // 3: synkronus.v1.ListBucketsResponse
+	(*DescribeBucketRequest)(nil),   // 4: synkronus.v1.DescribeBucketRequest
+	(*Bucket)(nil),                  // 5: synkronus.v1.Bucket
+	(*ListObjectsRequest)(nil),      // 6: synkronus.v1.ListObjectsRequest
+	(*ListObjectsResponse)(nil),     // 7: synkronus.v1.ListObjectsResponse
+	(*DescribeObjectRequest)(nil),   // 8: synkronus.v1.DescribeObjectRequest
+	(*Object)(nil),                  // 9: synkronus.v1.Object
+	(*ListInstancesRequest)(nil),    // 10: synkronus.v1.ListInstancesRequest
+	(*ListInstancesResponse)(nil),   // 11: synkronus.v1.ListInstancesResponse
+	(*DescribeInstanceRequest)(nil), // 12: synkronus.v1.DescribeInstanceRequest
+	(*Instance)(nil),                // 13: synkronus.v1.Instance
+	(*SyncTarget)(nil),              // 14: synkronus.v1.SyncTarget
+	(*StartSyncRequest)(nil),        // 15: synkronus.v1.StartSyncRequest
+	(*SyncFailure)(nil),             // 16: synkronus.v1.SyncFailure
+	(*SyncResult)(nil),              // 17: synkronus.v1.SyncResult
+	(*Job)(nil),                     // 18: synkronus.v1.Job
+	(*GetJobRequest)(nil),           // 19: synkronus.v1.GetJobRequest
+	(*ListJobsRequest)(nil),         // 20: synkronus.v1.ListJobsRequest
+	(*ListJobsResponse)(nil),        // 21: synkronus.v1.ListJobsResponse
+	nil,                             // 22: synkronus.v1.Bucket.LabelsEntry
+	nil,                             // 23: synkronus.v1.Object.MetadataEntry
+	nil,                             // 24: synkronus.v1.Instance.LabelsEntry
+	(*timestamppb.Timestamp)(nil),   // 25: google.protobuf.Timestamp
+}
+var file_synkronus_v1_synkronus_proto_depIdxs = []int32{
+	5,  // 0: synkronus.v1.ListBucketsResponse.buckets:type_name -> synkronus.v1.Bucket
+	1,  // 1: synkronus.v1.ListBucketsResponse.errors:type_name -> synkronus.v1.ProviderError
+	25, // 2: synkronus.v1.Bucket.created_at:type_name -> google.protobuf.Timestamp
+	25, // 3: synkronus.v1.Bucket.updated_at:type_name -> google.protobuf.Timestamp
+	22, // 4: synkronus.v1.Bucket.labels:type_name -> synkronus.v1.Bucket.LabelsEntry
+	9,  // 5: synkronus.v1.ListObjectsResponse.objects:type_name -> synkronus.v1.Object
+	25, // 6: synkronus.v1.Object.last_modified:type_name -> google.protobuf.Timestamp
+	23, // 7: synkronus.v1.Object.metadata:type_name -> synkronus.v1.Object.MetadataEntry
+	13, // 8: synkronus.v1.ListInstancesResponse.instances:type_name -> synkronus.v1.Instance
+	1,  // 9: synkronus.v1.ListInstancesResponse.errors:type_name -> synkronus.v1.ProviderError
+	25, // 10: synkronus.v1.Instance.created_at:type_name -> google.protobuf.Timestamp
+	24, // 11: synkronus.v1.Instance.labels:type_name -> synkronus.v1.Instance.LabelsEntry
+	14, // 12: synkronus.v1.StartSyncRequest.source:type_name -> synkronus.v1.SyncTarget
+	14, // 13: synkronus.v1.StartSyncRequest.destination:type_name -> synkronus.v1.SyncTarget
+	16, // 14: synkronus.v1.SyncResult.failed:type_name -> synkronus.v1.SyncFailure
+	0,  // 15: synkronus.v1.Job.status:type_name -> synkronus.v1.JobStatus
+	15, // 16: synkronus.v1.Job.sync:type_name -> synkronus.v1.StartSyncRequest
+	17, // 17: synkronus.v1.Job.result:type_name -> synkronus.v1.SyncResult
+	25, // 18: synkronus.v1.Job.started_at:type_name -> google.protobuf.Timestamp
+	25, // 19: synkronus.v1.Job.finished_at:type_name -> google.protobuf.Timestamp
+	18, // 20: synkronus.v1.ListJobsResponse.jobs:type_name -> synkronus.v1.Job
+	2,  // 21: synkronus.v1.StorageService.ListBuckets:input_type -> synkronus.v1.ListBucketsRequest
+	4,  // 22: synkronus.v1.StorageService.DescribeBucket:input_type -> synkronus.v1.DescribeBucketRequest
+	6,  // 23: synkronus.v1.StorageService.ListObjects:input_type -> synkronus.v1.ListObjectsRequest
+	8,  // 24: synkronus.v1.StorageService.DescribeObject:input_type -> synkronus.v1.DescribeObjectRequest
+	10, // 25: synkronus.v1.SqlService.ListInstances:input_type -> synkronus.v1.ListInstancesRequest
+	12, // 26: synkronus.v1.SqlService.DescribeInstance:input_type -> synkronus.v1.DescribeInstanceRequest
+	15, // 27: synkronus.v1.JobService.StartSync:input_type -> synkronus.v1.StartSyncRequest
+	19, // 28: synkronus.v1.JobService.GetJob:input_type -> synkronus.v1.GetJobRequest
+	20, // 29: synkronus.v1.JobService.ListJobs:input_type -> synkronus.v1.ListJobsRequest
+	3,  // 30: synkronus.v1.StorageService.ListBuckets:output_type -> synkronus.v1.ListBucketsResponse
+	5,  // 31: synkronus.v1.StorageService.DescribeBucket:output_type -> synkronus.v1.Bucket
+	7,  // 32: synkronus.v1.StorageService.ListObjects:output_type -> synkronus.v1.ListObjectsResponse
+	9,  // 33: synkronus.v1.StorageService.DescribeObject:output_type -> synkronus.v1.Object
+	11, // 34: synkronus.v1.SqlService.ListInstances:output_type -> synkronus.v1.ListInstancesResponse
+	13, // 35: synkronus.v1.SqlService.DescribeInstance:output_type -> synkronus.v1.Instance
+	18, // 36: synkronus.v1.JobService.StartSync:output_type -> synkronus.v1.Job
+	18, // 37: synkronus.v1.JobService.GetJob:output_type -> synkronus.v1.Job
+	21, // 38: synkronus.v1.JobService.ListJobs:output_type -> synkronus.v1.ListJobsResponse
+	30, // [30:39] is the sub-list for method output_type
+	21, // [21:30] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_synkronus_v1_synkronus_proto_init() }
+func file_synkronus_v1_synkronus_proto_init() {
+	if File_synkronus_v1_synkronus_proto != nil {
+		return
+	}
+	file_synkronus_v1_synkronus_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_synkronus_v1_synkronus_proto_rawDesc), len(file_synkronus_v1_synkronus_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_synkronus_v1_synkronus_proto_goTypes,
+		DependencyIndexes: file_synkronus_v1_synkronus_proto_depIdxs,
+		EnumInfos:         file_synkronus_v1_synkronus_proto_enumTypes,
+		MessageInfos:      file_synkronus_v1_synkronus_proto_msgTypes,
+	}.Build()
+	File_synkronus_v1_synkronus_proto = out.File
+	file_synkronus_v1_synkronus_proto_goTypes = nil
+	file_synkronus_v1_synkronus_proto_depIdxs = nil
+}
@@ -2,16 +2,30 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/audit"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/logger"
+	"github.com/jkleinne/synkronus/internal/network"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/retry"
+	"github.com/jkleinne/synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
+	"io"
 	"log/slog"
 	"os"
-	"synkronus/internal/config"
-	"synkronus/internal/logger"
-	"synkronus/internal/output"
-	"synkronus/internal/provider/factory"
-	"synkronus/internal/service"
-	"synkronus/internal/ui/prompt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Defines a specific type for the context key to avoid collisions
@@ -19,6 +33,9 @@ type contextKey string
 
 const appContextKey contextKey = "appContainer"
 
+// Name of the cache holding list and describe results when cache.enabled is set
+const responseCacheName = "responses"
+
 // appContainer holds all the shared dependencies for the application
 // This includes configuration, service clients, output format, and the logger
 type appContainer struct {
@@ -30,32 +47,133 @@ type appContainer struct {
 	OutputFormat    output.Format
 	Prompter        prompt.Prompter
 	Logger          *slog.Logger
+	// DryRun makes mutating commands report the change instead of making it
+	DryRun bool
+	// PartialResults is set by multi-provider commands that showed the results
+	// of some providers after others failed; the command then exits with exitPartial
+	PartialResults bool
+
+	// Releases the command's --timeout deadline, if one was set
+	cancelTimeout context.CancelFunc
+	// The log file from --log-file or log.file; nil when logging to stderr
+	logFile *logger.File
+	// Flushes and stops trace export; a no-op unless SYNKRONUS_OTEL_ENDPOINT is set
+	shutdownTracing func() error
+	// Spans the whole command; nil until StartCommandSpan
+	commandSpan trace.Span
 }
 
-// Creates and initializes a new application container based on the debug mode and output format settings
-func newApp(debugMode bool, outputFormat output.Format) (*appContainer, error) {
-	// 1. Initialize the logger first, as it's required by other components
-	logLevel := slog.LevelInfo
-	if debugMode {
-		logLevel = slog.LevelDebug
-	}
-	log := logger.NewLogger(logLevel)
+// Global flags that shape the application container
+type appOptions struct {
+	Debug bool
+	// Profile overrides the profile persisted in the config file when set
+	Profile string
+	// Impersonate overrides gcp.impersonate_service_account when set
+	Impersonate string
+	// LogFile overrides log.file when set
+	LogFile string
+	// LogFormat overrides log.format when set
+	LogFormat string
+	// DebugHTTP logs the provider API requests and responses
+	DebugHTTP bool
+	// Demo swaps the configured providers for the in-memory mock provider
+	Demo bool
+	// ConfigManager is used instead of reading the config again when set
+	ConfigManager *config.ConfigManager
+}
 
-	// 2. Load configuration
-	cfgManager, err := config.NewConfigManager()
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize config manager: %w", err)
+// Creates and initializes a new application container from the global flags. The output
+// format is set by the caller once config defaults have been applied to the flags.
+func newApp(opts appOptions) (*appContainer, error) {
+	// 1. Load configuration
+	cfgManager := opts.ConfigManager
+	if cfgManager == nil {
+		var err error
+		if cfgManager, err = config.NewConfigManager(); err != nil {
+			return nil, fmt.Errorf("failed to initialize config manager: %w", err)
+		}
+	}
+	if opts.Profile != "" {
+		cfgManager.SetProfileOverride(opts.Profile)
+	}
+	if opts.Impersonate != "" {
+		if err := cfgManager.SetImpersonationOverride(opts.Impersonate); err != nil {
+			return nil, err
+		}
 	}
 
 	cfg, err := cfgManager.LoadConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
+	if opts.Demo {
+		cfg = demoConfig(cfg)
+	}
+	if err := network.Apply(cfg.Network); err != nil {
+		return nil, fmt.Errorf("failed to apply network settings: %w", err)
+	}
+
+	// 2. Initialize the logger, as it's required by the other components
+	logLevel := slog.LevelInfo
+	if opts.Debug {
+		logLevel = slog.LevelDebug
+	}
+	logFile, logFormatName := opts.LogFile, opts.LogFormat
+	if cfg.Log != nil {
+		logFile = cmp.Or(logFile, cfg.Log.File)
+		logFormatName = cmp.Or(logFormatName, cfg.Log.Format)
+	}
+	logFormat, err := logger.ParseFormat(logFormatName)
+	if err != nil {
+		return nil, &usageError{err: err}
+	}
+	var logWriter io.Writer = os.Stderr
+	var openedLogFile *logger.File
+	if logFile != "" {
+		if openedLogFile, err = logger.OpenFile(logFile); err != nil {
+			return nil, err
+		}
+		logWriter = openedLogFile
+	}
+	log := logger.NewLogger(logLevel, logWriter, logFormat)
+	var httpLogger *slog.Logger
+	if opts.DebugHTTP {
+		httpLogger = log.With("component", "http")
+	}
+	network.SetDebugHTTPLogger(httpLogger)
+	shutdownTracing, err := telemetry.Setup(context.Background(), log)
+	if err != nil {
+		return nil, err
+	}
 
 	// 3. Initialize factories and services
 	providerFactory := factory.NewFactory(cfg, log)
 	storageService := service.NewStorageService(providerFactory, log)
 	sqlService := service.NewSqlService(providerFactory, log)
+	if cfg.Cache != nil && cfg.Cache.Enabled {
+		responses, err := cache.Open(responseCacheName, cfg.Cache.ResponseTTL())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open response cache: %w", err)
+		}
+		// Results depend on the provider settings, so each profile gets its own entries
+		responses = responses.Scoped(responseCacheScope(cfg))
+		storageService.UseResponseCache(responses)
+		sqlService.UseResponseCache(responses)
+	}
+	// Demo changes vanish on exit, so they are not worth auditing
+	if opts.Demo {
+		log.Debug("Demo mode: using the mock provider")
+	} else if auditLog, err := audit.Open(cfgManager.ActiveProfile()); err == nil {
+		storageService.UseAuditLog(auditLog)
+	} else {
+		log.Warn("Audit log unavailable; changes will not be recorded", "error", err)
+	}
+	if cfg.Retry != nil && cfg.Retry.MaxRetries != nil {
+		storageService.UseRetryPolicy(retry.WithMaxRetries(*cfg.Retry.MaxRetries))
+		sqlService.UseRetryPolicy(retry.WithMaxRetries(*cfg.Retry.MaxRetries))
+	}
+	storageService.UseFanOutLimits(fanOutLimits(cfg))
+	sqlService.UseFanOutLimits(fanOutLimits(cfg))
 
 	// 4. Initialize UI components
 	prompter := prompt.NewStandardPrompter(os.Stdin, os.Stdout)
@@ -66,17 +184,140 @@ func newApp(debugMode bool, outputFormat output.Format) (*appContainer, error) {
 		ProviderFactory: providerFactory,
 		StorageService:  storageService,
 		SqlService:      sqlService,
-		OutputFormat:    outputFormat,
+		OutputFormat:    output.FormatTable,
 		Prompter:        prompter,
 		Logger:          log,
+		logFile:         openedLogFile,
+		shutdownTracing: shutdownTracing,
 	}, nil
 }
 
+// demoConfig returns a copy of cfg for --demo, with the mock provider in place of
+// the configured ones so no real account is touched. Provider defaults are dropped
+// since they name real providers, and so is the response cache, since the mock
+// data changes with every run's edits.
+func demoConfig(cfg *config.Config) *config.Config {
+	demo := *cfg
+	demo.GCP, demo.AWS = nil, nil
+	demo.Mock = &config.MockConfig{Enabled: true}
+	demo.Cache = nil
+	if cfg.Defaults != nil {
+		defaults := *cfg.Defaults
+		defaults.Provider, defaults.Providers = "", nil
+		demo.Defaults = &defaults
+	}
+	return &demo
+}
+
+// Overrides retry.max_retries for the services and the provider clients they
+// create, e.g. from --max-retries. It must be called before any provider
+// client is created.
+func (a *appContainer) SetMaxRetries(n int) {
+	retryCfg := config.RetryConfig{}
+	if a.Config.Retry != nil {
+		retryCfg = *a.Config.Retry
+	}
+	retryCfg.MaxRetries = &n
+	a.Config.Retry = &retryCfg
+	a.StorageService.UseRetryPolicy(retry.WithMaxRetries(n))
+	a.SqlService.UseRetryPolicy(retry.WithMaxRetries(n))
+}
+
+// Overrides fanout.provider_timeout for the services, e.g. from --provider-timeout
+func (a *appContainer) SetProviderTimeout(d time.Duration) {
+	limits := fanOutLimits(a.Config)
+	limits.ProviderTimeout = d
+	a.StorageService.UseFanOutLimits(limits)
+	a.SqlService.UseFanOutLimits(limits)
+}
+
+// Reads the limits of multi-provider calls from the fanout config block
+func fanOutLimits(cfg *config.Config) service.FanOutLimits {
+	if cfg.FanOut == nil {
+		return service.FanOutLimits{}
+	}
+	return service.FanOutLimits{ProviderTimeout: cfg.FanOut.Timeout(), Concurrency: cfg.FanOut.Concurrency}
+}
+
+// Identifies the provider settings results were fetched with, so switching profile or
+// credentials never serves another account's cached results.
+func responseCacheScope(cfg *config.Config) string {
+	data, _ := json.Marshal(struct {
+		GCP *config.GCPConfig
+		AWS *config.AWSConfig
+	}{cfg.GCP, cfg.AWS})
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Closes the provider clients the services created during the command. Close errors
+// are only logged, since the command's own result has already been decided.
+func (a *appContainer) Shutdown() {
+	if a.cancelTimeout != nil {
+		a.cancelTimeout()
+	}
+	if err := errors.Join(a.StorageService.Shutdown(), a.SqlService.Shutdown()); err != nil {
+		a.Logger.Debug("Failed to close provider clients", "error", err)
+	}
+	if a.shutdownTracing != nil {
+		if err := a.shutdownTracing(); err != nil {
+			a.Logger.Debug("Failed to flush traces", "error", err)
+		}
+	}
+	// Last, since closing the clients may log
+	if a.logFile != nil {
+		a.logFile.Close()
+	}
+}
+
+// Returns ctx carrying a span for the command, the parent of the spans of its
+// service and provider calls. EndCommandSpan ends it.
+func (a *appContainer) StartCommandSpan(ctx context.Context, commandPath string) context.Context {
+	ctx, a.commandSpan = telemetry.Start(ctx, commandPath)
+	return ctx
+}
+
+// Ends the command span with the command's result, before Shutdown flushes it
+func (a *appContainer) EndCommandSpan(err error) {
+	if a.commandSpan != nil {
+		telemetry.End(a.commandSpan, err)
+		a.commandSpan = nil
+	}
+}
+
+// Returns ctx with a deadline of timeout from now, released by Shutdown.
+// A zero timeout leaves ctx without a deadline.
+func (a *appContainer) WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if timeout <= 0 {
+		return ctx
+	}
+	ctx, a.cancelTimeout = context.WithTimeout(ctx, timeout)
+	return ctx
+}
+
 // Injects the application container into the given context
 func (a *appContainer) ToContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, appContextKey, a)
 }
 
+const configManagerContextKey contextKey = "configManager"
+
+// withConfigManager makes cm, already read before the command was parsed,
+// available to the application container. A nil cm leaves ctx unchanged.
+func withConfigManager(ctx context.Context, cm *config.ConfigManager) context.Context {
+	if cm == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, configManagerContextKey, cm)
+}
+
+// configManagerFromContext returns the config manager stored by
+// withConfigManager, or nil.
+func configManagerFromContext(ctx context.Context) *config.ConfigManager {
+	cm, _ := ctx.Value(configManagerContextKey).(*config.ConfigManager)
+	return cm
+}
+
 // Retrieves the application container from the context
 func appFromContext(ctx context.Context) (*appContainer, error) {
 	app, ok := ctx.Value(appContextKey).(*appContainer)
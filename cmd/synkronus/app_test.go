@@ -2,7 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestAppFromContext_MissingContainer(t *testing.T) {
@@ -24,3 +35,91 @@ func TestToContext_RoundTrip(t *testing.T) {
 		t.Error("expected same pointer back from context")
 	}
 }
+
+func TestWithTimeout_SetsDeadline(t *testing.T) {
+	app := &appContainer{}
+	ctx := app.WithTimeout(context.Background(), time.Minute)
+
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute {
+		t.Fatalf("expected a deadline within a minute, got %v (set: %v)", deadline, ok)
+	}
+	app.cancelTimeout()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("expected cancelTimeout to release the context, got %v", ctx.Err())
+	}
+}
+
+func TestWithTimeout_ZeroMeansNoLimit(t *testing.T) {
+	app := &appContainer{}
+	ctx := app.WithTimeout(context.Background(), 0)
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("expected no deadline for a zero timeout")
+	}
+	if app.cancelTimeout != nil {
+		t.Error("expected no cancel func for a zero timeout")
+	}
+}
+
+func TestCommandSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	app := &appContainer{}
+	app.EndCommandSpan(nil) // before StartCommandSpan is a no-op
+
+	ctx := app.StartCommandSpan(context.Background(), "synkronus storage list-buckets")
+	if !trace.SpanFromContext(ctx).SpanContext().Equal(app.commandSpan.SpanContext()) {
+		t.Fatal("expected the command span in the context")
+	}
+	app.EndCommandSpan(errors.New("boom"))
+	app.EndCommandSpan(nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "synkronus storage list-buckets" {
+		t.Fatalf("expected one command span, got %v", spans)
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("expected the command error on the span, got %v", spans[0].Status())
+	}
+}
+
+func TestFanOutLimits(t *testing.T) {
+	if got := fanOutLimits(&config.Config{}); got != (service.FanOutLimits{}) {
+		t.Errorf("expected no limits without a fanout block, got %+v", got)
+	}
+	cfg := &config.Config{FanOut: &config.FanOutConfig{ProviderTimeout: "45s", Concurrency: 3}}
+	want := service.FanOutLimits{ProviderTimeout: 45 * time.Second, Concurrency: 3}
+	if got := fanOutLimits(cfg); got != want {
+		t.Errorf("fanOutLimits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDemoConfig(t *testing.T) {
+	cfg := &config.Config{
+		GCP:      &config.GCPConfig{Project: "real-project"},
+		AWS:      &config.AWSConfig{Region: "us-east-1"},
+		Cache:    &config.CacheConfig{Enabled: true},
+		Defaults: &config.DefaultsConfig{Provider: "gcp", Output: "json"},
+	}
+
+	demo := demoConfig(cfg)
+	if demo.GCP != nil || demo.AWS != nil {
+		t.Error("expected the real provider blocks to be dropped")
+	}
+	if demo.Mock == nil || !demo.Mock.Enabled {
+		t.Error("expected the mock provider to be enabled")
+	}
+	if demo.Cache != nil {
+		t.Error("expected the response cache to be off")
+	}
+	if demo.Defaults.Provider != "" || demo.Defaults.Output != "json" {
+		t.Errorf("expected only the provider defaults to be dropped, got %+v", demo.Defaults)
+	}
+	if cfg.GCP == nil || cfg.Defaults.Provider != "gcp" {
+		t.Error("expected the loaded config to be left unchanged")
+	}
+}
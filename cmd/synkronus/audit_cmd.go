@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newAuditCmd returns the "audit" parent command for the local audit log.
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the local audit log of changes",
+		Long: `Every change synkronus makes to provider resources (creating or deleting a bucket, uploading,
+copying or deleting an object) is appended to ~/.config/synkronus/audit.log with its time, local
+user, profile, provider, resource and result, including changes that failed.`,
+	}
+	cmd.AddCommand(newAuditShowCmd())
+	return cmd
+}
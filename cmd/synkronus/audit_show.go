@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/audit"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuditShowCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show recorded changes",
+		Long:  `Displays the most recent entries of the audit log, oldest first.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if limit < 0 {
+				return fmt.Errorf("--%s must not be negative, got %d", flags.Limit, limit)
+			}
+
+			path, err := audit.Path()
+			if err != nil {
+				return err
+			}
+			entries, err := audit.Read(path, limit)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 && app.OutputFormat == output.FormatTable {
+				fmt.Println("No changes recorded yet.")
+				return nil
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.AuditLogView(entries))
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, flags.Limit, 50, "Number of most recent entries to show; 0 shows all")
+	return cmd
+}
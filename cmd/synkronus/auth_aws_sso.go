@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/provider/awssso"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuthAWSSSOCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "aws-sso",
+		Short: "Log in with AWS IAM Identity Center (SSO)",
+		Long: `Runs the AWS IAM Identity Center device authorization flow for the active profile and
+caches the role credentials it returns; AWS commands use them until they expire.
+
+Requires aws.sso_start_url, aws.sso_account_id and aws.sso_role_name (and optionally
+aws.sso_region, which defaults to aws.region), e.g.:
+  synkronus config set aws.sso_start_url https://my-org.awsapps.com/start
+  synkronus config set aws.sso_account_id 123456789012
+  synkronus config set aws.sso_role_name ReadOnly`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if app.Config.AWS == nil || !app.Config.AWS.UsesSSO() {
+				return fmt.Errorf("AWS SSO is not configured for profile '%s'; set aws.sso_start_url, aws.sso_account_id and aws.sso_role_name", app.ConfigManager.ActiveProfile())
+			}
+			settings, err := awssso.SettingsFrom(app.Config.AWS)
+			if err != nil {
+				return err
+			}
+
+			login, err := awssso.NewLogin(cmd.Context(), settings)
+			if err != nil {
+				return err
+			}
+			creds, err := login.Run(cmd.Context(), settings, func(auth awssso.Authorization) {
+				fmt.Printf("To sign in, open %s\nand confirm the code %s (expires at %s).\n\nWaiting for approval...\n",
+					auth.VerificationURI, auth.UserCode, auth.ExpiresAt.Format(time.Kitchen))
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := awssso.SaveCredentials(settings, creds); err != nil {
+				return err
+			}
+			fmt.Printf("Logged in as %s in account %s; credentials valid until %s\n",
+				settings.RoleName, settings.AccountID, creds.Expires.Local().Format(time.RFC1123))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newAuthCmd returns the "auth" parent command with provider login subcommands.
+func newAuthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Log in to cloud providers",
+		Long:  `Obtain short-lived provider credentials without installing the provider's own CLI.`,
+	}
+	cmd.AddCommand(newAuthAWSSSOCmd())
+	return cmd
+}
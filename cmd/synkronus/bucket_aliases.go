@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/service"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// objectRefAnnotation marks commands whose first argument is an object key, so
+// it may also be written as "alias/path/to/object" in place of --bucket.
+const objectRefAnnotation = "synkronus_object_ref"
+
+// markObjectRefArg lets the command's object key argument carry a bucket alias.
+func markObjectRefArg(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[objectRefAnnotation] = "true"
+}
+
+// applyBucketAliases resolves bucket aliases in --bucket, --dest-bucket, and
+// (for annotated commands) the object key argument, before the command runs.
+// args is the slice cobra passes on to RunE, so an alias-prefixed object key is
+// rewritten in place to the bare key.
+func applyBucketAliases(cmd *cobra.Command, args []string, cfg *config.Config) error {
+	if len(cfg.Aliases) == 0 {
+		return nil
+	}
+
+	for _, name := range []string{flags.Bucket, flags.DestBucket} {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		ref, ok, err := cfg.ResolveBucketAlias(flag.Value.String())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if ref.Path != "" {
+			// Only object listing has somewhere to put a path given with the bucket
+			prefix := cmd.Flags().Lookup(flags.Prefix)
+			if name != flags.Bucket || prefix == nil || prefix.Changed {
+				return fmt.Errorf("--%s %q includes an object path; pass the object key separately", name, flag.Value.String())
+			}
+			if err := cmd.Flags().Set(flags.Prefix, ref.Path); err != nil {
+				return err
+			}
+		}
+		if err := cmd.Flags().Set(name, ref.Bucket); err != nil {
+			return err
+		}
+		if err := setAliasProvider(cmd, ref); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := cmd.Annotations[objectRefAnnotation]; ok && len(args) > 0 && !cmd.Flags().Changed(flags.Bucket) {
+		ref, ok, err := cfg.ResolveBucketAlias(args[0])
+		if err != nil || !ok || ref.Path == "" {
+			return err
+		}
+		if err := cmd.Flags().Set(flags.Bucket, ref.Bucket); err != nil {
+			return err
+		}
+		args[0] = ref.Path
+		return setAliasProvider(cmd, ref)
+	}
+	return nil
+}
+
+// setAliasProvider fills in --provider from the alias target's scheme and
+// rejects an explicit --provider that contradicts it.
+func setAliasProvider(cmd *cobra.Command, ref config.BucketRef) error {
+	flag := cmd.Flags().Lookup(flags.Provider)
+	if flag == nil || ref.Provider == "" {
+		return nil
+	}
+	if flag.Changed {
+		if !strings.EqualFold(flag.Value.String(), ref.Provider) {
+			return fmt.Errorf("bucket %q is a %s bucket, but --%s is %q", ref.Bucket, ref.Provider, flags.Provider, flag.Value.String())
+		}
+		return nil
+	}
+	return cmd.Flags().Set(flags.Provider, ref.Provider)
+}
+
+// parseBucketURL resolves a bucket argument written as "gs://bucket/prefix",
+// "s3://bucket/prefix" or "alias/prefix" into a provider, bucket and prefix.
+func parseBucketURL(cfg *config.Config, arg string) (service.SyncTarget, error) {
+	ref, ok, err := cfg.ResolveBucketAlias(arg)
+	if err != nil {
+		return service.SyncTarget{}, err
+	}
+	if !ok {
+		if !strings.Contains(arg, "://") {
+			return service.SyncTarget{}, fmt.Errorf("%q is neither a gs:// or s3:// URL nor a bucket alias", arg)
+		}
+		if ref, err = config.ParseBucketTarget(arg); err != nil {
+			return service.SyncTarget{}, err
+		}
+	}
+	if ref.Provider == "" {
+		return service.SyncTarget{}, fmt.Errorf("bucket alias %q has no gs:// or s3:// scheme to infer its provider from", arg)
+	}
+	return service.SyncTarget{Provider: ref.Provider, Bucket: ref.Bucket, Prefix: ref.Path}, nil
+}
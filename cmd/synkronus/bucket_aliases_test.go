@@ -0,0 +1,129 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/service"
+	"testing"
+)
+
+func TestApplyBucketAliases(t *testing.T) {
+	cfg := &config.Config{Aliases: map[string]string{
+		"data-lake": "gs://my-company-data-lake-prod",
+		"archive":   "s3://archive-bucket",
+	}}
+
+	t.Run("object key argument", func(t *testing.T) {
+		cmd := newDescribeObjectCmd()
+		args := []string{"data-lake/path/file.csv"}
+		if err := cmd.ParseFlags(nil); err != nil {
+			t.Fatalf("ParseFlags failed: %v", err)
+		}
+		if err := applyBucketAliases(cmd, args, cfg); err != nil {
+			t.Fatalf("applyBucketAliases failed: %v", err)
+		}
+		if args[0] != "path/file.csv" {
+			t.Errorf("object key = %q, want %q", args[0], "path/file.csv")
+		}
+		assertFlag(t, cmd.Flags().Lookup(flags.Bucket).Value.String(), "my-company-data-lake-prod")
+		assertFlag(t, cmd.Flags().Lookup(flags.Provider).Value.String(), "gcp")
+	})
+
+	t.Run("bucket and dest-bucket flags", func(t *testing.T) {
+		cmd := newCopyObjectCmd()
+		if err := cmd.ParseFlags([]string{"--bucket", "archive", "--dest-bucket", "archive"}); err != nil {
+			t.Fatalf("ParseFlags failed: %v", err)
+		}
+		args := []string{"key.txt"}
+		if err := applyBucketAliases(cmd, args, cfg); err != nil {
+			t.Fatalf("applyBucketAliases failed: %v", err)
+		}
+		assertFlag(t, cmd.Flags().Lookup(flags.Bucket).Value.String(), "archive-bucket")
+		assertFlag(t, cmd.Flags().Lookup(flags.DestBucket).Value.String(), "archive-bucket")
+		assertFlag(t, cmd.Flags().Lookup(flags.Provider).Value.String(), "aws")
+		assertFlag(t, args[0], "key.txt")
+	})
+
+	t.Run("literal bucket and aliased dest-bucket", func(t *testing.T) {
+		cmd := newCopyObjectCmd()
+		if err := cmd.ParseFlags([]string{"--bucket", "real-bucket", "--dest-bucket", "archive"}); err != nil {
+			t.Fatalf("ParseFlags failed: %v", err)
+		}
+		if err := applyBucketAliases(cmd, []string{"key.txt"}, cfg); err != nil {
+			t.Fatalf("applyBucketAliases failed: %v", err)
+		}
+		assertFlag(t, cmd.Flags().Lookup(flags.Bucket).Value.String(), "real-bucket")
+		assertFlag(t, cmd.Flags().Lookup(flags.DestBucket).Value.String(), "archive-bucket")
+		assertFlag(t, cmd.Flags().Lookup(flags.Provider).Value.String(), "aws")
+	})
+
+	t.Run("bucket flag with path sets prefix", func(t *testing.T) {
+		cmd := newListObjectsCmd()
+		if err := cmd.ParseFlags([]string{"--bucket", "data-lake/raw/"}); err != nil {
+			t.Fatalf("ParseFlags failed: %v", err)
+		}
+		if err := applyBucketAliases(cmd, nil, cfg); err != nil {
+			t.Fatalf("applyBucketAliases failed: %v", err)
+		}
+		assertFlag(t, cmd.Flags().Lookup(flags.Prefix).Value.String(), "raw/")
+	})
+
+	t.Run("conflicting provider", func(t *testing.T) {
+		cmd := newDescribeObjectCmd()
+		if err := cmd.ParseFlags([]string{"--provider", "aws"}); err != nil {
+			t.Fatalf("ParseFlags failed: %v", err)
+		}
+		if err := applyBucketAliases(cmd, []string{"data-lake/file"}, cfg); err == nil {
+			t.Error("expected error when --provider contradicts the alias")
+		}
+	})
+
+	t.Run("non-alias argument untouched", func(t *testing.T) {
+		cmd := newDescribeObjectCmd()
+		if err := cmd.ParseFlags([]string{"--bucket", "real-bucket"}); err != nil {
+			t.Fatalf("ParseFlags failed: %v", err)
+		}
+		args := []string{"data-lake/file"}
+		if err := applyBucketAliases(cmd, args, cfg); err != nil {
+			t.Fatalf("applyBucketAliases failed: %v", err)
+		}
+		assertFlag(t, args[0], "data-lake/file")
+		assertFlag(t, cmd.Flags().Lookup(flags.Bucket).Value.String(), "real-bucket")
+	})
+}
+
+func assertFlag(t *testing.T, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseBucketURL(t *testing.T) {
+	cfg := &config.Config{Aliases: map[string]string{
+		"data-lake": "gs://my-company-data-lake-prod/exports",
+		"bare":      "plain-bucket",
+	}}
+	tests := []struct {
+		arg     string
+		want    service.SyncTarget
+		wantErr bool
+	}{
+		{arg: "gs://a/prefix/", want: service.SyncTarget{Provider: "gcp", Bucket: "a", Prefix: "prefix/"}},
+		{arg: "s3://b", want: service.SyncTarget{Provider: "aws", Bucket: "b"}},
+		{arg: "data-lake/2024/", want: service.SyncTarget{Provider: "gcp", Bucket: "my-company-data-lake-prod", Prefix: "exports/2024/"}},
+		{arg: "bare", wantErr: true},
+		{arg: "some-bucket", wantErr: true},
+		{arg: "ftp://x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseBucketURL(cfg, tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseBucketURL(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseBucketURL(%q) = %+v, want %+v", tt.arg, got, tt.want)
+		}
+	}
+}
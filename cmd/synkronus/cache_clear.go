@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cache"
+
+	"github.com/spf13/cobra"
+)
+
+func newCacheClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete all cached results",
+		Long:  `Deletes the cached usage metrics and list and describe results, so the next commands fetch fresh data from the providers.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cache.Clear(); err != nil {
+				return err
+			}
+			fmt.Println("Cache cleared.")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newCacheCmd returns the "cache" parent command for managing on-disk caches.
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage cached results",
+		Long: `Manage the on-disk caches of bucket usage metrics and, when enabled with
+'synkronus config set cache.enabled true', of list and describe results (kept for cache.ttl,
+5m by default). Pass --no-cache to any command to bypass them.`,
+	}
+	cmd.AddCommand(newCacheClearCmd())
+	return cmd
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// expandCommandAlias replaces the first command word in args with its alias
+// expansion from the config (e.g., "lsb" -> "storage list-buckets -o json").
+// As with git, built-in commands always win over aliases, and expansions are
+// not expanded again. loadAliases is only called when the first command word
+// is not a built-in command, so most runs don't read the config twice.
+func expandCommandAlias(root *cobra.Command, args []string, loadAliases func() map[string]string) ([]string, error) {
+	i := firstCommandWord(root, args)
+	if i < 0 {
+		return args, nil
+	}
+	name := args[i]
+	if isBuiltinCommand(root, name) {
+		return args, nil
+	}
+	expansion, ok := loadAliases()[strings.ToLower(name)]
+	if !ok {
+		return args, nil
+	}
+
+	words, err := splitAliasArgs(expansion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias %q: %w", name, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("alias %q expands to nothing", name)
+	}
+
+	expanded := make([]string, 0, len(args)+len(words)-1)
+	expanded = append(expanded, args[:i]...)
+	expanded = append(expanded, words...)
+	return append(expanded, args[i+1:]...), nil
+}
+
+// firstCommandWord returns the index of the first argument that is not a
+// global flag or a global flag's value, or -1 if there is none.
+func firstCommandWord(root *cobra.Command, args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return i
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		// Skip the value of a global flag given as a separate argument (e.g., --profile prod)
+		flag := root.PersistentFlags().Lookup(strings.TrimLeft(arg, "-"))
+		if !strings.HasPrefix(arg, "--") && len(arg) == 2 {
+			flag = root.PersistentFlags().ShorthandLookup(arg[1:])
+		}
+		if flag != nil && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return -1
+}
+
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAliasArgs splits an alias expansion into words, honoring single and
+// double quotes and backslash escapes the way a POSIX shell would.
+func splitAliasArgs(s string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\' && quote != '\'':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			current.WriteRune(runes[i])
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// aliasLoader reads the command aliases from the config and keeps the config
+// manager it read them with, so the application container reuses it rather
+// than reading (and possibly decrypting) the config a second time.
+type aliasLoader struct {
+	configManager *config.ConfigManager
+}
+
+// load returns the command aliases. Errors are ignored here: they are reported
+// properly once the command initializes the application.
+func (l *aliasLoader) load() map[string]string {
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		return nil
+	}
+	l.configManager = cm
+	return cm.CommandAliases()
+}
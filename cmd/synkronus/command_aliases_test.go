@@ -0,0 +1,116 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExpandCommandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"lsb":     "storage list-buckets -o json",
+		"storage": "sql list-instances",
+		"quoted":  `storage objects list --prefix "my dir/"`,
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"expands alias", []string{"lsb"}, []string{"storage", "list-buckets", "-o", "json"}},
+		{"keeps trailing args", []string{"lsb", "-p", "gcp"}, []string{"storage", "list-buckets", "-o", "json", "-p", "gcp"}},
+		{"skips global flag values", []string{"--profile", "prod", "lsb"}, []string{"--profile", "prod", "storage", "list-buckets", "-o", "json"}},
+		{"skips global bool flags", []string{"-d", "lsb"}, []string{"-d", "storage", "list-buckets", "-o", "json"}},
+		{"built-in commands win", []string{"storage", "list-buckets"}, []string{"storage", "list-buckets"}},
+		{"unknown word untouched", []string{"nope"}, []string{"nope"}},
+		{"quoted words", []string{"quoted"}, []string{"storage", "objects", "list", "--prefix", "my dir/"}},
+		{"no command", []string{"--debug"}, []string{"--debug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandCommandAlias(newRootCmd(), tt.args, func() map[string]string { return aliases })
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandCommandAlias(%v) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandCommandAlias_LoadsAliasesOnlyForUnknownCommands(t *testing.T) {
+	loads := 0
+	load := func() map[string]string {
+		loads++
+		return map[string]string{"lsb": "storage list-buckets"}
+	}
+
+	for _, args := range [][]string{{"storage", "list-buckets"}, {"--debug", "config", "list"}, {"help"}, {"--debug"}} {
+		if _, err := expandCommandAlias(newRootCmd(), args, load); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if loads != 0 {
+		t.Errorf("expected built-in commands not to load the aliases, loaded %d times", loads)
+	}
+
+	if _, err := expandCommandAlias(newRootCmd(), []string{"lsb"}, load); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loads != 1 {
+		t.Errorf("expected an unknown command to load the aliases once, loaded %d times", loads)
+	}
+}
+
+func TestSplitAliasArgs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"a b  c", []string{"a", "b", "c"}, false},
+		{`a "b c" 'd e'`, []string{"a", "b c", "d e"}, false},
+		{`a\ b ""`, []string{"a b", ""}, false},
+		{`'it''s'`, []string{"its"}, false},
+		{`"unterminated`, nil, true},
+		{`trailing\`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := splitAliasArgs(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitAliasArgs(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("splitAliasArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasLoader_SharesConfigManagerWithApp(t *testing.T) {
+	setupIntegrationTest(t)
+	if _, err := executeCommand("config", "set", "alias.cl", "config list"); err != nil {
+		t.Fatalf("config set failed: %v", err)
+	}
+
+	var loader aliasLoader
+	if got := loader.load()["cl"]; got != "config list" {
+		t.Fatalf("expected alias cl to expand to %q, got %q", "config list", got)
+	}
+	if loader.configManager == nil {
+		t.Fatal("expected the loader to keep its config manager")
+	}
+
+	app, err := newApp(appOptions{ConfigManager: loader.configManager})
+	if err != nil {
+		t.Fatalf("newApp failed: %v", err)
+	}
+	defer app.Shutdown()
+	if app.ConfigManager != loader.configManager {
+		t.Error("expected the application to reuse the config manager read for the aliases")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Shell completion queries the providers on each <TAB>, so it keeps their
+// responses in a cache of its own. It is always on, unlike the response cache,
+// and short-lived so new buckets and objects soon show up.
+const (
+	completionCacheName = "completions"
+	completionCacheTTL  = 30 * time.Second
+	// completionTimeout bounds the provider calls of one completion, so a slow
+	// provider leaves the shell without suggestions rather than hung
+	completionTimeout = 5 * time.Second
+)
+
+// registerCompletions walks the command tree, completing --bucket and
+// --dest-bucket with bucket names, and the object key argument of commands
+// marked with markObjectRefArg with the keys in --bucket.
+func registerCompletions(cmd *cobra.Command) {
+	for _, name := range []string{flags.Bucket, flags.DestBucket} {
+		if cmd.Flags().Lookup(name) != nil {
+			cmd.RegisterFlagCompletionFunc(name, completeBucketNames)
+		}
+	}
+	if _, ok := cmd.Annotations[objectRefAnnotation]; ok && cmd.ValidArgsFunction == nil {
+		cmd.ValidArgsFunction = completeObjectKeys
+	}
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
+
+// completionApp builds the application container for a completion request.
+// PersistentPreRunE does not run for completions, so the profile, bucket
+// aliases and config defaults are applied here. The returned context carries
+// completionTimeout, released by the app's Shutdown.
+func completionApp(cmd *cobra.Command, args []string) (*appContainer, context.Context, error) {
+	profile, _ := cmd.Flags().GetString(flags.Profile)
+	impersonate, _ := cmd.Flags().GetString(flags.ImpersonateServiceAccount)
+	app, err := newApp(appOptions{Profile: profile, Impersonate: impersonate})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := applyBucketAliases(cmd, args, app.Config); err != nil {
+		app.Shutdown()
+		return nil, nil, err
+	}
+	if err := applyCommandDefaults(cmd, app.Config.Defaults); err != nil {
+		app.Shutdown()
+		return nil, nil, err
+	}
+
+	if responses, err := cache.Open(completionCacheName, completionCacheTTL); err == nil {
+		responses = responses.Scoped(responseCacheScope(app.Config))
+		app.StorageService.UseResponseCache(responses)
+	}
+	return app, app.WithTimeout(context.Background(), completionTimeout), nil
+}
+
+// completeBucketArg completes a bucket name given as the command's argument.
+func completeBucketArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeBucketNames(cmd, args, toComplete)
+}
+
+// completeBucketNames completes the names of the buckets of --provider, or of
+// every configured provider, and the bucket aliases. Providers that fail are
+// left out rather than failing the completion.
+func completeBucketNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	app, ctx, err := completionApp(cmd, args)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer app.Shutdown()
+
+	providers := app.ProviderFactory.ConfiguredStorageProviders()
+	if provider, _ := cmd.Flags().GetString(flags.Provider); provider != "" {
+		providers = []string{provider}
+	}
+	buckets, _ := app.StorageService.ListAllBuckets(ctx, providers)
+
+	names := make([]string, 0, len(buckets)+len(app.Config.Aliases))
+	for _, b := range buckets {
+		names = append(names, b.Name)
+	}
+	for alias := range app.Config.Aliases {
+		names = append(names, alias)
+	}
+	return matchingCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeObjectKeys completes an object key in --bucket one "directory" at a
+// time, listing the level toComplete is in.
+func completeObjectKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	app, ctx, err := completionApp(cmd, args)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer app.Shutdown()
+
+	bucket, _ := cmd.Flags().GetString(flags.Bucket)
+	provider, _ := cmd.Flags().GetString(flags.Provider)
+	if bucket == "" || provider == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	prefix := toComplete[:strings.LastIndex(toComplete, "/")+1]
+	objects, err := app.StorageService.ListObjects(ctx, bucket, provider, prefix)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return objectKeyCompletions(objects, toComplete)
+}
+
+// objectKeyCompletions returns the prefixes and keys of a listing that start
+// with toComplete. When a prefix is offered, the shell does not add a space
+// after completing it, so the key can be completed further.
+func objectKeyCompletions(objects storage.ObjectList, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefixes := matchingCompletions(objects.CommonPrefixes, toComplete)
+	keys := make([]string, len(objects.Objects))
+	for i, obj := range objects.Objects {
+		keys[i] = obj.Key
+	}
+
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if len(prefixes) > 0 {
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+	return append(prefixes, matchingCompletions(keys, toComplete)...), directive
+}
+
+// matchingCompletions returns the sorted, distinct candidates starting with
+// toComplete.
+func matchingCompletions(candidates []string, toComplete string) []string {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			matches = append(matches, c)
+		}
+	}
+	slices.Sort(matches)
+	return slices.Compact(matches)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRegisterCompletions(t *testing.T) {
+	root := newRootCmd()
+
+	for _, path := range [][]string{
+		{"storage", "objects", "list"},
+		{"storage", "objects", "copy"},
+		{"storage", "find"},
+	} {
+		cmd, _, err := root.Find(path)
+		if err != nil {
+			t.Fatalf("finding %v: %v", path, err)
+		}
+		if _, ok := cmd.GetFlagCompletionFunc(flags.Bucket); !ok {
+			t.Errorf("expected --%s completion on %q", flags.Bucket, cmd.CommandPath())
+		}
+	}
+
+	copyCmd, _, _ := root.Find([]string{"storage", "objects", "copy"})
+	if _, ok := copyCmd.GetFlagCompletionFunc(flags.DestBucket); !ok {
+		t.Errorf("expected --%s completion on %q", flags.DestBucket, copyCmd.CommandPath())
+	}
+	describeObject, _, _ := root.Find([]string{"storage", "objects", "describe"})
+	if describeObject.ValidArgsFunction == nil {
+		t.Errorf("expected object key completion on %q", describeObject.CommandPath())
+	}
+	describeBucket, _, _ := root.Find([]string{"storage", "buckets", "describe"})
+	if describeBucket.ValidArgsFunction == nil {
+		t.Errorf("expected bucket name completion on %q", describeBucket.CommandPath())
+	}
+}
+
+func TestObjectKeyCompletions(t *testing.T) {
+	objects := storage.ObjectList{
+		Prefix:         "logs/",
+		CommonPrefixes: []string{"logs/2023/", "logs/2024/"},
+		Objects:        []storage.Object{{Key: "logs/2024.txt"}, {Key: "logs/README"}},
+	}
+
+	got, directive := objectKeyCompletions(objects, "logs/202")
+	want := []string{"logs/2023/", "logs/2024/", "logs/2024.txt"}
+	if !slices.Equal(got, want) {
+		t.Errorf("completions = %v, want %v", got, want)
+	}
+	if directive&cobra.ShellCompDirectiveNoSpace == 0 {
+		t.Error("expected no space after a prefix")
+	}
+
+	got, directive = objectKeyCompletions(objects, "logs/R")
+	if !slices.Equal(got, []string{"logs/README"}) {
+		t.Errorf("completions = %v, want [logs/README]", got)
+	}
+	if directive&cobra.ShellCompDirectiveNoSpace != 0 {
+		t.Error("expected a space after a complete key")
+	}
+}
+
+func TestMatchingCompletions(t *testing.T) {
+	got := matchingCompletions([]string{"logs", "data-lake", "logs-archive", "logs"}, "logs")
+	want := []string{"logs", "logs-archive"}
+	if !slices.Equal(got, want) {
+		t.Errorf("matchingCompletions = %v, want %v", got, want)
+	}
+}
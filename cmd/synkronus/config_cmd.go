@@ -1,15 +1,24 @@
 // File: cmd/synkronus/config_cmd.go
 package main
 
-import "github.com/spf13/cobra"
+import (
+	synkconfig "github.com/jkleinne/synkronus/internal/config"
 
-// newConfigCmd returns the "config" parent command with set/get/delete/list subcommands.
+	"github.com/spf13/cobra"
+)
+
+// newConfigCmd returns the "config" parent command with set/get/delete/list/export/import/encrypt/decrypt/doctor subcommands.
 func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage configuration settings",
-		Long:  `Manage configuration settings for providers. You can set, get, list, and delete configuration values.`,
+		Long: `Manage configuration settings for providers. You can set, get, list, and delete configuration values, and export or import the whole configuration.
+
+Settings are read from ~/.config/synkronus/config.json, with the nearest project-local
+'` + synkconfig.LocalConfigFileName + `' (in the working directory or a parent) layered on top. A project file may
+only set gcp.project(s), aws.region, defaults.output, defaults.provider(s) and active_profile.
+Commands that change settings always write the global file, encrypted if 'config encrypt' was used.`,
 	}
-	cmd.AddCommand(newConfigSetCmd(), newConfigGetCmd(), newConfigDeleteCmd(), newConfigListCmd())
+	cmd.AddCommand(newConfigSetCmd(), newConfigGetCmd(), newConfigDeleteCmd(), newConfigListCmd(), newConfigExportCmd(), newConfigImportCmd(), newConfigEncryptCmd(), newConfigDecryptCmd(), newConfigDoctorCmd())
 	return cmd
 }
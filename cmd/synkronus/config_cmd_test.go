@@ -3,7 +3,7 @@ package main
 import (
 	"testing"
 
-	synkconfig "synkronus/internal/config"
+	synkconfig "github.com/jkleinne/synkronus/internal/config"
 )
 
 func TestFlattenSettings_EmptyMap(t *testing.T) {
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigDecryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt",
+		Short: "Store the configuration file in plaintext again",
+		Long:  `Decrypts the global configuration file and writes it back in plaintext. A keychain-held encryption key is removed.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if app.ConfigManager.EncryptionMode() == "" {
+				fmt.Println("Configuration is not encrypted.")
+				return nil
+			}
+			if err := app.ConfigManager.DisableEncryption(); err != nil {
+				return fmt.Errorf("decrypting configuration: %w", err)
+			}
+			fmt.Println("Configuration decrypted.")
+			return nil
+		},
+	}
+}
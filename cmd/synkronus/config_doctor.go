@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/doctor"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose configuration and connectivity problems",
+		Long: `Checks config file permissions, the credentials each configured provider will use,
+that provider endpoints are reachable, local clock skew, and that the required GCP APIs
+are enabled, printing a fix for each problem found. Exits non-zero if any check fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			configPath, err := app.ConfigManager.ConfigPath()
+			if err != nil {
+				return err
+			}
+
+			results := doctor.Run(cmd.Context(), doctor.Options{
+				ConfigPath: configPath,
+				Config:     app.Config,
+			})
+			for _, r := range results {
+				fmt.Printf("[%-4s] %s: %s\n", strings.ToUpper(string(r.Status)), r.Check, r.Detail)
+				if r.Fix != "" && r.Status != doctor.StatusOK {
+					fmt.Printf("       fix: %s\n", r.Fix)
+				}
+			}
+
+			if doctor.Failed(results) {
+				return fmt.Errorf("one or more checks failed")
+			}
+			return nil
+		},
+	}
+}
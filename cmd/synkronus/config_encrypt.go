@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	synkconfig "github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigEncryptCmd() *cobra.Command {
+	var useKeychain bool
+
+	cmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt the configuration file at rest",
+		Long: `Encrypts the global configuration file with AES-256-GCM.
+By default the key is derived from the passphrase in $` + synkconfig.PassphraseEnvVar + `, which must then be set for every invocation.
+Use --keychain to generate a random key and store it in the OS keychain instead.
+Running it again on an encrypted config rotates the key (or switches modes).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			mode := synkconfig.EncryptionPassphrase
+			if useKeychain {
+				mode = synkconfig.EncryptionKeychain
+			}
+			if err := app.ConfigManager.EnableEncryption(mode); err != nil {
+				return fmt.Errorf("encrypting configuration: %w", err)
+			}
+			fmt.Printf("Configuration encrypted (key source: %s).\n", mode)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&useKeychain, flags.Keychain, false, "Store a random encryption key in the OS keychain instead of using a passphrase")
+
+	return cmd
+}
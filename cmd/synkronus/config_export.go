@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigExportCmd() *cobra.Command {
+	var redactSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the full configuration as JSON",
+		Long: `Writes the full configuration, including all profiles, to stdout as JSON.
+Use --redact-secrets to replace sensitive values before sharing the file. For example: 'synkronus config export --redact-secrets > team.json'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			data, err := app.ConfigManager.Export(redactSecrets)
+			if err != nil {
+				return fmt.Errorf("exporting configuration: %w", err)
+			}
+			if _, err := os.Stdout.Write(data); err != nil {
+				return fmt.Errorf("error writing to stdout: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&redactSecrets, flags.RedactSecrets, false, "Replace sensitive values (secrets, tokens, passwords) with a placeholder")
+
+	return cmd
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	synkconfig "github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigImportCmd() *cobra.Command {
+	var merge bool
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import configuration from a JSON file",
+		Long: `Validates a JSON configuration file (such as one produced by 'config export') and saves it as the current configuration.
+By default the existing configuration is replaced, which requires --force if one already exists. Use --merge to overlay the file onto the existing configuration instead.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			filePath := args[0]
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return fmt.Errorf("reading configuration file %q: %w", filePath, err)
+			}
+
+			opts := synkconfig.ImportOptions{Merge: merge, Overwrite: force}
+			if err := app.ConfigManager.Import(data, opts); err != nil {
+				if errors.Is(err, synkconfig.ErrConfigExists) {
+					return fmt.Errorf("importing %q: %w. Use --%s to combine them or --%s to replace it", filePath, err, flags.Merge, flags.Force)
+				}
+				return fmt.Errorf("importing %q: %w", filePath, err)
+			}
+			fmt.Printf("Configuration imported from '%s'\n", filePath)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&merge, flags.Merge, false, "Merge the imported settings into the existing configuration")
+	cmd.Flags().BoolVarP(&force, flags.Force, flags.ForceShort, false, "Replace an existing configuration without merging")
+
+	return cmd
+}
@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
+	synkconfig "github.com/jkleinne/synkronus/internal/config"
 	"maps"
 	"slices"
-	synkconfig "synkronus/internal/config"
 
 	"github.com/spf13/cobra"
 )
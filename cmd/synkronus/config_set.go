@@ -2,17 +2,24 @@ package main
 
 import (
 	"fmt"
+	synkconfig "github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 func newConfigSetCmd() *cobra.Command {
-	return &cobra.Command{
+	var useKeychain bool
+
+	cmd := &cobra.Command{
 		Use:   "set [key] [value]",
 		Short: "Set a configuration key-value pair",
-		Long:  `Sets a configuration value. For example: 'synkronus config set gcp.project my-gcp-123'`,
-		Args:  cobra.ExactArgs(2),
+		Long: `Sets a configuration value. For example: 'synkronus config set gcp.project my-gcp-123'
+List values are comma-separated: 'synkronus config set gcp.projects proj-a,proj-b'
+Use --keychain to store sensitive values in the OS keychain (macOS Keychain, Secret Service, Windows Credential Manager);
+the config file then only holds a '` + synkconfig.KeychainRefPrefix + `<key>' reference.`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
@@ -22,6 +29,14 @@ func newConfigSetCmd() *cobra.Command {
 			key := strings.ToLower(args[0])
 			value := args[1]
 
+			if useKeychain {
+				if err := app.ConfigManager.SetSecret(key, value); err != nil {
+					return fmt.Errorf("setting configuration %q: %w", key, err)
+				}
+				fmt.Printf("Configuration set: %s = %s%s (value stored in OS keychain)\n", key, synkconfig.KeychainRefPrefix, key)
+				return nil
+			}
+
 			if err := app.ConfigManager.SetValue(key, value); err != nil {
 				return fmt.Errorf("setting configuration %q: %w", key, err)
 			}
@@ -29,4 +44,7 @@ func newConfigSetCmd() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&useKeychain, flags.Keychain, false, "Store the value in the OS keychain instead of the config file")
+
+	return cmd
 }
@@ -1,17 +1,84 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"synkronus/internal/ui/prompt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Operations whose confirmation mode can be set with defaults.confirm_modes
+const (
+	confirmDeleteBucket = "delete-bucket"
+	confirmDeleteObject = "delete-object"
+	confirmCleanup      = "cleanup"
 )
 
-// confirmThenRun prompts the user for confirmation unless force is true,
-// then runs the action. Returns ErrOperationAborted if the user declines.
-func confirmThenRun(prompter prompt.Prompter, message, expectedValue string, force bool, action func() error) error {
+// confirmFlagAnnotation marks --force flags that bypass a confirmation prompt,
+// so that defaults.confirm applies to them.
+const confirmFlagAnnotation = "synkronus_confirm"
+
+// markConfirmFlag annotates the command's --force flag as a confirmation bypass.
+func markConfirmFlag(cmd *cobra.Command) {
+	cmd.Flags().SetAnnotation(flags.Force, confirmFlagAnnotation, []string{"true"})
+}
+
+func isConfirmFlag(flag *pflag.Flag) bool {
+	if flag == nil {
+		return false
+	}
+	_, ok := flag.Annotations[confirmFlagAnnotation]
+	return ok
+}
+
+// applyYesFlag sets the command's confirmation --force flag when --yes is given.
+// Commands without a confirmation prompt have nothing to answer, so --yes does
+// nothing there. An explicit --force wins.
+func applyYesFlag(cmd *cobra.Command, yes bool) error {
+	flag := cmd.Flags().Lookup(flags.Force)
+	if !yes || !isConfirmFlag(flag) || flag.Changed {
+		return nil
+	}
+	return cmd.Flags().Set(flags.Force, "true")
+}
+
+// confirmation describes how an operation is confirmed.
+type confirmation struct {
+	Mode prompt.Mode
+	// Timeout aborts the operation if no answer comes in time; 0 waits forever
+	Timeout time.Duration
+}
+
+// confirmationFor returns how operation is confirmed: in the mode set in
+// defaults.confirm_modes (or fallback), within defaults.confirm_timeout.
+func confirmationFor(cfg *config.Config, operation string, fallback prompt.Mode) confirmation {
+	c := confirmation{Mode: fallback}
+	if cfg == nil || cfg.Defaults == nil {
+		return c
+	}
+	if mode, ok := cfg.Defaults.ConfirmModes[operation]; ok {
+		c.Mode = prompt.Mode(mode)
+	}
+	c.Timeout = cfg.Defaults.ConfirmationTimeout()
+	return c
+}
+
+// confirmThenRun asks for confirmation as c describes unless force is true,
+// then runs the action. Returns ErrOperationAborted if the user declines or
+// does not answer within c.Timeout, and a usage error without prompting if
+// stdin is not a terminal. Waiting for an answer stops when ctx is done, e.g.
+// on Ctrl+C.
+func confirmThenRun(ctx context.Context, prompter prompt.Prompter, c confirmation, message, expectedValue string, force bool, action func() error) error {
 	if !force {
-		confirmed, err := prompter.Confirm(message, expectedValue)
+		confirmed, err := confirm(ctx, prompter, c, message, expectedValue)
 		if err != nil {
-			return fmt.Errorf("reading confirmation input: %w", err)
+			return err
 		}
 		if !confirmed {
 			fmt.Println("Deletion aborted: Confirmation mismatch or cancelled.")
@@ -20,3 +87,46 @@ func confirmThenRun(prompter prompt.Prompter, message, expectedValue string, for
 	}
 	return action()
 }
+
+// confirm asks prompter for confirmation, giving up when ctx is done or, as a
+// refusal, after c.Timeout. Reading the terminal cannot be interrupted, so the
+// read is left to finish on its own.
+func confirm(ctx context.Context, prompter prompt.Prompter, c confirmation, message, expectedValue string) (bool, error) {
+	type answer struct {
+		confirmed bool
+		err       error
+	}
+	answers := make(chan answer, 1)
+	go func() {
+		var a answer
+		if c.Mode == prompt.ModeYesNo {
+			a.confirmed, a.err = prompter.ConfirmYesNo(message)
+		} else {
+			a.confirmed, a.err = prompter.Confirm(message, expectedValue)
+		}
+		answers <- a
+	}()
+
+	var expired <-chan time.Time
+	if c.Timeout > 0 {
+		timer := time.NewTimer(c.Timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case a := <-answers:
+		if errors.Is(a.err, prompt.ErrNonInteractive) {
+			return false, &usageError{err: fmt.Errorf("%w; pass --%s to proceed without confirming", a.err, flags.Yes)}
+		}
+		if a.err != nil {
+			return false, fmt.Errorf("reading confirmation input: %w", a.err)
+		}
+		return a.confirmed, nil
+	case <-expired:
+		fmt.Printf("\nNo answer within %s.\n", c.Timeout)
+		return false, nil
+	case <-ctx.Done():
+		return false, context.Cause(ctx)
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
 	"strings"
 	"testing"
-	"synkronus/internal/ui/prompt"
+	"time"
+
+	"github.com/spf13/cobra"
 )
 
 // mockPrompter implements prompt.Prompter for testing.
@@ -17,9 +23,17 @@ func (m *mockPrompter) Confirm(message, expectedValue string) (bool, error) {
 	return m.confirmed, m.err
 }
 
+func (m *mockPrompter) ConfirmYesNo(message string) (bool, error) {
+	return m.confirmed, m.err
+}
+
+func (m *mockPrompter) Ask(question, defaultValue string) (string, error) {
+	return defaultValue, m.err
+}
+
 func TestConfirmThenRun_Force_SkipsPrompt(t *testing.T) {
 	called := false
-	err := confirmThenRun(nil, "warning", "value", true, func() error {
+	err := confirmThenRun(context.Background(), nil, confirmation{Mode: prompt.ModeTyped}, "warning", "value", true, func() error {
 		called = true
 		return nil
 	})
@@ -34,7 +48,7 @@ func TestConfirmThenRun_Force_SkipsPrompt(t *testing.T) {
 func TestConfirmThenRun_Confirmed_RunsAction(t *testing.T) {
 	called := false
 	p := &mockPrompter{confirmed: true}
-	err := confirmThenRun(p, "warning", "value", false, func() error {
+	err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeTyped}, "warning", "value", false, func() error {
 		called = true
 		return nil
 	})
@@ -48,7 +62,7 @@ func TestConfirmThenRun_Confirmed_RunsAction(t *testing.T) {
 
 func TestConfirmThenRun_Declined_ReturnsAborted(t *testing.T) {
 	p := &mockPrompter{confirmed: false}
-	err := confirmThenRun(p, "warning", "value", false, func() error {
+	err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeTyped}, "warning", "value", false, func() error {
 		t.Fatal("action should not be called when declined")
 		return nil
 	})
@@ -59,7 +73,7 @@ func TestConfirmThenRun_Declined_ReturnsAborted(t *testing.T) {
 
 func TestConfirmThenRun_PrompterError_Propagates(t *testing.T) {
 	p := &mockPrompter{err: errors.New("input broken")}
-	err := confirmThenRun(p, "warning", "value", false, func() error {
+	err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeTyped}, "warning", "value", false, func() error {
 		t.Fatal("action should not be called on prompter error")
 		return nil
 	})
@@ -77,7 +91,7 @@ func TestConfirmThenRun_PrompterError_Propagates(t *testing.T) {
 func TestConfirmThenRun_ActionError_Propagates(t *testing.T) {
 	p := &mockPrompter{confirmed: true}
 	actionErr := errors.New("delete failed")
-	err := confirmThenRun(p, "warning", "value", false, func() error {
+	err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeTyped}, "warning", "value", false, func() error {
 		return actionErr
 	})
 	if !errors.Is(err, actionErr) {
@@ -94,7 +108,7 @@ func TestConfirmThenRun_WithStandardPrompter(t *testing.T) {
 		p := prompt.NewStandardPrompter(input, &output)
 
 		called := false
-		err := confirmThenRun(p, "Delete my-bucket?", "my-bucket", false, func() error {
+		err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeTyped}, "Delete my-bucket?", "my-bucket", false, func() error {
 			called = true
 			return nil
 		})
@@ -111,7 +125,7 @@ func TestConfirmThenRun_WithStandardPrompter(t *testing.T) {
 		var output strings.Builder
 		p := prompt.NewStandardPrompter(input, &output)
 
-		err := confirmThenRun(p, "Delete my-bucket?", "my-bucket", false, func() error {
+		err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeTyped}, "Delete my-bucket?", "my-bucket", false, func() error {
 			t.Fatal("action should not be called on mismatch")
 			return nil
 		})
@@ -120,3 +134,122 @@ func TestConfirmThenRun_WithStandardPrompter(t *testing.T) {
 		}
 	})
 }
+
+func TestConfirmThenRun_YesNoMode(t *testing.T) {
+	input := strings.NewReader("y\n")
+	var output strings.Builder
+	p := prompt.NewStandardPrompter(input, &output)
+
+	called := false
+	err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeYesNo}, "Delete report.csv?", "report.csv", false, func() error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected action to be called after answering y")
+	}
+	if strings.Contains(output.String(), "type the name") {
+		t.Errorf("expected a y/N prompt, got %q", output.String())
+	}
+}
+
+func TestConfirmationFor(t *testing.T) {
+	cfg := &config.Config{Defaults: &config.DefaultsConfig{
+		ConfirmModes:   map[string]string{confirmDeleteObject: "typed"},
+		ConfirmTimeout: "2m",
+	}}
+
+	if got := confirmationFor(cfg, confirmDeleteObject, prompt.ModeYesNo); got != (confirmation{Mode: prompt.ModeTyped, Timeout: 2 * time.Minute}) {
+		t.Errorf("configured confirmation = %+v, want typed within 2m", got)
+	}
+	if got := confirmationFor(cfg, confirmDeleteBucket, prompt.ModeTyped); got.Mode != prompt.ModeTyped {
+		t.Errorf("unconfigured mode = %q, want the fallback %q", got.Mode, prompt.ModeTyped)
+	}
+	if got := confirmationFor(&config.Config{}, confirmDeleteObject, prompt.ModeYesNo); got != (confirmation{Mode: prompt.ModeYesNo}) {
+		t.Errorf("confirmation without defaults = %+v, want the fallback without a timeout", got)
+	}
+}
+
+func TestConfirmThenRun_Unanswered_TimesOut(t *testing.T) {
+	err := confirmThenRun(context.Background(), blockingPrompter{}, confirmation{Mode: prompt.ModeTyped, Timeout: 10 * time.Millisecond}, "warning", "value", false, func() error {
+		t.Fatal("action should not be called without an answer")
+		return nil
+	})
+	if !errors.Is(err, ErrOperationAborted) {
+		t.Errorf("expected ErrOperationAborted, got: %v", err)
+	}
+}
+
+// blockingPrompter waits for an answer that never comes, like an idle terminal.
+type blockingPrompter struct{}
+
+func (blockingPrompter) Confirm(string, string) (bool, error) {
+	select {}
+}
+
+func (blockingPrompter) ConfirmYesNo(string) (bool, error) {
+	select {}
+}
+
+func (blockingPrompter) Ask(string, string) (string, error) {
+	select {}
+}
+
+func TestConfirmThenRun_CanceledWhilePrompting(t *testing.T) {
+	interrupted := errors.New("interrupted")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(interrupted)
+
+	err := confirmThenRun(ctx, blockingPrompter{}, confirmation{Mode: prompt.ModeTyped}, "warning", "value", false, func() error {
+		t.Fatal("action should not be called after cancellation")
+		return nil
+	})
+	if !errors.Is(err, interrupted) {
+		t.Errorf("expected the cancellation cause, got: %v", err)
+	}
+}
+
+func TestConfirmThenRun_NonInteractive_IsUsageError(t *testing.T) {
+	p := &mockPrompter{err: prompt.ErrNonInteractive}
+	err := confirmThenRun(context.Background(), p, confirmation{Mode: prompt.ModeTyped}, "warning", "value", false, func() error {
+		t.Fatal("action should not be called without confirmation")
+		return nil
+	})
+	var usage *usageError
+	if !errors.As(err, &usage) || !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("expected a usage error suggesting --yes, got: %v", err)
+	}
+}
+
+func TestApplyYesFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		newCmd    func() *cobra.Command
+		args      []string
+		yes       bool
+		wantForce string
+	}{
+		{"yes sets force", newDeleteBucketCmd, []string{"my-bucket"}, true, "true"},
+		{"without yes", newDeleteBucketCmd, []string{"my-bucket"}, false, "false"},
+		{"explicit force wins", newDeleteObjectCmd, []string{"key", "--force=false"}, true, "false"},
+		{"force without confirmation meaning is untouched", newConfigImportCmd, []string{"file.json"}, true, "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := tt.newCmd()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags failed: %v", err)
+			}
+			if err := applyYesFlag(cmd, tt.yes); err != nil {
+				t.Fatalf("applyYesFlag failed: %v", err)
+			}
+			if got := cmd.Flags().Lookup(flags.Force).Value.String(); got != tt.wantForce {
+				t.Errorf("--%s = %q, want %q", flags.Force, got, tt.wantForce)
+			}
+		})
+	}
+}
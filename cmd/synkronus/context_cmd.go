@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newContextCmd returns the "context" parent command with use/show subcommands.
+func newContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Switch between named contexts",
+		Long: `Switch between named contexts. A context pairs a profile with the providers commands
+target by default, stored under 'contexts.<name>' (e.g., 'synkronus config set contexts.prod.profile prod'
+and 'synkronus config set contexts.prod.providers gcp,aws').`,
+	}
+	cmd.AddCommand(newContextUseCmd(), newContextShowCmd())
+	return cmd
+}
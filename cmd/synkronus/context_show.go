@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newContextShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the current context",
+		Long:  `Displays the current context and the profile and default providers it applied, noting any that have since been changed.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cfg := app.Config
+			current := app.ConfigManager.CurrentContext()
+			if current == "" {
+				fmt.Println("No context selected. Use 'synkronus context use <name>'.")
+				if names := cfg.ContextNames(); len(names) > 0 {
+					fmt.Printf("Available contexts: %s\n", strings.Join(names, ", "))
+				}
+				return nil
+			}
+
+			fmt.Printf("Current context: %s\n", current)
+			ctx, ok := cfg.Contexts[current]
+			if !ok {
+				fmt.Println("  (context no longer exists in the config)")
+				return nil
+			}
+
+			profile := ctx.Profile
+			if profile == "" {
+				profile = "default"
+			}
+			fmt.Printf("  profile:   %s\n", profile)
+			fmt.Printf("  providers: %s\n", formatProviderList(ctx.Providers))
+
+			var defaultProviders []string
+			if cfg.Defaults != nil {
+				defaultProviders = cfg.Defaults.Providers
+			}
+			if !strings.EqualFold(app.ConfigManager.ActiveProfile(), profile) || !slices.Equal(defaultProviders, ctx.Providers) {
+				fmt.Printf("\nNote: the active profile (%s) or default providers (%s) have changed since; run 'synkronus context use %s' to reapply it.\n",
+					app.ConfigManager.ActiveProfile(), formatProviderList(defaultProviders), current)
+			}
+			return nil
+		},
+	}
+}
+
+func formatProviderList(providers []string) string {
+	if len(providers) == 0 {
+		return "all configured"
+	}
+	return strings.Join(providers, ", ")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newContextUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [context]",
+		Short: "Switch to a context",
+		Long:  `Applies the context's profile as the active profile and its providers as the default providers, and persists the selection. For example: 'synkronus context use prod'`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			name := args[0]
+			if err := app.ConfigManager.UseContext(name); err != nil {
+				return fmt.Errorf("switching to context %q: %w", name, err)
+			}
+			fmt.Printf("Switched to context '%s'\n", name)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// applyCommandDefaults fills in flags the user did not pass on the command line
+// from the defaults section of the config. It runs before cobra validates
+// required flags, so a default provider satisfies --provider.
+func applyCommandDefaults(cmd *cobra.Command, defaults *config.DefaultsConfig) error {
+	if defaults == nil {
+		return nil
+	}
+
+	if err := setFlagDefault(cmd, flags.Output, defaults.Output); err != nil {
+		return fmt.Errorf("applying defaults.output: %w", err)
+	}
+	if err := setFlagDefault(cmd, flags.Provider, defaults.Provider); err != nil {
+		return fmt.Errorf("applying defaults.provider: %w", err)
+	}
+	if err := setFlagDefault(cmd, flags.Providers, strings.Join(defaults.Providers, ",")); err != nil {
+		return fmt.Errorf("applying defaults.providers: %w", err)
+	}
+	// Only --force flags that skip a confirmation prompt are affected, not
+	// ones with other meanings such as 'config import --force'
+	if defaults.Confirm != nil && isConfirmFlag(cmd.Flags().Lookup(flags.Force)) {
+		if err := setFlagDefault(cmd, flags.Force, strconv.FormatBool(!*defaults.Confirm)); err != nil {
+			return fmt.Errorf("applying defaults.confirm: %w", err)
+		}
+	}
+	return nil
+}
+
+// setFlagDefault sets the named flag to value if the command has that flag
+// and it was not set explicitly.
+func setFlagDefault(cmd *cobra.Command, name, value string) error {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil || flag.Changed || value == "" {
+		return nil
+	}
+	return cmd.Flags().Set(name, value)
+}
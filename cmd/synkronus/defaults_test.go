@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyCommandDefaults(t *testing.T) {
+	confirmFalse := false
+	defaults := &config.DefaultsConfig{Output: "json", Provider: "gcp", Confirm: &confirmFalse}
+
+	tests := []struct {
+		name      string
+		newCmd    func() *cobra.Command
+		args      []string
+		wantFlags map[string]string
+	}{
+		{
+			name:      "fills unset flags",
+			newCmd:    newDeleteBucketCmd,
+			args:      []string{"my-bucket"},
+			wantFlags: map[string]string{flags.Provider: "gcp", flags.Force: "true"},
+		},
+		{
+			name:      "explicit flags win",
+			newCmd:    newDeleteBucketCmd,
+			args:      []string{"my-bucket", "--provider", "aws", "--force=false"},
+			wantFlags: map[string]string{flags.Provider: "aws", flags.Force: "false"},
+		},
+		{
+			name:      "force without confirmation meaning is untouched",
+			newCmd:    newConfigImportCmd,
+			args:      []string{"file.json"},
+			wantFlags: map[string]string{flags.Force: "false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := tt.newCmd()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags failed: %v", err)
+			}
+			if err := applyCommandDefaults(cmd, defaults); err != nil {
+				t.Fatalf("applyCommandDefaults failed: %v", err)
+			}
+			for name, want := range tt.wantFlags {
+				if got := cmd.Flags().Lookup(name).Value.String(); got != want {
+					t.Errorf("--%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyCommandDefaults_Output(t *testing.T) {
+	root := newRootCmd()
+	listCmd, _, err := root.Find([]string{"storage", "list-buckets"})
+	if err != nil {
+		t.Fatalf("failed to find command: %v", err)
+	}
+	if err := listCmd.ParseFlags(nil); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+
+	if err := applyCommandDefaults(listCmd, &config.DefaultsConfig{Output: "yaml"}); err != nil {
+		t.Fatalf("applyCommandDefaults failed: %v", err)
+	}
+	if got := listCmd.Flags().Lookup(flags.Output).Value.String(); got != "yaml" {
+		t.Errorf("--output = %q, want %q", got, "yaml")
+	}
+}
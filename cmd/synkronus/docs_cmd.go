@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newDocsCmd returns the "docs" parent command for generating reference documentation.
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate reference documentation",
+		Long: `Generate man pages or Markdown reference pages for every command and its flags,
+from the same definitions as --help, so packagers can ship them alongside the binary.`,
+	}
+	cmd.AddCommand(newDocsManCmd())
+	cmd.AddCommand(newDocsMarkdownCmd())
+	return cmd
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// manSection is the man section for user commands
+const manSection = "1"
+
+func newDocsManCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "man [directory]",
+		Short: "Generate man pages",
+		Long: `Writes a man page for every command to the directory, creating it if needed,
+e.g. synkronus.1 and synkronus-storage-buckets-list.1. Install them under share/man/man1.`,
+		Example: `  synkronus docs man ./man
+  man ./man/synkronus-storage-find.1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+
+			root := cmd.Root()
+			// Without the generation date, the pages are the same on every build
+			root.DisableAutoGenTag = true
+			header := &doc.GenManHeader{
+				Title:   "SYNKRONUS",
+				Section: manSection,
+				Source:  "synkronus",
+				Manual:  "Synkronus Manual",
+			}
+			if err := doc.GenManTree(root, header, dir); err != nil {
+				return fmt.Errorf("failed to generate man pages: %w", err)
+			}
+			fmt.Printf("Man pages written to %s\n", dir)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+func newDocsMarkdownCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "markdown [directory]",
+		Short: "Generate Markdown reference pages",
+		Long: `Writes a Markdown page for every command to the directory, creating it if needed,
+e.g. synkronus.md and synkronus_storage_buckets_list.md, linked to their parent and subcommands.`,
+		Example: `  synkronus docs markdown ./docs/reference`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+
+			root := cmd.Root()
+			// Without the generation date, the pages are the same on every build
+			root.DisableAutoGenTag = true
+			if err := doc.GenMarkdownTree(root, dir); err != nil {
+				return fmt.Errorf("failed to generate Markdown pages: %w", err)
+			}
+			fmt.Printf("Markdown pages written to %s\n", dir)
+			return nil
+		},
+	}
+}
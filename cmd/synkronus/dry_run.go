@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/output"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// renderDryRun reports the change a mutating command would make under --dry-run.
+// The provider's client is still initialized, so an unknown or unconfigured
+// provider fails the same way it would without --dry-run; no change is made.
+func renderDryRun(ctx context.Context, app *appContainer, operation, provider string, params map[string]string) error {
+	if err := app.StorageService.CheckProvider(ctx, provider); err != nil {
+		return err
+	}
+	return output.Render(os.Stdout, app.OutputFormat, output.DryRunView{
+		Operation:  operation,
+		Provider:   strings.ToLower(provider),
+		Parameters: params,
+	})
+}
+
+// formatPairs renders key=value pairs sorted by key, as they are given on the command line.
+func formatPairs(pairs map[string]string) string {
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(pairs)) {
+		parts = append(parts, k+"="+pairs[k])
+	}
+	return strings.Join(parts, ",")
+}
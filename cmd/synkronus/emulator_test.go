@@ -0,0 +1,223 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// End-to-end tests running the whole CLI, from flag parsing to the provider
+// clients, against the storage emulators of docker-compose.dev.yml:
+//
+//	docker compose -f docker-compose.dev.yml up -d
+//	go test -tags integration ./cmd/synkronus -run TestEmulator -v
+//
+// Emulators that are not reachable are skipped. Point the tests elsewhere with
+// SYNKRONUS_TEST_GCS_ENDPOINT, SYNKRONUS_TEST_LOCALSTACK_ENDPOINT and
+// SYNKRONUS_TEST_MINIO_ENDPOINT.
+
+// emulator is a storage emulator and the config that points synkronus at it.
+type emulator struct {
+	name     string
+	provider string
+	location string
+	endpoint string
+	settings map[string]string
+}
+
+func emulators() []emulator {
+	gcs := envOr("SYNKRONUS_TEST_GCS_ENDPOINT", "http://localhost:4443/storage/v1/")
+	localstack := envOr("SYNKRONUS_TEST_LOCALSTACK_ENDPOINT", "http://localhost:4566")
+	minio := envOr("SYNKRONUS_TEST_MINIO_ENDPOINT", "http://localhost:9000")
+
+	return []emulator{
+		{
+			name:     "fake-gcs-server",
+			provider: "gcp",
+			location: "US",
+			endpoint: gcs,
+			settings: map[string]string{"gcp.project": "test-project", "gcp.storage_endpoint": gcs},
+		},
+		{
+			name:     "localstack",
+			provider: "aws",
+			location: "us-east-1",
+			endpoint: localstack,
+			settings: map[string]string{
+				"aws.region":            "us-east-1",
+				"aws.endpoint_url":      localstack,
+				"aws.access_key_id":     "test",
+				"aws.secret_access_key": "test",
+			},
+		},
+		{
+			name:     "minio",
+			provider: "aws",
+			location: "us-east-1",
+			endpoint: minio,
+			settings: map[string]string{
+				"aws.region":            "us-east-1",
+				"aws.endpoint_url":      minio,
+				"aws.access_key_id":     "minioadmin",
+				"aws.secret_access_key": "minioadmin",
+			},
+		},
+	}
+}
+
+func TestEmulator_StorageLifecycle(t *testing.T) {
+	for _, e := range emulators() {
+		t.Run(e.name, func(t *testing.T) {
+			skipUnlessReachable(t, e.endpoint)
+			setupIntegrationTest(t)
+			for key, value := range e.settings {
+				mustRun(t, "config", "set", key, value)
+			}
+			testStorageLifecycle(t, e)
+		})
+	}
+}
+
+// testStorageLifecycle creates a bucket, round-trips objects through it and
+// deletes it again, checking what each command reports along the way.
+func testStorageLifecycle(t *testing.T, e emulator) {
+	bucket := fmt.Sprintf("synkronus-e2e-%d", time.Now().UnixNano())
+	provider := []string{"--provider", e.provider}
+	objectFlags := append([]string{"--bucket", bucket}, provider...)
+
+	mustRun(t, append([]string{"storage", "buckets", "create", bucket, "--location", e.location}, provider...)...)
+	t.Cleanup(func() {
+		// Best effort, in case a step failed before the bucket was emptied
+		for _, key := range []string{"docs/hello.txt", "docs/copy.txt"} {
+			executeCommand(append([]string{"storage", "objects", "delete", key, "--force"}, objectFlags...)...)
+		}
+		executeCommand(append([]string{"storage", "buckets", "delete", bucket, "--force"}, provider...)...)
+	})
+
+	var buckets []storage.Bucket
+	decodeJSON(t, mustRun(t, "storage", "buckets", "list", "--providers", e.provider, "-o", "json"), &buckets)
+	if !containsBucket(buckets, bucket) {
+		t.Fatalf("expected %s in the bucket list", bucket)
+	}
+
+	var described storage.Bucket
+	decodeJSON(t, mustRun(t, append([]string{"storage", "buckets", "describe", bucket, "-o", "json"}, provider...)...), &described)
+	if described.Name != bucket {
+		t.Errorf("describe returned bucket %q, want %q", described.Name, bucket)
+	}
+
+	content := "hello from synkronus\n"
+	src := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(src, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mustRun(t, append([]string{"storage", "objects", "upload", src, "--key", "docs/hello.txt"}, objectFlags...)...)
+
+	var object storage.Object
+	decodeJSON(t, mustRun(t, append([]string{"storage", "objects", "describe", "docs/hello.txt", "-o", "json"}, objectFlags...)...), &object)
+	if object.Size != int64(len(content)) {
+		t.Errorf("object size = %d, want %d", object.Size, len(content))
+	}
+
+	mustRun(t, append([]string{"storage", "objects", "copy", "docs/hello.txt", "--dest-bucket", bucket, "--dest-key", "docs/copy.txt"}, objectFlags...)...)
+
+	var listing storage.ObjectList
+	decodeJSON(t, mustRun(t, append([]string{"storage", "objects", "list", "--prefix", "docs/", "-o", "json"}, objectFlags...)...), &listing)
+	if len(listing.Objects) != 2 {
+		t.Errorf("expected the original and the copy under docs/, got %d objects", len(listing.Objects))
+	}
+
+	dest := filepath.Join(t.TempDir(), "copy.txt")
+	mustRun(t, append([]string{"storage", "objects", "download", "docs/copy.txt", "--output-path", dest}, objectFlags...)...)
+	if got, err := os.ReadFile(dest); err != nil || string(got) != content {
+		t.Errorf("downloaded %q (%v), want %q", got, err, content)
+	}
+
+	for _, key := range []string{"docs/hello.txt", "docs/copy.txt"} {
+		mustRun(t, append([]string{"storage", "objects", "delete", key, "--force"}, objectFlags...)...)
+	}
+	mustRun(t, append([]string{"storage", "buckets", "delete", bucket, "--force"}, provider...)...)
+
+	decodeJSON(t, mustRun(t, "storage", "buckets", "list", "--providers", e.provider, "-o", "json"), &buckets)
+	if containsBucket(buckets, bucket) {
+		t.Errorf("expected %s to be gone after delete", bucket)
+	}
+}
+
+// mustRun runs the CLI with args and returns what it printed to stdout.
+// Commands print with fmt rather than to cobra's writer, so os.Stdout itself
+// is captured.
+func mustRun(t *testing.T, args ...string) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	captured := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		captured <- buf.String()
+	}()
+
+	_, runErr := executeCommand(args...)
+	os.Stdout = stdout
+	w.Close()
+	out := <-captured
+	r.Close()
+
+	if runErr != nil {
+		t.Fatalf("synkronus %s: %v\n%s", strings.Join(args, " "), runErr, out)
+	}
+	return out
+}
+
+func decodeJSON(t *testing.T, out string, v any) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(out), v); err != nil {
+		t.Fatalf("failed to decode JSON output: %v\n%s", err, out)
+	}
+}
+
+func containsBucket(buckets []storage.Bucket, name string) bool {
+	for _, b := range buckets {
+		if b.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// skipUnlessReachable skips the test when nothing listens at the endpoint.
+func skipUnlessReachable(t *testing.T, endpoint string) {
+	t.Helper()
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		t.Fatalf("invalid endpoint %q: %v", endpoint, err)
+	}
+	conn, err := net.DialTimeout("tcp", u.Host, time.Second)
+	if err != nil {
+		t.Skipf("emulator not reachable at %s: %v", u.Host, err)
+	}
+	conn.Close()
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
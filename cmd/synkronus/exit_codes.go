@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/service"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes set by Execute, so scripts can branch on the kind of failure
+// instead of parsing the error message
+const (
+	// exitFailure is any failure not covered by a more specific code
+	exitFailure = 1
+	// exitUsage is an invalid argument, flag or config value
+	exitUsage = 2
+	// exitAuth is missing or rejected credentials, or missing permissions
+	exitAuth = 3
+	// exitNotFound is a bucket, object or instance that does not exist
+	exitNotFound = 4
+	// exitPartial is a multi-provider listing that only some providers answered
+	exitPartial = 5
+	// exitAborted is a destructive operation the user declined to confirm
+	exitAborted = 6
+)
+
+// exitCodesHelp documents the exit codes in the root command's help
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  other failure
+  2  invalid arguments, flags or configuration
+  3  authentication or authorization failure
+  4  resource not found
+  5  some providers failed; the results of the others were shown
+  6  aborted at the confirmation prompt
+  130, 143  interrupted by Ctrl+C (SIGINT) or SIGTERM`
+
+// usageError marks an error caused by invalid arguments or flags.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// exitCode maps a command error to the exit code Execute returns.
+func exitCode(err error) int {
+	var usage *usageError
+	switch {
+	case errors.Is(err, ErrOperationAborted):
+		return exitAborted
+	case errors.As(err, &usage), errors.Is(err, config.ErrInvalidConfig):
+		return exitUsage
+	case errors.Is(err, service.ErrAuth):
+		return exitAuth
+	case errors.Is(err, service.ErrNotFound):
+		return exitNotFound
+	default:
+		return exitFailure
+	}
+}
+
+// markUsageErrors makes the flag parsing and argument validation errors of cmd
+// and its subcommands usage errors. Required flags are checked in the root
+// command's PersistentPreRunE, once config defaults have been applied.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+	cmd.Args = usageArgs(cmd.Args)
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// usageArgs wraps an argument validator so its errors are usage errors. Without
+// a validator, arguments are checked as cobra does by default: only the root
+// command rejects them, as unknown commands.
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if validate == nil {
+			if !cmd.HasSubCommands() || cmd.HasParent() || len(args) == 0 {
+				return nil
+			}
+			return &usageError{err: unknownCommandError(cmd, args[0])}
+		}
+		if err := validate(cmd, args); err != nil {
+			return &usageError{err: err}
+		}
+		return nil
+	}
+}
+
+// unknownCommandError matches cobra's error for an unknown subcommand,
+// including its suggestions.
+func unknownCommandError(cmd *cobra.Command, name string) error {
+	msg := fmt.Sprintf("unknown command %q for %q", name, cmd.CommandPath())
+	if cmd.SuggestionsMinimumDistance <= 0 {
+		cmd.SuggestionsMinimumDistance = 2
+	}
+	if suggestions := cmd.SuggestionsFor(name); len(suggestions) > 0 {
+		msg += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+	}
+	return errors.New(msg)
+}
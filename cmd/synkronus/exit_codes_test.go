@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"other", errors.New("boom"), exitFailure},
+		{"usage", &usageError{err: errors.New("bad flag")}, exitUsage},
+		{"invalid config", fmt.Errorf("setting configuration: %w", config.ErrInvalidConfig), exitUsage},
+		{"auth", fmt.Errorf("listing buckets: %w", service.ErrAuth), exitAuth},
+		{"not found", fmt.Errorf("describing bucket: %w", service.ErrNotFound), exitNotFound},
+		{"aborted", ErrOperationAborted, exitAborted},
+		{"provider failures", service.ProviderErrors{{Provider: "aws", Err: service.ErrAuth}}, exitAuth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkUsageErrors(t *testing.T) {
+	newTree := func() *cobra.Command {
+		root := &cobra.Command{Use: "synkronus", RunE: func(*cobra.Command, []string) error { return nil }, SilenceErrors: true, SilenceUsage: true}
+		root.Flags().Int("count", 0, "")
+		root.AddCommand(&cobra.Command{Use: "describe", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error { return nil }})
+		markUsageErrors(root)
+		return root
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantUsage bool
+	}{
+		{"valid", []string{"describe", "x"}, false},
+		{"no arguments for the root", nil, false},
+		{"wrong argument count", []string{"describe"}, true},
+		{"unknown command", []string{"descrbe"}, true},
+		{"invalid flag value", []string{"--count", "many"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newTree()
+			root.SetArgs(tt.args)
+			err := root.Execute()
+			var usage *usageError
+			if got := errors.As(err, &usage); got != tt.wantUsage {
+				t.Errorf("usage error = %v (err: %v), want %v", got, err, tt.wantUsage)
+			}
+		})
+	}
+}
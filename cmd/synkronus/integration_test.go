@@ -2,8 +2,13 @@ package main
 
 import (
 	"bytes"
+	"github.com/jkleinne/synkronus/internal/audit"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -148,3 +153,258 @@ func TestIntegration_InvalidProvider(t *testing.T) {
 		t.Fatal("expected error for unsupported provider 'azure', got nil")
 	}
 }
+
+// TestIntegration_CacheClear verifies that "cache clear" removes cached
+// entries and that --no-cache is accepted as a global flag.
+func TestIntegration_CacheClear(t *testing.T) {
+	setupIntegrationTest(t)
+
+	if _, err := executeCommand("config", "set", "cache.enabled", "true"); err != nil {
+		t.Fatalf("config set failed: %v", err)
+	}
+	entry := filepath.Join(os.Getenv("HOME"), ".config", "synkronus", "cache", "responses", "entry.json")
+	if err := os.MkdirAll(filepath.Dir(entry), 0700); err != nil {
+		t.Fatalf("failed to create cache dir: %v", err)
+	}
+	if err := os.WriteFile(entry, []byte("{}"), 0600); err != nil {
+		t.Fatalf("failed to write cache entry: %v", err)
+	}
+
+	if _, err := executeCommand("--no-cache", "cache", "clear"); err != nil {
+		t.Fatalf("cache clear failed: %v", err)
+	}
+	if _, err := os.Stat(entry); !os.IsNotExist(err) {
+		t.Errorf("expected cache entry to be removed, stat error: %v", err)
+	}
+}
+
+// TestIntegration_MaxRetries verifies that retry.max_retries can be set and that
+// --max-retries rejects values outside the range the config key accepts.
+func TestIntegration_MaxRetries(t *testing.T) {
+	setupIntegrationTest(t)
+
+	if _, err := executeCommand("config", "set", "retry.max_retries", "2"); err != nil {
+		t.Fatalf("config set failed: %v", err)
+	}
+	if _, err := executeCommand("--max-retries", "0", "config", "get", "retry.max_retries"); err != nil {
+		t.Errorf("expected --max-retries 0 to be accepted, got: %v", err)
+	}
+	if _, err := executeCommand("--max-retries", "-1", "config", "get", "retry.max_retries"); err == nil {
+		t.Error("expected error for a negative --max-retries")
+	}
+	if _, err := executeCommand("--max-retries", "11", "config", "get", "retry.max_retries"); err == nil {
+		t.Error("expected error for a --max-retries above the limit")
+	}
+}
+
+// TestIntegration_Timeout verifies that --timeout accepts durations and rejects
+// negative values.
+func TestIntegration_Timeout(t *testing.T) {
+	setupIntegrationTest(t)
+
+	if _, err := executeCommand("--timeout", "30s", "config", "list"); err != nil {
+		t.Errorf("expected --timeout 30s to be accepted, got: %v", err)
+	}
+	if _, err := executeCommand("--timeout", "-1s", "config", "list"); err == nil {
+		t.Error("expected error for a negative --timeout")
+	}
+	if _, err := executeCommand("--timeout", "soon", "config", "list"); err == nil {
+		t.Error("expected error for an unparseable --timeout")
+	}
+}
+
+// TestIntegration_LogFile verifies that --log-file sends logs to the file.
+func TestIntegration_LogFile(t *testing.T) {
+	setupIntegrationTest(t)
+	logPath := filepath.Join(t.TempDir(), "synkronus.log")
+
+	if _, err := executeCommand("--debug", "--log-file", logPath, "config", "list"); err != nil {
+		t.Fatalf("config list failed: %v", err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("expected the log file to be created: %v", err)
+	}
+	if !strings.Contains(string(data), "Debug logging enabled") {
+		t.Errorf("expected debug logs in the file, got %q", data)
+	}
+}
+
+// TestIntegration_LogFormat verifies that --log-format json writes JSON records
+// and that unknown formats are rejected.
+func TestIntegration_LogFormat(t *testing.T) {
+	setupIntegrationTest(t)
+	logPath := filepath.Join(t.TempDir(), "synkronus.log")
+
+	if _, err := executeCommand("--debug", "--log-file", logPath, "--log-format", "json", "config", "list"); err != nil {
+		t.Fatalf("config list failed: %v", err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("expected the log file to be created: %v", err)
+	}
+	if !strings.Contains(string(data), `"msg":"Debug logging enabled"`) {
+		t.Errorf("expected JSON records in the file, got %q", data)
+	}
+
+	if _, err := executeCommand("--log-format", "xml", "config", "list"); err == nil {
+		t.Error("expected an error for an unsupported log format")
+	}
+}
+
+// TestIntegration_AuditShow verifies that 'audit show' reads the audit log in
+// every output format and rejects a negative --limit.
+func TestIntegration_AuditShow(t *testing.T) {
+	setupIntegrationTest(t)
+
+	if _, err := executeCommand("audit", "show"); err != nil {
+		t.Fatalf("audit show on an empty log failed: %v", err)
+	}
+
+	path, err := audit.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.New(path, "").Record("delete-bucket", "gcp", "old-logs", nil); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	for _, format := range []string{"table", "json", "yaml"} {
+		if _, err := executeCommand("audit", "show", "-o", format); err != nil {
+			t.Errorf("audit show -o %s failed: %v", format, err)
+		}
+	}
+
+	if _, err := executeCommand("audit", "show", "--limit", "-1"); err == nil {
+		t.Error("expected an error for a negative --limit")
+	}
+}
+
+// TestIntegration_DocsMan verifies that "docs man" writes a page per command.
+func TestIntegration_DocsMan(t *testing.T) {
+	setupIntegrationTest(t)
+	dir := filepath.Join(t.TempDir(), "man")
+
+	if _, err := executeCommand("docs", "man", dir); err != nil {
+		t.Fatalf("docs man failed: %v", err)
+	}
+	for _, page := range []string{"synkronus.1", "synkronus-storage-buckets-create.1"} {
+		data, err := os.ReadFile(filepath.Join(dir, page))
+		if err != nil {
+			t.Fatalf("expected %s: %v", page, err)
+		}
+		if !strings.Contains(string(data), ".TH \"SYNKRONUS\"") {
+			t.Errorf("%s has no man page header", page)
+		}
+	}
+}
+
+// TestIntegration_DocsMarkdown verifies that "docs markdown" writes a page per command.
+func TestIntegration_DocsMarkdown(t *testing.T) {
+	setupIntegrationTest(t)
+	dir := t.TempDir()
+
+	if _, err := executeCommand("docs", "markdown", dir); err != nil {
+		t.Fatalf("docs markdown failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "synkronus_storage_find.md"))
+	if err != nil {
+		t.Fatalf("expected a page for 'storage find': %v", err)
+	}
+	if !strings.Contains(string(data), "--name-pattern") {
+		t.Error("expected the page to document --name-pattern")
+	}
+}
+
+// TestIntegration_ServeRequiresToken verifies that "serve" refuses to start an
+// unauthenticated API.
+func TestIntegration_ServeRequiresToken(t *testing.T) {
+	setupIntegrationTest(t)
+	t.Setenv(serverTokenEnv, "")
+
+	_, err := executeCommand("serve", "--demo")
+	if err == nil || exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error without a token, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "server.token") {
+		t.Errorf("expected the error to explain how to set the token, got %v", err)
+	}
+}
+
+// TestIntegration_ServeInvalidAddress verifies that a bad listen address is
+// reported instead of serving.
+func TestIntegration_ServeInvalidAddress(t *testing.T) {
+	setupIntegrationTest(t)
+	t.Setenv(serverTokenEnv, "token")
+
+	if _, err := executeCommand("serve", "--demo", "--address", "256.0.0.1:99999"); err == nil {
+		t.Fatal("expected an error for an invalid address")
+	}
+}
+
+func TestIntegration_ServeInvalidGRPCAddress(t *testing.T) {
+	setupIntegrationTest(t)
+	t.Setenv(serverTokenEnv, "token")
+
+	_, err := executeCommand("serve", "--demo", "--address", "127.0.0.1:0", "--grpc-address", "256.0.0.1:99999")
+	if err == nil || !strings.Contains(err.Error(), "256.0.0.1:99999") {
+		t.Fatalf("expected an error naming the gRPC address, got %v", err)
+	}
+}
+
+func TestIntegration_ServeInvalidJobsFile(t *testing.T) {
+	setupIntegrationTest(t)
+	t.Setenv(serverTokenEnv, "token")
+	jobsFile := filepath.Join(t.TempDir(), "jobs.yaml")
+	os.WriteFile(jobsFile, []byte("jobs:\n  - name: nightly\n    schedule: \"every night\"\n"), 0600)
+
+	_, err := executeCommand("serve", "--demo", "--address", "127.0.0.1:0", "--jobs-file", jobsFile)
+	if err == nil || !strings.Contains(err.Error(), "jobs file") {
+		t.Fatalf("expected an error for the invalid jobs file, got %v", err)
+	}
+}
+
+func TestIntegration_JobsList(t *testing.T) {
+	setupIntegrationTest(t)
+	jobsFile := filepath.Join(t.TempDir(), "jobs.yaml")
+	os.WriteFile(jobsFile, []byte(`jobs:
+  - name: nightly
+    schedule: "@daily"
+    source: {provider: mock, bucket: acme-data-lake}
+    destination: {provider: mock, bucket: acme-backups}
+`), 0600)
+
+	if _, err := executeCommand("jobs", "list", "--jobs-file", jobsFile); err != nil {
+		t.Fatalf("jobs list failed: %v", err)
+	}
+	if _, err := executeCommand("jobs", "history", "nightly"); err != nil {
+		t.Fatalf("jobs history failed: %v", err)
+	}
+	if _, err := executeCommand("jobs", "history", "--limit", "-1"); err == nil {
+		t.Error("expected an error for a negative --limit")
+	}
+}
+
+func TestIntegration_JobsTestNotification(t *testing.T) {
+	setupIntegrationTest(t)
+	received := make(chan string, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+	}))
+	t.Cleanup(hook.Close)
+
+	if _, err := executeCommand("jobs", "test-notification", "ops"); err == nil {
+		t.Error("expected an error without notifiers")
+	}
+	for _, kv := range [][2]string{{"notifications.ops.type", "slack"}, {"notifications.ops.url", hook.URL}} {
+		if _, err := executeCommand("config", "set", kv[0], kv[1]); err != nil {
+			t.Fatalf("config set %s failed: %v", kv[0], err)
+		}
+	}
+	if _, err := executeCommand("jobs", "test-notification", "ops"); err != nil {
+		t.Fatalf("jobs test-notification failed: %v", err)
+	}
+	if body := <-received; !strings.Contains(body, "notification-test failed") {
+		t.Errorf("unexpected message: %s", body)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// interruptedError is the cancellation cause of a command stopped by a signal.
+type interruptedError struct {
+	signal os.Signal
+}
+
+func (e *interruptedError) Error() string {
+	if e.signal == syscall.SIGTERM {
+		return "terminated by SIGTERM"
+	}
+	return "interrupted by Ctrl+C"
+}
+
+// exitCode follows the shell convention of 128 plus the signal number.
+func (e *interruptedError) exitCode() int {
+	if sig, ok := e.signal.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return 128 + int(syscall.SIGINT)
+}
+
+// notifyInterrupt returns a context canceled with an *interruptedError on the
+// first SIGINT or SIGTERM, so the command can stop its provider calls, remove
+// partial downloads, and flush the output it has produced. The handler is then
+// removed, so a second Ctrl+C kills the process at once. stop releases the
+// handler when the command finishes first.
+func notifyInterrupt(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			cancel(&interruptedError{signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(nil)
+	}
+}
+
+// interruption returns the signal that canceled ctx, if any.
+func interruption(ctx context.Context) *interruptedError {
+	var interrupted *interruptedError
+	if errors.As(context.Cause(ctx), &interrupted) {
+		return interrupted
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyInterrupt_CancelsOnSignal(t *testing.T) {
+	tests := []struct {
+		signal   syscall.Signal
+		wantCode int
+	}{
+		{syscall.SIGINT, 130},
+		{syscall.SIGTERM, 143},
+	}
+	for _, tt := range tests {
+		t.Run(tt.signal.String(), func(t *testing.T) {
+			ctx, stop := notifyInterrupt(context.Background())
+			defer stop()
+
+			if err := syscall.Kill(syscall.Getpid(), tt.signal); err != nil {
+				t.Fatalf("sending %v: %v", tt.signal, err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected the signal to cancel the context")
+			}
+
+			interrupted := interruption(ctx)
+			if interrupted == nil {
+				t.Fatalf("expected an interruption cause, got %v", context.Cause(ctx))
+			}
+			if got := interrupted.exitCode(); got != tt.wantCode {
+				t.Errorf("exitCode() = %d, want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestNotifyInterrupt_StopIsNotAnInterruption(t *testing.T) {
+	ctx, stop := notifyInterrupt(context.Background())
+	stop()
+
+	if ctx.Err() == nil {
+		t.Fatal("expected stop to cancel the context")
+	}
+	if interruption(ctx) != nil {
+		t.Error("expected no interruption when the command finished first")
+	}
+}
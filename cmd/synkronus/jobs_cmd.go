@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// newJobsCmd returns the "jobs" parent command for scheduled sync jobs.
+func newJobsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Inspect scheduled sync jobs and their runs",
+		Long: `'synkronus serve' runs sync jobs on cron schedules, turning synkronus into a lightweight
+replication service. Jobs are defined in the jobs section of the config file, or in a YAML
+jobs file passed with 'serve --jobs-file' (or set as server.jobs_file):
+
+  jobs:
+    - name: nightly-exports
+      schedule: "0 2 * * *"          # minute hour day-of-month month day-of-week
+      source: {provider: gcp, bucket: data, prefix: exports/}
+      destination: {provider: aws, bucket: data-replica}
+      include: ["*.csv", "daily/*"]  # optional; patterns without "/" match the file name
+      exclude: ["*.tmp"]             # optional
+      verify: true                   # optional; compare checksums after the copy
+
+Schedules are evaluated in the server's local time zone and also accept @hourly, @daily,
+@weekly, @monthly and "@every <duration>" (e.g., "@every 15m"). Each run copies the source
+objects that are missing or different at the destination and is appended to
+~/.config/synkronus/jobs.log. With verify, the destination is then compared with the source
+(size, and MD5 or CRC32C when both providers report it), and the run fails, listing the
+objects in its result, if any are still missing or different. Syncs submitted through the
+API take "verify": true the same way; 'synkronus storage diff' makes the same check on demand.
+
+Notifiers in the notifications section of the config file are told when a sync job (scheduled
+or submitted through the API) finishes. Each has a name and a type (webhook, slack or email):
+
+  synkronus config set notifications.ops-slack.type slack
+  synkronus config set notifications.ops-slack.url https://hooks.slack.com/services/...
+  synkronus config set notifications.ops-slack.on failure           # optional; success,failure
+  synkronus config set notifications.ops-slack.jobs nightly-exports # optional; scheduled jobs
+
+Email notifiers set smtp_address (host:port), from and to (comma-separated), and optionally
+smtp_username and smtp_password (store it with 'config set --keychain'). Webhooks accept
+extra request headers in headers (e.g., notifications.hook.headers.Authorization).
+
+A webhook POSTs the job as JSON (job_id, job, status, source, destination, copied, skipped,
+bytes, failed_count, failures, error, started_at, finished_at). The message of any notifier
+can be replaced with a Go template over those fields in 'template' (and an email's subject
+in 'subject'), e.g. "{{.Name}} {{.Status}}: {{bytes .Bytes}} in {{.Duration}}"; {{json .Error}}
+quotes a value for a JSON body.`,
+	}
+	cmd.AddCommand(newJobsListCmd(), newJobsHistoryCmd(), newJobsTestNotificationCmd())
+	return cmd
+}
+
+// scheduledJobs returns the jobs of the config file followed by those of
+// jobsFile, if set.
+func scheduledJobs(cfg *config.Config, jobsFile string) ([]config.SyncJob, error) {
+	jobs := slices.Clone(cfg.Jobs)
+	if jobsFile != "" {
+		fileJobs, err := config.LoadJobsFile(jobsFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range fileJobs {
+			for _, existing := range jobs {
+				if existing.Name == job.Name {
+					return nil, fmt.Errorf("scheduled job %q is defined both in the config file and in %s", job.Name, jobsFile)
+				}
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	for _, job := range jobs {
+		if job.MetadataTemplate == "" {
+			continue
+		}
+		if _, err := cfg.MetadataTemplate(job.MetadataTemplate); err != nil {
+			return nil, fmt.Errorf("scheduled job %q: %w", job.Name, err)
+		}
+	}
+	return jobs, nil
+}
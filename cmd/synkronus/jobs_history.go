@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/server"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newJobsHistoryCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history [job-name]",
+		Short: "Show the runs of sync jobs",
+		Long: `Displays the most recent sync jobs that 'synkronus serve' finished, oldest first, from
+~/.config/synkronus/jobs.log. With a job name, only the runs of that scheduled job are shown.`,
+		Example: `  synkronus jobs history
+  synkronus jobs history nightly-exports --limit 10`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if limit < 0 {
+				return fmt.Errorf("--%s must not be negative, got %d", flags.Limit, limit)
+			}
+			var name string
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			path, err := server.HistoryPath()
+			if err != nil {
+				return err
+			}
+			jobs, err := server.ReadHistory(path, name, limit)
+			if err != nil {
+				return err
+			}
+			if len(jobs) == 0 && app.OutputFormat == output.FormatTable {
+				fmt.Println("No job runs recorded yet.")
+				return nil
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.JobHistoryView(jobs))
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, flags.Limit, 20, "Number of most recent runs to show; 0 shows all")
+	return cmd
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/schedule"
+	"github.com/jkleinne/synkronus/internal/server"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newJobsListCmd() *cobra.Command {
+	var jobsFile string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled sync jobs with their next and last runs",
+		Long: `Lists the scheduled sync jobs that 'synkronus serve' would run, from the config file and
+--jobs-file (or server.jobs_file), with the time each next comes due and its last recorded run.`,
+		Example: `  synkronus jobs list
+  synkronus jobs list --jobs-file /etc/synkronus/jobs.yaml -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed(flags.JobsFile) && app.Config.Server != nil {
+				jobsFile = app.Config.Server.JobsFile
+			}
+			jobs, err := scheduledJobs(app.Config, jobsFile)
+			if err != nil {
+				return err
+			}
+			if len(jobs) == 0 && app.OutputFormat == output.FormatTable {
+				fmt.Println("No scheduled jobs defined. See 'synkronus jobs --help' for the format.")
+				return nil
+			}
+
+			historyPath, err := server.HistoryPath()
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			view := make(output.ScheduledJobsView, len(jobs))
+			for i, job := range jobs {
+				view[i].SyncJob = job
+				if when, err := schedule.Parse(job.Schedule); err == nil {
+					if next := when.Next(now); !next.IsZero() {
+						view[i].NextRun = &next
+					}
+				}
+				runs, err := server.ReadHistory(historyPath, job.Name, 1)
+				if err != nil {
+					return err
+				}
+				if len(runs) > 0 {
+					view[i].LastRun = &runs[0]
+				}
+			}
+			return output.Render(os.Stdout, app.OutputFormat, view)
+		},
+	}
+
+	cmd.Flags().StringVar(&jobsFile, flags.JobsFile, "", "YAML file of scheduled sync jobs, in addition to the jobs in the config file (overrides server.jobs_file)")
+	return cmd
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/notify"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newJobsTestNotificationCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test-notification <notifier>",
+		Short: "Send a sample job notification to check a notifier",
+		Long: `Sends a notification for a made-up failed sync job through one of the notifiers of the
+notifications section, regardless of its 'on' and 'jobs' filters, so its URL, credentials and
+templates can be checked before a real job finishes.`,
+		Example: `  synkronus jobs test-notification ops-slack`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			notifier, err := notify.New(app.Config.Notifications, app.Logger)
+			if err != nil {
+				return err
+			}
+			if !notifier.Has(args[0]) {
+				names := notifier.Names()
+				if len(names) == 0 {
+					return &usageError{err: fmt.Errorf("no notifiers configured; see 'synkronus jobs --help'")}
+				}
+				return &usageError{err: fmt.Errorf("unknown notifier %q (configured: %s)", args[0], strings.Join(names, ", "))}
+			}
+			if err := notifier.Send(cmd.Context(), args[0], notify.SampleEvent()); err != nil {
+				return err
+			}
+			fmt.Printf("Sent a sample notification to '%s'.\n", args[0])
+			return nil
+		},
+	}
+}
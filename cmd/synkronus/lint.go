@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"github.com/jkleinne/synkronus/internal/lint"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newLintCmd() *cobra.Command {
+	var providersList []string
+	var rulesPath string
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check buckets against naming, labeling, location and encryption rules",
+		Long: `Describes every bucket of the configured providers (or --providers) and checks it against the
+conventions of a rules file:
+
+  buckets:
+    name_pattern: '^acme-[a-z0-9-]+$'   # regular expression names must match
+    required_labels: [team, env]        # labels (tags on S3) every bucket must have
+    label_values:                       # allowed values of labels
+      env: [prod, staging, dev]
+    default_labels:                     # values 'storage enforce-labels' adds
+      env: dev
+    locations: [EU, europe-west1, eu-west-1]
+    encryption: customer-managed        # CMEK, or SSE-KMS with a key other than aws/s3
+
+Rules left out are not checked. Exits non-zero when any bucket breaks a rule, so the command
+can fail a CI pipeline. Buckets that cannot be described are reported as warnings on stderr.`,
+		Example: `  synkronus lint --rules policy.yaml
+  synkronus lint --rules policy.yaml --providers gcp -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			rules, err := readLintRules(rulesPath)
+			if err != nil {
+				return err
+			}
+			providers, err := storageProviderResolver(app).Resolve(providersList)
+			if err != nil {
+				return err
+			}
+
+			buckets, describeErr := inventory.Describe(cmd.Context(), app.StorageService, providers)
+			if describeErr != nil && len(buckets) == 0 {
+				return describeErr
+			}
+			report := lint.Check(buckets, rules)
+			if err := output.Render(os.Stdout, app.OutputFormat, output.LintReportView(report)); err != nil {
+				return err
+			}
+			app.PartialResults = describeErr != nil
+			if err := renderInventoryWarnings(os.Stderr, describeErr); err != nil {
+				return err
+			}
+			if len(report.Violations) > 0 {
+				return fmt.Errorf("%d rule violations found", len(report.Violations))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&rulesPath, flags.Rules, "", "The rules file to check against (required)")
+	cmd.MarkFlagRequired(flags.Rules)
+	cmd.Flags().StringSliceVarP(&providersList, flags.Providers, flags.ProvidersShort, nil, "Specify providers to check (comma-separated). Defaults to all configured providers.")
+
+	return cmd
+}
+
+// readLintRules reads the rules file at path.
+func readLintRules(path string) (lint.Rules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return lint.Rules{}, &usageError{err: fmt.Errorf("failed to open rules file: %w", err)}
+	}
+	defer f.Close()
+	rules, err := lint.ReadRules(f)
+	if err != nil {
+		return lint.Rules{}, &usageError{err: fmt.Errorf("%s: %w", path, err)}
+	}
+	return rules, nil
+}
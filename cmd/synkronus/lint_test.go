@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestLintCmd(t *testing.T) {
+	mock := &cmdMockStorage{
+		buckets: []storage.Bucket{{Name: "data", Provider: domain.GCP}},
+		bucket:  storage.Bucket{Name: "data", Provider: domain.GCP, Location: "EU", Labels: map[string]string{"team": "data"}},
+	}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{"Passes", "buckets:\n  required_labels: [team]\n  locations: [eu]\n", false},
+		{"Violation", "buckets:\n  required_labels: [team, env]\n", true},
+		{"InvalidRules", "buckets:\n  name_pattern: '('\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".yaml")
+			if err := os.WriteFile(path, []byte(tt.rules), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cmd := newLintCmd()
+			cmd.SetContext(app.ToContext(context.Background()))
+			cmd.SetArgs([]string{"--rules", path})
+
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,7 +4,7 @@ package main
 import (
 	// Explicitly import the provider registration package to ensure all provider init() functions run
 	// and they register themselves (both storage and SQL providers)
-	_ "synkronus/internal/provider"
+	_ "github.com/jkleinne/synkronus/internal/provider"
 )
 
 func main() {
@@ -0,0 +1,18 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newPricingCmd returns the "pricing" parent command for managing the prices
+// cost estimates are made with.
+func newPricingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Manage the pricing data cost estimates use",
+		Long: `Cost estimates (e.g., 'synkronus storage recommend') use a dataset of list prices per
+provider, region and storage class, with operation, retrieval and egress prices. A dataset
+is bundled with synkronus, so estimates work offline and are the same from run to run;
+'synkronus pricing update' installs a newer one in the config directory.`,
+	}
+	cmd.AddCommand(newPricingUpdateCmd())
+	return cmd
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/pricing"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newPricingUpdateCmd() *cobra.Command {
+	var url string
+	var file string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Download the latest pricing data",
+		Long: `Downloads the pricing dataset of the latest release and installs it in the config directory,
+where cost estimates pick it up instead of the bundled dataset as long as it is newer.
+Use --file to install a dataset from a local file (e.g., on a machine without internet
+access), or --url to download it from a mirror. A dataset older than the one in use is
+not installed unless --force is given.`,
+		Example: `  synkronus pricing update
+  synkronus pricing update --file prices.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			current, err := pricing.Load()
+			if err != nil && !force {
+				return err
+			}
+
+			var update *pricing.Dataset
+			var data []byte
+			if file != "" {
+				if data, err = os.ReadFile(file); err != nil {
+					return fmt.Errorf("failed to read pricing data: %w", err)
+				}
+				if update, err = pricing.Parse(data); err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+			} else {
+				client := &http.Client{Timeout: 30 * time.Second}
+				if update, data, err = pricing.Fetch(cmd.Context(), client, url); err != nil {
+					return err
+				}
+			}
+
+			// current is nil when the installed dataset is invalid and --force replaces it
+			if current != nil && update.Version <= current.Version && !force {
+				if update.Version == current.Version {
+					fmt.Printf("Pricing data is up to date (%s).\n", current.Version)
+					return nil
+				}
+				return fmt.Errorf("pricing data %s is older than the data in use (%s); use --%s to install it anyway", update.Version, current.Version, flags.Force)
+			}
+
+			path, err := pricing.Path()
+			if err != nil {
+				return err
+			}
+			if err := pricing.Install(path, data); err != nil {
+				return err
+			}
+			if bundled := pricing.Bundled(); update.Version <= bundled.Version {
+				fmt.Fprintf(os.Stderr, "Warning: the bundled pricing data (%s) is newer, so it is used instead.\n", bundled.Version)
+			}
+			if current != nil {
+				fmt.Printf("Pricing data updated from %s to %s.\n", current.Version, update.Version)
+			} else {
+				fmt.Printf("Pricing data %s installed.\n", update.Version)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&url, flags.URL, pricing.DefaultURL, "The URL to download the pricing data from")
+	cmd.Flags().StringVar(&file, flags.PricingFile, "", "Install the pricing data of this local file instead of downloading it")
+	cmd.Flags().BoolVarP(&force, flags.Force, flags.ForceShort, false, "Install the pricing data even if it is older than the data in use")
+	cmd.MarkFlagsMutuallyExclusive(flags.URL, flags.PricingFile)
+
+	return cmd
+}
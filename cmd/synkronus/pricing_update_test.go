@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/pricing"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func pricingDataset(version string) string {
+	return `{"version": "` + version + `", "currency": "USD", "providers": {"gcp": {"default_region": "us-central1",
+		"regions": {"us-central1": {"storage_classes": {"STANDARD": {"storage_per_gib_month": 0.03}}}}}}}`
+}
+
+func TestPricingUpdate(t *testing.T) {
+	setupIntegrationTest(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(pricingDataset("2999-01-01")))
+	}))
+	defer srv.Close()
+
+	if _, err := executeCommand("pricing", "update", "--url", srv.URL); err != nil {
+		t.Fatalf("pricing update failed: %v", err)
+	}
+	d, err := pricing.Load()
+	if err != nil || d.Version != "2999-01-01" {
+		t.Fatalf("expected the downloaded dataset to be used, got %+v, %v", d, err)
+	}
+	// Downloading the same version again leaves it in place
+	if _, err := executeCommand("pricing", "update", "--url", srv.URL); err != nil {
+		t.Errorf("expected an up-to-date dataset to be accepted, got %v", err)
+	}
+
+	older := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(older, []byte(pricingDataset("2998-01-01")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeCommand("pricing", "update", "--file", older); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected an older dataset to need --force, got %v", err)
+	}
+	if _, err := executeCommand("pricing", "update", "--file", older, "--force"); err != nil {
+		t.Fatalf("pricing update --force failed: %v", err)
+	}
+	if d, err := pricing.Load(); err != nil || d.Version != "2998-01-01" {
+		t.Errorf("expected the forced dataset to be used, got %+v, %v", d, err)
+	}
+}
+
+func TestPricingUpdate_InvalidFile(t *testing.T) {
+	setupIntegrationTest(t)
+	invalid := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(invalid, []byte(`{"version": "soon"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeCommand("pricing", "update", "--file", invalid); err == nil {
+		t.Error("expected an invalid dataset to be rejected")
+	}
+	path, _ := pricing.Path()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected nothing to be installed, stat error: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newProfileCmd returns the "profile" parent command with use/list subcommands.
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named configuration profiles",
+		Long: `Manage named configuration profiles. A profile is a self-contained set of provider settings
+stored under 'profiles.<name>' (e.g., 'synkronus config set profiles.prod.gcp.project my-prod-123').
+The top-level provider settings form the implicit 'default' profile.`,
+	}
+	cmd.AddCommand(newProfileUseCmd(), newProfileListCmd())
+	return cmd
+}
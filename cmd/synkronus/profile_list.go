@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newProfileListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List configuration profiles",
+		Long:  `Displays all configured profiles and the GCP credentials each one uses. The profile in effect for this command is marked with '*'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			names, err := app.ConfigManager.ListProfiles()
+			if err != nil {
+				return err
+			}
+
+			active := app.ConfigManager.ActiveProfile()
+			fmt.Println("Profiles:")
+			for _, name := range names {
+				marker := " "
+				if name == active {
+					marker = "*"
+				}
+
+				cfg, err := app.ConfigManager.ProfileConfig(name)
+				if err != nil {
+					return err
+				}
+				if cfg.GCP != nil {
+					fmt.Printf("%s %-15s gcp: %s\n", marker, name, cfg.GCP.CredentialsSource())
+				} else {
+					fmt.Printf("%s %s\n", marker, name)
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newProfileUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [profile]",
+		Short: "Set the active configuration profile",
+		Long:  `Persists the given profile as the active one for subsequent commands. Use 'default' to return to the top-level provider settings. For example: 'synkronus profile use prod'`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			name := args[0]
+			if err := app.ConfigManager.UseProfile(name); err != nil {
+				return fmt.Errorf("switching to profile %q: %w", name, err)
+			}
+			fmt.Printf("Active profile set to '%s'\n", name)
+			return nil
+		},
+	}
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/service"
+	"io"
 	"strings"
 )
 
@@ -30,6 +34,17 @@ func isInList(list func() []string) func(string) bool {
 	}
 }
 
+// storageProviderResolver returns the resolver of the storage providers of app.
+func storageProviderResolver(app *appContainer) *ProviderResolver {
+	return &ProviderResolver{
+		IsSupported:   isInList(app.ProviderFactory.SupportedStorageProviders),
+		IsConfigured:  app.ProviderFactory.IsConfigured,
+		GetConfigured: app.ProviderFactory.ConfiguredStorageProviders,
+		GetSupported:  app.ProviderFactory.SupportedStorageProviders,
+		Label:         "storage",
+	}
+}
+
 // Resolve validates the requested providers and returns a deduplicated, normalized list.
 // If no providers are requested, returns all configured providers.
 func (r *ProviderResolver) Resolve(requested []string) ([]string, error) {
@@ -60,8 +75,42 @@ func (r *ProviderResolver) Resolve(requested []string) ([]string, error) {
 	}
 
 	if len(unsupported) > 0 {
-		return nil, fmt.Errorf("unsupported %s providers requested: %v. Supported %s providers are: %v", r.Label, unsupported, r.Label, r.GetSupported())
+		return nil, &usageError{err: fmt.Errorf("unsupported %s providers requested: %v. Supported %s providers are: %v", r.Label, unsupported, r.Label, r.GetSupported())}
 	}
 
 	return validated, nil
 }
+
+// Reports the providers that failed in a multi-provider listing as a warnings section.
+// Other errors are reported as a single warning line.
+func renderProviderWarnings(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	var failures service.ProviderErrors
+	if !errors.As(err, &failures) {
+		_, writeErr := fmt.Fprintf(w, "Warning: %v\n", err)
+		return writeErr
+	}
+
+	warnings := make([]output.ProviderWarning, len(failures))
+	for i, failure := range failures {
+		warnings[i] = output.ProviderWarning{Provider: failure.Provider, Message: failure.Err.Error()}
+	}
+	return output.RenderWarnings(w, warnings)
+}
+
+// renderInventoryWarnings reports the failures of an inventory, which joins
+// the providers that failed to list with the buckets that failed to describe.
+func renderInventoryWarnings(w io.Writer, err error) error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return renderProviderWarnings(w, err)
+	}
+	for _, e := range joined.Unwrap() {
+		if err := renderProviderWarnings(w, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/service"
 	"strings"
 	"testing"
 )
@@ -108,3 +111,35 @@ func TestResolve_AllConfiguredWhenNoneConfigured(t *testing.T) {
 		t.Errorf("expected empty result, got %v", result)
 	}
 }
+
+func TestRenderProviderWarnings(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"no error", nil, nil},
+		{
+			"provider failures",
+			service.ProviderErrors{{Provider: "aws", Err: errors.New("access denied")}},
+			[]string{"-- Warnings --", "aws", "access denied"},
+		},
+		{"other error", errors.New("boom"), []string{"Warning: boom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderProviderWarnings(&buf, tt.err); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == nil && buf.Len() != 0 {
+				t.Errorf("expected no output, got %q", buf.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected output to contain %q, got %q", want, buf.String())
+				}
+			}
+		})
+	}
+}
@@ -2,15 +2,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/metrics"
+	"github.com/jkleinne/synkronus/internal/network"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/retry"
+	"github.com/jkleinne/synkronus/internal/tui"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"synkronus/internal/config"
-	"synkronus/internal/flags"
-	"synkronus/internal/output"
-	"synkronus/internal/tui"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -20,34 +27,109 @@ const debugLogFileName = "debug.log"
 // Creates the root command, defines global flags, and sets up the initialization hook
 func newRootCmd() *cobra.Command {
 	var debugMode bool
+	var debugHTTP bool
 	var outputFormatStr string
+	var profile string
+	var impersonate string
+	var noCache bool
+	var maxRetries int
+	var timeout time.Duration
+	var providerTimeout time.Duration
+	var logFile string
+	var logFormat string
+	var metricsAddr string
+	var dryRun bool
+	var yes bool
+	var demo bool
 
 	cmd := &cobra.Command{
 		Use:   "synkronus",
 		Short: "Synkronus is a command-line tool for managing cloud resources.",
 		Long: `A unified CLI to interact with various cloud services for resources
 like storage, SQL databases, and more. Configure your providers and
-manage your infrastructure from one place.`,
+manage your infrastructure from one place.
+
+Define shortcuts with command aliases, e.g.
+'synkronus config set alias.lsb "storage list-buckets -o json"' makes 'synkronus lsb' work.
+
+` + exitCodesHelp,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			// Parse and validate the output format flag
-			outputFormat, err := output.ParseFormat(outputFormatStr)
+			// Initialize the application container
+			app, err := newApp(appOptions{
+				Debug:         debugMode,
+				Profile:       profile,
+				Impersonate:   impersonate,
+				LogFile:       logFile,
+				LogFormat:     logFormat,
+				DebugHTTP:     debugHTTP,
+				Demo:          demo,
+				ConfigManager: configManagerFromContext(cmd.Context()),
+			})
 			if err != nil {
+				return fmt.Errorf("failed to initialize application: %w", err)
+			}
+
+			// Resolve bucket aliases first so an alias's provider wins over defaults.provider
+			if err := applyBucketAliases(cmd, args, app.Config); err != nil {
 				return err
 			}
 
-			// Initialize the application container
-			app, err := newApp(debugMode, outputFormat)
+			// --yes stands in for --force, so it also wins over defaults.confirm
+			if err := applyYesFlag(cmd, yes); err != nil {
+				return err
+			}
+
+			// Fill in flags not given on the command line from the config defaults
+			if err := applyCommandDefaults(cmd, app.Config.Defaults); err != nil {
+				return err
+			}
+			// Checked here rather than by cobra, after PersistentPreRunE, so a
+			// missing flag is reported as a usage error
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return &usageError{err: err}
+			}
+
+			// --max-retries wins over retry.max_retries
+			if cmd.Flags().Changed(flags.MaxRetries) {
+				if maxRetries < 0 || maxRetries > retry.MaxMaxRetries {
+					return &usageError{err: fmt.Errorf("--%s must be between 0 and %d, got %d", flags.MaxRetries, retry.MaxMaxRetries, maxRetries)}
+				}
+				app.SetMaxRetries(maxRetries)
+			}
+
+			if timeout < 0 {
+				return &usageError{err: fmt.Errorf("--%s must not be negative, got %s", flags.Timeout, timeout)}
+			}
+			// --provider-timeout wins over fanout.provider_timeout
+			if cmd.Flags().Changed(flags.ProviderTimeout) {
+				if providerTimeout < 0 {
+					return &usageError{err: fmt.Errorf("--%s must not be negative, got %s", flags.ProviderTimeout, providerTimeout)}
+				}
+				app.SetProviderTimeout(providerTimeout)
+			}
+
+			// Parse and validate the output format flag
+			app.OutputFormat, err = output.ParseFormat(outputFormatStr)
 			if err != nil {
-				return fmt.Errorf("failed to initialize application: %w", err)
+				return &usageError{err: err}
 			}
 
+			app.DryRun = dryRun
+
 			if debugMode {
-				app.Logger.Debug("Debug logging enabled")
+				app.Logger.Debug("Debug logging enabled", "profile", app.ConfigManager.ActiveProfile(), "local_config", app.ConfigManager.LocalConfigPath())
 			}
 
 			// Inject the initialized container into the command's context
 			// so subcommands can access it
 			ctx := app.ToContext(cmd.Context())
+			if noCache {
+				ctx = cache.WithBypass(ctx)
+			}
+			// Bounds the whole command, so hung API calls fail instead of blocking.
+			// The TUI sets its own per-request timeouts.
+			ctx = app.WithTimeout(ctx, timeout)
+			ctx = app.StartCommandSpan(ctx, cmd.CommandPath())
 			cmd.SetContext(ctx)
 
 			return nil
@@ -58,7 +140,10 @@ manage your infrastructure from one place.`,
 			if err != nil {
 				return err
 			}
-			return launchTUI(app, debugMode)
+			if metricsAddr == "" && app.Config.Metrics != nil {
+				metricsAddr = app.Config.Metrics.Address
+			}
+			return launchTUI(app, debugMode, metricsAddr, tui.BrowseStart{})
 		},
 		// Silence usage on error, error reporting is explicitly handled in Execute()
 		SilenceUsage: true,
@@ -68,12 +153,41 @@ manage your infrastructure from one place.`,
 
 	// Define persistent flags (available to all subcommands)
 	cmd.PersistentFlags().BoolVarP(&debugMode, flags.Debug, flags.DebugShort, false, "Enable verbose debug logging")
+	cmd.PersistentFlags().BoolVar(&debugHTTP, flags.DebugHTTP, false, "Log provider API requests and responses (method, URL, status, duration, headers with credentials redacted)")
 	cmd.PersistentFlags().StringVarP(&outputFormatStr, flags.Output, flags.OutputShort, string(output.FormatTable), "Output format: table, json, yaml")
+	cmd.PersistentFlags().StringVar(&profile, flags.Profile, "", "Configuration profile to use for this command (overrides the active profile)")
+	cmd.PersistentFlags().StringVar(&impersonate, flags.ImpersonateServiceAccount, "", "Service account email for GCP clients to impersonate (overrides gcp.impersonate_service_account)")
+	cmd.PersistentFlags().StringVar(&logFile, flags.LogFile, "", "Write logs to this file instead of stderr, rotated at 10 MB (overrides log.file)")
+	cmd.PersistentFlags().StringVar(&logFormat, flags.LogFormat, "", "Log format: text or json (overrides log.format; default text)")
+	cmd.PersistentFlags().BoolVar(&noCache, flags.NoCache, false, "Fetch fresh results instead of using cached usage metrics and responses")
+	cmd.PersistentFlags().DurationVar(&timeout, flags.Timeout, 0, "Fail the command if it takes longer than this (e.g., 30s, 5m); 0 means no limit")
+	cmd.PersistentFlags().DurationVar(&providerTimeout, flags.ProviderTimeout, 0, "Report a provider as failed if it takes longer than this in multi-provider listings, and show the others' results; 0 means no limit (overrides fanout.provider_timeout)")
+	cmd.PersistentFlags().BoolVar(&dryRun, flags.DryRun, false, "Show the change a create, delete, upload, copy or benchmark command would make, with the resolved provider and parameters, without making it")
+	cmd.PersistentFlags().BoolVarP(&yes, flags.Yes, flags.YesShort, false, "Answer yes to confirmation prompts, like --force on the commands that ask for confirmation (required when stdin is not a terminal)")
+	cmd.PersistentFlags().BoolVar(&demo, flags.Demo, false, "Use the in-memory mock provider and its sample buckets instead of the configured providers, e.g. for screenshots and tutorials; changes are discarded on exit")
+	cmd.PersistentFlags().IntVar(&maxRetries, flags.MaxRetries, retry.DefaultMaxRetries, "Times to retry provider calls that fail with throttling, server or network errors (overrides retry.max_retries)")
+
+	// The TUI is the only long-running mode, so only it serves metrics
+	cmd.Flags().StringVar(&metricsAddr, flags.MetricsAddress, "", "Serve Prometheus metrics at /metrics on this address (e.g., :9464) while the TUI runs (overrides metrics.address)")
 
 	// Add subcommands
 	cmd.AddCommand(newStorageCmd())
 	cmd.AddCommand(newConfigCmd())
 	cmd.AddCommand(newSqlCmd())
+	cmd.AddCommand(newProfileCmd())
+	cmd.AddCommand(newContextCmd())
+	cmd.AddCommand(newAuthCmd())
+	cmd.AddCommand(newCacheCmd())
+	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newAuditCmd())
+	cmd.AddCommand(newDocsCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newJobsCmd())
+	cmd.AddCommand(newSnapshotCmd())
+	cmd.AddCommand(newLintCmd())
+	cmd.AddCommand(newPricingCmd())
+
+	registerCompletions(cmd)
 
 	return cmd
 }
@@ -81,12 +195,14 @@ manage your infrastructure from one place.`,
 // launchTUI redirects stderr away from the terminal (slog writes from the service
 // layer would corrupt Bubble Tea's alt-screen), runs the TUI, and restores stderr
 // after the TUI exits. In debug mode, stderr is redirected to a log file instead
-// of being discarded.
-func launchTUI(app *appContainer, debugMode bool) error {
+// of being discarded. With --log-file or log.file, the TUI logs there like any command.
+// With a metrics address, Prometheus metrics are served until the TUI exits.
+// browse sets where the storage tab opens.
+func launchTUI(app *appContainer, debugMode bool, metricsAddr string, browse tui.BrowseStart) error {
 	origStderr := os.Stderr
 
 	var logWriter io.Writer = io.Discard
-	if debugMode {
+	if debugMode && app.logFile == nil {
 		logPath := filepath.Join(os.Getenv("HOME"), ".config", config.ConfigDirName, debugLogFileName)
 		if f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600); err == nil {
 			defer f.Close()
@@ -96,7 +212,24 @@ func launchTUI(app *appContainer, debugMode bool) error {
 	os.Stderr = os.NewFile(0, os.DevNull)
 
 	tuiLogger := slog.New(slog.NewTextHandler(logWriter, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if app.logFile != nil {
+		// Already away from the terminal
+		tuiLogger = app.Logger
+	}
 	slog.SetDefault(tuiLogger)
+	if network.DebugHTTPEnabled() {
+		// Provider clients are created after this point, so they pick up the TUI's logger
+		network.SetDebugHTTPLogger(tuiLogger.With("component", "http"))
+	}
+
+	if metricsAddr != "" {
+		ctx, stopMetrics := context.WithCancel(context.Background())
+		defer stopMetrics()
+		if err := metrics.Serve(ctx, metricsAddr, tuiLogger); err != nil {
+			os.Stderr = origStderr
+			return err
+		}
+	}
 
 	tuiErr := tui.Run(tui.Deps{
 		StorageService: app.StorageService,
@@ -105,6 +238,7 @@ func launchTUI(app *appContainer, debugMode bool) error {
 		Config:         app.Config,
 		Factory:        app.ProviderFactory,
 		Logger:         tuiLogger,
+		Browse:         browse,
 	})
 
 	os.Stderr = origStderr
@@ -114,8 +248,58 @@ func launchTUI(app *appContainer, debugMode bool) error {
 // Starts the CLI execution
 func Execute() {
 	rootCmd := newRootCmd()
-	if err := rootCmd.Execute(); err != nil {
+
+	// Expand user-defined command aliases before cobra parses the arguments
+	var aliases aliasLoader
+	args, err := expandCommandAlias(rootCmd, os.Args[1:], aliases.load)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitUsage)
+	}
+	rootCmd.SetArgs(args)
+	markUsageErrors(rootCmd)
+
+	ctx, stopInterrupt := notifyInterrupt(withConfigManager(context.Background(), aliases.configManager))
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	shutdownApp(cmd, err)
+	interrupted := interruption(ctx)
+	stopInterrupt()
+	if err != nil && interrupted != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\nStopped early (%v); run the command again to finish it.\n", err, interrupted)
+		os.Exit(interrupted.exitCode())
+	}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && cmd.Flags().Changed(flags.Timeout) {
+			timeout, _ := cmd.Flags().GetDuration(flags.Timeout)
+			err = fmt.Errorf("command timed out after %s (--%s): %w", timeout, flags.Timeout, err)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	// The results of the providers that answered were shown, so this is not an error
+	if app := commandApp(cmd); app != nil && app.PartialResults {
+		os.Exit(exitPartial)
+	}
+}
+
+// Shuts down the application container of the executed command, if one was created,
+// recording cmdErr on the command span. Runs after the command whether or not it
+// failed, so it isn't a PersistentPostRunE hook.
+func shutdownApp(cmd *cobra.Command, cmdErr error) {
+	if app := commandApp(cmd); app != nil {
+		app.EndCommandSpan(cmdErr)
+		app.Shutdown()
+	}
+}
+
+// Returns the application container of the executed command, or nil if none was created
+func commandApp(cmd *cobra.Command) *appContainer {
+	if cmd == nil || cmd.Context() == nil {
+		return nil
+	}
+	app, err := appFromContext(cmd.Context())
+	if err != nil {
+		return nil
 	}
+	return app
 }
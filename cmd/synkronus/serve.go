@@ -0,0 +1,170 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/metrics"
+	"github.com/jkleinne/synkronus/internal/notify"
+	"github.com/jkleinne/synkronus/internal/server"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultServeAddress keeps the API off the network unless an address is chosen
+const defaultServeAddress = "localhost:8080"
+
+// serverTokenEnv supplies the API token without storing it in the config file
+const serverTokenEnv = "SYNKRONUS_SERVER_TOKEN"
+
+func newServeCmd() *cobra.Command {
+	var address string
+	var grpcAddr string
+	var metricsAddr string
+	var jobsFile string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the storage and SQL services over a REST or gRPC API",
+		Long: `Runs synkronus as a daemon answering a JSON REST API, so dashboards and internal tools
+can list and describe buckets, objects and SQL instances across the configured providers,
+and submit sync jobs, without shelling out to the CLI.
+
+Every request except GET /healthz must send the API token as "Authorization: Bearer <token>".
+The token comes from $` + serverTokenEnv + ` or server.token; store the latter in the OS
+keychain with 'synkronus config set --keychain server.token <token>'.
+
+Routes:
+  GET  /v1/providers                                         configured providers
+  GET  /v1/storage/buckets?providers=gcp,aws                 buckets across providers
+  GET  /v1/storage/buckets/{provider}/{bucket}               bucket details
+  GET  /v1/storage/buckets/{provider}/{bucket}/objects       objects (?prefix=)
+  GET  /v1/storage/buckets/{provider}/{bucket}/objects/{key} object details
+  GET  /v1/sql/instances?providers=gcp                       SQL instances
+  GET  /v1/sql/instances/{provider}/{instance}               SQL instance details
+  POST /v1/jobs/sync                                         start a sync job
+  GET  /v1/jobs, /v1/jobs/{id}                               job status and results
+
+With --grpc-address, the same services and jobs are also served over gRPC; see
+api/synkronus/v1/synkronus.proto for the API and the generated Go clients. gRPC calls send
+the token in the "authorization" metadata as "Bearer <token>".
+
+A sync job copies the objects under a source prefix that are missing or different at the
+destination, e.g.:
+  {"source": {"provider": "gcp", "bucket": "data", "prefix": "exports/"},
+   "destination": {"provider": "aws", "bucket": "data-replica"}}
+
+Scheduled sync jobs from the jobs section of the config file and from --jobs-file (or
+server.jobs_file) run on their cron schedules while the server is up; a run that comes due
+while the previous one is still going is skipped. See 'synkronus jobs --help' for the format.
+  GET  /v1/schedules                                         scheduled jobs and next runs
+  GET  /v1/schedules/{name}/history                          past runs (?limit=)
+
+Running jobs are canceled when the server stops (Ctrl+C or SIGTERM). Finished jobs are
+appended to ~/.config/synkronus/jobs.log; see 'synkronus jobs history'. The notifiers of the
+notifications section (webhooks, Slack or email) are told when a sync job finishes.`,
+		Example: `  SYNKRONUS_SERVER_TOKEN=$(openssl rand -hex 32) synkronus serve
+  synkronus serve --address :8080 --metrics-address :9464
+  synkronus serve --grpc-address localhost:9090
+  synkronus serve --jobs-file /etc/synkronus/jobs.yaml
+  curl -H "Authorization: Bearer $TOKEN" localhost:8080/v1/storage/buckets`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			settings := app.Config.Server
+			if settings == nil {
+				settings = &config.ServerConfig{}
+			}
+			token := cmp.Or(os.Getenv(serverTokenEnv), settings.Token)
+			if token == "" {
+				return &usageError{err: fmt.Errorf("no API token: set $%s or server.token (e.g., 'synkronus config set --keychain server.token <token>')", serverTokenEnv)}
+			}
+			if !cmd.Flags().Changed(flags.Address) {
+				address = cmp.Or(settings.Address, defaultServeAddress)
+			}
+			if !cmd.Flags().Changed(flags.GRPCAddress) {
+				grpcAddr = settings.GRPCAddress
+			}
+			if !cmd.Flags().Changed(flags.MetricsAddress) && app.Config.Metrics != nil {
+				metricsAddr = app.Config.Metrics.Address
+			}
+			if !cmd.Flags().Changed(flags.JobsFile) {
+				jobsFile = settings.JobsFile
+			}
+			jobs, err := scheduledJobs(app.Config, jobsFile)
+			if err != nil {
+				return err
+			}
+			historyPath, err := server.HistoryPath()
+			if err != nil {
+				return err
+			}
+			notifier, err := notify.New(app.Config.Notifications, app.Logger)
+			if err != nil {
+				return err
+			}
+
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", address, err)
+			}
+			var grpcListener net.Listener
+			if grpcAddr != "" {
+				grpcListener, err = net.Listen("tcp", grpcAddr)
+				if err != nil {
+					listener.Close()
+					return fmt.Errorf("failed to listen on %s: %w", grpcAddr, err)
+				}
+			}
+			if metricsAddr != "" {
+				if err := metrics.Serve(cmd.Context(), metricsAddr, app.Logger); err != nil {
+					listener.Close()
+					if grpcListener != nil {
+						grpcListener.Close()
+					}
+					return err
+				}
+			}
+
+			srv := server.New(server.Deps{
+				StorageService:    app.StorageService,
+				SqlService:        app.SqlService,
+				Providers:         app.ProviderFactory,
+				Token:             token,
+				Logger:            app.Logger,
+				Jobs:              jobs,
+				MetadataTemplates: app.Config.MetadataTemplates,
+				History:           server.NewHistory(historyPath),
+				Notifier:          notifier,
+			})
+			// Any server failing stops the others
+			g, ctx := errgroup.WithContext(cmd.Context())
+			app.Logger.Info("Serving the synkronus API", "address", listener.Addr().String())
+			g.Go(func() error { return srv.Serve(ctx, listener) })
+			if grpcListener != nil {
+				app.Logger.Info("Serving the synkronus gRPC API", "address", grpcListener.Addr().String())
+				g.Go(func() error { return srv.ServeGRPC(ctx, grpcListener) })
+			}
+			if len(jobs) > 0 {
+				app.Logger.Info("Running scheduled jobs", "count", len(jobs))
+				g.Go(func() error { return srv.RunSchedules(ctx) })
+			}
+			return g.Wait()
+		},
+	}
+
+	cmd.Flags().StringVar(&address, flags.Address, defaultServeAddress, "Address to listen on, e.g. :8080 for all interfaces (overrides server.address)")
+	cmd.Flags().StringVar(&grpcAddr, flags.GRPCAddress, "", "Also serve the gRPC API on this address, e.g. localhost:9090 (overrides server.grpc_address)")
+	cmd.Flags().StringVar(&metricsAddr, flags.MetricsAddress, "", "Serve Prometheus metrics at /metrics on this address (e.g., :9464) (overrides metrics.address)")
+	cmd.Flags().StringVar(&jobsFile, flags.JobsFile, "", "YAML file of scheduled sync jobs to run, in addition to the jobs in the config file (overrides server.jobs_file)")
+
+	return cmd
+}
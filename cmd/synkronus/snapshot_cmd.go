@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/snapshot"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newSnapshotCmd returns the "snapshot" parent command for saving resource
+// configurations and detecting drift from them.
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save resource configurations and detect changes made since",
+		Long: `A snapshot records the configuration of every bucket (IAM policy, ACLs, lifecycle rules,
+versioning, encryption, labels...) and SQL instance of the configured providers to a file.
+Diffing a snapshot against the current state reports what was added, removed or changed
+since, so changes made outside synkronus (in a cloud console, by another tool) are noticed.
+
+  synkronus snapshot save baseline.json
+  synkronus snapshot diff baseline.json`,
+	}
+	cmd.AddCommand(newSnapshotSaveCmd(), newSnapshotDiffCmd())
+	return cmd
+}
+
+// takeSnapshot captures the buckets and SQL instances of the given providers.
+func takeSnapshot(ctx context.Context, app *appContainer, storageProviders, sqlProviders []string) (snapshot.Snapshot, error) {
+	return snapshot.Take(ctx, app.StorageService, app.SqlService, storageProviders, sqlProviders)
+}
+
+// readSnapshot reads the snapshot file at path.
+func readSnapshot(path string) (snapshot.Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return snapshot.Snapshot{}, &usageError{err: fmt.Errorf("failed to open snapshot: %w", err)}
+	}
+	defer f.Close()
+	snap, err := snapshot.Read(f)
+	if err != nil {
+		return snapshot.Snapshot{}, &usageError{err: fmt.Errorf("%s: %w", path, err)}
+	}
+	return snap, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/snapshot"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <file>",
+		Short: "Report what changed since a snapshot was saved",
+		Long: `Captures the current configuration of the providers recorded in the snapshot <file> and
+reports the buckets and SQL instances added, removed or modified since it was saved. For a
+modified resource, each changed value is shown by its path (e.g., versioning.enabled,
+labels.team, iam_policy.bindings[0].role). Bucket usage and update times are not compared.
+
+Exits non-zero when anything changed, so the command can gate a CI pipeline or a
+scheduled drift check.`,
+		Example: `  synkronus snapshot diff baseline.json
+  synkronus snapshot diff baseline.json -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			saved, err := readSnapshot(args[0])
+			if err != nil {
+				return err
+			}
+			current, err := takeSnapshot(cmd.Context(), app, saved.StorageProviders, saved.SqlProviders)
+			if err != nil {
+				return err
+			}
+			report, err := snapshot.Diff(saved, current)
+			if err != nil {
+				return err
+			}
+
+			if err := output.Render(os.Stdout, app.OutputFormat, output.SnapshotDiffView(report)); err != nil {
+				return err
+			}
+			if len(report.Changes) > 0 {
+				return fmt.Errorf("drift detected: %d changes since the snapshot", len(report.Changes))
+			}
+			return nil
+		},
+	}
+}
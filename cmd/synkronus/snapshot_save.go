@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/snapshot"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newSnapshotSaveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "save <file>",
+		Short: "Save the configuration of buckets and SQL instances to a file",
+		Long: `Describes every bucket and lists every SQL instance of the configured providers, and
+writes their configuration to <file> as JSON, replacing it. If any provider or bucket cannot
+be read, nothing is written, since a partial snapshot would later report the resources it
+missed as removed.`,
+		Example: `  synkronus snapshot save baseline.json
+  synkronus --profile prod snapshot save prod-$(date +%F).json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			storageProviders := app.ProviderFactory.ConfiguredStorageProviders()
+			sqlProviders := app.ProviderFactory.ConfiguredSqlProviders()
+			if len(storageProviders) == 0 && len(sqlProviders) == 0 {
+				return fmt.Errorf("no providers configured. Use 'synkronus config set gcp.project <id>' or 'synkronus config set aws.region <region>'")
+			}
+
+			snap, err := takeSnapshot(cmd.Context(), app, storageProviders, sqlProviders)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Create(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to write snapshot: %w", err)
+			}
+			if err := snapshot.Write(f, snap); err != nil {
+				f.Close()
+				return fmt.Errorf("failed to write snapshot: %w", err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to write snapshot: %w", err)
+			}
+			fmt.Printf("Saved %d buckets and %d SQL instances to %s\n", len(snap.Buckets), len(snap.Instances), args[0])
+			return nil
+		},
+	}
+}
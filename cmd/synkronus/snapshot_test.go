@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotDiffCmd_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snap.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app := newStorageTestApp(&cmdStorageFactory{}, nil)
+
+	cmd := newSnapshotDiffCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{path})
+
+	var usage *usageError
+	if err := cmd.Execute(); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
@@ -1,9 +1,9 @@
 package main
 
 import (
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
 	"os"
-	"synkronus/internal/flags"
-	"synkronus/internal/output"
 
 	"github.com/spf13/cobra"
 )
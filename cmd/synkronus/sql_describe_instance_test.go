@@ -7,8 +7,8 @@ import (
 	"strings"
 	"testing"
 
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
 )
 
 // Note: The commands in this package render output via output.Render(os.Stdout, ...)
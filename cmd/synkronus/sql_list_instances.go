@@ -1,24 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
 	"os"
 	"strings"
-	"synkronus/internal/flags"
-	"synkronus/internal/output"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 func newListInstancesCmd() *cobra.Command {
 	var providersList []string
+	var watch time.Duration
 
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"list-instances"},
 		Short:   "List SQL database instances",
 		Long: `Lists all SQL database instances. If no flags are provided, it queries all configured SQL providers.
-Use the --providers flag to specify which providers to query (e.g., --providers gcp).`,
+Use the --providers flag to specify which providers to query (e.g., --providers gcp).
+Use --watch to refresh the listing periodically and see which instances were added or removed.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
@@ -37,12 +41,27 @@ Use the --providers flag to specify which providers to query (e.g., --providers
 				return err
 			}
 
+			if cmd.Flags().Changed(flags.Watch) {
+				return watchListing(cmd.Context(), app, watch, cmd.CommandPath(), func(ctx context.Context) (watchSnapshot, error) {
+					instances, err := app.SqlService.ListAllInstances(ctx, providersToQuery)
+					if err != nil && len(instances) == 0 {
+						return watchSnapshot{}, err
+					}
+					keys := make([]string, len(instances))
+					for i, inst := range instances {
+						keys[i] = strings.ToLower(string(inst.Provider)) + "/" + inst.Name
+					}
+					return watchSnapshot{view: output.InstanceListView(instances), keys: keys, warnings: err}, nil
+				})
+			}
+
 			allInstances, err := app.SqlService.ListAllInstances(cmd.Context(), providersToQuery)
 			if err != nil && len(allInstances) == 0 {
 				return err
 			}
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: some SQL providers failed: %v\n", err)
+				app.PartialResults = true
 			}
 
 			if len(allInstances) == 0 {
@@ -57,6 +76,7 @@ Use the --providers flag to specify which providers to query (e.g., --providers
 		},
 	}
 	cmd.Flags().StringSliceVarP(&providersList, flags.Providers, flags.ProvidersShort, nil, "Specify providers to query (comma-separated). Defaults to all configured SQL providers.")
+	addWatchFlag(cmd, &watch)
 
 	return cmd
 }
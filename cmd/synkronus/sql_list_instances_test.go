@@ -7,12 +7,12 @@ import (
 	"strings"
 	"testing"
 
-	"synkronus/internal/config"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/sql"
-	"synkronus/internal/output"
-	"synkronus/internal/provider/factory"
-	"synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/service"
 )
 
 // --- SQL CLI-layer mock types ---
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/doctor"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Check the health of the configured providers",
+		Long: `Probes each configured provider concurrently: that its credentials work, that its API
+answers (and how quickly), and, for GCP, that the required APIs are enabled. Useful before
+running long jobs. Exits non-zero if any provider is unhealthy.
+
+For a fuller diagnosis, including config file permissions and clock skew, run 'synkronus config doctor'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			health := doctor.Probe(cmd.Context(), doctor.Options{Config: app.Config})
+			if len(health) == 0 {
+				fmt.Println("No providers configured. Use 'synkronus config set gcp.project <id>' or 'synkronus config set aws.region <region>'.")
+				return nil
+			}
+
+			if err := output.Render(os.Stdout, app.OutputFormat, output.ProviderHealthView(health)); err != nil {
+				return err
+			}
+			if doctor.Unhealthy(health) {
+				return fmt.Errorf("one or more providers are unhealthy")
+			}
+			return nil
+		},
+	}
+}
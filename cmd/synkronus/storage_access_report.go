@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newAccessReportCmd() *cobra.Command {
+	var providersList []string
+	var member string
+
+	cmd := &cobra.Command{
+		Use:   "access-report",
+		Short: "List the buckets a principal can read, write or administer, across providers",
+		Long: `Describes every bucket of the configured providers (or --providers) and reports the access
+--member has to each through the bucket's IAM policy or bucket policy and its ACL, with the
+grants giving it.
+
+On Cloud Storage, the member is an IAM principal (user:EMAIL, serviceAccount:EMAIL,
+group:EMAIL) and also gets what allUsers, allAuthenticatedUsers and its domain are granted.
+On S3, it is an IAM ARN and also gets what its account and "*" are granted; unconditional
+Deny statements are taken into account. S3 ACLs name users by canonical ID, which an ARN
+does not match: pass the canonical ID as --member to see a user's ACL grants.
+
+Only grants on the buckets themselves are seen: group memberships, project, organization
+and account level policies, IAM identity policies and custom roles are not resolved, and
+conditional grants are listed as if their condition held. Buckets that cannot be described
+are reported as warnings on stderr.`,
+		Example: `  synkronus storage access-report --member user:alice@example.com
+  synkronus storage access-report --member arn:aws:iam::123456789012:user/alice -p aws -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			providers, err := storageProviderResolver(app).Resolve(providersList)
+			if err != nil {
+				return err
+			}
+
+			buckets, describeErr := inventory.Describe(cmd.Context(), app.StorageService, providers)
+			if describeErr != nil && len(buckets) == 0 {
+				return describeErr
+			}
+			report := inventory.PrincipalAccess(buckets, member)
+			if err := output.Render(os.Stdout, app.OutputFormat, output.AccessReportView(report)); err != nil {
+				return err
+			}
+			app.PartialResults = describeErr != nil
+			return renderInventoryWarnings(os.Stderr, describeErr)
+		},
+	}
+
+	cmd.Flags().StringVar(&member, flags.Member, "", "The principal to report on (required)")
+	cmd.MarkFlagRequired(flags.Member)
+	cmd.Flags().StringSliceVarP(&providersList, flags.Providers, flags.ProvidersShort, nil, "Specify providers to report on (comma-separated). Defaults to all configured providers.")
+
+	return cmd
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestAccessReportCmd(t *testing.T) {
+	mock := &cmdMockStorage{
+		buckets: []storage.Bucket{{Name: "data", Provider: domain.GCP}},
+		bucket: storage.Bucket{Name: "data", Provider: domain.GCP, IAMPolicy: &storage.IAMPolicy{Bindings: []storage.IAMBinding{
+			{Role: "roles/storage.objectViewer", Principals: []string{"user:alice@example.com"}},
+		}}},
+	}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	cmd := newAccessReportCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--member", "user:alice@example.com"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.PartialResults {
+		t.Error("expected a complete report")
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/acl"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newAnalyzeACLsCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var outputPath string
+
+	cmd := &cobra.Command{
+		Use:   "analyze-acls",
+		Short: "Map a bucket's fine-grained ACL to IAM, to plan enabling Uniform Bucket-Level Access",
+		Long: `Lists the entries of a Cloud Storage bucket's ACL with the IAM role and member granting the
+same access (READER, WRITER and OWNER map to the legacy bucket roles), whether the bucket's
+IAM policy already grants it, and the bindings to add so that enabling Uniform Bucket-Level
+Access keeps everyone's access. Entries with no IAM equivalent are flagged, as are grants to
+allUsers and allAuthenticatedUsers.
+
+With --output-path, the bucket's IAM policy with the missing bindings added is written as a
+migration plan, to review and then apply with:
+
+  gcloud storage buckets set-iam-policy gs://BUCKET FILE
+  gcloud storage buckets update gs://BUCKET --uniform-bucket-level-access
+
+Object ACLs are not read, so access granted object by object is not covered.`,
+		Example: `  synkronus storage analyze-acls --provider gcp --bucket legacy-assets
+  synkronus storage analyze-acls -p gcp -b legacy-assets --output-path iam-plan.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			analysis, err := acl.Analyze(cmd.Context(), app.StorageService, provider, bucket)
+			if err != nil {
+				return err
+			}
+			if err := output.Render(os.Stdout, app.OutputFormat, output.ACLAnalysisView(analysis)); err != nil {
+				return err
+			}
+			if outputPath == "" {
+				return nil
+			}
+
+			f, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to write migration plan: %w", err)
+			}
+			if err := errors.Join(analysis.WritePlan(f), f.Close()); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Wrote an IAM policy adding %d bindings to %s\n", len(analysis.Missing), outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to analyze (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&outputPath, flags.OutputPath, "", "File to write the migration plan (IAM policy) to")
+
+	return cmd
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestAnalyzeACLsCmd(t *testing.T) {
+	mock := &cmdMockStorage{bucket: storage.Bucket{
+		Name: "legacy",
+		ACLs: []storage.ACLRule{{Entity: "user-ann@example.com", Role: "READER"}},
+	}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	path := filepath.Join(t.TempDir(), "plan.json")
+	cmd := newAnalyzeACLsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "legacy", "--output-path", path})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plan struct {
+		Bindings []struct {
+			Role    string   `json:"role"`
+			Members []string `json:"members"`
+		} `json:"bindings"`
+	}
+	if err := json.Unmarshal(data, &plan); err != nil {
+		t.Fatalf("invalid plan: %v\n%s", err, data)
+	}
+	if len(plan.Bindings) != 1 || plan.Bindings[0].Role != "roles/storage.legacyBucketReader" || plan.Bindings[0].Members[0] != "user:ann@example.com" {
+		t.Errorf("unexpected plan:\n%s", data)
+	}
+}
+
+func TestAnalyzeACLsCmd_S3(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"aws": &cmdMockStorage{}}}, nil)
+	cmd := newAnalyzeACLsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "legacy"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an S3 bucket")
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/batch"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newBatchCmd() *cobra.Command {
+	var manifestPath string
+	var failuresPath string
+	var parallel int
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Run the object operations listed in a CSV manifest",
+		Long: `Runs the copy, delete and set-metadata operations listed in a CSV manifest, --parallel
+at a time, and reports the outcome of every line. One line per operation:
+
+  copy,<provider>,<bucket>,<key>,<dest-bucket>[,<dest-key>]
+  delete,<provider>,<bucket>,<key>
+  set-metadata,<provider>,<bucket>,<key>,<name>=<value>[,<name>=<value>...]
+
+Copies stay within a provider, and keep the key when <dest-key> is omitted. set-metadata
+changes only the named metadata keys of the object. Blank lines, lines starting with '#'
+and a first line of column names (starting with "operation") are ignored. The whole
+manifest is checked before anything runs.
+
+A failed line does not stop the batch. The lines that failed, or did not run because the
+batch was interrupted, are written to --failures-file (by default the manifest's name with
+.failed before the extension) in the manifest format, so running that file resumes the batch.
+
+A manifest with delete lines asks for confirmation, unless --force (or the global --yes) is
+given.`,
+		Example: `  synkronus storage batch -f manifest.csv
+  synkronus storage batch -f manifest.csv --parallel 32 --force
+  synkronus storage batch -f manifest.failed.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if parallel <= 0 {
+				return &usageError{err: fmt.Errorf("--%s must be positive, got %d", flags.Parallel, parallel)}
+			}
+
+			f, err := os.Open(manifestPath)
+			if err != nil {
+				return &usageError{err: fmt.Errorf("failed to open manifest: %w", err)}
+			}
+			entries, err := batch.Parse(f)
+			f.Close()
+			if err != nil {
+				return &usageError{err: err}
+			}
+			if failuresPath == "" {
+				failuresPath = defaultFailuresPath(manifestPath)
+			}
+
+			counts := make(map[string]int)
+			providers := make(map[string]bool)
+			for _, e := range entries {
+				counts[string(e.Operation)]++
+				providers[strings.ToLower(e.Provider)] = true
+			}
+			for _, provider := range slices.Sorted(maps.Keys(providers)) {
+				if err := app.StorageService.CheckProvider(cmd.Context(), provider); err != nil {
+					return err
+				}
+			}
+
+			if app.DryRun {
+				params := map[string]string{"manifest": manifestPath, "parallel": strconv.Itoa(parallel)}
+				for op, n := range counts {
+					params[op] = strconv.Itoa(n)
+				}
+				return output.Render(os.Stdout, app.OutputFormat, output.DryRunView{
+					Operation:  "batch",
+					Provider:   strings.Join(slices.Sorted(maps.Keys(providers)), ","),
+					Parameters: params,
+				})
+			}
+
+			run := func() error {
+				report, runErr := batch.Run(cmd.Context(), app.StorageService, entries, parallel)
+				if unfinished := batch.Unfinished(entries, report); len(unfinished) > 0 {
+					if err := writeFailures(failuresPath, unfinished); err != nil {
+						runErr = errors.Join(runErr, err)
+					} else {
+						fmt.Fprintf(os.Stderr, "Wrote %d unfinished lines to %s; run 'synkronus storage batch -f %s' to retry them.\n", len(unfinished), failuresPath, failuresPath)
+					}
+				} else if failuresPath == manifestPath {
+					// A retried failures file is done with once every line succeeded
+					if err := os.Remove(failuresPath); err != nil {
+						runErr = errors.Join(runErr, err)
+					}
+				}
+				if err := output.Render(os.Stdout, app.OutputFormat, output.BatchView(report)); err != nil {
+					return errors.Join(runErr, err)
+				}
+				return runErr
+			}
+
+			deletes := counts[string(batch.OpDelete)]
+			if deletes == 0 {
+				return run()
+			}
+			warningMessage := fmt.Sprintf(
+				"\nWARNING: Manifest '%s' deletes %d objects.\nThis action cannot be undone.",
+				manifestPath, deletes)
+			c := confirmationFor(app.Config, confirmDeleteObject, prompt.ModeYesNo)
+			return confirmThenRun(cmd.Context(), app.Prompter, c, warningMessage, filepath.Base(manifestPath), force, run)
+		},
+	}
+
+	cmd.Flags().StringVarP(&manifestPath, flags.ManifestFile, flags.ManifestFileShort, "", "The CSV manifest of operations to run (required)")
+	cmd.MarkFlagRequired(flags.ManifestFile)
+	cmd.Flags().StringVar(&failuresPath, flags.FailuresFile, "", "Where to write the lines that did not succeed (defaults to the manifest's name with .failed before the extension)")
+	cmd.Flags().IntVar(&parallel, flags.Parallel, 8, "Number of operations to run at once")
+	cmd.Flags().BoolVar(&force, flags.Force, false, "Bypass the confirmation prompt for manifests with delete lines")
+	markConfirmFlag(cmd)
+
+	return cmd
+}
+
+// defaultFailuresPath names the failures file of a manifest: manifest.csv
+// gives manifest.failed.csv. A failures file being retried is rewritten in
+// place, rather than growing another .failed.
+func defaultFailuresPath(manifestPath string) string {
+	ext := filepath.Ext(manifestPath)
+	base := strings.TrimSuffix(manifestPath, ext)
+	if strings.HasSuffix(base, ".failed") {
+		return manifestPath
+	}
+	return base + ".failed" + ext
+}
+
+// writeFailures writes the unfinished entries as a manifest at path.
+func writeFailures(path string, entries []batch.Entry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write failures file: %w", err)
+	}
+	if err := batch.WriteManifest(f, entries); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write failures file: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func writeManifest(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBatchCmd_FailuresWrittenForRetry(t *testing.T) {
+	mock := &cmdMockStorage{err: errors.New("access denied")}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	manifest := writeManifest(t, "manifest.csv", "copy,gcp,src,a.txt,dst\ndelete,gcp,src,b.txt\n")
+
+	cmd := newBatchCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"-f", manifest, "--force"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected the failures to be reported")
+	}
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(manifest), "manifest.failed.csv"))
+	if err != nil {
+		t.Fatalf("expected a failures file: %v", err)
+	}
+	if want := "copy,gcp,src,a.txt,dst\ndelete,gcp,src,b.txt\n"; string(data) != want {
+		t.Errorf("expected failures file %q, got %q", want, data)
+	}
+}
+
+func TestBatchCmd_RetriedFailuresFileRemoved(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdMockStorage{}}}, nil)
+	manifest := writeManifest(t, "manifest.failed.csv", "copy,gcp,src,a.txt,dst\n")
+
+	cmd := newBatchCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"-f", manifest})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(manifest); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the completed failures file to be removed, got %v", err)
+	}
+}
+
+func TestBatchCmd_DeleteConfirmationDeclined(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdMockStorage{}}}, &mockPrompter{confirmed: false})
+	manifest := writeManifest(t, "manifest.csv", "delete,gcp,src,b.txt\n")
+
+	cmd := newBatchCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"-f", manifest})
+
+	if err := cmd.Execute(); !errors.Is(err, ErrOperationAborted) {
+		t.Errorf("expected ErrOperationAborted, got %v", err)
+	}
+}
+
+func TestBatchCmd_InvalidManifest(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdMockStorage{}}}, nil)
+	manifest := writeManifest(t, "manifest.csv", "move,gcp,src,a.txt\n")
+
+	cmd := newBatchCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"-f", manifest})
+
+	err := cmd.Execute()
+	var usage *usageError
+	if !errors.As(err, &usage) {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
+
+func TestDefaultFailuresPath(t *testing.T) {
+	tests := map[string]string{
+		"manifest.csv":        "manifest.failed.csv",
+		"dir/ops":             "dir/ops.failed",
+		"manifest.failed.csv": "manifest.failed.csv",
+	}
+	for manifest, want := range tests {
+		if got := defaultFailuresPath(manifest); got != want {
+			t.Errorf("defaultFailuresPath(%q) = %q, want %q", manifest, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/benchmark"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newBenchListCmd is a hidden stress test for recursive listings, used to
+// catch regressions in listing rate and memory use.
+func newBenchListCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+
+	cmd := &cobra.Command{
+		Use:    "bench-list",
+		Short:  "Measure the rate and memory use of a large recursive object listing",
+		Hidden: true,
+		Long: `Walks every object under --prefix, discarding the results, and reports objects and
+pages per second, the time to the first object, and the peak heap in use. Point the provider
+at an emulator (gcp.storage_endpoint or aws.endpoint_url) to test against a generated bucket.
+
+Pages are estimated from the object count, assuming the providers' default of 1000 objects
+per page. Peak heap use should stay flat as the bucket grows.`,
+		Example: `  synkronus storage bench-list --provider aws --bucket my-large-bucket -o json`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			result, err := benchmark.RunList(cmd.Context(), app.StorageService, benchmark.ListOptions{
+				Provider: provider,
+				Bucket:   bucket,
+				Prefix:   prefix,
+			})
+			if err != nil {
+				return err
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.ListBenchmarkView(result))
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to list (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only list objects beginning with this prefix (optional)")
+
+	return cmd
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestBenchListCmd_Runs(t *testing.T) {
+	mock := &cmdMockStorage{objects: storage.ObjectList{Objects: []storage.Object{{Key: "a"}, {Key: "b"}}}}
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"aws": mock}}
+	app := newStorageTestApp(factory, nil)
+
+	cmd := newBenchListCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "big"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBenchListCmd_WalkFails_ReturnsError(t *testing.T) {
+	serviceErr := errors.New("access denied")
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"aws": &cmdMockStorage{err: serviceErr}}}
+	app := newStorageTestApp(factory, nil)
+
+	cmd := newBenchListCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "big"})
+
+	if err := cmd.Execute(); !errors.Is(err, serviceErr) {
+		t.Fatalf("expected the walk error, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/benchmark"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newBenchmarkCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var size string
+	var count int
+	var parallel int
+
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Measure upload and download throughput and latency of a bucket",
+		Long: `Uploads synthetic objects to a bucket, downloads them back, and reports throughput,
+latency percentiles (p50, p90, p99), and error rates. Useful for choosing a region or
+validating the network path to a provider.
+
+The objects are written under --prefix (by default a new synkronus-benchmark/<timestamp>/
+prefix) and deleted afterwards, even if the benchmark is interrupted.`,
+		Example: `  synkronus storage benchmark --provider gcp --bucket my-bucket --size 1GB --parallel 8`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			objectSize, err := storage.ParseBytes(size)
+			if err != nil {
+				return &usageError{err: fmt.Errorf("invalid --%s: %w", flags.Size, err)}
+			}
+			if prefix == "" {
+				prefix = fmt.Sprintf("synkronus-benchmark/%d/", time.Now().Unix())
+			}
+			opts := benchmark.Options{
+				Provider: provider,
+				Bucket:   bucket,
+				Prefix:   prefix,
+				Size:     objectSize,
+				Count:    count,
+				Parallel: parallel,
+			}
+			if err := opts.Validate(); err != nil {
+				return &usageError{err: err}
+			}
+
+			if app.DryRun {
+				return renderDryRun(cmd.Context(), app, "benchmark", provider, map[string]string{
+					"bucket":      bucket,
+					"prefix":      prefix,
+					"object_size": storage.FormatBytes(objectSize),
+					"objects":     strconv.Itoa(count),
+					"parallel":    strconv.Itoa(parallel),
+				})
+			}
+
+			report, err := benchmark.Run(cmd.Context(), app.StorageService, opts)
+			if err != nil {
+				for _, key := range report.Leftover {
+					fmt.Fprintf(os.Stderr, "Warning: benchmark object '%s' could not be deleted from bucket '%s'\n", key, bucket)
+				}
+				return err
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.BenchmarkView(report))
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to benchmark (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Key prefix for the synthetic objects (defaults to a new synkronus-benchmark/<timestamp>/ prefix)")
+	cmd.Flags().StringVar(&size, flags.Size, "1MB", "Size of each synthetic object (e.g., 64KB, 1MB, 1GB)")
+	cmd.Flags().IntVar(&count, flags.Count, 16, "Number of objects to upload and download")
+	cmd.Flags().IntVar(&parallel, flags.Parallel, 4, "Number of transfers to run at once")
+
+	return cmd
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestBenchmarkCmd_Runs(t *testing.T) {
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdMockStorage{}}}
+	app := newStorageTestApp(factory, nil)
+
+	cmd := newBenchmarkCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--size", "1KB", "--count", "3", "--parallel", "2"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBenchmarkCmd_UploadsFail_ReturnsError(t *testing.T) {
+	serviceErr := errors.New("access denied")
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdMockStorage{err: serviceErr}}}
+	app := newStorageTestApp(factory, nil)
+
+	cmd := newBenchmarkCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--count", "2"})
+
+	if err := cmd.Execute(); !errors.Is(err, serviceErr) {
+		t.Fatalf("expected the upload error, got %v", err)
+	}
+}
+
+func TestBenchmarkCmd_InvalidOptions_AreUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown size unit", []string{"--size", "1XB"}},
+		{"zero objects", []string{"--count", "0"}},
+		{"negative parallelism", []string{"--parallel", "-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newStorageTestApp(&cmdStorageFactory{}, nil)
+			cmd := newBenchmarkCmd()
+			cmd.SetContext(app.ToContext(context.Background()))
+			cmd.SetArgs(append([]string{"--provider", "gcp", "--bucket", "my-bucket"}, tt.args...))
+
+			err := cmd.Execute()
+			var usage *usageError
+			if !errors.As(err, &usage) {
+				t.Errorf("expected a usage error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+func newBrowseCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+
+	cmd := &cobra.Command{
+		Use:   "browse",
+		Short: "Browse buckets and objects in an interactive terminal UI",
+		Long: `Opens the terminal UI on the storage browser: navigate from buckets to prefixes to
+objects, describe them, and download or delete objects.
+
+In lists, press / to fuzzy-filter by name (Enter keeps the filter, Esc clears it) and p to
+cycle the bucket list through providers. In an object list, Backspace goes up one prefix.
+
+Use --provider to start with one provider's buckets, and --bucket (with --provider) to open
+a bucket's objects directly, optionally under --prefix.`,
+		Example: `  synkronus storage browse
+  synkronus storage browse --provider aws
+  synkronus storage browse --provider gcp --bucket my-bucket --prefix logs/2024/`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if bucket != "" && provider == "" {
+				return &usageError{err: fmt.Errorf("--%s requires --%s", flags.Bucket, flags.Provider)}
+			}
+			if prefix != "" && bucket == "" {
+				return &usageError{err: fmt.Errorf("--%s requires --%s", flags.Prefix, flags.Bucket)}
+			}
+
+			debugMode, _ := cmd.Flags().GetBool(flags.Debug)
+			var metricsAddr string
+			if app.Config.Metrics != nil {
+				metricsAddr = app.Config.Metrics.Address
+			}
+			return launchTUI(app, debugMode, metricsAddr, tui.BrowseStart{
+				Provider: provider,
+				Bucket:   bucket,
+				Prefix:   prefix,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "Start with the buckets of this provider (optional)")
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "Open this bucket's objects directly (optional; requires --provider)")
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Open the bucket at this prefix (optional; requires --bucket)")
+
+	return cmd
+}
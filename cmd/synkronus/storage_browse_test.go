@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBrowseCmd_IncompleteLocation_IsUsageError(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"bucket without provider", []string{"--bucket", "my-bucket"}},
+		{"prefix without bucket", []string{"--provider", "gcp", "--prefix", "logs/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newStorageTestApp(&cmdStorageFactory{}, nil)
+			cmd := newBrowseCmd()
+			cmd.SetContext(app.ToContext(context.Background()))
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			var usage *usageError
+			if !errors.As(err, &usage) {
+				t.Errorf("expected a usage error, got %v", err)
+			}
+		})
+	}
+}
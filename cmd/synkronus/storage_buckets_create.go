@@ -1,12 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/flags"
-	"synkronus/internal/provider/storage/shared"
 
 	"github.com/spf13/cobra"
 )
@@ -19,23 +23,69 @@ func newCreateBucketCmd() *cobra.Command {
 	var versioning bool
 	var uniformAccess bool
 	var publicAccessPrevention string
+	var interactive bool
 
 	cmd := &cobra.Command{
 		Use:   "create [bucket-name]",
 		Short: "Create a new storage bucket",
-		Long:    `Creates a new storage bucket on the specified provider. You must specify the bucket name, the --provider flag, and the --location flag. Optional flags control storage class, labels, versioning, uniform access, and public access prevention.`,
-		Args:    cobra.ExactArgs(1),
+		Long: `Creates a new storage bucket on the specified provider. You must specify the bucket name, the --provider flag, and the --location flag. Optional flags control storage class, labels, versioning, uniform access, and public access prevention.
+
+Use --interactive to be walked through the provider, a name checked against the provider's
+naming rules, the location, storage class, and protection options. Flags given alongside are
+offered as defaults. The equivalent non-interactive command is shown before the bucket is created.`,
+		Example: `  synkronus storage buckets create my-bucket --provider gcp --location us-central1
+  synkronus storage buckets create --interactive`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			opts, err := buildCreateBucketOptions(cmd, args[0], provider, location, storageClass, publicAccessPrevention, labels, versioning, uniformAccess)
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if interactive {
+				wizard := &bucketWizard{
+					prompter:  app.Prompter,
+					out:       os.Stdout,
+					providers: app.ProviderFactory.ConfiguredStorageProviders(),
+				}
+				if app.Config.AWS != nil {
+					wizard.awsRegion = app.Config.AWS.Region
+				}
+				if name, err = wizard.run(cmd, name); err != nil {
+					return wizardError(err)
+				}
+			} else if err := requireCreateBucketArgs(cmd, args); err != nil {
+				return err
+			}
+
+			opts, err := buildCreateBucketOptions(cmd, name, provider, location, storageClass, publicAccessPrevention, labels, versioning, uniformAccess)
 			if err != nil {
 				return err
 			}
 
+			if interactive {
+				fmt.Printf("\nEquivalent command:\n  %s\n\n", createBucketCommandLine(cmd.CommandPath(), provider, opts))
+			}
+			if interactive && !app.DryRun {
+				message := fmt.Sprintf("Create bucket '%s' in %s on provider %s?", opts.Name, opts.Location, provider)
+				confirmed, err := confirm(cmd.Context(), app.Prompter, confirmation{Mode: prompt.ModeYesNo}, message, opts.Name)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Creation aborted.")
+					return ErrOperationAborted
+				}
+			}
+
+			if app.DryRun {
+				return renderDryRun(cmd.Context(), app, "create-bucket", provider, createBucketParams(opts))
+			}
+
 			result, err := app.StorageService.CreateBucket(cmd.Context(), opts, provider)
 			if err != nil {
 				return err
@@ -49,20 +99,50 @@ func newCreateBucketCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider to create the bucket on (required)")
-	cmd.MarkFlagRequired(flags.Provider)
-	cmd.Flags().StringVarP(&location, flags.Location, flags.LocationShort, "", "The location/region to create the bucket in (required)")
-	cmd.MarkFlagRequired(flags.Location)
+	// Not marked required, since --interactive asks for them; see requireCreateBucketArgs
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider to create the bucket on (required unless --interactive)")
+	cmd.Flags().StringVarP(&location, flags.Location, flags.LocationShort, "", "The location/region to create the bucket in (required unless --interactive)")
 
 	cmd.Flags().StringVarP(&storageClass, flags.StorageClass, flags.StorageClassShort, "", "Storage class (STANDARD, NEARLINE, COLDLINE, ARCHIVE)")
 	cmd.Flags().StringToStringVar(&labels, flags.Labels, nil, "Labels as key=value pairs (e.g. --labels env=prod,team=data)")
 	cmd.Flags().BoolVar(&versioning, flags.VersioningFlag, false, "Enable object versioning")
 	cmd.Flags().BoolVar(&uniformAccess, flags.UniformAccess, false, "Enable Uniform Bucket-Level Access")
 	cmd.Flags().StringVar(&publicAccessPrevention, flags.PublicAccessPreventionFlag, "", "Public access prevention (enforced or inherited)")
+	cmd.Flags().BoolVarP(&interactive, flags.Interactive, flags.InteractiveShort, false, "Ask for each setting in turn, then show the equivalent command before creating the bucket")
 
 	return cmd
 }
 
+// requireCreateBucketArgs checks the bucket name and the flags a non-interactive
+// create needs, failing as cobra's own argument and required flag checks would.
+func requireCreateBucketArgs(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return &usageError{err: fmt.Errorf("accepts 1 arg(s), received %d", len(args))}
+	}
+	var missing []string
+	for _, name := range []string{flags.Location, flags.Provider} {
+		if flagValue(cmd, name) == "" {
+			missing = append(missing, strconv.Quote(name))
+		}
+	}
+	if len(missing) > 0 {
+		return &usageError{err: fmt.Errorf("required flag(s) %s not set", strings.Join(missing, ", "))}
+	}
+	return nil
+}
+
+// wizardError explains why the create-bucket wizard stopped.
+func wizardError(err error) error {
+	switch {
+	case errors.Is(err, prompt.ErrNonInteractive):
+		return &usageError{err: fmt.Errorf("--%s needs a terminal to answer questions on; pass the bucket name and flags instead", flags.Interactive)}
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("input ended before the bucket was fully described")
+	default:
+		return err
+	}
+}
+
 // buildCreateBucketOptions assembles a CreateBucketOptions from the parsed flag values.
 // Flag-changed checks are used rather than zero-value checks so that explicitly passing
 // false (e.g. --versioning=false) is still honored.
@@ -102,3 +182,28 @@ func buildCreateBucketOptions(
 
 	return opts, nil
 }
+
+// createBucketParams lists the options a bucket would be created with, leaving
+// out the ones left to the provider's defaults.
+func createBucketParams(opts storage.CreateBucketOptions) map[string]string {
+	params := map[string]string{
+		"name":     opts.Name,
+		"location": opts.Location,
+	}
+	if opts.StorageClass != "" {
+		params["storage_class"] = opts.StorageClass
+	}
+	if len(opts.Labels) > 0 {
+		params["labels"] = formatPairs(opts.Labels)
+	}
+	if opts.Versioning != nil {
+		params["versioning"] = strconv.FormatBool(*opts.Versioning)
+	}
+	if opts.UniformBucketLevelAccess != nil {
+		params["uniform_access"] = strconv.FormatBool(*opts.UniformBucketLevelAccess)
+	}
+	if opts.PublicAccessPrevention != nil {
+		params["public_access_prevention"] = *opts.PublicAccessPrevention
+	}
+	return params
+}
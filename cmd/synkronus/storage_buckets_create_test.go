@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"maps"
 	"testing"
 
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 func TestCreateBucketCmd_HappyPath(t *testing.T) {
@@ -112,3 +113,32 @@ func TestCreateBucketCmd_InvalidPublicAccessPrevention_ReturnsError(t *testing.T
 		t.Fatal("expected error for invalid --public-access-prevention value, got nil")
 	}
 }
+
+func TestCreateBucketParams(t *testing.T) {
+	versioning := false
+	opts := storage.CreateBucketOptions{
+		Name:       "logs",
+		Location:   "EU",
+		Labels:     map[string]string{"team": "data", "env": "prod"},
+		Versioning: &versioning,
+	}
+
+	got := createBucketParams(opts)
+	want := map[string]string{"name": "logs", "location": "EU", "labels": "env=prod,team=data", "versioning": "false"}
+	if !maps.Equal(got, want) {
+		t.Errorf("createBucketParams() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateBucketCmd_MissingName_IsUsageError(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{}, nil)
+	cmd := newCreateBucketCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--location", "us-central1"})
+
+	err := cmd.Execute()
+	var usage *usageError
+	if !errors.As(err, &usage) {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
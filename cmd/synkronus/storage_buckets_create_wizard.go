@@ -0,0 +1,220 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// bucketWizard walks through the settings of a new bucket for
+// 'storage buckets create --interactive'. Answers are set as the command's
+// flags, so the bucket is built and created exactly as from the command line.
+type bucketWizard struct {
+	prompter prompt.Prompter
+	out      io.Writer
+	// providers are the configured storage providers to choose from
+	providers []string
+	// awsRegion is where S3 buckets are created, whatever their location
+	awsRegion string
+}
+
+// run asks for each setting, offering the flags already given as defaults,
+// and returns the bucket name.
+func (w *bucketWizard) run(cmd *cobra.Command, name string) (string, error) {
+	if len(w.providers) == 0 {
+		return "", fmt.Errorf("no storage providers configured. Use 'synkronus config set'")
+	}
+
+	provider, err := w.choose("Provider", w.providers, cmp.Or(flagValue(cmd, flags.Provider), w.providers[0]), false)
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Flags().Set(flags.Provider, provider); err != nil {
+		return "", err
+	}
+
+	for {
+		if name, err = w.prompter.Ask("Bucket name", name); err != nil {
+			return "", err
+		}
+		err := shared.ValidateBucketName(provider, name)
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(w.out, "  Invalid name: %v\n", err)
+		name = ""
+	}
+
+	if err := w.askLocation(cmd, provider); err != nil {
+		return "", err
+	}
+
+	if classes := shared.BucketStorageClasses[provider]; len(classes) > 0 {
+		class, err := w.choose("Storage class", classes, cmp.Or(flagValue(cmd, flags.StorageClass), classes[0]), false)
+		if err != nil {
+			return "", err
+		}
+		if err := cmd.Flags().Set(flags.StorageClass, class); err != nil {
+			return "", err
+		}
+	}
+
+	fmt.Fprintln(w.out, "\nProtection:")
+	versioning, err := w.askYesNo("Enable object versioning", flagValue(cmd, flags.VersioningFlag) == "true")
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Flags().Set(flags.VersioningFlag, strconv.FormatBool(versioning)); err != nil {
+		return "", err
+	}
+	blockPublic, err := w.askYesNo("Prevent public access", flagValue(cmd, flags.PublicAccessPreventionFlag) != storage.PublicAccessPreventionInherited)
+	if err != nil {
+		return "", err
+	}
+	prevention := storage.PublicAccessPreventionInherited
+	if blockPublic {
+		prevention = storage.PublicAccessPreventionEnforced
+	}
+	if err := cmd.Flags().Set(flags.PublicAccessPreventionFlag, prevention); err != nil {
+		return "", err
+	}
+	if shared.SupportsOption(provider, "uniform-access") {
+		uniform, err := w.askYesNo("Enable uniform bucket-level access", !cmd.Flags().Changed(flags.UniformAccess) || flagValue(cmd, flags.UniformAccess) == "true")
+		if err != nil {
+			return "", err
+		}
+		if err := cmd.Flags().Set(flags.UniformAccess, strconv.FormatBool(uniform)); err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// askLocation sets --location. S3 buckets are always created in aws.region,
+// so it is shown rather than asked for.
+func (w *bucketWizard) askLocation(cmd *cobra.Command, provider string) error {
+	if provider == "aws" {
+		if w.awsRegion == "" {
+			return fmt.Errorf("aws.region is not set. Use 'synkronus config set aws.region <region>'")
+		}
+		fmt.Fprintf(w.out, "Location: %s (S3 buckets are created in aws.region)\n", w.awsRegion)
+		return cmd.Flags().Set(flags.Location, w.awsRegion)
+	}
+
+	locations := shared.BucketLocations[provider]
+	location, err := w.choose("Location", locations, cmp.Or(flagValue(cmd, flags.Location), firstOrEmpty(locations)), true)
+	if err != nil {
+		return err
+	}
+	return cmd.Flags().Set(flags.Location, location)
+}
+
+// choose lists options by number and asks for one, by number or by value. With
+// allowOther, a value not listed is accepted as typed.
+func (w *bucketWizard) choose(question string, options []string, defaultValue string, allowOther bool) (string, error) {
+	fmt.Fprintf(w.out, "%s:\n", question)
+	for i, option := range options {
+		fmt.Fprintf(w.out, "  %d) %s\n", i+1, option)
+	}
+	for {
+		answer, err := w.prompter.Ask("Choose "+strings.ToLower(question), defaultValue)
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		if i := slices.IndexFunc(options, func(o string) bool { return strings.EqualFold(o, answer) }); i >= 0 {
+			return options[i], nil
+		}
+		if allowOther && answer != "" {
+			return answer, nil
+		}
+		fmt.Fprintf(w.out, "  Enter a number from 1 to %d or one of the listed values\n", len(options))
+	}
+}
+
+// askYesNo asks a yes/no question, re-asking until the answer is one.
+func (w *bucketWizard) askYesNo(question string, defaultValue bool) (bool, error) {
+	def := "n"
+	if defaultValue {
+		def = "y"
+	}
+	for {
+		answer, err := w.prompter.Ask(question+"? (y/n)", def)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(w.out, "  Answer y or n")
+	}
+}
+
+// createBucketCommandLine returns the non-interactive command that creates the
+// bucket opts describes, for the wizard to show before creating it.
+func createBucketCommandLine(commandPath, provider string, opts storage.CreateBucketOptions) string {
+	args := []string{commandPath, opts.Name, "--" + flags.Provider, provider, "--" + flags.Location, opts.Location}
+	if opts.StorageClass != "" {
+		args = append(args, "--"+flags.StorageClass, opts.StorageClass)
+	}
+	if len(opts.Labels) > 0 {
+		args = append(args, "--"+flags.Labels, formatPairs(opts.Labels))
+	}
+	if opts.Versioning != nil {
+		args = append(args, boolFlagArg(flags.VersioningFlag, *opts.Versioning))
+	}
+	if opts.UniformBucketLevelAccess != nil {
+		args = append(args, boolFlagArg(flags.UniformAccess, *opts.UniformBucketLevelAccess))
+	}
+	if opts.PublicAccessPrevention != nil {
+		args = append(args, "--"+flags.PublicAccessPreventionFlag, *opts.PublicAccessPrevention)
+	}
+	for i, arg := range args[1:] {
+		args[i+1] = shellQuote(arg)
+	}
+	return strings.Join(args, " ")
+}
+
+// boolFlagArg writes a boolean flag as --name or --name=false.
+func boolFlagArg(name string, value bool) string {
+	if value {
+		return "--" + name
+	}
+	return "--" + name + "=false"
+}
+
+// shellQuote single-quotes s if a POSIX shell would otherwise split or expand it.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,@+", r)
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func flagValue(cmd *cobra.Command, name string) string {
+	return cmd.Flags().Lookup(name).Value.String()
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
+)
+
+func TestBucketWizard_Run(t *testing.T) {
+	// provider by number, an invalid then a valid name, location by value,
+	// storage class by default, then the protection questions
+	input := "1\nMy_Bucket\nmy-bucket\neurope-west1\n\ny\nn\n\n"
+	wizard := &bucketWizard{
+		prompter:  prompt.NewStandardPrompter(strings.NewReader(input), io.Discard),
+		out:       io.Discard,
+		providers: []string{"gcp", "aws"},
+	}
+	cmd := newCreateBucketCmd()
+
+	name, err := wizard.run(cmd, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "my-bucket" {
+		t.Errorf("name = %q, want my-bucket", name)
+	}
+	for flag, want := range map[string]string{
+		flags.Provider:                   "gcp",
+		flags.Location:                   "europe-west1",
+		flags.StorageClass:               "STANDARD",
+		flags.VersioningFlag:             "true",
+		flags.PublicAccessPreventionFlag: storage.PublicAccessPreventionInherited,
+		flags.UniformAccess:              "true",
+	} {
+		if got := flagValue(cmd, flag); got != want {
+			t.Errorf("--%s = %q, want %q", flag, got, want)
+		}
+	}
+}
+
+func TestBucketWizard_AWSUsesConfiguredRegion(t *testing.T) {
+	wizard := &bucketWizard{
+		prompter:  prompt.NewStandardPrompter(strings.NewReader("aws\nmy-bucket\n\n\n"), io.Discard),
+		out:       io.Discard,
+		providers: []string{"gcp", "aws"},
+		awsRegion: "eu-west-1",
+	}
+	cmd := newCreateBucketCmd()
+
+	if _, err := wizard.run(cmd, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := flagValue(cmd, flags.Location); got != "eu-west-1" {
+		t.Errorf("--%s = %q, want eu-west-1", flags.Location, got)
+	}
+	if got := flagValue(cmd, flags.StorageClass); got != "" {
+		t.Errorf("expected no storage class for aws, got %q", got)
+	}
+}
+
+func TestBucketWizard_InputEnds(t *testing.T) {
+	wizard := &bucketWizard{
+		prompter:  prompt.NewStandardPrompter(strings.NewReader("gcp\n"), io.Discard),
+		out:       io.Discard,
+		providers: []string{"gcp"},
+	}
+
+	_, err := wizard.run(newCreateBucketCmd(), "")
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestCreateBucketCommandLine(t *testing.T) {
+	versioning, uniform := true, false
+	prevention := storage.PublicAccessPreventionEnforced
+	opts := storage.CreateBucketOptions{
+		Name:                     "my-bucket",
+		Location:                 "US",
+		StorageClass:             "NEARLINE",
+		Labels:                   map[string]string{"team": "data eng"},
+		Versioning:               &versioning,
+		UniformBucketLevelAccess: &uniform,
+		PublicAccessPrevention:   &prevention,
+	}
+
+	got := createBucketCommandLine("synkronus storage buckets create", "gcp", opts)
+	want := "synkronus storage buckets create my-bucket --provider gcp --location US --storage-class NEARLINE" +
+		" --labels 'team=data eng' --versioning --uniform-access=false --public-access-prevention enforced"
+	if got != want {
+		t.Errorf("command line =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	for in, want := range map[string]string{
+		"my-bucket":   "my-bucket",
+		"":            "''",
+		"a b":         "'a b'",
+		"it's":        `'it'\''s'`,
+		"us-central1": "us-central1",
+	} {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
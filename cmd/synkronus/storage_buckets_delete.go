@@ -2,8 +2,9 @@ package main
 
 import (
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
 	"strings"
-	"synkronus/internal/flags"
 
 	"github.com/spf13/cobra"
 )
@@ -16,8 +17,12 @@ func newDeleteBucketCmd() *cobra.Command {
 		Use:   "delete [bucket-name]",
 		Short: "Delete a storage bucket",
 		Long: `Deletes a storage bucket on the specified provider. This operation is destructive.
-Confirmation is required by typing the bucket name, unless the --force flag is used.`,
-		Args: cobra.ExactArgs(1),
+Confirmation is required by typing the bucket name, unless --force (or the global --yes) is given.
+Set defaults.confirm_modes.delete-bucket to "yes-no" to answer a y/N prompt instead.
+When stdin is not a terminal (e.g., in CI), the command fails rather than prompt. Set
+defaults.confirm_timeout (e.g., 2m) to abort the deletion if the prompt goes unanswered.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeBucketArg,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
@@ -25,9 +30,14 @@ Confirmation is required by typing the bucket name, unless the --force flag is u
 			}
 
 			bucketName := args[0]
+			if app.DryRun {
+				return renderDryRun(cmd.Context(), app, "delete-bucket", provider, map[string]string{"bucket": bucketName})
+			}
+
 			warningMessage := fmt.Sprintf("\nWARNING: You are about to delete the bucket '%s' on provider '%s'.\nThis action CANNOT be undone and may result in permanent data loss.", bucketName, strings.ToUpper(provider))
 
-			return confirmThenRun(app.Prompter, warningMessage, bucketName, force, func() error {
+			c := confirmationFor(app.Config, confirmDeleteBucket, prompt.ModeTyped)
+			return confirmThenRun(cmd.Context(), app.Prompter, c, warningMessage, bucketName, force, func() error {
 				if err := app.StorageService.DeleteBucket(cmd.Context(), bucketName, provider); err != nil {
 					return err
 				}
@@ -39,6 +49,7 @@ Confirmation is required by typing the bucket name, unless the --force flag is u
 	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
 	cmd.MarkFlagRequired(flags.Provider)
 	cmd.Flags().BoolVarP(&force, flags.Force, flags.ForceShort, false, "If set, bypass the interactive confirmation prompt and proceed with deletion")
+	markConfirmFlag(cmd)
 
 	return cmd
 }
@@ -6,12 +6,13 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
 
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/output"
-	"synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/service"
 )
 
 // --- CLI-layer mock types ---
@@ -62,11 +63,25 @@ func (m *cmdMockStorage) DeleteBucket(_ context.Context, _ string) error {
 func (m *cmdMockStorage) ListObjects(_ context.Context, _ string, _ string) (storage.ObjectList, error) {
 	return m.objects, m.err
 }
+func (m *cmdMockStorage) WalkObjects(_ context.Context, _ string, _ string, fn func(storage.Object) error) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, obj := range m.objects.Objects {
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 func (m *cmdMockStorage) DescribeObject(_ context.Context, _ string, _ string) (storage.Object, error) {
 	return m.object, m.err
 }
 func (m *cmdMockStorage) DownloadObject(_ context.Context, _ string, _ string) (io.ReadCloser, error) {
-	return nil, m.err
+	if m.err != nil {
+		return nil, m.err
+	}
+	return io.NopCloser(strings.NewReader("")), nil
 }
 func (m *cmdMockStorage) UploadObject(_ context.Context, _ storage.UploadObjectOptions, _ io.Reader) error {
 	return m.err
@@ -118,8 +133,11 @@ func TestDeleteBucketCmd_ForceFlag_SkipsConfirmationAndSucceeds(t *testing.T) {
 	if err := cmd.Execute(); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if err := app.StorageService.Shutdown(); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
 	if !mock.closeCalled {
-		t.Error("expected provider client Close to be called after delete")
+		t.Error("expected provider client Close to be called on shutdown")
 	}
 }
 
@@ -196,3 +214,32 @@ func TestDeleteBucketCmd_ConfirmDeclined_AbortsWithoutDelete(t *testing.T) {
 		t.Error("provider client should not be called when confirmation is declined")
 	}
 }
+
+func TestDeleteBucketCmd_DryRun_SkipsPromptAndDeletion(t *testing.T) {
+	// A provider call would fail, so success means nothing was deleted
+	mock := &cmdMockStorage{err: errors.New("delete should not be called")}
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}
+	app := newStorageTestApp(factory, nil) // nil prompter: a prompt would panic
+	app.DryRun = true
+
+	cmd := newDeleteBucketCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "my-bucket"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteBucketCmd_DryRun_UnknownProviderFails(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{}, nil)
+	app.DryRun = true
+
+	cmd := newDeleteBucketCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "azure", "my-bucket"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error for an unknown provider")
+	}
+}
@@ -1,9 +1,9 @@
 package main
 
 import (
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
 	"os"
-	"synkronus/internal/flags"
-	"synkronus/internal/output"
 
 	"github.com/spf13/cobra"
 )
@@ -16,6 +16,7 @@ func newDescribeBucketCmd() *cobra.Command {
 		Short: "Describe a specific storage bucket",
 		Long:    `Provides detailed information about a specific storage bucket. You must specify the bucket name and the --provider flag.`,
 		Args:    cobra.ExactArgs(1),
+		ValidArgsFunction: completeBucketArg,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
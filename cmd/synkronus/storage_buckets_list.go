@@ -1,23 +1,53 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/service"
 	"os"
+	"strconv"
 	"strings"
-	"synkronus/internal/flags"
-	"synkronus/internal/output"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 func newListBucketsCmd() *cobra.Command {
 	var providersList []string
+	var failFast bool
+	var summary bool
+	var organization, folder string
+	var watch time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List storage buckets",
 		Long: `Lists all storage buckets. If no flags are provided, it queries all configured providers.
-Use the --providers flag to specify which providers to query (e.g., --providers gcp,aws).`,
+Use the --providers flag to specify which providers to query (e.g., --providers gcp,aws).
+Usage metrics are cached for 15 minutes; use --no-cache to fetch fresh metrics.
+If some providers fail, the buckets of the others are listed and the failures
+are reported as warnings on stderr; use --fail-fast to stop the other providers and
+exit with an error at the first failure instead.
+Use --provider-timeout (or fanout.provider_timeout) to report a slow provider as
+failed rather than wait for it.
+On a terminal, table rows are shown as each provider answers.
+Use --watch to refresh the listing periodically and see which buckets were added
+or removed, e.g. while a migration runs.
+Use --summary to follow the listing with the number of buckets, their total usage
+and a breakdown by default storage class; in JSON and YAML the buckets and the
+summary are then fields of one object.
+Use --organization or --folder to list the buckets of every project under a GCP
+organization or folder, each annotated with its project, with a Cloud Asset
+Inventory search; this needs the cloudasset.assets.searchAllResources permission
+on the organization or folder. Storage class and usage are not indexed, so they
+are left blank; describe a bucket for its details. Configured providers that
+cannot search an organization (e.g., AWS) are skipped unless named in --providers.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
@@ -35,13 +65,43 @@ Use the --providers flag to specify which providers to query (e.g., --providers
 			if err != nil {
 				return err
 			}
+			scope, err := searchScope(organization, folder)
+			if err != nil {
+				return &usageError{err: err}
+			}
+			ctx := cmd.Context()
+			if failFast {
+				// The first failure decides the outcome, so the other providers are stopped
+				ctx = service.WithFailFast(ctx)
+			}
+			listBuckets := func(ctx context.Context) ([]storage.Bucket, error) {
+				if scope != "" {
+					return listScopeBuckets(ctx, app, providersToQuery, scope, cmd.Flags().Changed(flags.Providers), failFast)
+				}
+				return app.StorageService.ListAllBuckets(ctx, providersToQuery)
+			}
 
-			allBuckets, err := app.StorageService.ListAllBuckets(cmd.Context(), providersToQuery)
-			if err != nil && len(allBuckets) == 0 {
-				return err
+			if cmd.Flags().Changed(flags.Watch) {
+				return watchListing(ctx, app, watch, cmd.CommandPath(), func(ctx context.Context) (watchSnapshot, error) {
+					buckets, err := listBuckets(ctx)
+					if err != nil && len(buckets) == 0 {
+						return watchSnapshot{}, err
+					}
+					keys := make([]string, len(buckets))
+					for i, b := range buckets {
+						keys[i] = strings.ToLower(string(b.Provider)) + "/" + b.Name
+					}
+					return watchSnapshot{view: bucketListView(buckets, summary), keys: keys, warnings: err}, nil
+				})
 			}
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: some providers failed: %v\n", err)
+
+			if app.OutputFormat == output.FormatTable && len(providersToQuery) > 0 && scope == "" && isatty.IsTerminal(os.Stdout.Fd()) {
+				return streamBuckets(ctx, app, providersToQuery, failFast, summary)
+			}
+
+			allBuckets, listErr := listBuckets(ctx)
+			if listErr != nil && (failFast || len(allBuckets) == 0) {
+				return listErr
 			}
 
 			if len(allBuckets) == 0 {
@@ -52,10 +112,105 @@ Use the --providers flag to specify which providers to query (e.g., --providers
 				}
 				return nil
 			}
-			return output.Render(os.Stdout, app.OutputFormat, output.BucketListView(allBuckets))
+			if err := output.Render(os.Stdout, app.OutputFormat, bucketListView(allBuckets, summary)); err != nil {
+				return err
+			}
+			// Failures go to stderr after the listing, so stdout stays parseable
+			app.PartialResults = listErr != nil
+			return renderProviderWarnings(os.Stderr, listErr)
 		},
 	}
 	cmd.Flags().StringSliceVarP(&providersList, flags.Providers, flags.ProvidersShort, nil, "Specify providers to query (comma-separated). Defaults to all configured providers.")
+	cmd.Flags().BoolVar(&failFast, flags.FailFast, false, "Exit with an error if any provider fails, instead of listing the buckets of the others")
+	cmd.Flags().BoolVar(&summary, flags.Summary, false, "Follow the listing with the bucket count, total usage and a breakdown by storage class")
+	cmd.Flags().StringVar(&organization, flags.Organization, "", "List the buckets of every project in this GCP organization (numeric ID)")
+	cmd.Flags().StringVar(&folder, flags.Folder, "", "List the buckets of every project in this GCP folder (numeric ID)")
+	cmd.MarkFlagsMutuallyExclusive(flags.Organization, flags.Folder)
+	addWatchFlag(cmd, &watch)
 
 	return cmd
 }
+
+// searchScope returns the organization or folder to list the buckets of, in
+// the "organizations/ID" or "folders/ID" form, or "" for the configured projects.
+func searchScope(organization, folder string) (string, error) {
+	kind, id, flag := "organizations", organization, flags.Organization
+	if folder != "" {
+		kind, id, flag = "folders", folder, flags.Folder
+	}
+	if id == "" {
+		return "", nil
+	}
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return "", fmt.Errorf("--%s takes a numeric ID, got %q", flag, id)
+	}
+	return kind + "/" + id, nil
+}
+
+// listScopeBuckets lists the buckets under scope from each provider that can
+// search it. Providers that cannot are skipped, unless explicitly requested.
+// With failFast, the providers after the first failure are not searched.
+func listScopeBuckets(ctx context.Context, app *appContainer, providers []string, scope string, explicit, failFast bool) ([]storage.Bucket, error) {
+	var buckets []storage.Bucket
+	var failures service.ProviderErrors
+	searched := 0
+	for _, provider := range providers {
+		found, err := app.StorageService.ListOrganizationBuckets(ctx, provider, scope)
+		if errors.Is(err, service.ErrUnsupported) && !explicit {
+			continue
+		}
+		searched++
+		if err != nil {
+			failures = append(failures, &service.ProviderError{Provider: provider, Err: err})
+			if failFast {
+				break
+			}
+			continue
+		}
+		buckets = append(buckets, found...)
+	}
+	if searched == 0 {
+		return nil, fmt.Errorf("none of the providers %s can list the buckets of %s: %w", strings.Join(providers, ", "), scope, service.ErrUnsupported)
+	}
+	if len(failures) > 0 {
+		return buckets, failures
+	}
+	return buckets, nil
+}
+
+// bucketListView returns the view of a bucket listing, with its summary if asked for.
+func bucketListView(buckets []storage.Bucket, summary bool) output.TableRenderer {
+	if summary {
+		return output.BucketListSummaryView{Buckets: buckets, Summary: output.SummarizeBuckets(buckets)}
+	}
+	return output.BucketListView(buckets)
+}
+
+// streamBuckets lists buckets as a table that grows as each provider answers,
+// so one slow provider does not hold back the others' results. With
+// --fail-fast the rows already shown stay, but the command still fails. The
+// summary, if asked for, follows once every provider has answered.
+func streamBuckets(ctx context.Context, app *appContainer, providers []string, failFast, summary bool) error {
+	stream := output.NewBucketStream(os.Stdout, len(providers))
+	var writeErr error
+	allBuckets, listErr := app.StorageService.ListAllBucketsFunc(ctx, providers, func(_ string, buckets []storage.Bucket) {
+		if writeErr == nil {
+			writeErr = stream.Add(buckets)
+		}
+	})
+	if err := cmp.Or(writeErr, stream.Close()); err != nil {
+		return err
+	}
+	if listErr != nil && (failFast || len(allBuckets) == 0) {
+		return listErr
+	}
+	if len(allBuckets) == 0 {
+		fmt.Println("No buckets found.")
+		return nil
+	}
+	if summary {
+		fmt.Printf("\n%s", output.SummarizeBuckets(allBuckets).RenderTable())
+	}
+	app.PartialResults = listErr != nil
+	return renderProviderWarnings(os.Stderr, listErr)
+}
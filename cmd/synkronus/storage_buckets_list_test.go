@@ -7,12 +7,12 @@ import (
 	"strings"
 	"testing"
 
-	"synkronus/internal/config"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/output"
-	"synkronus/internal/provider/factory"
-	"synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/service"
 )
 
 // newBucketListTestApp builds an appContainer suitable for list-buckets tests.
@@ -56,6 +56,68 @@ func TestListBucketsCmd_HappyPath_ReturnsBuckets(t *testing.T) {
 	// successful execution rather than captured output content.
 }
 
+func TestListBucketsCmd_Summary(t *testing.T) {
+	mock := &cmdMockStorage{buckets: []storage.Bucket{
+		{Name: "alpha", Provider: domain.GCP, StorageClass: "STANDARD", UsageBytes: 10},
+	}}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	cmd := newListBucketsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--summary"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// cmdOrganizationStorage lists the buckets of an organization, recording the scope searched.
+type cmdOrganizationStorage struct {
+	cmdMockStorage
+	scope string
+}
+
+func (m *cmdOrganizationStorage) ListOrganizationBuckets(_ context.Context, scope string) ([]storage.Bucket, error) {
+	m.scope = scope
+	return m.buckets, nil
+}
+
+func TestListBucketsCmd_Organization(t *testing.T) {
+	mock := &cmdOrganizationStorage{cmdMockStorage: cmdMockStorage{
+		buckets: []storage.Bucket{{Name: "alpha", Provider: domain.GCP, Project: "team-prod"}},
+	}}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	cmd := newListBucketsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--folder", "42"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.scope != "folders/42" {
+		t.Errorf("expected the folder to be searched, got %q", mock.scope)
+	}
+
+	cmd = newListBucketsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--organization", "acme"})
+	var usage *usageError
+	if err := cmd.Execute(); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error for a non-numeric ID, got %v", err)
+	}
+}
+
+func TestListBucketsCmd_Organization_Unsupported(t *testing.T) {
+	mock := &cmdMockStorage{buckets: []storage.Bucket{{Name: "alpha", Provider: domain.GCP}}}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	cmd := newListBucketsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--organization", "123"})
+	if err := cmd.Execute(); !errors.Is(err, service.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
 func TestListBucketsCmd_EmptyResults_PrintsMessage(t *testing.T) {
 	mock := &cmdMockStorage{buckets: []storage.Bucket{}}
 	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}
@@ -113,3 +175,52 @@ func TestListBucketsCmd_ServiceError_AllProvidersFail_ReturnsError(t *testing.T)
 		t.Fatal("expected error when all providers fail, got nil")
 	}
 }
+
+// newMultiProviderBucketListTestApp is newBucketListTestApp with both GCP and AWS configured.
+func newMultiProviderBucketListTestApp(storageFactory service.StorageProviderFactory) *appContainer {
+	app := newBucketListTestApp(storageFactory)
+	cfg := &config.Config{
+		GCP: &config.GCPConfig{Project: "test-project"},
+		AWS: &config.AWSConfig{Region: "us-east-1"},
+	}
+	app.ProviderFactory = factory.NewFactory(cfg, app.Logger)
+	return app
+}
+
+func TestListBucketsCmd_PartialFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{"lists the buckets of the providers that succeeded", nil, false},
+		{"fail-fast returns the provider error", []string{"--fail-fast"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := &cmdStorageFactory{providers: map[string]storage.Storage{
+				"gcp": &cmdMockStorage{buckets: []storage.Bucket{{Name: "gcp-bucket", Provider: domain.GCP}}},
+				"aws": &cmdMockStorage{err: errors.New("access denied")},
+			}}
+			app := newMultiProviderBucketListTestApp(factory)
+
+			var buf bytes.Buffer
+			cmd := newListBucketsCmd()
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+			cmd.SetContext(app.ToContext(context.Background()))
+			cmd.SetArgs(append([]string{"--providers", "gcp,aws"}, tt.args...))
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "provider aws") {
+				t.Errorf("expected error to name the failing provider, got: %v", err)
+			}
+			if app.PartialResults == tt.wantErr {
+				t.Errorf("expected PartialResults %v when the buckets of gcp are listed", !tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cleanup"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newCleanupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Find and delete objects that only take up space",
+		Long: `Finds objects, or object versions, that only clutter a bucket or take up space, and deletes
+them. Under the global --dry-run, what would be deleted is listed and nothing is. Otherwise
+confirmation is required by typing the number of objects to delete, unless --force (or the
+global --yes) is given. Set defaults.confirm_modes.cleanup to "yes-no" to answer y instead.`,
+	}
+	cmd.AddCommand(newCleanupDeleteMarkersCmd(), newCleanupVersionsCmd(), newCleanupEmptyCmd())
+	return cmd
+}
+
+// runCleanup deletes what plan lists once confirmed, describing the
+// candidates as what (e.g., "delete markers"), or lists them under --dry-run.
+func runCleanup(cmd *cobra.Command, app *appContainer, plan cleanup.Plan, what string, parallel int, force bool) error {
+	if len(plan.Candidates) == 0 {
+		fmt.Fprintf(os.Stderr, "No %s to delete in bucket %s\n", what, plan.Bucket)
+		return nil
+	}
+	if app.DryRun {
+		if err := output.Render(os.Stdout, app.OutputFormat, output.CleanupPlanView(plan)); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Dry run: nothing was deleted.")
+		return nil
+	}
+
+	count := strconv.Itoa(len(plan.Candidates))
+	warningMessage := fmt.Sprintf(
+		"\nWARNING: You are about to permanently delete %s %s (%s) from bucket '%s' (%s).\nThis action cannot be undone.",
+		count, what, storage.FormatBytes(plan.Bytes), plan.Bucket, strings.ToUpper(plan.Provider))
+	c := confirmationFor(app.Config, confirmCleanup, prompt.ModeTyped)
+	return confirmThenRun(cmd.Context(), app.Prompter, c, warningMessage, count, force, func() error {
+		report, runErr := cleanup.Run(cmd.Context(), app.StorageService, plan, parallel)
+		if err := output.Render(os.Stdout, app.OutputFormat, output.CleanupReportView(report)); err != nil {
+			return errors.Join(runErr, err)
+		}
+		return runErr
+	})
+}
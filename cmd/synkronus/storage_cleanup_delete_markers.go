@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cleanup"
+	"github.com/jkleinne/synkronus/internal/flags"
+
+	"github.com/spf13/cobra"
+)
+
+func newCleanupDeleteMarkersCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var parallel int
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "delete-markers",
+		Short: "Remove S3 delete markers that no longer hide any version",
+		Long: `Finds the delete markers of an S3 versioned bucket whose object has no version left, e.g.
+after lifecycle rules expired the noncurrent versions, and removes them. Such markers hold no
+data, but each is still listed and slows down listing the bucket's versions. Markers hiding a
+version are kept, since removing them would bring the object back.
+
+Only S3 has delete markers.`,
+		Example: `  synkronus storage cleanup delete-markers --provider aws --bucket logs --dry-run
+  synkronus storage cleanup delete-markers -p aws -b logs --prefix 2023/ --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if parallel <= 0 {
+				return &usageError{err: fmt.Errorf("--%s must be positive, got %d", flags.Parallel, parallel)}
+			}
+
+			plan, err := cleanup.DeleteMarkers(cmd.Context(), app.StorageService, provider, bucket, prefix)
+			if err != nil {
+				return err
+			}
+			return runCleanup(cmd, app, plan, "delete markers", parallel, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The versioned bucket to clean up (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only clean up the objects under this prefix")
+	cmd.Flags().IntVar(&parallel, flags.Parallel, 8, "Number of markers to remove at once")
+	cmd.Flags().BoolVarP(&force, flags.Force, flags.ForceShort, false, "Bypass interactive confirmation prompt")
+	markConfirmFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// cmdVersionedStorage serves a fixed list of object versions and records the
+// versions deleted.
+type cmdVersionedStorage struct {
+	cmdMockStorage
+	versions []storage.ObjectVersion
+	deleted  []string
+}
+
+func (m *cmdVersionedStorage) WalkObjectVersions(_ context.Context, _, _ string, fn func(storage.ObjectVersion) error) error {
+	for _, v := range m.versions {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *cmdVersionedStorage) DeleteObjectVersion(_ context.Context, _, objectKey, versionID string) error {
+	m.deleted = append(m.deleted, objectKey+"#"+versionID)
+	return nil
+}
+
+func newOrphanedMarkerStorage() *cmdVersionedStorage {
+	return &cmdVersionedStorage{versions: []storage.ObjectVersion{
+		{Key: "gone.txt", VersionID: "m1", DeleteMarker: true, IsLatest: true},
+		{Key: "hidden.txt", VersionID: "m2", DeleteMarker: true, IsLatest: true},
+		{Key: "hidden.txt", VersionID: "v1", Size: 10},
+	}}
+}
+
+func TestCleanupDeleteMarkersCmd(t *testing.T) {
+	mock := newOrphanedMarkerStorage()
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"aws": mock}}, nil)
+	cmd := newCleanupDeleteMarkersCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "logs", "--force"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.deleted) != 1 || mock.deleted[0] != "gone.txt#m1" {
+		t.Errorf("expected only the orphaned marker removed, got %v", mock.deleted)
+	}
+}
+
+func TestCleanupDeleteMarkersCmd_DryRun(t *testing.T) {
+	mock := newOrphanedMarkerStorage()
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"aws": mock}}, nil)
+	app.DryRun = true
+	cmd := newCleanupDeleteMarkersCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "logs"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.deleted) != 0 {
+		t.Errorf("expected nothing deleted under --dry-run, got %v", mock.deleted)
+	}
+}
+
+func TestCleanupDeleteMarkersCmd_Declined(t *testing.T) {
+	mock := newOrphanedMarkerStorage()
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"aws": mock}}, &mockPrompter{confirmed: false})
+	cmd := newCleanupDeleteMarkersCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "logs"})
+
+	if err := cmd.Execute(); !errors.Is(err, ErrOperationAborted) {
+		t.Errorf("expected the cleanup aborted, got %v", err)
+	}
+	if len(mock.deleted) != 0 {
+		t.Errorf("expected nothing deleted when declined, got %v", mock.deleted)
+	}
+}
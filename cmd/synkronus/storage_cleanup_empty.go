@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cleanup"
+	"github.com/jkleinne/synkronus/internal/flags"
+
+	"github.com/spf13/cobra"
+)
+
+func newCleanupEmptyCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var placeholdersOnly bool
+	var parallel int
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "empty",
+		Short: "Delete zero-byte objects and directory placeholders",
+		Long: `Finds the zero-byte objects under --prefix and deletes them: the "directory" placeholders
+(keys ending in "/") that creating a folder in a console leaves behind, and other empty files.
+With --placeholders-only, empty files are kept.
+
+Run with the global --dry-run to list them without deleting anything. On a versioned bucket,
+deleting an object makes its current version noncurrent rather than removing it.`,
+		Example: `  synkronus storage cleanup empty --provider gcp --bucket uploads --dry-run
+  synkronus storage cleanup empty -p aws -b uploads --prefix incoming/ --placeholders-only --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if parallel <= 0 {
+				return &usageError{err: fmt.Errorf("--%s must be positive, got %d", flags.Parallel, parallel)}
+			}
+
+			plan, err := cleanup.EmptyObjects(cmd.Context(), app.StorageService, provider, bucket, prefix, placeholdersOnly)
+			if err != nil {
+				return err
+			}
+			what := "empty objects"
+			if placeholdersOnly {
+				what = "directory placeholders"
+			}
+			return runCleanup(cmd, app, plan, what, parallel, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to clean up (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only clean up the objects under this prefix")
+	cmd.Flags().BoolVar(&placeholdersOnly, flags.PlaceholdersOnly, false, "Only delete directory placeholders, keeping empty files")
+	cmd.Flags().IntVar(&parallel, flags.Parallel, 8, "Number of objects to delete at once")
+	cmd.Flags().BoolVarP(&force, flags.Force, flags.ForceShort, false, "Bypass interactive confirmation prompt")
+	markConfirmFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// cmdDeleteRecordingStorage records the objects deleted.
+type cmdDeleteRecordingStorage struct {
+	cmdMockStorage
+	deleted []string
+}
+
+func (m *cmdDeleteRecordingStorage) DeleteObject(_ context.Context, _, objectKey string) error {
+	m.deleted = append(m.deleted, objectKey)
+	return nil
+}
+
+func TestCleanupEmptyCmd_PlaceholdersOnly(t *testing.T) {
+	mock := &cmdDeleteRecordingStorage{cmdMockStorage: cmdMockStorage{
+		objects: storage.ObjectList{Objects: []storage.Object{
+			{Key: "incoming/"},
+			{Key: "incoming/empty.txt"},
+			{Key: "incoming/data.csv", Size: 42},
+		}},
+	}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	cmd := newCleanupEmptyCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "uploads", "--placeholders-only", "--force"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.deleted) != 1 || mock.deleted[0] != "incoming/" {
+		t.Errorf("expected only the placeholder deleted, got %v", mock.deleted)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cleanup"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+
+	"github.com/spf13/cobra"
+)
+
+func newCleanupVersionsCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var olderThan string
+	var keep int
+	var parallel int
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "versions",
+		Short: "Delete old noncurrent object versions",
+		Long: `Deletes the noncurrent versions (S3) or generations (GCP) under --prefix that were replaced or
+deleted longer than --older-than ago (e.g., 90d, 36h), keeping the --keep newest noncurrent
+versions of each object whatever their age. Current versions are never deleted, nor are S3
+delete markers (see 'storage cleanup delete-markers').
+
+Run with the global --dry-run to list the versions that would be deleted and the space that
+would be reclaimed.`,
+		Example: `  synkronus storage cleanup versions --provider gcp --bucket backups --older-than 90d --keep 3 --dry-run
+  synkronus storage cleanup versions -p aws -b backups --prefix db/ --older-than 30d --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			age, err := storage.ParseAge(olderThan)
+			if err != nil {
+				return &usageError{err: fmt.Errorf("invalid --%s: %w", flags.OlderThan, err)}
+			}
+			if keep < 0 {
+				return &usageError{err: fmt.Errorf("--%s must not be negative, got %d", flags.Keep, keep)}
+			}
+			if parallel <= 0 {
+				return &usageError{err: fmt.Errorf("--%s must be positive, got %d", flags.Parallel, parallel)}
+			}
+
+			plan, err := cleanup.NoncurrentVersions(cmd.Context(), app.StorageService, provider, bucket, prefix, age, keep)
+			if err != nil {
+				return err
+			}
+			return runCleanup(cmd, app, plan, "noncurrent versions", parallel, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The versioned bucket to clean up (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only clean up the objects under this prefix")
+	cmd.Flags().StringVar(&olderThan, flags.OlderThan, "90d", "Delete versions noncurrent for longer than this")
+	cmd.Flags().IntVar(&keep, flags.Keep, 0, "Noncurrent versions of each object to keep regardless of age")
+	cmd.Flags().IntVar(&parallel, flags.Parallel, 8, "Number of versions to delete at once")
+	cmd.Flags().BoolVarP(&force, flags.Force, flags.ForceShort, false, "Bypass interactive confirmation prompt")
+	markConfirmFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestCleanupVersionsCmd(t *testing.T) {
+	old := time.Now().AddDate(-1, 0, 0)
+	mock := &cmdVersionedStorage{versions: []storage.ObjectVersion{
+		{Key: "db.dump", VersionID: "3", IsLatest: true, LastModified: old},
+		{Key: "db.dump", VersionID: "2", Size: 10, LastModified: old, NoncurrentSince: old},
+		{Key: "db.dump", VersionID: "1", Size: 10, LastModified: old, NoncurrentSince: old},
+	}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	cmd := newCleanupVersionsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "backups", "--older-than", "90d", "--keep", "1", "--force"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(mock.deleted, []string{"db.dump#1"}) {
+		t.Errorf("expected only the oldest version deleted, got %v", mock.deleted)
+	}
+}
+
+func TestCleanupVersionsCmd_InvalidFlags(t *testing.T) {
+	for _, args := range [][]string{{"--older-than", "soon"}, {"--keep", "-1"}} {
+		app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdVersionedStorage{}}}, nil)
+		cmd := newCleanupVersionsCmd()
+		cmd.SetContext(app.ToContext(context.Background()))
+		cmd.SetArgs(append([]string{"--provider", "gcp", "--bucket", "backups"}, args...))
+
+		var usage *usageError
+		if err := cmd.Execute(); !errors.As(err, &usage) {
+			t.Errorf("%v: expected a usage error, got %v", args, err)
+		}
+	}
+}
@@ -12,6 +12,26 @@ func newStorageCmd() *cobra.Command {
 	cmd.AddCommand(
 		newBucketsCmd(),
 		newObjectsCmd(),
+		newBenchmarkCmd(),
+		newBenchListCmd(),
+		newBrowseCmd(),
+		newFindCmd(),
+		newEventsCmd(),
+		newBatchCmd(),
+		newDiffCmd(),
+		newMetadataCmd(),
+		newSignPostPolicyCmd(),
+		newCorsCmd(),
+		newURLCmd(),
+		newRecommendCmd(),
+		newLifecycleCmd(),
+		newCleanupCmd(),
+		newReportCmd(),
+		newAnalyzeACLsCmd(),
+		newIAMCmd(),
+		newAccessReportCmd(),
+		newEnforceLabelsCmd(),
+		newHistoryCmd(),
 	)
 	return cmd
 }
@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newCorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cors",
+		Short: "Check the CORS rules of buckets",
+		Long:  `Check what cross-origin requests from web pages the CORS rules of a bucket allow.`,
+	}
+
+	cmd.AddCommand(
+		newCorsTestCmd(),
+	)
+	return cmd
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cors"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newCorsTestCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var key string
+	var origin string
+	var method string
+	var headers []string
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Send a CORS preflight to a bucket and report whether it is allowed",
+		Long: `Sends the preflight (OPTIONS) request a browser would send before a cross-origin request
+from --origin with --method (and --header, for each non-simple header the page sends), to the
+bucket's own endpoint, and reports whether the bucket's CORS rules allow the request, with the
+Access-Control headers it answered with.
+
+The preflight goes to the bucket itself, or to --key within it. It is a real request from this
+machine, so it checks the rules the provider actually applies, not just the configuration.
+
+Exits non-zero when the request is not allowed.`,
+		Example: `  synkronus storage cors test --provider gcp --bucket uploads --origin https://app.example.com --method PUT
+  synkronus storage cors test -p aws -b assets --origin https://app.example.com --method GET --header Range -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			bucketURL, err := app.StorageService.BucketURL(cmd.Context(), bucket, provider, "")
+			if err != nil {
+				return err
+			}
+			result, err := cors.Preflight(cmd.Context(), nil, storage.ObjectURL(bucketURL, key), cors.Request{
+				Origin:  origin,
+				Method:  method,
+				Headers: headers,
+			})
+			if err != nil {
+				return err
+			}
+			if err := output.Render(os.Stdout, app.OutputFormat, output.CORSResultView(result)); err != nil {
+				return err
+			}
+			if !result.Allowed {
+				return fmt.Errorf("bucket %s does not allow %s requests from %s: %s", bucket, result.Method, origin, result.Reason)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket whose CORS rules are tested (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&origin, flags.Origin, "", "The origin of the page making the request (required)")
+	cmd.MarkFlagRequired(flags.Origin)
+	cmd.Flags().StringVar(&method, flags.Method, "GET", "The method of the request")
+	cmd.Flags().StringSliceVar(&headers, flags.Header, nil, "A header the request sends (repeatable)")
+	cmd.Flags().StringVar(&key, flags.ObjectKey, "", "The object the request is for (defaults to the bucket itself)")
+
+	return cmd
+}
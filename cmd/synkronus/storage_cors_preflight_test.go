@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// cmdEndpointStorage gives its buckets URLs on a test server.
+type cmdEndpointStorage struct {
+	cmdMockStorage
+	baseURL string
+}
+
+func (m *cmdEndpointStorage) BucketURL(ctx context.Context, bucketName, style string) (string, error) {
+	return m.baseURL + "/" + bucketName, nil
+}
+
+func TestCorsTestCmd(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET")
+	}))
+	defer server.Close()
+	mock := &cmdEndpointStorage{baseURL: server.URL}
+
+	run := func(method string) error {
+		app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+		cmd := newCorsTestCmd()
+		cmd.SetContext(app.ToContext(context.Background()))
+		cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "assets", "--key", "img/logo.png", "--origin", "https://app.example.com", "--method", method})
+		return cmd.Execute()
+	}
+
+	if err := run("GET"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/assets/img/logo.png" {
+		t.Errorf("expected the preflight sent for the object, got %s", gotPath)
+	}
+	if err := run("DELETE"); err == nil || !strings.Contains(err.Error(), "does not allow DELETE") {
+		t.Errorf("expected a denied DELETE to fail, got %v", err)
+	}
+}
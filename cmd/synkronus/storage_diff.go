@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/service"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	var prefix string
+
+	cmd := &cobra.Command{
+		Use:   "diff <source> <destination>",
+		Short: "Compare the objects of two buckets",
+		Long: `Compares the objects under two bucket locations, written as gs://bucket/prefix,
+s3://bucket/prefix or a bucket alias, and reports the keys only in the source, the keys
+only in the destination, and the keys whose content differs. Keys are compared relative
+to each location's prefix, as a sync copies them. --prefix is appended to both locations.
+
+Content is judged by size and by the checksums both providers report (MD5, else CRC32C).
+Between GCP and AWS only sizes can be compared, since S3 reports no MD5 outside its
+ETag; such objects are counted as compared by size only.
+
+Exits non-zero when the locations differ, so the command can check a sync or migration.`,
+		Example: `  synkronus storage diff gs://data s3://data-replica
+  synkronus storage diff gs://data/exports/ s3://data-replica/exports/ -o json
+  synkronus storage diff data-lake replica --prefix 2024/`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			src, err := parseBucketURL(app.Config, args[0])
+			if err != nil {
+				return &usageError{err: err}
+			}
+			dst, err := parseBucketURL(app.Config, args[1])
+			if err != nil {
+				return &usageError{err: err}
+			}
+			src.Prefix += prefix
+			dst.Prefix += prefix
+
+			comparison, err := app.StorageService.CompareObjects(cmd.Context(), service.SyncOptions{Source: src, Destination: dst})
+			if err != nil {
+				return err
+			}
+			if err := output.Render(os.Stdout, app.OutputFormat, output.ObjectComparisonView(comparison)); err != nil {
+				return err
+			}
+			if comparison.Differs() {
+				return fmt.Errorf("%s and %s differ", src, dst)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Compare only the objects under this prefix of both locations")
+
+	return cmd
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"github.com/jkleinne/synkronus/internal/lint"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newEnforceLabelsCmd() *cobra.Command {
+	var providersList []string
+	var rulesPath string
+	var apply bool
+
+	cmd := &cobra.Command{
+		Use:   "enforce-labels",
+		Short: "Add default labels to the buckets missing them",
+		Long: `Describes every bucket of the configured providers (or --providers) and lists the labels (tags
+on S3) it lacks among the default_labels of a rules file, the same file 'synkronus lint' reads:
+
+  buckets:
+    required_labels: [team, env, cost-center]
+    default_labels:
+      env: dev
+      cost-center: shared
+
+Nothing is changed unless --apply is given; then the default labels are added to each bucket,
+leaving its other labels, including ones whose value breaks the rules, as they are. Required
+labels without a default are listed to set by hand. Buckets that cannot be described are
+reported as warnings on stderr.`,
+		Example: `  synkronus storage enforce-labels --rules policy.yaml
+  synkronus storage enforce-labels --rules policy.yaml --providers aws --apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			rules, err := readLintRules(rulesPath)
+			if err != nil {
+				return err
+			}
+			providers, err := storageProviderResolver(app).Resolve(providersList)
+			if err != nil {
+				return err
+			}
+
+			buckets, describeErr := inventory.Describe(cmd.Context(), app.StorageService, providers)
+			if describeErr != nil && len(buckets) == 0 {
+				return describeErr
+			}
+			plan := lint.PlanLabels(buckets, rules)
+
+			var applyErr error
+			if apply && !app.DryRun {
+				plan, applyErr = lint.ApplyLabels(cmd.Context(), app.StorageService, plan)
+			}
+			if err := output.Render(os.Stdout, app.OutputFormat, output.LabelPlanView(plan)); err != nil {
+				return errors.Join(applyErr, err)
+			}
+			if !apply || app.DryRun {
+				fmt.Fprintf(os.Stderr, "Dry run: no labels were changed; run with --%s to add them.\n", flags.Apply)
+			}
+			app.PartialResults = describeErr != nil
+			return errors.Join(applyErr, renderInventoryWarnings(os.Stderr, describeErr))
+		},
+	}
+
+	cmd.Flags().StringVar(&rulesPath, flags.Rules, "", "The rules file with the default labels (required)")
+	cmd.MarkFlagRequired(flags.Rules)
+	cmd.Flags().BoolVar(&apply, flags.Apply, false, "Add the default labels instead of only listing them")
+	cmd.Flags().StringSliceVarP(&providersList, flags.Providers, flags.ProvidersShort, nil, "Specify providers to label (comma-separated). Defaults to all configured providers.")
+
+	return cmd
+}
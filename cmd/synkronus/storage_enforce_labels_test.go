@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// cmdLabelingStorage records the labels set on buckets.
+type cmdLabelingStorage struct {
+	cmdMockStorage
+	labels map[string]map[string]string
+}
+
+func (m *cmdLabelingStorage) UpdateBucketLabels(_ context.Context, bucketName string, labels map[string]string) error {
+	m.labels[bucketName] = labels
+	return nil
+}
+
+func TestEnforceLabelsCmd(t *testing.T) {
+	mock := &cmdLabelingStorage{
+		cmdMockStorage: cmdMockStorage{
+			buckets: []storage.Bucket{{Name: "data", Provider: domain.GCP}},
+			bucket:  storage.Bucket{Name: "data", Provider: domain.GCP, Labels: map[string]string{"team": "data"}},
+		},
+		labels: map[string]map[string]string{},
+	}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("buckets:\n  default_labels: {team: unknown, env: dev}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newEnforceLabelsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--rules", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.labels) != 0 {
+		t.Fatalf("expected no labels to be set without --apply, got %v", mock.labels)
+	}
+
+	cmd = newEnforceLabelsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--rules", path, "--apply"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mock.labels["data"]; len(got) != 1 || got["env"] != "dev" {
+		t.Errorf("expected only env=dev to be added, got %v", got)
+	}
+}
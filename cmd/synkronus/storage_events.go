@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Inspect bucket event notifications",
+		Long:  `Follow the object events that buckets publish through their event notifications.`,
+	}
+
+	cmd.AddCommand(
+		newEventsTailCmd(),
+	)
+	return cmd
+}
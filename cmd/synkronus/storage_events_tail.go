@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newEventsTailCmd() *cobra.Command {
+	var provider string
+	var bucket string
+
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Stream a bucket's object events as they happen",
+		Long: `Streams the object create, delete and update events of a bucket as its event
+notifications deliver them, until Ctrl+C. Use it to check what an event-driven
+pipeline receives when objects change.
+
+The bucket must already have a notification configuration:
+  GCP  A Pub/Sub notification. A temporary subscription is created on its topic, so
+       existing subscribers still receive every event, and deleted on exit (Pub/Sub
+       removes it after a day without use if synkronus is killed).
+  AWS  An SQS queue notification. Messages are peeked without being consumed, so the
+       queue's consumers still receive and delete them; each peek does count towards
+       a message's receive count, which matters for queues with a redrive policy. Only
+       events still waiting in the queue are seen.
+
+With -o json, events are written as JSON Lines (one object per line).`,
+		Example: `  synkronus storage events tail --provider gcp --bucket my-bucket
+  synkronus storage events tail --provider aws --bucket my-bucket -o json | jq .key`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			stream, err := output.NewEventStream(os.Stdout, app.OutputFormat)
+			if err != nil {
+				return err
+			}
+			ready := func(source string) {
+				fmt.Fprintf(os.Stderr, "Tailing events from %s (Ctrl+C to stop)\n", source)
+			}
+			return app.StorageService.TailEvents(cmd.Context(), bucket, provider, ready, stream.Write)
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider of the bucket (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket whose events to stream (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+
+	return cmd
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+)
+
+// cmdTailingStorage delivers a fixed list of events, then stops.
+type cmdTailingStorage struct {
+	cmdMockStorage
+	events []storage.ObjectEvent
+}
+
+func (m *cmdTailingStorage) TailEvents(ctx context.Context, bucketName string, ready func(string), fn func(storage.ObjectEvent) error) error {
+	ready("test queue")
+	for _, event := range m.events {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestEventsTailCmd(t *testing.T) {
+	mock := &cmdTailingStorage{events: []storage.ObjectEvent{
+		{Type: storage.ObjectCreated, Bucket: "my-bucket", Key: "a.txt", Size: 10},
+		{Type: storage.ObjectDeleted, Bucket: "my-bucket", Key: "b.txt"},
+	}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"aws": mock}}, nil)
+
+	cmd := newEventsTailCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "my-bucket"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEventsTailCmd_Unsupported(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"aws": &cmdMockStorage{}}}, nil)
+
+	cmd := newEventsTailCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "aws", "--bucket", "my-bucket"})
+
+	if err := cmd.Execute(); !errors.Is(err, service.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newFindCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var namePattern string
+	var regex bool
+
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Search object keys by pattern",
+		Long: `Scans every object key under --prefix and lists the objects whose key matches
+--name-pattern, with their size and last modified time.
+
+The pattern is a glob matched against the whole key: '*' matches any run of characters,
+'/' included, and '?' matches one character. Use --regex for a regular expression, which
+may match anywhere in the key.
+
+Without --bucket, every bucket of the provider is searched. Buckets that cannot be listed
+(e.g., for lack of permission) are skipped and reported as warnings on stderr.`,
+		Example: `  synkronus storage find --provider gcp --bucket my-bucket --name-pattern '*backup*2024*'
+  synkronus storage find --provider aws --name-pattern '\.sql\.gz$' --regex -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			pattern, err := storage.CompileKeyPattern(namePattern, regex)
+			if err != nil {
+				return &usageError{err: err}
+			}
+
+			buckets := []string{bucket}
+			if bucket == "" {
+				buckets, err = providerBucketNames(cmd.Context(), app, provider)
+				if err != nil {
+					return err
+				}
+			}
+
+			matches := []storage.Object{}
+			for _, name := range buckets {
+				err := app.StorageService.WalkObjects(cmd.Context(), name, provider, prefix, func(obj storage.Object) error {
+					if pattern.MatchString(obj.Key) {
+						obj.Bucket = name
+						matches = append(matches, obj)
+					}
+					return nil
+				})
+				if err == nil {
+					continue
+				}
+				// A single bucket was asked for, so there is nothing to show without it
+				if bucket != "" || cmd.Context().Err() != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Warning: skipped bucket: %v\n", err)
+				app.PartialResults = true
+			}
+
+			return output.Render(os.Stdout, app.OutputFormat, output.ObjectMatchView(matches))
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider to search (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to search (optional; defaults to every bucket of the provider)")
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only search objects beginning with this prefix (optional)")
+	cmd.Flags().StringVar(&namePattern, flags.NamePattern, "", "The glob (or, with --regex, regular expression) to match object keys against (required)")
+	cmd.MarkFlagRequired(flags.NamePattern)
+	cmd.Flags().BoolVar(&regex, flags.Regex, false, "Treat --name-pattern as a regular expression")
+
+	return cmd
+}
+
+// providerBucketNames returns the names of every bucket of provider.
+func providerBucketNames(ctx context.Context, app *appContainer, provider string) ([]string, error) {
+	buckets, err := app.StorageService.ListAllBuckets(ctx, []string{provider})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(buckets))
+	for i, b := range buckets {
+		names[i] = b.Name
+	}
+	return names, nil
+}
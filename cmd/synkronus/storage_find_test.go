@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestFindCmd_SingleBucket(t *testing.T) {
+	mock := &cmdMockStorage{objects: storage.ObjectList{Objects: []storage.Object{
+		{Key: "db/backup-2024-01.tar", Size: 2048},
+		{Key: "db/backup-2023-12.tar", Size: 1024},
+	}}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+
+	var buf bytes.Buffer
+	cmd := newFindCmd()
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--name-pattern", "*backup*2024*"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFindCmd_AllBuckets(t *testing.T) {
+	mock := &cmdMockStorage{
+		buckets: []storage.Bucket{{Name: "a", Provider: domain.GCP}, {Name: "b", Provider: domain.GCP}},
+		objects: storage.ObjectList{Objects: []storage.Object{{Key: "exports/2024-05.json"}}},
+	}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+
+	cmd := newFindCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--name-pattern", `\d{4}-\d{2}`, "--regex"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.PartialResults {
+		t.Error("expected complete results")
+	}
+}
+
+func TestFindCmd_SingleBucketListingFails(t *testing.T) {
+	mock := &cmdMockStorage{err: errors.New("access denied")}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+
+	cmd := newFindCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--name-pattern", "*"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected the listing error")
+	}
+}
+
+func TestFindCmd_InvalidRegex_IsUsageError(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{}, nil)
+	cmd := newFindCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--name-pattern", "backup(", "--regex"})
+
+	err := cmd.Execute()
+	var usage *usageError
+	if !errors.As(err, &usage) {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
+
+func TestFindCmd_MissingNamePattern_ReturnsError(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{}, nil)
+
+	var buf bytes.Buffer
+	cmd := newFindCmd()
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for missing --name-pattern flag, got nil")
+	}
+}
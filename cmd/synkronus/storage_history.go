@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var since string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history [object-key]",
+		Short: "Show who wrote, deleted or changed the ACL of an object, from audit logs",
+		Long: `Searches the provider's audit logs for the calls made on an object since --since and shows
+them as a timeline, newest first: writes, deletes, ACL and metadata changes, who made each
+call and from where. Failed calls (e.g., denied ones) are listed with their error. Reads
+are left out. Use it to find out what happened to an object during an incident.
+
+Object calls are data events, which are not logged by default:
+  GCP  Cloud Audit Logs of the bucket's project. Data Write audit logs must be enabled
+       for Cloud Storage; needs logging.logEntries.list (e.g., roles/logging.viewer,
+       plus roles/logging.privateLogViewer for Data Access logs).
+  AWS  CloudTrail Lake. An event data store of the configured region must collect S3
+       data events (resources.type AWS::S3::Object); the first one that does is
+       queried. Batch deletes (DeleteObjects) do not name their keys, so they are not
+       found. Queries are billed by the data they scan.
+
+Audit logs are only searched as far back as they are kept (e.g., 30 days for GCP Data
+Access logs by default).`,
+		Example: `  synkronus storage history reports/q3.csv --provider gcp --bucket finance
+  synkronus storage history backup.tar -p aws -b archive --since 90d -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			window, err := storage.ParseAge(since)
+			if err != nil {
+				return &usageError{err: fmt.Errorf("invalid --%s: %w", flags.Since, err)}
+			}
+			if limit < 0 {
+				return &usageError{err: fmt.Errorf("--%s must not be negative, got %d", flags.Limit, limit)}
+			}
+
+			objectKey := args[0]
+			from := time.Now().Add(-window)
+			changes, err := app.StorageService.ObjectHistory(cmd.Context(), bucket, objectKey, provider, from, limit)
+			if err != nil {
+				return err
+			}
+			if changes == nil {
+				changes = []storage.ObjectChange{}
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.ObjectHistoryView{
+				Provider: provider,
+				Bucket:   bucket,
+				Key:      objectKey,
+				Since:    from,
+				Changes:  changes,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the object resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket containing the object (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&since, flags.Since, "30d", "How far back to search the audit logs")
+	cmd.Flags().IntVar(&limit, flags.Limit, 100, "Number of most recent changes to show; 0 shows all")
+	markObjectRefArg(cmd)
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+)
+
+// cmdHistoryStorage records the search ObjectHistory was called with.
+type cmdHistoryStorage struct {
+	cmdMockStorage
+	key   string
+	since time.Time
+	limit int
+}
+
+func (m *cmdHistoryStorage) ObjectHistory(ctx context.Context, bucketName, objectKey string, since time.Time, limit int) ([]storage.ObjectChange, error) {
+	m.key, m.since, m.limit = objectKey, since, limit
+	return []storage.ObjectChange{{Time: time.Now(), Type: storage.ChangeDelete, Operation: "DeleteObject", Principal: "arn:aws:iam::123456789012:user/alice"}}, nil
+}
+
+func TestHistoryCmd(t *testing.T) {
+	mock := &cmdHistoryStorage{}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"aws": mock}}, nil)
+
+	cmd := newHistoryCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"reports/q3.csv", "--provider", "aws", "--bucket", "finance", "--since", "7d", "--limit", "10"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.key != "reports/q3.csv" || mock.limit != 10 {
+		t.Errorf("unexpected search of %q with limit %d", mock.key, mock.limit)
+	}
+	if ago := time.Since(mock.since); ago < 7*24*time.Hour || ago > 7*24*time.Hour+time.Minute {
+		t.Errorf("expected a search of the last 7 days, got since %v", mock.since)
+	}
+}
+
+func TestHistoryCmd_Errors(t *testing.T) {
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"aws": &cmdMockStorage{}}}
+
+	for _, args := range [][]string{{"--since", "soon"}, {"--limit", "-1"}} {
+		cmd := newHistoryCmd()
+		cmd.SetContext(newStorageTestApp(factory, nil).ToContext(context.Background()))
+		cmd.SetArgs(append([]string{"a.txt", "-p", "aws", "-b", "data"}, args...))
+		var usage *usageError
+		if err := cmd.Execute(); !errors.As(err, &usage) {
+			t.Errorf("%v: expected a usage error, got %v", args, err)
+		}
+	}
+
+	cmd := newHistoryCmd()
+	cmd.SetContext(newStorageTestApp(factory, nil).ToContext(context.Background()))
+	cmd.SetArgs([]string{"a.txt", "-p", "aws", "-b", "data"})
+	if err := cmd.Execute(); !errors.Is(err, service.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newIAMCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iam",
+		Short: "Check the access policies of buckets",
+		Long:  `Check the IAM policies of Cloud Storage buckets and the bucket policies of S3 buckets against reference policies.`,
+	}
+
+	cmd.AddCommand(
+		newIAMDiffCmd(),
+	)
+	return cmd
+}
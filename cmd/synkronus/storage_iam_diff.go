@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/iam"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newIAMDiffCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var filePath string
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare a bucket's live access policy with a reference policy file",
+		Long: `Compares the access policy of a bucket with a reference policy kept under version control, and
+lists the principals granted a role only by the live policy (+) or only by the reference (-).
+Bindings are compared per role, condition and principal, so their order does not matter.
+
+The reference is the JSON of a Cloud Storage IAM policy, as written by
+'gcloud storage buckets get-iam-policy gs://BUCKET --format=json', or of an S3 bucket policy,
+whose statements are compared whole. Version and etag fields are ignored.
+
+Exits non-zero when the policy has drifted, so the command can guard policy-as-code pipelines.`,
+		Example: `  synkronus storage iam diff --provider gcp --bucket data --file policies/data.json
+  synkronus storage iam diff -p aws -b logs --file policies/logs.json -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(filePath)
+			if err != nil {
+				return fmt.Errorf("opening policy file %q: %w", filePath, err)
+			}
+			expected, err := iam.ReadPolicy(f)
+			f.Close()
+			if err != nil {
+				return &usageError{err: fmt.Errorf("reading policy file %q: %w", filePath, err)}
+			}
+
+			drift, err := iam.Diff(cmd.Context(), app.StorageService, provider, bucket, expected)
+			if err != nil {
+				return err
+			}
+			if err := output.Render(os.Stdout, app.OutputFormat, output.IAMDriftView(drift)); err != nil {
+				return err
+			}
+			if drift.Drifted() {
+				return fmt.Errorf("the policy of bucket %s differs from %s", bucket, filePath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to check (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&filePath, flags.File, "", "The reference policy file (required)")
+	cmd.MarkFlagRequired(flags.File)
+
+	return cmd
+}
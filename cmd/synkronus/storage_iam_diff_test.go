@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestIAMDiffCmd(t *testing.T) {
+	mock := &cmdMockStorage{bucket: storage.Bucket{
+		Name: "data",
+		IAMPolicy: &storage.IAMPolicy{Bindings: []storage.IAMBinding{
+			{Role: "roles/storage.objectViewer", Principals: []string{"group:data@example.com"}},
+		}},
+	}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr bool
+	}{
+		{"Matches", `{"version": 1, "bindings": [{"role": "roles/storage.objectViewer", "members": ["group:data@example.com"]}]}`, false},
+		{"Drifted", `{"bindings": [{"role": "roles/storage.objectViewer", "members": ["group:analysts@example.com"]}]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			if err := os.WriteFile(path, []byte(tt.policy), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cmd := newIAMDiffCmd()
+			cmd.SetContext(app.ToContext(context.Background()))
+			cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "data", "--file", path})
+
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
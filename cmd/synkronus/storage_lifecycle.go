@@ -0,0 +1,12 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newLifecycleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lifecycle",
+		Short: "Work with bucket lifecycle rules",
+	}
+	cmd.AddCommand(newLifecycleSuggestCmd())
+	return cmd
+}
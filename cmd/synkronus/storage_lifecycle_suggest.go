@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/pricing"
+	"github.com/jkleinne/synkronus/internal/recommend"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newLifecycleSuggestCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var outputPath string
+	var noncurrentDays int
+
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Write lifecycle rules suited to the ages of a bucket's objects",
+		Long: `Groups the objects under --prefix by age, as 'storage recommend' does, and writes a lifecycle
+configuration moving them to colder storage classes as they age, for the ages that would save
+money. When the bucket keeps object versions, a rule deletes versions --noncurrent-days after
+they are replaced.
+
+The configuration is in the provider's own format, to review and then apply with:
+
+  gcloud storage buckets update gs://BUCKET --lifecycle-file=FILE
+  aws s3api put-bucket-lifecycle-configuration --bucket BUCKET --lifecycle-configuration file://FILE
+
+Applying it replaces the bucket's current rules. Nothing is written when there is nothing to
+suggest.`,
+		Example: `  synkronus storage lifecycle suggest --provider gcp --bucket archive > lifecycle.json
+  synkronus storage lifecycle suggest -p aws -b logs --noncurrent-days 30 --output-path lifecycle.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if noncurrentDays < 0 {
+				return &usageError{err: fmt.Errorf("--%s must not be negative, got %d", flags.NoncurrentDays, noncurrentDays)}
+			}
+
+			prices, err := pricing.Load()
+			if err != nil {
+				return err
+			}
+			report, err := recommend.Analyze(cmd.Context(), app.StorageService, prices, provider, bucket, prefix)
+			if err != nil {
+				return err
+			}
+			lifecycle, err := recommend.SuggestLifecycle(report, noncurrentDays)
+			if err != nil {
+				return err
+			}
+			if lifecycle.Rules == 0 {
+				fmt.Fprintf(os.Stderr, "No lifecycle rules to suggest for bucket %s\n", bucket)
+				return nil
+			}
+
+			var w io.Writer = os.Stdout
+			var f *os.File
+			if outputPath != "" {
+				if f, err = os.Create(outputPath); err != nil {
+					return fmt.Errorf("failed to write lifecycle file: %w", err)
+				}
+				w = f
+			}
+			err = lifecycle.Write(w)
+			if f != nil {
+				err = errors.Join(err, f.Close())
+			}
+			if err != nil {
+				return err
+			}
+			if outputPath != "" {
+				fmt.Fprintf(os.Stderr, "Wrote %d lifecycle rules to %s\n", lifecycle.Rules, outputPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to suggest rules for (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only analyze, and apply the rules to, the objects under this prefix")
+	cmd.Flags().StringVar(&outputPath, flags.OutputPath, "", "File to write to (omit for stdout)")
+	cmd.Flags().IntVar(&noncurrentDays, flags.NoncurrentDays, 90, "Days to keep replaced object versions, on versioned buckets (0 keeps them)")
+
+	return cmd
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestLifecycleSuggestCmd(t *testing.T) {
+	mock := &cmdMockStorage{
+		bucket: storage.Bucket{Name: "archive", StorageClass: "STANDARD", Versioning: &storage.Versioning{Enabled: true}},
+		objects: storage.ObjectList{Objects: []storage.Object{
+			{Key: "old.tar", Size: 1 << 30, CreatedAt: time.Now().AddDate(-2, 0, 0)},
+		}},
+	}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	path := filepath.Join(t.TempDir(), "lifecycle.json")
+	cmd := newLifecycleSuggestCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "archive", "--output-path", path})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Rule []json.RawMessage `json:"rule"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid lifecycle file: %v\n%s", err, data)
+	}
+	if len(doc.Rule) != 2 {
+		t.Errorf("expected an ARCHIVE transition and a noncurrent deletion, got:\n%s", data)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newMetadataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metadata",
+		Short: "Export and re-apply the headers and metadata of many objects",
+		Long: `Captures the content headers (Content-Type, Content-Encoding, Content-Language,
+Cache-Control, Content-Disposition) and user-defined metadata of the objects under a prefix
+to a JSON Lines file, and applies such a file back. Edit the file, or keep it from before
+a bad upload, to fix the headers of many objects at once, e.g. a static site uploaded with
+the wrong Content-Type. Each line describes one object:
+
+  {"provider":"gcp","bucket":"site","key":"index.html","content_type":"text/html","cache_control":"no-cache","metadata":{"owner":"web"}}`,
+	}
+	cmd.AddCommand(newMetadataExportCmd(), newMetadataApplyCmd())
+	return cmd
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/metadata"
+	"github.com/jkleinne/synkronus/internal/output"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newMetadataApplyCmd() *cobra.Command {
+	var filePath string
+	var parallel int
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Set the headers and metadata of objects from a JSON Lines file",
+		Long: `Sets the content headers and user-defined metadata of each object in a file written by
+'storage metadata export', --parallel at a time, and reports the outcome for every line.
+Each object ends up with exactly the headers and metadata of its line: headers left out are
+removed, as are metadata keys not listed. The whole file is checked before anything is
+applied, and a failed line does not stop the others.
+
+S3 metadata cannot be changed in place, so S3 objects are copied onto themselves (up to
+5 GB), which creates a new version in a versioned bucket.`,
+		Example: `  synkronus storage metadata apply -f site.jsonl
+  synkronus storage metadata apply -f site.jsonl --parallel 32 --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if parallel <= 0 {
+				return &usageError{err: fmt.Errorf("--%s must be positive, got %d", flags.Parallel, parallel)}
+			}
+
+			f, err := os.Open(filePath)
+			if err != nil {
+				return &usageError{err: fmt.Errorf("failed to open metadata file: %w", err)}
+			}
+			records, err := metadata.Parse(f)
+			f.Close()
+			if err != nil {
+				return &usageError{err: err}
+			}
+
+			providers := make(map[string]bool)
+			for _, r := range records {
+				providers[r.Provider] = true
+			}
+			for _, provider := range slices.Sorted(maps.Keys(providers)) {
+				if err := app.StorageService.CheckProvider(cmd.Context(), provider); err != nil {
+					return err
+				}
+			}
+
+			if app.DryRun {
+				return output.Render(os.Stdout, app.OutputFormat, output.DryRunView{
+					Operation: "apply-metadata",
+					Provider:  strings.Join(slices.Sorted(maps.Keys(providers)), ","),
+					Parameters: map[string]string{
+						"file":     filePath,
+						"objects":  strconv.Itoa(len(records)),
+						"parallel": strconv.Itoa(parallel),
+					},
+				})
+			}
+
+			report, applyErr := metadata.Apply(cmd.Context(), app.StorageService, records, parallel)
+			if err := output.Render(os.Stdout, app.OutputFormat, output.MetadataApplyView(report)); err != nil {
+				return errors.Join(applyErr, err)
+			}
+			return applyErr
+		},
+	}
+
+	cmd.Flags().StringVarP(&filePath, flags.MetadataFile, flags.MetadataFileShort, "", "The JSON Lines file of objects and their headers and metadata (required)")
+	cmd.MarkFlagRequired(flags.MetadataFile)
+	cmd.Flags().IntVar(&parallel, flags.Parallel, 8, "Number of objects to update at once")
+
+	return cmd
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/metadata"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newMetadataExportCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var outputPath string
+	var parallel int
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the headers and metadata of objects to a JSON Lines file",
+		Long: `Writes one JSON line per object under --prefix, with its content headers and user-defined
+metadata, to stdout or --output-path. Each object is described, --parallel at a time, since
+listings do not carry the headers on every provider.`,
+		Example: `  synkronus storage metadata export --provider gcp --bucket site --prefix assets/ > site.jsonl
+  synkronus storage metadata export -p aws -b site --output-path site.jsonl`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if parallel <= 0 {
+				return &usageError{err: fmt.Errorf("--%s must be positive, got %d", flags.Parallel, parallel)}
+			}
+
+			var w io.Writer = os.Stdout
+			var f *os.File
+			if outputPath != "" {
+				if f, err = os.Create(outputPath); err != nil {
+					return fmt.Errorf("failed to write metadata file: %w", err)
+				}
+				w = f
+			}
+			n, err := metadata.Export(cmd.Context(), app.StorageService, provider, bucket, prefix, parallel, w)
+			if f != nil {
+				err = errors.Join(err, f.Close())
+			}
+			if err != nil {
+				return err
+			}
+			if outputPath != "" {
+				fmt.Fprintf(os.Stderr, "Exported the metadata of %d objects to %s\n", n, outputPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket whose objects are exported (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Export only the objects under this prefix")
+	cmd.Flags().StringVar(&outputPath, flags.OutputPath, "", "File to write to (omit for stdout)")
+	cmd.Flags().IntVar(&parallel, flags.Parallel, 8, "Number of objects to describe at once")
+
+	return cmd
+}
@@ -6,7 +6,11 @@ func newObjectsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "objects",
 		Short: "Manage storage objects",
-		Long:  `List, describe, download, and manage objects within storage buckets.`,
+		Long: `List, describe, download, and manage objects within storage buckets.
+
+Buckets can be referred to by alias (e.g., 'synkronus config set aliases.data-lake gs://my-data-lake').
+An alias works in --bucket, and object keys may be written as 'data-lake/path/file' instead of using --bucket.
+The provider is inferred from the gs:// or s3:// scheme.`,
 	}
 
 	cmd.AddCommand(
@@ -16,6 +20,8 @@ func newObjectsCmd() *cobra.Command {
 		newUploadObjectCmd(),
 		newDeleteObjectCmd(),
 		newCopyObjectCmd(),
+		newSignURLCmd(),
+		newVerifyObjectCmd(),
 	)
 	return cmd
 }
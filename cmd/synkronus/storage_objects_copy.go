@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/flags"
 
 	"github.com/spf13/cobra"
 )
@@ -31,6 +31,15 @@ If --dest-key is omitted, the source key is reused. Same-bucket copy is supporte
 				destKey = srcKey
 			}
 
+			if app.DryRun {
+				return renderDryRun(cmd.Context(), app, "copy-object", provider, map[string]string{
+					"bucket":      bucket,
+					"key":         srcKey,
+					"dest_bucket": destBucket,
+					"dest_key":    destKey,
+				})
+			}
+
 			if err := app.StorageService.CopyObject(cmd.Context(), bucket, srcKey, destBucket, destKey, provider); err != nil {
 				return err
 			}
@@ -45,6 +54,7 @@ If --dest-key is omitted, the source key is reused. Same-bucket copy is supporte
 	cmd.MarkFlagRequired(flags.Provider)
 	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The source bucket (required)")
 	cmd.MarkFlagRequired(flags.Bucket)
+	markObjectRefArg(cmd)
 	cmd.Flags().StringVar(&destBucket, flags.DestBucket, "", "The destination bucket (required)")
 	cmd.MarkFlagRequired(flags.DestBucket)
 	cmd.Flags().StringVar(&destKey, flags.DestKey, "", "Destination object key (defaults to source key)")
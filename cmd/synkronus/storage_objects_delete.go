@@ -2,8 +2,9 @@ package main
 
 import (
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/ui/prompt"
 	"strings"
-	"synkronus/internal/flags"
 
 	"github.com/spf13/cobra"
 )
@@ -17,7 +18,10 @@ func newDeleteObjectCmd() *cobra.Command {
 		Use:   "delete [object-key]",
 		Short: "Delete a storage object",
 		Long: `Deletes an object from a storage bucket. This operation is destructive.
-Confirmation is required by typing the object key, unless the --force flag is used.`,
+Confirmation is required by answering y to a prompt, unless --force (or the global --yes) is given.
+Set defaults.confirm_modes.delete-object to "typed" to require typing the object key instead.
+When stdin is not a terminal (e.g., in CI), the command fails rather than prompt. Set
+defaults.confirm_timeout (e.g., 2m) to abort the deletion if the prompt goes unanswered.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
@@ -26,11 +30,16 @@ Confirmation is required by typing the object key, unless the --force flag is us
 			}
 
 			objectKey := args[0]
+			if app.DryRun {
+				return renderDryRun(cmd.Context(), app, "delete-object", provider, map[string]string{"bucket": bucket, "key": objectKey})
+			}
+
 			warningMessage := fmt.Sprintf(
 				"\nWARNING: You are about to delete object '%s' from bucket '%s' (%s).\nThis action cannot be undone.",
 				objectKey, bucket, strings.ToUpper(provider))
 
-			return confirmThenRun(app.Prompter, warningMessage, objectKey, force, func() error {
+			c := confirmationFor(app.Config, confirmDeleteObject, prompt.ModeYesNo)
+			return confirmThenRun(cmd.Context(), app.Prompter, c, warningMessage, objectKey, force, func() error {
 				if err := app.StorageService.DeleteObject(cmd.Context(), bucket, objectKey, provider); err != nil {
 					return err
 				}
@@ -44,7 +53,9 @@ Confirmation is required by typing the object key, unless the --force flag is us
 	cmd.MarkFlagRequired(flags.Provider)
 	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket containing the object (required)")
 	cmd.MarkFlagRequired(flags.Bucket)
+	markObjectRefArg(cmd)
 	cmd.Flags().BoolVarP(&force, flags.Force, flags.ForceShort, false, "Bypass interactive confirmation prompt")
+	markConfirmFlag(cmd)
 
 	return cmd
 }
@@ -6,7 +6,7 @@ import (
 	"errors"
 	"testing"
 
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 // --- delete-object tests ---
@@ -26,8 +26,11 @@ func TestDeleteObjectCmd_ForceFlag_SkipsConfirmationAndSucceeds(t *testing.T) {
 	if err := cmd.Execute(); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if err := app.StorageService.Shutdown(); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
 	if !mock.closeCalled {
-		t.Error("expected provider client Close to be called after delete")
+		t.Error("expected provider client Close to be called on shutdown")
 	}
 }
 
@@ -1,9 +1,9 @@
 package main
 
 import (
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
 	"os"
-	"synkronus/internal/flags"
-	"synkronus/internal/output"
 
 	"github.com/spf13/cobra"
 )
@@ -37,6 +37,7 @@ func newDescribeObjectCmd() *cobra.Command {
 	cmd.MarkFlagRequired(flags.Provider)
 	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket containing the object (required)")
 	cmd.MarkFlagRequired(flags.Bucket)
+	markObjectRefArg(cmd)
 
 	return cmd
 }
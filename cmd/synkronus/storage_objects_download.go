@@ -2,12 +2,12 @@ package main
 
 import (
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
-	"synkronus/internal/flags"
-	"synkronus/internal/provider/storage/shared"
 
 	"github.com/spf13/cobra"
 )
@@ -57,6 +57,7 @@ func newDownloadObjectCmd() *cobra.Command {
 	cmd.MarkFlagRequired(flags.Provider)
 	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket containing the object (required)")
 	cmd.MarkFlagRequired(flags.Bucket)
+	markObjectRefArg(cmd)
 	cmd.Flags().StringVar(&outputPath, flags.OutputPath, "", "File or directory path to write to (omit for stdout)")
 
 	return cmd
@@ -7,7 +7,7 @@ import (
 	"strings"
 	"testing"
 
-	"synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
 )
 
 func TestObjectBasename(t *testing.T) {
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
 	"os"
-	"synkronus/internal/flags"
-	"synkronus/internal/output"
+	"slices"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -12,24 +18,59 @@ func newListObjectsCmd() *cobra.Command {
 	var provider string
 	var bucket string
 	var prefix string
+	var recursive bool
+	var summary bool
+	var watch time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List objects within a storage bucket",
 		Long: `Lists objects (files) and common prefixes (directories) within a specified bucket.
-Requires the --bucket and --provider flags. Use --prefix to filter the results (e.g., list contents of a specific directory).`,
+Requires the --bucket and --provider flags. Use --prefix to filter the results (e.g., list contents of a specific directory).
+Use --recursive to list every object under the prefix; results are written as they arrive, so buckets
+of any size can be listed.
+Use --watch to refresh the listing periodically and see which objects were added or removed,
+e.g. while a lifecycle purge runs.
+Use --summary to follow the listing with the number of objects, their total size and a
+breakdown by storage class; "directories" are not counted. With --recursive, the summary
+is only shown in table output.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
 				return err
 			}
 
+			if summary && recursive && app.OutputFormat != output.FormatTable {
+				return &usageError{err: fmt.Errorf("--%s with --%s requires table output, since the objects are streamed", flags.Summary, flags.Recursive)}
+			}
+
+			if cmd.Flags().Changed(flags.Watch) {
+				if recursive {
+					return &usageError{err: fmt.Errorf("--%s cannot be combined with --%s", flags.Watch, flags.Recursive)}
+				}
+				return watchListing(cmd.Context(), app, watch, cmd.CommandPath(), func(ctx context.Context) (watchSnapshot, error) {
+					objectList, err := app.StorageService.ListObjects(ctx, bucket, provider, prefix)
+					if err != nil {
+						return watchSnapshot{}, err
+					}
+					keys := slices.Clone(objectList.CommonPrefixes)
+					for _, obj := range objectList.Objects {
+						keys = append(keys, obj.Key)
+					}
+					return watchSnapshot{view: objectListView(objectList, summary), keys: keys}, nil
+				})
+			}
+
+			if recursive {
+				return streamObjects(cmd.Context(), app, bucket, provider, prefix, summary)
+			}
+
 			objectList, err := app.StorageService.ListObjects(cmd.Context(), bucket, provider, prefix)
 			if err != nil {
 				return err
 			}
 
-			return output.Render(os.Stdout, app.OutputFormat, output.ObjectListView{ObjectList: objectList})
+			return output.Render(os.Stdout, app.OutputFormat, objectListView(objectList, summary))
 		},
 	}
 	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
@@ -37,6 +78,33 @@ Requires the --bucket and --provider flags. Use --prefix to filter the results (
 	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket to list objects from (required)")
 	cmd.MarkFlagRequired(flags.Bucket)
 	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Filter results to objects beginning with this prefix (optional)")
+	cmd.Flags().BoolVarP(&recursive, flags.Recursive, flags.RecursiveShort, false, "List all objects under the prefix, streaming results as they arrive")
+	cmd.Flags().BoolVar(&summary, flags.Summary, false, "Follow the listing with the object count, total size and a breakdown by storage class")
+	addWatchFlag(cmd, &watch)
 
 	return cmd
 }
+
+// objectListView returns the view of an object listing, with its summary if asked for.
+func objectListView(objectList storage.ObjectList, summary bool) output.TableRenderer {
+	view := output.ObjectListView{ObjectList: objectList}
+	if summary {
+		return output.ObjectListSummaryView{ObjectListView: view, Summary: output.SummarizeObjects(objectList.Objects)}
+	}
+	return view
+}
+
+// streamObjects writes every object under prefix to stdout as pages arrive,
+// keeping memory bounded for buckets with millions of objects.
+func streamObjects(ctx context.Context, app *appContainer, bucket, provider, prefix string, summary bool) error {
+	stream, err := output.NewObjectStream(os.Stdout, app.OutputFormat)
+	if err != nil {
+		return err
+	}
+	if summary {
+		stream.ShowSummary()
+	}
+	walkErr := app.StorageService.WalkObjects(ctx, bucket, provider, prefix, stream.Write)
+	// Close even after a failed walk, so the objects already listed are shown
+	return errors.Join(walkErr, stream.Close())
+}
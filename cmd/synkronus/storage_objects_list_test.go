@@ -3,10 +3,12 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"testing"
 
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/output"
 )
 
 // --- list-objects tests ---
@@ -38,6 +40,52 @@ func TestListObjectsCmd_HappyPath_ReturnsObjects(t *testing.T) {
 	// successful execution rather than captured output content.
 }
 
+func TestListObjectsCmd_Recursive_StreamsObjects(t *testing.T) {
+	mock := &cmdMockStorage{objects: storage.ObjectList{
+		BucketName: "my-bucket",
+		Objects:    []storage.Object{{Key: "data/export.json", Bucket: "my-bucket", Provider: domain.GCP}},
+	}}
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}
+	app := newStorageTestApp(factory, nil)
+
+	var buf bytes.Buffer
+	cmd := newListObjectsCmd()
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--recursive"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestListObjectsCmd_Summary(t *testing.T) {
+	mock := &cmdMockStorage{objects: storage.ObjectList{
+		BucketName: "my-bucket",
+		Objects:    []storage.Object{{Key: "data/export.json", Bucket: "my-bucket", Provider: domain.GCP, Size: 10}},
+	}}
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}
+	app := newStorageTestApp(factory, nil)
+
+	cmd := newListObjectsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--summary"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A streamed listing has no room for the summary in JSON
+	app.OutputFormat = output.FormatJSON
+	cmd = newListObjectsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "my-bucket", "--summary", "--recursive"})
+	var usage *usageError
+	if err := cmd.Execute(); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
+
 func TestListObjectsCmd_MissingProviderFlag_ReturnsError(t *testing.T) {
 	app := newStorageTestApp(&cmdStorageFactory{}, nil)
 
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// signableMethods are the requests a URL can be signed for
+var signableMethods = []string{http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete}
+
+func newSignURLCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var method string
+	var expires time.Duration
+	var headers map[string]string
+	var disposition string
+	var style string
+
+	cmd := &cobra.Command{
+		Use:   "sign-url [object-key]",
+		Short: "Sign a URL that grants temporary access to an object",
+		Long: `Signs a URL that lets anyone holding it make one kind of request on the object (--method,
+GET by default) until it expires (--expires, at most 7 days), without credentials.
+
+--header signs a header into the URL, so the request must send it with that value (e.g.,
+the Content-Type of an upload). --response-content-disposition makes a download carry the
+given Content-Disposition, e.g. to save it under another name. --style picks whether the
+bucket goes in the path or in the host name (virtual-hosted); by default GCS URLs are
+path-style and S3 URLs virtual-hosted.
+
+GCP signs with a service account key from the credentials, or with the IAM signBlob API of
+the account synkronus runs as. When impersonating a service account
+(--impersonate-service-account or gcp.impersonate_service_account), the URL is signed as
+that account through signBlob, so no private key is needed locally. AWS signs with the
+current credentials; a URL signed with temporary credentials stops working when they expire.`,
+		Example: `  synkronus storage objects sign-url reports/q1.pdf --provider gcp --bucket finance --expires 24h
+  synkronus storage objects sign-url reports/q1.pdf -p aws -b finance --response-content-disposition 'attachment; filename="q1.pdf"'
+  synkronus storage objects sign-url incoming/data.json -p gcp -b uploads --method PUT --header Content-Type=application/json
+  synkronus storage objects sign-url site/index.html -p gcp -b web --style virtual-hosted --impersonate-service-account signer@my-project.iam.gserviceaccount.com`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			method = strings.ToUpper(method)
+			if !slices.Contains(signableMethods, method) {
+				return &usageError{err: fmt.Errorf("invalid --%s %q (expected %s)", flags.Method, method, strings.Join(signableMethods, ", "))}
+			}
+			if disposition != "" && method != http.MethodGet {
+				return &usageError{err: fmt.Errorf("--%s applies to GET URLs only", flags.ResponseContentDisposition)}
+			}
+			if style != "" && style != storage.URLStylePath && style != storage.URLStyleVirtualHosted {
+				return &usageError{err: fmt.Errorf("invalid --%s %q (expected %s or %s)", flags.URLStyle, style, storage.URLStylePath, storage.URLStyleVirtualHosted)}
+			}
+			if expires <= 0 || expires > storage.MaxSignedExpiry {
+				return &usageError{err: fmt.Errorf("--%s must be between 1s and %s, got %s", flags.Expires, storage.MaxSignedExpiry, expires)}
+			}
+
+			signed, err := app.StorageService.SignURL(cmd.Context(), storage.SignedURLOptions{
+				BucketName:                 bucket,
+				ObjectKey:                  args[0],
+				Method:                     method,
+				Expires:                    expires,
+				Headers:                    headers,
+				ResponseContentDisposition: disposition,
+				Style:                      style,
+			}, provider)
+			if err != nil {
+				return err
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.SignedURLView(signed))
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the object resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket containing the object (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&method, flags.Method, http.MethodGet, "The HTTP method the URL is valid for (GET, PUT, HEAD or DELETE)")
+	cmd.Flags().DurationVar(&expires, flags.Expires, time.Hour, "How long the URL stays valid (at most 168h)")
+	cmd.Flags().StringToStringVar(&headers, flags.Header, nil, "Headers the request must send, as name=value pairs (e.g. --header Content-Type=text/csv)")
+	cmd.Flags().StringVar(&disposition, flags.ResponseContentDisposition, "", "Content-Disposition the download is served with (GET only)")
+	cmd.Flags().StringVar(&style, flags.URLStyle, "", "How the URL addresses the bucket: path or virtual-hosted (defaults to the provider's usual style)")
+	markObjectRefArg(cmd)
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// cmdURLSigningStorage records the options of the URL it signs.
+type cmdURLSigningStorage struct {
+	cmdMockStorage
+	opts storage.SignedURLOptions
+}
+
+func (m *cmdURLSigningStorage) SignURL(ctx context.Context, opts storage.SignedURLOptions) (storage.SignedURL, error) {
+	m.opts = opts
+	return storage.SignedURL{URL: "https://example.com/" + opts.BucketName + "/" + opts.ObjectKey, Method: opts.Method}, nil
+}
+
+func TestSignURLCmd(t *testing.T) {
+	mock := &cmdURLSigningStorage{}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+
+	cmd := newSignURLCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"data.json", "--provider", "gcp", "--bucket", "uploads", "--method", "put",
+		"--header", "Content-Type=application/json", "--style", "virtual-hosted", "--expires", "10m"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.opts.Method != "PUT" || mock.opts.Style != storage.URLStyleVirtualHosted || mock.opts.Expires != 10*time.Minute {
+		t.Errorf("unexpected options %+v", mock.opts)
+	}
+	if mock.opts.Headers["Content-Type"] != "application/json" {
+		t.Errorf("expected the header passed on, got %v", mock.opts.Headers)
+	}
+}
+
+func TestSignURLCmd_InvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"--method", "POST"},
+		{"--style", "subdomain"},
+		{"--expires", "200h"},
+		{"--method", "PUT", "--response-content-disposition", "attachment"},
+	} {
+		app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdURLSigningStorage{}}}, nil)
+		cmd := newSignURLCmd()
+		cmd.SetContext(app.ToContext(context.Background()))
+		cmd.SetArgs(append([]string{"a.txt", "--provider", "gcp", "--bucket", "b"}, args...))
+
+		var usage *usageError
+		if err := cmd.Execute(); !errors.As(err, &usage) {
+			t.Errorf("%v: expected a usage error, got %v", args, err)
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/flags"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -16,12 +23,24 @@ func newUploadObjectCmd() *cobra.Command {
 	var objectKey string
 	var contentType string
 	var metadata map[string]string
+	var checksum string
+	var contentTypeMap map[string]string
+	var templateName string
 
 	cmd := &cobra.Command{
 		Use:   "upload [file]",
 		Short: "Upload a local file as a storage object",
-		Long:  `Uploads a local file to a storage bucket. If --key is omitted, the object key is derived from the filename.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Uploads a local file to a storage bucket. If --key is omitted, the object key is derived from the filename.
+
+Without --content-type, the content type is that of the first --content-type-map pattern matching the key, else the
+one registered for the key's extension, else one sniffed from the file's first bytes.
+
+With --metadata-template, the headers and metadata of a template defined in the config are set on the object, e.g.
+  synkronus config set metadata_templates.web-assets.cache_control "public, max-age=86400"
+--content-type and --metadata take precedence over the template.
+
+With --checksum, the upload fails if the checksum the provider computes does not match the file's.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app, err := appFromContext(cmd.Context())
 			if err != nil {
@@ -30,6 +49,13 @@ func newUploadObjectCmd() *cobra.Command {
 
 			filePath := args[0]
 
+			if checksum != "" {
+				if _, err := storage.NewChecksum(checksum); err != nil {
+					return &usageError{err: err}
+				}
+				checksum = strings.ToUpper(checksum)
+			}
+
 			info, err := os.Stat(filePath)
 			if err != nil {
 				return fmt.Errorf("cannot access file %q: %w", filePath, err)
@@ -42,6 +68,47 @@ func newUploadObjectCmd() *cobra.Command {
 				objectKey = filepath.Base(filePath)
 			}
 
+			var template config.MetadataTemplate
+			if templateName != "" {
+				if template, err = app.Config.MetadataTemplate(templateName); err != nil {
+					return &usageError{err: err}
+				}
+				contentType = cmp.Or(contentType, template.ContentType)
+				metadata = mergeMetadata(template.Metadata, metadata)
+			}
+
+			rules, err := storage.ParseContentTypeRules(contentTypeMap)
+			if err != nil {
+				return &usageError{err: err}
+			}
+			if contentType == "" {
+				if contentType, err = detectFileContentType(filePath, objectKey, rules); err != nil {
+					return err
+				}
+			}
+
+			if app.DryRun {
+				params := map[string]string{
+					"file":       filePath,
+					"size_bytes": strconv.FormatInt(info.Size(), 10),
+					"bucket":     bucket,
+					"key":        objectKey,
+				}
+				if contentType != "" {
+					params["content_type"] = contentType
+				}
+				if len(metadata) > 0 {
+					params["metadata"] = formatPairs(metadata)
+				}
+				if checksum != "" {
+					params["checksum"] = checksum
+				}
+				if templateName != "" {
+					params["metadata_template"] = templateName
+				}
+				return renderDryRun(cmd.Context(), app, "upload-object", provider, params)
+			}
+
 			f, err := os.Open(filePath)
 			if err != nil {
 				return fmt.Errorf("opening file %q: %w", filePath, err)
@@ -49,10 +116,15 @@ func newUploadObjectCmd() *cobra.Command {
 			defer f.Close()
 
 			opts := storage.UploadObjectOptions{
-				BucketName:  bucket,
-				ObjectKey:   objectKey,
-				ContentType: contentType,
-				Metadata:    metadata,
+				BucketName:         bucket,
+				ObjectKey:          objectKey,
+				ContentType:        contentType,
+				Metadata:           metadata,
+				CacheControl:       template.CacheControl,
+				ContentDisposition: template.ContentDisposition,
+				ContentEncoding:    template.ContentEncoding,
+				ContentLanguage:    template.ContentLanguage,
+				ChecksumAlgorithm:  checksum,
 			}
 
 			if err := app.StorageService.UploadObject(cmd.Context(), opts, provider, f); err != nil {
@@ -70,7 +142,44 @@ func newUploadObjectCmd() *cobra.Command {
 	cmd.MarkFlagRequired(flags.Bucket)
 	cmd.Flags().StringVar(&objectKey, flags.ObjectKey, "", "Object key (defaults to filename if omitted)")
 	cmd.Flags().StringVar(&contentType, flags.ContentType, "", "Content-Type MIME type (auto-detected if omitted)")
+	cmd.Flags().StringVar(&templateName, flags.MetadataTemplate, "", "Apply the headers and metadata of a metadata template from the config (metadata_templates.<name>)")
+	cmd.Flags().StringToStringVar(&contentTypeMap, flags.ContentTypeMap, nil, "Content types by key pattern, e.g. '*.wasm=application/wasm' (overrides detection)")
 	cmd.Flags().StringToStringVar(&metadata, flags.Metadata, nil, "User-defined metadata as key=value pairs")
+	cmd.Flags().StringVar(&checksum, flags.Checksum, "", "Have the provider verify the upload with this checksum (SHA256, CRC32 or CRC32C on AWS; CRC32C or MD5 on GCP)")
 
 	return cmd
 }
+
+// detectFileContentType returns the content type of a file uploaded as key:
+// that of the rule matching key, else the one detected from key and the
+// file's leading bytes.
+func detectFileContentType(filePath, key string, rules storage.ContentTypeRules) (string, error) {
+	if contentType := rules.Match(key); contentType != "" {
+		return contentType, nil
+	}
+	if contentType := storage.DetectContentType(key, nil); contentType != "" {
+		return contentType, nil
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("opening file %q: %w", filePath, err)
+	}
+	defer f.Close()
+	head := make([]byte, storage.SniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("reading file %q: %w", filePath, err)
+	}
+	return storage.DetectContentType(key, head[:n]), nil
+}
+
+// mergeMetadata returns the keys of base overridden by those of overrides.
+func mergeMetadata(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 {
+		return overrides
+	}
+	merged := maps.Clone(base)
+	maps.Copy(merged, overrides)
+	return merged
+}
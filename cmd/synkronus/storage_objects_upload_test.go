@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 func TestUploadObjectCmd_HappyPath(t *testing.T) {
@@ -156,3 +158,124 @@ func TestUploadObjectCmd_ServiceError_ReturnsError(t *testing.T) {
 		t.Errorf("expected service error in chain, got: %v", err)
 	}
 }
+
+func TestUploadObjectCmd_DryRun_DoesNotUpload(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(tmpFile, []byte("col1,col2\n"), 0600); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	mock := &cmdMockStorage{err: errors.New("upload should not be called")}
+	factory := &cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}
+	app := newStorageTestApp(factory, nil)
+	app.DryRun = true
+
+	cmd := newUploadObjectCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{tmpFile, "--provider", "gcp", "--bucket", "my-bucket", "--metadata", "team=data"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// cmdUploadRecordingStorage records the options of the last upload.
+type cmdUploadRecordingStorage struct {
+	cmdMockStorage
+	opts storage.UploadObjectOptions
+}
+
+func (m *cmdUploadRecordingStorage) UploadObject(_ context.Context, opts storage.UploadObjectOptions, r io.Reader) error {
+	m.opts = opts
+	_, err := io.Copy(io.Discard, r)
+	return err
+}
+
+func TestUploadObjectCmd_ContentType(t *testing.T) {
+	dir := t.TempDir()
+	wasm := filepath.Join(dir, "app.wasm")
+	page := filepath.Join(dir, "index")
+	if err := os.WriteFile(wasm, []byte("\x00asm"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(page, []byte("<!DOCTYPE html><html></html>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"mapped", []string{wasm, "--content-type-map", "*.wasm=application/wasm"}, "application/wasm"},
+		{"explicit wins", []string{wasm, "--content-type-map", "*.wasm=application/wasm", "--content-type", "application/octet-stream"}, "application/octet-stream"},
+		{"sniffed", []string{page}, "text/html; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &cmdUploadRecordingStorage{}
+			app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+
+			cmd := newUploadObjectCmd()
+			cmd.SetContext(app.ToContext(context.Background()))
+			cmd.SetArgs(append(tt.args, "--provider", "gcp", "--bucket", "my-bucket"))
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mock.opts.ContentType != tt.want {
+				t.Errorf("content type = %q, want %q", mock.opts.ContentType, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadObjectCmd_InvalidContentTypeMap(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(tmpFile, []byte("a"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdMockStorage{}}}, nil)
+
+	cmd := newUploadObjectCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{tmpFile, "--provider", "gcp", "--bucket", "b", "--content-type-map", "[x=text/plain"})
+
+	var usage *usageError
+	if err := cmd.Execute(); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
+
+func TestUploadObjectCmd_MetadataTemplate(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(tmpFile, []byte("png"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mock := &cmdUploadRecordingStorage{}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	app.Config = &config.Config{MetadataTemplates: map[string]config.MetadataTemplate{
+		"web-assets": {CacheControl: "public, max-age=86400", Metadata: map[string]string{"team": "web", "tier": "static"}},
+	}}
+
+	cmd := newUploadObjectCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{tmpFile, "--provider", "gcp", "--bucket", "b", "--metadata-template", "web-assets", "--metadata", "tier=cdn"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.opts.CacheControl != "public, max-age=86400" {
+		t.Errorf("cache control = %q, want the template's", mock.opts.CacheControl)
+	}
+	if mock.opts.Metadata["team"] != "web" || mock.opts.Metadata["tier"] != "cdn" {
+		t.Errorf("expected the template metadata with --metadata taking precedence, got %v", mock.opts.Metadata)
+	}
+
+	cmd = newUploadObjectCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{tmpFile, "--provider", "gcp", "--bucket", "b", "--metadata-template", "images"})
+	var usage *usageError
+	if err := cmd.Execute(); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error for an unknown template, got %v", err)
+	}
+}
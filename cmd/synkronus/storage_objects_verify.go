@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newVerifyObjectCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var filePath string
+
+	cmd := &cobra.Command{
+		Use:   "verify [object-key]",
+		Short: "Verify a storage object against a local file by checksum",
+		Long: `Compares a storage object with a local file, using the strongest checksum the object reports
+(SHA-256, CRC32C, CRC32 or MD5). The command fails if the checksums differ.
+
+Composite checksums of S3 multipart uploads are recomputed over parts of the size the object was uploaded with.
+Uploads to S3 carry a CRC32 checksum unless --checksum picks another one. Objects uploaded by older
+clients that sent no checksum have none to verify against.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			objectKey := args[0]
+
+			f, err := os.Open(filePath)
+			if err != nil {
+				return fmt.Errorf("opening file %q: %w", filePath, err)
+			}
+			defer f.Close()
+
+			result, err := app.StorageService.VerifyObject(cmd.Context(), bucket, objectKey, provider, f)
+			if err != nil {
+				return err
+			}
+
+			if err := output.Render(os.Stdout, app.OutputFormat, output.ObjectVerificationView(result)); err != nil {
+				return err
+			}
+			if !result.Match {
+				return fmt.Errorf("object %q does not match %s", objectKey, filePath)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the object resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket containing the object (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&filePath, flags.File, "", "The local file to compare the object with (required)")
+	cmd.MarkFlagRequired(flags.File)
+	markObjectRefArg(cmd)
+
+	return cmd
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestVerifyObjectCmd(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(tmpFile, []byte("hello"), 0600); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		sha256  string
+		wantErr bool
+	}{
+		{"match", "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=", false},
+		{"mismatch", "AAAA", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &cmdMockStorage{object: storage.Object{Key: "hello.txt", SHA256: tt.sha256}}
+			app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+
+			cmd := newVerifyObjectCmd()
+			cmd.SetContext(app.ToContext(context.Background()))
+			cmd.SetArgs([]string{"hello.txt", "--provider", "gcp", "--bucket", "b", "--file", tmpFile})
+
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
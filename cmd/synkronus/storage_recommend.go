@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"github.com/jkleinne/synkronus/internal/pricing"
+	"github.com/jkleinne/synkronus/internal/recommend"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newRecommendCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+
+	cmd := &cobra.Command{
+		Use:   "recommend",
+		Short: "Suggest cheaper storage classes for a bucket's objects",
+		Long: `Groups the objects under --prefix by age and suggests lifecycle transitions to colder storage
+classes, or Autoclass (GCP) or Intelligent-Tiering (AWS), with the monthly savings each would
+bring at the list prices of the bucket's region, from the pricing dataset (see 'synkronus
+pricing update').
+
+Where the provider reports them (GCP, from Cloud Monitoring), the bucket's requests over the
+last 30 days are weighed in: colder classes charge for each read, so a busy bucket may save less
+than estimated. Savings are estimates for comparison, not quotes: retrieval and early
+deletion fees are not counted.`,
+		Example: `  synkronus storage recommend --provider gcp --bucket archive
+  synkronus storage recommend -p aws -b logs --prefix 2023/ -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			prices, err := pricing.Load()
+			if err != nil {
+				return err
+			}
+			report, err := recommend.Analyze(cmd.Context(), app.StorageService, prices, provider, bucket, prefix)
+			if err != nil {
+				return err
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.RecommendationView(report))
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to analyze (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only analyze the objects under this prefix")
+
+	return cmd
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// cmdAccessStorage reports a request count for every bucket.
+type cmdAccessStorage struct {
+	cmdMockStorage
+	windows []time.Duration
+}
+
+func (m *cmdAccessStorage) BucketAccess(ctx context.Context, bucketName string, window time.Duration) (storage.AccessStats, error) {
+	m.windows = append(m.windows, window)
+	return storage.AccessStats{Window: window, Requests: 3}, nil
+}
+
+func TestRecommendCmd(t *testing.T) {
+	mock := &cmdAccessStorage{cmdMockStorage: cmdMockStorage{
+		bucket: storage.Bucket{Name: "archive", StorageClass: "STANDARD"},
+		objects: storage.ObjectList{Objects: []storage.Object{
+			{Key: "old.tar", Size: 1 << 30, CreatedAt: time.Now().AddDate(-2, 0, 0)},
+		}},
+	}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	cmd := newRecommendCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "archive"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.windows) != 1 || mock.windows[0] != 30*24*time.Hour {
+		t.Errorf("expected the access of the last 30 days asked for, got %v", mock.windows)
+	}
+}
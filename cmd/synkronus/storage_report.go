@@ -0,0 +1,16 @@
+package main
+
+import "github.com/spf13/cobra"
+
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarize what buckets hold and how they are configured",
+	}
+	cmd.AddCommand(
+		newReportPrefixesCmd(),
+		newReportKMSCmd(),
+		newReportRetentionCmd(),
+	)
+	return cmd
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newReportKMSCmd() *cobra.Command {
+	var providersList []string
+
+	cmd := &cobra.Command{
+		Use:   "kms",
+		Short: "List the KMS keys buckets are encrypted with, across providers",
+		Long: `Describes every bucket of the configured providers (or --providers) and lists each
+customer-managed key in use (Cloud KMS keys of CMEK buckets, SSE-KMS keys of S3 buckets)
+with the buckets encrypted with it by default, for key rotation and compliance reviews.
+
+Buckets encrypted with keys the provider manages (Google-managed encryption, SSE-S3, or
+SSE-KMS with the aws/s3 key) are listed separately. Buckets that cannot be described
+are reported as warnings on stderr.`,
+		Example: `  synkronus storage report kms
+  synkronus storage report kms --providers aws -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			providers, err := storageProviderResolver(app).Resolve(providersList)
+			if err != nil {
+				return err
+			}
+
+			buckets, describeErr := inventory.Describe(cmd.Context(), app.StorageService, providers)
+			if describeErr != nil && len(buckets) == 0 {
+				return describeErr
+			}
+			if err := output.Render(os.Stdout, app.OutputFormat, output.KMSReportView(inventory.KMSKeys(buckets))); err != nil {
+				return err
+			}
+			app.PartialResults = describeErr != nil
+			return renderInventoryWarnings(os.Stderr, describeErr)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&providersList, flags.Providers, flags.ProvidersShort, nil, "Specify providers to report on (comma-separated). Defaults to all configured providers.")
+
+	return cmd
+}
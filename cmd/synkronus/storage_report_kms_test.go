@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestReportKMSCmd(t *testing.T) {
+	mock := &cmdMockStorage{
+		buckets: []storage.Bucket{{Name: "data", Provider: domain.GCP}},
+		bucket:  storage.Bucket{Name: "data", Provider: domain.GCP, Encryption: &storage.Encryption{KmsKeyName: "projects/p/keyRings/r/cryptoKeys/k"}},
+	}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	cmd := newReportKMSCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.PartialResults {
+		t.Error("expected a complete report")
+	}
+
+	cmd = newReportKMSCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--providers", "nope"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an unsupported provider")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newReportPrefixesCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var depth int
+
+	cmd := &cobra.Command{
+		Use:   "prefixes",
+		Short: "Show the size and object count of each prefix of a bucket",
+		Long: `Walks the objects under --prefix and sums their sizes and counts by the prefixes --depth
+levels below it, largest first, to show which datasets take up a bucket. With --depth 2,
+raw/2024/a.json counts towards raw/2024/. Objects shallower than --depth count towards the
+deepest prefix they are in; those directly under --prefix are shown as (top level).`,
+		Example: `  synkronus storage report prefixes --provider gcp --bucket lake
+  synkronus storage report prefixes -p aws -b lake --prefix raw/ --depth 2 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if depth <= 0 {
+				return &usageError{err: fmt.Errorf("--%s must be positive, got %d", flags.Depth, depth)}
+			}
+
+			report, err := app.StorageService.PrefixUsage(cmd.Context(), bucket, provider, prefix, depth)
+			if err != nil {
+				return err
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.PrefixUsageView(report))
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket to report on (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "Only report on the objects under this prefix")
+	cmd.Flags().IntVar(&depth, flags.Depth, 1, "Number of prefix levels to break the usage down by")
+
+	return cmd
+}
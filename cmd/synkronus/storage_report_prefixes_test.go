@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestReportPrefixesCmd(t *testing.T) {
+	mock := &cmdMockStorage{objects: storage.ObjectList{Objects: []storage.Object{
+		{Key: "raw/a.json", Size: 10},
+		{Key: "curated/b.csv", Size: 20},
+	}}}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+	cmd := newReportPrefixesCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "lake"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd = newReportPrefixesCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "lake", "--depth", "0"})
+	var usage *usageError
+	if err := cmd.Execute(); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error for --depth 0, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newReportRetentionCmd() *cobra.Command {
+	var providersList []string
+
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "List the buckets that retain their objects (WORM), across providers",
+		Long: `Describes every bucket of the configured providers (or --providers) and lists those whose
+objects cannot be deleted or overwritten for a minimum period: Cloud Storage buckets with a
+retention policy, and S3 buckets with an Object Lock default retention. For each, the period,
+whether it is locked, and when an object written now could first be deleted are shown.
+
+A locked retention policy, or Object Lock in COMPLIANCE mode, can no longer be shortened or
+removed. An unlocked policy, or GOVERNANCE mode, is pending: its window can still be lifted, so
+it is not WORM coverage a compliance review can rely on.
+
+Buckets without retention are listed separately. Buckets that cannot be described are
+reported as warnings on stderr.`,
+		Example: `  synkronus storage report retention
+  synkronus storage report retention --providers aws -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			providers, err := storageProviderResolver(app).Resolve(providersList)
+			if err != nil {
+				return err
+			}
+
+			buckets, describeErr := inventory.Describe(cmd.Context(), app.StorageService, providers)
+			if describeErr != nil && len(buckets) == 0 {
+				return describeErr
+			}
+			report := inventory.Retention(buckets, time.Now().UTC())
+			if err := output.Render(os.Stdout, app.OutputFormat, output.RetentionReportView(report)); err != nil {
+				return err
+			}
+			app.PartialResults = describeErr != nil
+			return renderInventoryWarnings(os.Stderr, describeErr)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&providersList, flags.Providers, flags.ProvidersShort, nil, "Specify providers to report on (comma-separated). Defaults to all configured providers.")
+
+	return cmd
+}
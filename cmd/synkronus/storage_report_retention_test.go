@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestReportRetentionCmd(t *testing.T) {
+	mock := &cmdMockStorage{
+		buckets: []storage.Bucket{{Name: "data", Provider: domain.GCP}},
+		bucket:  storage.Bucket{Name: "data", Provider: domain.GCP, RetentionPolicy: &storage.RetentionPolicy{RetentionPeriod: time.Hour}},
+	}
+	app := newBucketListTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	cmd := newReportRetentionCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.PartialResults {
+		t.Error("expected a complete report")
+	}
+
+	cmd = newReportRetentionCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--providers", "nope"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an unsupported provider")
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newSignPostPolicyCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var prefix string
+	var maxSize string
+	var expires time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "sign-post-policy",
+		Short: "Sign a POST policy that lets browsers upload to a bucket",
+		Long: `Signs a POST policy: a URL and form fields that let a browser (or any HTTP client) upload a
+file to the bucket with a multipart/form-data POST, without credentials of its own. Uploads
+are stored under --prefix, named after the uploaded file, and can be bounded in size with
+--max-size. The policy is valid for --expires, at most 7 days.
+
+GCP signs with a service account: a key file in the credentials, or the IAM signBlob API
+of the account synkronus runs as. AWS signs with the current credentials; a policy signed
+with temporary credentials (SSO, assumed roles) stops working when they expire.`,
+		Example: `  synkronus storage sign-post-policy --provider gcp --bucket uploads --prefix incoming/ --max-size 10MB
+  synkronus storage sign-post-policy -p aws -b uploads --expires 15m -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if expires <= 0 || expires > storage.MaxSignedExpiry {
+				return &usageError{err: fmt.Errorf("--%s must be between 1s and %s, got %s", flags.Expires, storage.MaxSignedExpiry, expires)}
+			}
+			var limit int64
+			if maxSize != "" {
+				if limit, err = storage.ParseBytes(maxSize); err != nil {
+					return &usageError{err: fmt.Errorf("invalid --%s: %w", flags.MaxSize, err)}
+				}
+			}
+
+			policy, err := app.StorageService.SignPostPolicy(cmd.Context(), storage.PostPolicyOptions{
+				BucketName: bucket,
+				Prefix:     prefix,
+				MaxSize:    limit,
+				Expires:    expires,
+			}, provider)
+			if err != nil {
+				return err
+			}
+			return output.Render(os.Stdout, app.OutputFormat, output.PostPolicyView(policy))
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the bucket resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The bucket uploads go to (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&prefix, flags.Prefix, "", "The prefix uploads are stored under (e.g., incoming/)")
+	cmd.Flags().StringVar(&maxSize, flags.MaxSize, "", "The largest upload allowed (e.g., 10MB; omit for no limit)")
+	cmd.Flags().DurationVar(&expires, flags.Expires, time.Hour, "How long the policy stays valid (at most 168h)")
+
+	return cmd
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+)
+
+// cmdSigningStorage records the options of the policy it signs.
+type cmdSigningStorage struct {
+	cmdMockStorage
+	opts storage.PostPolicyOptions
+}
+
+func (m *cmdSigningStorage) SignPostPolicy(ctx context.Context, opts storage.PostPolicyOptions) (storage.PostPolicy, error) {
+	m.opts = opts
+	return storage.PostPolicy{URL: "https://example.com/" + opts.BucketName, Fields: map[string]string{"key": opts.Prefix + "${filename}"}}, nil
+}
+
+func TestSignPostPolicyCmd(t *testing.T) {
+	mock := &cmdSigningStorage{}
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": mock}}, nil)
+
+	cmd := newSignPostPolicyCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "uploads", "--prefix", "incoming/", "--max-size", "10MB", "--expires", "15m"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := storage.PostPolicyOptions{BucketName: "uploads", Prefix: "incoming/", MaxSize: 10 << 20, Expires: 15 * time.Minute}
+	if mock.opts != want {
+		t.Errorf("expected options %+v, got %+v", want, mock.opts)
+	}
+}
+
+func TestSignPostPolicyCmd_InvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"--expires", "200h"},
+		{"--expires", "0s"},
+		{"--max-size", "lots"},
+	} {
+		app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdSigningStorage{}}}, nil)
+		cmd := newSignPostPolicyCmd()
+		cmd.SetContext(app.ToContext(context.Background()))
+		cmd.SetArgs(append([]string{"--provider", "gcp", "--bucket", "uploads"}, args...))
+
+		var usage *usageError
+		if err := cmd.Execute(); !errors.As(err, &usage) {
+			t.Errorf("%v: expected a usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestSignPostPolicyCmd_Unsupported(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{"gcp": &cmdMockStorage{}}}, nil)
+
+	cmd := newSignPostPolicyCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "uploads"})
+
+	if err := cmd.Execute(); !errors.Is(err, service.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Styles of 'storage url'
+const (
+	urlStyleGS          = "gs"
+	urlStyleS3          = "s3"
+	urlStyleHTTPS       = "https"
+	urlStyleVirtualHost = "virtual-host"
+)
+
+var urlStyles = []string{urlStyleGS, urlStyleS3, urlStyleHTTPS, urlStyleVirtualHost}
+
+func newURLCmd() *cobra.Command {
+	var provider string
+	var bucket string
+	var style string
+
+	cmd := &cobra.Command{
+		Use:   "url [object-key]",
+		Short: "Print the URI or URL of an object",
+		Long: `Prints the canonical URI or URL of an object, to paste into configs and tickets. The object
+is not looked up, so the URL is printed whether it exists yet or not.
+
+Styles:
+  gs            gs://bucket/key (GCP buckets)
+  s3            s3://bucket/key (AWS buckets)
+  https         the bucket's HTTPS endpoint, with the bucket in the path
+  virtual-host  the bucket's HTTPS endpoint, with the bucket in the host name
+
+The default is the provider's own URI scheme (gs or s3), else https. HTTPS URLs of S3
+objects name the bucket's region, which is looked up.`,
+		Example: `  synkronus storage url reports/q1.pdf --provider gcp --bucket finance
+  synkronus storage url reports/q1.pdf -p aws -b finance --style virtual-host`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := appFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			key := args[0]
+			scheme, hasScheme := config.BucketScheme(provider)
+			if style == "" {
+				style = urlStyleHTTPS
+				if hasScheme {
+					style = scheme
+				}
+			}
+			if !slices.Contains(urlStyles, style) {
+				return &usageError{err: fmt.Errorf("invalid --%s %q (expected %s)", flags.URLStyle, style, strings.Join(urlStyles, ", "))}
+			}
+
+			view := output.ObjectURLView{Provider: strings.ToLower(provider), Bucket: bucket, Key: key, Style: style}
+			switch style {
+			case urlStyleGS, urlStyleS3:
+				if style != scheme {
+					return &usageError{err: fmt.Errorf("--%s %s does not apply to %s buckets", flags.URLStyle, style, provider)}
+				}
+				view.URL = style + "://" + bucket + "/" + key
+			default:
+				urlStyle := storage.URLStylePath
+				if style == urlStyleVirtualHost {
+					urlStyle = storage.URLStyleVirtualHosted
+				}
+				bucketURL, err := app.StorageService.BucketURL(cmd.Context(), bucket, provider, urlStyle)
+				if err != nil {
+					return err
+				}
+				view.URL = storage.ObjectURL(bucketURL, key)
+			}
+			return output.Render(os.Stdout, app.OutputFormat, view)
+		},
+	}
+
+	cmd.Flags().StringVarP(&provider, flags.Provider, flags.ProviderShort, "", "The provider where the object resides (required)")
+	cmd.MarkFlagRequired(flags.Provider)
+	cmd.Flags().StringVarP(&bucket, flags.Bucket, flags.BucketShort, "", "The name of the bucket containing the object (required)")
+	cmd.MarkFlagRequired(flags.Bucket)
+	cmd.Flags().StringVar(&style, flags.URLStyle, "", "The form of the URL: gs, s3, https or virtual-host (defaults to the provider's URI scheme)")
+	markObjectRefArg(cmd)
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// cmdStyleRecordingStorage records the style of the bucket URL asked for.
+type cmdStyleRecordingStorage struct {
+	cmdMockStorage
+	styles []string
+}
+
+func (m *cmdStyleRecordingStorage) BucketURL(ctx context.Context, bucketName, style string) (string, error) {
+	m.styles = append(m.styles, style)
+	return "https://example.com/" + bucketName, nil
+}
+
+func runURLCmd(t *testing.T, mock storage.Storage, provider string, args ...string) error {
+	t.Helper()
+	app := newStorageTestApp(&cmdStorageFactory{providers: map[string]storage.Storage{provider: mock}}, nil)
+	cmd := newURLCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs(append([]string{"a/b.txt", "--provider", provider, "--bucket", "data"}, args...))
+	return cmd.Execute()
+}
+
+func TestURLCmd_Styles(t *testing.T) {
+	mock := &cmdStyleRecordingStorage{}
+	for _, args := range [][]string{nil, {"--style", "gs"}} {
+		if err := runURLCmd(t, mock, "gcp", args...); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+	}
+	if len(mock.styles) != 0 {
+		t.Errorf("expected gs:// URIs made without the provider, got calls for %v", mock.styles)
+	}
+
+	for _, args := range [][]string{{"--style", "https"}, {"--style", "virtual-host"}} {
+		if err := runURLCmd(t, mock, "aws", args...); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+	}
+	if want := []string{storage.URLStylePath, storage.URLStyleVirtualHosted}; len(mock.styles) != 2 || mock.styles[0] != want[0] || mock.styles[1] != want[1] {
+		t.Errorf("expected bucket URLs in styles %v, got %v", want, mock.styles)
+	}
+}
+
+func TestURLCmd_InvalidStyle(t *testing.T) {
+	for _, tt := range []struct {
+		provider, style string
+	}{
+		{"gcp", "s3"},
+		{"aws", "gs"},
+		{"gcp", "ftp"},
+	} {
+		var usage *usageError
+		if err := runURLCmd(t, &cmdStyleRecordingStorage{}, tt.provider, "--style", tt.style); !errors.As(err, &usage) {
+			t.Errorf("%s with --style %s: expected a usage error, got %v", tt.provider, tt.style, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/flags"
+	"github.com/jkleinne/synkronus/internal/output"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is how often a bare --watch refreshes
+const defaultWatchInterval = 5 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\x1b[H\x1b[2J"
+
+// addWatchFlag adds --watch to a list command. The interval must be attached
+// (--watch=30s), since a bare --watch uses defaultWatchInterval.
+func addWatchFlag(cmd *cobra.Command, interval *time.Duration) {
+	cmd.Flags().DurationVar(interval, flags.Watch, 0, fmt.Sprintf("Refresh the listing periodically, showing what was added or removed since the previous refresh (every %s, or e.g. --watch=30s)", defaultWatchInterval))
+	cmd.Flags().Lookup(flags.Watch).NoOptDefVal = defaultWatchInterval.String()
+}
+
+// watchSnapshot is the outcome of one refresh of a watched listing.
+type watchSnapshot struct {
+	view output.TableRenderer
+	// keys identify the entries, to find what changed between refreshes
+	keys []string
+	// warnings are the providers that failed while others answered
+	warnings error
+}
+
+// watchListing refreshes a listing every interval until ctx is done (e.g., on
+// Ctrl+C), redrawing it with the entries added and removed since the previous
+// refresh. A failed refresh is shown and retried at the next interval. Watch
+// mode renders tables only, and always fetches fresh results.
+func watchListing(ctx context.Context, app *appContainer, interval time.Duration, title string, refresh func(ctx context.Context) (watchSnapshot, error)) error {
+	if interval <= 0 {
+		return &usageError{err: fmt.Errorf("--%s must be positive, got %s", flags.Watch, interval)}
+	}
+	if app.OutputFormat != output.FormatTable {
+		return &usageError{err: fmt.Errorf("--%s only supports table output", flags.Watch)}
+	}
+	return watchLoop(cache.WithBypass(ctx), os.Stdout, isatty.IsTerminal(os.Stdout.Fd()), interval, title, refresh)
+}
+
+func watchLoop(ctx context.Context, w io.Writer, terminal bool, interval time.Duration, title string, refresh func(ctx context.Context) (watchSnapshot, error)) error {
+	var previous []string
+	var previousAt time.Time
+	for {
+		snapshot, err := refresh(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		now := time.Now()
+		var sb strings.Builder
+		if terminal {
+			sb.WriteString(clearScreen)
+		} else if !previousAt.IsZero() {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "Every %s: %s    %s\n\n", interval, title, now.Format(time.TimeOnly))
+		if err != nil {
+			fmt.Fprintf(&sb, "Refresh failed: %v\n", err)
+		} else {
+			sb.WriteString(snapshot.view.RenderTable())
+			sb.WriteString("\n")
+			if !previousAt.IsZero() {
+				added, removed := diffKeys(previous, snapshot.keys)
+				output.RenderChanges(&sb, added, removed, previousAt)
+			}
+			renderProviderWarnings(&sb, snapshot.warnings)
+			previous, previousAt = snapshot.keys, now
+		}
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// diffKeys returns the keys in current but not previous, and those in previous
+// but not current, each sorted.
+func diffKeys(previous, current []string) (added, removed []string) {
+	before := make(map[string]bool, len(previous))
+	for _, key := range previous {
+		before[key] = true
+	}
+	now := make(map[string]bool, len(current))
+	for _, key := range current {
+		now[key] = true
+		if !before[key] {
+			added = append(added, key)
+		}
+	}
+	for _, key := range previous {
+		if !now[key] {
+			removed = append(removed, key)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
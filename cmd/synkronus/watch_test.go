@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/output"
+)
+
+func TestDiffKeys(t *testing.T) {
+	added, removed := diffKeys([]string{"a", "b", "c"}, []string{"d", "b", "a"})
+	if !slices.Equal(added, []string{"d"}) || !slices.Equal(removed, []string{"c"}) {
+		t.Errorf("diffKeys() = (%v, %v), want ([d], [c])", added, removed)
+	}
+}
+
+func TestWatchLoop_StopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	refreshes := 0
+	refresh := func(context.Context) (watchSnapshot, error) {
+		refreshes++
+		if refreshes == 2 {
+			cancel()
+		}
+		return watchSnapshot{view: output.BucketListView{{Name: "a"}}, keys: []string{"gcp/a"}}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := watchLoop(ctx, &buf, false, time.Millisecond, "synkronus storage buckets list", refresh); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshes != 2 {
+		t.Fatalf("expected 2 refreshes, got %d", refreshes)
+	}
+	// The refresh interrupted by the cancellation is not drawn
+	if out := buf.String(); strings.Count(out, "Every 1ms: synkronus storage buckets list") != 1 || strings.Contains(out, clearScreen) {
+		t.Errorf("expected one plain drawn refresh, got:\n%s", out)
+	}
+}
+
+func TestWatchLoop_FailedRefreshIsShown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	refresh := func(context.Context) (watchSnapshot, error) {
+		calls++
+		switch calls {
+		case 1:
+			return watchSnapshot{view: output.BucketListView{{Name: "a"}}, keys: []string{"gcp/a"}}, nil
+		case 2:
+			return watchSnapshot{}, errors.New("connection reset")
+		case 3:
+			return watchSnapshot{view: output.BucketListView{{Name: "b"}}, keys: []string{"gcp/b"}}, nil
+		}
+		cancel()
+		return watchSnapshot{}, context.Canceled
+	}
+
+	var buf bytes.Buffer
+	if err := watchLoop(ctx, &buf, true, time.Millisecond, "list", refresh); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{clearScreen, "Refresh failed: connection reset", "+ gcp/b\n", "- gcp/a\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWatchListing_RejectsInvalidUse(t *testing.T) {
+	refresh := func(context.Context) (watchSnapshot, error) {
+		t.Fatal("refresh should not run")
+		return watchSnapshot{}, nil
+	}
+
+	app := newStorageTestApp(&cmdStorageFactory{}, nil)
+	var usage *usageError
+	if err := watchListing(context.Background(), app, 0, "list", refresh); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error for a zero interval, got %v", err)
+	}
+	app.OutputFormat = output.FormatJSON
+	if err := watchListing(context.Background(), app, time.Second, "list", refresh); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error for JSON output, got %v", err)
+	}
+}
+
+func TestListObjectsCmd_WatchWithRecursive_IsUsageError(t *testing.T) {
+	app := newStorageTestApp(&cmdStorageFactory{}, nil)
+	cmd := newListObjectsCmd()
+	cmd.SetContext(app.ToContext(context.Background()))
+	cmd.SetArgs([]string{"--provider", "gcp", "--bucket", "b", "--recursive", "--watch"})
+
+	var usage *usageError
+	if err := cmd.Execute(); !errors.As(err, &usage) {
+		t.Errorf("expected a usage error, got %v", err)
+	}
+}
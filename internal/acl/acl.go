@@ -0,0 +1,198 @@
+// Package acl analyzes the fine-grained ACLs of Cloud Storage buckets and
+// plans their migration to IAM, so Uniform Bucket-Level Access can be turned
+// on without anyone losing access.
+package acl
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"slices"
+	"strings"
+)
+
+// IAM roles equivalent to the bucket ACL roles, as documented for Cloud Storage
+var legacyRoles = map[string]string{
+	"READER": "roles/storage.legacyBucketReader",
+	"WRITER": "roles/storage.legacyBucketWriter",
+	"OWNER":  "roles/storage.legacyBucketOwner",
+}
+
+// Source is the subset of the storage service an analysis needs.
+type Source interface {
+	DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error)
+}
+
+// Grant is one ACL entry and the IAM binding that replaces it.
+type Grant struct {
+	Entity  string `json:"entity" yaml:"entity"`
+	ACLRole string `json:"acl_role" yaml:"acl_role"`
+	// Role and Member are the equivalent IAM role and principal; both are
+	// empty when the entry has no equivalent
+	Role   string `json:"role,omitempty" yaml:"role,omitempty"`
+	Member string `json:"member,omitempty" yaml:"member,omitempty"`
+	// Granted is set when the bucket's IAM policy already grants Role to Member
+	Granted bool   `json:"granted,omitempty" yaml:"granted,omitempty"`
+	Note    string `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+// Binding is an IAM role and the principals it is granted to, possibly only
+// under a condition.
+type Binding struct {
+	Role      string                `json:"role" yaml:"role"`
+	Members   []string              `json:"members" yaml:"members"`
+	Condition *storage.IAMCondition `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+// Analysis is the ACL of a bucket mapped to IAM.
+type Analysis struct {
+	Provider string  `json:"provider" yaml:"provider"`
+	Bucket   string  `json:"bucket" yaml:"bucket"`
+	Uniform  bool    `json:"uniform" yaml:"uniform"`
+	Grants   []Grant `json:"grants" yaml:"grants"`
+	// Missing are the bindings to add to the IAM policy before enabling
+	// Uniform Bucket-Level Access
+	Missing []Binding `json:"missing" yaml:"missing"`
+	// Policy is the bucket's IAM policy with Missing added. Conditional
+	// bindings are kept unchanged.
+	Policy []Binding `json:"policy" yaml:"policy"`
+	// Etag is the etag of the bucket's IAM policy, so setting Policy fails if
+	// the policy has changed since
+	Etag string `json:"etag,omitempty" yaml:"etag,omitempty"`
+}
+
+// Analyze describes a bucket and maps each entry of its ACL to the IAM
+// binding granting the same access, noting the bindings its IAM policy lacks.
+// Only Cloud Storage buckets have ACLs that Uniform Bucket-Level Access
+// replaces with IAM. Object ACLs are not read, so access granted object by
+// object is not covered.
+func Analyze(ctx context.Context, src Source, provider, bucketName string) (Analysis, error) {
+	provider = strings.ToLower(provider)
+	if provider != "gcp" && provider != "mock" {
+		return Analysis{}, fmt.Errorf("ACL migration applies to Cloud Storage buckets, not %s", provider)
+	}
+	bucket, err := src.DescribeBucket(ctx, bucketName, provider)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	analysis := Analysis{
+		Provider: provider,
+		Bucket:   bucket.Name,
+		Uniform:  bucket.UniformBucketLevelAccess != nil && bucket.UniformBucketLevelAccess.Enabled,
+		Grants:   []Grant{},
+	}
+	granted := make(map[[2]string]bool)
+	policy := make(map[string][]string)
+	var conditional []Binding
+	if bucket.IAMPolicy != nil {
+		analysis.Etag = bucket.IAMPolicy.Etag
+		for _, b := range bucket.IAMPolicy.Bindings {
+			// Conditional bindings do not grant unconditional access, but
+			// must stay in the policy
+			if b.Condition != nil {
+				conditional = append(conditional, Binding{Role: b.Role, Members: slices.Clone(b.Principals), Condition: b.Condition})
+				continue
+			}
+			for _, m := range b.Principals {
+				granted[[2]string{b.Role, m}] = true
+			}
+			policy[b.Role] = append(policy[b.Role], b.Principals...)
+		}
+	}
+
+	missing := make(map[string][]string)
+	for _, rule := range bucket.ACLs {
+		grant := MapRule(rule)
+		if grant.Role != "" {
+			grant.Granted = granted[[2]string{grant.Role, grant.Member}]
+			if !grant.Granted {
+				granted[[2]string{grant.Role, grant.Member}] = true
+				missing[grant.Role] = append(missing[grant.Role], grant.Member)
+				policy[grant.Role] = append(policy[grant.Role], grant.Member)
+			}
+		}
+		analysis.Grants = append(analysis.Grants, grant)
+	}
+	analysis.Missing = bindings(missing)
+	analysis.Policy = append(bindings(policy), conditional...)
+	slices.SortStableFunc(analysis.Policy, func(a, b Binding) int { return cmp.Compare(a.Role, b.Role) })
+	return analysis, nil
+}
+
+// MapRule returns the IAM role and member equivalent to an entry of a Cloud
+// Storage ACL, leaving them empty when there is none.
+func MapRule(rule storage.ACLRule) Grant {
+	grant := Grant{Entity: rule.Entity, ACLRole: rule.Role}
+	role, ok := legacyRoles[strings.ToUpper(rule.Role)]
+	if !ok {
+		grant.Note = "unknown ACL role"
+		return grant
+	}
+	member, note := mapEntity(rule.Entity)
+	if member == "" {
+		grant.Note = note
+		return grant
+	}
+	grant.Role, grant.Member, grant.Note = role, member, note
+	return grant
+}
+
+// mapEntity returns the IAM principal of an ACL entity (e.g., "user-a@example.com"
+// is "user:a@example.com"), with a note for entities worth a second look.
+func mapEntity(entity string) (member, note string) {
+	switch {
+	case entity == "allUsers":
+		return "allUsers", "public access"
+	case entity == "allAuthenticatedUsers":
+		return "allAuthenticatedUsers", "access for any Google account"
+	case strings.HasPrefix(entity, "user-"):
+		email := strings.TrimPrefix(entity, "user-")
+		if strings.HasSuffix(email, ".gserviceaccount.com") {
+			return "serviceAccount:" + email, ""
+		}
+		return "user:" + email, ""
+	case strings.HasPrefix(entity, "group-"):
+		return "group:" + strings.TrimPrefix(entity, "group-"), ""
+	case strings.HasPrefix(entity, "domain-"):
+		return "domain:" + strings.TrimPrefix(entity, "domain-"), ""
+	}
+	// Project convenience entities name the project by number, e.g. project-owners-123
+	for team, convenience := range map[string]string{"owners": "projectOwner", "editors": "projectEditor", "viewers": "projectViewer"} {
+		if number, ok := strings.CutPrefix(entity, "project-"+team+"-"); ok {
+			return convenience + ":" + number, "project number; replace with the project ID if the policy is rejected"
+		}
+	}
+	return "", "no IAM equivalent"
+}
+
+// bindings returns the members of each role as sorted bindings.
+func bindings(members map[string][]string) []Binding {
+	result := make([]Binding, 0, len(members))
+	for role, m := range members {
+		m = slices.Clone(m)
+		slices.Sort(m)
+		result = append(result, Binding{Role: role, Members: slices.Compact(m)})
+	}
+	slices.SortFunc(result, func(a, b Binding) int { return cmp.Compare(a.Role, b.Role) })
+	return result
+}
+
+// WritePlan writes the bucket's IAM policy with the missing bindings added,
+// in the format of 'gcloud storage buckets set-iam-policy'. Once it is set,
+// 'gcloud storage buckets update --uniform-bucket-level-access' keeps the
+// access the ACL granted. The policy replaces the bucket's whole policy, so it
+// is written as version 3, which conditional bindings need, with the etag of
+// the policy it was planned from.
+func (a Analysis) WritePlan(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Bindings []Binding `json:"bindings"`
+		Etag     string    `json:"etag,omitempty"`
+		Version  int       `json:"version"`
+	}{a.Policy, a.Etag, 3})
+}
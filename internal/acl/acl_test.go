@@ -0,0 +1,146 @@
+package acl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+type fakeSource struct {
+	bucket storage.Bucket
+	err    error
+}
+
+func (f fakeSource) DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error) {
+	return f.bucket, f.err
+}
+
+func TestAnalyze(t *testing.T) {
+	src := fakeSource{bucket: storage.Bucket{
+		Name: "legacy",
+		ACLs: []storage.ACLRule{
+			{Entity: "project-owners-123", Role: "OWNER"},
+			{Entity: "user-ann@example.com", Role: "WRITER"},
+			{Entity: "user-etl@p.iam.gserviceaccount.com", Role: "WRITER"},
+			{Entity: "group-data@example.com", Role: "READER"},
+			{Entity: "allUsers", Role: "READER"},
+			{Entity: "id-0123", Role: "READER"},
+		},
+		IAMPolicy: &storage.IAMPolicy{Bindings: []storage.IAMBinding{
+			{Role: "roles/storage.legacyBucketOwner", Principals: []string{"projectOwner:123"}},
+			{Role: "roles/storage.objectViewer", Principals: []string{"group:data@example.com"}},
+		}},
+	}}
+
+	a, err := Analyze(context.Background(), src, "GCP", "legacy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(a.Grants) != 6 {
+		t.Fatalf("expected 6 grants, got %+v", a.Grants)
+	}
+	if g := a.Grants[0]; !g.Granted || g.Member != "projectOwner:123" {
+		t.Errorf("expected the project owners grant to be granted already, got %+v", g)
+	}
+	if g := a.Grants[2]; g.Member != "serviceAccount:etl@p.iam.gserviceaccount.com" || g.Role != "roles/storage.legacyBucketWriter" {
+		t.Errorf("unexpected service account grant: %+v", g)
+	}
+	if g := a.Grants[4]; g.Note != "public access" {
+		t.Errorf("expected the allUsers grant to be flagged, got %+v", g)
+	}
+	if g := a.Grants[5]; g.Role != "" || g.Note != "no IAM equivalent" {
+		t.Errorf("expected the unknown entity to be unmapped, got %+v", g)
+	}
+
+	wantMissing := []Binding{
+		{Role: "roles/storage.legacyBucketReader", Members: []string{"allUsers", "group:data@example.com"}},
+		{Role: "roles/storage.legacyBucketWriter", Members: []string{"serviceAccount:etl@p.iam.gserviceaccount.com", "user:ann@example.com"}},
+	}
+	if !slices.EqualFunc(a.Missing, wantMissing, equalBinding) {
+		t.Errorf("Missing = %+v, want %+v", a.Missing, wantMissing)
+	}
+	if len(a.Policy) != 4 {
+		t.Errorf("expected the policy to keep its 2 bindings and add 2, got %+v", a.Policy)
+	}
+
+	var buf bytes.Buffer
+	if err := a.WritePlan(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var plan struct {
+		Bindings []Binding `json:"bindings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &plan); err != nil {
+		t.Fatalf("invalid plan: %v\n%s", err, buf.String())
+	}
+	if !slices.EqualFunc(plan.Bindings, a.Policy, equalBinding) {
+		t.Errorf("plan bindings = %+v, want %+v", plan.Bindings, a.Policy)
+	}
+}
+
+func TestWritePlan_KeepsConditionalBindings(t *testing.T) {
+	reportsOnly := &storage.IAMCondition{
+		Title:      "reports-only",
+		Expression: `resource.name.startsWith("projects/_/buckets/legacy/objects/reports/")`,
+	}
+	src := fakeSource{bucket: storage.Bucket{
+		Name: "legacy",
+		ACLs: []storage.ACLRule{{Entity: "user-ann@example.com", Role: "READER"}},
+		IAMPolicy: &storage.IAMPolicy{
+			Bindings: []storage.IAMBinding{
+				{Role: "roles/storage.objectViewer", Principals: []string{"user:ann@example.com"}, Condition: reportsOnly},
+			},
+			Etag: "CAU=",
+		},
+	}}
+
+	a, err := Analyze(context.Background(), src, "gcp", "legacy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := a.WritePlan(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var plan struct {
+		Bindings []Binding `json:"bindings"`
+		Etag     string    `json:"etag"`
+		Version  int       `json:"version"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &plan); err != nil {
+		t.Fatalf("invalid plan: %v\n%s", err, buf.String())
+	}
+
+	want := []Binding{
+		{Role: "roles/storage.legacyBucketReader", Members: []string{"user:ann@example.com"}},
+		{Role: "roles/storage.objectViewer", Members: []string{"user:ann@example.com"}, Condition: reportsOnly},
+	}
+	if !slices.EqualFunc(plan.Bindings, want, equalBinding) {
+		t.Errorf("plan bindings = %+v, want %+v", plan.Bindings, want)
+	}
+	if plan.Etag != "CAU=" || plan.Version != 3 {
+		t.Errorf("expected version 3 and the policy's etag, got version %d and etag %q", plan.Version, plan.Etag)
+	}
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	if _, err := Analyze(context.Background(), fakeSource{}, "aws", "b"); err == nil {
+		t.Error("expected an error for an S3 bucket")
+	}
+	want := errors.New("not found")
+	if _, err := Analyze(context.Background(), fakeSource{err: want}, "gcp", "b"); !errors.Is(err, want) {
+		t.Errorf("expected the describe error, got %v", err)
+	}
+}
+
+func equalBinding(a, b Binding) bool {
+	if (a.Condition == nil) != (b.Condition == nil) || (a.Condition != nil && *a.Condition != *b.Condition) {
+		return false
+	}
+	return a.Role == b.Role && slices.Equal(a.Members, b.Members)
+}
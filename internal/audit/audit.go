@@ -0,0 +1,155 @@
+// Package audit keeps an append-only local record of the changes synkronus
+// makes to provider resources (creating and deleting buckets, uploading,
+// copying and deleting objects), for traceability when several people operate
+// from a shared machine or account.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileName is created in the synkronus config directory
+const fileName = "audit.log"
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Entry is one recorded operation. Entries are stored one JSON object per line.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// User is the local account that ran the command
+	User string `json:"user"`
+	// Profile is the config profile the command ran with, if any
+	Profile   string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Operation string `json:"operation"`
+	Provider  string `json:"provider"`
+	// Resource identifies what was changed, e.g. "bucket" or "bucket/key"
+	Resource string `json:"resource"`
+	Result   string `json:"result"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Log appends entries to a file. A nil *Log is valid and records nothing.
+type Log struct {
+	mu      sync.Mutex
+	path    string
+	user    string
+	profile string
+	now     func() time.Time
+}
+
+// New returns a log appending to path, attributing entries to the current
+// user and profile.
+func New(path, profile string) *Log {
+	return &Log{path: path, user: currentUser(), profile: profile, now: time.Now}
+}
+
+// Path returns the location of the audit log.
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", config.ConfigDirName, fileName), nil
+}
+
+// Open returns the audit log at Path for commands run with profile.
+func Open(profile string) (*Log, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return New(path, profile), nil
+}
+
+// Record appends the outcome of operation on resource. err is the operation's
+// error, nil if it succeeded.
+func (l *Log) Record(operation, provider, resource string, err error) error {
+	if l == nil {
+		return nil
+	}
+	entry := Entry{
+		Time:      l.now().UTC(),
+		User:      l.user,
+		Profile:   l.profile,
+		Operation: operation,
+		Provider:  provider,
+		Resource:  resource,
+		Result:    ResultSuccess,
+	}
+	if err != nil {
+		entry.Result, entry.Error = ResultFailure, err.Error()
+	}
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", marshalErr)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, openErr := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if openErr != nil {
+		return fmt.Errorf("failed to open audit log: %w", openErr)
+	}
+	// One write per entry, so concurrent commands never interleave lines
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// Read returns the last limit entries of the log at path, oldest first, or all
+// of them if limit is 0. A missing log has no entries. Lines that are not valid
+// entries (e.g. cut short by a crash) are skipped.
+func Read(path string, limit int) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		entries = append(entries, e)
+		if limit > 0 && len(entries) > limit {
+			entries = entries[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// currentUser names the local account, falling back to $USER when the user
+// database is unavailable.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
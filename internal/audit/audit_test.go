@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synkronus", "audit.log")
+	log := New(path, "prod")
+	log.user = "alice"
+	log.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	if err := log.Record("create-bucket", "gcp", "logs", nil); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := log.Record("delete-object", "aws", "data/a.csv", errors.New("access denied")); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected the log to be created: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected mode 0600, got %o", perm)
+	}
+
+	entries, err := Read(path, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	want := Entry{
+		Time:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		User:      "alice",
+		Profile:   "prod",
+		Operation: "create-bucket",
+		Provider:  "gcp",
+		Resource:  "logs",
+		Result:    ResultSuccess,
+	}
+	if entries[0] != want {
+		t.Errorf("entries[0] = %+v, want %+v", entries[0], want)
+	}
+	if entries[1].Result != ResultFailure || entries[1].Error != "access denied" {
+		t.Errorf("expected the failure to be recorded, got %+v", entries[1])
+	}
+}
+
+func TestRead_LimitKeepsMostRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log := New(path, "")
+	for _, resource := range []string{"a", "b", "c"} {
+		if err := log.Record("delete-bucket", "gcp", resource, nil); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	entries, err := Read(path, 2)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Resource != "b" || entries[1].Resource != "c" {
+		t.Errorf("expected the last two entries oldest first, got %+v", entries)
+	}
+}
+
+func TestRead_SkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	data := `{"operation":"create-bucket","resource":"a"}` + "\n" + `{"operation":"delete-` + "\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(path, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Resource != "a" {
+		t.Errorf("expected only the valid entry, got %+v", entries)
+	}
+}
+
+func TestRead_MissingLog(t *testing.T) {
+	entries, err := Read(filepath.Join(t.TempDir(), "audit.log"), 0)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("expected no entries and no error, got %v, %v", entries, err)
+	}
+}
+
+func TestNilLog_RecordsNothing(t *testing.T) {
+	var log *Log
+	if err := log.Record("delete-bucket", "gcp", "a", nil); err != nil {
+		t.Errorf("expected a nil log to accept records, got %v", err)
+	}
+}
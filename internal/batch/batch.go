@@ -0,0 +1,272 @@
+// Package batch runs the object operations listed in a CSV manifest (copy,
+// delete, set-metadata), a few at a time, and reports the outcome of each
+// line. The lines that did not succeed can be written back out as a manifest,
+// so a failed or interrupted batch is resumed by running that file.
+package batch
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Operation is what a manifest line does to its object.
+type Operation string
+
+const (
+	// OpCopy copies the object to dest-bucket, at dest-key or the same key
+	OpCopy Operation = "copy"
+	// OpDelete deletes the object
+	OpDelete Operation = "delete"
+	// OpSetMetadata sets the given user-defined metadata keys of the object
+	OpSetMetadata Operation = "set-metadata"
+)
+
+// Status is the outcome of a manifest line.
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	// StatusNotRun is a line left over when the batch was interrupted
+	StatusNotRun Status = "not-run"
+)
+
+// Entry is one line of a manifest:
+//
+//	copy,<provider>,<bucket>,<key>,<dest-bucket>[,<dest-key>]
+//	delete,<provider>,<bucket>,<key>
+//	set-metadata,<provider>,<bucket>,<key>,<name>=<value>[,<name>=<value>...]
+type Entry struct {
+	// Line is the entry's line number in the manifest
+	Line      int
+	Operation Operation
+	Provider  string
+	Bucket    string
+	Key       string
+	// DestBucket and DestKey are the destination of a copy
+	DestBucket string
+	DestKey    string
+	// Metadata holds the keys set by set-metadata
+	Metadata map[string]string
+	// record is the line as read, written back out for the lines to retry
+	record []string
+}
+
+// Target describes the object(s) the entry acts on.
+func (e Entry) Target() string {
+	if e.Operation == OpCopy {
+		return fmt.Sprintf("%s/%s -> %s/%s", e.Bucket, e.Key, e.DestBucket, e.DestKey)
+	}
+	return e.Bucket + "/" + e.Key
+}
+
+// Parse reads a manifest. Blank lines and lines starting with '#' are ignored,
+// as is a first line naming the columns (starting with "operation"). Every
+// line is checked before any is run, so a malformed manifest fails as a whole,
+// naming the line at fault.
+func Parse(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []Entry
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "operation") {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		entry, err := parseEntry(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest line %d: %w", line, err)
+		}
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("manifest has no operations")
+	}
+	return entries, nil
+}
+
+func parseEntry(record []string) (Entry, error) {
+	fields := make([]string, len(record))
+	for i, f := range record {
+		fields[i] = strings.TrimSpace(f)
+	}
+	if len(fields) < 4 {
+		return Entry{}, fmt.Errorf("expected at least operation, provider, bucket and key, got %d fields", len(fields))
+	}
+	entry := Entry{
+		Operation: Operation(strings.ToLower(fields[0])),
+		Provider:  fields[1],
+		Bucket:    fields[2],
+		Key:       fields[3],
+		record:    record,
+	}
+	for i, name := range []string{"operation", "provider", "bucket", "key"} {
+		if fields[i] == "" {
+			return Entry{}, fmt.Errorf("%s is empty", name)
+		}
+	}
+	args := fields[4:]
+
+	switch entry.Operation {
+	case OpCopy:
+		if len(args) < 1 || len(args) > 2 || args[0] == "" {
+			return Entry{}, errors.New("copy takes a destination bucket and optionally a destination key")
+		}
+		entry.DestBucket, entry.DestKey = args[0], entry.Key
+		if len(args) == 2 && args[1] != "" {
+			entry.DestKey = args[1]
+		}
+		if entry.DestBucket == entry.Bucket && entry.DestKey == entry.Key {
+			return Entry{}, errors.New("copy source and destination are the same object")
+		}
+	case OpDelete:
+		if len(args) > 0 {
+			return Entry{}, fmt.Errorf("delete takes no arguments, got %d", len(args))
+		}
+	case OpSetMetadata:
+		if len(args) == 0 {
+			return Entry{}, errors.New("set-metadata takes at least one name=value pair")
+		}
+		entry.Metadata = make(map[string]string, len(args))
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return Entry{}, fmt.Errorf("invalid metadata %q, expected name=value", arg)
+			}
+			entry.Metadata[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	default:
+		return Entry{}, fmt.Errorf("unknown operation %q (expected %s, %s or %s)", fields[0], OpCopy, OpDelete, OpSetMetadata)
+	}
+	return entry, nil
+}
+
+// Storage is the subset of the storage service a batch needs.
+type Storage interface {
+	CopyObject(ctx context.Context, srcBucket, srcKey, destBucket, destKey, providerName string) error
+	DeleteObject(ctx context.Context, bucketName, objectKey, providerName string) error
+	UpdateObjectMetadata(ctx context.Context, bucketName, objectKey, providerName string, metadata map[string]string) error
+}
+
+// Result is the outcome of one manifest line.
+type Result struct {
+	Line      int       `json:"line"`
+	Operation Operation `json:"operation"`
+	Provider  string    `json:"provider"`
+	Target    string    `json:"target"`
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Report is the outcome of a batch, with a result per entry in manifest order.
+type Report struct {
+	Results   []Result `json:"results"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	NotRun    int      `json:"not_run" yaml:"not_run"`
+}
+
+// Run performs the entries, parallel at a time. A failed entry does not stop
+// the batch; it is reported, and the returned error counts the failures. When
+// ctx is canceled, the entries not yet started are reported as not run and
+// the context's error is returned.
+func Run(ctx context.Context, store Storage, entries []Entry, parallel int) (Report, error) {
+	if parallel <= 0 {
+		return Report{}, fmt.Errorf("parallelism must be positive, got %d", parallel)
+	}
+
+	report := Report{Results: make([]Result, len(entries))}
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(parallel, len(entries)) {
+		wg.Go(func() {
+			for i := range work {
+				entry := entries[i]
+				result := Result{Line: entry.Line, Operation: entry.Operation, Provider: entry.Provider, Target: entry.Target(), Status: StatusNotRun}
+				if ctx.Err() == nil {
+					result.Status = StatusSucceeded
+					if err := run(ctx, store, entry); err != nil {
+						result.Status, result.Error = StatusFailed, err.Error()
+					}
+				}
+				// Each worker writes its own index, so no lock is needed
+				report.Results[i] = result
+			}
+		})
+	}
+	for i := range entries {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for _, r := range report.Results {
+		switch r.Status {
+		case StatusSucceeded:
+			report.Succeeded++
+		case StatusFailed:
+			report.Failed++
+		case StatusNotRun:
+			report.NotRun++
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if report.Failed > 0 {
+		return report, fmt.Errorf("%d of %d operations failed", report.Failed, len(entries))
+	}
+	return report, nil
+}
+
+func run(ctx context.Context, store Storage, e Entry) error {
+	switch e.Operation {
+	case OpCopy:
+		return store.CopyObject(ctx, e.Bucket, e.Key, e.DestBucket, e.DestKey, e.Provider)
+	case OpDelete:
+		return store.DeleteObject(ctx, e.Bucket, e.Key, e.Provider)
+	case OpSetMetadata:
+		return store.UpdateObjectMetadata(ctx, e.Bucket, e.Key, e.Provider, e.Metadata)
+	default:
+		return fmt.Errorf("unknown operation %q", e.Operation)
+	}
+}
+
+// Unfinished returns the entries of report that failed or did not run.
+func Unfinished(entries []Entry, report Report) []Entry {
+	var unfinished []Entry
+	for i, r := range report.Results {
+		if r.Status != StatusSucceeded {
+			unfinished = append(unfinished, entries[i])
+		}
+	}
+	return unfinished
+}
+
+// WriteManifest writes entries as a manifest, each line as it was read.
+func WriteManifest(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	for _, e := range entries {
+		if err := cw.Write(e.record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
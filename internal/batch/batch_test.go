@@ -0,0 +1,169 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingStorage records the operations it is asked to perform, failing
+// those on the keys in fail.
+type recordingStorage struct {
+	mu   sync.Mutex
+	ops  []string
+	fail map[string]bool
+}
+
+func (s *recordingStorage) record(op, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ops = append(s.ops, op+" "+key)
+	if s.fail[key] {
+		return errors.New("access denied")
+	}
+	return nil
+}
+
+func (s *recordingStorage) CopyObject(_ context.Context, _, srcKey, _, destKey, _ string) error {
+	return s.record("copy", srcKey+"->"+destKey)
+}
+
+func (s *recordingStorage) DeleteObject(_ context.Context, _, objectKey, _ string) error {
+	return s.record("delete", objectKey)
+}
+
+func (s *recordingStorage) UpdateObjectMetadata(_ context.Context, _, objectKey, _ string, metadata map[string]string) error {
+	return s.record("set-metadata", objectKey+" owner="+metadata["owner"])
+}
+
+const manifest = `operation,provider,bucket,key,arguments
+# Move the reports
+copy,gcp,src,reports/a.csv,dst
+copy,gcp,src,reports/b.csv,dst,archive/b.csv
+
+delete,aws,logs,tmp/old.log
+set-metadata,gcp,src,reports/a.csv,owner=data,"note=a, b"
+`
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	if e := entries[0]; e.Line != 3 || e.Operation != OpCopy || e.DestBucket != "dst" || e.DestKey != "reports/a.csv" {
+		t.Errorf("unexpected copy entry: %+v", e)
+	}
+	if e := entries[1]; e.DestKey != "archive/b.csv" || e.Target() != "src/reports/b.csv -> dst/archive/b.csv" {
+		t.Errorf("unexpected copy entry: %+v", e)
+	}
+	if e := entries[2]; e.Line != 6 || e.Operation != OpDelete || e.Provider != "aws" || e.Target() != "logs/tmp/old.log" {
+		t.Errorf("unexpected delete entry: %+v", e)
+	}
+	if e := entries[3]; e.Metadata["owner"] != "data" || e.Metadata["note"] != "a, b" {
+		t.Errorf("unexpected set-metadata entry: %+v", e)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"too few fields":      "delete,gcp,bucket",
+		"empty key":           "delete,gcp,bucket,",
+		"unknown operation":   "move,gcp,bucket,key,other",
+		"copy without dest":   "copy,gcp,bucket,key",
+		"copy onto itself":    "copy,gcp,bucket,key,bucket",
+		"delete with args":    "delete,gcp,bucket,key,extra",
+		"metadata without kv": "set-metadata,gcp,bucket,key",
+		"metadata not a pair": "set-metadata,gcp,bucket,key,owner",
+		"only a header":       "operation,provider,bucket,key",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(input)); err == nil {
+				t.Errorf("expected an error for %q", input)
+			}
+		})
+	}
+}
+
+func TestParse_NamesTheLine(t *testing.T) {
+	_, err := Parse(strings.NewReader("delete,gcp,bucket,a\n\ndelete,gcp,bucket\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected the error to name line 3, got %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	entries, err := Parse(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &recordingStorage{fail: map[string]bool{"tmp/old.log": true}}
+
+	report, err := Run(context.Background(), store, entries, 2)
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 operations failed") {
+		t.Errorf("expected the failure to be counted, got %v", err)
+	}
+	if report.Succeeded != 3 || report.Failed != 1 || report.NotRun != 0 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if len(store.ops) != 4 {
+		t.Errorf("expected every operation to run, got %v", store.ops)
+	}
+	// Results follow the manifest, whatever order the operations finished in
+	for i, r := range report.Results {
+		if r.Line != entries[i].Line {
+			t.Errorf("result %d is for line %d, expected %d", i, r.Line, entries[i].Line)
+		}
+	}
+	if r := report.Results[2]; r.Status != StatusFailed || r.Error != "access denied" {
+		t.Errorf("unexpected result for the failed line: %+v", r)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	entries, err := Parse(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := Run(ctx, &recordingStorage{}, entries, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if report.NotRun != len(entries) {
+		t.Errorf("expected every line not run, got %+v", report)
+	}
+}
+
+func TestUnfinished_WriteManifest(t *testing.T) {
+	entries, err := Parse(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &recordingStorage{fail: map[string]bool{"reports/a.csv owner=data": true, "tmp/old.log": true}}
+	report, _ := Run(context.Background(), store, entries, 4)
+
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, Unfinished(entries, report)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "delete,aws,logs,tmp/old.log\nset-metadata,gcp,src,reports/a.csv,owner=data,\"note=a, b\"\n"
+	if buf.String() != want {
+		t.Errorf("expected failure file:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	// The failure file is itself a manifest
+	retry, err := Parse(&buf)
+	if err != nil || len(retry) != 2 {
+		t.Errorf("expected the failure file to parse as 2 entries, got %d (%v)", len(retry), err)
+	}
+}
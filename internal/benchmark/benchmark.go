@@ -0,0 +1,295 @@
+// Package benchmark measures how fast a bucket accepts and serves data: it
+// uploads synthetic objects, downloads them back, and reports throughput,
+// latency percentiles, and error rates. The objects are deleted afterwards.
+// RunList measures how fast large listings stream and how much memory they use.
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operations measured by Run
+const (
+	OperationUpload   = "upload"
+	OperationDownload = "download"
+)
+
+// cleanupTimeout bounds deleting the synthetic objects, which runs even after
+// the benchmark was canceled
+const cleanupTimeout = time.Minute
+
+// Storage is the subset of the storage service a benchmark needs.
+type Storage interface {
+	UploadObject(ctx context.Context, opts storage.UploadObjectOptions, providerName string, reader io.Reader) error
+	DownloadObject(ctx context.Context, bucketName, objectKey, providerName string) (io.ReadCloser, error)
+	DeleteObject(ctx context.Context, bucketName, objectKey, providerName string) error
+}
+
+// Options configures a benchmark run.
+type Options struct {
+	Provider string
+	Bucket   string
+	// Prefix is prepended to the keys of the synthetic objects
+	Prefix string
+	// Size is the size of each object in bytes
+	Size int64
+	// Count is how many objects are uploaded and downloaded
+	Count int
+	// Parallel is how many transfers run at once
+	Parallel int
+}
+
+// Result summarizes one operation of a run.
+type Result struct {
+	Provider  string `json:"provider"`
+	Operation string `json:"operation"`
+	Objects   int    `json:"objects"`
+	Errors    int    `json:"errors"`
+	// ErrorRate is Errors divided by Objects
+	ErrorRate float64 `json:"error_rate" yaml:"error_rate"`
+	// Bytes is the data transferred by the transfers that succeeded
+	Bytes int64 `json:"bytes"`
+	// Throughput is Bytes per second of wall-clock time for the whole operation
+	Throughput float64 `json:"throughput_bytes_per_second" yaml:"throughput_bytes_per_second"`
+	// Latencies are the durations of single transfers that succeeded, in milliseconds
+	LatencyP50 float64 `json:"latency_p50_ms" yaml:"latency_p50_ms"`
+	LatencyP90 float64 `json:"latency_p90_ms" yaml:"latency_p90_ms"`
+	LatencyP99 float64 `json:"latency_p99_ms" yaml:"latency_p99_ms"`
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	Results []Result `json:"results"`
+	// Leftover lists the synthetic objects that could not be deleted
+	Leftover []string `json:"leftover,omitempty" yaml:"leftover,omitempty"`
+}
+
+// Validate checks that opts describe a runnable benchmark.
+func (o Options) Validate() error {
+	switch {
+	case o.Size <= 0:
+		return fmt.Errorf("object size must be positive, got %d", o.Size)
+	case o.Count <= 0:
+		return fmt.Errorf("object count must be positive, got %d", o.Count)
+	case o.Parallel <= 0:
+		return fmt.Errorf("parallelism must be positive, got %d", o.Parallel)
+	}
+	return nil
+}
+
+// Run uploads opts.Count synthetic objects, downloads the ones that were
+// uploaded, and deletes them. It fails only if no upload succeeded; other
+// failures are counted in the results. Deletion runs even when ctx is
+// canceled, so an interrupted benchmark does not leave objects behind; the
+// partial report is then returned with the context's error.
+func Run(ctx context.Context, store Storage, opts Options) (Report, error) {
+	if err := opts.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	keys := make([]string, opts.Count)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%sobject-%05d", opts.Prefix, i)
+	}
+
+	var uploaded []string
+	var mu sync.Mutex
+	uploads, uploadErr := measure(ctx, opts, OperationUpload, keys, func(ctx context.Context, key string) (int64, error) {
+		err := store.UploadObject(ctx, storage.UploadObjectOptions{
+			BucketName:  opts.Bucket,
+			ObjectKey:   key,
+			ContentType: "application/octet-stream",
+		}, opts.Provider, newSyntheticObject(opts.Size))
+		if err != nil {
+			return 0, err
+		}
+		mu.Lock()
+		uploaded = append(uploaded, key)
+		mu.Unlock()
+		return opts.Size, nil
+	})
+
+	report := Report{Results: []Result{uploads}}
+	if len(uploaded) > 0 {
+		slices.Sort(uploaded)
+		downloads, _ := measure(ctx, opts, OperationDownload, uploaded, func(ctx context.Context, key string) (int64, error) {
+			reader, err := store.DownloadObject(ctx, opts.Bucket, key, opts.Provider)
+			if err != nil {
+				return 0, err
+			}
+			defer reader.Close()
+			n, err := io.Copy(io.Discard, reader)
+			if err == nil && n != opts.Size {
+				err = fmt.Errorf("object %q: downloaded %d bytes, expected %d", key, n, opts.Size)
+			}
+			return n, err
+		})
+		report.Results = append(report.Results, downloads)
+	}
+
+	report.Leftover = cleanup(ctx, store, opts, uploaded)
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if len(uploaded) == 0 {
+		return report, fmt.Errorf("every upload to bucket %q failed: %w", opts.Bucket, uploadErr)
+	}
+	return report, nil
+}
+
+// measure runs transfer for every key, opts.Parallel at a time, and summarizes
+// the outcome. It also returns the first error, for when every transfer failed.
+func measure(ctx context.Context, opts Options, operation string, keys []string, transfer func(ctx context.Context, key string) (int64, error)) (Result, error) {
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		bytes     int64
+		failures  int
+		firstErr  error
+	)
+
+	work := make(chan string)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range min(opts.Parallel, len(keys)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range work {
+				began := time.Now()
+				n, err := transfer(ctx, key)
+				elapsed := time.Since(began)
+
+				mu.Lock()
+				if err != nil {
+					failures++
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					latencies = append(latencies, elapsed)
+					bytes += n
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, key := range keys {
+		work <- key
+	}
+	close(work)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	result := Result{
+		Provider:  strings.ToLower(opts.Provider),
+		Operation: operation,
+		Objects:   len(keys),
+		Errors:    failures,
+		ErrorRate: float64(failures) / float64(len(keys)),
+		Bytes:     bytes,
+	}
+	if elapsed > 0 {
+		result.Throughput = float64(bytes) / elapsed.Seconds()
+	}
+	slices.Sort(latencies)
+	result.LatencyP50 = percentile(latencies, 50)
+	result.LatencyP90 = percentile(latencies, 90)
+	result.LatencyP99 = percentile(latencies, 99)
+	return result, firstErr
+}
+
+// percentile returns the nearest-rank percentile p of sorted, in milliseconds,
+// or 0 if sorted is empty.
+func percentile(sorted []time.Duration, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return float64(sorted[max(rank, 1)-1].Microseconds()) / 1000
+}
+
+// cleanup deletes the uploaded objects and returns the keys it could not
+// delete. It is not bound to ctx, which may already be canceled.
+func cleanup(ctx context.Context, store Storage, opts Options, keys []string) []string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+
+	var leftover []string
+	for _, key := range keys {
+		if err := store.DeleteObject(ctx, opts.Bucket, key, opts.Provider); err != nil {
+			leftover = append(leftover, key)
+		}
+	}
+	return leftover
+}
+
+// syntheticBlockSize is the size of the random block synthetic objects repeat
+const syntheticBlockSize = 1 << 20
+
+// syntheticBlock is generated once per process. Random data keeps providers
+// and proxies from compressing the transfers.
+var syntheticBlock = sync.OnceValue(func() []byte {
+	block := make([]byte, syntheticBlockSize)
+	for i := range block {
+		block[i] = byte(rand.IntN(256))
+	}
+	return block
+})
+
+// syntheticObject is an object body of a given size that repeats
+// syntheticBlock without holding the whole object in memory. It is seekable,
+// so providers can sign it and retry a part.
+type syntheticObject struct {
+	size   int64
+	offset int64
+}
+
+func newSyntheticObject(size int64) *syntheticObject {
+	return &syntheticObject{size: size}
+}
+
+func (o *syntheticObject) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	block := syntheticBlock()
+	p = p[:min(int64(len(p)), o.size-o.offset)]
+	n := 0
+	for n < len(p) {
+		n += copy(p[n:], block[(o.offset+int64(n))%syntheticBlockSize:])
+	}
+	o.offset += int64(n)
+	return n, nil
+}
+
+func (o *syntheticObject) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative position %d", offset)
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+// Len reports the unread bytes, which lets upload metrics count the object.
+func (o *syntheticObject) Len() int {
+	return int(max(o.size-o.offset, 0))
+}
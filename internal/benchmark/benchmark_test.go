@@ -0,0 +1,200 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStorage keeps uploaded objects in memory.
+type memoryStorage struct {
+	mu         sync.Mutex
+	objects    map[string][]byte
+	uploadErr  error
+	truncateTo int
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: map[string][]byte{}}
+}
+
+func (m *memoryStorage) UploadObject(_ context.Context, opts storage.UploadObjectOptions, _ string, reader io.Reader) error {
+	if m.uploadErr != nil {
+		return m.uploadErr
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[opts.ObjectKey] = data
+	return nil
+}
+
+func (m *memoryStorage) DownloadObject(_ context.Context, _, objectKey, _ string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[objectKey]
+	if !ok {
+		return nil, errors.New("no such object")
+	}
+	if m.truncateTo > 0 {
+		data = data[:m.truncateTo]
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryStorage) DeleteObject(_ context.Context, _, objectKey, _ string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, objectKey)
+	return nil
+}
+
+func testOptions() Options {
+	return Options{Provider: "GCP", Bucket: "bench", Prefix: "run/", Size: 3 << 19, Count: 5, Parallel: 2}
+}
+
+func TestRun(t *testing.T) {
+	store := newMemoryStorage()
+
+	report, err := Run(context.Background(), store, testOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("expected upload and download results, got %+v", report.Results)
+	}
+	for i, op := range []string{OperationUpload, OperationDownload} {
+		r := report.Results[i]
+		if r.Operation != op || r.Provider != "gcp" || r.Objects != 5 || r.Errors != 0 || r.Bytes != 5*(3<<19) {
+			t.Errorf("unexpected %s result: %+v", op, r)
+		}
+		if r.Throughput <= 0 || r.LatencyP50 > r.LatencyP99 {
+			t.Errorf("unexpected %s measurements: %+v", op, r)
+		}
+	}
+	if len(store.objects) != 0 || len(report.Leftover) != 0 {
+		t.Errorf("expected the objects to be deleted, left %v", store.objects)
+	}
+}
+
+func TestRun_EveryUploadFails(t *testing.T) {
+	store := newMemoryStorage()
+	store.uploadErr = errors.New("access denied")
+
+	report, err := Run(context.Background(), store, testOptions())
+	if !errors.Is(err, store.uploadErr) {
+		t.Fatalf("expected the upload error, got %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].ErrorRate != 1 {
+		t.Errorf("expected only a failed upload result, got %+v", report.Results)
+	}
+}
+
+func TestRun_ShortDownloadIsAnError(t *testing.T) {
+	store := newMemoryStorage()
+	store.truncateTo = 10
+
+	report, err := Run(context.Background(), store, testOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := report.Results[1]; got.Errors != 5 || got.ErrorRate != 1 {
+		t.Errorf("expected every download to fail, got %+v", got)
+	}
+}
+
+func TestRun_CanceledStillCleansUp(t *testing.T) {
+	store := newMemoryStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	wrapped := &cancelingStorage{memoryStorage: store, cancel: cancel}
+
+	_, err := Run(ctx, wrapped, testOptions())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(store.objects) != 0 {
+		t.Errorf("expected uploaded objects to be deleted after cancellation, left %d", len(store.objects))
+	}
+}
+
+// cancelingStorage cancels the benchmark after its first upload and fails the
+// uploads that follow, as a provider does once the context is canceled.
+type cancelingStorage struct {
+	*memoryStorage
+	cancel context.CancelFunc
+}
+
+func (c *cancelingStorage) UploadObject(ctx context.Context, opts storage.UploadObjectOptions, provider string, reader io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	defer c.cancel()
+	return c.memoryStorage.UploadObject(ctx, opts, provider, reader)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	for _, opts := range []Options{
+		{Size: 0, Count: 1, Parallel: 1},
+		{Size: 1, Count: 0, Parallel: 1},
+		{Size: 1, Count: 1, Parallel: 0},
+	} {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", opts)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		p    int
+		want float64
+	}{
+		{50, 5},
+		{90, 9},
+		{99, 10},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%d) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 without samples, got %v", got)
+	}
+}
+
+func TestSyntheticObject(t *testing.T) {
+	size := int64(syntheticBlockSize + 123)
+	obj := newSyntheticObject(size)
+	if obj.Len() != int(size) {
+		t.Errorf("Len() = %d, want %d", obj.Len(), size)
+	}
+
+	first, err := io.ReadAll(obj)
+	if err != nil || int64(len(first)) != size {
+		t.Fatalf("read %d bytes (err %v), want %d", len(first), err, size)
+	}
+	if _, err := obj.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	second, _ := io.ReadAll(obj)
+	if !bytes.Equal(first, second) {
+		t.Error("expected the same content after seeking back to the start")
+	}
+	if strings.Count(string(first[:16]), string(first[0])) == 16 {
+		t.Error("expected random content")
+	}
+}
@@ -0,0 +1,132 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// listPageSize is how many objects S3 and Cloud Storage return per listing
+// page by default. Walks do not expose page boundaries, so pages are derived
+// from the object count.
+const listPageSize = 1000
+
+// memSampleInterval is how often heap usage is sampled during a listing
+const memSampleInterval = 20 * time.Millisecond
+
+// Walker is the subset of the storage service a listing benchmark needs.
+type Walker interface {
+	WalkObjects(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.Object) error) error
+}
+
+// ListOptions configures a listing benchmark.
+type ListOptions struct {
+	Provider string
+	Bucket   string
+	Prefix   string
+}
+
+// ListResult summarizes a listing benchmark.
+type ListResult struct {
+	Provider string `json:"provider"`
+	Bucket   string `json:"bucket"`
+	Objects  int    `json:"objects"`
+	// Pages is estimated from Objects and the providers' default page size
+	Pages int `json:"pages"`
+	// Seconds is the wall-clock time of the whole walk
+	Seconds float64 `json:"seconds"`
+	// FirstObjectMillis is how long the first object took to arrive
+	FirstObjectMillis float64 `json:"first_object_ms" yaml:"first_object_ms"`
+	ObjectsPerSecond  float64 `json:"objects_per_second" yaml:"objects_per_second"`
+	PagesPerSecond    float64 `json:"pages_per_second" yaml:"pages_per_second"`
+	// PeakHeapBytes is the largest heap in use seen while walking, sampled
+	// every memSampleInterval. It should not grow with the bucket's size.
+	PeakHeapBytes uint64 `json:"peak_heap_bytes" yaml:"peak_heap_bytes"`
+	// AllocatedBytes is the memory allocated during the walk, garbage included
+	AllocatedBytes uint64 `json:"allocated_bytes" yaml:"allocated_bytes"`
+}
+
+// RunList walks every object under opts.Prefix, discarding them, and reports
+// the listing rate and memory use. On failure, the result covers the objects
+// listed before the error.
+func RunList(ctx context.Context, walker Walker, opts ListOptions) (ListResult, error) {
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	sampler := startHeapSampler()
+
+	var objects int
+	var firstObject time.Duration
+	start := time.Now()
+	err := walker.WalkObjects(ctx, opts.Bucket, opts.Provider, opts.Prefix, func(storage.Object) error {
+		if objects == 0 {
+			firstObject = time.Since(start)
+		}
+		objects++
+		return nil
+	})
+	elapsed := time.Since(start)
+
+	peak := sampler.stop()
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	result := ListResult{
+		Provider:          strings.ToLower(opts.Provider),
+		Bucket:            opts.Bucket,
+		Objects:           objects,
+		Pages:             (objects + listPageSize - 1) / listPageSize,
+		Seconds:           elapsed.Seconds(),
+		FirstObjectMillis: float64(firstObject.Microseconds()) / 1000,
+		PeakHeapBytes:     max(peak, after.HeapInuse),
+		AllocatedBytes:    after.TotalAlloc - before.TotalAlloc,
+	}
+	if elapsed > 0 {
+		result.ObjectsPerSecond = float64(result.Objects) / elapsed.Seconds()
+		result.PagesPerSecond = float64(result.Pages) / elapsed.Seconds()
+	}
+	if err != nil {
+		return result, fmt.Errorf("listing stopped after %d objects: %w", objects, err)
+	}
+	return result, nil
+}
+
+// heapSampler records the peak heap in use until stopped.
+type heapSampler struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+	peak uint64
+}
+
+func startHeapSampler() *heapSampler {
+	s := &heapSampler{done: make(chan struct{})}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(memSampleInterval)
+		defer ticker.Stop()
+		var stats runtime.MemStats
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				runtime.ReadMemStats(&stats)
+				s.peak = max(s.peak, stats.HeapInuse)
+			}
+		}
+	}()
+	return s
+}
+
+// stop ends sampling and returns the peak heap in use.
+func (s *heapSampler) stop() uint64 {
+	close(s.done)
+	s.wg.Wait()
+	return s.peak
+}
@@ -0,0 +1,61 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"testing"
+)
+
+// generatedWalker emulates a bucket of n objects, failing after failAfter
+// objects if failAfter is positive.
+type generatedWalker struct {
+	n         int
+	failAfter int
+}
+
+func (w generatedWalker) WalkObjects(ctx context.Context, _, _, _ string, fn func(storage.Object) error) error {
+	for i := range w.n {
+		if w.failAfter > 0 && i == w.failAfter {
+			return errors.New("connection reset")
+		}
+		if err := fn(storage.Object{Key: fmt.Sprintf("object-%07d", i), Size: 1024}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRunList(t *testing.T) {
+	result, err := RunList(context.Background(), generatedWalker{n: 2500}, ListOptions{Provider: "AWS", Bucket: "big"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Provider != "aws" || result.Bucket != "big" || result.Objects != 2500 || result.Pages != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.ObjectsPerSecond <= 0 || result.PagesPerSecond <= 0 || result.PeakHeapBytes == 0 {
+		t.Errorf("expected rates and memory to be measured, got %+v", result)
+	}
+}
+
+func TestRunList_WalkError_ReportsPartialResult(t *testing.T) {
+	result, err := RunList(context.Background(), generatedWalker{n: 2500, failAfter: 1200}, ListOptions{Provider: "gcp", Bucket: "big"})
+	if err == nil {
+		t.Fatal("expected the walk error")
+	}
+	if result.Objects != 1200 || result.Pages != 2 {
+		t.Errorf("expected the objects listed before the error, got %+v", result)
+	}
+}
+
+func TestRunList_EmptyBucket(t *testing.T) {
+	result, err := RunList(context.Background(), generatedWalker{}, ListOptions{Provider: "gcp", Bucket: "empty"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Objects != 0 || result.Pages != 0 || result.FirstObjectMillis != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
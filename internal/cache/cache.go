@@ -0,0 +1,173 @@
+// Package cache stores query results on disk for a short time. It backs the
+// bucket usage metrics cache, which keeps repeated bucket listings from
+// re-querying the monitoring APIs, and the opt-in response cache for read-only
+// commands (cache.enabled).
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// dirName is created under the synkronus config directory; each cache
+	// gets its own subdirectory
+	dirName = "cache"
+)
+
+// entry is the on-disk form of a cached value.
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// Cache reads and writes results as JSON files in a directory. A nil *Cache
+// is valid and caches nothing.
+type Cache struct {
+	dir   string
+	ttl   time.Duration
+	scope string
+	now   func() time.Time
+}
+
+// New returns a cache storing entries in dir that expire after ttl.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// Dir returns the directory holding all synkronus caches.
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", config.ConfigDirName, dirName), nil
+}
+
+// Open returns the cache named name in Dir with entries that expire after ttl.
+func Open(name string, ttl time.Duration) (*Cache, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return New(filepath.Join(dir, name), ttl), nil
+}
+
+// Clear deletes every synkronus cache.
+func Clear() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// Scoped returns a cache sharing c's directory whose keys don't collide with
+// those of other scopes, e.g. to keep results of different profiles apart.
+func (c *Cache) Scoped(scope string) *Cache {
+	if c == nil {
+		return nil
+	}
+	scoped := *c
+	scoped.scope = scope
+	return &scoped
+}
+
+// Invalidate deletes every entry in c's directory, across all scopes.
+func (c *Cache) Invalidate() error {
+	if c == nil {
+		return nil
+	}
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to invalidate cache: %w", err)
+	}
+	return nil
+}
+
+// Get decodes the value cached under key into v. It reports false if there is
+// no entry, the entry has expired or can't be decoded, or ctx asks to bypass
+// the cache.
+func (c *Cache) Get(ctx context.Context, key string, v any) bool {
+	if c == nil || Bypassed(ctx) {
+		return false
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	if c.now().Sub(e.StoredAt) > c.ttl {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Put caches v under key. Bypassing the cache only skips reads, so a
+// --no-cache run still refreshes the entry for later runs.
+func (c *Cache) Put(key string, v any) error {
+	if c == nil {
+		return nil
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	data, err := json.Marshal(entry{StoredAt: c.now().UTC(), Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, config.ConfigDirPermissions); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file and rename it, so concurrent runs never read a partial entry
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha1.Sum([]byte(c.scope + "|" + key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+type bypassKey struct{}
+
+// WithBypass returns a context that makes Get miss, forcing fresh results.
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// Bypassed reports whether ctx was created by WithBypass.
+func Bypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestCache(t *testing.T, now *time.Time) *Cache {
+	t.Helper()
+	c := New(t.TempDir(), 15*time.Minute)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestCache_PutThenGet(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestCache(t, &now)
+
+	if err := c.Put("gcp/p1", map[string]int64{"a": 10, "b": 20}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	var got map[string]int64
+	if !c.Get(context.Background(), "gcp/p1", &got) {
+		t.Fatal("expected a cache hit")
+	}
+	if got["a"] != 10 || got["b"] != 20 {
+		t.Errorf("got %v, want a=10 b=20", got)
+	}
+}
+
+func TestCache_Get(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		advance time.Duration
+		ctx     context.Context
+		want    bool
+	}{
+		{name: "fresh entry", key: "gcp/p1", advance: 14 * time.Minute, ctx: context.Background(), want: true},
+		{name: "expired entry", key: "gcp/p1", advance: 16 * time.Minute, ctx: context.Background(), want: false},
+		{name: "missing key", key: "gcp/p2", ctx: context.Background(), want: false},
+		{name: "bypassed", key: "gcp/p1", ctx: WithBypass(context.Background()), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			c := newTestCache(t, &now)
+			if err := c.Put("gcp/p1", map[string]int64{"a": 1}); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			now = now.Add(tt.advance)
+
+			var got map[string]int64
+			if hit := c.Get(tt.ctx, tt.key, &got); hit != tt.want {
+				t.Errorf("Get() hit = %v, want %v", hit, tt.want)
+			}
+		})
+	}
+}
+
+func TestCache_CorruptEntryIsAMiss(t *testing.T) {
+	now := time.Now()
+	c := newTestCache(t, &now)
+	if err := os.WriteFile(c.path("gcp/p1"), []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]int64
+	if c.Get(context.Background(), "gcp/p1", &got) {
+		t.Error("expected a corrupt entry to be a miss")
+	}
+}
+
+func TestCache_NilCacheIsANoOp(t *testing.T) {
+	var c *Cache
+	if err := c.Put("gcp/p1", 1); err != nil {
+		t.Errorf("Put on nil cache: %v", err)
+	}
+	var got int
+	if c.Get(context.Background(), "gcp/p1", &got) {
+		t.Error("expected nil cache to miss")
+	}
+}
+
+func TestCache_FilesAreOwnerOnly(t *testing.T) {
+	now := time.Now()
+	c := newTestCache(t, &now)
+	if err := c.Put("gcp/p1", 1); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	info, err := os.Stat(c.path("gcp/p1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("cache file mode = %04o, want 0600", mode)
+	}
+}
+
+func TestCache_ScopesDoNotShareEntries(t *testing.T) {
+	now := time.Now()
+	c := newTestCache(t, &now)
+	prod, dev := c.Scoped("prod"), c.Scoped("dev")
+
+	if err := prod.Put("list-buckets", []string{"prod-bucket"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	var got []string
+	if dev.Get(context.Background(), "list-buckets", &got) {
+		t.Errorf("expected a miss in another scope, got %v", got)
+	}
+	if !prod.Get(context.Background(), "list-buckets", &got) || got[0] != "prod-bucket" {
+		t.Errorf("expected a hit in the same scope, got %v", got)
+	}
+}
+
+func TestCache_InvalidateDropsAllScopes(t *testing.T) {
+	now := time.Now()
+	c := newTestCache(t, &now)
+	for _, scope := range []string{"prod", "dev"} {
+		if err := c.Scoped(scope).Put("k", 1); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	if err := c.Scoped("prod").Invalidate(); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+
+	var got int
+	for _, scope := range []string{"prod", "dev"} {
+		if c.Scoped(scope).Get(context.Background(), "k", &got) {
+			t.Errorf("expected scope %s to be invalidated", scope)
+		}
+	}
+	// The cache keeps working after invalidation
+	if err := c.Put("k", 2); err != nil {
+		t.Errorf("Put after Invalidate: %v", err)
+	}
+}
+
+func TestClear_RemovesAllCaches(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, name := range []string{"metrics", "responses"} {
+		c, err := Open(name, time.Minute)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", name, err)
+		}
+		if err := c.Put("k", 1); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	if err := Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, stat error: %v", dir, err)
+	}
+	// Clearing an already empty cache is not an error
+	if err := Clear(); err != nil {
+		t.Errorf("second Clear: %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+// Package cleanup finds the objects and object versions of a bucket that only
+// clutter it or take up space, such as S3 delete markers left with no version
+// to hide or empty "directory" placeholders, and deletes them. Finding them is separate from deleting them, so
+// what would be deleted can be reviewed first.
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Candidate is an object, or an object version, a cleanup would delete.
+type Candidate struct {
+	Key string `json:"key" yaml:"key"`
+	// VersionID is empty for an object, whose current version is deleted
+	VersionID    string    `json:"version_id,omitempty" yaml:"version_id,omitempty"`
+	Size         int64     `json:"size" yaml:"size"`
+	LastModified time.Time `json:"last_modified" yaml:"last_modified"`
+}
+
+// Plan is what a cleanup of a bucket would delete.
+type Plan struct {
+	Provider   string      `json:"provider" yaml:"provider"`
+	Bucket     string      `json:"bucket" yaml:"bucket"`
+	Prefix     string      `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Candidates []Candidate `json:"candidates" yaml:"candidates"`
+	// Bytes is the space deleting the candidates reclaims
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+}
+
+func newPlan(provider, bucket, prefix string) Plan {
+	return Plan{Provider: strings.ToLower(provider), Bucket: bucket, Prefix: prefix, Candidates: []Candidate{}}
+}
+
+func (p *Plan) add(v storage.ObjectVersion) {
+	p.Candidates = append(p.Candidates, Candidate{Key: v.Key, VersionID: v.VersionID, Size: v.Size, LastModified: v.LastModified})
+	p.Bytes += v.Size
+}
+
+// VersionSource is the subset of the storage service finding versions needs.
+type VersionSource interface {
+	WalkObjectVersions(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.ObjectVersion) error) error
+}
+
+// walkKeys calls fn with the versions of each key under prefix, newest first.
+func walkKeys(ctx context.Context, src VersionSource, provider, bucket, prefix string, fn func([]storage.ObjectVersion)) error {
+	var versions []storage.ObjectVersion
+	err := src.WalkObjectVersions(ctx, bucket, provider, prefix, func(v storage.ObjectVersion) error {
+		if len(versions) > 0 && versions[0].Key != v.Key {
+			fn(versions)
+			versions = nil
+		}
+		versions = append(versions, v)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 {
+		fn(versions)
+	}
+	return nil
+}
+
+// DeleteMarkers finds the S3 delete markers under prefix whose key has no
+// version left for them to hide, e.g. after the versions expired. They hold
+// no data, but each is still listed and slows down version listings.
+func DeleteMarkers(ctx context.Context, src VersionSource, provider, bucket, prefix string) (Plan, error) {
+	plan := newPlan(provider, bucket, prefix)
+	err := walkKeys(ctx, src, provider, bucket, prefix, func(versions []storage.ObjectVersion) {
+		for _, v := range versions {
+			if !v.DeleteMarker {
+				return
+			}
+		}
+		for _, v := range versions {
+			plan.add(v)
+		}
+	})
+	if err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// NoncurrentVersions finds the noncurrent versions under prefix that have been
+// noncurrent for longer than olderThan, keeping the keep newest noncurrent
+// versions of each object whatever their age, as S3's
+// NoncurrentVersionExpiration does. Current versions and delete markers are
+// never candidates.
+func NoncurrentVersions(ctx context.Context, src VersionSource, provider, bucket, prefix string, olderThan time.Duration, keep int) (Plan, error) {
+	if olderThan < 0 || keep < 0 {
+		return Plan{}, fmt.Errorf("age and versions to keep must not be negative, got %v and %d", olderThan, keep)
+	}
+	plan := newPlan(provider, bucket, prefix)
+	cutoff := time.Now().Add(-olderThan)
+	err := walkKeys(ctx, src, provider, bucket, prefix, func(versions []storage.ObjectVersion) {
+		noncurrent := 0
+		for i, v := range versions {
+			if v.IsLatest || v.DeleteMarker {
+				continue
+			}
+			noncurrent++
+			if noncurrent <= keep {
+				continue
+			}
+			since := v.NoncurrentSince
+			if since.IsZero() && i > 0 {
+				since = versions[i-1].LastModified
+			}
+			if !since.IsZero() && since.Before(cutoff) {
+				plan.add(v)
+			}
+		}
+	})
+	if err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// ObjectSource is the subset of the storage service finding objects needs.
+type ObjectSource interface {
+	WalkObjects(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.Object) error) error
+}
+
+// EmptyObjects finds the zero-byte objects under prefix: the "directory"
+// placeholders consoles create for folders (keys ending in "/") and, unless
+// placeholdersOnly, empty files too.
+func EmptyObjects(ctx context.Context, src ObjectSource, provider, bucket, prefix string, placeholdersOnly bool) (Plan, error) {
+	plan := newPlan(provider, bucket, prefix)
+	err := src.WalkObjects(ctx, bucket, provider, prefix, func(obj storage.Object) error {
+		if obj.Size != 0 || (placeholdersOnly && !strings.HasSuffix(obj.Key, "/")) {
+			return nil
+		}
+		plan.Candidates = append(plan.Candidates, Candidate{Key: obj.Key, LastModified: obj.LastModified})
+		return nil
+	})
+	if err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// Deleter is the subset of the storage service a cleanup needs.
+type Deleter interface {
+	DeleteObject(ctx context.Context, bucketName, objectKey, providerName string) error
+	DeleteObjectVersion(ctx context.Context, bucketName, objectKey, versionID, providerName string) error
+}
+
+// Status is the outcome of deleting a candidate.
+type Status string
+
+const (
+	StatusDeleted Status = "deleted"
+	StatusFailed  Status = "failed"
+	// StatusNotRun is a candidate left over when the cleanup was interrupted
+	StatusNotRun Status = "not-run"
+)
+
+// Result is the outcome of deleting one candidate.
+type Result struct {
+	Key       string `json:"key" yaml:"key"`
+	VersionID string `json:"version_id,omitempty" yaml:"version_id,omitempty"`
+	Status    Status `json:"status" yaml:"status"`
+	Error     string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Report is the outcome of a cleanup, with a result per candidate in plan
+// order.
+type Report struct {
+	Results []Result `json:"results" yaml:"results"`
+	Deleted int      `json:"deleted" yaml:"deleted"`
+	Failed  int      `json:"failed" yaml:"failed"`
+	NotRun  int      `json:"not_run" yaml:"not_run"`
+	// Bytes is the space the deleted candidates took up
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+}
+
+// Run deletes the candidates of plan, parallel at a time. A failed deletion
+// does not stop the others; it is reported, and the returned error counts the
+// failures. When ctx is canceled, the candidates not yet started are reported
+// as not run and the context's error is returned.
+func Run(ctx context.Context, deleter Deleter, plan Plan, parallel int) (Report, error) {
+	if parallel <= 0 {
+		return Report{}, fmt.Errorf("parallelism must be positive, got %d", parallel)
+	}
+
+	report := Report{Results: make([]Result, len(plan.Candidates))}
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(parallel, len(plan.Candidates)) {
+		wg.Go(func() {
+			for i := range work {
+				c := plan.Candidates[i]
+				result := Result{Key: c.Key, VersionID: c.VersionID, Status: StatusNotRun}
+				if ctx.Err() == nil {
+					result.Status = StatusDeleted
+					var err error
+					if c.VersionID == "" {
+						err = deleter.DeleteObject(ctx, plan.Bucket, c.Key, plan.Provider)
+					} else {
+						err = deleter.DeleteObjectVersion(ctx, plan.Bucket, c.Key, c.VersionID, plan.Provider)
+					}
+					if err != nil {
+						result.Status, result.Error = StatusFailed, err.Error()
+					}
+				}
+				// Each worker writes its own index, so no lock is needed
+				report.Results[i] = result
+			}
+		})
+	}
+	for i := range plan.Candidates {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for i, r := range report.Results {
+		switch r.Status {
+		case StatusDeleted:
+			report.Deleted++
+			report.Bytes += plan.Candidates[i].Size
+		case StatusFailed:
+			report.Failed++
+		case StatusNotRun:
+			report.NotRun++
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if report.Failed > 0 {
+		return report, fmt.Errorf("%d of %d deletions failed", report.Failed, len(plan.Candidates))
+	}
+	return report, nil
+}
@@ -0,0 +1,183 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeVersions []storage.ObjectVersion
+
+func (f fakeVersions) WalkObjectVersions(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.ObjectVersion) error) error {
+	for _, v := range f {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeDeleter struct {
+	mu      sync.Mutex
+	deleted []string
+	fail    string
+}
+
+func (f *fakeDeleter) DeleteObject(ctx context.Context, bucketName, objectKey, providerName string) error {
+	return f.DeleteObjectVersion(ctx, bucketName, objectKey, "", providerName)
+}
+
+func (f *fakeDeleter) DeleteObjectVersion(ctx context.Context, bucketName, objectKey, versionID, providerName string) error {
+	if versionID == f.fail {
+		return errors.New("access denied")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if versionID != "" {
+		objectKey += "#" + versionID
+	}
+	f.deleted = append(f.deleted, objectKey)
+	return nil
+}
+
+func TestDeleteMarkers(t *testing.T) {
+	src := fakeVersions{
+		{Key: "a.txt", VersionID: "a2", DeleteMarker: true, IsLatest: true},
+		{Key: "a.txt", VersionID: "a1", Size: 5},
+		{Key: "b.txt", VersionID: "b2", DeleteMarker: true, IsLatest: true},
+		{Key: "b.txt", VersionID: "b1", DeleteMarker: true},
+		{Key: "c.txt", VersionID: "c1", Size: 3, IsLatest: true},
+	}
+	plan, err := DeleteMarkers(context.Background(), src, "AWS", "data", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, c := range plan.Candidates {
+		ids = append(ids, c.VersionID)
+	}
+	if !slices.Equal(ids, []string{"b2", "b1"}) {
+		t.Errorf("expected only the markers of b.txt, which has no versions, got %v", ids)
+	}
+	if plan.Provider != "aws" || plan.Bytes != 0 {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+}
+
+func TestNoncurrentVersions(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.Add(-time.Duration(days) * 24 * time.Hour) }
+	src := fakeVersions{
+		// S3: noncurrent from the time the next newer version was written
+		{Key: "a.txt", VersionID: "a5", LastModified: daysAgo(1), IsLatest: true},
+		{Key: "a.txt", VersionID: "a4", Size: 4, LastModified: daysAgo(200)},
+		{Key: "a.txt", VersionID: "a3", Size: 3, LastModified: daysAgo(300)},
+		{Key: "a.txt", VersionID: "a2", Size: 2, LastModified: daysAgo(400)},
+		{Key: "a.txt", VersionID: "a1", Size: 1, LastModified: daysAgo(500)},
+		// Cloud Storage: a deleted object has no current generation
+		{Key: "b.txt", VersionID: "2", Size: 20, LastModified: daysAgo(400), NoncurrentSince: daysAgo(10)},
+		{Key: "b.txt", VersionID: "1", Size: 10, LastModified: daysAgo(500), NoncurrentSince: daysAgo(400)},
+	}
+
+	plan, err := NoncurrentVersions(context.Background(), src, "aws", "data", "", 90*24*time.Hour, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, c := range plan.Candidates {
+		ids = append(ids, c.Key+"#"+c.VersionID)
+	}
+	// a4 is kept as the newest noncurrent version, a3 only went noncurrent
+	// when a4 was written 200 days ago, and b.txt#2 is the one kept
+	if !slices.Equal(ids, []string{"a.txt#a3", "a.txt#a2", "a.txt#a1", "b.txt#1"}) {
+		t.Errorf("unexpected candidates: %v", ids)
+	}
+	if plan.Bytes != 3+2+1+10 {
+		t.Errorf("expected %d bytes reclaimable, got %d", 16, plan.Bytes)
+	}
+
+	plan, err = NoncurrentVersions(context.Background(), src, "aws", "data", "", 250*24*time.Hour, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Candidates) != 3 {
+		t.Errorf("expected the versions noncurrent for over 250 days, got %+v", plan.Candidates)
+	}
+
+	if _, err := NoncurrentVersions(context.Background(), src, "aws", "data", "", 0, -1); err == nil {
+		t.Error("expected an error for a negative number of versions to keep")
+	}
+}
+
+type fakeObjects []storage.Object
+
+func (f fakeObjects) WalkObjects(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.Object) error) error {
+	for _, obj := range f {
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestEmptyObjects(t *testing.T) {
+	src := fakeObjects{
+		{Key: "logs/"},
+		{Key: "logs/app.log", Size: 10},
+		{Key: "logs/empty.log"},
+		{Key: "logs/2024/"},
+	}
+	for _, tt := range []struct {
+		placeholdersOnly bool
+		want             []string
+	}{
+		{false, []string{"logs/", "logs/empty.log", "logs/2024/"}},
+		{true, []string{"logs/", "logs/2024/"}},
+	} {
+		plan, err := EmptyObjects(context.Background(), src, "gcp", "data", "logs/", tt.placeholdersOnly)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var keys []string
+		for _, c := range plan.Candidates {
+			keys = append(keys, c.Key)
+		}
+		if !slices.Equal(keys, tt.want) {
+			t.Errorf("placeholders only %v: got %v, want %v", tt.placeholdersOnly, keys, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	plan := Plan{Provider: "aws", Bucket: "data", Candidates: []Candidate{
+		{Key: "a.txt", VersionID: "a1", Size: 5},
+		{Key: "b.txt", VersionID: "b1", Size: 7},
+	}}
+	deleter := &fakeDeleter{fail: "b1"}
+	plan.Candidates = append(plan.Candidates, Candidate{Key: "dir/"})
+	report, err := Run(context.Background(), deleter, plan, 4)
+	if err == nil {
+		t.Fatal("expected an error counting the failure")
+	}
+	if report.Deleted != 2 || report.Failed != 1 || report.Bytes != 5 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	slices.Sort(deleter.deleted)
+	if !slices.Equal(deleter.deleted, []string{"a.txt#a1", "dir/"}) {
+		t.Errorf("expected a version and an object deleted, got %v", deleter.deleted)
+	}
+	if report.Results[1].Status != StatusFailed || report.Results[1].Error == "" {
+		t.Errorf("expected the failure reported in plan order, got %+v", report.Results)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err = Run(ctx, &fakeDeleter{}, plan, 1)
+	if !errors.Is(err, context.Canceled) || report.NotRun != 3 {
+		t.Errorf("expected every candidate not run after cancellation, got %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// CommandAliasKey is the config section holding user-defined command aliases
+const CommandAliasKey = "alias"
+
+// bucketSchemes maps URL schemes accepted in alias targets to provider names.
+var bucketSchemes = map[string]string{
+	"gs": "gcp",
+	"s3": "aws",
+}
+
+// BucketScheme returns the URL scheme of provider's buckets (e.g., "gs" for
+// gcp). ok is false for providers without one.
+func BucketScheme(provider string) (scheme string, ok bool) {
+	for scheme, p := range bucketSchemes {
+		if strings.EqualFold(p, provider) {
+			return scheme, true
+		}
+	}
+	return "", false
+}
+
+// BucketRef is a bucket reference resolved from a bucket alias.
+type BucketRef struct {
+	Bucket string
+	// Provider is inferred from the target's scheme (empty for bare bucket names)
+	Provider string
+	// Path is the object path following the alias, joined with any prefix in the target
+	Path string
+}
+
+// ParseBucketTarget parses an alias target such as "gs://bucket/prefix",
+// "s3://bucket", or a bare bucket name.
+func ParseBucketTarget(target string) (BucketRef, error) {
+	var ref BucketRef
+	rest := target
+	if scheme, after, found := strings.Cut(target, "://"); found {
+		provider, ok := bucketSchemes[strings.ToLower(scheme)]
+		if !ok {
+			return BucketRef{}, fmt.Errorf("unsupported scheme %q in bucket alias target %q (use gs:// or s3://)", scheme, target)
+		}
+		ref.Provider = provider
+		rest = after
+	}
+
+	ref.Bucket, ref.Path, _ = strings.Cut(rest, "/")
+	if ref.Bucket == "" {
+		return BucketRef{}, fmt.Errorf("bucket alias target %q has no bucket name", target)
+	}
+	return ref, nil
+}
+
+// ResolveBucketAlias resolves ref of the form "alias" or "alias/path/to/object"
+// using the aliases section. ok is false if ref does not start with an alias.
+func (c *Config) ResolveBucketAlias(ref string) (BucketRef, bool, error) {
+	name, objectPath, _ := strings.Cut(ref, "/")
+	target, ok := c.Aliases[strings.ToLower(name)]
+	if !ok {
+		return BucketRef{}, false, nil
+	}
+
+	resolved, err := ParseBucketTarget(target)
+	if err != nil {
+		return BucketRef{}, false, err
+	}
+	if objectPath != "" {
+		// path.Join would drop a trailing slash that marks a prefix
+		resolved.Path = strings.TrimPrefix(path.Join(resolved.Path, objectPath), "/")
+		if strings.HasSuffix(objectPath, "/") {
+			resolved.Path += "/"
+		}
+	}
+	return resolved, true, nil
+}
+
+// CommandAliases returns the command aliases (alias.<name>) of the global config.
+func (cm *ConfigManager) CommandAliases() map[string]string {
+	section, _ := cm.effectiveSettings()[CommandAliasKey].(map[string]any)
+	aliases := make(map[string]string, len(section))
+	for name, expansion := range section {
+		if s, ok := expansion.(string); ok && s != "" {
+			aliases[name] = s
+		}
+	}
+	return aliases
+}
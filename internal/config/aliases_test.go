@@ -0,0 +1,78 @@
+package config
+
+import "testing"
+
+func TestResolveBucketAlias(t *testing.T) {
+	cfg := &Config{Aliases: map[string]string{
+		"data-lake": "gs://my-company-data-lake-prod",
+		"logs":      "s3://audit-logs/2025",
+		"plain":     "some-bucket",
+	}}
+
+	tests := []struct {
+		ref    string
+		want   BucketRef
+		wantOK bool
+	}{
+		{"data-lake/path/file.csv", BucketRef{Bucket: "my-company-data-lake-prod", Provider: "gcp", Path: "path/file.csv"}, true},
+		{"Data-Lake", BucketRef{Bucket: "my-company-data-lake-prod", Provider: "gcp"}, true},
+		{"logs/jan/", BucketRef{Bucket: "audit-logs", Provider: "aws", Path: "2025/jan/"}, true},
+		{"plain/key", BucketRef{Bucket: "some-bucket", Path: "key"}, true},
+		{"unknown/key", BucketRef{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, ok, err := cfg.ResolveBucketAlias(tt.ref)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ResolveBucketAlias(%q) = %+v, %v; want %+v, %v", tt.ref, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseBucketTarget_Invalid(t *testing.T) {
+	for _, target := range []string{"ftp://bucket", "gs://", "/path-only"} {
+		if _, err := ParseBucketTarget(target); err == nil {
+			t.Errorf("ParseBucketTarget(%q) expected error", target)
+		}
+	}
+}
+
+func TestSetValue_BucketAlias(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("aliases.data-lake", "gs://my-company-data-lake-prod"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cm.SetValue("aliases.broken", "ftp://nope"); err == nil {
+		t.Error("expected error for an unsupported alias target")
+	}
+}
+
+func TestCommandAliases(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("alias.lsb", "storage list-buckets -o json"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	aliases := cm.CommandAliases()
+	if aliases["lsb"] != "storage list-buckets -o json" {
+		t.Errorf("CommandAliases() = %v", aliases)
+	}
+}
+
+func TestBucketScheme(t *testing.T) {
+	if scheme, ok := BucketScheme("GCP"); !ok || scheme != "gs" {
+		t.Errorf("expected gs for gcp, got %q, %v", scheme, ok)
+	}
+	if scheme, ok := BucketScheme("aws"); !ok || scheme != "s3" {
+		t.Errorf("expected s3 for aws, got %q, %v", scheme, ok)
+	}
+	if _, ok := BucketScheme("mock"); ok {
+		t.Error("expected no scheme for the mock provider")
+	}
+}
@@ -2,12 +2,20 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/retry"
+	"github.com/jkleinne/synkronus/internal/schedule"
+	"io/fs"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/go-viper/mapstructure/v2"
@@ -24,139 +32,472 @@ const (
 )
 
 type GCPConfig struct {
-	Project string `json:"project,omitempty" validate:"required"`
+	Project string `json:"project,omitempty" validate:"required_without=Projects"`
+	// Projects lists additional projects that list operations fan out across.
+	// Set as a comma-separated value (e.g., 'config set gcp.projects a,b').
+	Projects []string `json:"projects,omitempty" validate:"omitempty,dive,required"`
+	// CredentialsFile is the path to a service account key file. When empty,
+	// Application Default Credentials are used.
+	CredentialsFile string `json:"credentials_file,omitempty" mapstructure:"credentials_file" validate:"omitempty,file"`
+	// ImpersonateServiceAccount is the email of a service account to impersonate
+	// using the base credentials
+	ImpersonateServiceAccount string `json:"impersonate_service_account,omitempty" mapstructure:"impersonate_service_account" validate:"omitempty,email"`
+	// StorageEndpoint points the Cloud Storage client at an emulator such as
+	// fake-gcs-server (e.g., http://localhost:4443/storage/v1/) or a private
+	// endpoint. Requests to plain HTTP or loopback endpoints are unauthenticated.
+	StorageEndpoint string `json:"storage_endpoint,omitempty" mapstructure:"storage_endpoint" validate:"omitempty,url"`
 }
 
 type AWSConfig struct {
-	Region   string `json:"region,omitempty" validate:"required"`
-	Endpoint string `json:"endpoint,omitempty" validate:"omitempty,uri"`
+	Region string `json:"region,omitempty" validate:"required"`
+	// EndpointURL points the S3 client at an S3-compatible service such as
+	// LocalStack or MinIO instead of AWS
+	EndpointURL string `json:"endpoint_url,omitempty" mapstructure:"endpoint_url" validate:"omitempty,uri"`
+	// Profile selects a named profile from the shared AWS config/credentials files
+	Profile string `json:"profile,omitempty"`
+	// Static credentials take precedence over Profile and the default credential chain.
+	// They are checked as a pair when the provider is initialized, since config set
+	// writes one key at a time. Store the secret parts with 'config set --keychain'
+	// to keep them out of the config file.
+	AccessKeyID     string `json:"access_key_id,omitempty" mapstructure:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key,omitempty" mapstructure:"secret_access_key"`
+	SessionToken    string `json:"session_token,omitempty" mapstructure:"session_token"`
+	// AssumeRoles lists IAM role ARNs in other accounts. When set, bucket listing
+	// assumes each role and aggregates the results across those accounts.
+	AssumeRoles []string `json:"assume_roles,omitempty" mapstructure:"assume_roles" validate:"omitempty,dive,startswith=arn:aws"`
+	// SSO settings identify an IAM Identity Center role. When set, credentials come
+	// from the cache written by 'synkronus auth aws-sso' instead of Profile or the
+	// default chain; static credentials still take precedence.
+	SSOStartURL  string `json:"sso_start_url,omitempty" mapstructure:"sso_start_url" validate:"omitempty,url"`
+	SSORegion    string `json:"sso_region,omitempty" mapstructure:"sso_region"`
+	SSOAccountID string `json:"sso_account_id,omitempty" mapstructure:"sso_account_id" validate:"omitempty,len=12,numeric"`
+	SSORoleName  string `json:"sso_role_name,omitempty" mapstructure:"sso_role_name"`
+}
+
+// UsesSSO reports whether any IAM Identity Center setting is present.
+func (c *AWSConfig) UsesSSO() bool {
+	return c.SSOStartURL != "" || c.SSOAccountID != "" || c.SSORoleName != ""
+}
+
+// MockConfig enables the in-memory "mock" storage provider, which serves the
+// same fake buckets and objects on every run and forgets changes on exit.
+type MockConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// NetworkConfig holds settings applied to every provider HTTP client, for
+// networks that require a proxy or intercept TLS.
+type NetworkConfig struct {
+	// Proxy is used for HTTP and HTTPS requests instead of HTTP(S)_PROXY
+	Proxy string `json:"proxy,omitempty" validate:"omitempty,url"`
+	// NoProxy lists hosts, domain suffixes, or CIDRs that bypass Proxy
+	NoProxy []string `json:"no_proxy,omitempty" mapstructure:"no_proxy"`
+	// CABundle is a PEM file of CAs to trust in addition to the system roots
+	CABundle string `json:"ca_bundle,omitempty" mapstructure:"ca_bundle" validate:"omitempty,file"`
+}
+
+// DefaultCacheTTL is how long cached responses are served when cache.ttl is not set.
+const DefaultCacheTTL = 5 * time.Minute
+
+// CacheConfig enables the on-disk cache of list and describe results, which
+// speeds up exploring large estates at the cost of results up to TTL old.
+type CacheConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// TTL is how long cached responses are served, as a duration (e.g., "10m")
+	TTL string `json:"ttl,omitempty" validate:"omitempty,duration"`
+}
+
+// ResponseTTL returns the configured TTL, or DefaultCacheTTL if unset.
+func (c *CacheConfig) ResponseTTL() time.Duration {
+	if ttl, err := time.ParseDuration(c.TTL); err == nil {
+		return ttl
+	}
+	return DefaultCacheTTL
+}
+
+// RetryConfig controls retries of provider calls that fail with transient
+// errors (throttling, server errors, dropped connections).
+type RetryConfig struct {
+	// MaxRetries is how many times a failed call is retried; 0 disables retries.
+	// Unset means the default (see retry.DefaultMaxRetries). The maximum is
+	// retry.MaxMaxRetries.
+	MaxRetries *int `json:"max_retries,omitempty" mapstructure:"max_retries" validate:"omitempty,min=0,max=10"`
+}
+
+// MaxRetries returns retry.max_retries, or retry.DefaultMaxRetries if unset.
+func (c *Config) MaxRetries() int {
+	if c.Retry == nil || c.Retry.MaxRetries == nil {
+		return retry.DefaultMaxRetries
+	}
+	return *c.Retry.MaxRetries
+}
+
+// FanOutConfig bounds commands that query several providers at once (e.g.,
+// listing buckets), so one slow provider does not stall the whole command.
+type FanOutConfig struct {
+	// ProviderTimeout bounds each provider's share, as a duration (e.g., "30s").
+	// Providers that exceed it are reported as failed; unset means no limit.
+	ProviderTimeout string `json:"provider_timeout,omitempty" mapstructure:"provider_timeout" validate:"omitempty,duration"`
+	// Concurrency is how many providers are queried at once; unset means all
+	Concurrency int `json:"concurrency,omitempty" validate:"omitempty,min=1"`
+}
+
+// Timeout returns the configured provider timeout, or 0 if unset.
+func (c *FanOutConfig) Timeout() time.Duration {
+	timeout, _ := time.ParseDuration(c.ProviderTimeout)
+	return timeout
+}
+
+// LogConfig controls where logs are written.
+type LogConfig struct {
+	// File receives logs instead of stderr, rotated as it grows
+	File string `json:"file,omitempty"`
+	// Format is "text" (the default) or "json", one object per record
+	Format string `json:"format,omitempty" validate:"omitempty,oneof=text json"`
+}
+
+// MetricsConfig controls the Prometheus metrics endpoint served by long-running
+// modes (the TUI and 'serve').
+type MetricsConfig struct {
+	// Address to serve /metrics on (e.g., ":9464"); unset serves nothing
+	Address string `json:"address,omitempty" validate:"omitempty,hostname_port"`
+}
+
+// ServerConfig configures the REST and gRPC APIs of 'synkronus serve'.
+type ServerConfig struct {
+	// Address to listen on (e.g., ":8080"); defaults to localhost:8080
+	Address string `json:"address,omitempty" validate:"omitempty,hostname_port"`
+	// GRPCAddress, when set, also serves the gRPC API on this address (e.g., ":9090")
+	GRPCAddress string `json:"grpc_address,omitempty" mapstructure:"grpc_address" validate:"omitempty,hostname_port"`
+	// JobsFile is a YAML file of scheduled sync jobs, run in addition to the
+	// jobs section (see LoadJobsFile)
+	JobsFile string `json:"jobs_file,omitempty" mapstructure:"jobs_file" validate:"omitempty,file"`
+	// Token that API clients must send as a bearer token. Store it with
+	// 'config set --keychain server.token <token>' to keep it out of the file.
+	Token string `json:"token,omitempty"`
+}
+
+// DefaultsConfig holds values applied to command flags that were not passed
+// on the command line. Explicit flags always win.
+type DefaultsConfig struct {
+	Output string `json:"output,omitempty" validate:"omitempty,oneof=table json yaml"`
+	// Provider is used by commands that take a single --provider
+	Provider string `json:"provider,omitempty"`
+	// Providers is used by commands that take --providers (e.g., list commands)
+	Providers []string `json:"providers,omitempty" validate:"omitempty,dive,required"`
+	// Confirm set to false skips confirmation prompts, as if --force were passed
+	Confirm *bool `json:"confirm,omitempty"`
+	// ConfirmModes sets how each destructive operation is confirmed: "typed"
+	// (type the resource name) or "yes-no" (e.g., defaults.confirm_modes.delete-object = typed)
+	ConfirmModes map[string]string `json:"confirm_modes,omitempty" mapstructure:"confirm_modes" validate:"omitempty,dive,keys,oneof=delete-bucket delete-object cleanup,endkeys,oneof=typed yes-no"`
+	// ConfirmTimeout aborts an operation whose confirmation prompt goes
+	// unanswered for this long, as a duration (e.g., "2m"); unset waits forever
+	ConfirmTimeout string `json:"confirm_timeout,omitempty" mapstructure:"confirm_timeout" validate:"omitempty,duration"`
+}
+
+// ConfirmationTimeout returns the configured confirmation timeout, or 0 if unset.
+func (c *DefaultsConfig) ConfirmationTimeout() time.Duration {
+	timeout, _ := time.ParseDuration(c.ConfirmTimeout)
+	return timeout
 }
 
 type Config struct {
+	// Version is the schema version of the config file (see migrate.go)
+	Version int `json:"version,omitempty"`
+
 	GCP *GCPConfig `json:"gcp,omitempty" validate:"omitempty"`
 	AWS *AWSConfig `json:"aws,omitempty" validate:"omitempty"`
+	// Mock enables the in-memory demo provider alongside the real ones
+	Mock *MockConfig `json:"mock,omitempty" validate:"omitempty"`
+
+	Network  *NetworkConfig  `json:"network,omitempty" validate:"omitempty"`
+	Cache    *CacheConfig    `json:"cache,omitempty" validate:"omitempty"`
+	Retry    *RetryConfig    `json:"retry,omitempty" validate:"omitempty"`
+	FanOut   *FanOutConfig   `json:"fanout,omitempty" validate:"omitempty"`
+	Log      *LogConfig      `json:"log,omitempty" validate:"omitempty"`
+	Metrics  *MetricsConfig  `json:"metrics,omitempty" validate:"omitempty"`
+	Server   *ServerConfig   `json:"server,omitempty" validate:"omitempty"`
+	Defaults *DefaultsConfig `json:"defaults,omitempty" validate:"omitempty"`
+	// Jobs are syncs that 'synkronus serve' runs on a schedule (see jobs.go)
+	Jobs []SyncJob `json:"jobs,omitempty" validate:"omitempty,unique=Name,dive"`
+	// Notifications are sent when sync jobs run by 'synkronus serve' finish,
+	// keyed by notifier name (see notifications.go)
+	Notifications map[string]NotifierConfig `json:"notifications,omitempty" validate:"omitempty,dive"`
+	// Aliases maps short names to buckets (e.g., aliases.data-lake = gs://my-data-lake),
+	// so object commands accept "data-lake/path/file" (see aliases.go)
+	Aliases map[string]string `json:"aliases,omitempty" validate:"omitempty,dive,required,bucket_target"`
+	// MetadataTemplates are named content headers and metadata applied to
+	// uploads, keyed by template name (see metadata_templates.go)
+	MetadataTemplates map[string]MetadataTemplate `json:"metadata_templates,omitempty" mapstructure:"metadata_templates" validate:"omitempty,dive"`
+	// Alias maps command names to expansions (e.g., alias.lsb = "storage list-buckets -o json"),
+	// expanded by the root command before parsing, like git aliases
+	Alias map[string]string `json:"alias,omitempty" validate:"omitempty,dive,required"`
+
+	// ActiveProfile names the profile selected by 'synkronus profile use'.
+	// Empty means the top-level provider blocks are used.
+	ActiveProfile string `json:"active_profile,omitempty" mapstructure:"active_profile"`
+	// Profiles holds named sets of provider blocks (e.g., profiles.prod.gcp.project)
+	Profiles map[string]ProfileConfig `json:"profiles,omitempty" validate:"omitempty,dive"`
+
+	// Contexts pair a profile with default providers (see context.go)
+	Contexts map[string]ContextConfig `json:"contexts,omitempty" validate:"omitempty,dive"`
+	// CurrentContext names the context last applied with 'synkronus context use'
+	CurrentContext string `json:"current_context,omitempty" mapstructure:"current_context"`
 }
 
 // IsGCPConfigured returns true if the GCP configuration block is present
-// and the project ID is set. Used by GCP provider registration callbacks.
+// and at least one project ID is set. Used by GCP provider registration callbacks.
 func IsGCPConfigured(cfg *Config) bool {
-	return cfg.GCP != nil && cfg.GCP.Project != ""
+	return cfg.GCP != nil && len(cfg.GCP.AllProjects()) > 0
+}
+
+// AllProjects returns the deduplicated project IDs to operate on: Project
+// first (if set), followed by Projects in their configured order.
+func (c *GCPConfig) AllProjects() []string {
+	var projects []string
+	seen := make(map[string]bool)
+	for _, p := range append([]string{c.Project}, c.Projects...) {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		projects = append(projects, p)
+	}
+	return projects
+}
+
+// CredentialsSource describes the credentials the GCP clients authenticate with:
+// the service account key file if one is set, otherwise Application Default
+// Credentials.
+func (c *GCPConfig) CredentialsSource() string {
+	if c.CredentialsFile != "" {
+		return c.CredentialsFile
+	}
+	return "application default credentials"
+}
+
+// PrimaryProject returns the project used for single-project operations such
+// as creating buckets or SQL calls. Empty if no project is configured.
+func (c *GCPConfig) PrimaryProject() string {
+	if projects := c.AllProjects(); len(projects) > 0 {
+		return projects[0]
+	}
+	return ""
 }
 
 type ConfigManager struct {
 	v         *viper.Viper
 	validator *validator.Validate
+	secrets   SecretStore
+	// profileOverride is set by the global --profile flag and takes precedence
+	// over the active_profile value stored in the config file
+	profileOverride string
+	// impersonationOverride is set by the global --impersonate-service-account
+	// flag and replaces gcp.impersonate_service_account for this process
+	impersonationOverride string
+	// encryption is set when the config file is encrypted at rest (see crypt.go)
+	encryption *encryption
+	// local holds the project-local overrides read from localPath (see local.go)
+	local     map[string]any
+	localPath string
 }
 
 func NewConfigManager() (*ConfigManager, error) {
-	v := viper.New()
+	cm := &ConfigManager{
+		validator: newValidator(),
+		secrets:   osKeychain{},
+	}
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("error determining user home directory: %w", err)
+	if err := cm.reload(); err != nil {
+		return nil, err
 	}
 
-	configDir := filepath.Join(homeDir, ".config", ConfigDirName)
+	if err := cm.migrate(); err != nil {
+		return nil, err
+	}
 
-	v.SetConfigName("config")
-	v.SetConfigType("json")
-	v.AddConfigPath(configDir)
-	v.AddConfigPath(".")
-
-	if err := v.ReadInConfig(); err != nil {
-		// It's okay if the config file doesn't exist (first run), but other errors (e.g., parsing, permissions) must be reported
-		var notFound viper.ConfigFileNotFoundError
-		if !errors.As(err, &notFound) {
-			return nil, fmt.Errorf("error reading config file: %w", err)
+	if workDir, err := os.Getwd(); err == nil {
+		if path, found := findLocalConfig(workDir); found {
+			if cm.local, err = readLocalConfig(path); err != nil {
+				return nil, err
+			}
+			cm.localPath = path
 		}
 	}
 
-	return &ConfigManager{
-		v:         v,
-		validator: validator.New(),
-	}, nil
+	return cm, nil
 }
 
+// LoadConfig parses the global configuration with any project-local overrides
+// applied, resolves keychain references, and applies the active profile, so the
+// returned GCP and AWS blocks are the ones providers should use.
 func (cm *ConfigManager) LoadConfig() (*Config, error) {
+	settings, err := cm.resolveSecretRefs(cm.effectiveSettings(), "")
+	if err != nil {
+		return nil, err
+	}
+
 	var config Config
-	if err := cm.unmarshalStrict(&config); err != nil {
+	if err := decodeStrict(settings, &config); err != nil {
 		return nil, fmt.Errorf("error parsing config file: %w", err)
 	}
-	return &config, nil
+	cfg, err := config.withProfile(cm.ActiveProfile(), cm.profileOverride != "")
+	if err != nil {
+		return nil, err
+	}
+	if cm.impersonationOverride != "" && cfg.GCP != nil {
+		cfg.GCP.ImpersonateServiceAccount = cm.impersonationOverride
+	}
+	return cfg, nil
+}
+
+// SetImpersonationOverride makes GCP clients impersonate the given service
+// account for this process only, regardless of gcp.impersonate_service_account.
+// Used by the global --impersonate-service-account flag.
+func (cm *ConfigManager) SetImpersonationOverride(email string) error {
+	if err := cm.validator.Var(email, "email"); err != nil {
+		return fmt.Errorf("%w: invalid service account %q: must be an email address", ErrInvalidConfig, email)
+	}
+	cm.impersonationOverride = email
+	return nil
 }
 
 func (cm *ConfigManager) SaveConfig() error {
+	_, err := cm.writeConfigFile(cm.v.AllSettings())
+	return err
+}
+
+// reload reads the global config file into a fresh Viper instance, decrypting
+// it if needed. A missing file (first run) yields an empty configuration.
+func (cm *ConfigManager) reload() error {
 	configPath, err := cm.getPreferredConfigPath()
 	if err != nil {
 		return err
 	}
 
-	configDir := filepath.Dir(configPath)
-	// Ensure the directory exists with secure permissions (0700)
-	if err := os.MkdirAll(configDir, ConfigDirPermissions); err != nil {
-		return fmt.Errorf("error creating config directory: %w", err)
-	}
+	v := viper.New()
+	v.SetConfigType("json")
 
-	// Write the configuration file
-	if err := cm.v.WriteConfigAs(configPath); err != nil {
-		return fmt.Errorf("error writing config file: %w", err)
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		cm.v = v
+		cm.encryption = nil
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
 	}
 
-	// Explicitly set secure permissions (0600) on the file itself (Defense-in-Depth)
-	if err := os.Chmod(configPath, ConfigFilePermissions); err != nil {
-		return fmt.Errorf("error setting secure permissions on config file: %w", err)
+	encrypted := false
+	if env, ok := parseEnvelope(data); ok {
+		if data, err = cm.openConfig(env); err != nil {
+			return err
+		}
+		encrypted = true
+	}
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
 	}
 
+	cm.v = v
+	if !encrypted {
+		cm.encryption = nil
+	}
 	return nil
 }
 
+// SetValue sets key in the global config. A keychain entry the key referenced
+// before is removed once the new value is saved.
 func (cm *ConfigManager) SetValue(key, value string) error {
+	previous := cm.v.Get(key)
 	cm.v.Set(key, value)
 
 	var config Config
 	if err := cm.unmarshalStrict(&config); err != nil {
-		if revertErr := cm.v.ReadInConfig(); revertErr != nil {
+		if revertErr := cm.reload(); revertErr != nil {
 			return fmt.Errorf("%w (additionally, failed to revert config: %v)", err, revertErr)
 		}
 		return err
 	}
 
 	if err := cm.validateConfig(&config); err != nil {
-		if revertErr := cm.v.ReadInConfig(); revertErr != nil {
+		if revertErr := cm.reload(); revertErr != nil {
 			return fmt.Errorf("%w (additionally, failed to revert config: %v)", err, revertErr)
 		}
 		return err
 	}
 
-	return cm.SaveConfig()
+	if err := cm.SaveConfig(); err != nil {
+		return err
+	}
+	if previous == value {
+		return nil
+	}
+	return cm.deleteSecretRefs(previous)
 }
 
+// GetValue returns the effective value of key: the project-local override if
+// one sets it, otherwise the value from the global config.
 func (cm *ConfigManager) GetValue(key string) (string, bool) {
+	if raw, ok := lookupNestedKey(cm.local, strings.Split(key, ".")); ok {
+		value := formatSettingValue(raw)
+		return value, value != ""
+	}
+	return cm.globalValue(key)
+}
+
+// globalValue returns the value of key in the global config only.
+func (cm *ConfigManager) globalValue(key string) (string, bool) {
 	if !cm.v.IsSet(key) {
 		return "", false
 	}
 	value := cm.v.GetString(key)
+	if list, ok := cm.v.Get(key).([]any); ok {
+		value = formatSettingValue(list)
+	}
 	return value, value != ""
 }
 
+// formatSettingValue renders a raw setting for display. List values (e.g.,
+// gcp.projects) read from a file are reported in the same comma-separated form
+// that 'config set' accepts; nested blocks have no scalar value.
+func formatSettingValue(raw any) string {
+	switch v := raw.(type) {
+	case nil, map[string]any:
+		return ""
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprintf("%v", item)
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// DeleteValue clears key in the global config. Project-local overrides are
+// not affected.
 func (cm *ConfigManager) DeleteValue(key string) (bool, error) {
-	val, exists := cm.GetValue(key)
+	val, exists := cm.globalValue(key)
 	if !exists || val == "" {
 		return false, nil
 	}
+	previous := cm.v.Get(key)
 
 	cm.v.Set(key, "")
 
 	var config Config
 	if err := cm.unmarshalStrict(&config); err != nil {
-		if revertErr := cm.v.ReadInConfig(); revertErr != nil {
+		if revertErr := cm.reload(); revertErr != nil {
 			return false, fmt.Errorf("%w (additionally, failed to revert config: %v)", err, revertErr)
 		}
 		return false, fmt.Errorf("error parsing config after deletion: %w", err)
 	}
 
 	if err := cm.validateConfig(&config); err != nil {
-		if revertErr := cm.v.ReadInConfig(); revertErr != nil {
+		if revertErr := cm.reload(); revertErr != nil {
 			return false, fmt.Errorf("%w (additionally, failed to revert config: %v)", err, revertErr)
 		}
 		return false, fmt.Errorf("cannot delete key '%s': %w", key, err)
@@ -166,48 +507,86 @@ func (cm *ConfigManager) DeleteValue(key string) (bool, error) {
 		return false, err
 	}
 
+	if err := cm.deleteSecretRefs(previous); err != nil {
+		return true, err
+	}
 	return true, nil
 }
 
+// GetAllSettings returns the effective settings, including project-local
+// overrides. The schema version is bookkeeping and is left out.
 func (cm *ConfigManager) GetAllSettings() map[string]any {
-	return cm.v.AllSettings()
+	settings := cm.effectiveSettings()
+	delete(settings, VersionKey)
+	return settings
 }
 
-// RemoveProvider removes an entire provider block (e.g., "gcp", "aws") from the
-// configuration. This bypasses per-field validation since the entire block is
-// removed - there are no dangling required fields. Returns true if the provider
-// was configured and removed, false if it wasn't present.
+// RemoveProvider removes an entire provider block (e.g., "gcp", "aws", or a
+// profile block such as "profiles.prod.gcp") from the configuration. This
+// bypasses per-field validation since the entire block is removed - there are
+// no dangling required fields. Returns true if the provider was configured and
+// removed, false if it wasn't present.
 func (cm *ConfigManager) RemoveProvider(providerName string) (bool, error) {
 	providerName = strings.ToLower(providerName)
 
 	settings := cm.v.AllSettings()
-	if _, exists := settings[providerName]; !exists {
+	removed, ok := deleteNestedKey(settings, strings.Split(providerName, "."))
+	if !ok {
 		return false, nil
 	}
 
-	// Remove the provider key from settings and write a fresh config file.
-	delete(settings, providerName)
-
-	configPath, err := cm.writeConfigFile(settings)
-	if err != nil {
+	if err := cm.replaceSettings(settings); err != nil {
 		return false, err
 	}
 
-	// Viper doesn't support key deletion - ReadInConfig merges with
-	// existing state. Create a fresh Viper instance pointing at the same file.
-	freshViper := viper.New()
-	freshViper.SetConfigFile(configPath)
-	if err := freshViper.ReadInConfig(); err != nil {
-		return false, fmt.Errorf("failed to reload config after provider removal: %w", err)
+	if err := cm.deleteSecretRefs(removed); err != nil {
+		return true, err
 	}
-	cm.v = freshViper
-
 	return true, nil
 }
 
-// writeConfigFile marshals settings as indented JSON and writes the file at the
-// preferred config path, creating the directory with secure permissions if needed.
-// It returns the resolved config path on success.
+// replaceSettings writes settings as the entire config file and reloads it.
+// Viper doesn't support key deletion, so reload creates a fresh Viper instance
+// from the rewritten file.
+func (cm *ConfigManager) replaceSettings(settings map[string]any) error {
+	if _, err := cm.writeConfigFile(settings); err != nil {
+		return err
+	}
+	if err := cm.reload(); err != nil {
+		return fmt.Errorf("failed to reload config after rewriting it: %w", err)
+	}
+	return nil
+}
+
+// deleteNestedKey removes the key at path from settings, pruning parent maps
+// that become empty. Returns the removed value, or false if the path does not exist.
+func deleteNestedKey(settings map[string]any, path []string) (any, bool) {
+	value, exists := settings[path[0]]
+	if !exists {
+		return nil, false
+	}
+	if len(path) == 1 {
+		delete(settings, path[0])
+		return value, true
+	}
+
+	child, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	removed, ok := deleteNestedKey(child, path[1:])
+	if !ok {
+		return nil, false
+	}
+	if len(child) == 0 {
+		delete(settings, path[0])
+	}
+	return removed, true
+}
+
+// writeConfigFile marshals settings as indented JSON (encrypted if enabled) and
+// writes the file at the preferred config path, creating the directory with
+// secure permissions if needed. It returns the resolved config path on success.
 func (cm *ConfigManager) writeConfigFile(settings map[string]any) (string, error) {
 	configPath, err := cm.getPreferredConfigPath()
 	if err != nil {
@@ -219,14 +598,25 @@ func (cm *ConfigManager) writeConfigFile(settings map[string]any) (string, error
 		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	settings = maps.Clone(settings)
+	settings[VersionKey] = CurrentSchemaVersion
 	data, err := marshalJSON(settings)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal config: %w", err)
 	}
+	if cm.encryption != nil {
+		if data, err = cm.sealConfig(data); err != nil {
+			return "", fmt.Errorf("failed to encrypt config: %w", err)
+		}
+	}
 
 	if err := os.WriteFile(configPath, data, ConfigFilePermissions); err != nil {
 		return "", fmt.Errorf("failed to write config file: %w", err)
 	}
+	// WriteFile only applies the mode to new files; enforce it on existing ones too (Defense-in-Depth)
+	if err := os.Chmod(configPath, ConfigFilePermissions); err != nil {
+		return "", fmt.Errorf("error setting secure permissions on config file: %w", err)
+	}
 
 	return configPath, nil
 }
@@ -236,6 +626,11 @@ func marshalJSON(settings map[string]any) ([]byte, error) {
 	return json.MarshalIndent(settings, "", "  ")
 }
 
+// ConfigPath returns the path of the global config file, whether or not it exists.
+func (cm *ConfigManager) ConfigPath() (string, error) {
+	return cm.getPreferredConfigPath()
+}
+
 func (cm *ConfigManager) getPreferredConfigPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -247,25 +642,85 @@ func (cm *ConfigManager) getPreferredConfigPath() (string, error) {
 }
 
 func (cm *ConfigManager) unmarshalStrict(target any) error {
+	return decodeStrict(cm.v.AllSettings(), target)
+}
+
+// decodeStrict decodes settings into target, rejecting any keys that do not
+// map to a field of target.
+func decodeStrict(settings map[string]any, target any) error {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		Result:      target,
 		ErrorUnused: true,
+		// 'config set' stores every value as a string: lists comma-separated,
+		// booleans as "true"/"false", numbers in decimal, and cleared values as ""
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			emptyStringToZero,
+			mapstructure.StringToSliceHookFunc(","),
+			mapstructure.StringToBoolHookFunc(),
+			mapstructure.StringToIntHookFunc(),
+		),
 	})
 	if err != nil {
 		return fmt.Errorf("internal error: failed to create config decoder: %w", err)
 	}
 
-	if err := decoder.Decode(cm.v.AllSettings()); err != nil {
+	if err := decoder.Decode(settings); err != nil {
 		if strings.Contains(err.Error(), "invalid keys") || strings.Contains(err.Error(), "unused keys") {
-			return fmt.Errorf("unrecognized configuration key provided. Please use a valid key (e.g., 'gcp.project')")
+			return fmt.Errorf("%w: unrecognized configuration key provided. Please use a valid key (e.g., 'gcp.project')", ErrInvalidConfig)
 		}
 		return err
 	}
 	return nil
 }
 
+// emptyStringToZero decodes a cleared value ("") of a non-string field as its
+// zero value, so rules such as required_without treat a cleared list as missing
+// rather than empty-but-present, and a cleared boolean does not fail to parse.
+func emptyStringToZero(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() == reflect.String && to.Kind() != reflect.String && data == "" {
+		return reflect.Zero(to).Interface(), nil
+	}
+	return data, nil
+}
+
+// ErrInvalidConfig is wrapped by the errors for unknown keys and values that fail
+// validation, whether they come from the config file, 'config set' or a flag.
+var ErrInvalidConfig = errors.New("configuration validation failed")
+
+// newValidator returns a validator with the config-specific rules registered.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterValidation("bucket_target", func(fl validator.FieldLevel) bool {
+		_, err := ParseBucketTarget(fl.Field().String())
+		return err == nil
+	})
+	v.RegisterValidation("duration", func(fl validator.FieldLevel) bool {
+		d, err := time.ParseDuration(fl.Field().String())
+		return err == nil && d > 0
+	})
+	v.RegisterValidation("cron", func(fl validator.FieldLevel) bool {
+		_, err := schedule.Parse(fl.Field().String())
+		return err == nil
+	})
+	v.RegisterValidation("glob", func(fl validator.FieldLevel) bool {
+		_, err := path.Match(fl.Field().String(), "")
+		return err == nil
+	})
+	return v
+}
+
 func (cm *ConfigManager) validateConfig(config *Config) error {
-	err := cm.validator.Struct(config)
+	return validateWith(cm.validator, config)
+}
+
+// Validate checks config against the same rules as the config file, for
+// configs built in code rather than loaded.
+func Validate(config *Config) error {
+	return validateWith(newValidator(), config)
+}
+
+func validateWith(v *validator.Validate, config any) error {
+	err := v.Struct(config)
 	if err == nil {
 		return nil
 	}
@@ -277,7 +732,7 @@ func (cm *ConfigManager) validateConfig(config *Config) error {
 			namespace := strings.ToLower(fe.Namespace())
 			errs = append(errs, fmt.Sprintf("field '%s' is invalid (rule: %s)", namespace, fe.Tag()))
 		}
-		return fmt.Errorf("configuration validation failed: %s", strings.Join(errs, "; "))
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(errs, "; "))
 	}
 
 	return fmt.Errorf("invalid configuration: %w", err)
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func setupTestConfig(t *testing.T) (*ConfigManager, string) {
@@ -280,3 +283,364 @@ func TestLoadConfig_EmptyFile(t *testing.T) {
 		t.Fatal("expected error for empty config file, got nil")
 	}
 }
+
+// TestSetValue_CredentialFields verifies that the provider credential settings
+// are recognized keys and that their validation rules apply.
+func TestSetValue_CredentialFields(t *testing.T) {
+	cm, tmpDir := setupTestConfig(t)
+
+	keyFile := filepath.Join(tmpDir, "sa.json")
+	if err := os.WriteFile(keyFile, []byte("{}"), ConfigFilePermissions); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	// Ordered so that aws.region (required) is set before the other AWS keys
+	valid := [][2]string{
+		{"gcp.credentials_file", keyFile},
+		{"gcp.impersonate_service_account", "reader@my-project.iam.gserviceaccount.com"},
+		{"aws.region", "us-east-1"},
+		{"aws.profile", "audit"},
+		{"aws.access_key_id", "AKIAEXAMPLE"},
+		{"aws.secret_access_key", "secret"},
+		{"aws.sso_start_url", "https://my-org.awsapps.com/start"},
+		{"aws.sso_account_id", "123456789012"},
+		{"aws.sso_role_name", "ReadOnly"},
+	}
+	for _, kv := range valid {
+		if err := cm.SetValue(kv[0], kv[1]); err != nil {
+			t.Errorf("SetValue(%q) failed: %v", kv[0], err)
+		}
+	}
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.GCP.CredentialsFile != keyFile || cfg.AWS.AccessKeyID != "AKIAEXAMPLE" || cfg.AWS.Profile != "audit" {
+		t.Errorf("credential fields not loaded: gcp=%+v aws=%+v", cfg.GCP, cfg.AWS)
+	}
+
+	if err := cm.SetValue("gcp.credentials_file", filepath.Join(tmpDir, "missing.json")); err == nil {
+		t.Error("expected error for a credentials file that does not exist")
+	}
+	if err := cm.SetValue("gcp.impersonate_service_account", "not-an-email"); err == nil {
+		t.Error("expected error for an invalid service account email")
+	}
+	if err := cm.SetValue("aws.sso_account_id", "1234"); err == nil {
+		t.Error("expected error for an invalid SSO account ID")
+	}
+}
+
+func TestSetValue_GCPProjectsList(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("gcp.projects", "project-b,test-project,project-c"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if value, _ := cm.GetValue("gcp.projects"); value != "project-b,test-project,project-c" {
+		t.Errorf("GetValue = %q, want the comma-separated list", value)
+	}
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	want := []string{"test-project", "project-b", "project-c"}
+	if got := cfg.GCP.AllProjects(); !slices.Equal(got, want) {
+		t.Errorf("AllProjects() = %v, want %v", got, want)
+	}
+	if got := cfg.GCP.PrimaryProject(); got != "test-project" {
+		t.Errorf("PrimaryProject() = %q, want %q", got, "test-project")
+	}
+}
+
+func TestLoadConfig_GCPProjectsWithoutProject(t *testing.T) {
+	cm := setupProfileConfig(t, `{"gcp": {"projects": ["project-a", "project-b"]}}`)
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if !IsGCPConfigured(cfg) {
+		t.Error("expected GCP to be configured from the projects list alone")
+	}
+	if got := cfg.GCP.PrimaryProject(); got != "project-a" {
+		t.Errorf("PrimaryProject() = %q, want %q", got, "project-a")
+	}
+
+	if _, err := cm.DeleteValue("gcp.projects"); err == nil {
+		t.Error("expected error when removing the last configured project")
+	}
+}
+
+func TestSetValue_AWSAssumeRoles(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	if err := cm.SetValue("aws.region", "us-east-1"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+
+	roles := "arn:aws:iam::111111111111:role/Audit,arn:aws:iam::222222222222:role/Audit"
+	if err := cm.SetValue("aws.assume_roles", roles); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(cfg.AWS.AssumeRoles) != 2 {
+		t.Errorf("expected 2 roles, got %v", cfg.AWS.AssumeRoles)
+	}
+
+	if err := cm.SetValue("aws.assume_roles", "Audit"); err == nil {
+		t.Error("expected error for a value that is not an ARN")
+	}
+}
+
+func TestSetValue_Defaults(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	for _, kv := range [][2]string{{"defaults.output", "json"}, {"defaults.provider", "gcp"}, {"defaults.confirm", "false"}} {
+		if err := cm.SetValue(kv[0], kv[1]); err != nil {
+			t.Fatalf("SetValue(%q) failed: %v", kv[0], err)
+		}
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Defaults.Output != "json" || cfg.Defaults.Provider != "gcp" || cfg.Defaults.Confirm == nil || *cfg.Defaults.Confirm {
+		t.Errorf("unexpected defaults: %+v", cfg.Defaults)
+	}
+
+	if err := cm.SetValue("defaults.output", "xml"); err == nil {
+		t.Error("expected error for an unsupported output format")
+	}
+	if err := cm.SetValue("defaults.confirm_timeout", "2m"); err != nil {
+		t.Errorf("SetValue(defaults.confirm_timeout) failed: %v", err)
+	}
+	if err := cm.SetValue("defaults.confirm_timeout", "soon"); err == nil {
+		t.Error("expected error for a confirmation timeout that is not a duration")
+	}
+	if _, err := cm.DeleteValue("defaults.confirm"); err != nil {
+		t.Errorf("DeleteValue failed: %v", err)
+	}
+}
+
+func TestSetValue_ConfirmModes(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("defaults.confirm_modes.delete-object", "typed"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := cfg.Defaults.ConfirmModes["delete-object"]; got != "typed" {
+		t.Errorf("confirm mode = %q, want typed", got)
+	}
+
+	if err := cm.SetValue("defaults.confirm_modes.delete-bucket", "maybe"); err == nil {
+		t.Error("expected error for an unknown confirmation mode")
+	}
+	if err := cm.SetValue("defaults.confirm_modes.create-bucket", "typed"); err == nil {
+		t.Error("expected error for an operation without a confirmation")
+	}
+}
+
+func TestSetValue_NetworkSettings(t *testing.T) {
+	cm, tmpDir := setupTestConfig(t)
+
+	bundle := filepath.Join(tmpDir, "ca.pem")
+	if err := os.WriteFile(bundle, []byte("pem"), ConfigFilePermissions); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	for _, kv := range [][2]string{
+		{"network.proxy", "http://proxy.corp:3128"},
+		{"network.no_proxy", "localhost,.internal.corp"},
+		{"network.ca_bundle", bundle},
+	} {
+		if err := cm.SetValue(kv[0], kv[1]); err != nil {
+			t.Fatalf("SetValue(%q) failed: %v", kv[0], err)
+		}
+	}
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Network == nil || cfg.Network.CABundle != bundle || len(cfg.Network.NoProxy) != 2 {
+		t.Errorf("network settings not loaded: %+v", cfg.Network)
+	}
+
+	if err := cm.SetValue("network.ca_bundle", filepath.Join(tmpDir, "missing.pem")); err == nil {
+		t.Error("expected error for a CA bundle that does not exist")
+	}
+	if err := cm.SetValue("network.proxy", "not a url"); err == nil {
+		t.Error("expected error for an invalid proxy URL")
+	}
+}
+
+func TestSetValue_CacheSettings(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("cache.enabled", "true"); err != nil {
+		t.Fatalf("SetValue(cache.enabled) failed: %v", err)
+	}
+	if err := cm.SetValue("cache.ttl", "10m"); err != nil {
+		t.Fatalf("SetValue(cache.ttl) failed: %v", err)
+	}
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Cache == nil || !cfg.Cache.Enabled || cfg.Cache.ResponseTTL() != 10*time.Minute {
+		t.Errorf("cache settings not loaded: %+v", cfg.Cache)
+	}
+
+	for _, ttl := range []string{"soon", "-1m", "0s"} {
+		if err := cm.SetValue("cache.ttl", ttl); err == nil {
+			t.Errorf("expected error for cache.ttl %q", ttl)
+		}
+	}
+	if got := (&CacheConfig{}).ResponseTTL(); got != DefaultCacheTTL {
+		t.Errorf("ResponseTTL() with no ttl = %s, want %s", got, DefaultCacheTTL)
+	}
+}
+
+func TestSetValue_RetrySettings(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("retry.max_retries", "5"); err != nil {
+		t.Fatalf("SetValue(retry.max_retries) failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Retry == nil || cfg.Retry.MaxRetries == nil || *cfg.Retry.MaxRetries != 5 {
+		t.Errorf("retry settings not loaded: %+v", cfg.Retry)
+	}
+
+	// 0 disables retries, so it must be kept rather than read as unset
+	if err := cm.SetValue("retry.max_retries", "0"); err != nil {
+		t.Fatalf("SetValue(retry.max_retries, 0) failed: %v", err)
+	}
+	if cfg, err = cm.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Retry == nil || cfg.Retry.MaxRetries == nil || *cfg.Retry.MaxRetries != 0 {
+		t.Errorf("expected max_retries 0 to be kept, got %+v", cfg.Retry)
+	}
+
+	for _, value := range []string{"many", "-1", "11"} {
+		if err := cm.SetValue("retry.max_retries", value); err == nil {
+			t.Errorf("expected error for retry.max_retries %q", value)
+		}
+	}
+}
+
+func TestSetValue_FanOutSettings(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("fanout.provider_timeout", "30s"); err != nil {
+		t.Fatalf("SetValue(fanout.provider_timeout) failed: %v", err)
+	}
+	if err := cm.SetValue("fanout.concurrency", "2"); err != nil {
+		t.Fatalf("SetValue(fanout.concurrency) failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.FanOut == nil || cfg.FanOut.Timeout() != 30*time.Second || cfg.FanOut.Concurrency != 2 {
+		t.Errorf("fanout settings not loaded: %+v", cfg.FanOut)
+	}
+
+	for key, value := range map[string]string{"fanout.provider_timeout": "soon", "fanout.concurrency": "-1"} {
+		if err := cm.SetValue(key, value); err == nil {
+			t.Errorf("expected error for %s %q", key, value)
+		}
+	}
+}
+
+func TestSetValue_LogFile(t *testing.T) {
+	cm, tmpDir := setupTestConfig(t)
+	logPath := filepath.Join(tmpDir, "synkronus.log")
+
+	if err := cm.SetValue("log.file", logPath); err != nil {
+		t.Fatalf("SetValue(log.file) failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Log == nil || cfg.Log.File != logPath {
+		t.Errorf("log settings not loaded: %+v", cfg.Log)
+	}
+}
+
+func TestSetValue_LogFormat(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("log.format", "json"); err != nil {
+		t.Fatalf("SetValue(log.format) failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Log == nil || cfg.Log.Format != "json" {
+		t.Errorf("log settings not loaded: %+v", cfg.Log)
+	}
+
+	if err := cm.SetValue("log.format", "xml"); err == nil {
+		t.Error("expected an error for an unsupported log format")
+	}
+}
+
+func TestSetValue_MetricsAddress(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("metrics.address", ":9464"); err != nil {
+		t.Fatalf("SetValue(metrics.address) failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Metrics == nil || cfg.Metrics.Address != ":9464" {
+		t.Errorf("metrics settings not loaded: %+v", cfg.Metrics)
+	}
+
+	if err := cm.SetValue("metrics.address", "not an address"); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(&Config{GCP: &GCPConfig{Project: "p"}}); err != nil {
+		t.Errorf("unexpected error for a valid config: %v", err)
+	}
+
+	err := Validate(&Config{AWS: &AWSConfig{}})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a missing region, got %v", err)
+	}
+}
+
+func TestSetValue_ServerGRPCAddress(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+
+	if err := cm.SetValue("server.grpc_address", "localhost:9090"); err != nil {
+		t.Fatalf("SetValue(server.grpc_address) failed: %v", err)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Server == nil || cfg.Server.GRPCAddress != "localhost:9090" {
+		t.Errorf("server settings not loaded: %+v", cfg.Server)
+	}
+}
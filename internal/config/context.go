@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	// ContextsKey is the config key under which named contexts are stored
+	ContextsKey = "contexts"
+	// CurrentContextKey is the config key that records the last context applied
+	CurrentContextKey = "current_context"
+	// DefaultsKey is the config key of the command defaults section
+	DefaultsKey = "defaults"
+)
+
+// ContextConfig bundles a profile with the providers to target by default, so
+// both can be switched in one step with 'synkronus context use'.
+type ContextConfig struct {
+	Profile   string   `json:"profile,omitempty"`
+	Providers []string `json:"providers,omitempty" validate:"omitempty,dive,required"`
+}
+
+// ContextNames returns the sorted names of all configured contexts.
+func (c *Config) ContextNames() []string {
+	return slices.Sorted(maps.Keys(c.Contexts))
+}
+
+// CurrentContext returns the name of the last context applied with UseContext,
+// or "" if none has been.
+func (cm *ConfigManager) CurrentContext() string {
+	name, _ := cm.GetValue(CurrentContextKey)
+	return name
+}
+
+// UseContext applies the named context: its profile becomes the active profile
+// and its providers become defaults.providers. All changes are written at once,
+// and nothing is written if the result does not validate.
+func (cm *ConfigManager) UseContext(name string) error {
+	name = strings.ToLower(name)
+
+	var config Config
+	if err := cm.unmarshalStrict(&config); err != nil {
+		return fmt.Errorf("error parsing config file: %w", err)
+	}
+	ctx, ok := config.Contexts[name]
+	if !ok {
+		return fmt.Errorf("context %q not found. Available contexts: %v", name, config.ContextNames())
+	}
+
+	settings := cm.v.AllSettings()
+	if isDefaultProfile(ctx.Profile) {
+		deleteNestedKey(settings, []string{ActiveProfileKey})
+	} else {
+		setNestedKey(settings, []string{ActiveProfileKey}, strings.ToLower(ctx.Profile))
+	}
+	if len(ctx.Providers) == 0 {
+		deleteNestedKey(settings, []string{DefaultsKey, "providers"})
+	} else {
+		setNestedKey(settings, []string{DefaultsKey, "providers"}, ctx.Providers)
+	}
+	setNestedKey(settings, []string{CurrentContextKey}, name)
+
+	var updated Config
+	if err := decodeStrict(settings, &updated); err != nil {
+		return err
+	}
+	if err := cm.validateConfig(&updated); err != nil {
+		return err
+	}
+	if _, err := updated.withProfile(updated.ActiveProfile, false); err != nil {
+		return fmt.Errorf("context %q: %w", name, err)
+	}
+
+	return cm.replaceSettings(settings)
+}
+
+// setNestedKey sets the value at path within settings, creating intermediate
+// maps as needed.
+func setNestedKey(settings map[string]any, path []string, value any) {
+	parent := settings
+	for _, segment := range path[:len(path)-1] {
+		child, ok := parent[segment].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			parent[segment] = child
+		}
+		parent = child
+	}
+	parent[path[len(path)-1]] = value
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+const contextTestConfig = `{
+	"gcp": {"project": "default-project"},
+	"profiles": {
+		"prod": {"gcp": {"project": "prod-project"}, "aws": {"region": "eu-west-1"}}
+	},
+	"contexts": {
+		"prod": {"profile": "prod", "providers": ["gcp", "aws"]},
+		"dev": {"providers": ["gcp"]},
+		"broken": {"profile": "staging"}
+	}
+}`
+
+func TestUseContext_AppliesProfileAndProviders(t *testing.T) {
+	cm := setupProfileConfig(t, contextTestConfig)
+	if err := cm.UseContext("PROD"); err != nil {
+		t.Fatalf("UseContext failed: %v", err)
+	}
+
+	reloaded, err := NewConfigManager()
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	cfg, err := reloaded.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GCP == nil || cfg.GCP.Project != "prod-project" {
+		t.Errorf("expected prod-project, got %+v", cfg.GCP)
+	}
+	if cfg.Defaults == nil || !slices.Equal(cfg.Defaults.Providers, []string{"gcp", "aws"}) {
+		t.Errorf("expected default providers [gcp aws], got %+v", cfg.Defaults)
+	}
+	if got := reloaded.CurrentContext(); got != "prod" {
+		t.Errorf("expected current context prod, got %q", got)
+	}
+}
+
+func TestUseContext_SwitchingBackClearsProfile(t *testing.T) {
+	cm := setupProfileConfig(t, contextTestConfig)
+	if err := cm.UseContext("prod"); err != nil {
+		t.Fatalf("UseContext(prod) failed: %v", err)
+	}
+	if err := cm.UseContext("dev"); err != nil {
+		t.Fatalf("UseContext(dev) failed: %v", err)
+	}
+
+	if got := cm.ActiveProfile(); got != DefaultProfileName {
+		t.Errorf("expected default profile, got %q", got)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Defaults == nil || !slices.Equal(cfg.Defaults.Providers, []string{"gcp"}) {
+		t.Errorf("expected default providers [gcp], got %+v", cfg.Defaults)
+	}
+}
+
+func TestUseContext_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		context string
+	}{
+		{"unknown context", "staging"},
+		{"unknown profile", "broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := setupProfileConfig(t, contextTestConfig)
+			if err := cm.UseContext(tt.context); err == nil {
+				t.Fatal("expected error")
+			}
+			if got := cm.CurrentContext(); got != "" {
+				t.Errorf("expected no context to be persisted, got %q", got)
+			}
+			if got := cm.ActiveProfile(); got != DefaultProfileName {
+				t.Errorf("expected active profile unchanged, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// EncryptionPassphrase derives the config key from SYNKRONUS_CONFIG_PASSPHRASE
+	EncryptionPassphrase = "passphrase"
+	// EncryptionKeychain uses a random key stored in the OS keychain
+	EncryptionKeychain = "keychain"
+	// PassphraseEnvVar supplies the passphrase for passphrase-encrypted configs
+	PassphraseEnvVar = "SYNKRONUS_CONFIG_PASSPHRASE"
+
+	// encryptedEnvelopeKey is the single top-level key of an encrypted config file
+	encryptedEnvelopeKey = "synkronus_encrypted"
+	// encryptionKeyAccount is the keychain account holding the key in keychain mode
+	encryptionKeyAccount = "config-encryption-key"
+
+	encryptionKeySize = 32 // AES-256
+	saltSize          = 16
+	// scrypt cost parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// encryptedEnvelope is the on-disk form of an encrypted config. The plaintext
+// is the regular JSON config, sealed with AES-256-GCM.
+type encryptedEnvelope struct {
+	Mode       string `json:"mode"`
+	Salt       string `json:"salt,omitempty"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// encryption holds the key in effect for the loaded config file.
+type encryption struct {
+	mode string
+	salt []byte
+	key  []byte
+}
+
+// parseEnvelope returns the envelope if data is an encrypted config file.
+func parseEnvelope(data []byte) (*encryptedEnvelope, bool) {
+	var file map[string]*encryptedEnvelope
+	if err := json.Unmarshal(data, &file); err != nil || len(file) != 1 {
+		return nil, false
+	}
+	env, ok := file[encryptedEnvelopeKey]
+	return env, ok && env != nil
+}
+
+// EncryptionMode returns how the config file is encrypted at rest, or "" if
+// it is stored in plaintext.
+func (cm *ConfigManager) EncryptionMode() string {
+	if cm.encryption == nil {
+		return ""
+	}
+	return cm.encryption.mode
+}
+
+// EnableEncryption rewrites the config file encrypted with a new key. It can
+// also be used to switch modes or rotate the key of an encrypted config.
+func (cm *ConfigManager) EnableEncryption(mode string) error {
+	var enc *encryption
+	switch mode {
+	case EncryptionPassphrase:
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+		key, err := passphraseKey(salt)
+		if err != nil {
+			return err
+		}
+		enc = &encryption{mode: mode, salt: salt, key: key}
+	case EncryptionKeychain:
+		key := make([]byte, encryptionKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate encryption key: %w", err)
+		}
+		if err := cm.secrets.Set(encryptionKeyAccount, base64.StdEncoding.EncodeToString(key)); err != nil {
+			return fmt.Errorf("failed to store encryption key in OS keychain: %w", err)
+		}
+		enc = &encryption{mode: mode, key: key}
+	default:
+		return fmt.Errorf("unknown encryption mode %q (expected %q or %q)", mode, EncryptionPassphrase, EncryptionKeychain)
+	}
+
+	previous := cm.encryption
+	cm.encryption = enc
+	if err := cm.replaceSettings(cm.v.AllSettings()); err != nil {
+		cm.encryption = previous
+		// The file is still sealed with the old key; put it back if it was overwritten
+		if previous != nil && previous.mode == EncryptionKeychain && mode == EncryptionKeychain {
+			if restoreErr := cm.secrets.Set(encryptionKeyAccount, base64.StdEncoding.EncodeToString(previous.key)); restoreErr != nil {
+				return fmt.Errorf("%w (additionally, failed to restore the previous keychain key: %v)", err, restoreErr)
+			}
+		}
+		return err
+	}
+	if previous != nil && previous.mode == EncryptionKeychain && mode != EncryptionKeychain {
+		return cm.secrets.Delete(encryptionKeyAccount)
+	}
+	return nil
+}
+
+// DisableEncryption rewrites the config file in plaintext.
+func (cm *ConfigManager) DisableEncryption() error {
+	previous := cm.encryption
+	if previous == nil {
+		return nil
+	}
+
+	cm.encryption = nil
+	if err := cm.replaceSettings(cm.v.AllSettings()); err != nil {
+		cm.encryption = previous
+		return err
+	}
+	if previous.mode == EncryptionKeychain {
+		return cm.secrets.Delete(encryptionKeyAccount)
+	}
+	return nil
+}
+
+// sealConfig encrypts plaintext config JSON into an envelope file.
+func (cm *ConfigManager) sealConfig(plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(cm.encryption.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	env := &encryptedEnvelope{
+		Mode:       cm.encryption.mode,
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}
+	if cm.encryption.salt != nil {
+		env.Salt = base64.StdEncoding.EncodeToString(cm.encryption.salt)
+	}
+	return json.MarshalIndent(map[string]*encryptedEnvelope{encryptedEnvelopeKey: env}, "", "  ")
+}
+
+// openConfig decrypts an envelope and records the key so later writes are
+// encrypted the same way.
+func (cm *ConfigManager) openConfig(env *encryptedEnvelope) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(env.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt in encrypted config: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(env.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce in encrypted config: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(env.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext in encrypted config: %w", err)
+	}
+
+	enc := cm.encryption
+	// Re-deriving a passphrase key is deliberately slow, so reuse the key when
+	// re-reading a file written by this manager
+	if enc == nil || enc.mode != env.Mode || !bytes.Equal(enc.salt, salt) {
+		enc, err = cm.loadEncryptionKey(env.Mode, salt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gcm, err := newGCM(enc.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce in encrypted config")
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt config file (wrong passphrase or key?)")
+	}
+
+	cm.encryption = enc
+	return plaintext, nil
+}
+
+// loadEncryptionKey obtains the key for an existing encrypted config.
+func (cm *ConfigManager) loadEncryptionKey(mode string, salt []byte) (*encryption, error) {
+	switch mode {
+	case EncryptionPassphrase:
+		if len(salt) == 0 {
+			return nil, fmt.Errorf("encrypted config is missing its salt")
+		}
+		key, err := passphraseKey(salt)
+		if err != nil {
+			return nil, err
+		}
+		return &encryption{mode: mode, salt: salt, key: key}, nil
+	case EncryptionKeychain:
+		encoded, err := cm.secrets.Get(encryptionKeyAccount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config encryption key from OS keychain: %w", err)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config encryption key in OS keychain: %w", err)
+		}
+		return &encryption{mode: mode, key: key}, nil
+	default:
+		return nil, fmt.Errorf("config file uses unknown encryption mode %q", mode)
+	}
+}
+
+// passphraseKey derives an AES key from the passphrase in PassphraseEnvVar.
+func passphraseKey(salt []byte) ([]byte, error) {
+	passphrase := os.Getenv(PassphraseEnvVar)
+	if passphrase == "" {
+		return nil, fmt.Errorf("config encryption passphrase not set; export %s", PassphraseEnvVar)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, encryptionKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
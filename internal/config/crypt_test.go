@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readConfigFile(t *testing.T, tmpDir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".config", ConfigDirName, ConfigFileName))
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	return string(data)
+}
+
+func TestEncryption_PassphraseRoundTrip(t *testing.T) {
+	cm, tmpDir := setupTestConfig(t)
+	t.Setenv(PassphraseEnvVar, "correct horse battery staple")
+
+	if err := cm.EnableEncryption(EncryptionPassphrase); err != nil {
+		t.Fatalf("EnableEncryption failed: %v", err)
+	}
+	if strings.Contains(readConfigFile(t, tmpDir), "test-project") {
+		t.Fatal("encrypted config file should not contain plaintext values")
+	}
+
+	// Later writes stay encrypted
+	if err := cm.SetValue("aws.region", "us-east-1"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if strings.Contains(readConfigFile(t, tmpDir), "us-east-1") {
+		t.Fatal("config written after encryption should stay encrypted")
+	}
+
+	reopened, err := NewConfigManager()
+	if err != nil {
+		t.Fatalf("NewConfigManager failed: %v", err)
+	}
+	if reopened.EncryptionMode() != EncryptionPassphrase {
+		t.Errorf("EncryptionMode() = %q, want %q", reopened.EncryptionMode(), EncryptionPassphrase)
+	}
+	cfg, err := reopened.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.GCP.Project != "test-project" || cfg.AWS == nil || cfg.AWS.Region != "us-east-1" {
+		t.Errorf("unexpected config after decryption: gcp=%+v aws=%+v", cfg.GCP, cfg.AWS)
+	}
+}
+
+func TestEncryption_WrongOrMissingPassphrase(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	t.Setenv(PassphraseEnvVar, "right")
+	if err := cm.EnableEncryption(EncryptionPassphrase); err != nil {
+		t.Fatalf("EnableEncryption failed: %v", err)
+	}
+
+	t.Setenv(PassphraseEnvVar, "wrong")
+	if _, err := NewConfigManager(); err == nil {
+		t.Error("expected error for a wrong passphrase")
+	}
+
+	t.Setenv(PassphraseEnvVar, "")
+	if _, err := NewConfigManager(); err == nil || !strings.Contains(err.Error(), PassphraseEnvVar) {
+		t.Errorf("expected error naming %s, got %v", PassphraseEnvVar, err)
+	}
+}
+
+func TestEncryption_KeychainAndDisable(t *testing.T) {
+	cm, tmpDir := setupTestConfig(t)
+	store := newFakeSecretStore()
+	cm.secrets = store
+
+	if err := cm.EnableEncryption(EncryptionKeychain); err != nil {
+		t.Fatalf("EnableEncryption failed: %v", err)
+	}
+	if _, ok := store.secrets[encryptionKeyAccount]; !ok {
+		t.Fatal("expected the encryption key to be stored in the keychain")
+	}
+
+	// Re-read from disk through the keychain key
+	cm.encryption = nil
+	if err := cm.reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if value, _ := cm.GetValue("gcp.project"); value != "test-project" {
+		t.Errorf("GetValue after reload = %q, want %q", value, "test-project")
+	}
+
+	if err := cm.DisableEncryption(); err != nil {
+		t.Fatalf("DisableEncryption failed: %v", err)
+	}
+	if !strings.Contains(readConfigFile(t, tmpDir), "test-project") {
+		t.Error("config should be plaintext after DisableEncryption")
+	}
+	if _, ok := store.secrets[encryptionKeyAccount]; ok {
+		t.Error("keychain key should be removed after DisableEncryption")
+	}
+}
+
+func TestEnableEncryption_UnknownMode(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	if err := cm.EnableEncryption("rot13"); err == nil {
+		t.Fatal("expected error for unknown encryption mode")
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SyncJob defines a sync that 'synkronus serve' runs on a schedule. Jobs come
+// from the jobs section of the config file or from a jobs file (see LoadJobsFile).
+type SyncJob struct {
+	Name string `json:"name" yaml:"name" validate:"required"`
+	// Schedule is a cron expression (e.g., "30 2 * * *"), a descriptor such as
+	// @hourly, or "@every 15m", evaluated in the server's local time zone
+	Schedule    string       `json:"schedule" yaml:"schedule" validate:"required,cron"`
+	Source      SyncEndpoint `json:"source" yaml:"source"`
+	Destination SyncEndpoint `json:"destination" yaml:"destination"`
+	// Include and Exclude filter the source objects with glob patterns (see
+	// service.SyncOptions)
+	Include []string `json:"include,omitempty" yaml:"include,omitempty" validate:"omitempty,dive,glob"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty" validate:"omitempty,dive,glob"`
+	// Verify compares the destination with the source after each run (see
+	// service.SyncOptions)
+	Verify bool `json:"verify,omitempty" yaml:"verify,omitempty"`
+	// ContentTypes sets the content type of the synced objects whose key
+	// matches a glob pattern (see service.SyncOptions)
+	ContentTypes map[string]string `json:"content_types,omitempty" yaml:"content_types,omitempty" mapstructure:"content_types" validate:"omitempty,dive,keys,glob,endkeys,required"`
+	// MetadataTemplate names a template of the metadata_templates section
+	// whose headers and metadata are set on the synced objects
+	MetadataTemplate string `json:"metadata_template,omitempty" yaml:"metadata_template,omitempty" mapstructure:"metadata_template"`
+}
+
+// SyncEndpoint is one side of a scheduled sync: the objects under Prefix in a bucket.
+type SyncEndpoint struct {
+	Provider string `json:"provider" yaml:"provider" validate:"required"`
+	Bucket   string `json:"bucket" yaml:"bucket" validate:"required"`
+	Prefix   string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
+
+// jobsFile is the layout of a jobs file.
+type jobsFile struct {
+	Jobs []SyncJob `yaml:"jobs" validate:"unique=Name,dive"`
+}
+
+// LoadJobsFile reads the scheduled sync jobs in a YAML (or JSON) file of the form:
+//
+//	jobs:
+//	  - name: nightly-exports
+//	    schedule: "0 2 * * *"
+//	    source: {provider: gcp, bucket: data, prefix: exports/}
+//	    destination: {provider: aws, bucket: data-replica}
+//	    exclude: ["*.tmp"]
+//
+// Jobs are checked against the same rules as the jobs section of the config file.
+func LoadJobsFile(path string) ([]SyncJob, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jobs file: %w", err)
+	}
+
+	var file jobsFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse jobs file %s: %w", path, err)
+	}
+	if err := validateWith(newValidator(), &file); err != nil {
+		return nil, fmt.Errorf("jobs file %s: %w", path, err)
+	}
+	return file.Jobs, nil
+}
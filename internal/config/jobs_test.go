@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate_Jobs(t *testing.T) {
+	job := SyncJob{
+		Name:        "nightly",
+		Schedule:    "0 2 * * *",
+		Source:      SyncEndpoint{Provider: "gcp", Bucket: "data", Prefix: "exports/"},
+		Destination: SyncEndpoint{Provider: "aws", Bucket: "replica"},
+		Exclude:     []string{"*.tmp"},
+	}
+	if err := Validate(&Config{Jobs: []SyncJob{job}}); err != nil {
+		t.Errorf("unexpected error for a valid job: %v", err)
+	}
+
+	badSchedule := job
+	badSchedule.Schedule = "every night"
+	noBucket := job
+	noBucket.Destination.Bucket = ""
+	badPattern := job
+	badPattern.Include = []string{"[a-"}
+	for name, jobs := range map[string][]SyncJob{
+		"schedule":       {badSchedule},
+		"bucket":         {noBucket},
+		"pattern":        {badPattern},
+		"duplicate name": {job, job},
+	} {
+		if err := Validate(&Config{Jobs: jobs}); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: expected ErrInvalidConfig, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadConfig_JobContentTypes(t *testing.T) {
+	cm := setupProfileConfig(t, `{"jobs": [{
+		"name": "nightly",
+		"schedule": "0 2 * * *",
+		"source": {"provider": "gcp", "bucket": "data"},
+		"destination": {"provider": "aws", "bucket": "replica"},
+		"content_types": {"*.csv": "text/csv"}
+	}]}`)
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Jobs) != 1 || cfg.Jobs[0].ContentTypes["*.csv"] != "text/csv" {
+		t.Errorf("expected the job's content types, got %+v", cfg.Jobs)
+	}
+}
+
+func TestLoadJobsFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	jobs, err := LoadJobsFile(write("jobs.yaml", `
+jobs:
+  - name: nightly
+    schedule: "@daily"
+    source: {provider: gcp, bucket: data, prefix: exports/}
+    destination: {provider: aws, bucket: replica}
+    exclude: ["*.tmp"]
+`))
+	if err != nil {
+		t.Fatalf("LoadJobsFile failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Source.Prefix != "exports/" || jobs[0].Exclude[0] != "*.tmp" {
+		t.Errorf("unexpected jobs: %+v", jobs)
+	}
+
+	if _, err := LoadJobsFile(write("unknown.yaml", "jobs:\n  - name: a\n    schedul: \"@daily\"\n")); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+	if _, err := LoadJobsFile(write("invalid.yaml", "jobs:\n  - name: a\n    schedule: \"@daily\"\n")); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for a job without buckets, got %v", err)
+	}
+	if _, err := LoadJobsFile(filepath.Join(dir, "missing.yaml")); err == nil || !strings.Contains(err.Error(), "jobs file") {
+		t.Errorf("expected an error for a missing file, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// KeychainRefPrefix marks a config value as a reference to a secret stored in
+	// the OS keychain (e.g., "keychain:aws.secret_access_key")
+	KeychainRefPrefix = "keychain:"
+	// keychainService is the service name secrets are stored under in the OS keychain
+	keychainService = "synkronus"
+)
+
+// SecretStore persists secret values outside the config file.
+type SecretStore interface {
+	Get(account string) (string, error)
+	Set(account, secret string) error
+	Delete(account string) error
+}
+
+// osKeychain stores secrets in the platform keychain (macOS Keychain,
+// Secret Service on Linux, Windows Credential Manager).
+type osKeychain struct{}
+
+func (osKeychain) Get(account string) (string, error) {
+	return keyring.Get(keychainService, account)
+}
+
+func (osKeychain) Set(account, secret string) error {
+	return keyring.Set(keychainService, account, secret)
+}
+
+func (osKeychain) Delete(account string) error {
+	err := keyring.Delete(keychainService, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// keychainRef returns the keychain account referenced by value, if any.
+func keychainRef(value any) (string, bool) {
+	s, ok := value.(string)
+	if !ok || !strings.HasPrefix(s, KeychainRefPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(s, KeychainRefPrefix), true
+}
+
+// SetSecret stores value in the OS keychain and writes a reference to it under
+// key, so the plaintext never reaches the config file. If the key is rejected
+// by validation, the keychain entry is removed again.
+func (cm *ConfigManager) SetSecret(key, value string) error {
+	if err := cm.secrets.Set(key, value); err != nil {
+		return fmt.Errorf("failed to store secret in OS keychain: %w", err)
+	}
+	if err := cm.SetValue(key, KeychainRefPrefix+key); err != nil {
+		if cleanupErr := cm.secrets.Delete(key); cleanupErr != nil {
+			return fmt.Errorf("%w (additionally, failed to remove keychain entry: %v)", err, cleanupErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveSecretRefs returns a copy of settings with every keychain reference
+// replaced by the secret it points to.
+func (cm *ConfigManager) resolveSecretRefs(settings map[string]any, prefix string) (map[string]any, error) {
+	result := make(map[string]any, len(settings))
+	for key, val := range settings {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+		if nested, ok := val.(map[string]any); ok {
+			resolved, err := cm.resolveSecretRefs(nested, fullKey)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+			continue
+		}
+		if account, ok := keychainRef(val); ok {
+			secret, err := cm.secrets.Get(account)
+			if err != nil {
+				return nil, fmt.Errorf("resolving keychain reference for '%s': %w", fullKey, err)
+			}
+			val = secret
+		}
+		result[key] = val
+	}
+	return result, nil
+}
+
+// deleteSecretRefs removes the keychain entries referenced anywhere within value.
+func (cm *ConfigManager) deleteSecretRefs(value any) error {
+	if nested, ok := value.(map[string]any); ok {
+		var errs []error
+		for _, v := range nested {
+			errs = append(errs, cm.deleteSecretRefs(v))
+		}
+		return errors.Join(errs...)
+	}
+	if account, ok := keychainRef(value); ok {
+		if err := cm.secrets.Delete(account); err != nil {
+			return fmt.Errorf("failed to remove keychain entry '%s': %w", account, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeSecretStore is an in-memory SecretStore for tests.
+type fakeSecretStore struct {
+	secrets map[string]string
+	setErr  error
+}
+
+func newFakeSecretStore() *fakeSecretStore {
+	return &fakeSecretStore{secrets: make(map[string]string)}
+}
+
+func (f *fakeSecretStore) Get(account string) (string, error) {
+	secret, ok := f.secrets[account]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return secret, nil
+}
+
+func (f *fakeSecretStore) Set(account, secret string) error {
+	if f.setErr != nil {
+		return f.setErr
+	}
+	f.secrets[account] = secret
+	return nil
+}
+
+func (f *fakeSecretStore) Delete(account string) error {
+	delete(f.secrets, account)
+	return nil
+}
+
+func TestSetSecret_StoresReferenceAndResolvesOnLoad(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	store := newFakeSecretStore()
+	cm.secrets = store
+
+	if err := cm.SetSecret("gcp.project", "secret-project"); err != nil {
+		t.Fatalf("SetSecret failed: %v", err)
+	}
+
+	raw, _ := cm.GetValue("gcp.project")
+	if raw != KeychainRefPrefix+"gcp.project" {
+		t.Errorf("expected config to hold a keychain reference, got %q", raw)
+	}
+	if store.secrets["gcp.project"] != "secret-project" {
+		t.Errorf("expected secret in store, got %v", store.secrets)
+	}
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.GCP.Project != "secret-project" {
+		t.Errorf("expected resolved secret, got %q", cfg.GCP.Project)
+	}
+}
+
+func TestSetSecret_InvalidKeyRemovesKeychainEntry(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	store := newFakeSecretStore()
+	cm.secrets = store
+
+	if err := cm.SetSecret("invalid.unknown", "value"); err == nil {
+		t.Fatal("expected error for unrecognized key")
+	}
+	if len(store.secrets) != 0 {
+		t.Errorf("expected keychain entry to be rolled back, got %v", store.secrets)
+	}
+}
+
+func TestSetSecret_KeychainFailure(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	store := newFakeSecretStore()
+	store.setErr = errors.New("keychain locked")
+	cm.secrets = store
+
+	if err := cm.SetSecret("gcp.project", "value"); err == nil {
+		t.Fatal("expected error when the keychain rejects the secret")
+	}
+	if val, _ := cm.GetValue("gcp.project"); val != "test-project" {
+		t.Errorf("config must be untouched when the keychain fails, got %q", val)
+	}
+}
+
+func TestLoadConfig_MissingKeychainEntry(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	cm.secrets = newFakeSecretStore()
+	if err := cm.SetValue("gcp.project", KeychainRefPrefix+"gcp.project"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if _, err := cm.LoadConfig(); err == nil {
+		t.Fatal("expected error for a dangling keychain reference")
+	}
+}
+
+func TestRemoveProvider_DeletesKeychainEntries(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	store := newFakeSecretStore()
+	cm.secrets = store
+
+	if err := cm.SetSecret("aws.region", "us-east-1"); err != nil {
+		t.Fatalf("SetSecret failed: %v", err)
+	}
+	if _, err := cm.RemoveProvider("aws"); err != nil {
+		t.Fatalf("RemoveProvider failed: %v", err)
+	}
+	if _, ok := store.secrets["aws.region"]; ok {
+		t.Error("expected keychain entry to be removed with its provider block")
+	}
+}
+
+func TestSetValue_ReplacingSecretDeletesKeychainEntry(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	store := newFakeSecretStore()
+	cm.secrets = store
+
+	if err := cm.SetSecret("gcp.project", "secret-project"); err != nil {
+		t.Fatalf("SetSecret failed: %v", err)
+	}
+	if err := cm.SetSecret("gcp.project", "other-project"); err != nil {
+		t.Fatalf("SetSecret failed: %v", err)
+	}
+	if store.secrets["gcp.project"] != "other-project" {
+		t.Fatalf("expected the secret to be replaced in place, got %v", store.secrets)
+	}
+
+	if err := cm.SetValue("gcp.project", "plain-project"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if _, ok := store.secrets["gcp.project"]; ok {
+		t.Error("expected the keychain entry to be removed once the key holds a plain value")
+	}
+}
+
+func TestDeleteValue_DeletesKeychainEntry(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	store := newFakeSecretStore()
+	cm.secrets = store
+
+	if err := cm.SetValue("aws.region", "us-east-1"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cm.SetSecret("aws.session_token", "token"); err != nil {
+		t.Fatalf("SetSecret failed: %v", err)
+	}
+	if _, err := cm.DeleteValue("aws.session_token"); err != nil {
+		t.Fatalf("DeleteValue failed: %v", err)
+	}
+	if _, ok := store.secrets["aws.session_token"]; ok {
+		t.Error("expected the keychain entry to be removed with its key")
+	}
+}
+
+func TestRedactSettings_KeepsKeychainReferences(t *testing.T) {
+	ref := KeychainRefPrefix + "aws.session_token"
+	redacted := redactSettings(map[string]any{"session_token": ref})
+	if redacted["session_token"] != ref {
+		t.Errorf("expected keychain reference to be kept, got %v", redacted["session_token"])
+	}
+}
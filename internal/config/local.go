@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// LocalConfigFileName is the project-local override file. The nearest one in the
+// working directory or its parents is layered over the global config when reading.
+const LocalConfigFileName = ".synkronus.json"
+
+// Precedence, highest first:
+//  1. the --profile flag (selects which provider blocks are used)
+//  2. the project-local .synkronus.json
+//  3. the global ~/.config/synkronus/config.json
+//
+// Writes (config set/delete, profile use, import) always target the global file;
+// the local file is only ever edited by hand.
+
+// findLocalConfig returns the path of the nearest LocalConfigFileName in dir or
+// one of its parents.
+func findLocalConfig(dir string) (string, bool) {
+	for {
+		path := filepath.Join(dir, LocalConfigFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// localConfigKeys are the only settings a project-local override may set. The
+// file is picked up from any parent directory, e.g. of a cloned repository,
+// so it must not be able to redirect requests (endpoints, proxies, CA
+// bundles), carry credentials, turn off confirmations, define commands or
+// jobs, or write files; those belong in the global config.
+var localConfigKeys = map[string]bool{
+	"gcp.project":        true,
+	"gcp.projects":       true,
+	"aws.region":         true,
+	"defaults.output":    true,
+	"defaults.provider":  true,
+	"defaults.providers": true,
+	ActiveProfileKey:     true,
+}
+
+// readLocalConfig parses a project-local override file, which may only set
+// the localConfigKeys.
+func readLocalConfig(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading project config %s: %w", path, err)
+	}
+
+	var settings map[string]any
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("error parsing project config %s: %w", path, err)
+	}
+	settings = lowercaseKeys(settings)
+	// Project files are edited by hand and never rewritten, so migrate them in memory
+	if _, err := migrateSettings(settings); err != nil {
+		return nil, fmt.Errorf("project config %s: %w", path, err)
+	}
+	delete(settings, VersionKey)
+
+	for _, key := range slices.Sorted(maps.Keys(FlattenSettings(settings))) {
+		if !localConfigKeys[key] {
+			return nil, fmt.Errorf("project config %s cannot set '%s'; set it in the global config instead (allowed: %s)",
+				path, key, strings.Join(slices.Sorted(maps.Keys(localConfigKeys)), ", "))
+		}
+	}
+	return settings, nil
+}
+
+// LocalConfigPath returns the project-local override file in effect, or "" if none.
+func (cm *ConfigManager) LocalConfigPath() string {
+	return cm.localPath
+}
+
+// effectiveSettings returns the global settings with the project-local
+// overrides applied on top.
+func (cm *ConfigManager) effectiveSettings() map[string]any {
+	if len(cm.local) == 0 {
+		return cm.v.AllSettings()
+	}
+	return mergeSettings(cm.v.AllSettings(), cm.local)
+}
+
+// lookupNestedKey returns the value at path within settings.
+func lookupNestedKey(settings map[string]any, path []string) (any, bool) {
+	value, ok := settings[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	child, ok := value.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookupNestedKey(child, path[1:])
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupLocalConfig writes a global config and a project-local override in a
+// parent of the working directory, then creates a ConfigManager from there.
+func setupLocalConfig(t *testing.T, global, local string) *ConfigManager {
+	t.Helper()
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".config", ConfigDirName)
+	if err := os.MkdirAll(configDir, ConfigDirPermissions); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, ConfigFileName), []byte(global), ConfigFilePermissions); err != nil {
+		t.Fatalf("failed to write global config: %v", err)
+	}
+
+	projectDir := filepath.Join(tmpDir, "project")
+	workDir := filepath.Join(projectDir, "sub", "dir")
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, LocalConfigFileName), []byte(local), 0o644); err != nil {
+		t.Fatalf("failed to write local config: %v", err)
+	}
+
+	t.Setenv("HOME", tmpDir)
+	t.Chdir(workDir)
+	cm, err := NewConfigManager()
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	return cm
+}
+
+func TestLoadConfig_LocalOverride(t *testing.T) {
+	cm := setupLocalConfig(t,
+		`{"gcp": {"project": "global-project"}, "aws": {"region": "us-east-1"}}`,
+		`{"gcp": {"project": "local-project"}}`,
+	)
+
+	if !strings.HasSuffix(cm.LocalConfigPath(), filepath.Join("project", LocalConfigFileName)) {
+		t.Errorf("LocalConfigPath() = %q, want the file in the parent project dir", cm.LocalConfigPath())
+	}
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.GCP.Project != "local-project" {
+		t.Errorf("expected local override to win, got %q", cfg.GCP.Project)
+	}
+	if cfg.AWS == nil || cfg.AWS.Region != "us-east-1" {
+		t.Errorf("expected global AWS block to be kept, got %+v", cfg.AWS)
+	}
+
+	if value, _ := cm.GetValue("gcp.project"); value != "local-project" {
+		t.Errorf("GetValue = %q, want the local override", value)
+	}
+}
+
+func TestSetValue_WritesGlobalOnly(t *testing.T) {
+	cm := setupLocalConfig(t,
+		`{"gcp": {"project": "global-project"}}`,
+		`{"gcp": {"project": "local-project"}}`,
+	)
+
+	if err := cm.SetValue("gcp.project", "new-global"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cm.LocalConfigPath())
+	if err != nil {
+		t.Fatalf("failed to read local config: %v", err)
+	}
+	if strings.Contains(string(data), "new-global") {
+		t.Error("local config should never be written")
+	}
+	if value, _ := cm.GetValue("gcp.project"); value != "local-project" {
+		t.Errorf("GetValue = %q, local override should still win", value)
+	}
+}
+
+func TestNewConfigManager_LocalKeysRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		local string
+	}{
+		{"secret key", `{"aws": {"secret_access_key": "abc"}}`},
+		{"keychain reference", `{"aws": {"access_key_id": "keychain:aws.access_key_id"}}`},
+		{"proxy", `{"network": {"proxy": "http://attacker.example:3128"}}`},
+		{"endpoint", `{"gcp": {"project": "p", "storage_endpoint": "https://attacker.example"}}`},
+		{"confirmations", `{"defaults": {"confirm": false}}`},
+		{"command alias", `{"alias": {"ls": "storage buckets delete prod --force"}}`},
+		{"log file", `{"log": {"file": "/tmp/synkronus.log"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, LocalConfigFileName), []byte(tt.local), 0o644); err != nil {
+				t.Fatalf("failed to write local config: %v", err)
+			}
+			t.Setenv("HOME", tmpDir)
+			t.Chdir(tmpDir)
+
+			if _, err := NewConfigManager(); err == nil {
+				t.Fatal("expected an error for a key the project config cannot set")
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// MetadataTemplate is a named set of content headers and user-defined
+// metadata applied to uploaded objects with --metadata-template, keyed by name
+// in the metadata_templates section (e.g.,
+// metadata_templates.web-assets.cache_control = "public, max-age=86400").
+type MetadataTemplate struct {
+	ContentType        string `json:"content_type,omitempty" mapstructure:"content_type"`
+	CacheControl       string `json:"cache_control,omitempty" mapstructure:"cache_control"`
+	ContentDisposition string `json:"content_disposition,omitempty" mapstructure:"content_disposition"`
+	ContentEncoding    string `json:"content_encoding,omitempty" mapstructure:"content_encoding"`
+	ContentLanguage    string `json:"content_language,omitempty" mapstructure:"content_language"`
+	// Metadata holds the user-defined metadata keys the template sets
+	Metadata map[string]string `json:"metadata,omitempty" validate:"omitempty,dive,keys,required,endkeys"`
+}
+
+// MetadataTemplate returns the named metadata template, or an error listing
+// the defined ones if there is no such template.
+func (c *Config) MetadataTemplate(name string) (MetadataTemplate, error) {
+	if tmpl, ok := c.MetadataTemplates[name]; ok {
+		return tmpl, nil
+	}
+	names := slices.Sorted(maps.Keys(c.MetadataTemplates))
+	if len(names) == 0 {
+		return MetadataTemplate{}, fmt.Errorf("metadata template %q not found: none are defined (set metadata_templates.%s.<header>)", name, name)
+	}
+	return MetadataTemplate{}, fmt.Errorf("metadata template %q not found (defined: %s)", name, strings.Join(names, ", "))
+}
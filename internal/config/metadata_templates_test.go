@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetValue_MetadataTemplates(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	for _, kv := range [][2]string{
+		{"metadata_templates.web-assets.cache_control", "public, max-age=86400"},
+		{"metadata_templates.web-assets.content_disposition", "attachment"},
+		{"metadata_templates.web-assets.metadata.team", "web"},
+	} {
+		if err := cm.SetValue(kv[0], kv[1]); err != nil {
+			t.Fatalf("SetValue(%s) failed: %v", kv[0], err)
+		}
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := cfg.MetadataTemplate("web-assets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tmpl.CacheControl != "public, max-age=86400" || tmpl.ContentDisposition != "attachment" || tmpl.Metadata["team"] != "web" {
+		t.Errorf("unexpected template: %+v", tmpl)
+	}
+
+	if _, err := cfg.MetadataTemplate("images"); err == nil || !strings.Contains(err.Error(), "web-assets") {
+		t.Errorf("expected an error listing the defined templates, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	// CurrentSchemaVersion is the config file schema this build reads and writes.
+	// Bump it together with a new entry in migrations whenever keys are renamed,
+	// moved, or change meaning.
+	CurrentSchemaVersion = 2
+	// VersionKey is the config key that records the schema version of the file
+	VersionKey = "version"
+)
+
+// migration upgrades settings from schema version `from` to from+1.
+type migration struct {
+	from        int
+	description string
+	apply       func(settings map[string]any) error
+}
+
+// migrations must be ordered by from, with exactly one entry per version below
+// CurrentSchemaVersion. Files without a version field are treated as version 0.
+var migrations = []migration{
+	{
+		from:        0,
+		description: "record the schema version",
+		apply:       func(map[string]any) error { return nil },
+	},
+	{
+		from:        1,
+		description: "rename aws.endpoint to aws.endpoint_url",
+		apply: func(settings map[string]any) error {
+			for _, block := range providerBlocks(settings, "aws") {
+				renameKey(block, []string{"endpoint"}, []string{"endpoint_url"})
+			}
+			return nil
+		},
+	},
+}
+
+// schemaVersion returns the version recorded in settings (0 if absent).
+func schemaVersion(settings map[string]any) (int, error) {
+	switch v := settings[VersionKey].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid config schema version %v", settings[VersionKey])
+}
+
+// migrateSettings upgrades settings in place to CurrentSchemaVersion and returns
+// the version it started from. Files written by a newer synkronus are rejected
+// rather than guessed at.
+func migrateSettings(settings map[string]any) (int, error) {
+	version, err := schemaVersion(settings)
+	if err != nil {
+		return 0, err
+	}
+	if version > CurrentSchemaVersion {
+		return version, fmt.Errorf("config schema version %d is newer than this build supports (%d); upgrade synkronus", version, CurrentSchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.apply(settings); err != nil {
+			return version, fmt.Errorf("config migration from version %d (%s) failed: %w", m.from, m.description, err)
+		}
+	}
+	settings[VersionKey] = CurrentSchemaVersion
+	return version, nil
+}
+
+// migrate upgrades the config file on disk if it uses an older schema. The
+// original file is copied to a versioned backup before it is rewritten.
+func (cm *ConfigManager) migrate() error {
+	settings := cm.v.AllSettings()
+	if len(settings) == 0 {
+		return nil
+	}
+	version, err := schemaVersion(settings)
+	if err != nil {
+		return err
+	}
+	if version == CurrentSchemaVersion {
+		return nil
+	}
+
+	if _, err := migrateSettings(settings); err != nil {
+		return err
+	}
+
+	configPath, err := cm.getPreferredConfigPath()
+	if err != nil {
+		return err
+	}
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config for backup: %w", err)
+	}
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, version)
+	if err := os.WriteFile(backupPath, original, ConfigFilePermissions); err != nil {
+		return fmt.Errorf("failed to back up config before migration: %w", err)
+	}
+
+	if err := cm.replaceSettings(settings); err != nil {
+		return fmt.Errorf("failed to write migrated config (original kept at %s): %w", backupPath, err)
+	}
+	return nil
+}
+
+// renameKey moves the value at the dotted path from to the dotted path to,
+// creating intermediate blocks as needed. A missing source is not an error.
+// Intended for use by migrations.
+func renameKey(settings map[string]any, from, to []string) {
+	value, ok := deleteNestedKey(settings, from)
+	if !ok {
+		return
+	}
+	setNestedKey(settings, to, value)
+}
+
+// providerBlocks returns the top-level block for provider and the matching
+// block of every profile, so migrations can rewrite them alike.
+func providerBlocks(settings map[string]any, provider string) []map[string]any {
+	var blocks []map[string]any
+	if block, ok := settings[provider].(map[string]any); ok {
+		blocks = append(blocks, block)
+	}
+	profiles, _ := settings[ProfilesKey].(map[string]any)
+	for _, profile := range profiles {
+		if profile, ok := profile.(map[string]any); ok {
+			if block, ok := profile[provider].(map[string]any); ok {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	return blocks
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewConfigManager_MigratesLegacyConfig(t *testing.T) {
+	legacy := `{"gcp": {"project": "legacy-project"}}`
+	cm := setupProfileConfig(t, legacy)
+
+	configPath, err := cm.getPreferredConfigPath()
+	if err != nil {
+		t.Fatalf("failed to resolve config path: %v", err)
+	}
+
+	backup, err := os.ReadFile(configPath + ".v0.bak")
+	if err != nil {
+		t.Fatalf("expected a backup of the legacy config: %v", err)
+	}
+	if string(backup) != legacy {
+		t.Errorf("backup content = %q, want the original file", backup)
+	}
+
+	if got := cm.v.GetInt(VersionKey); got != CurrentSchemaVersion {
+		t.Errorf("version = %d, want %d", got, CurrentSchemaVersion)
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.GCP.Project != "legacy-project" {
+		t.Errorf("migration lost settings: %+v", cfg.GCP)
+	}
+}
+
+func TestNewConfigManager_CurrentVersionUntouched(t *testing.T) {
+	cm := setupProfileConfig(t, fmt.Sprintf(`{"version": %d, "gcp": {"project": "p"}}`, CurrentSchemaVersion))
+
+	configPath, _ := cm.getPreferredConfigPath()
+	matches, _ := filepath.Glob(configPath + ".v*.bak")
+	if len(matches) != 0 {
+		t.Errorf("no backup expected for an up-to-date config, found %v", matches)
+	}
+}
+
+func TestNewConfigManager_NewerVersionRejected(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".config", ConfigDirName)
+	if err := os.MkdirAll(configDir, ConfigDirPermissions); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	content := `{"version": 99, "gcp": {"project": "p"}}`
+	if err := os.WriteFile(filepath.Join(configDir, ConfigFileName), []byte(content), ConfigFilePermissions); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("HOME", tmpDir)
+
+	_, err := NewConfigManager()
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Fatalf("expected error for a newer schema version, got %v", err)
+	}
+}
+
+func TestSetValue_StampsVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	cm, err := NewConfigManager()
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+
+	if err := cm.SetValue("gcp.project", "fresh"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	configPath, _ := cm.getPreferredConfigPath()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if !strings.Contains(string(data), fmt.Sprintf(`"version": %d`, CurrentSchemaVersion)) {
+		t.Errorf("expected new config files to record the schema version, got %s", data)
+	}
+	if _, ok := cm.GetAllSettings()[VersionKey]; ok {
+		t.Error("GetAllSettings should not expose the schema version")
+	}
+}
+
+func TestRenameKey(t *testing.T) {
+	settings := map[string]any{
+		"gcp": map[string]any{"project_id": "p", "location": "us"},
+	}
+
+	renameKey(settings, []string{"gcp", "project_id"}, []string{"gcp", "project"})
+	renameKey(settings, []string{"gcp", "missing"}, []string{"gcp", "other"})
+
+	gcp := settings["gcp"].(map[string]any)
+	if gcp["project"] != "p" {
+		t.Errorf("expected renamed key, got %v", gcp)
+	}
+	if _, ok := gcp["project_id"]; ok {
+		t.Error("old key should be removed")
+	}
+	if _, ok := gcp["other"]; ok {
+		t.Error("missing source should not create the target key")
+	}
+}
+
+func TestMigrations_CoverEveryVersion(t *testing.T) {
+	if len(migrations) != CurrentSchemaVersion {
+		t.Fatalf("expected %d migrations, got %d", CurrentSchemaVersion, len(migrations))
+	}
+	for i, m := range migrations {
+		if m.from != i {
+			t.Errorf("migrations[%d].from = %d, want %d", i, m.from, i)
+		}
+	}
+}
+
+func TestMigrateSettings_RenamesAWSEndpoint(t *testing.T) {
+	settings := map[string]any{
+		"version": 1,
+		"aws":     map[string]any{"region": "us-east-1", "endpoint": "http://localhost:4566"},
+		"profiles": map[string]any{
+			"dev":  map[string]any{"aws": map[string]any{"region": "us-east-1", "endpoint": "http://minio:9000"}},
+			"prod": map[string]any{"gcp": map[string]any{"project": "p"}},
+		},
+	}
+
+	if _, err := migrateSettings(settings); err != nil {
+		t.Fatalf("migrateSettings failed: %v", err)
+	}
+
+	var cfg Config
+	if err := decodeStrict(settings, &cfg); err != nil {
+		t.Fatalf("migrated settings do not decode: %v", err)
+	}
+	if cfg.AWS.EndpointURL != "http://localhost:4566" {
+		t.Errorf("expected top-level endpoint to be renamed, got %+v", cfg.AWS)
+	}
+	if cfg.Profiles["dev"].AWS.EndpointURL != "http://minio:9000" {
+		t.Errorf("expected profile endpoint to be renamed, got %+v", cfg.Profiles["dev"].AWS)
+	}
+}
+
+func TestReadLocalConfig_Migrated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LocalConfigFileName)
+	if err := os.WriteFile(path, []byte(`{"version": 1, "aws": {"region": "eu-west-1"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := readLocalConfig(path)
+	if err != nil {
+		t.Fatalf("readLocalConfig failed: %v", err)
+	}
+	if got, _ := lookupNestedKey(settings, []string{"aws", "region"}); got != "eu-west-1" {
+		t.Errorf("expected the region to be kept, got %v", settings)
+	}
+	if _, ok := settings[VersionKey]; ok {
+		t.Error("the schema version should not leak into the local overrides")
+	}
+
+	// A legacy key is migrated in memory before the allowed keys are checked
+	if err := os.WriteFile(path, []byte(`{"aws": {"endpoint": "http://localhost:4566"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readLocalConfig(path); err == nil || !strings.Contains(err.Error(), "'aws.endpoint_url'") {
+		t.Errorf("expected the migrated endpoint to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+// NotifierConfig sends a message when a sync job run by 'synkronus serve'
+// finishes: a JSON POST to a webhook, a Slack message through an incoming
+// webhook, or an email. Notifiers are keyed by name in the notifications
+// section (e.g., notifications.ops-slack.url). Only the format of each value
+// is validated here, so a notifier can be set up one key at a time with
+// 'config set'; notify.New checks that the settings of its type are present.
+type NotifierConfig struct {
+	// Type is "webhook", "slack" or "email"
+	Type string `json:"type" validate:"omitempty,oneof=webhook slack email"`
+	// On lists the outcomes to notify about, "success" and/or "failure"; empty means both
+	On []string `json:"on,omitempty" validate:"omitempty,dive,oneof=success failure"`
+	// Jobs limits the notifier to these scheduled jobs; empty means every sync
+	// job, including those submitted through the API
+	Jobs []string `json:"jobs,omitempty" validate:"omitempty,dive,required"`
+	// URL receives the webhook and Slack messages (required for those types)
+	URL string `json:"url,omitempty" validate:"omitempty,url"`
+	// Headers are added to webhook requests (e.g., an Authorization header)
+	Headers map[string]string `json:"headers,omitempty"`
+	// Template is a Go text/template rendering the message from the job's
+	// event (see notify.Event): the request body of a webhook, the text of a
+	// Slack message or the body of an email. Each type has a default.
+	Template string `json:"template,omitempty"`
+	// Subject is the template of an email's subject line
+	Subject string `json:"subject,omitempty"`
+
+	// SMTPAddress, From and To are required for email
+	SMTPAddress  string   `json:"smtp_address,omitempty" mapstructure:"smtp_address" validate:"omitempty,hostname_port"`
+	SMTPUsername string   `json:"smtp_username,omitempty" mapstructure:"smtp_username"`
+	SMTPPassword string   `json:"smtp_password,omitempty" mapstructure:"smtp_password"`
+	From         string   `json:"from,omitempty" validate:"omitempty,email"`
+	To           []string `json:"to,omitempty" validate:"omitempty,dive,email"`
+}
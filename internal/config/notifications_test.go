@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate_Notifications(t *testing.T) {
+	valid := map[string]NotifierConfig{
+		"ops":    {Type: "slack", URL: "https://hooks.slack.com/services/T0/B0/x", On: []string{"failure"}},
+		"hook":   {Type: "webhook", URL: "https://example.com/hook", Jobs: []string{"nightly"}},
+		"oncall": {Type: "email", SMTPAddress: "smtp.example.com:587", From: "synkronus@example.com", To: []string{"oncall@example.com"}},
+	}
+	if err := Validate(&Config{Notifications: valid}); err != nil {
+		t.Errorf("unexpected error for valid notifiers: %v", err)
+	}
+
+	for name, notifier := range map[string]NotifierConfig{
+		"type":     {Type: "pager", URL: "https://example.com"},
+		"bad url":  {Type: "slack", URL: "not a url"},
+		"outcome":  {Type: "webhook", URL: "https://example.com", On: []string{"done"}},
+		"bad smtp": {Type: "email", SMTPAddress: "smtp.example.com"},
+		"bad from": {Type: "email", From: "synkronus"},
+		"bad to":   {Type: "email", To: []string{"b"}},
+	} {
+		err := Validate(&Config{Notifications: map[string]NotifierConfig{"n": notifier}})
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: expected ErrInvalidConfig, got %v", name, err)
+		}
+	}
+}
+
+func TestSetValue_Notifications(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	for _, kv := range [][2]string{
+		{"notifications.ops.type", "email"},
+		{"notifications.ops.smtp_address", "smtp.example.com:587"},
+		{"notifications.ops.smtp_username", "synkronus"},
+		{"notifications.ops.from", "synkronus@example.com"},
+		{"notifications.ops.to", "oncall@example.com,dev@example.com"},
+		{"notifications.ops.on", "failure"},
+	} {
+		if err := cm.SetValue(kv[0], kv[1]); err != nil {
+			t.Fatalf("SetValue(%s) failed: %v", kv[0], err)
+		}
+	}
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := cfg.Notifications["ops"]
+	if ops.SMTPAddress != "smtp.example.com:587" || len(ops.To) != 2 || ops.On[0] != "failure" {
+		t.Errorf("unexpected notifier: %+v", ops)
+	}
+}
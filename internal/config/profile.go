@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultProfileName selects the top-level provider blocks instead of a named profile
+	DefaultProfileName = "default"
+	// ActiveProfileKey is the config key that persists the selected profile
+	ActiveProfileKey = "active_profile"
+	// ProfilesKey is the config key under which named profiles are stored
+	ProfilesKey = "profiles"
+)
+
+// ProfileConfig holds the provider blocks for a single named profile.
+// A profile is self-contained: when it is active, only its provider blocks
+// are used and the top-level blocks are ignored.
+type ProfileConfig struct {
+	GCP *GCPConfig `json:"gcp,omitempty" validate:"omitempty"`
+	AWS *AWSConfig `json:"aws,omitempty" validate:"omitempty"`
+}
+
+// withProfile returns a copy of the config whose provider blocks come from the
+// named profile. The empty name and DefaultProfileName select the top-level blocks.
+// fromFlag only changes the error hint for unknown profiles.
+func (c Config) withProfile(name string, fromFlag bool) (*Config, error) {
+	if isDefaultProfile(name) {
+		return &c, nil
+	}
+
+	profile, ok := c.Profiles[strings.ToLower(name)]
+	if !ok {
+		if fromFlag {
+			return nil, fmt.Errorf("profile %q not found. Available profiles: %v", name, c.ProfileNames())
+		}
+		return nil, fmt.Errorf("active profile %q not found in config (use --profile %s to bypass it)", name, DefaultProfileName)
+	}
+
+	c.GCP = profile.GCP
+	c.AWS = profile.AWS
+	return &c, nil
+}
+
+// ProfileNames returns the sorted names of all configured profiles, including
+// the implicit default profile.
+func (c *Config) ProfileNames() []string {
+	names := slices.Sorted(maps.Keys(c.Profiles))
+	return append([]string{DefaultProfileName}, names...)
+}
+
+func isDefaultProfile(name string) bool {
+	return name == "" || strings.EqualFold(name, DefaultProfileName)
+}
+
+// SetProfileOverride selects a profile for this process only, without
+// persisting it. Used by the global --profile flag.
+func (cm *ConfigManager) SetProfileOverride(name string) {
+	cm.profileOverride = strings.ToLower(name)
+}
+
+// ActiveProfile returns the name of the profile in effect: the --profile
+// override if set, otherwise the active_profile from the project-local or
+// global config, otherwise "default".
+func (cm *ConfigManager) ActiveProfile() string {
+	if cm.profileOverride != "" {
+		return cm.profileOverride
+	}
+	if name, ok := cm.GetValue(ActiveProfileKey); ok {
+		return name
+	}
+	return DefaultProfileName
+}
+
+// UseProfile persists name as the active profile. The profile must exist,
+// except for DefaultProfileName which clears the selection.
+func (cm *ConfigManager) UseProfile(name string) error {
+	name = strings.ToLower(name)
+
+	var config Config
+	if err := cm.unmarshalStrict(&config); err != nil {
+		return fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	if isDefaultProfile(name) {
+		return cm.SetValue(ActiveProfileKey, "")
+	}
+	if _, ok := config.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q not found. Available profiles: %v", name, config.ProfileNames())
+	}
+	return cm.SetValue(ActiveProfileKey, name)
+}
+
+// ListProfiles returns the names of all profiles, including "default".
+func (cm *ConfigManager) ListProfiles() ([]string, error) {
+	var config Config
+	if err := cm.unmarshalStrict(&config); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+	return config.ProfileNames(), nil
+}
+
+// ProfileConfig returns the configuration with the named profile applied, as
+// LoadConfig would for --profile name. Keychain references are left unresolved.
+func (cm *ConfigManager) ProfileConfig(name string) (*Config, error) {
+	var config Config
+	if err := decodeStrict(cm.effectiveSettings(), &config); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+	return config.withProfile(name, true)
+}
+
+// ProviderBlockKey returns the dotted path of the provider block that owns key.
+// For "gcp.project" this is "gcp"; for "profiles.prod.aws.region" it is
+// "profiles.prod.aws". Keys outside a provider block are returned unchanged.
+func ProviderBlockKey(key string) string {
+	parts := strings.Split(key, ".")
+	if parts[0] == ProfilesKey && len(parts) >= 3 {
+		return strings.Join(parts[:3], ".")
+	}
+	return parts[0]
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func setupProfileConfig(t *testing.T, content string) *ConfigManager {
+	t.Helper()
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".config", ConfigDirName)
+	if err := os.MkdirAll(configDir, ConfigDirPermissions); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, ConfigFileName), []byte(content), ConfigFilePermissions); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("HOME", tmpDir)
+	cm, err := NewConfigManager()
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	return cm
+}
+
+const profileTestConfig = `{
+	"gcp": {"project": "default-project"},
+	"profiles": {
+		"prod": {"gcp": {"project": "prod-project"}, "aws": {"region": "eu-west-1"}}
+	}
+}`
+
+func TestLoadConfig_DefaultProfileUsesTopLevel(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GCP == nil || cfg.GCP.Project != "default-project" {
+		t.Errorf("expected default-project, got %+v", cfg.GCP)
+	}
+	if cfg.AWS != nil {
+		t.Errorf("expected no AWS block in default profile, got %+v", cfg.AWS)
+	}
+}
+
+func TestLoadConfig_ProfileOverride(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	cm.SetProfileOverride("PROD")
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GCP == nil || cfg.GCP.Project != "prod-project" {
+		t.Errorf("expected prod-project, got %+v", cfg.GCP)
+	}
+	if cfg.AWS == nil || cfg.AWS.Region != "eu-west-1" {
+		t.Errorf("expected eu-west-1, got %+v", cfg.AWS)
+	}
+}
+
+func TestLoadConfig_UnknownProfileOverride(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	cm.SetProfileOverride("staging")
+	if _, err := cm.LoadConfig(); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestUseProfile_PersistsSelection(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	if err := cm.UseProfile("prod"); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+
+	// A fresh manager reads the persisted selection from disk
+	reloaded, err := NewConfigManager()
+	if err != nil {
+		t.Fatalf("failed to reload config manager: %v", err)
+	}
+	if got := reloaded.ActiveProfile(); got != "prod" {
+		t.Errorf("expected active profile prod, got %q", got)
+	}
+	cfg, err := reloaded.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.GCP.Project != "prod-project" {
+		t.Errorf("expected prod-project, got %q", cfg.GCP.Project)
+	}
+
+	if err := reloaded.UseProfile(DefaultProfileName); err != nil {
+		t.Fatalf("UseProfile(default) failed: %v", err)
+	}
+	if got := reloaded.ActiveProfile(); got != DefaultProfileName {
+		t.Errorf("expected default profile, got %q", got)
+	}
+}
+
+func TestUseProfile_UnknownProfile(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	if err := cm.UseProfile("staging"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	names, err := cm.ListProfiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(names, []string{DefaultProfileName, "prod"}) {
+		t.Errorf("unexpected profiles: %v", names)
+	}
+}
+
+func TestSetValue_ProfileKeyValidated(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	if err := cm.SetValue("profiles.staging.aws.region", "us-east-2"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if err := cm.SetValue("profiles.staging.unknown.key", "x"); err == nil {
+		t.Fatal("expected error for unrecognized profile key")
+	}
+}
+
+func TestRemoveProvider_ProfileBlock(t *testing.T) {
+	cm := setupProfileConfig(t, profileTestConfig)
+	removed, err := cm.RemoveProvider("profiles.prod.aws")
+	if err != nil {
+		t.Fatalf("RemoveProvider failed: %v", err)
+	}
+	if !removed {
+		t.Fatal("expected removed=true for existing profile block")
+	}
+	if _, exists := cm.GetValue("profiles.prod.aws.region"); exists {
+		t.Error("profiles.prod.aws.region should not exist after removal")
+	}
+	if val, _ := cm.GetValue("profiles.prod.gcp.project"); val != "prod-project" {
+		t.Errorf("expected sibling block to be untouched, got %q", val)
+	}
+}
+
+func TestProviderBlockKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"gcp.project", "gcp"},
+		{"aws.endpoint_url", "aws"},
+		{"profiles.prod.aws.region", "profiles.prod.aws"},
+		{"active_profile", "active_profile"},
+	}
+	for _, tt := range tests {
+		if got := ProviderBlockKey(tt.key); got != tt.want {
+			t.Errorf("ProviderBlockKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestProfileConfig_PerProfileGCPCredentials(t *testing.T) {
+	cm := setupProfileConfig(t, `{
+		"gcp": {"project": "default-project"},
+		"profiles": {
+			"prod": {"gcp": {"project": "prod-project", "credentials_file": "/keys/prod.json"}},
+			"staging": {"gcp": {"project": "staging-project"}}
+		}
+	}`)
+
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{DefaultProfileName, "application default credentials"},
+		{"prod", "/keys/prod.json"},
+		{"staging", "application default credentials"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			cfg, err := cm.ProfileConfig(tt.profile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := cfg.GCP.CredentialsSource(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := cm.ProfileConfig("missing"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestLoadConfig_ImpersonationOverride(t *testing.T) {
+	cm := setupProfileConfig(t, `{
+		"gcp": {"project": "default-project", "impersonate_service_account": "config@p.iam.gserviceaccount.com"},
+		"profiles": {"prod": {"gcp": {"project": "prod-project"}}}
+	}`)
+
+	cfg, err := cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GCP.ImpersonateServiceAccount != "config@p.iam.gserviceaccount.com" {
+		t.Errorf("expected configured service account, got %q", cfg.GCP.ImpersonateServiceAccount)
+	}
+
+	if err := cm.SetImpersonationOverride("flag@p.iam.gserviceaccount.com"); err != nil {
+		t.Fatalf("SetImpersonationOverride failed: %v", err)
+	}
+	cm.SetProfileOverride("prod")
+	cfg, err = cm.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.GCP.ImpersonateServiceAccount != "flag@p.iam.gserviceaccount.com" {
+		t.Errorf("expected the override to apply to the selected profile, got %q", cfg.GCP.ImpersonateServiceAccount)
+	}
+
+	if err := cm.SetImpersonationOverride("not-an-email"); err == nil {
+		t.Error("expected error for an invalid service account")
+	}
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// RedactedValue replaces secret values in redacted exports. Importing a file
+// that still contains it is rejected so placeholders never overwrite real secrets.
+const RedactedValue = "<redacted>"
+
+// secretKeyMarkers identify sensitive config keys by substring of their final
+// path segment (e.g., "secret_access_key", "session_token").
+var secretKeyMarkers = []string{"secret", "token", "password"}
+
+// ErrConfigExists is returned by Import when a non-empty configuration would be
+// replaced without the caller opting in to overwriting it.
+var ErrConfigExists = errors.New("a configuration already exists")
+
+// IsSecretKey reports whether a dotted config key holds a sensitive value
+// that should be hidden from redacted exports.
+func IsSecretKey(key string) bool {
+	leaf := strings.ToLower(key[strings.LastIndexByte(key, '.')+1:])
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(leaf, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Export returns the full configuration (all profiles) as indented JSON.
+// When redactSecrets is true, sensitive values are replaced with RedactedValue.
+func (cm *ConfigManager) Export(redactSecrets bool) ([]byte, error) {
+	settings := cm.v.AllSettings()
+	if redactSecrets {
+		settings = redactSettings(settings)
+	}
+	data, err := marshalJSON(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ImportOptions controls how Import combines the imported settings with the
+// existing configuration.
+type ImportOptions struct {
+	// Merge overlays the imported settings onto the existing configuration
+	// instead of replacing it
+	Merge bool
+	// Overwrite allows replacing a non-empty existing configuration
+	Overwrite bool
+}
+
+// Import validates a JSON configuration document and writes it as the new
+// configuration. The existing file is left untouched if validation fails.
+func (cm *ConfigManager) Import(data []byte, opts ImportOptions) error {
+	var imported map[string]any
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return fmt.Errorf("invalid configuration file: %w", err)
+	}
+	imported = lowercaseKeys(imported)
+	// Files exported by older versions are upgraded before they are validated
+	if _, err := migrateSettings(imported); err != nil {
+		return err
+	}
+
+	if key, found := findRedactedValue(imported, ""); found {
+		return fmt.Errorf("key '%s' contains a redacted placeholder; set real values before importing", key)
+	}
+
+	current := cm.v.AllSettings()
+	settings := imported
+	if opts.Merge {
+		settings = mergeSettings(current, imported)
+	} else if hasUserSettings(current) && !opts.Overwrite {
+		return ErrConfigExists
+	}
+
+	var config Config
+	if err := decodeStrict(settings, &config); err != nil {
+		return err
+	}
+	if err := cm.validateConfig(&config); err != nil {
+		return err
+	}
+	if _, err := config.withProfile(config.ActiveProfile, false); err != nil {
+		return err
+	}
+
+	return cm.replaceSettings(settings)
+}
+
+// hasUserSettings reports whether settings holds anything beyond the schema version.
+func hasUserSettings(settings map[string]any) bool {
+	for key := range settings {
+		if key != VersionKey {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSettings returns a deep copy of settings with secret values replaced.
+// Keychain references are kept since they do not contain the secret itself.
+func redactSettings(settings map[string]any) map[string]any {
+	result := make(map[string]any, len(settings))
+	for key, val := range settings {
+		switch v := val.(type) {
+		case map[string]any:
+			result[key] = redactSettings(v)
+		default:
+			_, isRef := keychainRef(v)
+			if IsSecretKey(key) && !isRef && fmt.Sprintf("%v", v) != "" {
+				result[key] = RedactedValue
+			} else {
+				result[key] = v
+			}
+		}
+	}
+	return result
+}
+
+// findRedactedValue returns the dotted key of the first value equal to RedactedValue.
+func findRedactedValue(settings map[string]any, prefix string) (string, bool) {
+	for key, val := range settings {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+		switch v := val.(type) {
+		case map[string]any:
+			if found, ok := findRedactedValue(v, fullKey); ok {
+				return found, true
+			}
+		case string:
+			if v == RedactedValue {
+				return fullKey, true
+			}
+		}
+	}
+	return "", false
+}
+
+// mergeSettings returns a copy of base with overlay applied on top.
+// Nested maps are merged recursively; all other values in overlay win.
+func mergeSettings(base, overlay map[string]any) map[string]any {
+	result := maps.Clone(base)
+	if result == nil {
+		result = make(map[string]any, len(overlay))
+	}
+	for key, val := range overlay {
+		overlayMap, overlayIsMap := val.(map[string]any)
+		baseMap, baseIsMap := result[key].(map[string]any)
+		if overlayIsMap && baseIsMap {
+			result[key] = mergeSettings(baseMap, overlayMap)
+			continue
+		}
+		result[key] = val
+	}
+	return result
+}
+
+// lowercaseKeys normalizes keys the same way Viper does when reading config files.
+func lowercaseKeys(settings map[string]any) map[string]any {
+	result := make(map[string]any, len(settings))
+	for key, val := range settings {
+		if nested, ok := val.(map[string]any); ok {
+			val = lowercaseKeys(nested)
+		}
+		result[strings.ToLower(key)] = val
+	}
+	return result
+}
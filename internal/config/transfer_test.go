@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsSecretKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"gcp.project", false},
+		{"aws.region", false},
+		{"aws.secret_access_key", true},
+		{"profiles.prod.aws.session_token", true},
+		{"api.password", true},
+	}
+	for _, tt := range tests {
+		if got := IsSecretKey(tt.key); got != tt.want {
+			t.Errorf("IsSecretKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestRedactSettings(t *testing.T) {
+	settings := map[string]any{
+		"aws": map[string]any{"region": "us-east-1", "session_token": "abc"},
+	}
+	redacted := redactSettings(settings)
+	aws := redacted["aws"].(map[string]any)
+	if aws["session_token"] != RedactedValue {
+		t.Errorf("expected token to be redacted, got %v", aws["session_token"])
+	}
+	if aws["region"] != "us-east-1" {
+		t.Errorf("expected region to be preserved, got %v", aws["region"])
+	}
+	if settings["aws"].(map[string]any)["session_token"] != "abc" {
+		t.Error("redaction must not modify the original settings")
+	}
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	data, err := cm.Export(false)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+
+	if err := cm.Import(data, ImportOptions{Overwrite: true}); err != nil {
+		t.Fatalf("Import of exported config failed: %v", err)
+	}
+	if val, _ := cm.GetValue("gcp.project"); val != "test-project" {
+		t.Errorf("expected gcp.project=test-project after round trip, got %q", val)
+	}
+}
+
+func TestImport_ExistingConfigRequiresOverwrite(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	err := cm.Import([]byte(`{"aws": {"region": "us-east-1"}}`), ImportOptions{})
+	if !errors.Is(err, ErrConfigExists) {
+		t.Fatalf("expected ErrConfigExists, got %v", err)
+	}
+}
+
+func TestImport_Replace(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	if err := cm.Import([]byte(`{"AWS": {"Region": "us-east-1"}}`), ImportOptions{Overwrite: true}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if _, exists := cm.GetValue("gcp.project"); exists {
+		t.Error("gcp.project should be gone after a replacing import")
+	}
+	if val, _ := cm.GetValue("aws.region"); val != "us-east-1" {
+		t.Errorf("expected aws.region=us-east-1, got %q", val)
+	}
+}
+
+func TestImport_Merge(t *testing.T) {
+	cm, _ := setupTestConfig(t)
+	if err := cm.Import([]byte(`{"aws": {"region": "us-east-1"}}`), ImportOptions{Merge: true}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if val, _ := cm.GetValue("gcp.project"); val != "test-project" {
+		t.Errorf("expected existing gcp.project to survive a merge, got %q", val)
+	}
+	if val, _ := cm.GetValue("aws.region"); val != "us-east-1" {
+		t.Errorf("expected aws.region=us-east-1, got %q", val)
+	}
+}
+
+func TestImport_RejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"malformed JSON", `{invalid`, "invalid configuration file"},
+		{"unknown key", `{"azure": {"tenant": "x"}}`, "unrecognized configuration key"},
+		{"failed validation", `{"aws": {"endpoint_url": "http://localhost:4566"}}`, "validation failed"},
+		{"redacted placeholder", `{"aws": {"region": "us-east-1", "session_token": "<redacted>"}}`, "redacted placeholder"},
+		{"unknown active profile", `{"active_profile": "prod"}`, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm, _ := setupTestConfig(t)
+			err := cm.Import([]byte(tt.content), ImportOptions{Overwrite: true})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if val, _ := cm.GetValue("gcp.project"); val != "test-project" {
+				t.Errorf("existing config must be untouched after a failed import, got %q", val)
+			}
+		})
+	}
+}
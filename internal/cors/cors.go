@@ -0,0 +1,125 @@
+// Package cors checks whether a bucket's CORS rules let a web page make a
+// request, the way a browser finds out: by sending the preflight (OPTIONS)
+// request to the bucket and reading the Access-Control-Allow-* headers of
+// the response.
+package cors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultClient sends preflights when the caller passes no client. Proxy and
+// CA settings apply through http.DefaultTransport.
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Request is the cross-origin request a preflight asks about.
+type Request struct {
+	// Origin is the page's origin (e.g., https://app.example.com)
+	Origin string
+	// Method is the method of the actual request (e.g., PUT)
+	Method string
+	// Headers are the non-simple headers the actual request sends
+	Headers []string
+}
+
+// Result is the outcome of a preflight.
+type Result struct {
+	URL            string   `json:"url" yaml:"url"`
+	Origin         string   `json:"origin" yaml:"origin"`
+	Method         string   `json:"method" yaml:"method"`
+	RequestHeaders []string `json:"request_headers,omitempty" yaml:"request_headers,omitempty"`
+	Status         int      `json:"status" yaml:"status"`
+	Allowed        bool     `json:"allowed" yaml:"allowed"`
+	// Reason says why the request is not allowed
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// The Access-Control-* headers of the response, as sent
+	AllowOrigin      string `json:"allow_origin,omitempty" yaml:"allow_origin,omitempty"`
+	AllowMethods     string `json:"allow_methods,omitempty" yaml:"allow_methods,omitempty"`
+	AllowHeaders     string `json:"allow_headers,omitempty" yaml:"allow_headers,omitempty"`
+	AllowCredentials string `json:"allow_credentials,omitempty" yaml:"allow_credentials,omitempty"`
+	ExposeHeaders    string `json:"expose_headers,omitempty" yaml:"expose_headers,omitempty"`
+	MaxAge           string `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+}
+
+// Preflight sends the preflight for req to url, with client (or a default
+// one if nil), and judges the response as a browser would. A response that
+// denies the request is not an error; failing to get one is.
+func Preflight(ctx context.Context, client *http.Client, url string, req Request) (Result, error) {
+	if client == nil {
+		client = defaultClient
+	}
+	method := strings.ToUpper(req.Method)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodOptions, url, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	httpReq.Header.Set("Origin", req.Origin)
+	httpReq.Header.Set("Access-Control-Request-Method", method)
+	if len(req.Headers) > 0 {
+		httpReq.Header.Set("Access-Control-Request-Headers", strings.ToLower(strings.Join(req.Headers, ",")))
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return Result{}, fmt.Errorf("sending preflight to %s: %w", url, err)
+	}
+	resp.Body.Close()
+
+	result := Result{
+		URL:              url,
+		Origin:           req.Origin,
+		Method:           method,
+		RequestHeaders:   req.Headers,
+		Status:           resp.StatusCode,
+		AllowOrigin:      resp.Header.Get("Access-Control-Allow-Origin"),
+		AllowMethods:     resp.Header.Get("Access-Control-Allow-Methods"),
+		AllowHeaders:     resp.Header.Get("Access-Control-Allow-Headers"),
+		AllowCredentials: resp.Header.Get("Access-Control-Allow-Credentials"),
+		ExposeHeaders:    resp.Header.Get("Access-Control-Expose-Headers"),
+		MaxAge:           resp.Header.Get("Access-Control-Max-Age"),
+	}
+	result.Reason = deniedReason(result)
+	result.Allowed = result.Reason == ""
+	return result, nil
+}
+
+// deniedReason returns why a browser would refuse the request after this
+// preflight response, or "" if it would send it.
+func deniedReason(r Result) string {
+	switch {
+	case r.Status < 200 || r.Status > 299:
+		return fmt.Sprintf("the preflight was answered with status %d", r.Status)
+	case r.AllowOrigin == "":
+		return "no CORS rule matches the origin"
+	case r.AllowOrigin != "*" && r.AllowOrigin != r.Origin:
+		return fmt.Sprintf("the allowed origin is %s", r.AllowOrigin)
+	}
+	// Simple methods need not be listed
+	if !listed(r.AllowMethods, r.Method, true) && !slices.Contains([]string{http.MethodGet, http.MethodHead, http.MethodPost}, r.Method) {
+		return fmt.Sprintf("method %s is not allowed", r.Method)
+	}
+	for _, h := range r.RequestHeaders {
+		if !listed(r.AllowHeaders, h, false) {
+			return fmt.Sprintf("header %s is not allowed", h)
+		}
+	}
+	return ""
+}
+
+// listed reports whether value is in the comma-separated header list, or the
+// list is the "*" wildcard. Methods compare case-sensitively, header names
+// do not.
+func listed(list, value string, caseSensitive bool) bool {
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || item == value || (!caseSensitive && strings.EqualFold(item, value)) {
+			return true
+		}
+	}
+	return false
+}
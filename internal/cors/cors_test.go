@@ -0,0 +1,77 @@
+package cors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newBucketServer answers preflights like a bucket allowing PUT and GET from
+// https://app.example.com with a Content-Type header.
+func newBucketServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			t.Errorf("expected an OPTIONS request, got %s", r.Method)
+		}
+		if r.Header.Get("Origin") != "https://app.example.com" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "https://app.example.com")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Max-Age", "3600")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPreflight(t *testing.T) {
+	server := newBucketServer(t)
+	tests := []struct {
+		name       string
+		req        Request
+		wantReason string
+	}{
+		{"allowed", Request{Origin: "https://app.example.com", Method: "put", Headers: []string{"content-type"}}, ""},
+		{"other origin", Request{Origin: "https://evil.example.com", Method: "PUT"}, "status 403"},
+		{"method", Request{Origin: "https://app.example.com", Method: "DELETE"}, "method DELETE"},
+		{"header", Request{Origin: "https://app.example.com", Method: "PUT", Headers: []string{"X-Api-Key"}}, "header X-Api-Key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Preflight(context.Background(), server.Client(), server.URL+"/bucket/probe", tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Allowed != (tt.wantReason == "") {
+				t.Errorf("allowed = %v, reason %q", result.Allowed, result.Reason)
+			}
+			if tt.wantReason != "" && !strings.Contains(result.Reason, tt.wantReason) {
+				t.Errorf("expected reason to mention %q, got %q", tt.wantReason, result.Reason)
+			}
+		})
+	}
+}
+
+func TestPreflight_RecordsResponseHeaders(t *testing.T) {
+	server := newBucketServer(t)
+	result, err := Preflight(context.Background(), server.Client(), server.URL, Request{Origin: "https://app.example.com", Method: "GET"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.AllowMethods != "GET, PUT" || result.MaxAge != "3600" || result.Status != http.StatusOK {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestPreflight_Unreachable(t *testing.T) {
+	server := newBucketServer(t)
+	server.Close()
+	if _, err := Preflight(context.Background(), nil, server.URL, Request{Origin: "https://app.example.com", Method: "GET"}); err == nil {
+		t.Error("expected an error when the bucket cannot be reached")
+	}
+}
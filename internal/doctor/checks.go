@@ -0,0 +1,171 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/provider/gcpauth"
+	awsstorage "github.com/jkleinne/synkronus/internal/provider/storage/aws"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	serviceusage "google.golang.org/api/serviceusage/v1"
+)
+
+// gcpEndpoints returns the Google APIs synkronus calls for cfg, with the
+// storage endpoint override in place of Cloud Storage if set.
+func gcpEndpoints(cfg *config.GCPConfig) []string {
+	storageEndpoint := "https://storage.googleapis.com"
+	if cfg.StorageEndpoint != "" {
+		storageEndpoint = cfg.StorageEndpoint
+	}
+	return []string{
+		storageEndpoint,
+		"https://monitoring.googleapis.com",
+		"https://sqladmin.googleapis.com",
+	}
+}
+
+// gcpService is a Google API that must be enabled in each configured project.
+type gcpService struct {
+	name string
+	// missing is the status reported when the API is disabled
+	missing Status
+	impact  string
+}
+
+var gcpServices = []gcpService{
+	{name: "storage.googleapis.com", missing: StatusFail, impact: "storage commands will fail"},
+	{name: "monitoring.googleapis.com", missing: StatusWarn, impact: "bucket usage metrics will be unavailable"},
+	{name: "sqladmin.googleapis.com", missing: StatusWarn, impact: "sql commands will fail"},
+}
+
+// checkConfigPermissions verifies the config file and its directory are only
+// accessible by their owner.
+func checkConfigPermissions(configPath string) []Result {
+	const check = "config permissions"
+	if configPath == "" {
+		return nil
+	}
+
+	configDir := filepath.Dir(configPath)
+	var results []Result
+	for _, target := range []struct {
+		path string
+		want os.FileMode
+	}{
+		{configDir, config.ConfigDirPermissions},
+		{configPath, config.ConfigFilePermissions},
+	} {
+		info, err := os.Stat(target.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			results = append(results, Result{Check: check, Status: StatusWarn, Detail: target.path + " does not exist", Fix: "run 'synkronus config set <key> <value>' to create it"})
+			return results
+		}
+		if err != nil {
+			results = append(results, Result{Check: check, Status: StatusFail, Detail: err.Error()})
+			continue
+		}
+
+		mode := info.Mode().Perm()
+		if mode&^target.want != 0 {
+			results = append(results, Result{
+				Check:  check,
+				Status: StatusFail,
+				Detail: fmt.Sprintf("%s has mode %04o; it may be readable by other users", target.path, mode),
+				Fix:    fmt.Sprintf("chmod %04o %s", target.want, target.path),
+			})
+			continue
+		}
+		results = append(results, Result{Check: check, Status: StatusOK, Detail: fmt.Sprintf("%s has mode %04o", target.path, mode)})
+	}
+	return results
+}
+
+// checkGCPCredentials verifies the configured or ambient Google credentials
+// can produce an access token.
+func checkGCPCredentials(ctx context.Context, cfg *config.GCPConfig) Result {
+	source, err := gcpauth.DescribeCredentials(ctx, cfg)
+	if err != nil {
+		fix := "run 'gcloud auth application-default login' or set gcp.credentials_file to a service account key"
+		if cfg.CredentialsFile != "" {
+			fix = "check that gcp.credentials_file points to a valid service account key"
+		}
+		return Result{Check: "gcp credentials", Status: StatusFail, Detail: fmt.Sprintf("%s: %v", source, err), Fix: fix}
+	}
+
+	detail := "using " + source
+	if cfg.ImpersonateServiceAccount != "" {
+		detail += ", impersonating " + cfg.ImpersonateServiceAccount
+	}
+	return Result{Check: "gcp credentials", Status: StatusOK, Detail: detail}
+}
+
+// checkAWSCredentials verifies credentials can be resolved for the AWS config,
+// whether static, from a shared profile, or from the environment.
+func checkAWSCredentials(ctx context.Context, cfg *config.AWSConfig) Result {
+	source, err := awsstorage.CredentialSource(ctx, cfg)
+	if err != nil {
+		fix := "export AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY, set aws.profile, or run 'aws sso login'"
+		if cfg.UsesSSO() {
+			fix = "run 'synkronus auth aws-sso'"
+		}
+		return Result{Check: "aws credentials", Status: StatusFail, Detail: err.Error(), Fix: fix}
+	}
+	return Result{Check: "aws credentials", Status: StatusOK, Detail: "using " + source}
+}
+
+// awsEndpoint returns the S3 endpoint for cfg: the override if set, otherwise
+// the regional endpoint.
+func awsEndpoint(cfg *config.AWSConfig) string {
+	if cfg.EndpointURL != "" {
+		return cfg.EndpointURL
+	}
+	return fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+}
+
+// checkGCPServices verifies the APIs synkronus depends on are enabled in every
+// configured project.
+func checkGCPServices(ctx context.Context, cfg *config.GCPConfig) []Result {
+	clientOpts, err := gcpauth.ClientOptions(ctx, cfg)
+	if err == nil {
+		clientOpts, err = gcpauth.HTTPClientOptions(ctx, clientOpts)
+	}
+	if err != nil {
+		return []Result{{Check: "gcp apis", Status: StatusWarn, Detail: err.Error()}}
+	}
+	svc, err := serviceusage.NewService(ctx, clientOpts...)
+	if err != nil {
+		return []Result{{Check: "gcp apis", Status: StatusWarn, Detail: fmt.Sprintf("failed to create Service Usage client: %v", err)}}
+	}
+
+	var results []Result
+	for _, project := range cfg.AllProjects() {
+		for _, api := range gcpServices {
+			check := fmt.Sprintf("gcp api %s (%s)", api.name, project)
+			service, err := svc.Services.Get(fmt.Sprintf("projects/%s/services/%s", project, api.name)).Context(ctx).Do()
+			if err != nil {
+				results = append(results, Result{
+					Check:  check,
+					Status: StatusWarn,
+					Detail: fmt.Sprintf("could not check: %v", err),
+					Fix:    "grant serviceusage.services.get or enable serviceusage.googleapis.com to let doctor check APIs",
+				})
+				continue
+			}
+			if service.State != "ENABLED" {
+				results = append(results, Result{
+					Check:  check,
+					Status: api.missing,
+					Detail: fmt.Sprintf("not enabled; %s", api.impact),
+					Fix:    fmt.Sprintf("gcloud services enable %s --project %s", api.name, project),
+				})
+				continue
+			}
+			results = append(results, Result{Check: check, Status: StatusOK, Detail: "enabled"})
+		}
+	}
+	return results
+}
@@ -0,0 +1,138 @@
+// Package doctor diagnoses common setup problems: insecure config file
+// permissions, missing credentials, unreachable endpoints, clock skew, and GCP
+// APIs that are not enabled. Each check reports an actionable fix. Probe runs
+// the provider checks alone, concurrently, as a quick health check.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"net/http"
+	"time"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// maxClockSkew is the largest difference from server time tolerated before
+// request signing starts to fail (AWS SigV4 allows 15 minutes; Google OAuth
+// assertions are stricter)
+const maxClockSkew = 5 * time.Minute
+
+// Result describes the outcome of one check and, when it did not pass, how to fix it.
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// Options configures a diagnostics run.
+type Options struct {
+	// ConfigPath is the global config file whose permissions are checked
+	ConfigPath string
+	// Config is the loaded configuration, with the active profile applied
+	Config *config.Config
+	// HTTPClient is used for endpoint reachability and clock skew checks
+	HTTPClient *http.Client
+}
+
+// Run performs all checks that apply to the configured providers and returns
+// their results in a stable order.
+func Run(ctx context.Context, opts Options) []Result {
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	results := checkConfigPermissions(opts.ConfigPath)
+
+	var endpoints []string
+	if config.IsGCPConfigured(opts.Config) {
+		results = append(results, checkGCPCredentials(ctx, opts.Config.GCP))
+		endpoints = append(endpoints, gcpEndpoints(opts.Config.GCP)...)
+	}
+	if opts.Config.AWS != nil && opts.Config.AWS.Region != "" {
+		results = append(results, checkAWSCredentials(ctx, opts.Config.AWS))
+		endpoints = append(endpoints, awsEndpoint(opts.Config.AWS))
+	}
+	if len(endpoints) == 0 {
+		results = append(results, Result{
+			Check:  "providers",
+			Status: StatusWarn,
+			Detail: "no provider is configured",
+			Fix:    "run 'synkronus config set gcp.project <id>' or 'synkronus config set aws.region <region>'",
+		})
+		return results
+	}
+
+	results = append(results, checkEndpoints(ctx, client, endpoints, time.Now)...)
+
+	if config.IsGCPConfigured(opts.Config) {
+		results = append(results, checkGCPServices(ctx, opts.Config.GCP)...)
+	}
+	return results
+}
+
+// Failed reports whether any result has StatusFail.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEndpoints issues a HEAD request to each endpoint. Any HTTP response
+// counts as reachable; the Date header of the first response is compared with
+// the local clock.
+func checkEndpoints(ctx context.Context, client *http.Client, endpoints []string, now func() time.Time) []Result {
+	var results []Result
+	var serverTime time.Time
+	for _, endpoint := range endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+		if err != nil {
+			results = append(results, Result{Check: "endpoint " + endpoint, Status: StatusFail, Detail: err.Error(), Fix: "check the endpoint URL in the config"})
+			continue
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			results = append(results, Result{
+				Check:  "endpoint " + endpoint,
+				Status: StatusFail,
+				Detail: err.Error(),
+				Fix:    "check network connectivity, DNS, and any proxy or firewall between you and " + endpoint,
+			})
+			continue
+		}
+		resp.Body.Close()
+
+		results = append(results, Result{Check: "endpoint " + endpoint, Status: StatusOK, Detail: resp.Status})
+		if serverTime.IsZero() {
+			if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+				serverTime = date
+			}
+		}
+	}
+
+	if serverTime.IsZero() {
+		return append(results, Result{Check: "clock skew", Status: StatusWarn, Detail: "no endpoint returned a Date header to compare against"})
+	}
+	return append(results, clockSkewResult(now().Sub(serverTime)))
+}
+
+func clockSkewResult(skew time.Duration) Result {
+	detail := fmt.Sprintf("local clock differs from server time by %s", skew.Round(time.Second))
+	if skew.Abs() > maxClockSkew {
+		return Result{Check: "clock skew", Status: StatusFail, Detail: detail, Fix: "enable time synchronization (NTP) on this machine"}
+	}
+	return Result{Check: "clock skew", Status: StatusOK, Detail: detail}
+}
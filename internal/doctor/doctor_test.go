@@ -0,0 +1,213 @@
+package doctor
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, dirMode, fileMode os.FileMode) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), config.ConfigDirName)
+	if err := os.Mkdir(dir, dirMode); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	path := filepath.Join(dir, config.ConfigFileName)
+	if err := os.WriteFile(path, []byte("{}"), fileMode); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	// Undo the umask so the requested modes are applied exactly
+	if err := os.Chmod(dir, dirMode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, fileMode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckConfigPermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		dirMode  os.FileMode
+		fileMode os.FileMode
+		want     []Status
+	}{
+		{"secure", 0700, 0600, []Status{StatusOK, StatusOK}},
+		{"world readable file", 0700, 0644, []Status{StatusOK, StatusFail}},
+		{"group accessible dir", 0750, 0600, []Status{StatusFail, StatusOK}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := checkConfigPermissions(writeConfig(t, tt.dirMode, tt.fileMode))
+			if len(results) != len(tt.want) {
+				t.Fatalf("expected %d results, got %+v", len(tt.want), results)
+			}
+			for i, r := range results {
+				if r.Status != tt.want[i] {
+					t.Errorf("result %d: expected %s, got %s (%s)", i, tt.want[i], r.Status, r.Detail)
+				}
+				if r.Status == StatusFail && r.Fix == "" {
+					t.Errorf("result %d: expected a fix for a failed check", i)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckConfigPermissions_MissingFile(t *testing.T) {
+	results := checkConfigPermissions(filepath.Join(t.TempDir(), "missing", config.ConfigFileName))
+	if len(results) != 1 || results[0].Status != StatusWarn {
+		t.Errorf("expected a single warning, got %+v", results)
+	}
+}
+
+func TestCheckEndpoints(t *testing.T) {
+	serverTime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		localTime time.Time
+		wantSkew  Status
+	}{
+		{"in sync", serverTime.Add(2 * time.Second), StatusOK},
+		{"clock ahead", serverTime.Add(20 * time.Minute), StatusFail},
+		{"clock behind", serverTime.Add(-10 * time.Minute), StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := checkEndpoints(context.Background(), srv.Client(), []string{srv.URL}, func() time.Time { return tt.localTime })
+			if len(results) != 2 {
+				t.Fatalf("expected endpoint and clock results, got %+v", results)
+			}
+			if results[0].Status != StatusOK {
+				t.Errorf("expected endpoint reachable despite 404, got %+v", results[0])
+			}
+			if results[1].Status != tt.wantSkew {
+				t.Errorf("expected clock skew %s, got %+v", tt.wantSkew, results[1])
+			}
+		})
+	}
+}
+
+func TestCheckEndpoints_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	results := checkEndpoints(context.Background(), &http.Client{Timeout: time.Second}, []string{url}, time.Now)
+	if len(results) != 2 {
+		t.Fatalf("expected endpoint and clock results, got %+v", results)
+	}
+	if results[0].Status != StatusFail || results[0].Fix == "" {
+		t.Errorf("expected a failure with a fix, got %+v", results[0])
+	}
+	if results[1].Status != StatusWarn {
+		t.Errorf("expected clock skew to be unknown, got %+v", results[1])
+	}
+}
+
+func TestAWSEndpoint(t *testing.T) {
+	if got := awsEndpoint(&config.AWSConfig{Region: "eu-west-1"}); got != "https://s3.eu-west-1.amazonaws.com" {
+		t.Errorf("unexpected regional endpoint %q", got)
+	}
+	if got := awsEndpoint(&config.AWSConfig{Region: "us-east-1", EndpointURL: "http://localhost:4566"}); got != "http://localhost:4566" {
+		t.Errorf("expected endpoint override, got %q", got)
+	}
+}
+
+func TestProbe_AWS(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	health := Probe(context.Background(), Options{
+		Config: &config.Config{AWS: &config.AWSConfig{Region: "us-east-1", EndpointURL: srv.URL}},
+	})
+	if len(health) != 1 || health[0].Provider != "aws" {
+		t.Fatalf("expected a single aws result, got %+v", health)
+	}
+	h := health[0]
+	if h.Status != StatusOK || h.Auth.Status != StatusOK || h.Endpoint.Status != StatusOK {
+		t.Errorf("expected a healthy provider, got %+v", h)
+	}
+	if h.APIs != nil {
+		t.Errorf("expected no API check for aws, got %+v", h.APIs)
+	}
+	if Unhealthy(health) {
+		t.Error("expected Unhealthy to be false")
+	}
+}
+
+func TestProbe_Unreachable(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	health := Probe(context.Background(), Options{
+		Config:     &config.Config{AWS: &config.AWSConfig{Region: "us-east-1", EndpointURL: url}},
+		HTTPClient: &http.Client{Timeout: time.Second},
+	})
+	if len(health) != 1 || health[0].Status != StatusFail || health[0].Endpoint.Fix == "" {
+		t.Fatalf("expected an unreachable endpoint with a fix, got %+v", health)
+	}
+	if health[0].LatencyMs != 0 {
+		t.Errorf("expected no latency for a failed request, got %d", health[0].LatencyMs)
+	}
+	if !Unhealthy(health) {
+		t.Error("expected Unhealthy to be true")
+	}
+}
+
+func TestProbe_NoProviders(t *testing.T) {
+	if health := Probe(context.Background(), Options{Config: &config.Config{}}); len(health) != 0 {
+		t.Errorf("expected no results, got %+v", health)
+	}
+}
+
+func TestSummarizeAPIs(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []Result
+		wantStatus Status
+		wantDetail string
+	}{
+		{"all enabled", []Result{{Status: StatusOK}, {Status: StatusOK}}, StatusOK, "required APIs enabled"},
+		{
+			"one disabled",
+			[]Result{
+				{Check: "gcp api storage.googleapis.com (p)", Status: StatusOK},
+				{Check: "gcp api monitoring.googleapis.com (p)", Status: StatusWarn, Detail: "not enabled", Fix: "gcloud services enable monitoring.googleapis.com --project p"},
+			},
+			StatusWarn,
+			"monitoring.googleapis.com (p): not enabled",
+		},
+		{"worst wins", []Result{{Status: StatusWarn}, {Status: StatusFail}, {Status: StatusOK}}, StatusFail, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeAPIs(tt.results)
+			if got.Status != tt.wantStatus {
+				t.Errorf("got status %s, want %s", got.Status, tt.wantStatus)
+			}
+			if tt.wantDetail != "" && got.Detail != tt.wantDetail {
+				t.Errorf("got detail %q, want %q", got.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
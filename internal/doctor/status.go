@@ -0,0 +1,143 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderHealth is the outcome of probing one configured provider: whether its
+// credentials work, whether its API answers (and how fast), and whether the
+// APIs it needs are enabled.
+type ProviderHealth struct {
+	Provider string `json:"provider"`
+	// Status is the worst status among the checks below
+	Status   Status `json:"status"`
+	Auth     Result `json:"auth"`
+	Endpoint Result `json:"endpoint"`
+	// APIs is nil for providers with nothing to enable (AWS)
+	APIs *Result `json:"apis,omitempty" yaml:"apis,omitempty"`
+	// LatencyMs is the round trip of the endpoint request; 0 if it failed
+	LatencyMs int64 `json:"latency_ms" yaml:"latency_ms"`
+}
+
+// Probe checks every configured provider concurrently and returns their health
+// in a stable order (GCP, then AWS). Unlike Run it skips local checks, so it is
+// quick enough to run before each long job.
+func Probe(ctx context.Context, opts Options) []ProviderHealth {
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var probes []func() ProviderHealth
+	if config.IsGCPConfigured(opts.Config) {
+		gcp := opts.Config.GCP
+		probes = append(probes, func() ProviderHealth {
+			health := ProviderHealth{Provider: "gcp", Auth: checkGCPCredentials(ctx, gcp)}
+			health.Endpoint, health.LatencyMs = probeEndpoint(ctx, client, "gcp", gcpEndpoints(gcp)[0])
+			apis := summarizeAPIs(checkGCPServices(ctx, gcp))
+			health.APIs = &apis
+			return health
+		})
+	}
+	if opts.Config.AWS != nil && opts.Config.AWS.Region != "" {
+		aws := opts.Config.AWS
+		probes = append(probes, func() ProviderHealth {
+			health := ProviderHealth{Provider: "aws", Auth: checkAWSCredentials(ctx, aws)}
+			health.Endpoint, health.LatencyMs = probeEndpoint(ctx, client, "aws", awsEndpoint(aws))
+			return health
+		})
+	}
+
+	results := make([]ProviderHealth, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			health := probe()
+			health.Status = worstStatus(health)
+			results[i] = health
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Unhealthy reports whether any provider has a failed check.
+func Unhealthy(health []ProviderHealth) bool {
+	for _, h := range health {
+		if h.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// probeEndpoint times a HEAD request to endpoint, returning the round trip in
+// milliseconds. Any HTTP response counts as reachable.
+func probeEndpoint(ctx context.Context, client *http.Client, provider, endpoint string) (Result, int64) {
+	check := provider + " endpoint"
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if err != nil {
+		return Result{Check: check, Status: StatusFail, Detail: err.Error(), Fix: "check the endpoint URL in the config"}, 0
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return Result{
+			Check:  check,
+			Status: StatusFail,
+			Detail: err.Error(),
+			Fix:    "check network connectivity, DNS, and any proxy or firewall between you and " + endpoint,
+		}, 0
+	}
+	resp.Body.Close()
+	return Result{Check: check, Status: StatusOK, Detail: fmt.Sprintf("%s answered %s", endpoint, resp.Status)}, latency.Milliseconds()
+}
+
+// summarizeAPIs folds the per-project API checks into one result: the worst
+// status, with the details of the checks that did not pass.
+func summarizeAPIs(results []Result) Result {
+	summary := Result{Check: "gcp apis", Status: StatusOK, Detail: "required APIs enabled"}
+	var problems, fixes []string
+	for _, r := range results {
+		summary.Status = worse(summary.Status, r.Status)
+		if r.Status != StatusOK {
+			problems = append(problems, fmt.Sprintf("%s: %s", strings.TrimPrefix(r.Check, "gcp api "), r.Detail))
+			if r.Fix != "" {
+				fixes = append(fixes, r.Fix)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		summary.Detail = strings.Join(problems, "; ")
+		summary.Fix = strings.Join(fixes, "; ")
+	}
+	return summary
+}
+
+// worstStatus returns the most severe status among h's checks.
+func worstStatus(h ProviderHealth) Status {
+	status := worse(h.Auth.Status, h.Endpoint.Status)
+	if h.APIs != nil {
+		status = worse(status, h.APIs.Status)
+	}
+	return status
+}
+
+// worse returns the more severe of a and b.
+func worse(a, b Status) Status {
+	rank := map[Status]int{StatusOK: 0, StatusWarn: 1, StatusFail: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
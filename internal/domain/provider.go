@@ -6,4 +6,6 @@ type Provider string
 const (
 	GCP Provider = "GCP"
 	AWS Provider = "AWS"
+	// Mock is the in-memory provider used by tests and demo mode
+	Mock Provider = "MOCK"
 )
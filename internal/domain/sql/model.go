@@ -1,7 +1,7 @@
 package sql
 
 import (
-	"synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain"
 	"time"
 )
 
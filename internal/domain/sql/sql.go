@@ -2,7 +2,7 @@ package sql
 
 import (
 	"context"
-	"synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain"
 )
 
 // SQL defines the interface for interacting with managed SQL database instances
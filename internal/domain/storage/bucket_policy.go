@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// policyDocument represents the JSON structure of an S3 bucket policy.
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Effect    string `json:"Effect"`
+	Principal any    `json:"Principal"` // Can be string ("*") or map
+	Action    any    `json:"Action"`    // Can be string or []string
+	Resource  any    `json:"Resource"`  // Can be string or []string
+	Condition any    `json:"Condition,omitempty"`
+}
+
+// ParseBucketPolicy parses an S3 bucket policy document into its statements.
+// Principals, actions and resources may each be a string or a list, and
+// principals a map such as {"AWS": [...]}.
+func ParseBucketPolicy(policyJSON string) ([]PolicyStatement, error) {
+	if policyJSON == "" {
+		return nil, nil
+	}
+
+	var doc policyDocument
+	if err := json.Unmarshal([]byte(policyJSON), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
+	}
+
+	var statements []PolicyStatement
+	for _, s := range doc.Statement {
+		stmt := PolicyStatement{
+			Effect:     s.Effect,
+			Principals: flattenStringOrSlice(s.Principal),
+			Actions:    flattenStringOrSlice(s.Action),
+			Resources:  flattenStringOrSlice(s.Resource),
+			Conditions: flattenConditions(s.Condition),
+		}
+		statements = append(statements, stmt)
+	}
+	return statements, nil
+}
+
+// flattenStringOrSlice handles JSON fields that can be a string, a []string, or a map with a key like "AWS".
+func flattenStringOrSlice(v any) []string {
+	if v == nil {
+		return nil
+	}
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []any:
+		result := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	case map[string]any:
+		// Handle {"AWS": "arn:..."} or {"AWS": ["arn:...", "arn:..."]}
+		var result []string
+		for _, sub := range val {
+			result = append(result, flattenStringOrSlice(sub)...)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// flattenConditions parses the Condition block of an IAM policy statement.
+func flattenConditions(v any) map[string]map[string][]string {
+	if v == nil {
+		return nil
+	}
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]map[string][]string, len(raw))
+	for operator, keysRaw := range raw {
+		keys, ok := keysRaw.(map[string]any)
+		if !ok {
+			continue
+		}
+		inner := make(map[string][]string, len(keys))
+		for key, valRaw := range keys {
+			inner[key] = flattenStringOrSlice(valRaw)
+		}
+		result[operator] = inner
+	}
+	return result
+}
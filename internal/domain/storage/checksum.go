@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Checksum algorithms an upload can ask the provider to verify, and that
+// objects report. S3 supports all but MD5 as additional checksums; Cloud
+// Storage computes MD5 and CRC32C for every object.
+const (
+	ChecksumCRC32C = "CRC32C"
+	ChecksumCRC32  = "CRC32"
+	ChecksumSHA256 = "SHA256"
+	ChecksumMD5    = "MD5"
+)
+
+// Checksum types of S3 objects
+const (
+	// ChecksumTypeFullObject is a checksum of the whole object
+	ChecksumTypeFullObject = "FULL_OBJECT"
+	// ChecksumTypeComposite is a checksum of the checksums of the parts of a
+	// multipart upload, reported with a "-<parts>" suffix
+	ChecksumTypeComposite = "COMPOSITE"
+)
+
+// ChecksumAlgorithms are the algorithms known to NewChecksum, strongest first.
+var ChecksumAlgorithms = []string{ChecksumSHA256, ChecksumCRC32C, ChecksumCRC32, ChecksumMD5}
+
+// NewChecksum returns a hash computing algorithm (case-insensitive).
+func NewChecksum(algorithm string) (hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	case ChecksumCRC32:
+		return crc32.NewIEEE(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumMD5:
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown checksum algorithm %q (expected %s)", algorithm, strings.Join(ChecksumAlgorithms, ", "))
+	}
+}
+
+// Checksum returns an object's checksum for algorithm, as Base64, or "" if it
+// reports none.
+func (o Object) Checksum(algorithm string) string {
+	switch strings.ToUpper(algorithm) {
+	case ChecksumCRC32C:
+		return o.CRC32C
+	case ChecksumCRC32:
+		return o.CRC32
+	case ChecksumSHA256:
+		return o.SHA256
+	case ChecksumMD5:
+		return o.MD5Hash
+	default:
+		return ""
+	}
+}
+
+// StrongestChecksum returns the strongest checksum an object reports, by
+// ChecksumAlgorithms order, or empty strings if it reports none.
+func (o Object) StrongestChecksum() (algorithm, value string) {
+	for _, alg := range ChecksumAlgorithms {
+		if v := o.Checksum(alg); v != "" {
+			return alg, v
+		}
+	}
+	return "", ""
+}
+
+// ComputeChecksum reads r to the end and returns its checksum as Base64, as
+// providers report it. With a positive partSize, it returns the composite
+// checksum S3 reports for a multipart upload of that part size: the checksum
+// of the parts' checksums, followed by "-<parts>".
+func ComputeChecksum(r io.Reader, algorithm string, partSize int64) (string, error) {
+	if partSize <= 0 {
+		h, err := NewChecksum(algorithm)
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.Copy(h, r); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+	}
+
+	var parts [][]byte
+	for {
+		h, err := NewChecksum(algorithm)
+		if err != nil {
+			return "", err
+		}
+		n, err := io.CopyN(h, r, partSize)
+		if n > 0 || len(parts) == 0 {
+			parts = append(parts, h.Sum(nil))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return CompositeChecksum(algorithm, parts)
+}
+
+// CompositeChecksum combines the binary checksums of the parts of a multipart
+// upload the way S3 does.
+func CompositeChecksum(algorithm string, parts [][]byte) (string, error) {
+	h, err := NewChecksum(algorithm)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(parts)), nil
+}
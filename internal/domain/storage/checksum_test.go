@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+func TestComputeChecksum(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		data      string
+		want      string
+	}{
+		{ChecksumSHA256, "hello", "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="},
+		{ChecksumCRC32, "hello", "NhCmhg=="},
+		{"crc32c", "123456789", "4waSgw=="},
+		{ChecksumMD5, "hello", "XUFAKrxLKna5cZ2REBfFkg=="},
+	}
+	for _, tt := range tests {
+		got, err := ComputeChecksum(strings.NewReader(tt.data), tt.algorithm, 0)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.algorithm, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s(%q) = %s, want %s", tt.algorithm, tt.data, got, tt.want)
+		}
+	}
+
+	if _, err := ComputeChecksum(strings.NewReader("hello"), "SHA1", 0); err == nil {
+		t.Error("expected an error for an unknown algorithm")
+	}
+}
+
+func TestComputeChecksum_Composite(t *testing.T) {
+	first, second := sha256.Sum256([]byte("hello ")), sha256.Sum256([]byte("world"))
+	want, err := CompositeChecksum(ChecksumSHA256, [][]byte{first[:], second[:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(want, "-2") {
+		t.Errorf("expected a part count suffix, got %s", want)
+	}
+
+	got, err := ComputeChecksum(strings.NewReader("hello world"), ChecksumSHA256, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("composite = %s, want %s", got, want)
+	}
+}
+
+func TestStrongestChecksum(t *testing.T) {
+	alg, value := Object{MD5Hash: "md5", CRC32C: "crc"}.StrongestChecksum()
+	if alg != ChecksumCRC32C || value != "crc" {
+		t.Errorf("got %s %s, want CRC32C crc", alg, value)
+	}
+	if alg, _ := (Object{}).StrongestChecksum(); alg != "" {
+		t.Errorf("expected no checksum, got %s", alg)
+	}
+}
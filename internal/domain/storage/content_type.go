@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"cmp"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SniffLen is how many leading bytes of an object DetectContentType looks at
+const SniffLen = 512
+
+// ContentTypeRules map glob patterns (path.Match syntax) of object keys to the
+// content type of the objects they match. A pattern without a "/" is matched
+// against the last element of the key (e.g., "*.wasm"), one with a "/"
+// against the whole key.
+type ContentTypeRules map[string]string
+
+// ParseContentTypeRules checks that rules hold well-formed patterns and
+// content types, returning them as ContentTypeRules.
+func ParseContentTypeRules(rules map[string]string) (ContentTypeRules, error) {
+	for pattern, contentType := range rules {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid content type pattern %q: %w", pattern, err)
+		}
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			return nil, fmt.Errorf("invalid content type %q for %q: %w", contentType, pattern, err)
+		}
+	}
+	return ContentTypeRules(rules), nil
+}
+
+// Match returns the content type of the rule matching key, or "" if none
+// does. When several rules match, the longest pattern wins, so
+// "assets/*.js" overrides "*.js".
+func (r ContentTypeRules) Match(key string) string {
+	patterns := make([]string, 0, len(r))
+	for pattern := range r {
+		patterns = append(patterns, pattern)
+	}
+	slices.SortFunc(patterns, func(a, b string) int {
+		return cmp.Or(len(b)-len(a), strings.Compare(a, b))
+	})
+	for _, pattern := range patterns {
+		name := key
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(key)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return r[pattern]
+		}
+	}
+	return ""
+}
+
+// DetectContentType returns the content type of an object: the type
+// registered for its key's extension or, failing that, the type sniffed from
+// head, the leading bytes of its data. It returns "" when neither says more
+// than "application/octet-stream", leaving the provider's default.
+func DetectContentType(key string, head []byte) string {
+	if ext := filepath.Ext(key); ext != "" {
+		if contentType := mime.TypeByExtension(ext); contentType != "" {
+			return contentType
+		}
+	}
+	if len(head) == 0 {
+		return ""
+	}
+	if contentType := http.DetectContentType(head[:min(len(head), SniffLen)]); contentType != "application/octet-stream" {
+		return contentType
+	}
+	return ""
+}
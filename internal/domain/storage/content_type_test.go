@@ -0,0 +1,60 @@
+package storage
+
+import "testing"
+
+func TestContentTypeRules_Match(t *testing.T) {
+	rules, err := ParseContentTypeRules(map[string]string{
+		"*.wasm":      "application/wasm",
+		"*.js":        "text/javascript",
+		"legacy/*.js": "application/javascript",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"app/main.wasm", "application/wasm"},
+		{"app/main.js", "text/javascript"},
+		{"legacy/main.js", "application/javascript"},
+		{"index.html", ""},
+	}
+	for _, tt := range tests {
+		if got := rules.Match(tt.key); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParseContentTypeRules_Invalid(t *testing.T) {
+	if _, err := ParseContentTypeRules(map[string]string{"[unclosed": "text/plain"}); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+	if _, err := ParseContentTypeRules(map[string]string{"*.txt": ""}); err == nil {
+		t.Error("expected an error for an empty content type")
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		head string
+		want string
+	}{
+		{"extension", "logo.png", "", "image/png"},
+		{"extension wins over sniffing", "notes.txt", "<html>", "text/plain; charset=utf-8"},
+		{"sniffed", "index", "<!DOCTYPE html><html></html>", "text/html; charset=utf-8"},
+		{"unknown", "blob", "\x00\x01\x02", ""},
+		{"no data", "blob", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContentType(tt.key, []byte(tt.head)); got != tt.want {
+				t.Errorf("DetectContentType(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// BucketURLResolver is implemented by providers that can give the HTTPS URL
+// of a bucket, for requests made outside their SDK (e.g., CORS preflights) and
+// for showing object URLs.
+type BucketURLResolver interface {
+	// BucketURL returns the URL of the bucket, without a trailing slash, in
+	// style (URLStylePath or URLStyleVirtualHosted; empty for the provider's
+	// usual one).
+	BucketURL(ctx context.Context, bucketName, style string) (string, error)
+}
+
+// ObjectURL returns the URL of an object from its bucket's URL, escaping the
+// key but keeping its slashes.
+func ObjectURL(bucketURL, key string) string {
+	return strings.TrimSuffix(bucketURL, "/") + "/" + (&url.URL{Path: key}).EscapedPath()
+}
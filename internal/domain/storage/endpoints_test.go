@@ -0,0 +1,18 @@
+package storage
+
+import "testing"
+
+func TestObjectURL(t *testing.T) {
+	tests := []struct {
+		bucketURL, key, want string
+	}{
+		{"https://storage.googleapis.com/data", "a/b.txt", "https://storage.googleapis.com/data/a/b.txt"},
+		{"https://data.s3.eu-west-1.amazonaws.com/", "reports/Q1 2024.pdf", "https://data.s3.eu-west-1.amazonaws.com/reports/Q1%202024.pdf"},
+		{"https://storage.googleapis.com/data", "", "https://storage.googleapis.com/data/"},
+	}
+	for _, tt := range tests {
+		if got := ObjectURL(tt.bucketURL, tt.key); got != tt.want {
+			t.Errorf("ObjectURL(%q, %q) = %q, want %q", tt.bucketURL, tt.key, got, tt.want)
+		}
+	}
+}
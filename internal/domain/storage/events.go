@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// ObjectEventType is the kind of change an ObjectEvent reports.
+type ObjectEventType string
+
+const (
+	ObjectCreated ObjectEventType = "created"
+	ObjectDeleted ObjectEventType = "deleted"
+	// ObjectArchived means a live version became noncurrent (GCP versioned buckets)
+	ObjectArchived        ObjectEventType = "archived"
+	ObjectMetadataUpdated ObjectEventType = "metadata-updated"
+	// ObjectOther covers the events synkronus has no kind for, such as S3
+	// restores and lifecycle transitions; see ObjectEvent.Name
+	ObjectOther ObjectEventType = "other"
+)
+
+// ObjectEvent is a change to an object, as delivered by the bucket's event
+// notifications.
+type ObjectEvent struct {
+	Time time.Time       `json:"time" yaml:"time"`
+	Type ObjectEventType `json:"type" yaml:"type"`
+	// Name is the provider's name for the event (e.g., OBJECT_FINALIZE, ObjectCreated:Put)
+	Name   string `json:"name" yaml:"name"`
+	Bucket string `json:"bucket" yaml:"bucket"`
+	Key    string `json:"key" yaml:"key"`
+	// Size is reported for created objects only, and not by every notification format
+	Size int64 `json:"size,omitempty" yaml:"size,omitempty"`
+	// Version is the object's generation (GCP) or version ID (AWS), if any
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// EventTailer is implemented by providers that can stream the object events
+// of a bucket from its event notifications.
+type EventTailer interface {
+	// TailEvents calls fn with each object event of bucketName as it arrives,
+	// until ctx is done (which is not an error) or fn fails. ready is called
+	// once listening has started, with a description of where the events come
+	// from (e.g., the Pub/Sub topic or SQS queue). Only changes made after
+	// ready is called are guaranteed to be seen.
+	TailEvents(ctx context.Context, bucketName string, ready func(source string), fn func(ObjectEvent) error) error
+}
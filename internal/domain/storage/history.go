@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// ObjectChangeType is the kind of change an ObjectChange records.
+type ObjectChangeType string
+
+const (
+	ChangeWrite    ObjectChangeType = "write"
+	ChangeDelete   ObjectChangeType = "delete"
+	ChangeACL      ObjectChangeType = "acl"
+	ChangeMetadata ObjectChangeType = "metadata"
+	// ChangeOther covers the calls synkronus has no kind for, such as
+	// restores and retention changes; see ObjectChange.Operation
+	ChangeOther ObjectChangeType = "other"
+)
+
+// ObjectChange is a call that changed (or tried to change) an object, as
+// recorded by the provider's audit logs.
+type ObjectChange struct {
+	Time time.Time        `json:"time" yaml:"time"`
+	Type ObjectChangeType `json:"type" yaml:"type"`
+	// Operation is the provider's name for the call (e.g., storage.objects.create, PutObject)
+	Operation string `json:"operation" yaml:"operation"`
+	// Principal is who made the call: an account email (GCP) or IAM ARN (AWS)
+	Principal string `json:"principal,omitempty" yaml:"principal,omitempty"`
+	SourceIP  string `json:"source_ip,omitempty" yaml:"source_ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	// Error is set for calls that failed (e.g., were denied), which changed nothing
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ObjectHistoryReader is implemented by providers that can search their
+// audit logs for the calls made on an object.
+type ObjectHistoryReader interface {
+	// ObjectHistory returns up to limit changes (all if limit is 0) to
+	// objectKey made since the given time, newest first. Only what the bucket's audit logging records
+	// is seen: object changes are data events, which are usually opt-in.
+	ObjectHistory(ctx context.Context, bucketName, objectKey string, since time.Time, limit int) ([]ObjectChange, error)
+}
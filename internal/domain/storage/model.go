@@ -2,13 +2,18 @@ package storage
 
 import (
 	"fmt"
-	"synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Bucket struct {
 	Name         string          `json:"name" yaml:"name"`
 	Provider     domain.Provider `json:"provider" yaml:"provider"`
+	Project      string          `json:"project,omitempty" yaml:"project,omitempty"` // GCP only: the project the bucket was listed from
+	Account      string          `json:"account,omitempty" yaml:"account,omitempty"` // AWS only: the account the bucket was listed from via an assumed role
 	Location     string          `json:"location" yaml:"location"`
 	LocationType string          `json:"location_type,omitempty" yaml:"location_type,omitempty"`
 	StorageClass string          `json:"storage_class" yaml:"storage_class"`
@@ -60,7 +65,15 @@ type Object struct {
 
 	// Checksums (Base64 encoded strings)
 	MD5Hash string `json:"md5_hash,omitempty" yaml:"md5_hash,omitempty"`
-	CRC32C  string `json:"crc32c,omitempty" yaml:"crc32c,omitempty"` // GCP specific
+	CRC32C  string `json:"crc32c,omitempty" yaml:"crc32c,omitempty"`
+	CRC32   string `json:"crc32,omitempty" yaml:"crc32,omitempty"`   // AWS specific
+	SHA256  string `json:"sha256,omitempty" yaml:"sha256,omitempty"` // AWS specific
+	// ChecksumType is FULL_OBJECT or COMPOSITE for objects with an S3
+	// additional checksum. A composite checksum ends in "-<parts>".
+	ChecksumType string `json:"checksum_type,omitempty" yaml:"checksum_type,omitempty"` // AWS specific
+	// PartSize is the size of the parts a multipart upload was made of, their
+	// last excepted; 0 for objects uploaded whole
+	PartSize int64 `json:"part_size,omitempty" yaml:"part_size,omitempty"` // AWS specific
 
 	// Versioning information
 	Generation     int64  `json:"generation,omitempty" yaml:"generation,omitempty"`         // GCP specific
@@ -103,6 +116,10 @@ type Encryption struct {
 type RetentionPolicy struct {
 	RetentionPeriod time.Duration `json:"retention_period" yaml:"retention_period"`
 	IsLocked        bool          `json:"is_locked" yaml:"is_locked"`
+	// AWS only: the default retention mode of Object Lock (GOVERNANCE or COMPLIANCE)
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// GCP only: when the policy (or an earlier one with a shorter period) took effect
+	EffectiveTime *time.Time `json:"effective_time,omitempty" yaml:"effective_time,omitempty"`
 }
 
 // IAMPolicy represents the IAM policy attached to a resource
@@ -111,6 +128,9 @@ type IAMPolicy struct {
 	Bindings []IAMBinding `json:"bindings,omitempty" yaml:"bindings,omitempty"`
 	// AWS: S3 bucket policy statements
 	Statements []PolicyStatement `json:"statements,omitempty" yaml:"statements,omitempty"`
+	// GCP: the etag of the policy; setting a policy with it fails if the
+	// policy has changed since
+	Etag string `json:"etag,omitempty" yaml:"etag,omitempty"`
 }
 
 // PolicyStatement represents a single statement in an AWS S3 bucket policy
@@ -180,6 +200,85 @@ func FormatBytes(bytes int64) string {
 	return fmt.Sprintf("%.1f %s", float64(bytes)/float64(divisor), sizes[sizeIndex])
 }
 
+// ParseBytes parses a size such as "512", "64KB", "1.5 GB" or "10MiB". Units
+// are binary, as in FormatBytes, so "1KB" and "1KiB" are both 1024 bytes.
+func ParseBytes(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	digits := strings.IndexFunc(trimmed, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if digits == -1 {
+		digits = len(trimmed)
+	}
+	number, unit := trimmed[:digits], strings.ToUpper(strings.TrimSpace(trimmed[digits:]))
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: expected a number with an optional unit (B, KB, MB, GB, TB)", s)
+	}
+	var multiplier int64
+	switch unit {
+	case "", "B":
+		multiplier = 1
+	case "K", "KB", "KIB":
+		multiplier = 1 << 10
+	case "M", "MB", "MIB":
+		multiplier = 1 << 20
+	case "G", "GB", "GIB":
+		multiplier = 1 << 30
+	case "T", "TB", "TIB":
+		multiplier = 1 << 40
+	default:
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q (use B, KB, MB, GB or TB)", s, unit)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// ParseAge parses an age such as "90d" or "36h": a whole number of days, or a
+// Go duration.
+func ParseAge(s string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(trimmed, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q: expected a number of days (e.g., 90d) or a duration (e.g., 36h)", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(trimmed)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q: expected a number of days (e.g., 90d) or a duration (e.g., 36h)", s)
+	}
+	return d, nil
+}
+
+// CompileKeyPattern compiles a pattern for matching object keys. A glob must
+// match the whole key: "*" matches any run of characters, "/" included, and
+// "?" matches one character (e.g., "*backup*2024*"). With regex, the pattern
+// is a regular expression that may match anywhere in the key.
+func CompileKeyPattern(pattern string, regex bool) (*regexp.Regexp, error) {
+	if regex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		return re, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String()), nil
+}
+
 // PublicAccessPrevention values for CreateBucketOptions.
 const (
 	PublicAccessPreventionEnforced  = "enforced"
@@ -212,4 +311,12 @@ type UploadObjectOptions struct {
 	ObjectKey   string
 	ContentType string            // optional — auto-detected from key extension if empty
 	Metadata    map[string]string // optional user-defined metadata
+	// Optional content headers; empty ones are not set
+	CacheControl       string
+	ContentDisposition string
+	ContentEncoding    string
+	ContentLanguage    string
+	// ChecksumAlgorithm, when set, has the provider verify the data against a
+	// checksum of it computed during the upload (see ChecksumAlgorithms)
+	ChecksumAlgorithm string
 }
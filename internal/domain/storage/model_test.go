@@ -1,6 +1,9 @@
 package storage
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestCreateBucketOptions_DefaultsAreNil(t *testing.T) {
 	opts := CreateBucketOptions{}
@@ -58,3 +61,103 @@ func TestFormatBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"512", 512, false},
+		{"512B", 512, false},
+		{"64KB", 64 << 10, false},
+		{"1.5 GB", 3 << 29, false},
+		{"10MiB", 10 << 20, false},
+		{"1gb", 1 << 30, false},
+		{"2T", 2 << 40, false},
+		{"", 0, true},
+		{"GB", 0, true},
+		{"1 PB", 0, true},
+		{"1KI", 0, true},
+		{"1.2.3MB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBytes(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBytes(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBytes(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"90d", 90 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"36h", 36 * time.Hour, false},
+		{" 1h30m ", 90 * time.Minute, false},
+		{"", 0, true},
+		{"d", 0, true},
+		{"1.5d", 0, true},
+		{"-2d", 0, true},
+		{"-1h", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAge(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAge(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAge(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileKeyPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		regex   bool
+		key     string
+		want    bool
+	}{
+		{"*backup*2024*", false, "db/backup-2024-01-02.tar", true},
+		{"*backup*2024*", false, "db/backup-2023.tar", false},
+		{"logs/?.gz", false, "logs/1.gz", true},
+		{"logs/?.gz", false, "logs/10.gz", false},
+		{"report.csv", false, "old/report.csv", false},
+		{"*.csv", false, "report.csv.bak", false},
+		{"a+b*", false, "a+b/c", true},
+		{`\d{4}-\d{2}`, true, "exports/2024-05/data.json", true},
+		{`^exports/.*\.json$`, true, "exports/data.csv", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.key, func(t *testing.T) {
+			re, err := CompileKeyPattern(tt.pattern, tt.regex)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := re.MatchString(tt.key); got != tt.want {
+				t.Errorf("CompileKeyPattern(%q).MatchString(%q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileKeyPattern_InvalidRegex(t *testing.T) {
+	if _, err := CompileKeyPattern("backup(", true); err == nil {
+		t.Fatal("expected an error for an invalid regular expression")
+	}
+}
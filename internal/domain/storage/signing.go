@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// MaxSignedExpiry is the longest lifetime GCS V4 and S3 SigV4 signatures allow
+const MaxSignedExpiry = 7 * 24 * time.Hour
+
+// PostPolicyOptions configures a signed POST policy.
+type PostPolicyOptions struct {
+	BucketName string
+	// Prefix is what the key of every upload must start with. The policy's
+	// key is Prefix followed by "${filename}", which the provider replaces
+	// with the name of the uploaded file.
+	Prefix string
+	// MaxSize bounds the size of an upload in bytes; 0 means no bound
+	MaxSize int64
+	Expires time.Duration
+}
+
+// PostPolicy is a signed form that lets a browser upload a file to a bucket
+// without credentials: a multipart/form-data POST to URL with each of Fields,
+// followed by the file in a field named "file".
+type PostPolicy struct {
+	URL     string            `json:"url" yaml:"url"`
+	Fields  map[string]string `json:"fields" yaml:"fields"`
+	Expires time.Time         `json:"expires" yaml:"expires"`
+}
+
+// PostPolicySigner is implemented by providers that can sign POST policies
+// for browser uploads.
+type PostPolicySigner interface {
+	SignPostPolicy(ctx context.Context, opts PostPolicyOptions) (PostPolicy, error)
+}
+
+// URL styles of a signed URL
+const (
+	// URLStylePath puts the bucket in the path (https://host/bucket/key)
+	URLStylePath = "path"
+	// URLStyleVirtualHosted puts the bucket in the host name
+	// (https://bucket.host/key)
+	URLStyleVirtualHosted = "virtual-hosted"
+)
+
+// SignedURLOptions configures a signed URL.
+type SignedURLOptions struct {
+	BucketName string
+	ObjectKey  string
+	// Method is the HTTP method the URL is valid for (GET, PUT, HEAD or DELETE)
+	Method  string
+	Expires time.Duration
+	// Headers are signed, so the request must send them with these values
+	// (e.g., Content-Type for an upload)
+	Headers map[string]string
+	// ResponseContentDisposition overrides the Content-Disposition of a
+	// download (e.g., attachment; filename="report.pdf")
+	ResponseContentDisposition string
+	// Style is URLStylePath or URLStyleVirtualHosted; empty is the provider's
+	// default (path for GCS, virtual-hosted for S3)
+	Style string
+}
+
+// SignedURL is a URL that grants its bearer one kind of request on an object
+// until it expires.
+type SignedURL struct {
+	URL    string `json:"url" yaml:"url"`
+	Method string `json:"method" yaml:"method"`
+	// Headers must be sent with the request as given
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Expires time.Time         `json:"expires" yaml:"expires"`
+}
+
+// URLSigner is implemented by providers that can sign URLs for objects.
+type URLSigner interface {
+	SignURL(ctx context.Context, opts SignedURLOptions) (SignedURL, error)
+}
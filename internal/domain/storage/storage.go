@@ -2,8 +2,9 @@ package storage
 
 import (
 	"context"
+	"github.com/jkleinne/synkronus/internal/domain"
 	"io"
-	"synkronus/internal/domain"
+	"time"
 )
 
 // Storage defines the interface for interacting with cloud storage buckets
@@ -22,6 +23,12 @@ type Storage interface {
 	// --- Object Operations ---
 	ListObjects(ctx context.Context, bucketName string, prefix string) (ObjectList, error)
 
+	// WalkObjects calls fn for every object under prefix, recursing into all
+	// "directories", as each page of results arrives. Nothing is accumulated,
+	// so memory stays bounded regardless of the bucket's size. An error from
+	// fn stops the walk and is returned.
+	WalkObjects(ctx context.Context, bucketName string, prefix string, fn func(Object) error) error
+
 	DescribeObject(ctx context.Context, bucketName string, objectKey string) (Object, error)
 
 	DownloadObject(ctx context.Context, bucketName string, objectKey string) (io.ReadCloser, error)
@@ -36,3 +43,60 @@ type Storage interface {
 
 	Close() error
 }
+
+// MetadataUpdater is implemented by providers that can change the user-defined
+// metadata of an existing object.
+type MetadataUpdater interface {
+	// UpdateObjectMetadata sets the given keys of the object's user-defined
+	// metadata, leaving its other keys as they are.
+	UpdateObjectMetadata(ctx context.Context, bucketName, objectKey string, metadata map[string]string) error
+}
+
+// BucketLabeler is implemented by providers that can change the labels (tags
+// on S3) of an existing bucket.
+type BucketLabeler interface {
+	// UpdateBucketLabels sets the given labels of the bucket, leaving its
+	// other labels as they are.
+	UpdateBucketLabels(ctx context.Context, bucketName string, labels map[string]string) error
+}
+
+// OrganizationBucketLister is implemented by providers that can find the
+// buckets of a whole organization or folder, rather than of the configured
+// projects only.
+type OrganizationBucketLister interface {
+	// ListOrganizationBuckets lists the buckets under scope, an organization
+	// ("organizations/ID") or a folder ("folders/ID"), each annotated with
+	// its project.
+	ListOrganizationBuckets(ctx context.Context, scope string) ([]Bucket, error)
+}
+
+// ObjectAttributes are the content headers and user-defined metadata of an object.
+type ObjectAttributes struct {
+	ContentType        string
+	ContentEncoding    string
+	ContentLanguage    string
+	CacheControl       string
+	ContentDisposition string
+	Metadata           map[string]string
+}
+
+// AttributesSetter is implemented by providers that can replace the content
+// headers and user-defined metadata of an existing object.
+type AttributesSetter interface {
+	// SetObjectAttributes makes attrs the object's content headers and
+	// user-defined metadata. Empty headers are removed, as are metadata keys
+	// missing from attrs.Metadata.
+	SetObjectAttributes(ctx context.Context, bucketName, objectKey string, attrs ObjectAttributes) error
+}
+
+// AccessStats counts the requests made to a bucket over a recent window.
+type AccessStats struct {
+	Window   time.Duration `json:"window" yaml:"window"`
+	Requests int64         `json:"requests" yaml:"requests"`
+}
+
+// AccessReporter is implemented by providers that report how often a bucket
+// is accessed, from their monitoring metrics.
+type AccessReporter interface {
+	BucketAccess(ctx context.Context, bucketName string, window time.Duration) (AccessStats, error)
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// ObjectVersion is one version of an object in a versioned bucket: an S3
+// version or delete marker, or a Cloud Storage generation.
+type ObjectVersion struct {
+	Key string `json:"key" yaml:"key"`
+	// VersionID is the S3 version ID, or the Cloud Storage generation
+	VersionID    string    `json:"version_id" yaml:"version_id"`
+	Size         int64     `json:"size" yaml:"size"`
+	StorageClass string    `json:"storage_class,omitempty" yaml:"storage_class,omitempty"`
+	LastModified time.Time `json:"last_modified" yaml:"last_modified"`
+	// IsLatest is true for the current version of the key, which may be a
+	// delete marker
+	IsLatest bool `json:"is_latest" yaml:"is_latest"`
+	// DeleteMarker is true for an S3 delete marker, which holds no data
+	DeleteMarker bool `json:"delete_marker,omitempty" yaml:"delete_marker,omitempty"`
+	// NoncurrentSince is when a Cloud Storage generation was replaced or
+	// deleted. S3 does not report it: an S3 version is noncurrent from the
+	// time the next newer version was written.
+	NoncurrentSince time.Time `json:"noncurrent_since,omitempty" yaml:"noncurrent_since,omitempty"`
+}
+
+// VersionManager is implemented by providers that list and delete the
+// individual versions of objects.
+type VersionManager interface {
+	// WalkObjectVersions calls fn for every version of the objects under
+	// prefix, delete markers included. The versions of a key are visited one
+	// after the other, newest first. An error from fn stops the walk and is
+	// returned.
+	WalkObjectVersions(ctx context.Context, bucketName, prefix string, fn func(ObjectVersion) error) error
+
+	// DeleteObjectVersion permanently deletes one version of an object, or
+	// removes a delete marker.
+	DeleteObjectVersion(ctx context.Context, bucketName, objectKey, versionID string) error
+}
@@ -24,10 +24,28 @@ const (
 	// Prefix flags are used to filter object listings
 	Prefix = "prefix"
 
+	// NamePattern flags give the pattern object keys are searched for
+	NamePattern = "name-pattern"
+
+	// Regex flags make a pattern a regular expression instead of a glob
+	Regex = "regex"
+
+	// Recursive flags list every object under the prefix instead of one directory level
+	Recursive      = "recursive"
+	RecursiveShort = "r"
+
 	// Force flags are used to bypass interactive confirmation prompts for destructive operations
 	Force      = "force"
 	ForceShort = "f"
 
+	// Yes flags answer yes to every confirmation prompt, like --force on the commands that prompt
+	Yes      = "yes"
+	YesShort = "y"
+
+	// Interactive flags ask for a command's settings one at a time instead of taking them as flags
+	Interactive      = "interactive"
+	InteractiveShort = "i"
+
 	// StorageClass flags specify the storage class for bucket creation
 	StorageClass      = "storage-class"
 	StorageClassShort = "s"
@@ -51,6 +69,21 @@ const (
 	Debug      = "debug"
 	DebugShort = "d"
 
+	// Profile flags select a named configuration profile for a single invocation
+	Profile = "profile"
+
+	// ImpersonateServiceAccount flags make GCP clients impersonate a service account for a single invocation
+	ImpersonateServiceAccount = "impersonate-service-account"
+
+	// RedactSecrets flags replace sensitive values with a placeholder in config exports
+	RedactSecrets = "redact-secrets"
+
+	// Merge flags overlay imported configuration onto the existing one instead of replacing it
+	Merge = "merge"
+
+	// Keychain flags store a config value (or the config encryption key) in the OS keychain
+	Keychain = "keychain"
+
 	// Output flags are used to select the output format (table, json, yaml)
 	Output      = "output"
 	OutputShort = "o"
@@ -69,4 +102,148 @@ const (
 
 	// DestKey flags specify the destination key for copy operations
 	DestKey = "dest-key"
+
+	// NoCache flags bypass the usage metrics and response caches and fetch fresh results
+	NoCache = "no-cache"
+
+	// MaxRetries flags set how many times provider calls are retried after transient errors
+	MaxRetries = "max-retries"
+
+	// Timeout flags set a deadline for the whole command
+	Timeout = "timeout"
+
+	// Demo flags swap the configured providers for the in-memory mock provider
+	Demo = "demo"
+
+	// DryRun flags make mutating commands report the change they would make instead of making it
+	DryRun = "dry-run"
+
+	// LogFile flags send logs to a file instead of stderr
+	LogFile = "log-file"
+
+	// LogFormat flags select the log record encoding (text, json)
+	LogFormat = "log-format"
+
+	// DebugHTTP flags log provider API requests and responses
+	DebugHTTP = "debug-http"
+
+	// MetricsAddress flags serve Prometheus metrics on an address
+	MetricsAddress = "metrics-address"
+
+	// Limit flags cap how many entries are shown
+	Limit = "limit"
+
+	// FailFast flags make multi-provider commands fail when any provider fails,
+	// instead of showing the results of the others
+	FailFast = "fail-fast"
+
+	// ProviderTimeout flags bound each provider's share of a multi-provider call
+	ProviderTimeout = "provider-timeout"
+
+	// Watch flags refresh a listing periodically, showing what changed
+	Watch = "watch"
+
+	// Size flags set the size of each synthetic object in a benchmark (e.g., 1MB, 1GB)
+	Size = "size"
+
+	// Count flags set how many objects a benchmark transfers
+	Count = "count"
+
+	// Parallel flags set how many transfers run at once
+	Parallel = "parallel"
+
+	// Address flags set the address a server listens on (e.g., :8080)
+	Address = "address"
+
+	// GRPCAddress flags set the address a gRPC server listens on (e.g., :9090)
+	GRPCAddress = "grpc-address"
+
+	// JobsFile flags name a YAML file of scheduled sync jobs
+	JobsFile = "jobs-file"
+
+	// ManifestFile flags name a CSV manifest of object operations to run as a batch
+	ManifestFile      = "file"
+	ManifestFileShort = "f"
+
+	// FailuresFile flags name where the manifest lines that did not succeed are written
+	FailuresFile = "failures-file"
+
+	// MetadataFile flags name a JSON Lines file of object headers and metadata to apply
+	MetadataFile      = "file"
+	MetadataFileShort = "f"
+
+	// MaxSize flags bound the size of an upload (e.g., 10MB)
+	MaxSize = "max-size"
+
+	// Expires flags set how long a signature stays valid (e.g., 15m, 24h)
+	Expires = "expires"
+
+	// Method flags set the HTTP method a signed URL is valid for
+	Method = "method"
+
+	// Header flags add name=value headers a signed request must send
+	Header = "header"
+
+	// ResponseContentDisposition flags override the Content-Disposition of a signed download
+	ResponseContentDisposition = "response-content-disposition"
+
+	// URLStyle flags select how a URL addresses the bucket
+	URLStyle = "style"
+
+	// Origin flags give the origin of a web page making cross-origin requests (e.g., https://app.example.com)
+	Origin = "origin"
+
+	// NoncurrentDays flags set how many days a replaced object version is kept
+	NoncurrentDays = "noncurrent-days"
+
+	// OlderThan flags set a minimum age (e.g., 90d, 36h)
+	OlderThan = "older-than"
+
+	// Keep flags set how many of the newest items are kept regardless
+	Keep = "keep"
+
+	// PlaceholdersOnly flags limit an empty object cleanup to "directory" placeholders
+	PlaceholdersOnly = "placeholders-only"
+
+	// Depth flags set how many levels of "directories" a report breaks down
+	Depth = "depth"
+
+	// Checksum flags select the checksum algorithm an upload is verified with (SHA256, CRC32C, CRC32, MD5)
+	Checksum = "checksum"
+
+	// File flags name a local file to compare an object or policy against
+	File = "file"
+
+	// ContentTypeMap flags set the content type of uploaded objects by key pattern (e.g., '*.wasm=application/wasm')
+	ContentTypeMap = "content-type-map"
+
+	// MetadataTemplate flags name a metadata template of the config applied to uploaded objects
+	MetadataTemplate = "metadata-template"
+
+	// Member flags name the principal a report is about (e.g., user:alice@example.com, an IAM user ARN)
+	Member = "member"
+
+	// Rules flags name a YAML file of conventions resources are checked against
+	Rules = "rules"
+
+	// Apply flags make a command carry out the changes it plans instead of only reporting them
+	Apply = "apply"
+
+	// Summary flags follow a listing with its totals: count, bytes and a breakdown by storage class
+	Summary = "summary"
+
+	// Organization flags select a GCP organization (by numeric ID) to search across all of its projects
+	Organization = "organization"
+
+	// Folder flags select a GCP folder (by numeric ID) to search across all of its projects
+	Folder = "folder"
+
+	// URL flags give the address a command downloads from
+	URL = "url"
+
+	// PricingFile flags name a local pricing dataset to install instead of downloading one
+	PricingFile = "file"
+
+	// Since flags set how far back a search of past events goes (e.g., 30d, 12h)
+	Since = "since"
 )
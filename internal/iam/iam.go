@@ -0,0 +1,207 @@
+// Package iam compares the live access policies of buckets with reference
+// policies kept under version control, to catch changes made outside it.
+package iam
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Source is the subset of the storage service a policy diff needs.
+type Source interface {
+	DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error)
+}
+
+// policyFile is a reference policy in the format 'gcloud storage buckets
+// get-iam-policy --format=json' writes, or an S3 bucket policy document.
+type policyFile struct {
+	Bindings []struct {
+		Role      string                `json:"role"`
+		Members   []string              `json:"members"`
+		Condition *storage.IAMCondition `json:"condition"`
+	} `json:"bindings"`
+	Statement json.RawMessage `json:"Statement"`
+}
+
+// ReadPolicy reads a reference policy: the JSON of a Cloud Storage IAM policy
+// (as 'gcloud storage buckets get-iam-policy --format=json' writes it) or of
+// an S3 bucket policy.
+func ReadPolicy(r io.Reader) (storage.IAMPolicy, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return storage.IAMPolicy{}, err
+	}
+	var file policyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return storage.IAMPolicy{}, fmt.Errorf("failed to parse policy: %w", err)
+	}
+
+	var policy storage.IAMPolicy
+	for _, b := range file.Bindings {
+		if b.Role == "" {
+			return storage.IAMPolicy{}, errors.New("policy binding without a role")
+		}
+		policy.Bindings = append(policy.Bindings, storage.IAMBinding{Role: b.Role, Principals: b.Members, Condition: b.Condition})
+	}
+	if len(file.Statement) > 0 {
+		if policy.Statements, err = storage.ParseBucketPolicy(string(data)); err != nil {
+			return storage.IAMPolicy{}, err
+		}
+	}
+	return policy, nil
+}
+
+// Drift is how the live policy of a bucket differs from its reference policy.
+// Added holds the principals granted a role only by the live policy, and
+// Removed those granted it only by the reference; statements of S3 bucket
+// policies are compared whole.
+type Drift struct {
+	Provider          string                    `json:"provider" yaml:"provider"`
+	Bucket            string                    `json:"bucket" yaml:"bucket"`
+	Added             []storage.IAMBinding      `json:"added" yaml:"added"`
+	Removed           []storage.IAMBinding      `json:"removed" yaml:"removed"`
+	AddedStatements   []storage.PolicyStatement `json:"added_statements,omitempty" yaml:"added_statements,omitempty"`
+	RemovedStatements []storage.PolicyStatement `json:"removed_statements,omitempty" yaml:"removed_statements,omitempty"`
+}
+
+// Drifted reports whether the live policy differs from the reference.
+func (d Drift) Drifted() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.AddedStatements) > 0 || len(d.RemovedStatements) > 0
+}
+
+// Diff describes a bucket and compares its policy with expected.
+func Diff(ctx context.Context, src Source, provider, bucketName string, expected storage.IAMPolicy) (Drift, error) {
+	bucket, err := src.DescribeBucket(ctx, bucketName, provider)
+	if err != nil {
+		return Drift{}, err
+	}
+	var live storage.IAMPolicy
+	if bucket.IAMPolicy != nil {
+		live = *bucket.IAMPolicy
+	}
+
+	drift := Compare(expected, live)
+	drift.Provider, drift.Bucket = strings.ToLower(provider), bucket.Name
+	return drift, nil
+}
+
+// Compare returns how live differs from expected. Principals are compared
+// per role and condition, so the order of bindings and members is irrelevant.
+func Compare(expected, live storage.IAMPolicy) Drift {
+	want, got := grants(expected.Bindings), grants(live.Bindings)
+	drift := Drift{
+		Added:   bindings(got, want),
+		Removed: bindings(want, got),
+	}
+
+	wantStatements, gotStatements := statements(expected.Statements), statements(live.Statements)
+	for _, key := range slices.Sorted(maps.Keys(gotStatements)) {
+		if _, ok := wantStatements[key]; !ok {
+			drift.AddedStatements = append(drift.AddedStatements, gotStatements[key])
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(wantStatements)) {
+		if _, ok := gotStatements[key]; !ok {
+			drift.RemovedStatements = append(drift.RemovedStatements, wantStatements[key])
+		}
+	}
+	return drift
+}
+
+// grant is a principal granted a role, under a condition or not.
+type grant struct {
+	role      string
+	condition storage.IAMCondition
+	member    string
+}
+
+func grants(bindings []storage.IAMBinding) map[grant]*storage.IAMCondition {
+	result := make(map[grant]*storage.IAMCondition)
+	for _, b := range bindings {
+		var condition storage.IAMCondition
+		if b.Condition != nil {
+			condition = *b.Condition
+		}
+		for _, m := range b.Principals {
+			result[grant{b.Role, condition, m}] = b.Condition
+		}
+	}
+	return result
+}
+
+// bindings returns the grants of a missing from b, grouped into bindings
+// ordered by role.
+func bindings(a, b map[grant]*storage.IAMCondition) []storage.IAMBinding {
+	type binding struct {
+		role      string
+		condition storage.IAMCondition
+	}
+	members := make(map[binding][]string)
+	conditions := make(map[binding]*storage.IAMCondition)
+	for g, condition := range a {
+		if _, ok := b[g]; ok {
+			continue
+		}
+		key := binding{g.role, g.condition}
+		members[key] = append(members[key], g.member)
+		conditions[key] = condition
+	}
+
+	result := make([]storage.IAMBinding, 0, len(members))
+	for key, m := range members {
+		slices.Sort(m)
+		result = append(result, storage.IAMBinding{Role: key.role, Principals: m, Condition: conditions[key]})
+	}
+	slices.SortFunc(result, func(x, y storage.IAMBinding) int {
+		return cmp.Or(cmp.Compare(x.Role, y.Role), cmp.Compare(conditionTitle(x), conditionTitle(y)))
+	})
+	return result
+}
+
+func conditionTitle(b storage.IAMBinding) string {
+	if b.Condition == nil {
+		return ""
+	}
+	return b.Condition.Title + b.Condition.Expression
+}
+
+// statements indexes statements by their canonical JSON, with principals,
+// actions, resources and condition values sorted.
+func statements(list []storage.PolicyStatement) map[string]storage.PolicyStatement {
+	result := make(map[string]storage.PolicyStatement, len(list))
+	for _, s := range list {
+		canonical := storage.PolicyStatement{
+			Effect:     s.Effect,
+			Principals: sorted(s.Principals),
+			Actions:    sorted(s.Actions),
+			Resources:  sorted(s.Resources),
+		}
+		for operator, keys := range s.Conditions {
+			if canonical.Conditions == nil {
+				canonical.Conditions = make(map[string]map[string][]string, len(s.Conditions))
+			}
+			canonical.Conditions[operator] = make(map[string][]string, len(keys))
+			for key, values := range keys {
+				canonical.Conditions[operator][key] = sorted(values)
+			}
+		}
+		// Maps are encoded with sorted keys, so equal statements encode equally
+		key, _ := json.Marshal(canonical)
+		result[string(key)] = s
+	}
+	return result
+}
+
+func sorted(s []string) []string {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s
+}
@@ -0,0 +1,83 @@
+package iam
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestReadPolicy(t *testing.T) {
+	policy, err := ReadPolicy(strings.NewReader(`{
+		"version": 3,
+		"etag": "CAE=",
+		"bindings": [
+			{"role": "roles/storage.objectViewer", "members": ["group:data@example.com"]},
+			{"role": "roles/storage.objectAdmin", "members": ["user:ann@example.com"],
+			 "condition": {"title": "temporary", "expression": "request.time < timestamp('2027-01-01T00:00:00Z')"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policy.Bindings) != 2 || policy.Bindings[1].Condition == nil || policy.Bindings[1].Condition.Title != "temporary" {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	policy, err = ReadPolicy(strings.NewReader(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::b/*"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policy.Statements) != 1 || policy.Statements[0].Actions[0] != "s3:GetObject" {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	for _, invalid := range []string{`{`, `{"bindings": [{"members": ["user:a@example.com"]}]}`} {
+		if _, err := ReadPolicy(strings.NewReader(invalid)); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	temporary := &storage.IAMCondition{Title: "temporary", Expression: "request.time < timestamp('2027-01-01T00:00:00Z')"}
+	expected := storage.IAMPolicy{Bindings: []storage.IAMBinding{
+		{Role: "roles/storage.objectViewer", Principals: []string{"group:data@example.com", "user:bob@example.com"}},
+		{Role: "roles/storage.objectAdmin", Principals: []string{"user:ann@example.com"}, Condition: temporary},
+	}}
+	live := storage.IAMPolicy{Bindings: []storage.IAMBinding{
+		{Role: "roles/storage.objectViewer", Principals: []string{"user:bob@example.com", "group:data@example.com", "allUsers"}},
+		{Role: "roles/storage.objectAdmin", Principals: []string{"user:ann@example.com"}},
+	}}
+
+	drift := Compare(expected, live)
+	if !drift.Drifted() {
+		t.Fatal("expected drift")
+	}
+	if len(drift.Added) != 2 || drift.Added[0].Role != "roles/storage.objectAdmin" || drift.Added[0].Condition != nil ||
+		drift.Added[1].Principals[0] != "allUsers" {
+		t.Errorf("unexpected added bindings: %+v", drift.Added)
+	}
+	if len(drift.Removed) != 1 || drift.Removed[0].Condition != temporary {
+		t.Errorf("expected the conditional binding to be removed, got %+v", drift.Removed)
+	}
+
+	if Compare(live, live).Drifted() {
+		t.Error("expected no drift between a policy and itself")
+	}
+}
+
+func TestCompare_Statements(t *testing.T) {
+	expected := storage.IAMPolicy{Statements: []storage.PolicyStatement{
+		{Effect: "Allow", Principals: []string{"arn:aws:iam::1:root", "arn:aws:iam::2:root"}, Actions: []string{"s3:GetObject"}, Resources: []string{"arn:aws:s3:::b/*"}},
+	}}
+	live := storage.IAMPolicy{Statements: []storage.PolicyStatement{
+		{Effect: "Allow", Principals: []string{"arn:aws:iam::2:root", "arn:aws:iam::1:root"}, Actions: []string{"s3:GetObject"}, Resources: []string{"arn:aws:s3:::b/*"}},
+		{Effect: "Allow", Principals: []string{"*"}, Actions: []string{"s3:GetObject"}, Resources: []string{"arn:aws:s3:::b/*"}},
+	}}
+
+	drift := Compare(expected, live)
+	if len(drift.AddedStatements) != 1 || drift.AddedStatements[0].Principals[0] != "*" || len(drift.RemovedStatements) != 0 {
+		t.Errorf("expected only the public statement to be added, got %+v", drift)
+	}
+}
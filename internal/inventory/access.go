@@ -0,0 +1,275 @@
+package inventory
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/acl"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Access levels a principal can have on a bucket, from least to most privileged
+const (
+	AccessRead  = "read"
+	AccessWrite = "write"
+	AccessAdmin = "admin"
+)
+
+var accessLevels = []string{AccessRead, AccessWrite, AccessAdmin}
+
+// gcsRoles are the levels granted by the predefined IAM roles for Cloud
+// Storage. Custom roles are not resolved.
+var gcsRoles = map[string][]string{
+	"roles/storage.admin":              {AccessRead, AccessWrite, AccessAdmin},
+	"roles/storage.legacyBucketOwner":  {AccessRead, AccessWrite, AccessAdmin},
+	"roles/owner":                      {AccessRead, AccessWrite, AccessAdmin},
+	"roles/storage.objectAdmin":        {AccessRead, AccessWrite},
+	"roles/storage.objectUser":         {AccessRead, AccessWrite},
+	"roles/storage.legacyObjectOwner":  {AccessRead, AccessWrite},
+	"roles/editor":                     {AccessRead, AccessWrite},
+	"roles/storage.legacyBucketWriter": {AccessWrite},
+	"roles/storage.objectCreator":      {AccessWrite},
+	"roles/storage.objectViewer":       {AccessRead},
+	"roles/storage.legacyBucketReader": {AccessRead},
+	"roles/storage.legacyObjectReader": {AccessRead},
+	"roles/viewer":                     {AccessRead},
+}
+
+// aclLevels are the levels granted by the roles of Cloud Storage ACLs and the
+// permissions of S3 ACLs. READ_ACP only lets the grantee read the ACL itself,
+// so it grants none of the levels; WRITE_ACP lets it grant itself anything.
+var aclLevels = map[string][]string{
+	"OWNER":        {AccessRead, AccessWrite, AccessAdmin},
+	"WRITER":       {AccessWrite},
+	"READER":       {AccessRead},
+	"FULL_CONTROL": {AccessRead, AccessWrite, AccessAdmin},
+	"WRITE_ACP":    {AccessAdmin},
+	"WRITE":        {AccessWrite},
+	"READ":         {AccessRead},
+}
+
+// s3Actions are the actions that give each level; a statement grants the
+// level when its actions, wildcards included, cover one of them
+var s3Actions = map[string][]string{
+	AccessRead:  {"s3:getobject", "s3:listbucket"},
+	AccessWrite: {"s3:putobject", "s3:deleteobject"},
+	AccessAdmin: {"s3:putbucketpolicy", "s3:putbucketacl", "s3:deletebucket"},
+}
+
+// S3 ACL grantee groups
+const (
+	s3AllUsers           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	s3AuthenticatedUsers = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// BucketAccess is the access a principal has to a bucket, and the grants
+// giving it.
+type BucketAccess struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	// Level is the most privileged level granted
+	Level string `json:"level" yaml:"level"`
+	// Levels are every level granted, e.g. write without read for objectCreator
+	Levels []string `json:"levels" yaml:"levels"`
+	// Grants describe the bindings, ACL entries and statements that apply,
+	// e.g. "IAM roles/storage.objectViewer to allUsers"
+	Grants []string `json:"grants" yaml:"grants"`
+}
+
+// AccessReport is the buckets a principal can access.
+type AccessReport struct {
+	Member  string         `json:"member" yaml:"member"`
+	Access  []BucketAccess `json:"access" yaml:"access"`
+	Buckets int            `json:"buckets" yaml:"buckets"`
+}
+
+// PrincipalAccess reports the access member has to each of buckets through
+// their IAM policies, bucket policies and ACLs, ordered by provider and
+// bucket. On Cloud Storage, member is an IAM principal (e.g.,
+// "user:alice@example.com") and is granted what allUsers,
+// allAuthenticatedUsers and its domain are. On S3, it is an IAM ARN (e.g.,
+// "arn:aws:iam::123456789012:user/alice") and is granted what its account
+// and "*" are, less the actions of unconditional Deny statements. S3 ACLs
+// name their grantees by canonical user ID (or display name), which cannot be
+// resolved to an ARN, so ACL grants to users only count when member is given
+// in that form; grants to the AllUsers and AuthenticatedUsers groups always do.
+//
+// Only grants made on the buckets are seen: group memberships, project and
+// account level policies, and custom roles are not resolved, and conditional
+// grants are counted as if their condition held.
+func PrincipalAccess(buckets []storage.Bucket, member string) AccessReport {
+	report := AccessReport{Member: member, Access: []BucketAccess{}, Buckets: len(buckets)}
+	for _, b := range buckets {
+		var g grants
+		if b.Provider == domain.AWS {
+			g = s3Grants(b, member)
+		} else {
+			g = gcsGrants(b, member)
+		}
+		if access, ok := g.access(); ok {
+			access.Provider, access.Bucket = strings.ToLower(string(b.Provider)), b.Name
+			report.Access = append(report.Access, access)
+		}
+	}
+	slices.SortFunc(report.Access, func(a, b BucketAccess) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Bucket, b.Bucket))
+	})
+	return report
+}
+
+// grants collects the levels granted on one bucket.
+type grants struct {
+	allowed map[string]bool
+	via     []string
+}
+
+func (g *grants) allow(levels []string, via string) {
+	if g.allowed == nil {
+		g.allowed = make(map[string]bool)
+	}
+	for _, level := range levels {
+		g.allowed[level] = true
+	}
+	if via != "" {
+		g.via = append(g.via, via)
+	}
+}
+
+func (g grants) access() (BucketAccess, bool) {
+	var access BucketAccess
+	for _, level := range accessLevels {
+		if g.allowed[level] {
+			access.Level = level
+			access.Levels = append(access.Levels, level)
+		}
+	}
+	access.Grants = g.via
+	return access, access.Level != ""
+}
+
+func gcsGrants(b storage.Bucket, member string) grants {
+	var g grants
+	if b.IAMPolicy != nil {
+		for _, binding := range b.IAMPolicy.Bindings {
+			levels, ok := gcsRoles[binding.Role]
+			if !ok {
+				continue
+			}
+			for _, principal := range binding.Principals {
+				if !gcsMatches(principal, member) {
+					continue
+				}
+				via := "IAM " + binding.Role
+				if principal != member {
+					via += " to " + principal
+				}
+				if binding.Condition != nil {
+					via += fmt.Sprintf(" if %q", binding.Condition.Title)
+				}
+				g.allow(levels, via)
+			}
+		}
+	}
+	for _, rule := range b.ACLs {
+		grant := acl.MapRule(rule)
+		if grant.Member == "" || !gcsMatches(grant.Member, member) {
+			continue
+		}
+		g.allow(aclLevels[strings.ToUpper(rule.Role)], fmt.Sprintf("ACL %s to %s", rule.Role, rule.Entity))
+	}
+	return g
+}
+
+// gcsMatches reports whether an IAM principal includes member.
+func gcsMatches(principal, member string) bool {
+	switch {
+	case principal == member, principal == "allUsers":
+		return true
+	case principal == "allAuthenticatedUsers":
+		return member != "allUsers"
+	case strings.HasPrefix(principal, "domain:"):
+		_, email, _ := strings.Cut(member, ":")
+		_, domainName, ok := strings.Cut(email, "@")
+		return ok && strings.EqualFold(domainName, strings.TrimPrefix(principal, "domain:"))
+	}
+	return false
+}
+
+func s3Grants(b storage.Bucket, member string) grants {
+	var g grants
+	// Statements allow and deny actions, not levels: denying s3:PutObject
+	// leaves the write access s3:DeleteObject gives
+	allowed, denied := make(map[string]bool), make(map[string]bool)
+	if b.IAMPolicy != nil {
+		for _, stmt := range b.IAMPolicy.Statements {
+			if !slices.ContainsFunc(stmt.Principals, func(p string) bool { return s3Matches(p, member) }) {
+				continue
+			}
+			actions := make(map[string]bool)
+			for _, level := range accessLevels {
+				for _, action := range s3Actions[level] {
+					if actionsCover(stmt.Actions, action) {
+						actions[action] = true
+					}
+				}
+			}
+			if len(actions) == 0 {
+				continue
+			}
+			via := fmt.Sprintf("policy %s %s", stmt.Effect, strings.Join(stmt.Actions, ", "))
+			switch {
+			case strings.EqualFold(stmt.Effect, "Deny") && len(stmt.Conditions) == 0:
+				maps.Copy(denied, actions)
+				g.via = append(g.via, via)
+			case strings.EqualFold(stmt.Effect, "Allow"):
+				if len(stmt.Conditions) > 0 {
+					via += " (conditional)"
+				}
+				maps.Copy(allowed, actions)
+				g.via = append(g.via, via)
+			}
+		}
+	}
+	for _, level := range accessLevels {
+		if slices.ContainsFunc(s3Actions[level], func(a string) bool { return allowed[a] && !denied[a] }) {
+			g.allow([]string{level}, "")
+		}
+	}
+
+	// A user grantee is a canonical ID, so it only matches a member given as one
+	for _, rule := range b.ACLs {
+		entity := rule.Entity
+		if entity == s3AllUsers || entity == s3AuthenticatedUsers || entity == member {
+			g.allow(aclLevels[rule.Role], fmt.Sprintf("ACL %s to %s", rule.Role, entity))
+		}
+	}
+	return g
+}
+
+// s3Matches reports whether a bucket policy principal includes member: "*",
+// member itself, or member's account (as an ID or root ARN).
+func s3Matches(principal, member string) bool {
+	if principal == "*" || principal == member {
+		return true
+	}
+	// arn:aws:iam::ACCOUNT:user/NAME
+	parts := strings.SplitN(member, ":", 6)
+	if len(parts) < 6 || parts[4] == "" {
+		return false
+	}
+	account := parts[4]
+	return principal == account || principal == "arn:aws:iam::"+account+":root"
+}
+
+// actionsCover reports whether any of the policy actions, which may hold
+// wildcards, matches action.
+func actionsCover(actions []string, action string) bool {
+	return slices.ContainsFunc(actions, func(pattern string) bool {
+		ok, _ := path.Match(strings.ToLower(pattern), action)
+		return ok
+	})
+}
@@ -0,0 +1,66 @@
+package inventory
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestPrincipalAccess(t *testing.T) {
+	buckets := []storage.Bucket{
+		{Name: "public", Provider: domain.GCP, IAMPolicy: &storage.IAMPolicy{Bindings: []storage.IAMBinding{
+			{Role: "roles/storage.objectViewer", Principals: []string{"allUsers"}},
+		}}},
+		{Name: "team", Provider: domain.GCP,
+			IAMPolicy: &storage.IAMPolicy{Bindings: []storage.IAMBinding{
+				{Role: "roles/storage.objectCreator", Principals: []string{"domain:example.com"}},
+				{Role: "roles/storage.custom", Principals: []string{"user:alice@example.com"}},
+			}},
+			ACLs: []storage.ACLRule{{Entity: "user-alice@example.com", Role: "OWNER"}},
+		},
+		{Name: "private", Provider: domain.GCP, IAMPolicy: &storage.IAMPolicy{Bindings: []storage.IAMBinding{
+			{Role: "roles/storage.admin", Principals: []string{"user:bob@example.com"}},
+		}}},
+	}
+
+	report := PrincipalAccess(buckets, "user:alice@example.com")
+	if report.Buckets != 3 || len(report.Access) != 2 {
+		t.Fatalf("expected access to 2 of 3 buckets, got %+v", report)
+	}
+	if a := report.Access[0]; a.Bucket != "public" || a.Level != AccessRead || a.Grants[0] != "IAM roles/storage.objectViewer to allUsers" {
+		t.Errorf("unexpected access to the public bucket: %+v", a)
+	}
+	if a := report.Access[1]; a.Bucket != "team" || a.Level != AccessAdmin || len(a.Grants) != 2 {
+		t.Errorf("unexpected access to the team bucket: %+v", a)
+	}
+}
+
+func TestPrincipalAccess_S3(t *testing.T) {
+	const alice = "arn:aws:iam::111122223333:user/alice"
+	buckets := []storage.Bucket{
+		{Name: "shared", Provider: domain.AWS, IAMPolicy: &storage.IAMPolicy{Statements: []storage.PolicyStatement{
+			{Effect: "Allow", Principals: []string{"arn:aws:iam::111122223333:root"}, Actions: []string{"s3:*"}},
+			{Effect: "Deny", Principals: []string{alice}, Actions: []string{"s3:Put*", "s3:DeleteBucket"}},
+		}}},
+		{Name: "logs", Provider: domain.AWS, ACLs: []storage.ACLRule{{Entity: s3AllUsers, Role: "READ"}}},
+		// Reading the ACL is not reading the bucket
+		{Name: "audit", Provider: domain.AWS, ACLs: []storage.ACLRule{{Entity: s3AuthenticatedUsers, Role: "READ_ACP"}}},
+		{Name: "other", Provider: domain.AWS, IAMPolicy: &storage.IAMPolicy{Statements: []storage.PolicyStatement{
+			{Effect: "Allow", Principals: []string{"arn:aws:iam::444455556666:root"}, Actions: []string{"s3:GetObject"}},
+		}}},
+	}
+
+	report := PrincipalAccess(buckets, alice)
+	if len(report.Access) != 2 {
+		t.Fatalf("expected access to 2 buckets, got %+v", report.Access)
+	}
+	if a := report.Access[0]; a.Bucket != "logs" || a.Level != AccessRead {
+		t.Errorf("unexpected access to the logs bucket: %+v", a)
+	}
+	// The Deny takes away s3:PutObject and s3:PutBucketPolicy, but not s3:DeleteObject
+	if a := report.Access[1]; a.Bucket != "shared" || a.Level != AccessWrite || !slices.Equal(a.Levels, []string{AccessRead, AccessWrite}) {
+		t.Errorf("unexpected access to the shared bucket: %+v", a)
+	}
+}
@@ -0,0 +1,71 @@
+// Package inventory describes every bucket of a set of providers and
+// summarizes their settings across providers, for reviews that span the
+// whole estate (which KMS keys are in use, which buckets lack them...).
+package inventory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// describeParallel is how many buckets are described at once
+const describeParallel = 8
+
+// Source is the subset of the storage service an inventory needs.
+type Source interface {
+	ListAllBuckets(ctx context.Context, providerNames []string) ([]storage.Bucket, error)
+	DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error)
+}
+
+// Describe lists the buckets of providers and describes each in full, ordered
+// by provider and name. Unlike a snapshot, an inventory is useful even when
+// incomplete: buckets of the providers that answered are described, those
+// that fail to describe are left out, and the failures are returned joined
+// with the described buckets. Only a listing that finds nothing at all fails
+// outright.
+func Describe(ctx context.Context, src Source, providers []string) ([]storage.Bucket, error) {
+	listed, listErr := src.ListAllBuckets(ctx, providers)
+	if listErr != nil && len(listed) == 0 {
+		return nil, listErr
+	}
+
+	described := make([]storage.Bucket, len(listed))
+	errs := make([]error, len(listed))
+	sem := make(chan struct{}, describeParallel)
+	var wg sync.WaitGroup
+	for i, b := range listed {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			bucket, err := src.DescribeBucket(ctx, b.Name, strings.ToLower(string(b.Provider)))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			// Listings record which project or account a bucket came from, describes may not
+			bucket.Project = cmp.Or(bucket.Project, b.Project)
+			bucket.Account = cmp.Or(bucket.Account, b.Account)
+			bucket.Provider = b.Provider
+			described[i] = bucket
+		}()
+	}
+	wg.Wait()
+
+	buckets := make([]storage.Bucket, 0, len(described))
+	for i, b := range described {
+		if errs[i] == nil {
+			buckets = append(buckets, b)
+		}
+	}
+	slices.SortFunc(buckets, func(a, b storage.Bucket) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Name, b.Name))
+	})
+	return buckets, errors.Join(append([]error{listErr}, errs...)...)
+}
@@ -0,0 +1,106 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+type fakeSource struct {
+	buckets   []storage.Bucket
+	described map[string]storage.Bucket
+	listErr   error
+}
+
+func (f *fakeSource) ListAllBuckets(_ context.Context, _ []string) ([]storage.Bucket, error) {
+	return f.buckets, f.listErr
+}
+
+func (f *fakeSource) DescribeBucket(_ context.Context, name, _ string) (storage.Bucket, error) {
+	b, ok := f.described[name]
+	if !ok {
+		return storage.Bucket{}, errors.New("access denied")
+	}
+	return b, nil
+}
+
+func TestDescribe(t *testing.T) {
+	src := &fakeSource{
+		buckets: []storage.Bucket{
+			{Name: "b", Provider: domain.GCP, Project: "proj"},
+			{Name: "a", Provider: domain.GCP},
+			{Name: "denied", Provider: domain.AWS},
+		},
+		described: map[string]storage.Bucket{
+			"a": {Name: "a", Provider: domain.GCP, Labels: map[string]string{"team": "data"}},
+			"b": {Name: "b", Provider: domain.GCP},
+		},
+	}
+
+	buckets, err := Describe(context.Background(), src, []string{"gcp", "aws"})
+	if err == nil {
+		t.Error("expected the failed describe reported")
+	}
+	if len(buckets) != 2 || buckets[0].Name != "a" || buckets[1].Name != "b" {
+		t.Fatalf("expected the described buckets in order, got %+v", buckets)
+	}
+	if buckets[0].Labels["team"] != "data" || buckets[1].Project != "proj" {
+		t.Errorf("expected the described settings and the listed project, got %+v", buckets)
+	}
+
+	src.buckets, src.listErr = nil, errors.New("no credentials")
+	if _, err := Describe(context.Background(), src, []string{"gcp"}); err == nil {
+		t.Error("expected an error when nothing is listed")
+	}
+}
+
+func TestKMSKeys(t *testing.T) {
+	key := "projects/p/locations/us/keyRings/r/cryptoKeys/k"
+	report := KMSKeys([]storage.Bucket{
+		{Name: "logs", Provider: domain.GCP, Encryption: &storage.Encryption{KmsKeyName: key}},
+		{Name: "data", Provider: domain.GCP, Encryption: &storage.Encryption{KmsKeyName: key}},
+		{Name: "web", Provider: domain.GCP},
+		{Name: "archive", Provider: domain.AWS, Encryption: &storage.Encryption{Algorithm: "AES256"}},
+		{Name: "default-kms", Provider: domain.AWS, Encryption: &storage.Encryption{Algorithm: "aws:kms", KmsKeyName: "alias/aws/s3"}},
+	})
+
+	if len(report.Keys) != 1 || report.Keys[0].Key != key || len(report.Keys[0].Buckets) != 2 || report.Keys[0].Buckets[0] != "data" {
+		t.Errorf("expected one key used by data and logs, got %+v", report.Keys)
+	}
+	if len(report.ProviderManaged) != 3 || report.ProviderManaged[0].Bucket != "archive" || report.ProviderManaged[0].Algorithm != "AES256" {
+		t.Errorf("expected three provider-managed buckets, aws first, got %+v", report.ProviderManaged)
+	}
+	if report.Buckets != 5 {
+		t.Errorf("buckets = %d, want 5", report.Buckets)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	now := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	report := Retention([]storage.Bucket{
+		{Name: "web", Provider: domain.GCP},
+		{Name: "backups", Provider: domain.GCP, RetentionPolicy: &storage.RetentionPolicy{RetentionPeriod: 30 * 24 * time.Hour, IsLocked: true}},
+		{Name: "ledger", Provider: domain.AWS, RetentionPolicy: &storage.RetentionPolicy{RetentionPeriod: 24 * time.Hour, Mode: "GOVERNANCE"}},
+	}, now)
+
+	if len(report.Retained) != 2 || report.Retained[0].Bucket != "ledger" || report.Retained[1].Bucket != "backups" {
+		t.Fatalf("expected ledger then backups, got %+v", report.Retained)
+	}
+	ledger, backups := report.Retained[0], report.Retained[1]
+	if ledger.Mechanism != MechanismObjectLock || ledger.Locked || ledger.Mode != "GOVERNANCE" {
+		t.Errorf("unexpected ledger retention: %+v", ledger)
+	}
+	if backups.Mechanism != MechanismRetentionPolicy || !backups.Locked {
+		t.Errorf("unexpected backups retention: %+v", backups)
+	}
+	if want := now.Add(30 * 24 * time.Hour); !backups.RetainUntil.Equal(want) {
+		t.Errorf("retain until = %v, want %v", backups.RetainUntil, want)
+	}
+	if len(report.Unretained) != 1 || report.Unretained[0].Bucket != "web" || report.Buckets != 3 {
+		t.Errorf("expected web without retention, got %+v", report)
+	}
+}
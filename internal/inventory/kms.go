@@ -0,0 +1,83 @@
+package inventory
+
+import (
+	"cmp"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"strings"
+)
+
+// awsManagedKey is the alias of the AWS managed key SSE-KMS uses when a bucket names none
+const awsManagedKey = "alias/aws/s3"
+
+// KMSKey is a customer-managed key (a Cloud KMS key name or an AWS KMS key ID
+// or ARN) and the buckets encrypted with it by default.
+type KMSKey struct {
+	Provider string   `json:"provider" yaml:"provider"`
+	Key      string   `json:"key" yaml:"key"`
+	Buckets  []string `json:"buckets" yaml:"buckets"`
+}
+
+// ProviderManagedBucket is a bucket encrypted with a key the provider
+// manages: Google-managed encryption, SSE-S3 (AES256), or SSE-KMS with the
+// AWS managed aws/s3 key.
+type ProviderManagedBucket struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	// Algorithm is the bucket's default encryption, if the provider reports one
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+}
+
+// KMSReport is the default encryption keys of a set of buckets.
+type KMSReport struct {
+	Keys            []KMSKey                `json:"keys" yaml:"keys"`
+	ProviderManaged []ProviderManagedBucket `json:"provider_managed" yaml:"provider_managed"`
+	Buckets         int                     `json:"buckets" yaml:"buckets"`
+}
+
+// KMSKeys groups buckets by their default customer-managed key, ordered by
+// provider and key, and lists the buckets without one.
+func KMSKeys(buckets []storage.Bucket) KMSReport {
+	report := KMSReport{Keys: []KMSKey{}, ProviderManaged: []ProviderManagedBucket{}, Buckets: len(buckets)}
+	byKey := make(map[[2]string]*KMSKey)
+	for _, b := range buckets {
+		provider := strings.ToLower(string(b.Provider))
+		keyName := CustomerManagedKey(b)
+		if keyName == "" {
+			managed := ProviderManagedBucket{Provider: provider, Bucket: b.Name}
+			if b.Encryption != nil {
+				managed.Algorithm = b.Encryption.Algorithm
+			}
+			report.ProviderManaged = append(report.ProviderManaged, managed)
+			continue
+		}
+		id := [2]string{provider, keyName}
+		key, ok := byKey[id]
+		if !ok {
+			key = &KMSKey{Provider: provider, Key: keyName}
+			byKey[id] = key
+		}
+		key.Buckets = append(key.Buckets, b.Name)
+	}
+
+	for _, key := range byKey {
+		slices.Sort(key.Buckets)
+		report.Keys = append(report.Keys, *key)
+	}
+	slices.SortFunc(report.Keys, func(a, b KMSKey) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Key, b.Key))
+	})
+	slices.SortFunc(report.ProviderManaged, func(a, b ProviderManagedBucket) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Bucket, b.Bucket))
+	})
+	return report
+}
+
+// CustomerManagedKey returns the customer-managed key a bucket is encrypted
+// with by default, or "" if it uses a key the provider manages.
+func CustomerManagedKey(b storage.Bucket) string {
+	if b.Encryption == nil || strings.HasSuffix(b.Encryption.KmsKeyName, awsManagedKey) {
+		return ""
+	}
+	return b.Encryption.KmsKeyName
+}
@@ -0,0 +1,87 @@
+package inventory
+
+import (
+	"cmp"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Retention mechanisms
+const (
+	MechanismRetentionPolicy = "retention-policy"
+	MechanismObjectLock      = "object-lock"
+)
+
+// RetainedBucket is a bucket whose objects are kept for a minimum period:
+// a Cloud Storage retention policy or the default retention of S3 Object Lock.
+type RetainedBucket struct {
+	Provider  string        `json:"provider" yaml:"provider"`
+	Bucket    string        `json:"bucket" yaml:"bucket"`
+	Mechanism string        `json:"mechanism" yaml:"mechanism"`
+	Period    time.Duration `json:"period" yaml:"period"`
+	// Locked is set when the period can no longer be shortened or removed: a
+	// locked retention policy, or Object Lock in COMPLIANCE mode
+	Locked bool `json:"locked" yaml:"locked"`
+	// Mode is the Object Lock retention mode (GOVERNANCE or COMPLIANCE)
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// EffectiveTime is when a retention policy took effect
+	EffectiveTime *time.Time `json:"effective_time,omitempty" yaml:"effective_time,omitempty"`
+	// RetainUntil is when an object written at the time of the report can
+	// first be deleted. Until the bucket is locked, the window is pending: it
+	// can still be shortened or lifted.
+	RetainUntil time.Time `json:"retain_until" yaml:"retain_until"`
+}
+
+// UnretainedBucket is a bucket without a retention policy or Object Lock
+// default retention.
+type UnretainedBucket struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Bucket   string `json:"bucket" yaml:"bucket"`
+}
+
+// RetentionReport is the write-once (WORM) coverage of a set of buckets.
+type RetentionReport struct {
+	Retained   []RetainedBucket   `json:"retained" yaml:"retained"`
+	Unretained []UnretainedBucket `json:"unretained" yaml:"unretained"`
+	Buckets    int                `json:"buckets" yaml:"buckets"`
+	At         time.Time          `json:"at" yaml:"at"`
+}
+
+// Retention lists the buckets that retain their objects, with the lock window
+// of an object written at now, and those that do not, ordered by provider
+// and bucket.
+func Retention(buckets []storage.Bucket, now time.Time) RetentionReport {
+	report := RetentionReport{Retained: []RetainedBucket{}, Unretained: []UnretainedBucket{}, Buckets: len(buckets), At: now}
+	for _, b := range buckets {
+		provider := strings.ToLower(string(b.Provider))
+		policy := b.RetentionPolicy
+		if policy == nil {
+			report.Unretained = append(report.Unretained, UnretainedBucket{Provider: provider, Bucket: b.Name})
+			continue
+		}
+		retained := RetainedBucket{
+			Provider:      provider,
+			Bucket:        b.Name,
+			Mechanism:     MechanismRetentionPolicy,
+			Period:        policy.RetentionPeriod,
+			Locked:        policy.IsLocked,
+			Mode:          policy.Mode,
+			EffectiveTime: policy.EffectiveTime,
+			RetainUntil:   now.Add(policy.RetentionPeriod),
+		}
+		if b.Provider == domain.AWS {
+			retained.Mechanism = MechanismObjectLock
+		}
+		report.Retained = append(report.Retained, retained)
+	}
+	slices.SortFunc(report.Retained, func(a, b RetainedBucket) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Bucket, b.Bucket))
+	})
+	slices.SortFunc(report.Unretained, func(a, b UnretainedBucket) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Bucket, b.Bucket))
+	})
+	return report
+}
@@ -0,0 +1,95 @@
+package lint
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Labeler is the subset of the storage service that applies label fixes.
+type Labeler interface {
+	UpdateBucketLabels(ctx context.Context, bucketName, providerName string, labels map[string]string) error
+}
+
+// LabelFix is the default labels a bucket lacks, and the required labels it
+// lacks that have no default and must be set by hand.
+type LabelFix struct {
+	Provider string            `json:"provider" yaml:"provider"`
+	Bucket   string            `json:"bucket" yaml:"bucket"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Missing  []string          `json:"missing,omitempty" yaml:"missing,omitempty"`
+	// Applied is set once Labels were added to the bucket
+	Applied bool   `json:"applied,omitempty" yaml:"applied,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// LabelPlan is the label fixes of a set of buckets.
+type LabelPlan struct {
+	Fixes   []LabelFix `json:"fixes" yaml:"fixes"`
+	Buckets int        `json:"buckets" yaml:"buckets"`
+}
+
+// PlanLabels returns, for each of buckets lacking a default or required
+// label, the fix bringing it in line with rules, ordered by provider and
+// bucket. Labels buckets already have are left as they are, even when their
+// value is not allowed.
+func PlanLabels(buckets []storage.Bucket, rules Rules) LabelPlan {
+	r := rules.Buckets
+	plan := LabelPlan{Fixes: []LabelFix{}, Buckets: len(buckets)}
+	for _, b := range buckets {
+		fix := LabelFix{Provider: strings.ToLower(string(b.Provider)), Bucket: b.Name}
+		for label, value := range r.DefaultLabels {
+			if _, ok := b.Labels[label]; !ok {
+				if fix.Labels == nil {
+					fix.Labels = make(map[string]string)
+				}
+				fix.Labels[label] = value
+			}
+		}
+		for _, label := range r.RequiredLabels {
+			_, has := b.Labels[label]
+			_, fixed := fix.Labels[label]
+			if !has && !fixed {
+				fix.Missing = append(fix.Missing, label)
+			}
+		}
+		if len(fix.Labels) > 0 || len(fix.Missing) > 0 {
+			plan.Fixes = append(plan.Fixes, fix)
+		}
+	}
+	slices.SortFunc(plan.Fixes, func(a, b LabelFix) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Bucket, b.Bucket))
+	})
+	return plan
+}
+
+// ApplyLabels adds the labels of each fix to its bucket. A failed bucket does
+// not stop the others; its error is recorded in the returned plan, and the
+// returned error counts the failures.
+func ApplyLabels(ctx context.Context, dst Labeler, plan LabelPlan) (LabelPlan, error) {
+	applied := LabelPlan{Fixes: slices.Clone(plan.Fixes), Buckets: plan.Buckets}
+	attempted, failed := 0, 0
+	for i, fix := range applied.Fixes {
+		if len(fix.Labels) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+		attempted++
+		if err := dst.UpdateBucketLabels(ctx, fix.Bucket, fix.Provider, maps.Clone(fix.Labels)); err != nil {
+			applied.Fixes[i].Error = err.Error()
+			failed++
+			continue
+		}
+		applied.Fixes[i].Applied = true
+	}
+	if failed > 0 {
+		return applied, fmt.Errorf("failed to label %d of %d buckets", failed, attempted)
+	}
+	return applied, nil
+}
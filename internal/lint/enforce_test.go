@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+type fakeLabeler struct {
+	updated map[string]map[string]string
+	fail    string
+}
+
+func (f *fakeLabeler) UpdateBucketLabels(_ context.Context, bucketName, _ string, labels map[string]string) error {
+	if bucketName == f.fail {
+		return errors.New("access denied")
+	}
+	f.updated[bucketName] = labels
+	return nil
+}
+
+func TestPlanLabels(t *testing.T) {
+	rules, err := ReadRules(strings.NewReader("buckets:\n  required_labels: [team, env]\n  default_labels: {env: dev, cost-center: shared}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buckets := []storage.Bucket{
+		{Name: "ok", Provider: domain.GCP, Labels: map[string]string{"team": "data", "env": "prod", "cost-center": "42"}},
+		{Name: "bare", Provider: domain.GCP},
+		{Name: "partial", Provider: domain.AWS, Labels: map[string]string{"team": "web", "env": "prod"}},
+	}
+
+	plan := PlanLabels(buckets, rules)
+	if len(plan.Fixes) != 2 {
+		t.Fatalf("expected 2 fixes, got %+v", plan.Fixes)
+	}
+	if f := plan.Fixes[0]; f.Provider != "aws" || f.Labels["cost-center"] != "shared" || len(f.Labels) != 1 || f.Missing != nil {
+		t.Errorf("unexpected fix of the partial bucket: %+v", f)
+	}
+	if f := plan.Fixes[1]; f.Bucket != "bare" || f.Labels["env"] != "dev" || len(f.Missing) != 1 || f.Missing[0] != "team" {
+		t.Errorf("unexpected fix of the bare bucket: %+v", f)
+	}
+}
+
+func TestApplyLabels(t *testing.T) {
+	plan := LabelPlan{Fixes: []LabelFix{
+		{Provider: "gcp", Bucket: "a", Labels: map[string]string{"env": "dev"}},
+		{Provider: "gcp", Bucket: "b", Labels: map[string]string{"env": "dev"}},
+		{Provider: "gcp", Bucket: "c", Missing: []string{"team"}},
+	}}
+	dst := &fakeLabeler{updated: map[string]map[string]string{}, fail: "b"}
+
+	applied, err := ApplyLabels(context.Background(), dst, plan)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Errorf("expected 1 of 2 buckets to fail, got %v", err)
+	}
+	if !applied.Fixes[0].Applied || applied.Fixes[1].Applied || applied.Fixes[1].Error == "" || applied.Fixes[2].Applied {
+		t.Errorf("unexpected result: %+v", applied.Fixes)
+	}
+	if dst.updated["a"]["env"] != "dev" {
+		t.Errorf("expected bucket a to be labeled, got %v", dst.updated)
+	}
+	if plan.Fixes[0].Applied {
+		t.Error("expected the plan to be left as it was")
+	}
+}
@@ -0,0 +1,155 @@
+// Package lint checks buckets against an organization's conventions (naming,
+// labels, locations, encryption) written down in a rules file, so CI can fail
+// when a resource breaks them.
+package lint
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncryptionCustomerManaged requires buckets to be encrypted with a
+// customer-managed key by default (CMEK, or SSE-KMS with a key other than aws/s3)
+const EncryptionCustomerManaged = "customer-managed"
+
+// Rule names of a Violation
+const (
+	RuleName          = "name"
+	RuleRequiredLabel = "required-label"
+	RuleLabelValue    = "label-value"
+	RuleLocation      = "location"
+	RuleEncryption    = "encryption"
+)
+
+// Rules are the conventions buckets are checked against. Empty rules are
+// not checked.
+type Rules struct {
+	Buckets BucketRules `yaml:"buckets"`
+}
+
+// BucketRules are the conventions for buckets.
+type BucketRules struct {
+	// NamePattern is a regular expression bucket names must match
+	NamePattern string `yaml:"name_pattern"`
+	// RequiredLabels are the labels (tags on S3) every bucket must have
+	RequiredLabels []string `yaml:"required_labels"`
+	// LabelValues restricts the values of labels, when buckets have them
+	LabelValues map[string][]string `yaml:"label_values"`
+	// DefaultLabels are the values 'storage enforce-labels' gives the labels
+	// buckets lack
+	DefaultLabels map[string]string `yaml:"default_labels"`
+	// Locations are the locations buckets may be in (e.g., EU, us-central1),
+	// compared without regard to case
+	Locations []string `yaml:"locations"`
+	// Encryption is the default encryption buckets must have: customer-managed
+	Encryption string `yaml:"encryption"`
+
+	name *regexp.Regexp
+}
+
+// ReadRules reads rules from YAML (or JSON) of the form:
+//
+//	buckets:
+//	  name_pattern: '^acme-[a-z0-9-]+$'
+//	  required_labels: [team, env]
+//	  label_values:
+//	    env: [prod, staging, dev]
+//	  default_labels:
+//	    env: dev
+//	  locations: [EU, europe-west1, eu-west-1]
+//	  encryption: customer-managed
+func ReadRules(r io.Reader) (Rules, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Rules{}, err
+	}
+	var rules Rules
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return Rules{}, fmt.Errorf("invalid rules file: %w", err)
+	}
+
+	b := &rules.Buckets
+	if b.NamePattern != "" {
+		if b.name, err = regexp.Compile(b.NamePattern); err != nil {
+			return Rules{}, fmt.Errorf("invalid name_pattern: %w", err)
+		}
+	}
+	for label, value := range b.DefaultLabels {
+		if allowed, ok := b.LabelValues[label]; ok && !slices.Contains(allowed, value) {
+			return Rules{}, fmt.Errorf("default value %q of label %q is not one of its label_values", value, label)
+		}
+	}
+	if b.Encryption != "" && b.Encryption != EncryptionCustomerManaged {
+		return Rules{}, fmt.Errorf("invalid encryption %q: expected %s", b.Encryption, EncryptionCustomerManaged)
+	}
+	return rules, nil
+}
+
+// Violation is a bucket breaking a rule.
+type Violation struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	Rule     string `json:"rule" yaml:"rule"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+// Report is the violations found among a set of buckets.
+type Report struct {
+	Violations []Violation `json:"violations" yaml:"violations"`
+	Buckets    int         `json:"buckets" yaml:"buckets"`
+}
+
+// Check returns the rules each of buckets breaks, ordered by provider and
+// bucket. Buckets should be described in full (see inventory.Describe), since
+// listings leave out labels and encryption on some providers.
+func Check(buckets []storage.Bucket, rules Rules) Report {
+	report := Report{Violations: []Violation{}, Buckets: len(buckets)}
+	r := rules.Buckets
+	for _, b := range buckets {
+		violation := func(rule, format string, args ...any) {
+			report.Violations = append(report.Violations, Violation{
+				Provider: strings.ToLower(string(b.Provider)),
+				Bucket:   b.Name,
+				Rule:     rule,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		if r.name != nil && !r.name.MatchString(b.Name) {
+			violation(RuleName, "name does not match %s", r.NamePattern)
+		}
+		for _, label := range r.RequiredLabels {
+			if _, ok := b.Labels[label]; !ok {
+				violation(RuleRequiredLabel, "missing label %q", label)
+			}
+		}
+		for _, label := range slices.Sorted(maps.Keys(r.LabelValues)) {
+			if value, ok := b.Labels[label]; ok && !slices.Contains(r.LabelValues[label], value) {
+				violation(RuleLabelValue, "label %q is %q, expected one of %s", label, value, strings.Join(r.LabelValues[label], ", "))
+			}
+		}
+		if len(r.Locations) > 0 && !slices.ContainsFunc(r.Locations, func(l string) bool { return strings.EqualFold(l, b.Location) }) {
+			violation(RuleLocation, "location %s is not allowed", cmp.Or(b.Location, "(unknown)"))
+		}
+		if r.Encryption == EncryptionCustomerManaged && inventory.CustomerManagedKey(b) == "" {
+			violation(RuleEncryption, "not encrypted with a customer-managed key")
+		}
+	}
+	slices.SortStableFunc(report.Violations, func(a, b Violation) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Bucket, b.Bucket))
+	})
+	return report
+}
@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+const testRules = `
+buckets:
+  name_pattern: '^acme-[a-z0-9-]+$'
+  required_labels: [team, env]
+  label_values:
+    env: [prod, dev]
+  locations: [EU, europe-west1]
+  encryption: customer-managed
+`
+
+func TestReadRules(t *testing.T) {
+	rules, err := ReadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules.Buckets.RequiredLabels) != 2 || rules.Buckets.name == nil {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+
+	for _, invalid := range []string{
+		"buckets:\n  name_pattern: '('\n",
+		"buckets:\n  encryption: strong\n",
+		"buckets:\n  labels: [team]\n",
+		"buckets:\n  label_values: {env: [prod]}\n  default_labels: {env: dev}\n",
+	} {
+		if _, err := ReadRules(strings.NewReader(invalid)); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules, err := ReadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buckets := []storage.Bucket{
+		{Name: "acme-data", Provider: domain.GCP, Location: "eu", Labels: map[string]string{"team": "data", "env": "prod"},
+			Encryption: &storage.Encryption{KmsKeyName: "projects/p/keyRings/r/cryptoKeys/k"}},
+		{Name: "Scratch", Provider: domain.AWS, Location: "us-east-1", Labels: map[string]string{"env": "test"},
+			Encryption: &storage.Encryption{Algorithm: "aws:kms", KmsKeyName: "alias/aws/s3"}},
+	}
+
+	report := Check(buckets, rules)
+	if report.Buckets != 2 {
+		t.Errorf("Buckets = %d, want 2", report.Buckets)
+	}
+	var got []string
+	for _, v := range report.Violations {
+		if v.Bucket != "Scratch" || v.Provider != "aws" {
+			t.Errorf("unexpected violation: %+v", v)
+		}
+		got = append(got, v.Rule)
+	}
+	want := []string{RuleName, RuleRequiredLabel, RuleLabelValue, RuleLocation, RuleEncryption}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("rules broken = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestFile_AppendsAcrossOpens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "synkronus.log")
+
+	for _, line := range []string{"first\n", "second\n"} {
+		f, err := OpenFile(path)
+		if err != nil {
+			t.Fatalf("OpenFile failed: %v", err)
+		}
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		f.Close()
+	}
+
+	if got := readFile(t, path); got != "first\nsecond\n" {
+		t.Errorf("got %q, want both lines", got)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %04o", info.Mode().Perm())
+	}
+}
+
+func TestFile_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synkronus.log")
+	f, err := openFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("openFile failed: %v", err)
+	}
+	defer f.Close()
+
+	for _, record := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := f.Write([]byte(record)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	// Each record fills a file, so the oldest ("a") was dropped
+	want := map[string]string{path: "dddddddd\n", path + ".1": "cccccccc\n", path + ".2": "bbbbbbbb\n"}
+	for p, content := range want {
+		if got := readFile(t, p); got != content {
+			t.Errorf("%s: got %q, want %q", filepath.Base(p), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected no third backup, stat error: %v", err)
+	}
+}
+
+func TestFile_WriteAfterClose(t *testing.T) {
+	f, err := OpenFile(filepath.Join(t.TempDir(), "synkronus.log"))
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := f.Write([]byte("late\n")); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("expected a closed-file error, got %v", err)
+	}
+}
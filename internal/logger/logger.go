@@ -2,16 +2,44 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
-	"os"
 )
 
-func NewLogger(level slog.Level) *slog.Logger {
+// Format is the encoding of log records
+type Format string
+
+const (
+	// FormatText writes key=value records, for people
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per record, for log aggregation
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates a log format name. An empty name means FormatText.
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported log format %q: must be text or json", name)
+	}
+}
+
+// NewLogger returns a logger writing format records to w (stderr, or a log file
+// so logs never mix into command output) and makes it the slog default.
+func NewLogger(level slog.Level, w io.Writer, format Format) *slog.Logger {
 	opts := &slog.HandlerOptions{
 		Level: level,
 	}
 
-	handler := slog.NewTextHandler(os.Stderr, opts)
+	var handler slog.Handler = slog.NewTextHandler(w, opts)
+	if format == FormatJSON {
+		handler = slog.NewJSONHandler(w, opts)
+	}
 
 	logger := slog.New(handler)
 
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{name: "", want: FormatText},
+		{name: "text", want: FormatText},
+		{name: "json", want: FormatJSON},
+		{name: "yaml", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewLogger_Formats(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var buf bytes.Buffer
+	NewLogger(slog.LevelInfo, &buf, FormatJSON).Info("hello", "bucket", "b1")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "hello" || record["bucket"] != "b1" {
+		t.Errorf("unexpected record: %v", record)
+	}
+
+	buf.Reset()
+	NewLogger(slog.LevelInfo, &buf, FormatText).Info("hello", "bucket", "b1")
+	if !strings.Contains(buf.String(), "msg=hello bucket=b1") {
+		t.Errorf("expected a text record, got %q", buf.String())
+	}
+}
@@ -0,0 +1,264 @@
+// Package metadata exports the content headers (Content-Type, Cache-Control...)
+// and user-defined metadata of many objects as JSON Lines, and applies such a
+// file back, restoring each object's headers and metadata as recorded.
+package metadata
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxLineBytes bounds a line of a metadata file
+const maxLineBytes = 1 << 20
+
+// Record is one line of a metadata file: an object and its headers and
+// metadata. Headers left out (or empty) are removed on apply, as are metadata
+// keys not listed.
+type Record struct {
+	Provider           string            `json:"provider"`
+	Bucket             string            `json:"bucket"`
+	Key                string            `json:"key"`
+	ContentType        string            `json:"content_type,omitempty"`
+	ContentEncoding    string            `json:"content_encoding,omitempty"`
+	ContentLanguage    string            `json:"content_language,omitempty"`
+	CacheControl       string            `json:"cache_control,omitempty"`
+	ContentDisposition string            `json:"content_disposition,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+
+	// line is the record's line number in the file it was read from
+	line int
+}
+
+// Attributes returns the headers and metadata the record sets.
+func (r Record) Attributes() storage.ObjectAttributes {
+	return storage.ObjectAttributes{
+		ContentType:        r.ContentType,
+		ContentEncoding:    r.ContentEncoding,
+		ContentLanguage:    r.ContentLanguage,
+		CacheControl:       r.CacheControl,
+		ContentDisposition: r.ContentDisposition,
+		Metadata:           r.Metadata,
+	}
+}
+
+// Source is the subset of the storage service an export needs.
+type Source interface {
+	WalkObjects(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.Object) error) error
+	DescribeObject(ctx context.Context, bucketName, objectKey, providerName string) (storage.Object, error)
+}
+
+// Export writes a record for every object under prefix to w, one JSON object
+// per line, in key order. Listings do not carry every header and metadata on
+// every provider (S3's omit both), so each object is described, parallel at a
+// time. It returns the number of records written.
+func Export(ctx context.Context, src Source, provider, bucket, prefix string, parallel int, w io.Writer) (int, error) {
+	if parallel <= 0 {
+		return 0, fmt.Errorf("parallelism must be positive, got %d", parallel)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Objects are described out of order, but each is written once those
+	// listed before it are
+	type pending struct {
+		key  string
+		done chan struct{}
+		rec  Record
+		err  error
+	}
+	queue := make(chan *pending, parallel)
+	sem := make(chan struct{}, parallel)
+	var walkErr error
+	var wg sync.WaitGroup
+	go func() {
+		defer close(queue)
+		walkErr = src.WalkObjects(ctx, bucket, provider, prefix, func(obj storage.Object) error {
+			p := &pending{key: obj.Key, done: make(chan struct{})}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			wg.Go(func() {
+				defer func() { <-sem }()
+				defer close(p.done)
+				described, err := src.DescribeObject(ctx, bucket, obj.Key, provider)
+				p.rec, p.err = recordOf(provider, bucket, described), err
+			})
+			select {
+			case queue <- p:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	enc := json.NewEncoder(w)
+	var written int
+	var err error
+	for p := range queue {
+		<-p.done
+		if err != nil {
+			continue
+		}
+		if p.err != nil {
+			err = p.err
+			cancel()
+			continue
+		}
+		if err = enc.Encode(p.rec); err != nil {
+			cancel()
+			continue
+		}
+		written++
+	}
+	wg.Wait()
+	if err != nil {
+		return written, err
+	}
+	return written, walkErr
+}
+
+func recordOf(provider, bucket string, obj storage.Object) Record {
+	return Record{
+		Provider:           strings.ToLower(provider),
+		Bucket:             bucket,
+		Key:                obj.Key,
+		ContentType:        obj.ContentType,
+		ContentEncoding:    obj.ContentEncoding,
+		ContentLanguage:    obj.ContentLanguage,
+		CacheControl:       obj.CacheControl,
+		ContentDisposition: obj.ContentDisposition,
+		Metadata:           obj.Metadata,
+	}
+}
+
+// Parse reads a metadata file written by Export. Blank lines are ignored.
+// Every line is checked before any is applied, so a malformed file fails as
+// a whole, naming the line at fault.
+func Parse(r io.Reader) ([]Record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	var records []Record
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec Record
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("invalid metadata file line %d: %w", line, err)
+		}
+		for name, value := range map[string]string{"provider": rec.Provider, "bucket": rec.Bucket, "key": rec.Key} {
+			if value == "" {
+				return nil, fmt.Errorf("invalid metadata file line %d: %s is empty", line, name)
+			}
+		}
+		rec.Provider = strings.ToLower(rec.Provider)
+		rec.line = line
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid metadata file: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("metadata file has no objects")
+	}
+	return records, nil
+}
+
+// Setter is the subset of the storage service an apply needs.
+type Setter interface {
+	SetObjectAttributes(ctx context.Context, bucketName, objectKey, providerName string, attrs storage.ObjectAttributes) error
+}
+
+// Status is the outcome of applying a record.
+type Status string
+
+const (
+	StatusApplied Status = "applied"
+	StatusFailed  Status = "failed"
+	// StatusNotRun is a record left over when the apply was interrupted
+	StatusNotRun Status = "not-run"
+)
+
+// Result is the outcome of applying one record.
+type Result struct {
+	Line     int    `json:"line"`
+	Provider string `json:"provider"`
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Report is the outcome of an apply, with a result per record in file order.
+type Report struct {
+	Results []Result `json:"results"`
+	Applied int      `json:"applied"`
+	Failed  int      `json:"failed"`
+	NotRun  int      `json:"not_run" yaml:"not_run"`
+}
+
+// Apply sets the headers and metadata of each record's object, parallel at a
+// time. A failed record does not stop the others; it is reported, and the
+// returned error counts the failures. When ctx is canceled, the records not
+// yet started are reported as not run and the context's error is returned.
+func Apply(ctx context.Context, setter Setter, records []Record, parallel int) (Report, error) {
+	if parallel <= 0 {
+		return Report{}, fmt.Errorf("parallelism must be positive, got %d", parallel)
+	}
+
+	report := Report{Results: make([]Result, len(records))}
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(parallel, len(records)) {
+		wg.Go(func() {
+			for i := range work {
+				rec := records[i]
+				result := Result{Line: rec.line, Provider: rec.Provider, Bucket: rec.Bucket, Key: rec.Key, Status: StatusNotRun}
+				if ctx.Err() == nil {
+					result.Status = StatusApplied
+					if err := setter.SetObjectAttributes(ctx, rec.Bucket, rec.Key, rec.Provider, rec.Attributes()); err != nil {
+						result.Status, result.Error = StatusFailed, err.Error()
+					}
+				}
+				// Each worker writes its own index, so no lock is needed
+				report.Results[i] = result
+			}
+		})
+	}
+	for i := range records {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for _, r := range report.Results {
+		switch r.Status {
+		case StatusApplied:
+			report.Applied++
+		case StatusFailed:
+			report.Failed++
+		case StatusNotRun:
+			report.NotRun++
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if report.Failed > 0 {
+		return report, fmt.Errorf("%d of %d objects failed", report.Failed, len(records))
+	}
+	return report, nil
+}
@@ -0,0 +1,136 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+type fakeStorage struct {
+	mu      sync.Mutex
+	objects map[string]storage.Object
+	failKey string
+	set     map[string]storage.ObjectAttributes
+}
+
+func (f *fakeStorage) WalkObjects(_ context.Context, _, _, prefix string, fn func(storage.Object) error) error {
+	for _, key := range []string{"site/a.html", "site/b.css", "site/c.js"} {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if err := fn(storage.Object{Key: key}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeStorage) DescribeObject(_ context.Context, _, key, _ string) (storage.Object, error) {
+	if key == f.failKey {
+		return storage.Object{}, errors.New("access denied")
+	}
+	return f.objects[key], nil
+}
+
+func (f *fakeStorage) SetObjectAttributes(_ context.Context, _, key, _ string, attrs storage.ObjectAttributes) error {
+	if key == f.failKey {
+		return errors.New("access denied")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.set[key] = attrs
+	return nil
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{
+		objects: map[string]storage.Object{
+			"site/a.html": {Key: "site/a.html", ContentType: "text/html", Metadata: map[string]string{"owner": "web"}},
+			"site/b.css":  {Key: "site/b.css", ContentType: "text/css", CacheControl: "max-age=60"},
+			"site/c.js":   {Key: "site/c.js", ContentType: "text/javascript"},
+		},
+		set: make(map[string]storage.ObjectAttributes),
+	}
+}
+
+func TestExportThenApply(t *testing.T) {
+	src := newFakeStorage()
+	var buf bytes.Buffer
+	n, err := Export(context.Background(), src, "GCP", "bucket", "site/", 2, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 records, got %d", n)
+	}
+	want := `{"provider":"gcp","bucket":"bucket","key":"site/a.html","content_type":"text/html","metadata":{"owner":"web"}}
+{"provider":"gcp","bucket":"bucket","key":"site/b.css","content_type":"text/css","cache_control":"max-age=60"}
+{"provider":"gcp","bucket":"bucket","key":"site/c.js","content_type":"text/javascript"}
+`
+	if buf.String() != want {
+		t.Errorf("expected the records in key order:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	records, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := Apply(context.Background(), src, records, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Applied != 3 || report.Results[1].Line != 2 {
+		t.Errorf("expected 3 lines applied in file order, got %+v", report)
+	}
+	if got := src.set["site/b.css"]; got.CacheControl != "max-age=60" || got.ContentType != "text/css" {
+		t.Errorf("expected the exported headers applied, got %+v", got)
+	}
+}
+
+func TestExport_DescribeFailure(t *testing.T) {
+	src := newFakeStorage()
+	src.failKey = "site/b.css"
+	var buf bytes.Buffer
+	n, err := Export(context.Background(), src, "gcp", "bucket", "", 4, &buf)
+	if err == nil {
+		t.Fatal("expected the describe failure to be returned")
+	}
+	if n != 1 || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("expected only the record before the failure, got %d:\n%s", n, buf.String())
+	}
+}
+
+func TestApply_ReportsFailures(t *testing.T) {
+	src := newFakeStorage()
+	src.failKey = "b"
+	records, err := Parse(strings.NewReader("{\"provider\":\"gcp\",\"bucket\":\"x\",\"key\":\"a\"}\n\n{\"provider\":\"gcp\",\"bucket\":\"x\",\"key\":\"b\"}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Apply(context.Background(), src, records, 1)
+	if err == nil {
+		t.Fatal("expected the failure to be counted")
+	}
+	if report.Applied != 1 || report.Failed != 1 || report.Results[1].Line != 3 || report.Results[1].Error == "" {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":      "nope\n",
+		"missing key":   `{"provider":"gcp","bucket":"x"}`,
+		"unknown field": `{"provider":"gcp","bucket":"x","key":"a","colour":"red"}`,
+		"empty":         "\n\n",
+	}
+	for name, input := range tests {
+		if _, err := Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
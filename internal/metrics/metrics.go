@@ -0,0 +1,160 @@
+// Package metrics counts provider API calls, errors, bytes transferred and job
+// durations in Prometheus form. The counts are always collected; Serve exposes
+// them over HTTP in long-running modes, where scraping them is meaningful.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "synkronus"
+
+// Transfer directions for AddBytes
+const (
+	Upload   = "upload"
+	Download = "download"
+)
+
+// shutdownTimeout bounds how long Serve waits for in-flight scrapes
+const shutdownTimeout = 5 * time.Second
+
+var (
+	registry = prometheus.NewRegistry()
+
+	apiCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_calls_total",
+		Help:      "Provider API operations, by provider and operation.",
+	}, []string{"provider", "operation"})
+
+	apiErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_errors_total",
+		Help:      "Provider API operations that failed, by provider and operation.",
+	}, []string{"provider", "operation"})
+
+	apiDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "api_call_duration_seconds",
+		Help:      "Duration of provider API operations, including retries.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "operation"})
+
+	bytesTransferred = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bytes_transferred_total",
+		Help:      "Object bytes uploaded and downloaded, by provider and direction.",
+	}, []string{"provider", "direction"})
+
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Duration of jobs run by long-running modes, by job and result.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14), // 1s to ~2h
+	}, []string{"job", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		apiCalls, apiErrors, apiDuration, bytesTransferred, jobDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// ObserveCall records a provider API operation that started at start and
+// ended with err.
+func ObserveCall(provider, operation string, start time.Time, err error) {
+	apiCalls.WithLabelValues(provider, operation).Inc()
+	apiDuration.WithLabelValues(provider, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		apiErrors.WithLabelValues(provider, operation).Inc()
+	}
+}
+
+// AddBytes records n object bytes moved in direction (Upload or Download).
+func AddBytes(provider, direction string, n int64) {
+	if n > 0 {
+		bytesTransferred.WithLabelValues(provider, direction).Add(float64(n))
+	}
+}
+
+// ObserveJob records a job that took d and ended with err.
+func ObserveJob(job string, d time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	jobDuration.WithLabelValues(job, result).Observe(d.Seconds())
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Serve exposes the metrics at /metrics on addr (e.g. ":9464") until ctx is
+// done. It returns once the listener is open, so a bad address is reported
+// to the caller; errors after that are logged.
+func Serve(ctx context.Context, addr string, logger *slog.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to serve metrics on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("Metrics server stopped", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Debug("Serving metrics", "address", listener.Addr().String())
+	return nil
+}
+
+// CountReader returns r, reporting the bytes read through it to AddBytes.
+func CountReader(r io.Reader, provider, direction string) io.Reader {
+	return &countingReader{r: r, provider: provider, direction: direction}
+}
+
+// CountReadCloser is CountReader for readers that must be closed, such as
+// downloads.
+func CountReadCloser(rc io.ReadCloser, provider, direction string) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{CountReader(rc, provider, direction), rc}
+}
+
+type countingReader struct {
+	r         io.Reader
+	provider  string
+	direction string
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	AddBytes(c.provider, c.direction, int64(n))
+	return n, err
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveCall(t *testing.T) {
+	calls := testutil.ToFloat64(apiCalls.WithLabelValues("gcp", "test.Describe"))
+	errs := testutil.ToFloat64(apiErrors.WithLabelValues("gcp", "test.Describe"))
+
+	ObserveCall("gcp", "test.Describe", time.Now(), nil)
+	ObserveCall("gcp", "test.Describe", time.Now(), errors.New("denied"))
+
+	if got := testutil.ToFloat64(apiCalls.WithLabelValues("gcp", "test.Describe")) - calls; got != 2 {
+		t.Errorf("expected 2 more calls, got %v", got)
+	}
+	if got := testutil.ToFloat64(apiErrors.WithLabelValues("gcp", "test.Describe")) - errs; got != 1 {
+		t.Errorf("expected 1 more error, got %v", got)
+	}
+}
+
+func TestCountReadCloser(t *testing.T) {
+	before := testutil.ToFloat64(bytesTransferred.WithLabelValues("aws", Download))
+
+	rc := CountReadCloser(io.NopCloser(strings.NewReader("hello world")), "aws", Download)
+	if _, err := io.Copy(io.Discard, rc); err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.ToFloat64(bytesTransferred.WithLabelValues("aws", Download)) - before; got != 11 {
+		t.Errorf("expected 11 bytes counted, got %v", got)
+	}
+}
+
+func TestHandler_ExposesMetrics(t *testing.T) {
+	ObserveJob("test-job", 3*time.Second, nil)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"synkronus_api_calls_total",
+		`synkronus_job_duration_seconds_count{job="test-job",result="success"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in the exposition", want)
+		}
+	}
+}
+
+func TestServe_InvalidAddress(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if err := Serve(context.Background(), "not-an-address", logger); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
@@ -0,0 +1,163 @@
+package network
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// redactedHeaders carry credentials. Only their scheme (e.g. "Bearer") is logged.
+var redactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Goog-Api-Key",
+	"X-Amz-Security-Token",
+	"X-Amz-Server-Side-Encryption-Customer-Key",
+}
+
+// redactedParams carry credentials in signed or keyed URLs
+var redactedParams = []string{
+	"X-Amz-Credential",
+	"X-Amz-Security-Token",
+	"X-Amz-Signature",
+	"X-Goog-Credential",
+	"X-Goog-Security-Token",
+	"X-Goog-Signature",
+	"access_token",
+	"key",
+}
+
+const redacted = "REDACTED"
+
+var debugLogger *slog.Logger
+
+// SetDebugHTTPLogger makes DebugTransport and DebugHTTPClient log every
+// provider API request and its response to logger, with credentials redacted;
+// nil turns the logging off. Like Apply, it must be called before any provider
+// client is created.
+func SetDebugHTTPLogger(logger *slog.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	debugLogger = logger
+}
+
+// DebugHTTPEnabled reports whether a debug HTTP logger is set.
+func DebugHTTPEnabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return debugLogger != nil
+}
+
+// DebugTransport returns next, wrapped to log each exchange when debug HTTP
+// logging is enabled. Google clients are built on it.
+func DebugTransport(next http.RoundTripper) http.RoundTripper {
+	mu.RLock()
+	defer mu.RUnlock()
+	if debugLogger == nil {
+		return next
+	}
+	return debugTransport{next: next, logger: debugLogger}
+}
+
+// HTTPClient is the interface of SDK HTTP clients such as the AWS SDK's.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DebugHTTPClient is DebugTransport for clients, like the AWS SDK's, that
+// send requests through a Do method instead of a RoundTripper.
+func DebugHTTPClient(next HTTPClient) HTTPClient {
+	mu.RLock()
+	defer mu.RUnlock()
+	if debugLogger == nil {
+		return next
+	}
+	return debugClient{next: next, logger: debugLogger}
+}
+
+type debugTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return logExchange(t.logger, req, t.next.RoundTrip)
+}
+
+type debugClient struct {
+	next   HTTPClient
+	logger *slog.Logger
+}
+
+func (c debugClient) Do(req *http.Request) (*http.Response, error) {
+	return logExchange(c.logger, req, c.next.Do)
+}
+
+// logExchange logs req, sends it, and logs the response (or error) with the
+// round trip time. Bodies are not logged.
+func logExchange(logger *slog.Logger, req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	method, target := slog.String("method", req.Method), slog.String("url", redactURL(req.URL))
+	logger.LogAttrs(ctx, slog.LevelInfo, "HTTP request", method, target, headerAttrs(req.Header))
+
+	start := time.Now()
+	resp, err := send(req)
+	duration := slog.Duration("duration", time.Since(start))
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelInfo, "HTTP request failed", method, target, duration, slog.Any("error", err))
+		return resp, err
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, "HTTP response", method, target, slog.Int("status", resp.StatusCode), duration, headerAttrs(resp.Header))
+	return resp, nil
+}
+
+// headerAttrs groups h under "headers", sorted by name, with credentials redacted.
+func headerAttrs(h http.Header) slog.Attr {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h.Values(name), ", ")
+		if slices.ContainsFunc(redactedHeaders, func(r string) bool { return strings.EqualFold(r, name) }) {
+			value = redactValue(value)
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+// redactValue keeps the scheme of a credential ("Bearer", "AWS4-HMAC-SHA256")
+// so the kind of authentication stays visible.
+func redactValue(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok {
+		return scheme + " " + redacted
+	}
+	return redacted
+}
+
+// redactURL returns u with credential query parameters redacted.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if slices.ContainsFunc(redactedParams, func(r string) bool { return strings.EqualFold(r, name) }) {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	clean := *u
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
@@ -0,0 +1,101 @@
+package network
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// useDebugLogger enables debug HTTP logging into a buffer for the test.
+func useDebugLogger(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetDebugHTTPLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { SetDebugHTTPLogger(nil) })
+	return &buf
+}
+
+func TestDebugTransport_DisabledReturnsNext(t *testing.T) {
+	if DebugHTTPEnabled() {
+		t.Fatal("expected debug HTTP logging to be off by default")
+	}
+	if got := DebugTransport(http.DefaultTransport); got != http.DefaultTransport {
+		t.Errorf("expected the transport unchanged, got %T", got)
+	}
+	if got := DebugHTTPClient(http.DefaultClient); got != http.DefaultClient {
+		t.Errorf("expected the client unchanged, got %T", got)
+	}
+}
+
+func TestDebugTransport_LogsExchange(t *testing.T) {
+	buf := useDebugLogger(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc123")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/storage/v1/b?project=p1&access_token=tok123", nil)
+	req.Header.Set("Authorization", "Bearer ya29.secret")
+	resp, err := (&http.Client{Transport: DebugTransport(http.DefaultTransport)}).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	logs := buf.String()
+	for _, want := range []string{
+		`msg="HTTP request" method=GET`,
+		"project=p1",
+		"headers.Authorization=\"Bearer REDACTED\"",
+		`msg="HTTP response"`,
+		"status=403",
+		"duration=",
+		"headers.Set-Cookie=REDACTED",
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected %q in logs:\n%s", want, logs)
+		}
+	}
+	for _, secret := range []string{"ya29.secret", "tok123", "abc123"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("credential %q leaked into logs:\n%s", secret, logs)
+		}
+	}
+}
+
+func TestDebugHTTPClient_LogsFailures(t *testing.T) {
+	buf := useDebugLogger(t)
+
+	req, _ := http.NewRequest(http.MethodPut, "http://127.0.0.1:1/bucket/key", nil)
+	if _, err := DebugHTTPClient(http.DefaultClient).Do(req); err == nil {
+		t.Fatal("expected the request to fail")
+	}
+	if !strings.Contains(buf.String(), `msg="HTTP request failed" method=PUT`) {
+		t.Errorf("expected the failure to be logged, got:\n%s", buf.String())
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://storage.googleapis.com/storage/v1/b?project=p1", "https://storage.googleapis.com/storage/v1/b?project=p1"},
+		{"https://b.s3.amazonaws.com/k?X-Amz-Signature=abc&X-Amz-Credential=AKIA%2F&versionId=1",
+			"https://b.s3.amazonaws.com/k?X-Amz-Credential=REDACTED&X-Amz-Signature=REDACTED&versionId=1"},
+		{"https://storage.googleapis.com/b/k?X-Goog-Algorithm=GOOG4-RSA-SHA256&X-Goog-Credential=sa%40p.iam.gserviceaccount.com%2F20261017%2Fauto%2Fstorage%2Fgoog4_request&X-Goog-Signature=0a1b2c",
+			"https://storage.googleapis.com/b/k?X-Goog-Algorithm=GOOG4-RSA-SHA256&X-Goog-Credential=REDACTED&X-Goog-Signature=REDACTED"},
+		{"https://example.com/v1?key=AIza", "https://example.com/v1?key=REDACTED"},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.raw)
+		if got := redactURL(u); got != tt.want {
+			t.Errorf("redactURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
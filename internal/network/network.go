@@ -0,0 +1,130 @@
+// Package network applies the proxy and CA settings from the config to the
+// transports used by the providers. Google HTTP clients (including their token
+// exchanges) build on http.DefaultTransport, which Apply configures; the AWS
+// SDK builds its own transport and is configured through ConfigureTransport.
+// gRPC clients read the proxy from the environment, which Apply exports, and
+// take the CA pool from RootCAs. SetDebugHTTPLogger turns on logging of the API
+// requests made through these transports.
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	mu      sync.RWMutex
+	proxy   func(*http.Request) (*url.URL, error)
+	rootCAs *x509.CertPool
+)
+
+// Apply makes cfg the process-wide network settings and configures
+// http.DefaultTransport with them. A nil cfg leaves the defaults in place
+// (proxy from the environment, system CAs). It must be called before any
+// provider client is created.
+func Apply(cfg *config.NetworkConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	var proxyFunc func(*http.Request) (*url.URL, error)
+	if cfg.Proxy != "" {
+		proxyFunc = proxyFor(cfg)
+	}
+
+	var pool *x509.CertPool
+	if cfg.CABundle != "" {
+		var err error
+		if pool, err = loadCABundle(cfg.CABundle); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	proxy, rootCAs = proxyFunc, pool
+	mu.Unlock()
+
+	// Only the settings that are configured are exported, so the environment's
+	// own values are kept otherwise
+	exports := map[string]string{}
+	if cfg.Proxy != "" {
+		exports["HTTPS_PROXY"], exports["HTTP_PROXY"] = cfg.Proxy, cfg.Proxy
+	}
+	if len(cfg.NoProxy) > 0 {
+		exports["NO_PROXY"] = strings.Join(cfg.NoProxy, ",")
+	}
+	for key, value := range exports {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to export %s: %w", key, err)
+		}
+	}
+
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		ConfigureTransport(t)
+	}
+	return nil
+}
+
+// ConfigureTransport applies the settings passed to Apply to t. It has the
+// signature of the AWS SDK's transport options so it can be passed directly.
+func ConfigureTransport(t *http.Transport) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if proxy != nil {
+		t.Proxy = proxy
+	}
+	if rootCAs != nil {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.RootCAs = rootCAs
+	}
+}
+
+// RootCAs returns the CA pool from network.ca_bundle (system roots included),
+// or nil if none is configured.
+func RootCAs() *x509.CertPool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return rootCAs
+}
+
+// proxyFor returns a proxy function that sends both HTTP and HTTPS requests
+// through cfg.Proxy, except for hosts matching cfg.NoProxy.
+func proxyFor(cfg *config.NetworkConfig) func(*http.Request) (*url.URL, error) {
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  cfg.Proxy,
+		HTTPSProxy: cfg.Proxy,
+		NoProxy:    strings.Join(cfg.NoProxy, ","),
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// loadCABundle returns the system roots plus the certificates in path.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network.ca_bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("network.ca_bundle %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,146 @@
+package network
+
+import (
+	"encoding/pem"
+	"github.com/jkleinne/synkronus/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// resetNetwork restores the process-wide settings changed by Apply.
+func resetNetwork(t *testing.T) {
+	t.Helper()
+	original := http.DefaultTransport
+	http.DefaultTransport = original.(*http.Transport).Clone()
+	for _, key := range []string{"HTTPS_PROXY", "HTTP_PROXY", "NO_PROXY"} {
+		t.Setenv(key, os.Getenv(key))
+	}
+	t.Cleanup(func() {
+		http.DefaultTransport = original
+		mu.Lock()
+		proxy, rootCAs = nil, nil
+		mu.Unlock()
+	})
+}
+
+func TestProxyFor(t *testing.T) {
+	proxyFunc := proxyFor(&config.NetworkConfig{
+		Proxy:   "http://proxy.corp:3128",
+		NoProxy: []string{"localhost", ".internal.corp"},
+	})
+
+	tests := []struct {
+		url       string
+		wantProxy bool
+	}{
+		{"https://storage.googleapis.com/storage/v1/b", true},
+		{"https://s3.us-east-1.amazonaws.com/", true},
+		{"http://localhost:4566/", false},
+		{"https://minio.internal.corp/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			got, err := proxyFunc(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got != nil) != tt.wantProxy {
+				t.Errorf("proxy = %v, want proxied %v", got, tt.wantProxy)
+			}
+		})
+	}
+}
+
+func TestApply_CABundle(t *testing.T) {
+	resetNetwork(t)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the bundle the test server's self-signed certificate is rejected
+	if _, err := (&http.Client{Transport: &http.Transport{}}).Get(srv.URL); err == nil {
+		t.Fatal("expected a certificate error before applying the bundle")
+	}
+
+	if err := Apply(&config.NetworkConfig{CABundle: bundle}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if RootCAs() == nil {
+		t.Fatal("expected RootCAs to be set")
+	}
+
+	transport := &http.Transport{}
+	ConfigureTransport(transport)
+	resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected the bundle to be trusted: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected http.DefaultTransport to trust the bundle: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestApply_InvalidCABundle(t *testing.T) {
+	resetNetwork(t)
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Apply(&config.NetworkConfig{CABundle: bundle}); err == nil {
+		t.Fatal("expected error for a bundle without certificates")
+	}
+	if RootCAs() != nil {
+		t.Error("a failed Apply must not change the settings")
+	}
+}
+
+func TestApply_ProxyExportedForGRPC(t *testing.T) {
+	resetNetwork(t)
+	if err := Apply(&config.NetworkConfig{Proxy: "http://proxy.corp:3128", NoProxy: []string{"localhost"}}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := os.Getenv("HTTPS_PROXY"); got != "http://proxy.corp:3128" {
+		t.Errorf("HTTPS_PROXY = %q", got)
+	}
+	if got := os.Getenv("NO_PROXY"); got != "localhost" {
+		t.Errorf("NO_PROXY = %q", got)
+	}
+}
+
+func TestApply_KeepsEnvironmentProxy(t *testing.T) {
+	t.Run("no proxy configured", func(t *testing.T) {
+		resetNetwork(t)
+		t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+		if err := Apply(&config.NetworkConfig{NoProxy: []string{"localhost"}}); err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		if got := os.Getenv("HTTPS_PROXY"); got != "http://env-proxy:3128" {
+			t.Errorf("HTTPS_PROXY = %q, want the environment's", got)
+		}
+	})
+
+	t.Run("no no_proxy configured", func(t *testing.T) {
+		resetNetwork(t)
+		t.Setenv("NO_PROXY", "10.0.0.1")
+		if err := Apply(&config.NetworkConfig{Proxy: "http://proxy.corp:3128"}); err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		if got := os.Getenv("NO_PROXY"); got != "10.0.0.1" {
+			t.Errorf("NO_PROXY = %q, want the environment's", got)
+		}
+	})
+}
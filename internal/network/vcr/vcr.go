@@ -0,0 +1,225 @@
+// Package vcr records the HTTP exchanges of provider clients to fixture files
+// and replays them in tests, so the mapping of real API responses is covered
+// without credentials or network access. Fixtures are replayed by default; run
+// the tests with SYNKRONUS_VCR=record and live credentials to refresh them.
+//
+// Only HTTP APIs can be recorded. gRPC clients, such as GCP's Monitoring
+// client, bypass the recorder.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/network"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ModeEnv selects recording when set to "record"
+const ModeEnv = "SYNKRONUS_VCR"
+
+// droppedHeaders are left out of fixtures: credentials, and values that change
+// on every request and would only add noise to fixture diffs
+var droppedHeaders = []string{
+	"Set-Cookie",
+	"Date",
+	"Expires",
+	"Alt-Svc",
+	"Server-Timing",
+	"X-Amz-Id-2",
+	"X-Amz-Request-Id",
+	"X-Guploader-Uploadid",
+}
+
+// redactedParams carry credentials in signed or keyed URLs
+var redactedParams = []string{
+	"X-Amz-Credential",
+	"X-Amz-Security-Token",
+	"X-Amz-Signature",
+	"access_token",
+	"key",
+}
+
+const redacted = "REDACTED"
+
+// Interaction is a recorded request and the response it got. Request headers
+// and bodies are not kept, since they carry credentials and requests are
+// matched by method and URL alone.
+type Interaction struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper, and a network.HTTPClient for the AWS SDK,
+// that records or replays exchanges.
+type Recorder struct {
+	path      string
+	recording bool
+	next      network.HTTPClient
+	replacer  *strings.Replacer
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Recording reports whether SYNKRONUS_VCR asks for fixtures to be recorded,
+// so tests know to build their clients with live credentials.
+func Recording() bool {
+	return os.Getenv(ModeEnv) == "record"
+}
+
+// New returns a recorder for the fixture at path. When recording, requests are
+// sent with next and the exchanges are written to path when the test ends;
+// otherwise they are answered from the fixture and next is not used.
+// replacements are old, new pairs applied to recorded URLs, headers and bodies,
+// e.g. to swap a real project ID for the placeholder the test uses.
+func New(t testing.TB, path string, next network.HTTPClient, replacements ...string) *Recorder {
+	t.Helper()
+	r := &Recorder{
+		path:      path,
+		recording: Recording(),
+		next:      next,
+		replacer:  strings.NewReplacer(replacements...),
+	}
+
+	if r.recording {
+		if next == nil {
+			t.Fatalf("vcr: recording %s needs a client to send requests with", path)
+		}
+		t.Cleanup(func() {
+			if err := r.save(); err != nil {
+				t.Errorf("vcr: %v", err)
+			}
+		})
+		return r
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("vcr: fixture %s not found; record it with %s=record", path, ModeEnv)
+	}
+	if err != nil {
+		t.Fatalf("vcr: %v", err)
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		t.Fatalf("vcr: invalid fixture %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.Do(req)
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	if r.recording {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// record sends req and keeps the sanitized exchange. Failed requests are not
+// recorded.
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := http.Header{}
+	for name, values := range resp.Header {
+		if slices.ContainsFunc(droppedHeaders, func(d string) bool { return strings.EqualFold(d, name) }) {
+			continue
+		}
+		for _, v := range values {
+			header.Add(name, r.replacer.Replace(v))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Method: req.Method,
+		URL:    r.sanitizeURL(req.URL),
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   r.replacer.Replace(string(body)),
+	})
+	return resp, nil
+}
+
+// replay answers req with the first unused interaction for its method and URL,
+// so repeated requests get their responses in recorded order.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	target := r.sanitizeURL(req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || in.Method != req.Method || in.URL != target {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode:    in.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Body)),
+			ContentLength: int64(len(in.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("vcr: no recorded response for %s %s in %s", req.Method, target, r.path)
+}
+
+// sanitizeURL returns u with the replacements applied and credential query
+// parameters redacted, as it is stored in fixtures.
+func (r *Recorder) sanitizeURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for name := range query {
+		if slices.ContainsFunc(redactedParams, func(p string) bool { return strings.EqualFold(p, name) }) {
+			query.Set(name, redacted)
+		}
+	}
+	clean.RawQuery = query.Encode()
+	return r.replacer.Replace(clean.String())
+}
+
+func (r *Recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
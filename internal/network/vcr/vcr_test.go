@@ -0,0 +1,62 @@
+package vcr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"project":"real-project","path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv(ModeEnv, "record")
+		client := &http.Client{Transport: New(t, fixture, server.Client(), "real-project", "test-project")}
+		resp, err := client.Get(server.URL + "/b/real-project-bucket?access_token=secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	})
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("expected a fixture: %v", err)
+	}
+	for _, secret := range []string{"real-project", "secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("fixture leaks %q:\n%s", secret, data)
+		}
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		server.Close()
+		client := &http.Client{Transport: New(t, fixture, nil)}
+		resp, err := client.Get(server.URL + "/b/test-project-bucket?access_token=other")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if want := `{"project":"test-project","path":"/b/test-project-bucket"}`; string(body) != want {
+			t.Errorf("body = %s, want %s", body, want)
+		}
+		if resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected the recorded Content-Type, got %q", resp.Header.Get("Content-Type"))
+		}
+
+		if _, err := client.Get(server.URL + "/b/test-project-bucket?access_token=other"); err == nil {
+			t.Error("expected an error once the recorded response was used")
+		}
+	})
+}
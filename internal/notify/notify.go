@@ -0,0 +1,294 @@
+// Package notify sends the notifications configured in the notifications
+// section of the config file when a sync job run by 'synkronus serve'
+// finishes: JSON webhooks, Slack messages and emails, rendered from Go
+// templates over an Event.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// sendTimeout bounds the delivery of one notification
+const sendTimeout = 30 * time.Second
+
+// maxFailures bounds the failed objects listed in an event, so a sync that
+// fails for every object does not produce an enormous message.
+const maxFailures = 10
+
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Event describes a finished sync job. It is the JSON body of a webhook
+// without a template, and the data of every template.
+type Event struct {
+	JobID string `json:"job_id"`
+	// Job names the scheduled job that ran, or is empty for a sync submitted
+	// through the API
+	Job         string `json:"job,omitempty"`
+	Status      string `json:"status"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Copied      int    `json:"copied"`
+	Skipped     int    `json:"skipped"`
+	Bytes       int64  `json:"bytes"`
+	// FailedCount counts the objects that could not be copied; Failures lists
+	// the first of them
+	FailedCount int       `json:"failed_count"`
+	Failures    []Failure `json:"failures,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// Failure is an object a sync could not copy.
+type Failure struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// Succeeded reports whether the job succeeded.
+func (e Event) Succeeded() bool {
+	return e.Status == StatusSucceeded
+}
+
+// Name returns the scheduled job's name, or "sync <job ID>" for a sync
+// submitted through the API.
+func (e Event) Name() string {
+	if e.Job != "" {
+		return e.Job
+	}
+	return "sync " + e.JobID
+}
+
+// Duration returns how long the job ran, to the second.
+func (e Event) Duration() time.Duration {
+	return e.FinishedAt.Sub(e.StartedAt).Round(time.Second)
+}
+
+// SetFailures sets FailedCount and the first failures listed in the event.
+func (e *Event) SetFailures(failures []Failure) {
+	e.FailedCount = len(failures)
+	e.Failures = failures[:min(len(failures), maxFailures)]
+}
+
+// SampleEvent returns a failed event with made-up values, to try notifiers out.
+func SampleEvent() Event {
+	finished := time.Now().UTC().Truncate(time.Second)
+	e := Event{
+		JobID:       "0123456789abcdef",
+		Job:         "notification-test",
+		Status:      StatusFailed,
+		Source:      "gcp:data/exports/",
+		Destination: "aws:data-replica",
+		Copied:      42,
+		Skipped:     7,
+		Bytes:       3 << 30,
+		Error:       "1 of 43 objects failed to copy",
+		StartedAt:   finished.Add(-2*time.Minute - 5*time.Second),
+		FinishedAt:  finished,
+	}
+	e.SetFailures([]Failure{{Key: "exports/2024-01-01.csv", Error: "access denied"}})
+	return e
+}
+
+// templateFuncs are available to every template.
+var templateFuncs = template.FuncMap{
+	// bytes formats a byte count, e.g. {{bytes .Bytes}}
+	"bytes": storage.FormatBytes,
+	// json encodes a value as JSON, e.g. {{json .Error}} in a webhook body
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// sub subtracts two counts, e.g. {{sub .FailedCount (len .Failures)}}
+	"sub": func(a, b int) int { return a - b },
+}
+
+// defaultText is the default Slack message and email body.
+const defaultText = `Sync job {{.Name}} {{.Status}} after {{.Duration}}: {{.Source}} -> {{.Destination}}
+Copied {{.Copied}} objects ({{bytes .Bytes}}), skipped {{.Skipped}}
+{{- if .Error}}
+Error: {{.Error}}{{end}}
+{{- range .Failures}}
+  {{.Key}}: {{.Error}}{{end}}
+{{- if gt .FailedCount (len .Failures)}}
+  ... and {{sub .FailedCount (len .Failures)}} more{{end}}`
+
+// defaultSubject is the default email subject line.
+const defaultSubject = `[synkronus] Sync job {{.Name}} {{.Status}}`
+
+// parseTemplate parses text, or fallback if text is empty.
+func parseTemplate(name, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+// render executes tmpl over event.
+func render(tmpl *template.Template, event Event) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, event); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// sender delivers one notification.
+type sender interface {
+	send(ctx context.Context, event Event) error
+}
+
+// notifier is a configured notifier with the outcomes and jobs it covers.
+type notifier struct {
+	name   string
+	on     []string
+	jobs   []string
+	sender sender
+}
+
+// covers reports whether the notifier is interested in event.
+func (n *notifier) covers(event Event) bool {
+	outcome := "failure"
+	if event.Succeeded() {
+		outcome = "success"
+	}
+	if len(n.on) > 0 && !slices.Contains(n.on, outcome) {
+		return false
+	}
+	return len(n.jobs) == 0 || slices.Contains(n.jobs, event.Job)
+}
+
+// Dispatcher sends events to the configured notifiers. A nil *Dispatcher
+// sends nothing.
+type Dispatcher struct {
+	notifiers []*notifier
+	logger    *slog.Logger
+}
+
+// New returns a dispatcher for the notifiers of the notifications section,
+// or nil if there are none. It fails if a notifier lacks a setting its type
+// needs or its templates do not parse.
+func New(configs map[string]config.NotifierConfig, logger *slog.Logger) (*Dispatcher, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	d := &Dispatcher{logger: logger}
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		cfg := configs[name]
+		s, err := newSender(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %q: %w", name, err)
+		}
+		d.notifiers = append(d.notifiers, &notifier{name: name, on: cfg.On, jobs: cfg.Jobs, sender: s})
+	}
+	return d, nil
+}
+
+// newSender returns the sender for a notifier of cfg.Type.
+func newSender(cfg config.NotifierConfig) (sender, error) {
+	switch cfg.Type {
+	case "webhook", "slack":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("a %s notifier needs a url", cfg.Type)
+		}
+		if cfg.Type == "slack" {
+			return newSlack(cfg)
+		}
+		return newWebhook(cfg)
+	case "email":
+		if cfg.SMTPAddress == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, errors.New("an email notifier needs smtp_address, from and to")
+		}
+		return newEmail(cfg)
+	case "":
+		return nil, errors.New("no type set (webhook, slack or email)")
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", cfg.Type)
+	}
+}
+
+// Notify sends event to every notifier that covers it, concurrently, and
+// waits for them. Failed deliveries are logged rather than returned, so a
+// broken notifier does not affect the job or the other notifiers.
+func (d *Dispatcher) Notify(ctx context.Context, event Event) {
+	if d == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, n := range d.notifiers {
+		if !n.covers(event) {
+			continue
+		}
+		wg.Go(func() {
+			if err := d.send(ctx, n, event); err != nil {
+				d.logger.Warn("Failed to send notification", "notifier", n.name, "job", event.JobID, "error", err)
+				return
+			}
+			d.logger.Debug("Sent notification", "notifier", n.name, "job", event.JobID)
+		})
+	}
+	wg.Wait()
+}
+
+// Send sends event to the notifier called name, whether or not it covers the
+// event.
+func (d *Dispatcher) Send(ctx context.Context, name string, event Event) error {
+	n := d.find(name)
+	if n == nil {
+		return fmt.Errorf("%w: %q", ErrUnknownNotifier, name)
+	}
+	return d.send(ctx, n, event)
+}
+
+// ErrUnknownNotifier is returned by Send for a name that is not configured.
+var ErrUnknownNotifier = errors.New("no such notifier")
+
+// Has reports whether a notifier called name is configured.
+func (d *Dispatcher) Has(name string) bool {
+	return d.find(name) != nil
+}
+
+func (d *Dispatcher) find(name string) *notifier {
+	if d == nil {
+		return nil
+	}
+	for _, n := range d.notifiers {
+		if n.name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the configured notifiers, sorted.
+func (d *Dispatcher) Names() []string {
+	if d == nil {
+		return nil
+	}
+	names := make([]string, len(d.notifiers))
+	for i, n := range d.notifiers {
+		names[i] = n.name
+	}
+	return names
+}
+
+func (d *Dispatcher) send(ctx context.Context, n *notifier, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	return n.sender.send(ctx, event)
+}
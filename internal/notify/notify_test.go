@@ -0,0 +1,219 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/config"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// recorder is an HTTP endpoint that keeps the requests it receives.
+type recorder struct {
+	mu     sync.Mutex
+	bodies []string
+	header http.Header
+}
+
+func newRecorder(t *testing.T) (*recorder, string) {
+	t.Helper()
+	r := &recorder{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.bodies = append(r.bodies, string(body))
+		r.header = req.Header
+	}))
+	t.Cleanup(ts.Close)
+	return r, ts.URL
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies
+}
+
+func TestWebhook_DefaultBody(t *testing.T) {
+	rec, url := newRecorder(t)
+	d, err := New(map[string]config.NotifierConfig{
+		"hook": {Type: "webhook", URL: url, Headers: map[string]string{"Authorization": "Bearer secret"}},
+	}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := SampleEvent()
+	if err := d.Send(context.Background(), "hook", event); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	var got Event
+	if err := json.Unmarshal([]byte(rec.received()[0]), &got); err != nil {
+		t.Fatalf("body is not an event: %v", err)
+	}
+	if got.JobID != event.JobID || got.Bytes != event.Bytes || got.FailedCount != 1 {
+		t.Errorf("unexpected event: %+v", got)
+	}
+	if rec.header.Get("Authorization") != "Bearer secret" || rec.header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected headers: %v", rec.header)
+	}
+}
+
+func TestWebhook_Template(t *testing.T) {
+	rec, url := newRecorder(t)
+	d, err := New(map[string]config.NotifierConfig{
+		"hook": {Type: "webhook", URL: url, Template: `{"summary": {{json .Error}}, "size": "{{bytes .Bytes}}"}`},
+	}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Send(context.Background(), "hook", SampleEvent()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	want := `{"summary": "1 of 43 objects failed to copy", "size": "3.0 GB"}`
+	if got := rec.received()[0]; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
+
+func TestSlack_DefaultText(t *testing.T) {
+	rec, url := newRecorder(t)
+	d, err := New(map[string]config.NotifierConfig{"ops": {Type: "slack", URL: url}}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := SampleEvent()
+	failures := make([]Failure, 12)
+	for i := range failures {
+		failures[i] = Failure{Key: "exports/file.csv", Error: "access denied"}
+	}
+	event.SetFailures(failures)
+	if err := d.Send(context.Background(), "ops", event); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	var msg struct{ Text string }
+	if err := json.Unmarshal([]byte(rec.received()[0]), &msg); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Sync job notification-test failed after 2m5s: gcp:data/exports/ -> aws:data-replica",
+		"Copied 42 objects (3.0 GB), skipped 7",
+		"Error: 1 of 43 objects failed to copy",
+		"exports/file.csv: access denied",
+		"... and 2 more",
+	} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("message missing %q:\n%s", want, msg.Text)
+		}
+	}
+}
+
+func TestSend_Rejected(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	t.Cleanup(ts.Close)
+	d, err := New(map[string]config.NotifierConfig{"ops": {Type: "slack", URL: ts.URL}}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Send(context.Background(), "ops", SampleEvent())
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("expected the rejection in the error, got %v", err)
+	}
+	if err := d.Send(context.Background(), "missing", SampleEvent()); !errors.Is(err, ErrUnknownNotifier) {
+		t.Errorf("expected ErrUnknownNotifier, got %v", err)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for name, cfg := range map[string]config.NotifierConfig{
+		"template": {Type: "slack", URL: "https://example.com", Template: "{{.Status"},
+		"no type":  {URL: "https://example.com"},
+		"no url":   {Type: "webhook"},
+		"no to":    {Type: "email", SMTPAddress: "smtp.example.com:587", From: "synkronus@example.com"},
+	} {
+		_, err := New(map[string]config.NotifierConfig{"ops": cfg}, discard)
+		if err == nil || !strings.Contains(err.Error(), `notifier "ops"`) {
+			t.Errorf("%s: expected an error naming the notifier, got %v", name, err)
+		}
+	}
+}
+
+func TestNotify_Filters(t *testing.T) {
+	rec, url := newRecorder(t)
+	d, err := New(map[string]config.NotifierConfig{
+		"failures": {Type: "webhook", URL: url + "/failures", On: []string{"failure"}, Template: "failures"},
+		"nightly":  {Type: "webhook", URL: url + "/nightly", Jobs: []string{"nightly"}, Template: "nightly"},
+		"all":      {Type: "webhook", URL: url + "/all", Template: "all"},
+	}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		event Event
+		want  []string
+	}{
+		{"api success", Event{JobID: "1", Status: StatusSucceeded}, []string{"all"}},
+		{"api failure", Event{JobID: "2", Status: StatusFailed}, []string{"all", "failures"}},
+		{"nightly success", Event{JobID: "3", Job: "nightly", Status: StatusSucceeded}, []string{"all", "nightly"}},
+	} {
+		rec.mu.Lock()
+		rec.bodies = nil
+		rec.mu.Unlock()
+		d.Notify(context.Background(), tc.event)
+
+		got := rec.received()
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: notified %v, want %v", tc.name, got, tc.want)
+			continue
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(strings.Join(got, " "), want) {
+				t.Errorf("%s: notified %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	}
+
+	var nilDispatcher *Dispatcher
+	nilDispatcher.Notify(context.Background(), SampleEvent())
+}
+
+func TestEmail_Message(t *testing.T) {
+	e, err := newEmail(config.NotifierConfig{
+		Type:        "email",
+		SMTPAddress: "smtp.example.com:587",
+		From:        "synkronus@example.com",
+		To:          []string{"oncall@example.com", "dev@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := string(e.message("Sync job\r\nBcc: everyone@example.com", "line one\nline two", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	for _, want := range []string{
+		"To: oncall@example.com, dev@example.com\r\n",
+		"Subject: Sync job Bcc: everyone@example.com\r\n",
+		"Date: Tue, 02 Jan 2024 03:04:05 +0000\r\n",
+		"\r\n\r\nline one\r\nline two\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+}
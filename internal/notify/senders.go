@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// httpClient posts webhooks and Slack messages. Built on the default
+// transport, it uses the proxy and CA bundle of the network section.
+var httpClient = &http.Client{}
+
+// webhook POSTs the event as JSON, or the rendered template if one is set.
+type webhook struct {
+	url     string
+	headers map[string]string
+	body    *template.Template
+}
+
+func newWebhook(cfg config.NotifierConfig) (*webhook, error) {
+	w := &webhook{url: cfg.URL, headers: cfg.Headers}
+	if cfg.Template != "" {
+		var err error
+		if w.body, err = parseTemplate("webhook template", cfg.Template, ""); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *webhook) send(ctx context.Context, event Event) error {
+	var body []byte
+	if w.body == nil {
+		var err error
+		if body, err = json.Marshal(event); err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	} else {
+		rendered, err := render(w.body, event)
+		if err != nil {
+			return err
+		}
+		body = []byte(rendered)
+	}
+	return post(ctx, w.url, w.headers, body)
+}
+
+// slack posts the rendered template as the text of a Slack incoming webhook message.
+type slack struct {
+	url  string
+	text *template.Template
+}
+
+func newSlack(cfg config.NotifierConfig) (*slack, error) {
+	text, err := parseTemplate("slack template", cfg.Template, defaultText)
+	if err != nil {
+		return nil, err
+	}
+	return &slack{url: cfg.URL, text: text}, nil
+}
+
+func (s *slack) send(ctx context.Context, event Event) error {
+	text, err := render(s.text, event)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	return post(ctx, s.url, nil, body)
+}
+
+// post sends body as JSON to url, failing on a non-2xx response.
+func post(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "synkronus")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification rejected with %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// email sends the rendered templates as a plain-text email over SMTP,
+// upgrading to TLS when the server supports STARTTLS.
+type email struct {
+	address  string
+	username string
+	password string
+	from     string
+	to       []string
+	subject  *template.Template
+	body     *template.Template
+}
+
+func newEmail(cfg config.NotifierConfig) (*email, error) {
+	subject, err := parseTemplate("email subject", cfg.Subject, defaultSubject)
+	if err != nil {
+		return nil, err
+	}
+	body, err := parseTemplate("email template", cfg.Template, defaultText)
+	if err != nil {
+		return nil, err
+	}
+	return &email{
+		address:  cfg.SMTPAddress,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+		from:     cfg.From,
+		to:       cfg.To,
+		subject:  subject,
+		body:     body,
+	}, nil
+}
+
+func (e *email) send(ctx context.Context, event Event) error {
+	subject, err := render(e.subject, event)
+	if err != nil {
+		return err
+	}
+	body, err := render(e.body, event)
+	if err != nil {
+		return err
+	}
+	msg := e.message(subject, body, time.Now())
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", e.address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(e.address)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to %s: %w", e.address, err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("failed to start TLS with %s: %w", e.address, err)
+		}
+	}
+	if e.username != "" {
+		// PlainAuth refuses to send the password without TLS, except to localhost
+		if err := client.Auth(smtp.PlainAuth("", e.username, e.password, host)); err != nil {
+			return fmt.Errorf("failed to authenticate with %s: %w", e.address, err)
+		}
+	}
+	if err := client.Mail(e.from); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	for _, to := range e.to {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("failed to send email to %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return client.Quit()
+}
+
+// message returns the email with its headers. Line breaks in the subject are
+// replaced so a rendered value cannot add headers.
+func (e *email) message(subject, body string, date time.Time) []byte {
+	subject = strings.Join(strings.Fields(subject), " ")
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
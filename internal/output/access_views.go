@@ -0,0 +1,37 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"strings"
+)
+
+// AccessReportView renders the buckets a principal can access as a table
+// with one row per grant.
+type AccessReportView inventory.AccessReport
+
+// RenderTable returns the buckets and the grants giving access to them as an
+// ASCII table, followed by a summary.
+func (v AccessReportView) RenderTable() string {
+	if len(v.Access) == 0 {
+		return fmt.Sprintf("%s has no access to any of %d buckets through bucket policies or ACLs.\n", v.Member, v.Buckets)
+	}
+
+	var sb strings.Builder
+	table := NewTable([]string{"PROVIDER", "BUCKET", "ACCESS", "GRANTED BY"})
+	counts := make(map[string]int)
+	for _, a := range v.Access {
+		counts[a.Level]++
+		access := strings.Join(a.Levels, ", ")
+		for _, g := range a.Grants {
+			table.AddRow([]string{a.Provider, a.Bucket, access, g})
+			a.Provider, a.Bucket, access = "", "", ""
+		}
+	}
+	sb.WriteString(table.String())
+	sb.WriteString("\n")
+
+	fmt.Fprintf(&sb, "\n%s can access %d of %d buckets: %d admin, %d write, %d read only\n",
+		v.Member, len(v.Access), v.Buckets, counts[inventory.AccessAdmin], counts[inventory.AccessWrite], counts[inventory.AccessRead])
+	return sb.String()
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/inventory"
+)
+
+func TestAccessReportView(t *testing.T) {
+	view := AccessReportView{
+		Member: "user:alice@example.com",
+		Access: []inventory.BucketAccess{
+			{Provider: "gcp", Bucket: "public", Level: inventory.AccessRead, Levels: []string{"read"}, Grants: []string{"IAM roles/storage.objectViewer to allUsers"}},
+			{Provider: "gcp", Bucket: "team", Level: inventory.AccessAdmin, Levels: []string{"read", "write", "admin"}, Grants: []string{"IAM roles/storage.objectCreator to domain:example.com", "ACL OWNER to user-alice@example.com"}},
+		},
+		Buckets: 5,
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"allUsers", "read, write, admin", "ACL OWNER", "can access 2 of 5 buckets: 1 admin, 0 write, 1 read only"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out = AccessReportView{Member: "user:bob@example.com", Buckets: 5}.RenderTable()
+	if !strings.Contains(out, "no access to any of 5 buckets") {
+		t.Errorf("expected no access to be reported:\n%s", out)
+	}
+}
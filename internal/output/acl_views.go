@@ -0,0 +1,60 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/acl"
+	"strings"
+)
+
+// ACLAnalysisView renders a bucket's ACL mapped to IAM as a table with one
+// row per ACL entry, followed by the bindings its IAM policy lacks.
+type ACLAnalysisView acl.Analysis
+
+// RenderTable returns the ACL entries and missing bindings as ASCII tables.
+func (v ACLAnalysisView) RenderTable() string {
+	var sb strings.Builder
+
+	sb.WriteString(FormatSectionTitle(fmt.Sprintf("ACL of %s", v.Bucket)))
+	sb.WriteString("\n")
+	if len(v.Grants) == 0 {
+		if v.Uniform {
+			sb.WriteString("None; Uniform Bucket-Level Access is already enabled.\n")
+		} else {
+			sb.WriteString("None.\n")
+		}
+	} else {
+		table := NewTable([]string{"ENTITY", "ACL ROLE", "IAM ROLE", "MEMBER", "STATUS", "NOTE"})
+		unmapped := 0
+		for _, g := range v.Grants {
+			status := "missing"
+			switch {
+			case g.Role == "":
+				status = "unmapped"
+				unmapped++
+			case g.Granted:
+				status = "granted"
+			}
+			table.AddRow([]string{g.Entity, g.ACLRole, g.Role, g.Member, status, g.Note})
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+		if unmapped > 0 {
+			fmt.Fprintf(&sb, "\n%d entries have no IAM equivalent and are lost when Uniform Bucket-Level Access is enabled\n", unmapped)
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(FormatSectionTitle("Bindings to Add"))
+	sb.WriteString("\n")
+	if len(v.Missing) == 0 {
+		sb.WriteString("None; the IAM policy already grants the access the ACL grants.\n")
+		return sb.String()
+	}
+	table := NewTable([]string{"ROLE", "MEMBERS"})
+	for _, b := range v.Missing {
+		table.AddRow([]string{b.Role, strings.Join(b.Members, ", ")})
+	}
+	sb.WriteString(table.String())
+	sb.WriteString("\n")
+	return sb.String()
+}
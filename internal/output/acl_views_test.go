@@ -0,0 +1,31 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/acl"
+)
+
+func TestACLAnalysisView(t *testing.T) {
+	view := ACLAnalysisView{
+		Bucket: "legacy",
+		Grants: []acl.Grant{
+			{Entity: "allUsers", ACLRole: "READER", Role: "roles/storage.legacyBucketReader", Member: "allUsers", Note: "public access"},
+			{Entity: "project-owners-1", ACLRole: "OWNER", Role: "roles/storage.legacyBucketOwner", Member: "projectOwner:1", Granted: true},
+			{Entity: "id-0123", ACLRole: "READER", Note: "no IAM equivalent"},
+		},
+		Missing: []acl.Binding{{Role: "roles/storage.legacyBucketReader", Members: []string{"allUsers", "group:g@example.com"}}},
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"public access", "granted", "unmapped", "missing", "allUsers, group:g@example.com", "1 entries have no IAM equivalent"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out = ACLAnalysisView{Bucket: "modern", Uniform: true}.RenderTable()
+	if !strings.Contains(out, "already enabled") {
+		t.Errorf("expected Uniform Bucket-Level Access to be mentioned:\n%s", out)
+	}
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/audit"
+	"time"
+)
+
+// AuditLogView renders audit log entries as a table, oldest first.
+type AuditLogView []audit.Entry
+
+// RenderTable returns the entries as an ASCII table. Failed operations show
+// their error in the RESULT column.
+func (v AuditLogView) RenderTable() string {
+	table := NewTable([]string{"TIME", "USER", "PROFILE", "OPERATION", "PROVIDER", "RESOURCE", "RESULT"})
+	for _, e := range v {
+		result := e.Result
+		if e.Error != "" {
+			result += ": " + e.Error
+		}
+		table.AddRow([]string{
+			e.Time.Local().Format(time.DateTime),
+			e.User,
+			e.Profile,
+			e.Operation,
+			e.Provider,
+			e.Resource,
+			result,
+		})
+	}
+	return table.String()
+}
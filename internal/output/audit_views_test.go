@@ -0,0 +1,22 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/audit"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLogView_RenderTable(t *testing.T) {
+	view := AuditLogView{
+		{Time: time.Now(), User: "alice", Operation: "delete-bucket", Provider: "gcp", Resource: "logs", Result: audit.ResultSuccess},
+		{Time: time.Now(), User: "bob", Profile: "prod", Operation: "upload-object", Provider: "aws", Resource: "data/a.csv", Result: audit.ResultFailure, Error: "access denied"},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"OPERATION", "alice", "delete-bucket", "prod", "data/a.csv", "failure: access denied"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/batch"
+	"strconv"
+	"strings"
+)
+
+// BatchView renders the outcome of a manifest-driven batch as a table with
+// one row per manifest line, followed by the totals.
+type BatchView batch.Report
+
+// RenderTable returns the batch results as an ASCII table.
+func (v BatchView) RenderTable() string {
+	table := NewTable([]string{"LINE", "OPERATION", "PROVIDER", "TARGET", "STATUS", "ERROR"})
+	for _, r := range v.Results {
+		table.AddRow([]string{
+			strconv.Itoa(r.Line),
+			string(r.Operation),
+			strings.ToLower(r.Provider),
+			r.Target,
+			string(r.Status),
+			r.Error,
+		})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	fmt.Fprintf(&sb, "\n%d succeeded, %d failed, %d not run\n", v.Succeeded, v.Failed, v.NotRun)
+	return sb.String()
+}
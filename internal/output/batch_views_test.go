@@ -0,0 +1,25 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/batch"
+	"strings"
+	"testing"
+)
+
+func TestBatchView_RenderTable(t *testing.T) {
+	view := BatchView{
+		Results: []batch.Result{
+			{Line: 1, Operation: batch.OpCopy, Provider: "GCP", Target: "a/x -> b/x", Status: batch.StatusSucceeded},
+			{Line: 2, Operation: batch.OpDelete, Provider: "aws", Target: "a/y", Status: batch.StatusFailed, Error: "access denied"},
+		},
+		Succeeded: 1,
+		Failed:    1,
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"OPERATION", "a/x -> b/x", "gcp", "succeeded", "access denied", "1 succeeded, 1 failed, 0 not run"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/benchmark"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"strings"
+)
+
+// BenchmarkView renders a benchmark report as a table with one row per
+// provider and operation, followed by any objects that could not be deleted.
+type BenchmarkView benchmark.Report
+
+// RenderTable returns the benchmark results as an ASCII table.
+func (v BenchmarkView) RenderTable() string {
+	table := NewTable([]string{"PROVIDER", "OPERATION", "OBJECTS", "ERRORS", "THROUGHPUT", "P50", "P90", "P99"})
+	for _, r := range v.Results {
+		table.AddRow([]string{
+			r.Provider,
+			r.Operation,
+			fmt.Sprintf("%d", r.Objects),
+			fmt.Sprintf("%d (%.0f%%)", r.Errors, r.ErrorRate*100),
+			storage.FormatBytes(int64(r.Throughput)) + "/s",
+			formatMillis(r.LatencyP50),
+			formatMillis(r.LatencyP90),
+			formatMillis(r.LatencyP99),
+		})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	if len(v.Leftover) > 0 {
+		sb.WriteString("\nThese benchmark objects could not be deleted:\n")
+		for _, key := range v.Leftover {
+			fmt.Fprintf(&sb, "  %s\n", key)
+		}
+	}
+	return sb.String()
+}
+
+func formatMillis(ms float64) string {
+	return fmt.Sprintf("%.1f ms", ms)
+}
+
+// ListBenchmarkView renders the result of a listing benchmark as a table.
+type ListBenchmarkView benchmark.ListResult
+
+// RenderTable returns the listing benchmark result as an ASCII table.
+func (v ListBenchmarkView) RenderTable() string {
+	table := NewTable([]string{"PROVIDER", "BUCKET", "OBJECTS", "PAGES", "DURATION", "FIRST OBJECT", "OBJECTS/S", "PAGES/S", "PEAK HEAP", "ALLOCATED"})
+	table.AddRow([]string{
+		v.Provider,
+		v.Bucket,
+		fmt.Sprintf("%d", v.Objects),
+		fmt.Sprintf("%d", v.Pages),
+		fmt.Sprintf("%.2fs", v.Seconds),
+		formatMillis(v.FirstObjectMillis),
+		fmt.Sprintf("%.0f", v.ObjectsPerSecond),
+		fmt.Sprintf("%.1f", v.PagesPerSecond),
+		storage.FormatBytes(int64(v.PeakHeapBytes)),
+		storage.FormatBytes(int64(v.AllocatedBytes)),
+	})
+	return table.String()
+}
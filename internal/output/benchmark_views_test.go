@@ -0,0 +1,34 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/benchmark"
+	"strings"
+	"testing"
+)
+
+func TestBenchmarkView_RenderTable(t *testing.T) {
+	view := BenchmarkView{
+		Results: []benchmark.Result{
+			{Provider: "gcp", Operation: benchmark.OperationUpload, Objects: 8, Errors: 2, ErrorRate: 0.25, Throughput: 2 << 20, LatencyP50: 12.5, LatencyP90: 20, LatencyP99: 31.25},
+		},
+		Leftover: []string{"bench/object-00003"},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"THROUGHPUT", "upload", "2 (25%)", "2.0 MB/s", "12.5 ms", "31.2 ms", "could not be deleted", "bench/object-00003"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestListBenchmarkView_RenderTable(t *testing.T) {
+	view := ListBenchmarkView{Provider: "aws", Bucket: "big", Objects: 250000, Pages: 250, Seconds: 12.5, FirstObjectMillis: 85, ObjectsPerSecond: 20000, PagesPerSecond: 20, PeakHeapBytes: 8 << 20}
+
+	out := view.RenderTable()
+	for _, want := range []string{"PAGES/S", "big", "250000", "12.50s", "85.0 ms", "20000", "20.0", "8.0 MB"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
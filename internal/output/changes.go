@@ -0,0 +1,26 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RenderChanges writes a section listing the entries added to and removed from
+// a watched listing since the refresh at since. It notes when nothing changed.
+func RenderChanges(w io.Writer, added, removed []string, since time.Time) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n%s\n", FormatSectionTitle("Changes since "+since.Format(time.TimeOnly)))
+	if len(added) == 0 && len(removed) == 0 {
+		sb.WriteString("  (none)\n")
+	}
+	for _, entry := range added {
+		fmt.Fprintf(&sb, "+ %s\n", entry)
+	}
+	for _, entry := range removed {
+		fmt.Fprintf(&sb, "- %s\n", entry)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderChanges(t *testing.T) {
+	var buf bytes.Buffer
+	since := time.Date(2024, 5, 1, 12, 30, 5, 0, time.UTC)
+	if err := RenderChanges(&buf, []string{"gcp/new-bucket"}, []string{"aws/old-bucket"}, since); err != nil {
+		t.Fatalf("RenderChanges failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Changes since 12:30:05", "+ gcp/new-bucket\n", "- aws/old-bucket\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderChanges_NoChanges(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderChanges(&buf, nil, nil, time.Now()); err != nil {
+		t.Fatalf("RenderChanges failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "(none)") {
+		t.Errorf("expected a note that nothing changed, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cleanup"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"strings"
+)
+
+// CleanupPlanView renders what a cleanup would delete, one row per object
+// version, followed by the space it would reclaim.
+type CleanupPlanView cleanup.Plan
+
+// RenderTable returns the candidates as an ASCII table.
+func (v CleanupPlanView) RenderTable() string {
+	table := NewTable([]string{"KEY", "VERSION", "SIZE", "LAST MODIFIED"})
+	for _, c := range v.Candidates {
+		lastModified := timeNotAvailable
+		if !c.LastModified.IsZero() {
+			lastModified = c.LastModified.Format("2006-01-02 15:04")
+		}
+		table.AddRow([]string{c.Key, c.VersionID, storage.FormatBytes(c.Size), lastModified})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	fmt.Fprintf(&sb, "\n%d to delete from bucket %s (%s), reclaiming %s\n", len(v.Candidates), v.Bucket, v.Provider, storage.FormatBytes(v.Bytes))
+	return sb.String()
+}
+
+// CleanupReportView renders the outcome of a cleanup as a table with one row
+// per object version, followed by the totals.
+type CleanupReportView cleanup.Report
+
+// RenderTable returns the cleanup results as an ASCII table.
+func (v CleanupReportView) RenderTable() string {
+	table := NewTable([]string{"KEY", "VERSION", "STATUS", "ERROR"})
+	for _, r := range v.Results {
+		table.AddRow([]string{r.Key, r.VersionID, string(r.Status), r.Error})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	fmt.Fprintf(&sb, "\n%d deleted (%s reclaimed), %d failed, %d not run\n", v.Deleted, storage.FormatBytes(v.Bytes), v.Failed, v.NotRun)
+	return sb.String()
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/cleanup"
+	"strings"
+	"testing"
+)
+
+func TestCleanupPlanView_RenderTable(t *testing.T) {
+	view := CleanupPlanView{Provider: "aws", Bucket: "data", Bytes: 2048, Candidates: []cleanup.Candidate{
+		{Key: "a.txt", VersionID: "v1", Size: 2048},
+	}}
+	out := view.RenderTable()
+	for _, want := range []string{"a.txt", "v1", "N/A", "1 to delete from bucket data (aws), reclaiming 2.0 KB"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestCleanupReportView_RenderTable(t *testing.T) {
+	view := CleanupReportView{
+		Results: []cleanup.Result{{Key: "a.txt", VersionID: "v1", Status: cleanup.StatusFailed, Error: "access denied"}},
+		Failed:  1,
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"access denied", "0 deleted (0 B reclaimed), 1 failed, 0 not run"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+	"strings"
+)
+
+// ObjectComparisonView renders the difference between two object inventories
+// as a table with one row per differing key, followed by the totals.
+type ObjectComparisonView service.ObjectComparison
+
+// RenderTable returns the differing keys as an ASCII table, or a note that
+// the two locations match.
+func (v ObjectComparisonView) RenderTable() string {
+	var sb strings.Builder
+	if len(v.OnlyInSource)+len(v.OnlyInDestination)+len(v.Mismatched) > 0 {
+		table := NewTable([]string{"STATUS", "KEY", "SOURCE SIZE", "DESTINATION SIZE"})
+		for _, key := range v.OnlyInSource {
+			table.AddRow([]string{"only in source", key, "", ""})
+		}
+		for _, key := range v.OnlyInDestination {
+			table.AddRow([]string{"only in destination", key, "", ""})
+		}
+		for _, m := range v.Mismatched {
+			table.AddRow([]string{m.Reason + " differs", m.Key, storage.FormatBytes(m.SourceSize), storage.FormatBytes(m.DestinationSize)})
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+	} else {
+		fmt.Fprintf(&sb, "%s and %s match.\n", v.Source, v.Destination)
+	}
+	fmt.Fprintf(&sb, "%d matching, %d only in source, %d only in destination, %d different\n",
+		v.Matched, len(v.OnlyInSource), len(v.OnlyInDestination), len(v.Mismatched))
+	if v.SizeOnly > 0 {
+		fmt.Fprintf(&sb, "%d matching objects were compared by size only; the providers report no common checksum.\n", v.SizeOnly)
+	}
+	return sb.String()
+}
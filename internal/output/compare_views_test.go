@@ -0,0 +1,39 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/service"
+	"strings"
+	"testing"
+)
+
+func TestObjectComparisonView_RenderTable(t *testing.T) {
+	view := ObjectComparisonView{
+		Source:            service.SyncTarget{Provider: "gcp", Bucket: "a"},
+		Destination:       service.SyncTarget{Provider: "aws", Bucket: "b"},
+		Matched:           3,
+		SizeOnly:          3,
+		OnlyInSource:      []string{"new.csv"},
+		OnlyInDestination: []string{"stale.csv"},
+		Mismatched:        []service.ObjectMismatch{{Key: "orders.csv", Reason: service.MismatchSize, SourceSize: 2048, DestinationSize: 10}},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"only in source", "new.csv", "only in destination", "stale.csv", "size differs", "2.0 KB",
+		"3 matching, 1 only in source, 1 only in destination, 1 different", "compared by size only"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestObjectComparisonView_Match(t *testing.T) {
+	view := ObjectComparisonView{
+		Source:      service.SyncTarget{Provider: "gcp", Bucket: "a"},
+		Destination: service.SyncTarget{Provider: "gcp", Bucket: "b"},
+		Matched:     2,
+	}
+	out := view.RenderTable()
+	if !strings.Contains(out, "gcp:a/ and gcp:b/ match.") || strings.Contains(out, "STATUS") {
+		t.Errorf("expected a note that the locations match, got:\n%s", out)
+	}
+}
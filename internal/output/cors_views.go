@@ -0,0 +1,44 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/cors"
+	"strings"
+)
+
+// CORSResultView renders the outcome of a CORS preflight: the verdict, then
+// the Access-Control headers the bucket answered with.
+type CORSResultView cors.Result
+
+// RenderTable returns the verdict and the response headers as a table.
+func (v CORSResultView) RenderTable() string {
+	var sb strings.Builder
+	request := v.Method + " from " + v.Origin
+	if len(v.RequestHeaders) > 0 {
+		request += " with " + strings.Join(v.RequestHeaders, ", ")
+	}
+	if v.Allowed {
+		fmt.Fprintf(&sb, "Allowed: %s\n\n", request)
+	} else {
+		fmt.Fprintf(&sb, "Denied: %s (%s)\n\n", request, v.Reason)
+	}
+
+	table := NewTable([]string{"Response", "Value"})
+	for _, row := range [][2]string{
+		{"URL", v.URL},
+		{"Status", fmt.Sprint(v.Status)},
+		{"Allow-Origin", v.AllowOrigin},
+		{"Allow-Methods", v.AllowMethods},
+		{"Allow-Headers", v.AllowHeaders},
+		{"Allow-Credentials", v.AllowCredentials},
+		{"Expose-Headers", v.ExposeHeaders},
+		{"Max-Age", v.MaxAge},
+	} {
+		if row[1] != "" {
+			table.AddRow(row[:])
+		}
+	}
+	sb.WriteString(table.String())
+	sb.WriteString("\n")
+	return sb.String()
+}
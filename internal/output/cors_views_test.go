@@ -0,0 +1,34 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCORSResultView_RenderTable(t *testing.T) {
+	view := CORSResultView{
+		URL:            "https://storage.googleapis.com/uploads",
+		Origin:         "https://app.example.com",
+		Method:         "PUT",
+		RequestHeaders: []string{"Content-Type"},
+		Status:         200,
+		Reason:         "method PUT is not allowed",
+		AllowOrigin:    "https://app.example.com",
+		AllowMethods:   "GET",
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"Denied: PUT from https://app.example.com with Content-Type (method PUT is not allowed)", "Allow-Methods", "GET"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Max-Age") {
+		t.Errorf("expected headers missing from the response left out, got:\n%s", out)
+	}
+
+	view.Allowed = true
+	if out := view.RenderTable(); !strings.HasPrefix(out, "Allowed: PUT") {
+		t.Errorf("expected an allowed verdict, got:\n%s", out)
+	}
+}
@@ -0,0 +1,25 @@
+package output
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// DryRunView describes the change a mutating command would make under
+// --dry-run: the operation, the provider it would be sent to, and its
+// parameters.
+type DryRunView struct {
+	Operation  string            `json:"operation"`
+	Provider   string            `json:"provider"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// RenderTable returns a summary line followed by the parameters, sorted by name.
+func (v DryRunView) RenderTable() string {
+	table := NewTable([]string{"PARAMETER", "VALUE"})
+	for _, name := range slices.Sorted(maps.Keys(v.Parameters)) {
+		table.AddRow([]string{name, v.Parameters[name]})
+	}
+	return fmt.Sprintf("Dry run: would %s on provider %s. No changes were made.\n", v.Operation, v.Provider) + table.String()
+}
@@ -0,0 +1,24 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDryRunView_RenderTable(t *testing.T) {
+	view := DryRunView{
+		Operation:  "create-bucket",
+		Provider:   "gcp",
+		Parameters: map[string]string{"name": "logs", "location": "EU"},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"would create-bucket on provider gcp", "No changes were made", "PARAMETER", "location", "EU", "logs"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "location") > strings.Index(out, "name") {
+		t.Errorf("expected parameters sorted by name, got:\n%s", out)
+	}
+}
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"strings"
+	"time"
+)
+
+// ObjectHistoryView renders the audited changes to an object, newest first.
+type ObjectHistoryView struct {
+	Provider string                 `json:"provider" yaml:"provider"`
+	Bucket   string                 `json:"bucket" yaml:"bucket"`
+	Key      string                 `json:"key" yaml:"key"`
+	Since    time.Time              `json:"since" yaml:"since"`
+	Changes  []storage.ObjectChange `json:"changes" yaml:"changes"`
+}
+
+// RenderTable returns the changes as an ASCII table, one row per call. Failed
+// calls (e.g., denied ones) are listed with their error, since they changed
+// nothing but show who tried.
+func (v ObjectHistoryView) RenderTable() string {
+	since := v.Since.UTC().Format(time.RFC3339)
+	if len(v.Changes) == 0 {
+		return fmt.Sprintf("No changes to %s in bucket %s recorded since %s.\n", v.Key, v.Bucket, since)
+	}
+
+	table := NewTable([]string{"TIME", "CHANGE", "OPERATION", "PRINCIPAL", "SOURCE IP", "RESULT"})
+	for _, c := range v.Changes {
+		when := timeNotAvailable
+		if !c.Time.IsZero() {
+			when = c.Time.Format(time.RFC3339)
+		}
+		table.AddRow([]string{when, string(c.Type), c.Operation, cmp.Or(c.Principal, "-"), cmp.Or(c.SourceIP, "-"), cmp.Or(c.Error, "ok")})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	fmt.Fprintf(&sb, "\nChanges to %s in bucket %s (%s) since %s: %d\n", v.Key, v.Bucket, v.Provider, since, len(v.Changes))
+	return sb.String()
+}
@@ -0,0 +1,34 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObjectHistoryView_RenderTable(t *testing.T) {
+	view := ObjectHistoryView{
+		Provider: "gcp",
+		Bucket:   "logs",
+		Key:      "report.csv",
+		Since:    time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		Changes: []storage.ObjectChange{
+			{Time: time.Date(2026, 9, 14, 8, 15, 30, 0, time.UTC), Type: storage.ChangeDelete, Operation: "storage.objects.delete",
+				Principal: "alice@example.com", SourceIP: "203.0.113.7", Error: "PERMISSION_DENIED"},
+			{Type: storage.ChangeWrite, Operation: "storage.objects.create"},
+		},
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"2026-09-14T08:15:30Z", "alice@example.com", "PERMISSION_DENIED", "ok", "N/A",
+		"Changes to report.csv in bucket logs (gcp) since 2026-09-01T00:00:00Z: 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	view.Changes = nil
+	if out := view.RenderTable(); !strings.Contains(out, "No changes to report.csv in bucket logs recorded since 2026-09-01T00:00:00Z.") {
+		t.Errorf("expected a message without changes, got:\n%s", out)
+	}
+}
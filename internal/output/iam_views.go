@@ -0,0 +1,66 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/iam"
+	"strings"
+)
+
+// IAMDriftView renders how a bucket's live policy differs from its reference
+// as a table with one row per principal added or removed.
+type IAMDriftView iam.Drift
+
+// RenderTable returns the changed bindings and statements as ASCII tables,
+// followed by a summary.
+func (v IAMDriftView) RenderTable() string {
+	if !iam.Drift(v).Drifted() {
+		return fmt.Sprintf("The policy of %s matches the reference.\n", v.Bucket)
+	}
+
+	var sb strings.Builder
+	added, removed := 0, 0
+	if len(v.Added) > 0 || len(v.Removed) > 0 {
+		table := NewTable([]string{"CHANGE", "ROLE", "PRINCIPAL", "CONDITION"})
+		for _, change := range []struct {
+			sign     string
+			bindings []storage.IAMBinding
+			count    *int
+		}{{"+", v.Added, &added}, {"-", v.Removed, &removed}} {
+			for _, b := range change.bindings {
+				condition := ""
+				if b.Condition != nil {
+					condition = b.Condition.Title
+				}
+				for _, p := range b.Principals {
+					table.AddRow([]string{change.sign, b.Role, p, condition})
+					*change.count++
+				}
+			}
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+	}
+
+	if len(v.AddedStatements) > 0 || len(v.RemovedStatements) > 0 {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		table := NewTable([]string{"CHANGE", "EFFECT", "PRINCIPAL(S)", "ACTION(S)", "RESOURCE(S)"})
+		for _, change := range []struct {
+			sign       string
+			statements []storage.PolicyStatement
+		}{{"+", v.AddedStatements}, {"-", v.RemovedStatements}} {
+			for _, s := range change.statements {
+				table.AddRow([]string{change.sign, s.Effect, strings.Join(s.Principals, ", "), strings.Join(s.Actions, ", "), strings.Join(s.Resources, ", ")})
+			}
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+		added += len(v.AddedStatements)
+		removed += len(v.RemovedStatements)
+	}
+
+	fmt.Fprintf(&sb, "\nThe policy of %s has drifted: %d grants added and %d removed since the reference\n", v.Bucket, added, removed)
+	return sb.String()
+}
@@ -0,0 +1,28 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/iam"
+)
+
+func TestIAMDriftView(t *testing.T) {
+	view := IAMDriftView{
+		Bucket:  "data",
+		Added:   []storage.IAMBinding{{Role: "roles/storage.objectViewer", Principals: []string{"allUsers", "user:eve@example.com"}}},
+		Removed: []storage.IAMBinding{{Role: "roles/storage.objectAdmin", Principals: []string{"user:ann@example.com"}, Condition: &storage.IAMCondition{Title: "temporary"}}},
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"allUsers", "user:eve@example.com", "temporary", "2 grants added and 1 removed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out = IAMDriftView(iam.Drift{Bucket: "data"}).RenderTable()
+	if !strings.Contains(out, "matches the reference") {
+		t.Errorf("expected no drift to be reported:\n%s", out)
+	}
+}
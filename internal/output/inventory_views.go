@@ -0,0 +1,111 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/inventory"
+	"strings"
+	"time"
+)
+
+// KMSReportView renders the KMS keys in use as a table with one row per key,
+// followed by the buckets using provider-managed keys.
+type KMSReportView inventory.KMSReport
+
+// RenderTable returns the keys and provider-managed buckets as ASCII tables.
+func (v KMSReportView) RenderTable() string {
+	var sb strings.Builder
+
+	sb.WriteString(FormatSectionTitle("Customer-Managed Keys"))
+	sb.WriteString("\n")
+	if len(v.Keys) == 0 {
+		sb.WriteString("None.\n")
+	} else {
+		table := NewTable([]string{"PROVIDER", "KEY", "BUCKETS"})
+		for _, k := range v.Keys {
+			table.AddRow([]string{k.Provider, k.Key, strings.Join(k.Buckets, ", ")})
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(FormatSectionTitle("Provider-Managed Keys"))
+	sb.WriteString("\n")
+	if len(v.ProviderManaged) == 0 {
+		sb.WriteString("None.\n")
+	} else {
+		table := NewTable([]string{"PROVIDER", "BUCKET", "ENCRYPTION"})
+		for _, b := range v.ProviderManaged {
+			encryption := b.Algorithm
+			if encryption == "" {
+				encryption = "default"
+			}
+			table.AddRow([]string{b.Provider, b.Bucket, encryption})
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "\n%d keys across %d buckets; %d buckets use provider-managed keys\n",
+		len(v.Keys), v.Buckets-len(v.ProviderManaged), len(v.ProviderManaged))
+	return sb.String()
+}
+
+// RetentionReportView renders the buckets that retain their objects as a
+// table with one row per bucket, followed by the buckets that do not.
+type RetentionReportView inventory.RetentionReport
+
+// RenderTable returns the retained and unretained buckets as ASCII tables.
+func (v RetentionReportView) RenderTable() string {
+	var sb strings.Builder
+
+	sb.WriteString(FormatSectionTitle("Retained"))
+	sb.WriteString("\n")
+	locked := 0
+	if len(v.Retained) == 0 {
+		sb.WriteString("None.\n")
+	} else {
+		table := NewTable([]string{"PROVIDER", "BUCKET", "MECHANISM", "PERIOD", "LOCKED", "RETAIN UNTIL"})
+		for _, b := range v.Retained {
+			status := "no (pending)"
+			if b.Locked {
+				status = "yes"
+				locked++
+			}
+			mechanism := b.Mechanism
+			if b.Mode != "" {
+				mechanism += " (" + b.Mode + ")"
+			}
+			table.AddRow([]string{b.Provider, b.Bucket, mechanism, formatPeriod(b.Period), status, b.RetainUntil.Format(time.DateOnly)})
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(FormatSectionTitle("Not Retained"))
+	sb.WriteString("\n")
+	if len(v.Unretained) == 0 {
+		sb.WriteString("None.\n")
+	} else {
+		table := NewTable([]string{"PROVIDER", "BUCKET"})
+		for _, b := range v.Unretained {
+			table.AddRow([]string{b.Provider, b.Bucket})
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "\n%d of %d buckets retain their objects, %d of them locked; RETAIN UNTIL is for objects written %s\n",
+		len(v.Retained), v.Buckets, locked, v.At.Format(time.DateOnly))
+	return sb.String()
+}
+
+// formatPeriod shows a retention period in days when it is a whole number of them.
+func formatPeriod(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d > 0 && d%day == 0 {
+		return fmt.Sprintf("%d days", d/day)
+	}
+	return d.String()
+}
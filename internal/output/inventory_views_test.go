@@ -0,0 +1,42 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/inventory"
+)
+
+func TestKMSReportView(t *testing.T) {
+	view := KMSReportView{
+		Keys:            []inventory.KMSKey{{Provider: "gcp", Key: "projects/p/keyRings/r/cryptoKeys/k", Buckets: []string{"data", "logs"}}},
+		ProviderManaged: []inventory.ProviderManagedBucket{{Provider: "aws", Bucket: "archive", Algorithm: "AES256"}, {Provider: "gcp", Bucket: "web"}},
+		Buckets:         4,
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"cryptoKeys/k", "data, logs", "archive", "AES256", "default", "1 keys across 2 buckets; 2 buckets use provider-managed keys"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRetentionReportView(t *testing.T) {
+	at := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	view := RetentionReportView{
+		Retained: []inventory.RetainedBucket{
+			{Provider: "aws", Bucket: "ledger", Mechanism: inventory.MechanismObjectLock, Mode: "GOVERNANCE", Period: 36 * time.Hour, RetainUntil: at.Add(36 * time.Hour)},
+			{Provider: "gcp", Bucket: "backups", Mechanism: inventory.MechanismRetentionPolicy, Period: 30 * 24 * time.Hour, Locked: true, RetainUntil: at.Add(30 * 24 * time.Hour)},
+		},
+		Unretained: []inventory.UnretainedBucket{{Provider: "gcp", Bucket: "web"}},
+		Buckets:    3,
+		At:         at,
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"object-lock (GOVERNANCE)", "36h0m0s", "no (pending)", "30 days", "2024-04-03", "web", "2 of 3 buckets retain their objects, 1 of them locked"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/server"
+	"strings"
+	"time"
+)
+
+// ScheduledJobsView renders scheduled sync jobs as a table with their next
+// and last runs.
+type ScheduledJobsView []server.ScheduledJob
+
+// RenderTable returns the scheduled jobs as an ASCII table.
+func (v ScheduledJobsView) RenderTable() string {
+	table := NewTable([]string{"NAME", "SCHEDULE", "SOURCE", "DESTINATION", "FILTERS", "NEXT RUN", "LAST RUN"})
+	for _, j := range v {
+		next := "never"
+		if j.NextRun != nil {
+			next = j.NextRun.Local().Format(time.DateTime)
+		}
+		last := "-"
+		if j.LastRun != nil {
+			last = fmt.Sprintf("%s (%s)", j.LastRun.StartedAt.Local().Format(time.DateTime), j.LastRun.Status)
+		}
+		table.AddRow([]string{
+			j.Name,
+			j.Schedule,
+			formatEndpoint(j.Source.Provider, j.Source.Bucket, j.Source.Prefix),
+			formatEndpoint(j.Destination.Provider, j.Destination.Bucket, j.Destination.Prefix),
+			formatFilters(j.Include, j.Exclude),
+			next,
+			last,
+		})
+	}
+	return table.String()
+}
+
+// JobHistoryView renders finished jobs as a table, oldest first.
+type JobHistoryView []server.Job
+
+// RenderTable returns the jobs as an ASCII table. Failed jobs show their
+// error in the STATUS column.
+func (v JobHistoryView) RenderTable() string {
+	table := NewTable([]string{"STARTED", "ID", "SCHEDULE", "SOURCE", "DESTINATION", "STATUS", "COPIED", "SKIPPED", "BYTES", "DURATION"})
+	for _, j := range v {
+		schedule := j.Schedule
+		if schedule == "" {
+			schedule = "-"
+		}
+		var source, destination string
+		if j.Sync != nil {
+			source = j.Sync.Source.String()
+			destination = j.Sync.Destination.String()
+		}
+		status := string(j.Status)
+		if j.Error != "" {
+			status += ": " + j.Error
+		}
+		var copied, skipped, bytes, duration string
+		if j.Result != nil {
+			copied = fmt.Sprintf("%d", j.Result.Copied)
+			skipped = fmt.Sprintf("%d", j.Result.Skipped)
+			bytes = storage.FormatBytes(j.Result.Bytes)
+		}
+		if j.FinishedAt != nil {
+			duration = j.FinishedAt.Sub(j.StartedAt).Round(time.Second).String()
+		}
+		table.AddRow([]string{
+			j.StartedAt.Local().Format(time.DateTime),
+			j.ID,
+			schedule,
+			source,
+			destination,
+			status,
+			copied,
+			skipped,
+			bytes,
+			duration,
+		})
+	}
+	return table.String()
+}
+
+func formatEndpoint(provider, bucket, prefix string) string {
+	return fmt.Sprintf("%s:%s/%s", strings.ToLower(provider), bucket, prefix)
+}
+
+// formatFilters summarizes include and exclude patterns, e.g. "+*.csv -tmp/*".
+func formatFilters(include, exclude []string) string {
+	var parts []string
+	for _, p := range include {
+		parts = append(parts, "+"+p)
+	}
+	for _, p := range exclude {
+		parts = append(parts, "-"+p)
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/server"
+	"github.com/jkleinne/synkronus/internal/service"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduledJobsView_RenderTable(t *testing.T) {
+	next := time.Now().Add(time.Hour)
+	view := ScheduledJobsView{
+		{
+			SyncJob: config.SyncJob{
+				Name:        "nightly",
+				Schedule:    "0 2 * * *",
+				Source:      config.SyncEndpoint{Provider: "gcp", Bucket: "data", Prefix: "exports/"},
+				Destination: config.SyncEndpoint{Provider: "aws", Bucket: "replica"},
+				Exclude:     []string{"*.tmp"},
+			},
+			NextRun: &next,
+			LastRun: &server.Job{StartedAt: time.Now(), Status: server.JobFailed},
+		},
+		{SyncJob: config.SyncJob{Name: "never", Schedule: "0 0 30 2 *"}},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"NEXT RUN", "nightly", "gcp:data/exports/", "aws:replica/", "-*.tmp", "(failed)", "never"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestJobHistoryView_RenderTable(t *testing.T) {
+	started := time.Now().Add(-90 * time.Second)
+	finished := time.Now()
+	view := JobHistoryView{
+		{
+			ID:         "abc123",
+			Status:     server.JobFailed,
+			Schedule:   "nightly",
+			Sync:       &service.SyncOptions{Source: service.SyncTarget{Provider: "gcp", Bucket: "data"}, Destination: service.SyncTarget{Provider: "aws", Bucket: "replica"}},
+			Result:     &service.SyncResult{Copied: 3, Skipped: 7, Bytes: 2048},
+			Error:      "1 of 4 objects failed to sync",
+			StartedAt:  started,
+			FinishedAt: &finished,
+		},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"SCHEDULE", "abc123", "nightly", "gcp:data/", "failed: 1 of 4 objects failed to sync", "2.0 KB", "1m30s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
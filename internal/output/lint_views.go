@@ -0,0 +1,84 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/lint"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// LintReportView renders the rules buckets break as a table with one row per
+// violation.
+type LintReportView lint.Report
+
+// RenderTable returns the violations as an ASCII table, followed by a summary.
+func (v LintReportView) RenderTable() string {
+	if len(v.Violations) == 0 {
+		return fmt.Sprintf("All %d buckets follow the rules.\n", v.Buckets)
+	}
+
+	var sb strings.Builder
+	table := NewTable([]string{"PROVIDER", "BUCKET", "RULE", "VIOLATION"})
+	buckets := make(map[[2]string]bool)
+	for _, violation := range v.Violations {
+		table.AddRow([]string{violation.Provider, violation.Bucket, violation.Rule, violation.Message})
+		buckets[[2]string{violation.Provider, violation.Bucket}] = true
+	}
+	sb.WriteString(table.String())
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "\n%d violations in %d of %d buckets\n", len(v.Violations), len(buckets), v.Buckets)
+	return sb.String()
+}
+
+// LabelPlanView renders the labels to add to buckets, or added, as a table
+// with one row per bucket.
+type LabelPlanView lint.LabelPlan
+
+// RenderTable returns the label fixes as an ASCII table, followed by a summary.
+func (v LabelPlanView) RenderTable() string {
+	if len(v.Fixes) == 0 {
+		return fmt.Sprintf("All %d buckets have the required and default labels.\n", v.Buckets)
+	}
+
+	table := NewTable([]string{"PROVIDER", "BUCKET", "ADD", "MISSING (NO DEFAULT)", "STATUS"})
+	planned, applied, failed, unresolved := 0, 0, 0, 0
+	for _, f := range v.Fixes {
+		status := ""
+		switch {
+		case f.Error != "":
+			status = "failed: " + f.Error
+			failed++
+		case f.Applied:
+			status = "applied"
+			applied++
+		case len(f.Labels) > 0:
+			status = "planned"
+			planned++
+		}
+		if len(f.Missing) > 0 {
+			unresolved++
+		}
+		table.AddRow([]string{f.Provider, f.Bucket, formatLabels(f.Labels), strings.Join(f.Missing, ", "), status})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	sb.WriteString("\n\n")
+	if applied > 0 || failed > 0 {
+		fmt.Fprintf(&sb, "%d buckets labeled, %d failed", applied, failed)
+	} else {
+		fmt.Fprintf(&sb, "%d of %d buckets to label", planned, v.Buckets)
+	}
+	fmt.Fprintf(&sb, "; %d lack required labels without a default\n", unresolved)
+	return sb.String()
+}
+
+// formatLabels renders labels as key=value pairs sorted by key.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return strings.Join(pairs, ", ")
+}
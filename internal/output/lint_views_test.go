@@ -0,0 +1,54 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/lint"
+)
+
+func TestLintReportView(t *testing.T) {
+	view := LintReportView{
+		Violations: []lint.Violation{
+			{Provider: "aws", Bucket: "scratch", Rule: lint.RuleRequiredLabel, Message: `missing label "team"`},
+			{Provider: "aws", Bucket: "scratch", Rule: lint.RuleEncryption, Message: "not encrypted with a customer-managed key"},
+		},
+		Buckets: 4,
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"required-label", `missing label "team"`, "2 violations in 1 of 4 buckets"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out = LintReportView{Buckets: 4}.RenderTable()
+	if !strings.Contains(out, "All 4 buckets follow the rules") {
+		t.Errorf("expected a clean report:\n%s", out)
+	}
+}
+
+func TestLabelPlanView(t *testing.T) {
+	view := LabelPlanView{
+		Fixes: []lint.LabelFix{
+			{Provider: "gcp", Bucket: "bare", Labels: map[string]string{"env": "dev", "cost-center": "shared"}, Missing: []string{"team"}},
+			{Provider: "aws", Bucket: "logs", Labels: map[string]string{"env": "dev"}},
+		},
+		Buckets: 5,
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"cost-center=shared, env=dev", "team", "planned", "2 of 5 buckets to label; 1 lack required labels without a default"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	view.Fixes[0].Applied = true
+	view.Fixes[1].Error = "access denied"
+	out = view.RenderTable()
+	for _, want := range []string{"applied", "failed: access denied", "1 buckets labeled, 1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/metadata"
+	"strconv"
+	"strings"
+)
+
+// MetadataApplyView renders the outcome of applying a metadata file as a
+// table with one row per line, followed by the totals.
+type MetadataApplyView metadata.Report
+
+// RenderTable returns the apply results as an ASCII table.
+func (v MetadataApplyView) RenderTable() string {
+	table := NewTable([]string{"LINE", "PROVIDER", "OBJECT", "STATUS", "ERROR"})
+	for _, r := range v.Results {
+		table.AddRow([]string{
+			strconv.Itoa(r.Line),
+			r.Provider,
+			r.Bucket + "/" + r.Key,
+			string(r.Status),
+			r.Error,
+		})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	fmt.Fprintf(&sb, "\n%d applied, %d failed, %d not run\n", v.Applied, v.Failed, v.NotRun)
+	return sb.String()
+}
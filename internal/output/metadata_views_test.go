@@ -0,0 +1,25 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/metadata"
+	"strings"
+	"testing"
+)
+
+func TestMetadataApplyView_RenderTable(t *testing.T) {
+	view := MetadataApplyView{
+		Results: []metadata.Result{
+			{Line: 1, Provider: "gcp", Bucket: "site", Key: "index.html", Status: metadata.StatusApplied},
+			{Line: 2, Provider: "aws", Bucket: "site", Key: "app.js", Status: metadata.StatusFailed, Error: "access denied"},
+		},
+		Applied: 1,
+		Failed:  1,
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"site/index.html", "applied", "access denied", "1 applied, 1 failed, 0 not run"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+	"strconv"
+	"strings"
+)
+
+// topLevelLabel names the objects directly under the report's prefix
+const topLevelLabel = "(top level)"
+
+// PrefixUsageView renders the usage of a bucket by prefix as a table, largest
+// first, with each prefix's share of the total.
+type PrefixUsageView service.PrefixUsageReport
+
+// RenderTable returns the prefixes as an ASCII table followed by the totals.
+func (v PrefixUsageView) RenderTable() string {
+	table := NewTable([]string{"PREFIX", "OBJECTS", "SIZE", "SHARE"})
+	for _, p := range v.Prefixes {
+		label := p.Prefix
+		if label == v.Prefix {
+			label = topLevelLabel
+		}
+		share := "0.0%"
+		if v.Bytes > 0 {
+			share = fmt.Sprintf("%.1f%%", float64(p.Bytes)*100/float64(v.Bytes))
+		}
+		table.AddRow([]string{label, strconv.FormatInt(p.Objects, 10), storage.FormatBytes(p.Bytes), share})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table.String())
+	fmt.Fprintf(&sb, "\n%d prefixes, %d objects, %s in bucket %s (%s)\n", len(v.Prefixes), v.Objects, storage.FormatBytes(v.Bytes), v.Bucket, v.Provider)
+	return sb.String()
+}
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/service"
+	"strings"
+	"testing"
+)
+
+func TestPrefixUsageView_RenderTable(t *testing.T) {
+	view := PrefixUsageView{
+		Provider: "gcp",
+		Bucket:   "lake",
+		Prefix:   "raw/",
+		Objects:  4,
+		Bytes:    4096,
+		Prefixes: []service.PrefixUsage{
+			{Prefix: "raw/2024/", Objects: 3, Bytes: 3072},
+			{Prefix: "raw/", Objects: 1, Bytes: 1024},
+		},
+	}
+	out := view.RenderTable()
+	for _, want := range []string{"raw/2024/", "75.0%", "(top level)", "25.0%", "2 prefixes, 4 objects, 4.0 KB in bucket lake (gcp)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
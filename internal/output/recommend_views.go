@@ -0,0 +1,75 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/recommend"
+	"strings"
+)
+
+// RecommendationView renders a storage class analysis: the bucket's objects
+// by age, then the suggested changes with their estimated savings.
+type RecommendationView recommend.Report
+
+// RenderTable returns the analysis as sectioned tables.
+func (v RecommendationView) RenderTable() string {
+	var sb strings.Builder
+	sb.WriteString(FormatHeaderSection("Recommendations: " + v.Bucket))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(FormatSectionTitle("Overview"))
+	sb.WriteString("\n")
+	overview := NewTable([]string{"Parameter", "Value"})
+	overview.AddRow([]string{"Provider", v.Provider})
+	if v.Prefix != "" {
+		overview.AddRow([]string{"Prefix", v.Prefix})
+	}
+	overview.AddRow([]string{"Default Storage Class", v.StorageClass})
+	overview.AddRow([]string{"Objects", fmt.Sprint(v.Objects)})
+	overview.AddRow([]string{"Size", storage.FormatBytes(v.Bytes)})
+	overview.AddRow([]string{"Estimated Cost", formatMonthly(v.MonthlyCost)})
+	if v.PriceRegion != "" {
+		overview.AddRow([]string{"Prices", fmt.Sprintf("%s (pricing data %s)", v.PriceRegion, v.Pricing)})
+	}
+	if v.Access != nil {
+		overview.AddRow([]string{"Requests", fmt.Sprintf("%d in %d days", v.Access.Requests, int(v.Access.Window.Hours()/24))})
+	}
+	sb.WriteString(overview.String())
+	sb.WriteString("\n\n")
+
+	if len(v.Ages) > 0 {
+		sb.WriteString(FormatSectionTitle("Object Age"))
+		sb.WriteString("\n")
+		ages := NewTable([]string{"AGE", "OBJECTS", "SIZE"})
+		for _, band := range v.Ages {
+			ages.AddRow([]string{band.Label, fmt.Sprint(band.Objects), storage.FormatBytes(band.Bytes)})
+		}
+		sb.WriteString(ages.String())
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(FormatSectionTitle("Recommendations"))
+	sb.WriteString("\n")
+	if len(v.Recommendations) == 0 {
+		sb.WriteString("No changes would reduce the storage cost.\n")
+	} else {
+		recs := NewTable([]string{"ACTION", "OBJECTS", "SIZE", "SAVINGS"})
+		for _, r := range v.Recommendations {
+			recs.AddRow([]string{r.Action, fmt.Sprint(r.Objects), storage.FormatBytes(r.Bytes), formatMonthly(r.MonthlySavings)})
+		}
+		sb.WriteString(recs.String())
+		sb.WriteString("\n")
+	}
+
+	if len(v.Notes) > 0 {
+		sb.WriteString("\n")
+		for _, note := range v.Notes {
+			sb.WriteString("Note: " + note + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func formatMonthly(usd float64) string {
+	return fmt.Sprintf("$%.2f/month", usd)
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/recommend"
+	"strings"
+	"testing"
+)
+
+func TestRecommendationView_RenderTable(t *testing.T) {
+	view := RecommendationView{
+		Provider:     "gcp",
+		Bucket:       "archive",
+		StorageClass: "STANDARD",
+		Objects:      2,
+		Bytes:        2 << 30,
+		MonthlyCost:  0.04,
+		Ages:         []recommend.AgeBand{{Label: "< 30 days", Objects: 1, Bytes: 1 << 30}, {Label: "365+ days", MinDays: 365, Objects: 1, Bytes: 1 << 30}},
+		Recommendations: []recommend.Recommendation{
+			{Kind: recommend.KindLifecycle, Action: "Transition objects older than 365 days to ARCHIVE", Objects: 1, Bytes: 1 << 30, MonthlySavings: 0.0188},
+		},
+		Notes:       []string{"Access metrics are unavailable."},
+		Pricing:     "2026-10-01",
+		PriceRegion: "us-central1",
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"Recommendations: archive", "365+ days", "Transition objects older than 365 days to ARCHIVE", "$0.02/month", "us-central1 (pricing data 2026-10-01)", "Note: Access metrics are unavailable."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	view.Recommendations = nil
+	if out := view.RenderTable(); !strings.Contains(out, "No changes would reduce the storage cost.") {
+		t.Errorf("expected a message when there is nothing to recommend, got:\n%s", out)
+	}
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PostPolicyView renders a signed POST policy: where to post, the form
+// fields to send and an example curl command.
+type PostPolicyView storage.PostPolicy
+
+// RenderTable returns the policy as a fields table and a curl example.
+func (v PostPolicyView) RenderTable() string {
+	var sb strings.Builder
+	sb.WriteString(FormatHeaderSection("POST Policy"))
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "URL:     %s\nExpires: %s\n\n", v.URL, v.Expires.Local().Format(time.RFC1123))
+
+	names := slices.Sorted(maps.Keys(v.Fields))
+	sb.WriteString(FormatSectionTitle("Form Fields"))
+	sb.WriteString("\n")
+	table := NewTable([]string{"Name", "Value"})
+	for _, name := range names {
+		table.AddRow([]string{name, v.Fields[name]})
+	}
+	sb.WriteString(table.String())
+	sb.WriteString("\n\n")
+
+	// The file must be the last field of the form
+	sb.WriteString(FormatSectionTitle("Example"))
+	sb.WriteString("\ncurl")
+	for _, name := range names {
+		fmt.Fprintf(&sb, " \\\n  -F '%s=%s'", name, v.Fields[name])
+	}
+	fmt.Fprintf(&sb, " \\\n  -F 'file=@<path>' \\\n  '%s'\n", v.URL)
+	return sb.String()
+}
+
+// SignedURLView renders a signed URL with the headers its request must send
+// and an example curl command.
+type SignedURLView storage.SignedURL
+
+// RenderTable returns the URL, its headers and a curl example.
+func (v SignedURLView) RenderTable() string {
+	var sb strings.Builder
+	sb.WriteString(FormatHeaderSection("Signed URL"))
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "Method:  %s\nExpires: %s\n\n%s\n\n", v.Method, v.Expires.Local().Format(time.RFC1123), v.URL)
+
+	names := slices.Sorted(maps.Keys(v.Headers))
+	if len(names) > 0 {
+		sb.WriteString(FormatSectionTitle("Required Headers"))
+		sb.WriteString("\n")
+		table := NewTable([]string{"Name", "Value"})
+		for _, name := range names {
+			table.AddRow([]string{name, v.Headers[name]})
+		}
+		sb.WriteString(table.String())
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(FormatSectionTitle("Example"))
+	fmt.Fprintf(&sb, "\ncurl -X %s", v.Method)
+	for _, name := range names {
+		fmt.Fprintf(&sb, " \\\n  -H '%s: %s'", name, v.Headers[name])
+	}
+	if v.Method == "PUT" {
+		sb.WriteString(" \\\n  --upload-file <path>")
+	}
+	fmt.Fprintf(&sb, " \\\n  '%s'\n", v.URL)
+	return sb.String()
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostPolicyView_RenderTable(t *testing.T) {
+	view := PostPolicyView{
+		URL:     "https://storage.googleapis.com/uploads/",
+		Fields:  map[string]string{"key": "incoming/${filename}", "policy": "eyJjb25kaXRpb25zIjpbXX0="},
+		Expires: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"https://storage.googleapis.com/uploads/", "incoming/${filename}", "-F 'policy=eyJjb25kaXRpb25zIjpbXX0='", "-F 'file=@<path>'"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "-F 'key=") > strings.Index(out, "-F 'file=") {
+		t.Error("expected the file to be the last field of the example")
+	}
+}
+
+func TestSignedURLView_RenderTable(t *testing.T) {
+	view := SignedURLView{
+		URL:     "https://storage.googleapis.com/uploads/a.json?X-Goog-Signature=abc",
+		Method:  "PUT",
+		Headers: map[string]string{"Content-Type": "application/json"},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"Required Headers", "curl -X PUT", "-H 'Content-Type: application/json'", "--upload-file <path>", "'https://storage.googleapis.com/uploads/a.json?X-Goog-Signature=abc'"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
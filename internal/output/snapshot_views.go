@@ -0,0 +1,45 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/snapshot"
+	"strings"
+	"time"
+)
+
+// SnapshotDiffView renders what changed since a snapshot, one line per added
+// or removed resource and one per changed value of a modified resource.
+type SnapshotDiffView snapshot.Report
+
+// RenderTable returns the changes since the snapshot, noting when there are none.
+func (v SnapshotDiffView) RenderTable() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", FormatSectionTitle("Changes since snapshot of "+v.TakenAt.Local().Format(time.DateTime)))
+	if len(v.Changes) == 0 {
+		sb.WriteString("  (none)\n")
+		return sb.String()
+	}
+	for _, c := range v.Changes {
+		resource := fmt.Sprintf("%s %s/%s", c.Resource, c.Provider, c.Name)
+		switch c.Kind {
+		case snapshot.ChangeAdded:
+			fmt.Fprintf(&sb, "+ %s\n", resource)
+		case snapshot.ChangeRemoved:
+			fmt.Fprintf(&sb, "- %s\n", resource)
+		default:
+			fmt.Fprintf(&sb, "~ %s\n", resource)
+			for _, f := range c.Fields {
+				fmt.Fprintf(&sb, "    %s: %s -> %s\n", f.Field, orNone(f.Before), orNone(f.After))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// orNone shows a value missing on one side of a change
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
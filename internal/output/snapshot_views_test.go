@@ -0,0 +1,36 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/snapshot"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotDiffView_RenderTable(t *testing.T) {
+	view := SnapshotDiffView{
+		TakenAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Changes: []snapshot.Change{
+			{Kind: snapshot.ChangeAdded, Resource: snapshot.ResourceBucket, Provider: "gcp", Name: "new"},
+			{Kind: snapshot.ChangeRemoved, Resource: snapshot.ResourceSqlInstance, Provider: "gcp", Name: "old-db"},
+			{Kind: snapshot.ChangeModified, Resource: snapshot.ResourceBucket, Provider: "aws", Name: "logs", Fields: []snapshot.FieldChange{
+				{Field: "versioning.enabled", Before: "true", After: "false"},
+				{Field: "labels.team", Before: "", After: "data"},
+			}},
+		},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"+ bucket gcp/new", "- sql-instance gcp/old-db", "~ bucket aws/logs", "versioning.enabled: true -> false", "labels.team: (none) -> data"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestSnapshotDiffView_NoChanges(t *testing.T) {
+	out := SnapshotDiffView{}.RenderTable()
+	if !strings.Contains(out, "(none)") {
+		t.Errorf("expected no changes to be noted, got:\n%s", out)
+	}
+}
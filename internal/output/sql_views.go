@@ -5,7 +5,7 @@ import (
 	"strings"
 	"time"
 
-	domainsql "synkronus/internal/domain/sql"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
 )
 
 // InstanceListView renders a slice of SQL instances as an ASCII table.
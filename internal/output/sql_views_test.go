@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"synkronus/internal/domain"
-	domainsql "synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
 )
 
 func TestInstanceListView_RenderTable(t *testing.T) {
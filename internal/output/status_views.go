@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/doctor"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusStyles color a provider's row by its overall status. lipgloss drops the
+// colors when stdout is not a terminal or NO_COLOR is set.
+var statusStyles = map[doctor.Status]lipgloss.Style{
+	doctor.StatusOK:   lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+	doctor.StatusWarn: lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+	doctor.StatusFail: lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+}
+
+// ProviderHealthView renders provider health as a table with one row per
+// provider, followed by the details and fixes of the checks that did not pass.
+type ProviderHealthView []doctor.ProviderHealth
+
+// RenderTable returns the provider health table. Rows are colored by status
+// (green, yellow, red); the colors are applied to whole rows after layout, since
+// column widths are computed from the plain text.
+func (v ProviderHealthView) RenderTable() string {
+	table := NewTable([]string{"PROVIDER", "STATUS", "AUTH", "ENDPOINT", "APIS", "LATENCY"})
+	for _, h := range v {
+		apis := "-"
+		if h.APIs != nil {
+			apis = strings.ToUpper(string(h.APIs.Status))
+		}
+		latency := "-"
+		if h.Endpoint.Status == doctor.StatusOK {
+			latency = fmt.Sprintf("%d ms", h.LatencyMs)
+		}
+		table.AddRow([]string{
+			h.Provider,
+			strings.ToUpper(string(h.Status)),
+			strings.ToUpper(string(h.Auth.Status)),
+			strings.ToUpper(string(h.Endpoint.Status)),
+			apis,
+			latency,
+		})
+	}
+
+	// Data rows follow the top border, header, and header border
+	lines := strings.Split(table.String(), "\n")
+	for i, h := range v {
+		lines[3+i] = statusStyles[h.Status].Render(lines[3+i])
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Join(lines, "\n"))
+	// A blank line separates the table from the details of failed checks
+	if slices.ContainsFunc(v, func(h doctor.ProviderHealth) bool { return h.Status != doctor.StatusOK }) {
+		sb.WriteString("\n\n")
+	}
+	for _, h := range v {
+		checks := []doctor.Result{h.Auth, h.Endpoint}
+		if h.APIs != nil {
+			checks = append(checks, *h.APIs)
+		}
+		for _, r := range checks {
+			if r.Status == doctor.StatusOK {
+				continue
+			}
+			fmt.Fprintf(&sb, "%s: %s\n", r.Check, r.Detail)
+			if r.Fix != "" {
+				fmt.Fprintf(&sb, "  fix: %s\n", r.Fix)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"github.com/jkleinne/synkronus/internal/doctor"
+	"strings"
+	"testing"
+)
+
+func TestProviderHealthView_RenderTable(t *testing.T) {
+	apis := doctor.Result{Check: "gcp apis", Status: doctor.StatusOK}
+	view := ProviderHealthView{
+		{
+			Provider:  "gcp",
+			Status:    doctor.StatusOK,
+			Auth:      doctor.Result{Status: doctor.StatusOK},
+			Endpoint:  doctor.Result{Status: doctor.StatusOK},
+			APIs:      &apis,
+			LatencyMs: 42,
+		},
+		{
+			Provider: "aws",
+			Status:   doctor.StatusFail,
+			Auth:     doctor.Result{Check: "aws credentials", Status: doctor.StatusFail, Detail: "no credentials", Fix: "run 'aws sso login'"},
+			Endpoint: doctor.Result{Status: doctor.StatusOK},
+		},
+	}
+
+	out := view.RenderTable()
+	for _, want := range []string{"PROVIDER", "LATENCY", "42 ms", "FAIL", "aws credentials: no credentials", "fix: run 'aws sso login'"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "gcp apis") {
+		t.Errorf("expected passing checks to be left out of the details, got:\n%s", out)
+	}
+}
@@ -2,12 +2,13 @@ package output
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
 )
 
 const (
@@ -20,23 +21,40 @@ const (
 // BucketListView renders a slice of buckets as an ASCII table.
 type BucketListView []storage.Bucket
 
-// RenderTable returns the bucket list formatted as an ASCII table.
+// RenderTable returns the bucket list formatted as an ASCII table. PROJECT and
+// ACCOUNT columns are added when any bucket carries a project (GCP listings) or
+// an account (multi-account AWS listings).
 func (v BucketListView) RenderTable() string {
-	table := NewTable([]string{"BUCKET NAME", "PROVIDER", "LOCATION", "USAGE", "STORAGE CLASS", "CREATED"})
+	showProject := slices.ContainsFunc(v, func(b storage.Bucket) bool { return b.Project != "" })
+	showAccount := slices.ContainsFunc(v, func(b storage.Bucket) bool { return b.Account != "" })
+
+	headers := []string{"BUCKET NAME", "PROVIDER"}
+	if showProject {
+		headers = append(headers, "PROJECT")
+	}
+	if showAccount {
+		headers = append(headers, "ACCOUNT")
+	}
+	table := NewTable(append(headers, "LOCATION", "USAGE", "STORAGE CLASS", "CREATED"))
 
 	for _, bucket := range v {
 		createdAt := timeNotAvailable
 		if !bucket.CreatedAt.IsZero() {
 			createdAt = bucket.CreatedAt.Format("2006-01-02")
 		}
-		table.AddRow([]string{
-			bucket.Name,
-			string(bucket.Provider),
+		row := []string{bucket.Name, string(bucket.Provider)}
+		if showProject {
+			row = append(row, bucket.Project)
+		}
+		if showAccount {
+			row = append(row, bucket.Account)
+		}
+		table.AddRow(append(row,
 			bucket.Location,
 			storage.FormatBytes(bucket.UsageBytes),
 			bucket.StorageClass,
 			createdAt,
-		})
+		))
 	}
 
 	return table.String()
@@ -398,6 +416,31 @@ func (v ObjectListView) RenderTable() string {
 	return sb.String()
 }
 
+// ObjectMatchView renders the objects found by a key search as an ASCII table.
+// Matches can come from several buckets, so each row names its bucket.
+type ObjectMatchView []storage.Object
+
+// RenderTable returns the matches formatted as an ASCII table, followed by a
+// count and total size.
+func (v ObjectMatchView) RenderTable() string {
+	if len(v) == 0 {
+		return "No matching objects found.\n"
+	}
+
+	table := NewTable([]string{"BUCKET", "KEY", "SIZE", "LAST MODIFIED"})
+	var total int64
+	for _, obj := range v {
+		lastMod := timeNotAvailable
+		if !obj.LastModified.IsZero() {
+			lastMod = obj.LastModified.Format(time.RFC3339)
+		}
+		table.AddRow([]string{obj.Bucket, obj.Key, storage.FormatBytes(obj.Size), lastMod})
+		total += obj.Size
+	}
+
+	return fmt.Sprintf("%s\n%d matching objects, %s total\n", table.String(), len(v), storage.FormatBytes(total))
+}
+
 // ObjectDetailView renders a single object's full detail as an ASCII table.
 type ObjectDetailView struct{ storage.Object }
 
@@ -453,10 +496,21 @@ func (v ObjectDetailView) renderOverview() string {
 	if v.MD5Hash != "" {
 		table.AddRow([]string{"MD5 Hash (Base64)", v.MD5Hash})
 	}
-	if v.Provider == domain.GCP {
-		if v.CRC32C != "" {
-			table.AddRow([]string{"CRC32C (Base64)", v.CRC32C})
+	for _, c := range []struct{ label, value string }{
+		{"CRC32C (Base64)", v.CRC32C},
+		{"CRC32 (Base64)", v.CRC32},
+		{"SHA-256 (Base64)", v.SHA256},
+	} {
+		if c.value != "" {
+			table.AddRow([]string{c.label, c.value})
 		}
+	}
+	if v.ChecksumType == storage.ChecksumTypeComposite {
+		table.AddRow([]string{"Checksum Type", fmt.Sprintf("Composite (%s parts)", storage.FormatBytes(v.PartSize))})
+	} else if v.ChecksumType != "" {
+		table.AddRow([]string{"Checksum Type", "Full object"})
+	}
+	if v.Provider == domain.GCP {
 		table.AddRow([]string{"Generation", fmt.Sprintf("%d", v.Generation)})
 		table.AddRow([]string{"Metageneration", fmt.Sprintf("%d", v.Metageneration)})
 	}
@@ -523,3 +577,17 @@ func (v ObjectDetailView) renderMetadata() string {
 
 	return sb.String()
 }
+
+// ObjectURLView is the URI or URL of an object.
+type ObjectURLView struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	Key      string `json:"key" yaml:"key"`
+	Style    string `json:"style" yaml:"style"`
+	URL      string `json:"url" yaml:"url"`
+}
+
+// RenderTable returns the URL alone, ready to paste.
+func (v ObjectURLView) RenderTable() string {
+	return v.URL + "\n"
+}
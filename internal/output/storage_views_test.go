@@ -5,8 +5,8 @@ import (
 	"testing"
 	"time"
 
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 func TestBucketListView_RenderTable(t *testing.T) {
@@ -35,6 +35,41 @@ func TestBucketListView_RenderTable(t *testing.T) {
 	}
 }
 
+func TestBucketListView_ProjectColumn(t *testing.T) {
+	withoutProject := BucketListView{{Name: "aws-bucket", Provider: domain.AWS}}
+	if strings.Contains(withoutProject.RenderTable(), "PROJECT") {
+		t.Error("PROJECT column should be omitted when no bucket has a project")
+	}
+
+	withProject := BucketListView{
+		{Name: "bucket-a", Provider: domain.GCP, Project: "project-a"},
+		{Name: "bucket-b", Provider: domain.GCP, Project: "project-b"},
+		{Name: "aws-bucket", Provider: domain.AWS},
+	}
+	result := withProject.RenderTable()
+	for _, s := range []string{"PROJECT", "project-a", "project-b", "aws-bucket"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, result)
+		}
+	}
+}
+
+func TestBucketListView_AccountColumn(t *testing.T) {
+	buckets := BucketListView{
+		{Name: "audit-logs", Provider: domain.AWS, Account: "111111111111"},
+		{Name: "backups", Provider: domain.AWS, Account: "222222222222"},
+	}
+	result := buckets.RenderTable()
+	for _, s := range []string{"ACCOUNT", "111111111111", "222222222222"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, result)
+		}
+	}
+	if strings.Contains(result, "PROJECT") {
+		t.Error("PROJECT column should be omitted when no bucket has a project")
+	}
+}
+
 func TestBucketListView_Empty(t *testing.T) {
 	buckets := BucketListView{}
 
@@ -186,6 +221,33 @@ func TestObjectListView_Empty(t *testing.T) {
 	}
 }
 
+func TestObjectMatchView_RenderTable(t *testing.T) {
+	view := ObjectMatchView{
+		{Key: "db/backup-2024.tar", Bucket: "archive", Size: 2048, LastModified: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Key: "backup-2024.sql", Bucket: "exports", Size: 1024},
+	}
+	result := view.RenderTable()
+
+	expectedSubstrings := []string{
+		"BUCKET", "KEY", "SIZE", "LAST MODIFIED",
+		"archive", "db/backup-2024.tar", "2.0 KB", "2024-03-01T00:00:00Z",
+		"exports", "backup-2024.sql", "N/A",
+		"2 matching objects, 3.0 KB total",
+	}
+	for _, s := range expectedSubstrings {
+		if !strings.Contains(result, s) {
+			t.Errorf("expected output to contain %q, got:\n%s", s, result)
+		}
+	}
+}
+
+func TestObjectMatchView_Empty(t *testing.T) {
+	result := ObjectMatchView{}.RenderTable()
+	if !strings.Contains(result, "No matching objects found.") {
+		t.Errorf("expected empty message, got:\n%s", result)
+	}
+}
+
 func TestObjectDetailView_RenderTable(t *testing.T) {
 	object := storage.Object{
 		Key:          "data/report.pdf",
@@ -679,3 +741,10 @@ func TestBucketDetailView_IAMDuplicateRoleConditions(t *testing.T) {
 		t.Errorf("expected second principal in condition annotation, got:\n%s", result)
 	}
 }
+
+func TestObjectURLView_RenderTable(t *testing.T) {
+	view := ObjectURLView{Provider: "gcp", Bucket: "data", Key: "a.txt", Style: "gs", URL: "gs://data/a.txt"}
+	if got := view.RenderTable(); got != "gs://data/a.txt\n" {
+		t.Errorf("expected the URL alone, got %q", got)
+	}
+}
@@ -0,0 +1,272 @@
+package output
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Column widths of the streamed object table. Rows are written as they arrive,
+// so widths can't be fitted to the data; the key comes last since it is the
+// only unbounded column.
+const (
+	streamSizeWidth  = 10
+	streamClassWidth = 20
+	streamTimeWidth  = 25
+)
+
+// ObjectStream writes objects one at a time in the requested format, without
+// holding the listing in memory. JSON and YAML output is a single array, the
+// same shape as rendering the full slice.
+type ObjectStream struct {
+	w           *bufio.Writer
+	format      Format
+	summary     ListSummary
+	showSummary bool
+}
+
+// NewObjectStream returns a stream writing to w in format. Close must be called
+// to complete the output.
+func NewObjectStream(w io.Writer, format Format) (*ObjectStream, error) {
+	switch format {
+	case FormatTable, FormatJSON, FormatYAML:
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+	return &ObjectStream{w: bufio.NewWriter(w), format: format}, nil
+}
+
+// Write outputs obj. Output is flushed in buffered chunks rather than per row.
+func (s *ObjectStream) Write(obj storage.Object) error {
+	var err error
+	switch s.format {
+	case FormatTable:
+		err = s.writeRow(obj)
+	case FormatJSON:
+		err = s.writeJSON(obj)
+	case FormatYAML:
+		err = s.writeYAML(obj)
+	}
+	if err != nil {
+		return err
+	}
+	s.summary.Add(obj.StorageClass, obj.Size)
+	return nil
+}
+
+// ShowSummary makes Close follow the table with the totals by storage class,
+// rather than a single summary line. JSON and YAML output has no summary.
+func (s *ObjectStream) ShowSummary() {
+	s.showSummary = true
+}
+
+// Close completes the output (closing the JSON array, or printing the table's
+// summary) and flushes it.
+func (s *ObjectStream) Close() error {
+	switch s.format {
+	case FormatTable:
+		switch {
+		case s.summary.Count == 0:
+			fmt.Fprintln(s.w, "No objects found.")
+		case s.showSummary:
+			fmt.Fprintf(s.w, "\n%s", s.summary.RenderTable())
+		default:
+			fmt.Fprintf(s.w, "\n%d objects, %s total\n", s.summary.Count, storage.FormatBytes(s.summary.Bytes))
+		}
+	case FormatJSON:
+		if s.summary.Count == 0 {
+			io.WriteString(s.w, "[]\n")
+		} else {
+			io.WriteString(s.w, "\n]\n")
+		}
+	case FormatYAML:
+		if s.summary.Count == 0 {
+			io.WriteString(s.w, "[]\n")
+		}
+	}
+	return s.w.Flush()
+}
+
+func (s *ObjectStream) writeRow(obj storage.Object) error {
+	if s.summary.Count == 0 {
+		if _, err := fmt.Fprintf(s.w, "%-*s %-*s %-*s %s\n", streamSizeWidth, "SIZE", streamClassWidth, "STORAGE CLASS", streamTimeWidth, "LAST MODIFIED", "KEY"); err != nil {
+			return err
+		}
+	}
+
+	lastMod := timeNotAvailable
+	if !obj.LastModified.IsZero() {
+		lastMod = obj.LastModified.Format(time.RFC3339)
+	}
+	_, err := fmt.Fprintf(s.w, "%-*s %-*s %-*s %s\n", streamSizeWidth, storage.FormatBytes(obj.Size), streamClassWidth, obj.StorageClass, streamTimeWidth, lastMod, obj.Key)
+	return err
+}
+
+func (s *ObjectStream) writeJSON(obj storage.Object) error {
+	data, err := json.MarshalIndent(obj, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if s.summary.Count == 0 {
+		sep = "[\n  "
+	}
+	if _, err := io.WriteString(s.w, sep); err != nil {
+		return err
+	}
+	_, err = s.w.Write(data)
+	return err
+}
+
+func (s *ObjectStream) writeYAML(obj storage.Object) error {
+	// A one-element sequence marshals as a "- " list item, so consecutive
+	// items form a single YAML sequence
+	data, err := yaml.Marshal([]storage.Object{obj})
+	if err != nil {
+		return err
+	}
+	_, err = s.w.Write(data)
+	return err
+}
+
+// Column widths of the streamed bucket table. As with objects, the name comes
+// last since it is the only unbounded column.
+const (
+	streamProviderWidth = 8
+	streamOwnerWidth    = 20
+	streamLocationWidth = 16
+	streamUsageWidth    = 10
+	streamCreatedWidth  = 10
+)
+
+// clearLine returns the cursor to the start of the line and erases it
+const clearLine = "\r\x1b[K"
+
+// BucketStream writes a bucket table to a terminal as each provider answers,
+// below which a footer counts the buckets listed and the providers still
+// pending. The footer is redrawn in place, so w must be a terminal; other
+// writers should render a BucketListView once the listing is complete.
+type BucketStream struct {
+	w         io.Writer
+	providers int
+	answered  int
+	count     int
+}
+
+// NewBucketStream returns a stream for a listing of providers providers and
+// draws the initial footer. Close must be called to erase the footer.
+func NewBucketStream(w io.Writer, providers int) *BucketStream {
+	s := &BucketStream{w: w, providers: providers}
+	s.writeFooter()
+	return s
+}
+
+// Add writes the rows of one provider's buckets and updates the footer.
+func (s *BucketStream) Add(buckets []storage.Bucket) error {
+	var sb strings.Builder
+	sb.WriteString(clearLine)
+	for _, bucket := range buckets {
+		if s.count == 0 {
+			fmt.Fprintf(&sb, "%-*s %-*s %-*s %-*s %-*s %s\n", streamProviderWidth, "PROVIDER", streamOwnerWidth, "PROJECT/ACCOUNT", streamLocationWidth, "LOCATION", streamUsageWidth, "USAGE", streamCreatedWidth, "CREATED", "BUCKET NAME")
+		}
+		createdAt := timeNotAvailable
+		if !bucket.CreatedAt.IsZero() {
+			createdAt = bucket.CreatedAt.Format("2006-01-02")
+		}
+		owner := cmp.Or(bucket.Project, bucket.Account, "-")
+		fmt.Fprintf(&sb, "%-*s %-*s %-*s %-*s %-*s %s\n", streamProviderWidth, bucket.Provider, streamOwnerWidth, owner, streamLocationWidth, bucket.Location, streamUsageWidth, storage.FormatBytes(bucket.UsageBytes), streamCreatedWidth, createdAt, bucket.Name)
+		s.count++
+	}
+	s.answered++
+	if _, err := io.WriteString(s.w, sb.String()); err != nil {
+		return err
+	}
+	return s.writeFooter()
+}
+
+// Close replaces the footer with a summary line, or erases it if no bucket
+// was listed.
+func (s *BucketStream) Close() error {
+	if s.count == 0 {
+		_, err := io.WriteString(s.w, clearLine)
+		return err
+	}
+	_, err := fmt.Fprintf(s.w, "%s\n%d buckets from %d of %d providers\n", clearLine, s.count, s.answered, s.providers)
+	return err
+}
+
+func (s *BucketStream) writeFooter() error {
+	_, err := fmt.Fprintf(s.w, "%s%d buckets so far, waiting for %d of %d providers...", clearLine, s.count, s.providers-s.answered, s.providers)
+	return err
+}
+
+// streamEventTypeWidth fits the longest event type, "metadata-updated"
+const streamEventTypeWidth = 16
+
+// EventStream writes object events as they happen, for tailing a bucket.
+// Unlike ObjectStream, the output never ends, so each event is written out on
+// its own: a table row, a line of JSON (JSON Lines) or a YAML document.
+type EventStream struct {
+	w      io.Writer
+	format Format
+	count  int
+}
+
+// NewEventStream returns a stream writing to w in format.
+func NewEventStream(w io.Writer, format Format) (*EventStream, error) {
+	switch format {
+	case FormatTable, FormatJSON, FormatYAML:
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+	return &EventStream{w: w, format: format}, nil
+}
+
+// Write outputs event immediately.
+func (s *EventStream) Write(event storage.ObjectEvent) error {
+	var data []byte
+	var err error
+	switch s.format {
+	case FormatTable:
+		data = s.row(event)
+	case FormatJSON:
+		data, err = json.Marshal(event)
+		data = append(data, '\n')
+	case FormatYAML:
+		data, err = yaml.Marshal(event)
+		data = append([]byte("---\n"), data...)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+func (s *EventStream) row(event storage.ObjectEvent) []byte {
+	var b strings.Builder
+	if s.count == 0 {
+		fmt.Fprintf(&b, "%-*s %-*s %-*s %s\n", streamTimeWidth, "TIME", streamEventTypeWidth, "EVENT", streamSizeWidth, "SIZE", "KEY")
+	}
+	when := timeNotAvailable
+	if !event.Time.IsZero() {
+		when = event.Time.Format(time.RFC3339)
+	}
+	size := "-"
+	if event.Size > 0 {
+		size = storage.FormatBytes(event.Size)
+	}
+	fmt.Fprintf(&b, "%-*s %-*s %-*s %s\n", streamTimeWidth, when, streamEventTypeWidth, event.Type, streamSizeWidth, size, event.Key)
+	return []byte(b.String())
+}
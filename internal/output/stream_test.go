@@ -0,0 +1,228 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"gopkg.in/yaml.v3"
+)
+
+var streamTestObjects = []storage.Object{
+	{Key: "a.txt", Bucket: "b", Size: 1024, StorageClass: "STANDARD"},
+	{Key: "dir/b.txt", Bucket: "b", Size: 2048, StorageClass: "NEARLINE"},
+}
+
+func streamObjects(t *testing.T, format Format, objects []storage.Object) string {
+	t.Helper()
+	var buf bytes.Buffer
+	stream, err := NewObjectStream(&buf, format)
+	if err != nil {
+		t.Fatalf("NewObjectStream failed: %v", err)
+	}
+	for _, obj := range objects {
+		if err := stream.Write(obj); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestObjectStream_Table(t *testing.T) {
+	out := streamObjects(t, FormatTable, streamTestObjects)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if !strings.HasPrefix(lines[0], "SIZE") || !strings.HasSuffix(lines[0], "KEY") {
+		t.Errorf("expected header row first, got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "a.txt") || !strings.HasSuffix(lines[2], "dir/b.txt") {
+		t.Errorf("expected rows in write order, got %q", out)
+	}
+	if !strings.Contains(out, "2 objects, 3.0 KB total") {
+		t.Errorf("expected summary line, got %q", out)
+	}
+}
+
+func TestObjectStream_TableSummary(t *testing.T) {
+	var buf bytes.Buffer
+	stream, err := NewObjectStream(&buf, FormatTable)
+	if err != nil {
+		t.Fatalf("NewObjectStream failed: %v", err)
+	}
+	stream.ShowSummary()
+	for _, obj := range streamTestObjects {
+		if err := stream.Write(obj); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Summary", "NEARLINE", "2.0 KB", "Total: 2, 3.0 KB"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestObjectStream_JSON(t *testing.T) {
+	out := streamObjects(t, FormatJSON, streamTestObjects)
+
+	var decoded []storage.Object
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out)
+	}
+	if len(decoded) != 2 || decoded[0].Key != "a.txt" || decoded[1].Key != "dir/b.txt" {
+		t.Errorf("unexpected decoded objects: %+v", decoded)
+	}
+}
+
+func TestObjectStream_YAML(t *testing.T) {
+	out := streamObjects(t, FormatYAML, streamTestObjects)
+
+	var decoded []storage.Object
+	if err := yaml.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("output is not valid YAML: %v\n%s", err, out)
+	}
+	if len(decoded) != 2 || decoded[1].Size != 2048 {
+		t.Errorf("unexpected decoded objects: %+v", decoded)
+	}
+}
+
+func TestObjectStream_Empty(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatTable, "No objects found.\n"},
+		{FormatJSON, "[]\n"},
+		{FormatYAML, "[]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			if got := streamObjects(t, tt.format, nil); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewObjectStream_UnsupportedFormat(t *testing.T) {
+	if _, err := NewObjectStream(&bytes.Buffer{}, Format("xml")); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestBucketStream(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewBucketStream(&buf, 2)
+	if !strings.Contains(buf.String(), "waiting for 2 of 2 providers") {
+		t.Errorf("expected the initial footer, got %q", buf.String())
+	}
+
+	err := stream.Add([]storage.Bucket{
+		{Name: "logs", Provider: "gcp", Project: "prod", Location: "US", UsageBytes: 2048},
+		{Name: "assets", Provider: "gcp", Project: "prod", Location: "EU", UsageBytes: -1},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "2 buckets so far, waiting for 1 of 2 providers") {
+		t.Errorf("expected the footer to count the first provider, got %q", buf.String())
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Count(out, "BUCKET NAME") != 1 {
+		t.Errorf("expected a single header, got %q", out)
+	}
+	for _, want := range []string{"prod", "2.0 KB", "N/A", "logs\n", "assets\n", "2 buckets from 1 of 2 providers\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got %q", want, out)
+		}
+	}
+}
+
+func TestBucketStream_NoBuckets_ErasesFooter(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewBucketStream(&buf, 1)
+	if err := stream.Add(nil); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if out := buf.String(); !strings.HasSuffix(out, clearLine) || strings.Contains(out, "BUCKET NAME") {
+		t.Errorf("expected only the erased footer, got %q", out)
+	}
+}
+
+var streamTestEvents = []storage.ObjectEvent{
+	{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Type: storage.ObjectCreated, Bucket: "b", Key: "a.txt", Size: 1024},
+	{Time: time.Date(2024, 1, 1, 0, 0, 5, 0, time.UTC), Type: storage.ObjectDeleted, Bucket: "b", Key: "dir/b.txt"},
+}
+
+func streamEvents(t *testing.T, format Format) string {
+	t.Helper()
+	var buf bytes.Buffer
+	stream, err := NewEventStream(&buf, format)
+	if err != nil {
+		t.Fatalf("NewEventStream failed: %v", err)
+	}
+	for _, event := range streamTestEvents {
+		if err := stream.Write(event); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	return buf.String()
+}
+
+func TestEventStream_Table(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(streamEvents(t, FormatTable)), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "TIME") {
+		t.Fatalf("expected a header and two rows, got %q", lines)
+	}
+	if !strings.Contains(lines[1], "2024-01-01T00:00:00Z") || !strings.Contains(lines[1], "created") || !strings.Contains(lines[1], "1.0 KB") {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "deleted") || !strings.Contains(lines[2], " - ") || !strings.HasSuffix(lines[2], "dir/b.txt") {
+		t.Errorf("unexpected row: %q", lines[2])
+	}
+}
+
+func TestEventStream_JSONLines(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(streamEvents(t, FormatJSON)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per event, got %q", lines)
+	}
+	var event storage.ObjectEvent
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil || event.Key != "dir/b.txt" {
+		t.Errorf("unexpected line %q: %v", lines[1], err)
+	}
+}
+
+func TestEventStream_YAMLDocuments(t *testing.T) {
+	decoder := yaml.NewDecoder(strings.NewReader(streamEvents(t, FormatYAML)))
+	var keys []string
+	for {
+		var event storage.ObjectEvent
+		if err := decoder.Decode(&event); err != nil {
+			break
+		}
+		keys = append(keys, event.Key)
+	}
+	if len(keys) != 2 || keys[0] != "a.txt" {
+		t.Errorf("expected one document per event, got %v", keys)
+	}
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"strings"
+)
+
+// ListSummary totals a listing of buckets or objects, overall and by storage class.
+type ListSummary struct {
+	Count int   `json:"count" yaml:"count"`
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+	// UnknownSize counts the entries whose size the provider did not report,
+	// which Bytes leaves out
+	UnknownSize    int          `json:"unknown_size,omitempty" yaml:"unknown_size,omitempty"`
+	ByStorageClass []ClassTotal `json:"by_storage_class" yaml:"by_storage_class"`
+}
+
+// ClassTotal totals the entries of a listing in one storage class.
+type ClassTotal struct {
+	StorageClass string `json:"storage_class" yaml:"storage_class"`
+	Count        int    `json:"count" yaml:"count"`
+	Bytes        int64  `json:"bytes" yaml:"bytes"`
+}
+
+// Add counts an entry of storageClass and size; a negative size is unknown.
+func (s *ListSummary) Add(storageClass string, size int64) {
+	i, found := slices.BinarySearchFunc(s.ByStorageClass, storageClass, func(c ClassTotal, class string) int {
+		return cmp.Compare(c.StorageClass, class)
+	})
+	if !found {
+		s.ByStorageClass = slices.Insert(s.ByStorageClass, i, ClassTotal{StorageClass: storageClass})
+	}
+	s.Count++
+	s.ByStorageClass[i].Count++
+	if size < 0 {
+		s.UnknownSize++
+		return
+	}
+	s.Bytes += size
+	s.ByStorageClass[i].Bytes += size
+}
+
+// SummarizeBuckets totals buckets by their default storage class and usage.
+func SummarizeBuckets(buckets []storage.Bucket) ListSummary {
+	summary := ListSummary{ByStorageClass: []ClassTotal{}}
+	for _, b := range buckets {
+		summary.Add(b.StorageClass, b.UsageBytes)
+	}
+	return summary
+}
+
+// SummarizeObjects totals objects by storage class and size.
+func SummarizeObjects(objects []storage.Object) ListSummary {
+	summary := ListSummary{ByStorageClass: []ClassTotal{}}
+	for _, o := range objects {
+		summary.Add(o.StorageClass, o.Size)
+	}
+	return summary
+}
+
+// RenderTable returns the totals by storage class as an ASCII table,
+// followed by the overall totals.
+func (s ListSummary) RenderTable() string {
+	var sb strings.Builder
+	sb.WriteString(FormatSectionTitle("Summary"))
+	sb.WriteString("\n")
+	table := NewTable([]string{"STORAGE CLASS", "COUNT", "SIZE"})
+	for _, c := range s.ByStorageClass {
+		table.AddRow([]string{cmp.Or(c.StorageClass, "-"), fmt.Sprint(c.Count), storage.FormatBytes(c.Bytes)})
+	}
+	sb.WriteString(table.String())
+	fmt.Fprintf(&sb, "\n\nTotal: %d, %s", s.Count, storage.FormatBytes(s.Bytes))
+	if s.UnknownSize > 0 {
+		fmt.Fprintf(&sb, " (%d without reported usage)", s.UnknownSize)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// BucketListSummaryView renders a bucket listing followed by its summary.
+// Structured output is an object holding both, rather than a bare list.
+type BucketListSummaryView struct {
+	Buckets BucketListView `json:"buckets" yaml:"buckets"`
+	Summary ListSummary    `json:"summary" yaml:"summary"`
+}
+
+// RenderTable returns the bucket table followed by the summary.
+func (v BucketListSummaryView) RenderTable() string {
+	return v.Buckets.RenderTable() + "\n\n" + v.Summary.RenderTable()
+}
+
+// ObjectListSummaryView renders an object listing followed by the summary of
+// its objects; directories are not counted.
+type ObjectListSummaryView struct {
+	ObjectListView `yaml:",inline"`
+	Summary        ListSummary `json:"summary" yaml:"summary"`
+}
+
+// RenderTable returns the object table followed by the summary.
+func (v ObjectListSummaryView) RenderTable() string {
+	return v.ObjectListView.RenderTable() + "\n\n" + v.Summary.RenderTable()
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestSummarizeBuckets(t *testing.T) {
+	summary := SummarizeBuckets([]storage.Bucket{
+		{Name: "a", StorageClass: "STANDARD", UsageBytes: 1024},
+		{Name: "b", StorageClass: "COLDLINE", UsageBytes: 2048},
+		{Name: "c", StorageClass: "STANDARD", UsageBytes: -1},
+	})
+
+	if summary.Count != 3 || summary.Bytes != 3072 || summary.UnknownSize != 1 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+	want := []ClassTotal{
+		{StorageClass: "COLDLINE", Count: 1, Bytes: 2048},
+		{StorageClass: "STANDARD", Count: 2, Bytes: 1024},
+	}
+	if len(summary.ByStorageClass) != len(want) {
+		t.Fatalf("expected %d classes, got %+v", len(want), summary.ByStorageClass)
+	}
+	for i, c := range want {
+		if summary.ByStorageClass[i] != c {
+			t.Errorf("class %d: expected %+v, got %+v", i, c, summary.ByStorageClass[i])
+		}
+	}
+}
+
+func TestListSummary_RenderTable(t *testing.T) {
+	out := SummarizeBuckets([]storage.Bucket{
+		{Name: "a", StorageClass: "STANDARD", UsageBytes: 1024},
+		{Name: "b", UsageBytes: -1},
+	}).RenderTable()
+
+	for _, want := range []string{"Summary", "STANDARD", "1.0 KB", "Total: 2, 1.0 KB", "(1 without reported usage)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestBucketListSummaryView(t *testing.T) {
+	buckets := []storage.Bucket{{Name: "alpha", StorageClass: "STANDARD", UsageBytes: 10}}
+	view := BucketListSummaryView{Buckets: buckets, Summary: SummarizeBuckets(buckets)}
+
+	out := view.RenderTable()
+	if !strings.Contains(out, "alpha") || !strings.Contains(out, "Total: 1, 10 B") {
+		t.Errorf("expected the listing and its summary, got:\n%s", out)
+	}
+
+	data, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var decoded struct {
+		Buckets []storage.Bucket `json:"buckets"`
+		Summary ListSummary      `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(decoded.Buckets) != 1 || decoded.Summary.Count != 1 {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestObjectListSummaryView_JSONKeepsListingFields(t *testing.T) {
+	list := storage.ObjectList{
+		BucketName:     "b",
+		Objects:        []storage.Object{{Key: "a.txt", Size: 5, StorageClass: "STANDARD"}},
+		CommonPrefixes: []string{"dir/"},
+	}
+	view := ObjectListSummaryView{ObjectListView: ObjectListView{list}, Summary: SummarizeObjects(list.Objects)}
+
+	data, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var decoded struct {
+		storage.ObjectList
+		Summary ListSummary `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if decoded.BucketName != "b" || len(decoded.Objects) != 1 || decoded.Summary.Count != 1 {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	out := view.RenderTable()
+	if !strings.Contains(out, "a.txt") || !strings.Contains(out, "Total: 1, 5 B") {
+		t.Errorf("expected the listing and its summary, got:\n%s", out)
+	}
+}
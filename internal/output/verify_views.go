@@ -0,0 +1,33 @@
+package output
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+)
+
+// ObjectVerificationView renders the outcome of verifying an object against
+// local data as a table of the checksums compared.
+type ObjectVerificationView service.ObjectVerification
+
+// RenderTable returns the checksums compared as an ASCII table, followed by
+// whether they match.
+func (v ObjectVerificationView) RenderTable() string {
+	algorithm := v.Algorithm
+	if v.Composite {
+		algorithm = fmt.Sprintf("%s (composite, %s parts)", v.Algorithm, storage.FormatBytes(v.PartSize))
+	}
+
+	table := NewTable([]string{"Parameter", "Value"})
+	table.AddRow([]string{"Object", v.Bucket + "/" + v.Key})
+	table.AddRow([]string{"Provider", v.Provider})
+	table.AddRow([]string{"Algorithm", algorithm})
+	table.AddRow([]string{"Expected", v.Expected})
+	table.AddRow([]string{"Actual", v.Actual})
+
+	result := "\nChecksums match.\n"
+	if !v.Match {
+		result = "\nChecksums DO NOT match.\n"
+	}
+	return table.String() + "\n" + result
+}
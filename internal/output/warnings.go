@@ -0,0 +1,29 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// ProviderWarning is a provider that failed while others succeeded, so its
+// results are missing from the output.
+type ProviderWarning struct {
+	Provider string
+	Message  string
+}
+
+// RenderWarnings writes a Warnings section listing each failed provider. It is
+// meant for stderr, so it is plain text whatever the output format and never
+// mixes into JSON or YAML on stdout. Nothing is written if warnings is empty.
+func RenderWarnings(w io.Writer, warnings []ProviderWarning) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	table := NewTable([]string{"Provider", "Error"})
+	for _, warning := range warnings {
+		table.AddRow([]string{warning.Provider, warning.Message})
+	}
+	_, err := fmt.Fprintf(w, "\n%s\n%s\n", FormatSectionTitle("Warnings"), table.String())
+	return err
+}
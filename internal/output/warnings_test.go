@@ -0,0 +1,38 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderWarnings(&buf, []ProviderWarning{
+		{Provider: "aws", Message: "access denied"},
+		{Provider: "gcp", Message: "quota exceeded"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"-- Warnings --", "aws", "access denied", "gcp", "quota exceeded"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "aws") > strings.Index(out, "gcp") {
+		t.Errorf("expected warnings in the given order, got:\n%s", out)
+	}
+}
+
+func TestRenderWarnings_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderWarnings(&buf, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
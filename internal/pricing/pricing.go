@@ -0,0 +1,200 @@
+// Package pricing holds the list prices cost estimates are made with: storage,
+// operation, retrieval and egress prices per provider, region and storage
+// class. A dataset is bundled with synkronus, so estimates need no network
+// access and are the same from run to run. 'synkronus pricing update'
+// installs a newer dataset in the config directory, which is used instead of
+// the bundled one as long as it is newer.
+package pricing
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed prices.json
+var bundled []byte
+
+// fileName is the dataset installed by an update, in the synkronus config directory
+const fileName = "pricing.json"
+
+// SourceBundled is the Source of the dataset bundled with synkronus
+const SourceBundled = "bundled"
+
+// Dataset is a versioned set of list prices.
+type Dataset struct {
+	// Version is the date the prices were collected, as YYYY-MM-DD
+	Version   string              `json:"version"`
+	Currency  string              `json:"currency"`
+	Providers map[string]Provider `json:"providers"`
+	// Source is where the dataset was loaded from: SourceBundled or a file path
+	Source string `json:"-"`
+}
+
+// Provider is the prices of one provider.
+type Provider struct {
+	// DefaultRegion prices buckets in regions the dataset does not list
+	DefaultRegion string            `json:"default_region"`
+	Regions       map[string]Region `json:"regions"`
+	// TieringFeePer1000Objects is the monthly Autoclass management or
+	// Intelligent-Tiering monitoring fee
+	TieringFeePer1000Objects float64 `json:"tiering_fee_per_1000_objects"`
+}
+
+// Region is the prices of a region (or multi-region location) of a provider.
+type Region struct {
+	StorageClasses          map[string]Class `json:"storage_classes"`
+	InternetEgressPerGiB    float64          `json:"internet_egress_per_gib"`
+	InterRegionEgressPerGiB float64          `json:"inter_region_egress_per_gib"`
+}
+
+// Class is the prices of a storage class. Writes are class A operations on
+// Cloud Storage (PUT, COPY, POST and LIST on S3), reads class B (GET).
+type Class struct {
+	StoragePerGiBMonth float64 `json:"storage_per_gib_month"`
+	WritesPer1000      float64 `json:"writes_per_1000"`
+	ReadsPer1000       float64 `json:"reads_per_1000"`
+	RetrievalPerGiB    float64 `json:"retrieval_per_gib,omitempty"`
+	// MinStorageDays is the minimum storage duration objects are billed for
+	MinStorageDays int `json:"min_storage_days,omitempty"`
+}
+
+// Parse decodes and validates a dataset.
+func Parse(data []byte) (*Dataset, error) {
+	var d Dataset
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("invalid pricing data: %w", err)
+	}
+	if _, err := time.Parse(time.DateOnly, d.Version); err != nil {
+		return nil, fmt.Errorf("invalid pricing data: version %q is not a YYYY-MM-DD date", d.Version)
+	}
+	if len(d.Providers) == 0 {
+		return nil, errors.New("invalid pricing data: no providers")
+	}
+	for name, p := range d.Providers {
+		if _, ok := p.Regions[p.DefaultRegion]; !ok {
+			return nil, fmt.Errorf("invalid pricing data: default region %q of %s has no prices", p.DefaultRegion, name)
+		}
+		for region, r := range p.Regions {
+			if len(r.StorageClasses) == 0 {
+				return nil, fmt.Errorf("invalid pricing data: %s region %s has no storage classes", name, region)
+			}
+		}
+	}
+	return &d, nil
+}
+
+// Bundled returns the dataset bundled with synkronus.
+func Bundled() *Dataset {
+	d, err := Parse(bundled)
+	if err != nil {
+		// The bundled data is checked by the tests
+		panic(err)
+	}
+	d.Source = SourceBundled
+	return d
+}
+
+// Path returns the location of the dataset installed by an update.
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", config.ConfigDirName, fileName), nil
+}
+
+// Load returns the installed dataset if there is one newer than the bundled
+// dataset, and the bundled dataset otherwise.
+func Load() (*Dataset, error) {
+	path, err := Path()
+	if err != nil {
+		return Bundled(), nil
+	}
+	return load(path)
+}
+
+func load(path string) (*Dataset, error) {
+	current := Bundled()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return current, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing data: %w", err)
+	}
+	installed, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w; run 'synkronus pricing update' or delete it", path, err)
+	}
+	if installed.Version <= current.Version {
+		return current, nil
+	}
+	installed.Source = path
+	return installed, nil
+}
+
+// Install writes data, a dataset, to path for Load to use.
+func Install(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), config.ConfigDirPermissions); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	// Written to a temporary file first, so a failed write leaves the
+	// installed dataset as it was
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, config.ConfigFilePermissions); err != nil {
+		return fmt.Errorf("failed to write pricing data: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to install pricing data: %w", err)
+	}
+	return nil
+}
+
+// Region returns the prices of provider's region, and the name of the region
+// they are for: region itself, or the provider's default region if the
+// dataset does not list it. The mock provider is priced as Cloud Storage.
+func (d *Dataset) Region(provider, region string) (Region, string, bool) {
+	p, ok := d.provider(provider)
+	if !ok {
+		return Region{}, "", false
+	}
+	region = strings.ToLower(region)
+	if r, ok := p.Regions[region]; ok {
+		return r, region, true
+	}
+	return p.Regions[p.DefaultRegion], p.DefaultRegion, true
+}
+
+// TieringFee returns provider's monthly fee per 1,000 objects managed by
+// Autoclass or Intelligent-Tiering.
+func (d *Dataset) TieringFee(provider string) float64 {
+	p, _ := d.provider(provider)
+	return p.TieringFeePer1000Objects
+}
+
+func (d *Dataset) provider(name string) (Provider, bool) {
+	name = strings.ToLower(name)
+	if name == "mock" {
+		name = "gcp"
+	}
+	p, ok := d.Providers[name]
+	return p, ok
+}
+
+// Class returns the prices of a storage class of the region.
+func (r Region) Class(class string) (Class, bool) {
+	for name, c := range r.StorageClasses {
+		if strings.EqualFold(name, class) {
+			return c, true
+		}
+	}
+	return Class{}, false
+}
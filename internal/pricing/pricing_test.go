@@ -0,0 +1,132 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// dataset returns a minimal valid dataset of version.
+func dataset(version string) string {
+	return `{"version": "` + version + `", "currency": "USD", "providers": {"gcp": {"default_region": "us-central1",
+		"regions": {"us-central1": {"storage_classes": {"STANDARD": {"storage_per_gib_month": 0.03}}}}}}}`
+}
+
+func TestBundled(t *testing.T) {
+	d := Bundled()
+	if d.Source != SourceBundled || d.Currency != "USD" {
+		t.Errorf("unexpected bundled dataset: version %q, source %q, currency %q", d.Version, d.Source, d.Currency)
+	}
+	for _, provider := range []string{"gcp", "aws"} {
+		if _, ok := d.Providers[provider]; !ok {
+			t.Errorf("expected prices for %s", provider)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":       "{",
+		"bad version":    strings.Replace(dataset("2026-10-01"), "2026-10-01", "latest", 1),
+		"no providers":   `{"version": "2026-10-01", "providers": {}}`,
+		"unknown region": strings.Replace(dataset("2026-10-01"), `"default_region": "us-central1"`, `"default_region": "us-east1"`, 1),
+		"no classes":     `{"version": "2026-10-01", "providers": {"gcp": {"default_region": "r", "regions": {"r": {}}}}}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricing.json")
+
+	if d, err := load(path); err != nil || d.Source != SourceBundled {
+		t.Fatalf("expected the bundled dataset without an installed one, got %+v, %v", d, err)
+	}
+
+	if err := Install(path, []byte(dataset("2000-01-01"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d, err := load(path); err != nil || d.Source != SourceBundled {
+		t.Errorf("expected the bundled dataset over an older installed one, got %+v, %v", d, err)
+	}
+
+	if err := Install(path, []byte(dataset("2999-01-01"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d, err := load(path)
+	if err != nil || d.Source != path || d.Version != "2999-01-01" {
+		t.Errorf("expected the newer installed dataset, got %+v, %v", d, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the installed dataset to be private, got %v, %v", info, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := load(path); err == nil || !strings.Contains(err.Error(), "pricing update") {
+		t.Errorf("expected an invalid installed dataset to be reported, got %v", err)
+	}
+}
+
+func TestRegion(t *testing.T) {
+	d := Bundled()
+
+	r, name, ok := d.Region("aws", "eu-central-1")
+	if !ok || name != "eu-central-1" {
+		t.Fatalf("expected eu-central-1 prices, got %q, %v", name, ok)
+	}
+	if c, ok := r.Class("standard_ia"); !ok || c.StoragePerGiBMonth == 0 {
+		t.Errorf("expected a STANDARD_IA price, got %+v, %v", c, ok)
+	}
+
+	if _, name, _ := d.Region("GCP", "US-CENTRAL1"); name != "us-central1" {
+		t.Errorf("expected regions to be matched regardless of case, got %q", name)
+	}
+	if _, name, _ := d.Region("gcp", "mars-north1"); name != d.Providers["gcp"].DefaultRegion {
+		t.Errorf("expected the default region for an unknown region, got %q", name)
+	}
+	if _, name, ok := d.Region("mock", "EU"); !ok || name != "eu" {
+		t.Errorf("expected the mock provider to be priced as Cloud Storage, got %q, %v", name, ok)
+	}
+	if _, _, ok := d.Region("azure", "westeurope"); ok {
+		t.Error("expected no prices for an unknown provider")
+	}
+	if d.TieringFee("aws") == 0 {
+		t.Error("expected an Intelligent-Tiering monitoring fee")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/prices.json":
+			w.Write([]byte(dataset("2999-01-01")))
+		case "/invalid.json":
+			w.Write([]byte(`{"version": "2999-01-01"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	d, data, err := Fetch(context.Background(), srv.Client(), srv.URL+"/prices.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Version != "2999-01-01" || string(data) != dataset("2999-01-01") {
+		t.Errorf("unexpected dataset %+v", d)
+	}
+	if _, _, err := Fetch(context.Background(), srv.Client(), srv.URL+"/invalid.json"); err == nil {
+		t.Error("expected an invalid dataset to be rejected")
+	}
+	if _, _, err := Fetch(context.Background(), srv.Client(), srv.URL+"/missing.json"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected the HTTP status in the error, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultURL serves the dataset of the latest release: the bundled file on
+// the main branch
+const DefaultURL = "https://raw.githubusercontent.com/jkleinne/synkronus/main/internal/pricing/prices.json"
+
+// maxSize bounds a downloaded dataset
+const maxSize = 10 << 20
+
+// Fetch downloads a dataset from url and validates it, returning the
+// dataset and its encoded form to install.
+func Fetch(ctx context.Context, client *http.Client, url string) (*Dataset, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pricing data URL: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to download pricing data: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to download pricing data from %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to download pricing data: %w", err)
+	}
+	if len(data) > maxSize {
+		return nil, nil, fmt.Errorf("pricing data from %s is larger than %d bytes", url, maxSize)
+	}
+	d, err := Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, data, nil
+}
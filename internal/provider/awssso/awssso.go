@@ -0,0 +1,193 @@
+// Package awssso implements the AWS IAM Identity Center (SSO) device
+// authorization flow and caches the resulting role credentials, so the AWS
+// provider can authenticate without a separately installed aws-cli.
+package awssso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/network"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+const (
+	// clientName identifies synkronus when registering the OIDC client
+	clientName      = "synkronus"
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// defaultPollInterval is used when the service does not specify one
+	defaultPollInterval = 5 * time.Second
+	// slowDownIncrement is added to the interval on each SlowDownException (RFC 8628)
+	slowDownIncrement = 5 * time.Second
+)
+
+// Settings identify the Identity Center role to obtain credentials for.
+type Settings struct {
+	StartURL  string
+	Region    string
+	AccountID string
+	RoleName  string
+}
+
+// SettingsFrom returns the SSO settings of cfg. The start URL, account ID and
+// role name must be set together; the SSO region defaults to aws.region.
+func SettingsFrom(cfg *config.AWSConfig) (Settings, error) {
+	s := Settings{
+		StartURL:  cfg.SSOStartURL,
+		Region:    cfg.SSORegion,
+		AccountID: cfg.SSOAccountID,
+		RoleName:  cfg.SSORoleName,
+	}
+	if s.StartURL == "" || s.AccountID == "" || s.RoleName == "" {
+		return s, fmt.Errorf("aws.sso_start_url, aws.sso_account_id and aws.sso_role_name must be set together")
+	}
+	if s.Region == "" {
+		s.Region = cfg.Region
+	}
+	return s, nil
+}
+
+// OIDCClient is the subset of the SSO OIDC API used by the device flow.
+type OIDCClient interface {
+	RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error)
+	StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error)
+	CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error)
+}
+
+// PortalClient is the subset of the SSO portal API used to fetch role credentials.
+type PortalClient interface {
+	GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error)
+}
+
+// Authorization is what the user needs to approve the login in a browser.
+type Authorization struct {
+	VerificationURI string
+	UserCode        string
+	ExpiresAt       time.Time
+}
+
+// Login drives the device authorization flow.
+type Login struct {
+	oidc   OIDCClient
+	portal PortalClient
+	// sleep waits between token polls; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewLogin creates a Login using the SSO services in the settings' region.
+func NewLogin(ctx context.Context, s Settings) (*Login, error) {
+	sdkCfg, err := awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithRegion(s.Region),
+		// The device flow APIs are unauthenticated; don't resolve ambient credentials
+		awsconfig.WithCredentialsProvider(aws.AnonymousCredentials{}),
+		awsconfig.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(network.ConfigureTransport)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS SDK config: %w", err)
+	}
+	return &Login{
+		oidc:   ssooidc.NewFromConfig(sdkCfg),
+		portal: sso.NewFromConfig(sdkCfg),
+		sleep:  sleepContext,
+	}, nil
+}
+
+// Run registers a client, starts device authorization, passes the code to
+// notify for the user to approve, and waits for approval. It returns the role
+// credentials for the configured account and role.
+func (l *Login) Run(ctx context.Context, s Settings, notify func(Authorization)) (aws.Credentials, error) {
+	client, err := l.oidc.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String(clientName),
+		ClientType: aws.String("public"),
+	})
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to register SSO client: %w", err)
+	}
+
+	auth, err := l.oidc.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     client.ClientId,
+		ClientSecret: client.ClientSecret,
+		StartUrl:     aws.String(s.StartURL),
+	})
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to start SSO device authorization: %w", err)
+	}
+
+	expiresAt := time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	verificationURI := aws.ToString(auth.VerificationUriComplete)
+	if verificationURI == "" {
+		verificationURI = aws.ToString(auth.VerificationUri)
+	}
+	notify(Authorization{VerificationURI: verificationURI, UserCode: aws.ToString(auth.UserCode), ExpiresAt: expiresAt})
+
+	interval := time.Duration(auth.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	var accessToken string
+	for accessToken == "" {
+		if time.Now().After(expiresAt) {
+			return aws.Credentials{}, fmt.Errorf("SSO authorization expired before it was approved")
+		}
+		if err := l.sleep(ctx, interval); err != nil {
+			return aws.Credentials{}, err
+		}
+
+		token, err := l.oidc.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     client.ClientId,
+			ClientSecret: client.ClientSecret,
+			DeviceCode:   auth.DeviceCode,
+			GrantType:    aws.String(deviceGrantType),
+		})
+		var pending *types.AuthorizationPendingException
+		var slowDown *types.SlowDownException
+		switch {
+		case errors.As(err, &pending):
+			continue
+		case errors.As(err, &slowDown):
+			interval += slowDownIncrement
+			continue
+		case err != nil:
+			return aws.Credentials{}, fmt.Errorf("SSO authorization failed: %w", err)
+		}
+		accessToken = aws.ToString(token.AccessToken)
+	}
+
+	out, err := l.portal.GetRoleCredentials(ctx, &sso.GetRoleCredentialsInput{
+		AccessToken: aws.String(accessToken),
+		AccountId:   aws.String(s.AccountID),
+		RoleName:    aws.String(s.RoleName),
+	})
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to get credentials for role %s in account %s: %w", s.RoleName, s.AccountID, err)
+	}
+	rc := out.RoleCredentials
+	return aws.Credentials{
+		AccessKeyID:     aws.ToString(rc.AccessKeyId),
+		SecretAccessKey: aws.ToString(rc.SecretAccessKey),
+		SessionToken:    aws.ToString(rc.SessionToken),
+		Source:          credentialsSource,
+		CanExpire:       true,
+		Expires:         time.UnixMilli(rc.Expiration),
+	}, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package awssso
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+var testSettings = Settings{
+	StartURL:  "https://my.awsapps.com/start",
+	Region:    "eu-west-1",
+	AccountID: "123456789012",
+	RoleName:  "ReadOnly",
+}
+
+// fakeOIDC answers CreateToken with the queued errors before succeeding.
+type fakeOIDC struct {
+	tokenErrs  []error
+	tokenCalls int
+}
+
+func (f *fakeOIDC) RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error) {
+	return &ssooidc.RegisterClientOutput{ClientId: aws.String("id"), ClientSecret: aws.String("secret")}, nil
+}
+
+func (f *fakeOIDC) StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error) {
+	return &ssooidc.StartDeviceAuthorizationOutput{
+		DeviceCode:              aws.String("device"),
+		UserCode:                aws.String("ABCD-EFGH"),
+		VerificationUriComplete: aws.String("https://device.sso.eu-west-1.amazonaws.com/?user_code=ABCD-EFGH"),
+		ExpiresIn:               600,
+		Interval:                1,
+	}, nil
+}
+
+func (f *fakeOIDC) CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+	f.tokenCalls++
+	if len(f.tokenErrs) > 0 {
+		err := f.tokenErrs[0]
+		f.tokenErrs = f.tokenErrs[1:]
+		return nil, err
+	}
+	return &ssooidc.CreateTokenOutput{AccessToken: aws.String("access-token")}, nil
+}
+
+type fakePortal struct {
+	gotToken string
+}
+
+func (f *fakePortal) GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error) {
+	f.gotToken = aws.ToString(params.AccessToken)
+	return &sso.GetRoleCredentialsOutput{RoleCredentials: &ssotypes.RoleCredentials{
+		AccessKeyId:     aws.String("ASIA"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("session"),
+		Expiration:      time.Now().Add(time.Hour).UnixMilli(),
+	}}, nil
+}
+
+func TestLoginRun_PollsUntilApproved(t *testing.T) {
+	oidc := &fakeOIDC{tokenErrs: []error{&types.AuthorizationPendingException{}, &types.SlowDownException{}}}
+	portal := &fakePortal{}
+	var intervals []time.Duration
+	login := &Login{oidc: oidc, portal: portal, sleep: func(ctx context.Context, d time.Duration) error {
+		intervals = append(intervals, d)
+		return nil
+	}}
+
+	var notified Authorization
+	creds, err := login.Run(context.Background(), testSettings, func(a Authorization) { notified = a })
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if notified.UserCode != "ABCD-EFGH" {
+		t.Errorf("expected user code to be shown, got %+v", notified)
+	}
+	if oidc.tokenCalls != 3 {
+		t.Errorf("expected 3 token polls, got %d", oidc.tokenCalls)
+	}
+	if len(intervals) != 3 || intervals[2] != time.Second+slowDownIncrement {
+		t.Errorf("expected the interval to grow after SlowDown, got %v", intervals)
+	}
+	if portal.gotToken != "access-token" {
+		t.Errorf("expected role credentials fetched with the access token, got %q", portal.gotToken)
+	}
+	if creds.AccessKeyID != "ASIA" || !creds.CanExpire {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+}
+
+func TestLoginRun_Denied(t *testing.T) {
+	oidc := &fakeOIDC{tokenErrs: []error{&types.AccessDeniedException{}}}
+	login := &Login{oidc: oidc, portal: &fakePortal{}, sleep: func(context.Context, time.Duration) error { return nil }}
+
+	_, err := login.Run(context.Background(), testSettings, func(Authorization) {})
+	var denied *types.AccessDeniedException
+	if !errors.As(err, &denied) {
+		t.Fatalf("expected AccessDeniedException, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package awssso
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	// cacheDirName is created under the synkronus config directory
+	cacheDirName = "aws-sso"
+	// credentialsSource is reported as aws.Credentials.Source
+	credentialsSource = "SynkronusSSOCache"
+)
+
+// cachedCredentials is the on-disk form of cached role credentials.
+type cachedCredentials struct {
+	AccessKeyID     string    `json:"access_key_id"`
+	SecretAccessKey string    `json:"secret_access_key"`
+	SessionToken    string    `json:"session_token"`
+	Expiration      time.Time `json:"expiration"`
+}
+
+// CachePath returns the cache file for the settings' role. Each start URL,
+// account and role combination gets its own file, so profiles that share a
+// role share a login.
+func CachePath(s Settings) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting user home directory: %w", err)
+	}
+	sum := sha1.Sum([]byte(s.StartURL + "|" + s.AccountID + "|" + s.RoleName))
+	return filepath.Join(homeDir, ".config", config.ConfigDirName, cacheDirName, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// SaveCredentials writes creds to the cache for s, readable only by the owner.
+func SaveCredentials(s Settings, creds aws.Credentials) error {
+	path, err := CachePath(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), config.ConfigDirPermissions); err != nil {
+		return fmt.Errorf("failed to create SSO cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cachedCredentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expiration:      creds.Expires.UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SSO credentials: %w", err)
+	}
+	if err := os.WriteFile(path, data, config.ConfigFilePermissions); err != nil {
+		return fmt.Errorf("failed to write SSO credentials cache: %w", err)
+	}
+	return nil
+}
+
+// NewCredentialsProvider returns a provider that serves the cached credentials
+// for s, failing with a hint to log in again once they are missing or expired.
+func NewCredentialsProvider(s Settings) aws.CredentialsProvider {
+	return cacheProvider{settings: s, now: time.Now}
+}
+
+type cacheProvider struct {
+	settings Settings
+	now      func() time.Time
+}
+
+func (p cacheProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	path, err := CachePath(p.settings)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return aws.Credentials{}, fmt.Errorf("no cached AWS SSO credentials for role %s in account %s; run 'synkronus auth aws-sso'", p.settings.RoleName, p.settings.AccountID)
+	}
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to read SSO credentials cache: %w", err)
+	}
+
+	var cached cachedCredentials
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return aws.Credentials{}, fmt.Errorf("invalid SSO credentials cache %s: %w", path, err)
+	}
+	if !p.now().Before(cached.Expiration) {
+		return aws.Credentials{}, fmt.Errorf("AWS SSO credentials expired at %s; run 'synkronus auth aws-sso'", cached.Expiration.Local().Format(time.RFC3339))
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     cached.AccessKeyID,
+		SecretAccessKey: cached.SecretAccessKey,
+		SessionToken:    cached.SessionToken,
+		Source:          credentialsSource,
+		CanExpire:       true,
+		Expires:         cached.Expiration,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package awssso
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/config"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestSettingsFrom(t *testing.T) {
+	s, err := SettingsFrom(&config.AWSConfig{
+		Region:       "us-east-1",
+		SSOStartURL:  testSettings.StartURL,
+		SSOAccountID: testSettings.AccountID,
+		SSORoleName:  testSettings.RoleName,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Region != "us-east-1" {
+		t.Errorf("expected SSO region to default to aws.region, got %q", s.Region)
+	}
+
+	if _, err := SettingsFrom(&config.AWSConfig{Region: "us-east-1", SSOStartURL: testSettings.StartURL}); err == nil {
+		t.Error("expected error for incomplete SSO settings")
+	}
+}
+
+func TestCredentialsCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	provider := cacheProvider{settings: testSettings, now: time.Now}
+
+	if _, err := provider.Retrieve(context.Background()); err == nil || !strings.Contains(err.Error(), "synkronus auth aws-sso") {
+		t.Fatalf("expected a login hint before any login, got %v", err)
+	}
+
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := SaveCredentials(testSettings, aws.Credentials{AccessKeyID: "ASIA", SecretAccessKey: "secret", SessionToken: "session", Expires: expires}); err != nil {
+		t.Fatalf("SaveCredentials failed: %v", err)
+	}
+
+	path, err := CachePath(testSettings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("cache file not written: %v", err)
+	}
+	if info.Mode().Perm() != config.ConfigFilePermissions {
+		t.Errorf("expected cache mode %o, got %o", config.ConfigFilePermissions, info.Mode().Perm())
+	}
+
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if creds.AccessKeyID != "ASIA" || creds.SessionToken != "session" || !creds.Expires.Equal(expires) {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+
+	expired := cacheProvider{settings: testSettings, now: func() time.Time { return expires.Add(time.Minute) }}
+	if _, err := expired.Retrieve(context.Background()); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expected expired error, got %v", err)
+	}
+}
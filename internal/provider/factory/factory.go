@@ -4,31 +4,32 @@ package factory
 import (
 	"context"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/registry"
 	"log/slog"
 	"slices"
 	"strings"
-	"synkronus/internal/config"
-	"synkronus/internal/domain/sql"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/registry"
+	"sync/atomic"
 )
 
 type Factory struct {
-	cfg    *config.Config
+	// cfg is swapped by UpdateConfig while calls may be creating clients
+	cfg    atomic.Pointer[config.Config]
 	logger *slog.Logger
 }
 
 func NewFactory(cfg *config.Config, logger *slog.Logger) *Factory {
-	return &Factory{
-		cfg:    cfg,
-		logger: logger,
-	}
+	f := &Factory{logger: logger}
+	f.cfg.Store(cfg)
+	return f
 }
 
 // UpdateConfig replaces the factory's configuration, causing subsequent
 // provider lookups to reflect the new state. Used by the TUI after config changes.
 func (f *Factory) UpdateConfig(cfg *config.Config) {
-	f.cfg = cfg
+	f.cfg.Store(cfg)
 }
 
 // SupportedStorageProviders returns all registered storage provider names,
@@ -57,12 +58,12 @@ func (f *Factory) ConfiguredSqlProviders() []string {
 
 // Returns a list of providers that are registered and configured
 func (f *Factory) GetConfiguredProviders() []string {
-	return getConfigured(registry.GetAllRegistrations(), f.cfg)
+	return getConfigured(registry.GetAllRegistrations(), f.cfg.Load())
 }
 
 // Returns a list of SQL providers that are registered and configured
 func (f *Factory) GetConfiguredSqlProviders() []string {
-	return getConfigured(registry.GetAllSqlRegistrations(), f.cfg)
+	return getConfigured(registry.GetAllSqlRegistrations(), f.cfg.Load())
 }
 
 func getConfigured[T any](regs map[string]registry.Registration[T], cfg *config.Config) []string {
@@ -116,20 +117,20 @@ func isProviderConfigured[T any](name string, getRegistration func(string) (regi
 
 // Checks if a specific provider is registered and configured
 func (f *Factory) IsConfigured(providerName string) bool {
-	return isProviderConfigured(providerName, registry.GetRegistration, f.cfg)
+	return isProviderConfigured(providerName, registry.GetRegistration, f.cfg.Load())
 }
 
 // Checks if a specific SQL provider is registered and configured
 func (f *Factory) IsSqlConfigured(providerName string) bool {
-	return isProviderConfigured(providerName, registry.GetSqlRegistration, f.cfg)
+	return isProviderConfigured(providerName, registry.GetSqlRegistration, f.cfg.Load())
 }
 
 // Initializes and returns the storage client for the specified provider
 func (f *Factory) GetStorageProvider(ctx context.Context, name string) (storage.Storage, error) {
-	return getProvider(ctx, name, registry.GetRegistration, registry.GetSupportedProviders, f.cfg, f.logger, "storage")
+	return getProvider(ctx, name, registry.GetRegistration, registry.GetSupportedProviders, f.cfg.Load(), f.logger, "storage")
 }
 
 // Initializes and returns the SQL client for the specified provider
 func (f *Factory) GetSqlProvider(ctx context.Context, name string) (sql.SQL, error) {
-	return getProvider(ctx, name, registry.GetSqlRegistration, registry.GetSupportedSqlProviders, f.cfg, f.logger, "SQL")
+	return getProvider(ctx, name, registry.GetSqlRegistration, registry.GetSupportedSqlProviders, f.cfg.Load(), f.logger, "SQL")
 }
@@ -3,14 +3,14 @@ package factory
 import (
 	"context"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/registry"
 	"io"
 	"log/slog"
 	"strings"
-	"synkronus/internal/config"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/sql"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/registry"
 	"testing"
 )
 
@@ -29,6 +29,9 @@ func (f *fakeStorage) DeleteBucket(ctx context.Context, n string) error
 func (f *fakeStorage) ListObjects(ctx context.Context, b, p string) (storage.ObjectList, error) {
 	return storage.ObjectList{}, nil
 }
+func (f *fakeStorage) WalkObjects(ctx context.Context, b, p string, fn func(storage.Object) error) error {
+	return nil
+}
 func (f *fakeStorage) DescribeObject(ctx context.Context, b, k string) (storage.Object, error) {
 	return storage.Object{}, nil
 }
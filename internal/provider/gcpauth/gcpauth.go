@@ -0,0 +1,170 @@
+// Package gcpauth builds Google API client options from the GCP provider
+// configuration. It is shared by the GCP storage and SQL providers so both
+// authenticate the same way.
+package gcpauth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/network"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// cloudPlatformScope is requested for impersonated credentials; it covers
+// every GCP API the providers call.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// ClientOptions returns the client options for the configured credentials.
+// With no credential settings it returns nil, so clients fall back to
+// Application Default Credentials.
+func ClientOptions(ctx context.Context, cfg *config.GCPConfig) ([]option.ClientOption, error) {
+	baseOpts := baseClientOptions(cfg)
+
+	// HTTP clients pick up network.ca_bundle from http.DefaultTransport; gRPC
+	// clients (e.g., monitoring) need it as transport credentials
+	var transportOpts []option.ClientOption
+	if pool := network.RootCAs(); pool != nil {
+		transportOpts = append(transportOpts, option.WithGRPCDialOption(
+			grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool})),
+		))
+	}
+
+	if cfg.ImpersonateServiceAccount == "" {
+		return append(baseOpts, transportOpts...), nil
+	}
+
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: cfg.ImpersonateServiceAccount,
+		Scopes:          []string{cloudPlatformScope},
+	}, baseOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate service account %s: %w", cfg.ImpersonateServiceAccount, err)
+	}
+	return append([]option.ClientOption{option.WithTokenSource(ts)}, transportOpts...), nil
+}
+
+// baseClientOptions returns the client options of the configured credentials
+// themselves, before any impersonation.
+func baseClientOptions(cfg *config.GCPConfig) []option.ClientOption {
+	if cfg.CredentialsFile == "" {
+		return nil
+	}
+	return []option.ClientOption{option.WithAuthCredentialsFile(option.ServiceAccount, cfg.CredentialsFile)}
+}
+
+// BlobSigner signs bytes as the impersonated service account through the IAM
+// Credentials signBlob API. It lets signed URLs and POST policies be made
+// without the account's private key; the base credentials need the Service
+// Account Token Creator role on the account, as impersonation already does.
+type BlobSigner struct {
+	// ServiceAccount is the email of the account signatures are made as
+	ServiceAccount string
+	service        *iamcredentials.Service
+}
+
+// NewBlobSigner returns a signer for cfg's impersonated service account, or
+// nil when none is configured.
+func NewBlobSigner(ctx context.Context, cfg *config.GCPConfig) (*BlobSigner, error) {
+	if cfg.ImpersonateServiceAccount == "" {
+		return nil, nil
+	}
+	service, err := iamcredentials.NewService(ctx, baseClientOptions(cfg)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IAM credentials client: %w", err)
+	}
+	return &BlobSigner{ServiceAccount: cfg.ImpersonateServiceAccount, service: service}, nil
+}
+
+// SignBytes returns a function signing with the account's Google-managed key,
+// in the form the storage client's SignBytes options take.
+func (s *BlobSigner) SignBytes(ctx context.Context) func([]byte) ([]byte, error) {
+	name := "projects/-/serviceAccounts/" + s.ServiceAccount
+	return func(b []byte) ([]byte, error) {
+		resp, err := s.service.Projects.ServiceAccounts.SignBlob(name, &iamcredentials.SignBlobRequest{
+			Payload: base64.StdEncoding.EncodeToString(b),
+		}).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign as service account %s: %w", s.ServiceAccount, err)
+		}
+		return base64.StdEncoding.DecodeString(resp.SignedBlob)
+	}
+}
+
+// HTTPClientOptions returns clientOpts for a client of a JSON (HTTP) API. With
+// debug HTTP logging enabled, they are replaced by an HTTP client that
+// authenticates the same way and logs each exchange; gRPC clients such as
+// monitoring must keep using clientOpts.
+func HTTPClientOptions(ctx context.Context, clientOpts []option.ClientOption) ([]option.ClientOption, error) {
+	if !network.DebugHTTPEnabled() {
+		return clientOpts, nil
+	}
+	opts := append(slices.Clip(clientOpts), option.WithScopes(cloudPlatformScope))
+	transport, err := htransport.NewTransport(ctx, network.DebugTransport(http.DefaultTransport), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP HTTP transport: %w", err)
+	}
+	return []option.ClientOption{option.WithHTTPClient(&http.Client{Transport: transport})}, nil
+}
+
+// DescribeCredentials reports where the base credentials for cfg come from and
+// checks that they can produce an access token. Impersonation is not applied;
+// it is exercised by the first API call made with ClientOptions.
+func DescribeCredentials(ctx context.Context, cfg *config.GCPConfig) (string, error) {
+	var creds *google.Credentials
+	var source string
+	if cfg.CredentialsFile != "" {
+		source = "service account key " + cfg.CredentialsFile
+		data, err := os.ReadFile(cfg.CredentialsFile)
+		if err != nil {
+			return source, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+		if creds, err = google.CredentialsFromJSONWithType(ctx, data, google.ServiceAccount, cloudPlatformScope); err != nil {
+			return source, fmt.Errorf("invalid credentials file: %w", err)
+		}
+	} else {
+		source = defaultCredentialsSource()
+		var err error
+		if creds, err = google.FindDefaultCredentials(ctx, cloudPlatformScope); err != nil {
+			return source, err
+		}
+	}
+
+	if _, err := creds.TokenSource.Token(); err != nil {
+		return source, fmt.Errorf("failed to obtain an access token: %w", err)
+	}
+	return source, nil
+}
+
+// defaultCredentialsSource names the Application Default Credentials source
+// that will be used, following the same lookup order as the client libraries.
+func defaultCredentialsSource() string {
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		return "GOOGLE_APPLICATION_CREDENTIALS (" + path + ")"
+	}
+	configDir := os.Getenv("CLOUDSDK_CONFIG")
+	if configDir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configDir = filepath.Join(home, ".config", "gcloud")
+		}
+	}
+	if path := filepath.Join(configDir, "application_default_credentials.json"); configDir != "" {
+		if _, err := os.Stat(path); err == nil {
+			return "gcloud application default credentials (" + path + ")"
+		}
+	}
+	return "GCE metadata server"
+}
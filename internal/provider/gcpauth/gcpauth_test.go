@@ -0,0 +1,78 @@
+package gcpauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/jkleinne/synkronus/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/option"
+)
+
+func TestClientOptions_NoCredentialSettings(t *testing.T) {
+	opts, err := ClientOptions(context.Background(), &config.GCPConfig{Project: "p"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts != nil {
+		t.Errorf("expected nil options so clients use Application Default Credentials, got %d", len(opts))
+	}
+}
+
+func TestClientOptions_CredentialsFile(t *testing.T) {
+	opts, err := ClientOptions(context.Background(), &config.GCPConfig{Project: "p", CredentialsFile: "/tmp/key.json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opts) != 1 {
+		t.Errorf("expected 1 option for a credentials file, got %d", len(opts))
+	}
+}
+
+func TestNewBlobSigner_NoImpersonation(t *testing.T) {
+	signer, err := NewBlobSigner(context.Background(), &config.GCPConfig{Project: "p"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signer != nil {
+		t.Error("expected no signer without an impersonated service account")
+	}
+}
+
+func TestBlobSigner_SignBytes(t *testing.T) {
+	var gotPath, gotPayload string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		var req iamcredentials.SignBlobRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		gotPayload = req.Payload
+		json.NewEncoder(w).Encode(iamcredentials.SignBlobResponse{SignedBlob: base64.StdEncoding.EncodeToString([]byte("signature"))})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, err := iamcredentials.NewService(ctx, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &BlobSigner{ServiceAccount: "signer@p.iam.gserviceaccount.com", service: service}
+
+	sig, err := signer.SignBytes(ctx)([]byte("payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(sig) != "signature" {
+		t.Errorf("expected the decoded signature, got %q", sig)
+	}
+	if !strings.HasSuffix(gotPath, "/projects/-/serviceAccounts/signer@p.iam.gserviceaccount.com:signBlob") {
+		t.Errorf("expected a signBlob call for the service account, got %s", gotPath)
+	}
+	if gotPayload != base64.StdEncoding.EncodeToString([]byte("payload")) {
+		t.Errorf("expected the payload base64-encoded, got %q", gotPayload)
+	}
+}
@@ -5,9 +5,10 @@ package provider
 
 import (
 	// Storage providers
-	_ "synkronus/internal/provider/storage/aws"
-	_ "synkronus/internal/provider/storage/gcp"
+	_ "github.com/jkleinne/synkronus/internal/provider/storage/aws"
+	_ "github.com/jkleinne/synkronus/internal/provider/storage/gcp"
+	_ "github.com/jkleinne/synkronus/internal/provider/storage/mock"
 
 	// SQL providers
-	_ "synkronus/internal/provider/sql/gcp"
+	_ "github.com/jkleinne/synkronus/internal/provider/sql/gcp"
 )
@@ -4,14 +4,14 @@ package registry
 import (
 	"context"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 	"log/slog"
 	"maps"
 	"slices"
 	"strings"
 	"sync"
-	"synkronus/internal/config"
-	"synkronus/internal/domain/sql"
-	"synkronus/internal/domain/storage"
 )
 
 // ProviderConfigCheck defines the function signature for checking if a provider is configured
@@ -2,8 +2,8 @@ package registry
 
 import (
 	"context"
+	"github.com/jkleinne/synkronus/internal/config"
 	"log/slog"
-	"synkronus/internal/config"
 	"testing"
 )
 
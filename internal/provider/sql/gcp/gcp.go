@@ -3,14 +3,20 @@ package gcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/provider/gcpauth"
+	"github.com/jkleinne/synkronus/internal/provider/registry"
+	"github.com/jkleinne/synkronus/internal/retry"
 	"log/slog"
-	"synkronus/internal/config"
-	"synkronus/internal/domain"
-	domainsql "synkronus/internal/domain/sql"
-	"synkronus/internal/provider/registry"
+	"net/http"
 	"time"
 
+	"cloud.google.com/go/auth"
+	"google.golang.org/api/googleapi"
 	sqladmin "google.golang.org/api/sqladmin/v1"
 )
 
@@ -26,7 +32,7 @@ func initialize(ctx context.Context, cfg *config.Config, logger *slog.Logger) (d
 	if !config.IsGCPConfigured(cfg) {
 		return nil, fmt.Errorf("GCP configuration missing or incomplete")
 	}
-	return NewGCPSQL(ctx, cfg.GCP.Project, logger)
+	return NewGCPSQL(ctx, cfg.GCP, logger)
 }
 
 // GCPSql implements the domainsql.SQL interface for Google Cloud SQL
@@ -38,15 +44,25 @@ type GCPSql struct {
 
 var _ domainsql.SQL = (*GCPSql)(nil)
 
-func NewGCPSQL(ctx context.Context, projectID string, logger *slog.Logger) (*GCPSql, error) {
-	svc, err := sqladmin.NewService(ctx)
+// NewGCPSQL creates a new Cloud SQL Admin client for cfg.Project, authenticated
+// with the credentials configured in cfg (or Application Default Credentials).
+func NewGCPSQL(ctx context.Context, cfg *config.GCPConfig, logger *slog.Logger) (*GCPSql, error) {
+	clientOpts, err := gcpauth.ClientOptions(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if clientOpts, err = gcpauth.HTTPClientOptions(ctx, clientOpts); err != nil {
+		return nil, err
+	}
+
+	svc, err := sqladmin.NewService(ctx, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GCP SQL Admin client: %w", err)
 	}
 
 	return &GCPSql{
 		service:   svc,
-		projectID: projectID,
+		projectID: cfg.PrimaryProject(),
 		logger:    logger,
 	}, nil
 }
@@ -55,6 +71,33 @@ func (g *GCPSql) ProviderName() domain.Provider {
 	return domain.GCP
 }
 
+// IsRetryable reports whether err is transient: a throttled or failed SQL
+// Admin API request, or a dropped connection.
+func (g *GCPSql) IsRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return retry.IsRetryableStatus(apiErr.Code)
+	}
+	return retry.IsTransientNetworkError(err)
+}
+
+// IsNotFound reports whether err means the instance does not exist.
+func (g *GCPSql) IsNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// IsAuthError reports whether err means the credentials are missing, were
+// rejected, or lack permission for the request.
+func (g *GCPSql) IsAuthError(err error) bool {
+	var authErr *auth.Error
+	if errors.As(err, &authErr) {
+		return true
+	}
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && (apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusForbidden)
+}
+
 func (g *GCPSql) ListInstances(ctx context.Context) ([]domainsql.Instance, error) {
 	g.logger.Debug("Starting GCP ListInstances operation")
 
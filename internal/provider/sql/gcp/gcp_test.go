@@ -1,8 +1,8 @@
 package gcp
 
 import (
-	"synkronus/internal/domain"
-	domainsql "synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
 	"testing"
 	"time"
 
@@ -2,17 +2,33 @@ package aws
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
 	"log/slog"
-	"synkronus/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithy "github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 func newTestStorage(t *testing.T) *AWSStorage {
 	t.Helper()
 	s, err := NewAWSStorage(context.Background(), &config.AWSConfig{
-		Region:   "us-east-1",
-		Endpoint: "http://localhost:4566",
-	}, slog.Default())
+		Region:      "us-east-1",
+		EndpointURL: "http://localhost:4566",
+	}, 0, slog.Default())
 	if err != nil {
 		t.Fatalf("failed to create test storage: %v", err)
 	}
@@ -39,3 +55,202 @@ func TestIsConfigured(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.AWSConfig
+		wantOpts int
+		wantErr  bool
+	}{
+		{"default chain", config.AWSConfig{Region: "us-east-1"}, 0, false},
+		{"shared profile", config.AWSConfig{Region: "us-east-1", Profile: "audit"}, 1, false},
+		{"static keys", config.AWSConfig{Region: "us-east-1", AccessKeyID: "AKIA", SecretAccessKey: "secret"}, 1, false},
+		{"static keys win over profile", config.AWSConfig{Region: "us-east-1", Profile: "audit", AccessKeyID: "AKIA", SecretAccessKey: "secret"}, 1, false},
+		{"sso", config.AWSConfig{Region: "us-east-1", SSOStartURL: "https://my.awsapps.com/start", SSOAccountID: "123456789012", SSORoleName: "ReadOnly"}, 1, false},
+		{"sso incomplete", config.AWSConfig{Region: "us-east-1", SSOStartURL: "https://my.awsapps.com/start"}, 0, true},
+		{"access key without secret", config.AWSConfig{Region: "us-east-1", AccessKeyID: "AKIA"}, 0, true},
+		{"secret without access key", config.AWSConfig{Region: "us-east-1", SecretAccessKey: "secret"}, 0, true},
+		{"session token without keys", config.AWSConfig{Region: "us-east-1", SessionToken: "token"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := credentialOptions(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("credentialOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(opts) != tt.wantOpts {
+				t.Errorf("credentialOptions() returned %d options, want %d", len(opts), tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestNewAWSStorage_AssumeRoles(t *testing.T) {
+	s, err := NewAWSStorage(context.Background(), &config.AWSConfig{
+		Region:      "us-east-1",
+		EndpointURL: "http://localhost:4566",
+		AssumeRoles: []string{
+			"arn:aws:iam::111111111111:role/Audit",
+			"arn:aws:iam::222222222222:role/Audit",
+		},
+	}, 0, slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(s.accounts) != 2 {
+		t.Fatalf("expected 2 account clients, got %d", len(s.accounts))
+	}
+	if s.accounts[0].accountID != "111111111111" || s.accounts[1].accountID != "222222222222" {
+		t.Errorf("unexpected account IDs: %q, %q", s.accounts[0].accountID, s.accounts[1].accountID)
+	}
+}
+
+func TestNewAWSStorage_InvalidRoleARN(t *testing.T) {
+	_, err := NewAWSStorage(context.Background(), &config.AWSConfig{
+		Region:      "us-east-1",
+		AssumeRoles: []string{"arn:aws:not-a-role"},
+	}, 0, slog.Default())
+	if err == nil {
+		t.Fatal("expected error for an invalid role ARN")
+	}
+}
+
+func httpResponseError(code int) error {
+	return &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: code}},
+		Err:      errors.New("response error"),
+	}}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"slow down", &smithy.GenericAPIError{Code: "SlowDown"}, true},
+		{"throttling", fmt.Errorf("listing: %w", &smithy.GenericAPIError{Code: "ThrottlingException"}), true},
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+		{"server error", httpResponseError(503), true},
+		{"not found", httpResponseError(404), false},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"other", errors.New("invalid bucket name"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadObject_RetriesTransientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	newStorage := func(maxRetries int) *AWSStorage {
+		s, err := NewAWSStorage(context.Background(), &config.AWSConfig{
+			Region:          "us-east-1",
+			EndpointURL:     srv.URL,
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "secret",
+		}, maxRetries, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	opts := storage.UploadObjectOptions{BucketName: "data", ObjectKey: "a.txt"}
+
+	if err := newStorage(1).UploadObject(context.Background(), opts, strings.NewReader("hello")); err != nil {
+		t.Fatalf("expected the upload to succeed after a retry, got %v", err)
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("expected 2 attempts, got %d", n)
+	}
+
+	attempts.Store(0)
+	if err := newStorage(0).UploadObject(context.Background(), opts, strings.NewReader("hello")); err == nil {
+		t.Error("expected the upload to fail without retries")
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("expected a single attempt with retries off, got %d", n)
+	}
+}
+
+func TestListBuckets_PartialAccounts(t *testing.T) {
+	newClient := func(handler http.HandlerFunc) *s3.Client {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+		return s3.New(s3.Options{
+			Region:       "us-east-1",
+			BaseEndpoint: &srv.URL,
+			UsePathStyle: true,
+			Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+			Retryer:      newRetryer(0),
+		})
+	}
+	s := &AWSStorage{
+		region: "us-east-1",
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		accounts: []accountClient{
+			{accountID: "111111111111", client: newClient(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `<ListAllMyBucketsResult><Buckets><Bucket><Name>logs</Name></Bucket></Buckets></ListAllMyBucketsResult>`)
+			})},
+			{accountID: "222222222222", client: newClient(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+				io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>denied</Message></Error>`)
+			})},
+		},
+	}
+
+	buckets, err := s.ListBuckets(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "account 222222222222") {
+		t.Errorf("expected the failed account in the error, got %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "logs" || buckets[0].Account != "111111111111" {
+		t.Errorf("expected the other account's buckets, got %+v", buckets)
+	}
+}
+
+func TestClassifyErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantNotFound bool
+		wantAuth     bool
+	}{
+		{"no such bucket", fmt.Errorf("listing: %w", &smithy.GenericAPIError{Code: "NoSuchBucket"}), true, false},
+		{"no such key", &smithy.GenericAPIError{Code: "NoSuchKey"}, true, false},
+		{"head not found", httpResponseError(404), true, false},
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, false, true},
+		{"expired token", &smithy.GenericAPIError{Code: "ExpiredToken"}, false, true},
+		{"forbidden", httpResponseError(403), false, true},
+		{"no credentials", &v4.SigningError{Err: errors.New("failed to retrieve credentials")}, false, true},
+		{"server error", httpResponseError(503), false, false},
+		{"other", errors.New("invalid bucket name"), false, false},
+	}
+	s := &AWSStorage{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsNotFound(tt.err); got != tt.wantNotFound {
+				t.Errorf("IsNotFound(%v) = %v, want %v", tt.err, got, tt.wantNotFound)
+			}
+			if got := s.IsAuthError(tt.err); got != tt.wantAuth {
+				t.Errorf("IsAuthError(%v) = %v, want %v", tt.err, got, tt.wantAuth)
+			}
+		})
+	}
+}
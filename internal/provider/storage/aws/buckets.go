@@ -2,24 +2,54 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"maps"
+	"slices"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 func (s *AWSStorage) ListBuckets(ctx context.Context) ([]storage.Bucket, error) {
-	s.logger.Debug("Starting AWS ListBuckets operation")
+	s.logger.Debug("Starting AWS ListBuckets operation", "accounts", len(s.accounts))
 
+	if len(s.accounts) == 0 {
+		return s.listBuckets(ctx, s.client, "")
+	}
+
+	// Each account is listed concurrently; results are kept in configured role
+	// order. A failed account (e.g., a role that cannot be assumed) does not stop
+	// the others: their buckets are returned along with the failures.
+	results := make([][]storage.Bucket, len(s.accounts))
+	errs := make([]error, len(s.accounts))
+	var wg sync.WaitGroup
+	for i, account := range s.accounts {
+		wg.Go(func() {
+			buckets, err := s.listBuckets(ctx, account.client, account.accountID)
+			if err != nil {
+				err = fmt.Errorf("account %s: %w", account.accountID, err)
+			}
+			results[i], errs[i] = buckets, err
+		})
+	}
+	wg.Wait()
+
+	return slices.Concat(results...), errors.Join(errs...)
+}
+
+// listBuckets lists the buckets visible to client, tagging them with accountID.
+func (s *AWSStorage) listBuckets(ctx context.Context, client *s3.Client, accountID string) ([]storage.Bucket, error) {
 	input := &s3.ListBucketsInput{
 		BucketRegion: &s.region,
 	}
 
 	var buckets []storage.Bucket
-	paginator := s3.NewListBucketsPaginator(s.client, input)
+	paginator := s3.NewListBucketsPaginator(client, input)
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
@@ -30,6 +60,7 @@ func (s *AWSStorage) ListBuckets(ctx context.Context) ([]storage.Bucket, error)
 			bucket := storage.Bucket{
 				Name:         derefString(b.Name),
 				Provider:     domain.AWS,
+				Account:      accountID,
 				Location:     s.region,
 				StorageClass: shared.StorageClassStandard,
 				UsageBytes:   -1,
@@ -131,6 +162,40 @@ func (s *AWSStorage) DeleteBucket(ctx context.Context, bucketName string) error
 	return nil
 }
 
+var _ storage.BucketLabeler = (*AWSStorage)(nil)
+
+// UpdateBucketLabels merges the labels into the bucket's tags. S3 replaces the
+// whole tag set at once, so the current tags are read first.
+func (s *AWSStorage) UpdateBucketLabels(ctx context.Context, bucketName string, labels map[string]string) error {
+	s.logger.Debug("Starting AWS UpdateBucketLabels operation", "bucket", bucketName)
+
+	if len(labels) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(labels))
+	out, err := s.client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: &bucketName})
+	switch {
+	case err == nil:
+		maps.Copy(merged, mapTags(out.TagSet))
+	case !isS3NotConfiguredError(err):
+		return fmt.Errorf("reading tags of bucket %s: %w", bucketName, err)
+	}
+	maps.Copy(merged, labels)
+
+	tags := make([]types.Tag, 0, len(merged))
+	for _, k := range slices.Sorted(maps.Keys(merged)) {
+		tags = append(tags, types.Tag{Key: strPtr(k), Value: strPtr(merged[k])})
+	}
+	_, err = s.client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  &bucketName,
+		Tagging: &types.Tagging{TagSet: tags},
+	})
+	if err != nil {
+		return fmt.Errorf("updating tags of bucket %s: %w", bucketName, err)
+	}
+	return nil
+}
+
 // derefString safely dereferences a string pointer, returning "" if nil.
 func derefString(s *string) string {
 	if s == nil {
@@ -2,17 +2,35 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/network"
+	"github.com/jkleinne/synkronus/internal/provider/awssso"
+	"github.com/jkleinne/synkronus/internal/provider/registry"
+	"github.com/jkleinne/synkronus/internal/retry"
+	"github.com/jkleinne/synkronus/internal/telemetry"
 	"log/slog"
-	"synkronus/internal/config"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/registry"
+	"net/http"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithy "github.com/aws/smithy-go"
 )
 
+// assumeRoleSessionName identifies synkronus sessions in CloudTrail
+const assumeRoleSessionName = "synkronus"
+
 func init() {
 	registry.RegisterProvider("aws", registry.Registration[storage.Storage]{
 		ConfigCheck: isConfigured,
@@ -30,49 +48,239 @@ func initialize(ctx context.Context, cfg *config.Config, logger *slog.Logger) (s
 	if !isConfigured(cfg) {
 		return nil, fmt.Errorf("AWS configuration missing or incomplete")
 	}
-	return NewAWSStorage(ctx, cfg.AWS, logger)
+	return NewAWSStorage(ctx, cfg.AWS, cfg.MaxRetries(), logger)
 }
 
 // AWSStorage implements storage.Storage using the AWS S3 API.
 type AWSStorage struct {
 	client *s3.Client
 	region string
-	logger *slog.Logger
+	// endpointURL overrides the S3 endpoint (e.g., LocalStack); empty for AWS
+	endpointURL string
+	logger      *slog.Logger
+	// accounts holds one client per assumed role. When non-empty, ListBuckets
+	// aggregates across these accounts; all other operations use client.
+	accounts []accountClient
+}
+
+// accountClient is an S3 client authenticated as a role in another account.
+type accountClient struct {
+	accountID string
+	client    *s3.Client
 }
 
 var _ storage.Storage = (*AWSStorage)(nil)
 
-// NewAWSStorage creates a new S3 storage client. If cfg.Endpoint is set, the client
-// targets that URL (e.g., LocalStack) instead of real AWS endpoints.
-func NewAWSStorage(ctx context.Context, cfg *config.AWSConfig, logger *slog.Logger) (*AWSStorage, error) {
-	sdkCfg, err := awsconfig.LoadDefaultConfig(ctx,
-		awsconfig.WithRegion(cfg.Region),
-	)
+// NewAWSStorage creates a new S3 storage client. If cfg.EndpointURL is set, the client
+// targets that URL (e.g., LocalStack) instead of real AWS endpoints. Each role in
+// cfg.AssumeRoles gets its own client, assumed with the base credentials. The
+// SDK retries calls that fail with a transient error up to maxRetries times.
+func NewAWSStorage(ctx context.Context, cfg *config.AWSConfig, maxRetries int, logger *slog.Logger) (*AWSStorage, error) {
+	sdkCfg, err := loadSDKConfig(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS SDK config: %w", err)
+		return nil, err
 	}
 
-	var s3Opts []func(*s3.Options)
-	if cfg.Endpoint != "" {
+	// Each S3 request is traced, as the SDK does not report to OpenTelemetry itself.
+	// Wrapped here rather than in loadSDKConfig, which needs the SDK's own client
+	// to apply a custom CA bundle.
+	s3Opts := []func(*s3.Options){func(o *s3.Options) {
+		o.HTTPClient = telemetry.WrapHTTPClient(o.HTTPClient)
+		o.Retryer = newRetryer(maxRetries)
+	}}
+	if cfg.EndpointURL != "" {
 		s3Opts = append(s3Opts, func(o *s3.Options) {
-			o.BaseEndpoint = &cfg.Endpoint
+			o.BaseEndpoint = &cfg.EndpointURL
 			o.UsePathStyle = true // Required for LocalStack and most S3-compatible services
 		})
 	}
 
 	client := s3.NewFromConfig(sdkCfg, s3Opts...)
 
+	accounts, err := assumeRoleClients(sdkCfg, cfg.AssumeRoles, s3Opts)
+	if err != nil {
+		return nil, err
+	}
+
 	return &AWSStorage{
-		client: client,
-		region: cfg.Region,
-		logger: logger,
+		client:      client,
+		region:      cfg.Region,
+		endpointURL: cfg.EndpointURL,
+		logger:      logger,
+		accounts:    accounts,
 	}, nil
 }
 
+// assumeRoleClients builds an S3 client per role ARN. Credentials are fetched
+// lazily through STS on first use and cached until they expire.
+func assumeRoleClients(base aws.Config, roleARNs []string, s3Opts []func(*s3.Options)) ([]accountClient, error) {
+	if len(roleARNs) == 0 {
+		return nil, nil
+	}
+
+	stsClient := sts.NewFromConfig(base)
+	accounts := make([]accountClient, 0, len(roleARNs))
+	for _, roleARN := range roleARNs {
+		parsed, err := arn.Parse(roleARN)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role ARN in aws.assume_roles %q: %w", roleARN, err)
+		}
+
+		roleCfg := base.Copy()
+		roleCfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, roleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = assumeRoleSessionName
+		}))
+		accounts = append(accounts, accountClient{
+			accountID: parsed.AccountID,
+			client:    s3.NewFromConfig(roleCfg, s3Opts...),
+		})
+	}
+	return accounts, nil
+}
+
+// loadSDKConfig loads the SDK config for cfg's region and credentials, with
+// the HTTP transport configured from the network settings.
+func loadSDKConfig(ctx context.Context, cfg *config.AWSConfig) (aws.Config, error) {
+	loadOpts, err := credentialOptions(cfg)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	loadOpts = append(loadOpts,
+		awsconfig.WithRegion(cfg.Region),
+		awsconfig.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(network.ConfigureTransport)),
+	)
+
+	sdkCfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS SDK config: %w", err)
+	}
+	// After loading, which needs the SDK's own client to apply a custom CA bundle
+	sdkCfg.HTTPClient = network.DebugHTTPClient(sdkCfg.HTTPClient)
+	return sdkCfg, nil
+}
+
+// credentialOptions translates the credential settings in cfg into SDK load
+// options. Static keys win over cached SSO credentials, which win over a shared
+// config profile; with none set the SDK's default credential chain is used.
+func credentialOptions(cfg *config.AWSConfig) ([]func(*awsconfig.LoadOptions) error, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+
+	if (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == "") {
+		return nil, fmt.Errorf("aws.access_key_id and aws.secret_access_key must be set together")
+	}
+	if cfg.SessionToken != "" && cfg.AccessKeyID == "" {
+		return nil, fmt.Errorf("aws.session_token requires aws.access_key_id and aws.secret_access_key")
+	}
+
+	if cfg.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken),
+		))
+	} else if cfg.UsesSSO() {
+		settings, err := awssso.SettingsFrom(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, awsconfig.WithCredentialsProvider(aws.NewCredentialsCache(awssso.NewCredentialsProvider(settings))))
+	} else if cfg.Profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(cfg.Profile))
+	}
+
+	return opts, nil
+}
+
+// CredentialSource resolves the credentials NewAWSStorage would use for cfg
+// and returns the name of the provider that supplied them (e.g., EnvConfigCredentials,
+// SharedConfigCredentials, StaticCredentials).
+func CredentialSource(ctx context.Context, cfg *config.AWSConfig) (string, error) {
+	sdkCfg, err := loadSDKConfig(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	creds, err := sdkCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	return creds.Source, nil
+}
+
 func (s *AWSStorage) ProviderName() domain.Provider {
 	return domain.AWS
 }
 
+// newRetryer returns the SDK's standard retryer, making at most maxRetries
+// retries and also retrying the errors isRetryable classifies as transient.
+func newRetryer(maxRetries int) aws.Retryer {
+	return awsretry.NewStandard(func(o *awsretry.StandardOptions) {
+		o.MaxAttempts = maxRetries + 1
+		o.Retryables = append(o.Retryables, awsretry.IsErrorRetryableFunc(func(err error) aws.Ternary {
+			if isRetryable(err) {
+				return aws.TrueTernary
+			}
+			return aws.UnknownTernary
+		}))
+	})
+}
+
+// RetriesInSDK reports that S3 calls are retried by the SDK's retryer.
+func (s *AWSStorage) RetriesInSDK() bool {
+	return true
+}
+
+// isRetryable reports whether err is transient: S3 throttling (which is not
+// always reported as a 429), a 5xx response, or a dropped connection.
+func isRetryable(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "SlowDown", "Throttling", "ThrottlingException", "RequestLimitExceeded", "RequestTimeout":
+			return true
+		}
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return retry.IsRetryableStatus(respErr.HTTPStatusCode())
+	}
+	return retry.IsTransientNetworkError(err)
+}
+
+// IsNotFound reports whether err means the bucket or object does not exist.
+// HeadBucket and HeadObject have no response body, so they only report a 404.
+func (s *AWSStorage) IsNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchBucket", "NoSuchKey", "NotFound", "QueueDoesNotExist":
+			return true
+		}
+	}
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
+
+// IsAuthError reports whether err means no credentials could be found, they
+// were rejected, or they lack permission for the request.
+func (s *AWSStorage) IsAuthError(err error) bool {
+	var signErr *v4.SigningError
+	if errors.As(err, &signErr) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "AccessDenied", "AllAccessDisabled", "InvalidAccessKeyId", "SignatureDoesNotMatch",
+			"ExpiredToken", "InvalidToken", "TokenRefreshRequired", "AccessDeniedException", "InvalidClientTokenId":
+			return true
+		}
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		code := respErr.HTTPStatusCode()
+		return code == http.StatusUnauthorized || code == http.StatusForbidden
+	}
+	return false
+}
+
 func (s *AWSStorage) Close() error {
 	// AWS SDK v2 clients don't require explicit cleanup
 	return nil
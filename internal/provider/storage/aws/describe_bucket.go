@@ -3,9 +3,9 @@ package aws
 import (
 	"context"
 	"errors"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 	"log/slog"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	smithy "github.com/aws/smithy-go"
@@ -111,7 +111,7 @@ func (s *AWSStorage) bucketFetchers(bucketName string, bucket *storage.Bucket) [
 				return err
 			}
 			if out.Policy != nil {
-				statements, parseErr := parseBucketPolicy(*out.Policy)
+				statements, parseErr := storage.ParseBucketPolicy(*out.Policy)
 				if parseErr != nil {
 					return parseErr
 				}
@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+var _ storage.BucketURLResolver = (*AWSStorage)(nil)
+
+// BucketURL returns the bucket's regional endpoint, virtual-hosted unless
+// path style is asked for. With a custom endpoint (e.g., LocalStack) the URL
+// is always path-style, as the client addresses it.
+func (s *AWSStorage) BucketURL(ctx context.Context, bucketName, style string) (string, error) {
+	if s.endpointURL != "" {
+		return strings.TrimSuffix(s.endpointURL, "/") + "/" + bucketName, nil
+	}
+
+	// Buckets answer only at the endpoint of their own region
+	out, err := s.client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: &bucketName})
+	if err != nil {
+		return "", fmt.Errorf("getting the region of bucket %s: %w", bucketName, err)
+	}
+	region := string(out.LocationConstraint)
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	if style == storage.URLStylePath {
+		return fmt.Sprintf("https://s3.%s.amazonaws.com/%s", region, bucketName), nil
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucketName, region), nil
+}
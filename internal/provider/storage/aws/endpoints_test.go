@@ -0,0 +1,17 @@
+package aws
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"testing"
+)
+
+func TestBucketURL_CustomEndpoint(t *testing.T) {
+	got, err := newTestStorage(t).BucketURL(context.Background(), "data", storage.URLStyleVirtualHosted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "http://localhost:4566/data" {
+		t.Errorf("expected a path-style URL on the custom endpoint, got %q", got)
+	}
+}
@@ -0,0 +1,209 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// tailWait is the long-polling wait of each receive
+	tailWait = 20 * time.Second
+	// tailIdleInterval spaces receives that only return messages already
+	// shown, which the queue's consumers have not deleted yet
+	tailIdleInterval = 2 * time.Second
+	// tailSeenMessages bounds the message IDs remembered to skip repeats
+	tailSeenMessages = 10000
+)
+
+var _ storage.EventTailer = (*AWSStorage)(nil)
+
+// TailEvents streams the object events of bucketName from the SQS queue of one
+// of its event notification configurations. Messages are peeked rather than
+// consumed, so the queue's consumers still receive and delete them; each peek
+// does count towards a message's receive count, which matters for queues with
+// a redrive policy.
+func (s *AWSStorage) TailEvents(ctx context.Context, bucketName string, ready func(source string), fn func(storage.ObjectEvent) error) error {
+	cfg, err := s.client.GetBucketNotificationConfiguration(ctx, &s3.GetBucketNotificationConfigurationInput{Bucket: &bucketName})
+	if err != nil {
+		return fmt.Errorf("failed to get notification configuration: %w", err)
+	}
+	queueARN, err := tailQueue(cfg)
+	if err != nil {
+		return err
+	}
+	queue, err := arn.Parse(queueARN)
+	if err != nil {
+		return fmt.Errorf("invalid queue ARN %q in the notification configuration: %w", queueARN, err)
+	}
+
+	sqs := s.newSQSClient(queue.Region)
+	queueURL, err := sqs.queueURL(ctx, queue.Resource, queue.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to find queue %s: %w", queueARN, err)
+	}
+
+	seen := newSeenSet(tailSeenMessages)
+	ready(queueARN)
+	for {
+		messages, err := sqs.peek(ctx, queueURL, tailWait)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to receive events from %s: %w", queueARN, err)
+		}
+
+		fresh := 0
+		for _, msg := range messages {
+			if !seen.add(msg.MessageID) {
+				continue
+			}
+			fresh++
+			for _, event := range notificationEvents(msg.Body) {
+				// Other buckets may notify the same queue
+				if event.Bucket != bucketName {
+					continue
+				}
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+		}
+		if len(messages) > 0 && fresh == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(tailIdleInterval):
+			}
+		}
+	}
+}
+
+// tailQueue picks the queue to tail from a bucket's notification
+// configuration, preferring one without a key filter.
+func tailQueue(cfg *s3.GetBucketNotificationConfigurationOutput) (string, error) {
+	var queue *types.QueueConfiguration
+	for i, q := range cfg.QueueConfigurations {
+		if queue == nil || (queue.Filter != nil && q.Filter == nil) {
+			queue = &cfg.QueueConfigurations[i]
+		}
+	}
+	if queue != nil {
+		return aws.ToString(queue.QueueArn), nil
+	}
+
+	switch {
+	case len(cfg.TopicConfigurations) > 0:
+		return "", fmt.Errorf("the bucket notifies SNS topic %s rather than an SQS queue; subscribe a queue to the topic and add it to the bucket's notification configuration", aws.ToString(cfg.TopicConfigurations[0].TopicArn))
+	case cfg.EventBridgeConfiguration != nil:
+		return "", fmt.Errorf("the bucket sends its events to EventBridge rather than an SQS queue; add a queue to the bucket's notification configuration")
+	default:
+		return "", fmt.Errorf("bucket has no SQS event notification configuration; add one with 'aws s3api put-bucket-notification-configuration'")
+	}
+}
+
+// s3EventMessage is the body of an S3 event notification, or of an SNS
+// notification wrapping one (for queues subscribed to a topic).
+type s3EventMessage struct {
+	Records []struct {
+		EventTime time.Time `json:"eventTime"`
+		EventName string    `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key       string `json:"key"`
+				Size      int64  `json:"size"`
+				VersionID string `json:"versionId"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+	// Type and Message are set by SNS
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// notificationEvents maps the records of an S3 event notification to object
+// events. Other messages, such as the s3:TestEvent sent when a configuration
+// is saved, have no events.
+func notificationEvents(body string) []storage.ObjectEvent {
+	var msg s3EventMessage
+	if json.Unmarshal([]byte(body), &msg) != nil {
+		return nil
+	}
+	if msg.Type == "Notification" && msg.Message != "" {
+		return notificationEvents(msg.Message)
+	}
+
+	events := make([]storage.ObjectEvent, 0, len(msg.Records))
+	for _, r := range msg.Records {
+		// Keys are URL-encoded, with spaces as '+'
+		key, err := url.QueryUnescape(r.S3.Object.Key)
+		if err != nil {
+			key = r.S3.Object.Key
+		}
+		event := storage.ObjectEvent{
+			Time:    r.EventTime,
+			Type:    notificationEventType(r.EventName),
+			Name:    r.EventName,
+			Bucket:  r.S3.Bucket.Name,
+			Key:     key,
+			Version: r.S3.Object.VersionID,
+		}
+		if event.Type == storage.ObjectCreated {
+			event.Size = r.S3.Object.Size
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func notificationEventType(eventName string) storage.ObjectEventType {
+	category, _, _ := strings.Cut(eventName, ":")
+	switch category {
+	case "ObjectCreated":
+		return storage.ObjectCreated
+	case "ObjectRemoved", "LifecycleExpiration":
+		return storage.ObjectDeleted
+	case "ObjectTagging", "ObjectAcl":
+		return storage.ObjectMetadataUpdated
+	default:
+		return storage.ObjectOther
+	}
+}
+
+// seenSet remembers up to limit IDs, forgetting the oldest first.
+type seenSet struct {
+	limit int
+	ids   map[string]bool
+	order []string
+}
+
+func newSeenSet(limit int) *seenSet {
+	return &seenSet{limit: limit, ids: make(map[string]bool)}
+}
+
+// add records id and reports whether it is new.
+func (s *seenSet) add(id string) bool {
+	if s.ids[id] {
+		return false
+	}
+	s.ids[id] = true
+	s.order = append(s.order, id)
+	if len(s.order) > s.limit {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+	return true
+}
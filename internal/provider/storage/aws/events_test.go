@@ -0,0 +1,146 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const s3EventBody = `{"Records": [{
+	"eventTime": "2024-01-01T00:00:00.000Z",
+	"eventName": "ObjectCreated:Put",
+	"s3": {
+		"bucket": {"name": "data"},
+		"object": {"key": "exports/daily+report%282%29.csv", "size": 2048, "versionId": "v1"}
+	}
+}]}`
+
+func TestNotificationEvents(t *testing.T) {
+	want := storage.ObjectEvent{
+		Time:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Type:    storage.ObjectCreated,
+		Name:    "ObjectCreated:Put",
+		Bucket:  "data",
+		Key:     "exports/daily report(2).csv",
+		Size:    2048,
+		Version: "v1",
+	}
+	events := notificationEvents(s3EventBody)
+	if len(events) != 1 || events[0] != want {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+
+	sns := `{"Type": "Notification", "Message": ` + jsonString(s3EventBody) + `}`
+	if events := notificationEvents(sns); len(events) != 1 || events[0].Key != want.Key {
+		t.Errorf("expected the event inside the SNS notification, got %+v", events)
+	}
+
+	deleted := strings.Replace(s3EventBody, "ObjectCreated:Put", "ObjectRemoved:DeleteMarkerCreated", 1)
+	if events := notificationEvents(deleted); len(events) != 1 || events[0].Type != storage.ObjectDeleted || events[0].Size != 0 {
+		t.Errorf("expected a deletion without a size, got %+v", events)
+	}
+	if events := notificationEvents(`{"Service": "Amazon S3", "Event": "s3:TestEvent"}`); len(events) != 0 {
+		t.Errorf("expected no events for a test event, got %+v", events)
+	}
+}
+
+func TestTailQueue(t *testing.T) {
+	filtered := types.QueueConfiguration{QueueArn: aws.String("arn:aws:sqs:us-east-1:123456789012:logs"), Filter: &types.NotificationConfigurationFilter{}}
+	all := types.QueueConfiguration{QueueArn: aws.String("arn:aws:sqs:us-east-1:123456789012:all")}
+	queue, err := tailQueue(&s3.GetBucketNotificationConfigurationOutput{QueueConfigurations: []types.QueueConfiguration{filtered, all}})
+	if err != nil || queue != *all.QueueArn {
+		t.Errorf("tailQueue() = %q, %v; want the queue without a filter", queue, err)
+	}
+
+	_, err = tailQueue(&s3.GetBucketNotificationConfigurationOutput{
+		TopicConfigurations: []types.TopicConfiguration{{TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:events")}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "SNS topic") {
+		t.Errorf("expected an error about the SNS topic, got %v", err)
+	}
+	if _, err := tailQueue(&s3.GetBucketNotificationConfigurationOutput{}); err == nil {
+		t.Error("expected an error without notification configurations")
+	}
+}
+
+func TestTailEvents(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Query().Has("notification") {
+			io.WriteString(w, `<NotificationConfiguration><QueueConfiguration><Id>1</Id>`+
+				`<Queue>arn:aws:sqs:us-east-1:123456789012:events</Queue><Event>s3:ObjectCreated:*</Event>`+
+				`</QueueConfiguration></NotificationConfiguration>`)
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+			http.Error(w, `{"__type": "com.amazonaws.sqs#MissingAuthenticationToken"}`, http.StatusForbidden)
+			return
+		}
+		switch r.Header.Get("X-Amz-Target") {
+		case "AmazonSQS.GetQueueUrl":
+			io.WriteString(w, `{"QueueUrl": "`+srv.URL+`/123456789012/events"}`)
+		case "AmazonSQS.ReceiveMessage":
+			io.WriteString(w, `{"Messages": [{"MessageId": "m1", "Body": `+jsonString(s3EventBody)+`}]}`)
+		default:
+			http.Error(w, `{"__type": "com.amazonaws.sqs#InvalidAction"}`, http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := NewAWSStorage(context.Background(), &config.AWSConfig{
+		Region:          "us-east-1",
+		EndpointURL:     srv.URL,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	}, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errStop := errors.New("stop")
+	var source string
+	var got []storage.ObjectEvent
+	err = s.TailEvents(context.Background(), "data", func(s string) { source = s }, func(e storage.ObjectEvent) error {
+		got = append(got, e)
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("TailEvents() = %v, want the error of fn", err)
+	}
+	if source != "arn:aws:sqs:us-east-1:123456789012:events" {
+		t.Errorf("source = %q", source)
+	}
+	if len(got) != 1 || got[0].Key != "exports/daily report(2).csv" {
+		t.Errorf("unexpected events: %+v", got)
+	}
+}
+
+func TestSQSError(t *testing.T) {
+	s := newTestStorage(t)
+	err := error(&jsonError{service: "SQS", Type: "com.amazon.coral.service#AccessDeniedException", Message: "denied", statusCode: http.StatusForbidden})
+	if !s.IsAuthError(err) {
+		t.Errorf("expected %v to be an auth error", err)
+	}
+	if notFound := (&jsonError{service: "SQS", Type: "AWS.SimpleQueueService.NonExistentQueue#QueueDoesNotExist", statusCode: 400}); !s.IsNotFound(notFound) {
+		t.Errorf("expected %v to be a not-found error", notFound)
+	}
+}
+
+// jsonString returns s as a JSON string literal.
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
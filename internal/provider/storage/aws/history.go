@@ -0,0 +1,232 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// queryPollInterval spaces the checks of a running CloudTrail Lake query
+	queryPollInterval = time.Second
+	// lakeTimeLayout is how CloudTrail Lake writes and compares event times
+	lakeTimeLayout = "2006-01-02 15:04:05.000"
+)
+
+// cloudTrailAPI is the CloudTrail JSON protocol
+var cloudTrailAPI = jsonAPI{
+	name:         "CloudTrail",
+	signingName:  "cloudtrail",
+	targetPrefix: "com.amazonaws.cloudtrail.v20131101.CloudTrail_20131101.",
+	version:      "1.1",
+}
+
+// errNoEventDataStore is returned when no CloudTrail Lake event data store
+// collects S3 object events
+var errNoEventDataStore = errors.New("no enabled CloudTrail Lake event data store collects S3 data events (resources.type AWS::S3::Object) in this region")
+
+var _ storage.ObjectHistoryReader = (*AWSStorage)(nil)
+
+// ObjectHistory queries CloudTrail Lake for the calls made on objectKey.
+// Object writes, deletes and ACL changes are S3 data events, which CloudTrail
+// event history (LookupEvents) does not return, so an event data store of the
+// configured region must collect them. Reads are left out, as are batch
+// deletes (DeleteObjects), which do not name their keys in the event.
+func (s *AWSStorage) ObjectHistory(ctx context.Context, bucketName, objectKey string, since time.Time, limit int) ([]storage.ObjectChange, error) {
+	s.logger.Debug("Starting AWS ObjectHistory operation", "bucket", bucketName, "key", objectKey)
+
+	ct := s.newJSONClient(cloudTrailAPI, s.region)
+	store, err := objectEventStore(ctx, ct)
+	if err != nil {
+		return nil, err
+	}
+	var started struct {
+		QueryID string `json:"QueryId"`
+	}
+	err = ct.call(ctx, "StartQuery", map[string]string{"QueryStatement": historyQuery(store, bucketName, objectKey, since, limit)}, &started)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CloudTrail Lake: %w", err)
+	}
+	rows, err := queryResults(ctx, ct, started.QueryID)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]storage.ObjectChange, len(rows))
+	for i, row := range rows {
+		changes[i] = changeFromRow(row)
+	}
+	return changes, nil
+}
+
+// eventDataStore holds the fields of an event data store needed to pick one.
+type eventDataStore struct {
+	EventDataStoreARN      string `json:"EventDataStoreArn"`
+	Status                 string `json:"Status"`
+	AdvancedEventSelectors []struct {
+		FieldSelectors []struct {
+			Field  string   `json:"Field"`
+			Equals []string `json:"Equals"`
+		} `json:"FieldSelectors"`
+	} `json:"AdvancedEventSelectors"`
+}
+
+// collectsObjectEvents reports whether the store is enabled and selects S3
+// object data events.
+func (d eventDataStore) collectsObjectEvents() bool {
+	if d.Status != "ENABLED" {
+		return false
+	}
+	for _, selector := range d.AdvancedEventSelectors {
+		for _, field := range selector.FieldSelectors {
+			if field.Field == "resources.type" && slices.Contains(field.Equals, "AWS::S3::Object") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// objectEventStore returns the ID of the first event data store collecting S3
+// object events, which is what a query selects FROM.
+func objectEventStore(ctx context.Context, ct *jsonClient) (string, error) {
+	var token string
+	for {
+		var page struct {
+			EventDataStores []struct {
+				EventDataStoreARN string `json:"EventDataStoreArn"`
+			} `json:"EventDataStores"`
+			NextToken string `json:"NextToken"`
+		}
+		input := map[string]string{}
+		if token != "" {
+			input["NextToken"] = token
+		}
+		if err := ct.call(ctx, "ListEventDataStores", input, &page); err != nil {
+			return "", fmt.Errorf("failed to list CloudTrail Lake event data stores: %w", err)
+		}
+		for _, summary := range page.EventDataStores {
+			var store eventDataStore
+			if err := ct.call(ctx, "GetEventDataStore", map[string]string{"EventDataStore": summary.EventDataStoreARN}, &store); err != nil {
+				return "", fmt.Errorf("failed to get event data store %s: %w", summary.EventDataStoreARN, err)
+			}
+			if store.collectsObjectEvents() {
+				return store.EventDataStoreARN[strings.LastIndexByte(store.EventDataStoreARN, '/')+1:], nil
+			}
+		}
+		if page.NextToken == "" {
+			return "", errNoEventDataStore
+		}
+		token = page.NextToken
+	}
+}
+
+// historyQuery returns the CloudTrail Lake query for the calls on an object
+// since the given time, other than reads, newest first.
+func historyQuery(store, bucketName, objectKey string, since time.Time, limit int) string {
+	query := fmt.Sprintf(`SELECT eventTime, eventName, userIdentity.arn AS principal, sourceIPAddress, userAgent, errorCode, errorMessage
+FROM %s
+WHERE eventSource = 's3.amazonaws.com' AND readOnly = false
+AND element_at(requestParameters, 'bucketName') = %s
+AND element_at(requestParameters, 'key') = %s
+AND eventTime >= %s
+ORDER BY eventTime DESC`, store, sqlString(bucketName), sqlString(objectKey), sqlString(since.UTC().Format(lakeTimeLayout)))
+	if limit > 0 {
+		query += fmt.Sprintf("\nLIMIT %d", limit)
+	}
+	return query
+}
+
+// sqlString returns s as an SQL string literal.
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// queryResults waits for a query to finish and returns its rows, each a map
+// of column to value.
+func queryResults(ctx context.Context, ct *jsonClient, queryID string) ([]map[string]string, error) {
+	var rows []map[string]string
+	var token string
+	for {
+		var page struct {
+			QueryStatus     string                `json:"QueryStatus"`
+			QueryResultRows [][]map[string]string `json:"QueryResultRows"`
+			ErrorMessage    string                `json:"ErrorMessage"`
+			NextToken       string                `json:"NextToken"`
+		}
+		input := map[string]string{"QueryId": queryID}
+		if token != "" {
+			input["NextToken"] = token
+		}
+		if err := ct.call(ctx, "GetQueryResults", input, &page); err != nil {
+			return nil, fmt.Errorf("failed to get the results of CloudTrail Lake query %s: %w", queryID, err)
+		}
+
+		switch page.QueryStatus {
+		case "QUEUED", "RUNNING":
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(queryPollInterval):
+			}
+			continue
+		case "FINISHED":
+		default:
+			return nil, fmt.Errorf("CloudTrail Lake query %s %s: %s", queryID, strings.ToLower(page.QueryStatus), page.ErrorMessage)
+		}
+
+		for _, columns := range page.QueryResultRows {
+			row := make(map[string]string, len(columns))
+			for _, column := range columns {
+				for name, value := range column {
+					row[name] = value
+				}
+			}
+			rows = append(rows, row)
+		}
+		if page.NextToken == "" {
+			return rows, nil
+		}
+		token = page.NextToken
+	}
+}
+
+// changeFromRow maps a row of the history query.
+func changeFromRow(row map[string]string) storage.ObjectChange {
+	change := storage.ObjectChange{
+		Type:      changeType(row["eventName"]),
+		Operation: row["eventName"],
+		Principal: row["principal"],
+		SourceIP:  row["sourceIPAddress"],
+		UserAgent: row["userAgent"],
+		Error:     row["errorCode"],
+	}
+	if message := row["errorMessage"]; message != "" && change.Error != "" {
+		change.Error += ": " + message
+	}
+	if t, err := time.Parse(lakeTimeLayout, row["eventTime"]); err == nil {
+		change.Time = t
+	} else if t, err := time.Parse(time.RFC3339, row["eventTime"]); err == nil {
+		change.Time = t
+	}
+	return change
+}
+
+// changeType classifies an S3 API call (e.g., PutObject).
+func changeType(eventName string) storage.ObjectChangeType {
+	switch eventName {
+	case "PutObject", "CopyObject", "CompleteMultipartUpload":
+		return storage.ChangeWrite
+	case "DeleteObject":
+		return storage.ChangeDelete
+	case "PutObjectAcl":
+		return storage.ChangeACL
+	case "PutObjectTagging", "DeleteObjectTagging":
+		return storage.ChangeMetadata
+	default:
+		return storage.ChangeOther
+	}
+}
@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryQuery(t *testing.T) {
+	query := historyQuery("eds-1", "logs", "o'brien/report.csv", time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), 50)
+	for _, want := range []string{
+		"FROM eds-1",
+		"element_at(requestParameters, 'bucketName') = 'logs'",
+		"element_at(requestParameters, 'key') = 'o''brien/report.csv'",
+		"eventTime >= '2026-09-01 00:00:00.000'",
+		"LIMIT 50",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected the query to contain %q, got:\n%s", want, query)
+		}
+	}
+	if query := historyQuery("eds-1", "logs", "a", time.Now(), 0); strings.Contains(query, "LIMIT") {
+		t.Errorf("expected no LIMIT without a limit, got:\n%s", query)
+	}
+}
+
+func TestObjectHistory(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") || r.Header.Get("Content-Type") != "application/x-amz-json-1.1" {
+			http.Error(w, `{"__type": "MissingAuthenticationToken"}`, http.StatusForbidden)
+			return
+		}
+		var input map[string]string
+		json.NewDecoder(r.Body).Decode(&input)
+		switch strings.TrimPrefix(r.Header.Get("X-Amz-Target"), cloudTrailAPI.targetPrefix) {
+		case "ListEventDataStores":
+			io.WriteString(w, `{"EventDataStores": [
+				{"EventDataStoreArn": "arn:aws:cloudtrail:us-east-1:123456789012:eventdatastore/management"},
+				{"EventDataStoreArn": "arn:aws:cloudtrail:us-east-1:123456789012:eventdatastore/objects"}]}`)
+		case "GetEventDataStore":
+			selectors := `[{"FieldSelectors": [{"Field": "eventCategory", "Equals": ["Management"]}]}]`
+			if strings.HasSuffix(input["EventDataStore"], "/objects") {
+				selectors = `[{"FieldSelectors": [{"Field": "eventCategory", "Equals": ["Data"]}, {"Field": "resources.type", "Equals": ["AWS::S3::Object"]}]}]`
+			}
+			io.WriteString(w, `{"EventDataStoreArn": "`+input["EventDataStore"]+`", "Status": "ENABLED", "AdvancedEventSelectors": `+selectors+`}`)
+		case "StartQuery":
+			query = input["QueryStatement"]
+			io.WriteString(w, `{"QueryId": "q1"}`)
+		case "GetQueryResults":
+			io.WriteString(w, `{"QueryStatus": "FINISHED", "QueryResultRows": [
+				[{"eventTime": "2026-09-14 08:15:30.000"}, {"eventName": "DeleteObject"}, {"principal": "arn:aws:iam::123456789012:user/alice"},
+				 {"sourceIPAddress": "203.0.113.7"}, {"userAgent": "aws-cli"}, {"errorCode": "AccessDenied"}, {"errorMessage": "Access Denied"}],
+				[{"eventTime": "2026-09-01 10:00:00.000"}, {"eventName": "PutObject"}, {"principal": "arn:aws:iam::123456789012:role/etl"}]]}`)
+		default:
+			http.Error(w, `{"__type": "InvalidAction"}`, http.StatusBadRequest)
+		}
+	}))
+	s := newJSONTestStorage(t, srv)
+
+	changes, err := s.ObjectHistory(context.Background(), "data", "report.csv", time.Now().Add(-30*24*time.Hour), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(query, "FROM objects") {
+		t.Errorf("expected the store collecting object events to be queried, got:\n%s", query)
+	}
+	want := storage.ObjectChange{
+		Time:      time.Date(2026, 9, 14, 8, 15, 30, 0, time.UTC),
+		Type:      storage.ChangeDelete,
+		Operation: "DeleteObject",
+		Principal: "arn:aws:iam::123456789012:user/alice",
+		SourceIP:  "203.0.113.7",
+		UserAgent: "aws-cli",
+		Error:     "AccessDenied: Access Denied",
+	}
+	if len(changes) != 2 || changes[0] != want {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+	if changes[1].Type != storage.ChangeWrite || changes[1].Error != "" {
+		t.Errorf("expected a successful write, got %+v", changes[1])
+	}
+}
+
+func TestObjectEventStore_None(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"EventDataStores": []}`)
+	}))
+	s := newJSONTestStorage(t, srv)
+
+	if _, err := objectEventStore(context.Background(), s.newJSONClient(cloudTrailAPI, s.region)); !errors.Is(err, errNoEventDataStore) {
+		t.Errorf("expected errNoEventDataStore, got %v", err)
+	}
+}
+
+// newJSONTestStorage returns a client whose S3 and JSON protocol calls go to
+// srv, closed when the test ends.
+func newJSONTestStorage(t *testing.T, srv *httptest.Server) *AWSStorage {
+	t.Helper()
+	t.Cleanup(srv.Close)
+	s, err := NewAWSStorage(context.Background(), &config.AWSConfig{
+		Region:          "us-east-1",
+		EndpointURL:     srv.URL,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	}, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -6,11 +6,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 	"io"
 	"log/slog"
 	"strings"
-	"synkronus/internal/config"
-	"synkronus/internal/domain/storage"
 	"testing"
 	"time"
 
@@ -20,10 +20,10 @@ import (
 func newLocalStackStorage(t *testing.T) *AWSStorage {
 	t.Helper()
 	cfg := &config.AWSConfig{
-		Region:   "us-east-1",
-		Endpoint: "http://localhost:4566",
+		Region:      "us-east-1",
+		EndpointURL: "http://localhost:4566",
 	}
-	s, err := NewAWSStorage(context.Background(), cfg, slog.Default())
+	s, err := NewAWSStorage(context.Background(), cfg, 0, slog.Default())
 	if err != nil {
 		t.Fatalf("failed to create LocalStack storage: %v", err)
 	}
@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithy "github.com/aws/smithy-go"
+)
+
+// jsonAPI describes an AWS service spoken to with the JSON protocol.
+type jsonAPI struct {
+	// name is the service's name in messages (e.g., SQS)
+	name string
+	// signingName is the service's name in signatures and endpoints (e.g., sqs)
+	signingName string
+	// targetPrefix starts the X-Amz-Target header, before the action
+	targetPrefix string
+	// version is the JSON protocol version (1.0 or 1.1)
+	version string
+}
+
+// jsonClient makes the few calls synkronus needs to services other than S3,
+// with the JSON protocol and the S3 client's credentials and HTTP client,
+// rather than pulling in the SDK's module for each service.
+type jsonClient struct {
+	api         jsonAPI
+	httpClient  s3.HTTPClient
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	region      string
+	endpoint    string
+}
+
+// newJSONClient returns a client for api in region, sharing the S3 client's
+// credentials and HTTP client. A custom S3 endpoint (e.g., LocalStack) serves
+// the other services too.
+func (s *AWSStorage) newJSONClient(api jsonAPI, region string) *jsonClient {
+	opts := s.client.Options()
+	endpoint := fmt.Sprintf("https://%s.%s.amazonaws.com/", api.signingName, region)
+	if opts.BaseEndpoint != nil {
+		endpoint = *opts.BaseEndpoint
+	}
+	return &jsonClient{
+		api:         api,
+		httpClient:  opts.HTTPClient,
+		credentials: opts.Credentials,
+		signer:      v4.NewSigner(),
+		region:      region,
+		endpoint:    endpoint,
+	}
+}
+
+// jsonError is an error response of a JSON protocol service. It satisfies
+// smithy.APIError, so the error classifiers treat it like an SDK error.
+type jsonError struct {
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+	service    string
+	statusCode int
+}
+
+var _ smithy.APIError = (*jsonError)(nil)
+
+func (e *jsonError) Error() string {
+	return fmt.Sprintf("%s error %s (HTTP %d): %s", e.service, e.ErrorCode(), e.statusCode, e.Message)
+}
+
+// ErrorCode returns the error's name without the namespace of its type
+// (e.g., "AccessDeniedException" for "com.amazon.coral.service#AccessDeniedException").
+func (e *jsonError) ErrorCode() string {
+	return e.Type[strings.LastIndexByte(e.Type, '#')+1:]
+}
+
+func (e *jsonError) ErrorMessage() string {
+	return e.Message
+}
+
+func (e *jsonError) ErrorFault() smithy.ErrorFault {
+	if e.statusCode >= 500 {
+		return smithy.FaultServer
+	}
+	return smithy.FaultClient
+}
+
+// call sends an action with input as its JSON body and decodes the response
+// into output.
+func (c *jsonClient) call(ctx context.Context, action string, input, output any) error {
+	name := c.api.name
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s %s request: %w", name, action, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s %s request: %w", name, action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-"+c.api.version)
+	req.Header.Set("X-Amz-Target", c.api.targetPrefix+action)
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), c.api.signingName, c.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign %s %s request: %w", name, action, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s request failed: %w", name, action, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &jsonError{service: name, statusCode: resp.StatusCode}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Type == "" {
+			apiErr.Type, apiErr.Message = http.StatusText(resp.StatusCode), strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(output); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", name, action, err)
+	}
+	return nil
+}
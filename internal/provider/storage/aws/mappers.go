@@ -1,15 +1,31 @@
 package aws
 
 import (
-	"encoding/json"
 	"fmt"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+// mapListedObject maps an object from a ListObjectsV2 page to the domain model.
+func mapListedObject(bucketName string, obj types.Object) storage.Object {
+	o := storage.Object{
+		Key:          derefString(obj.Key),
+		Bucket:       bucketName,
+		Provider:     domain.AWS,
+		Size:         derefInt64(obj.Size),
+		StorageClass: storageClassOrDefault(string(obj.StorageClass)),
+		ETag:         derefString(obj.ETag),
+	}
+	if obj.LastModified != nil {
+		o.LastModified = *obj.LastModified
+	}
+	return o
+}
+
 func mapTags(tags []types.Tag) map[string]string {
 	if len(tags) == 0 {
 		return nil
@@ -192,99 +208,10 @@ func mapRetentionPolicy(config *types.ObjectLockConfiguration) *storage.Retentio
 	return &storage.RetentionPolicy{
 		RetentionPeriod: period,
 		IsLocked:        ret.Mode == types.ObjectLockRetentionModeCompliance,
+		Mode:            string(ret.Mode),
 	}
 }
 
-// policyDocument represents the JSON structure of an S3 bucket policy.
-type policyDocument struct {
-	Version   string            `json:"Version"`
-	Statement []policyStatement `json:"Statement"`
-}
-
-type policyStatement struct {
-	Effect    string `json:"Effect"`
-	Principal any    `json:"Principal"` // Can be string ("*") or map
-	Action    any    `json:"Action"`    // Can be string or []string
-	Resource  any    `json:"Resource"`  // Can be string or []string
-	Condition any    `json:"Condition,omitempty"`
-}
-
-func parseBucketPolicy(policyJSON string) ([]storage.PolicyStatement, error) {
-	if policyJSON == "" {
-		return nil, nil
-	}
-
-	var doc policyDocument
-	if err := json.Unmarshal([]byte(policyJSON), &doc); err != nil {
-		return nil, fmt.Errorf("failed to parse bucket policy: %w", err)
-	}
-
-	var statements []storage.PolicyStatement
-	for _, s := range doc.Statement {
-		stmt := storage.PolicyStatement{
-			Effect:     s.Effect,
-			Principals: flattenStringOrSlice(s.Principal),
-			Actions:    flattenStringOrSlice(s.Action),
-			Resources:  flattenStringOrSlice(s.Resource),
-			Conditions: flattenConditions(s.Condition),
-		}
-		statements = append(statements, stmt)
-	}
-	return statements, nil
-}
-
-// flattenStringOrSlice handles JSON fields that can be a string, a []string, or a map with a key like "AWS".
-func flattenStringOrSlice(v any) []string {
-	if v == nil {
-		return nil
-	}
-	switch val := v.(type) {
-	case string:
-		return []string{val}
-	case []any:
-		result := make([]string, 0, len(val))
-		for _, item := range val {
-			if s, ok := item.(string); ok {
-				result = append(result, s)
-			}
-		}
-		return result
-	case map[string]any:
-		// Handle {"AWS": "arn:..."} or {"AWS": ["arn:...", "arn:..."]}
-		var result []string
-		for _, sub := range val {
-			result = append(result, flattenStringOrSlice(sub)...)
-		}
-		return result
-	default:
-		return nil
-	}
-}
-
-// flattenConditions parses the Condition block of an IAM policy statement.
-func flattenConditions(v any) map[string]map[string][]string {
-	if v == nil {
-		return nil
-	}
-	raw, ok := v.(map[string]any)
-	if !ok {
-		return nil
-	}
-	result := make(map[string]map[string][]string, len(raw))
-	for operator, keysRaw := range raw {
-		keys, ok := keysRaw.(map[string]any)
-		if !ok {
-			continue
-		}
-		inner := make(map[string][]string, len(keys))
-		for key, valRaw := range keys {
-			inner[key] = flattenStringOrSlice(valRaw)
-		}
-		result[operator] = inner
-	}
-	return result
-}
-
 func derefInt32(p *int32) int32 {
 	if p == nil {
 		return 0
@@ -275,6 +275,9 @@ func TestMapRetentionPolicy_Compliance(t *testing.T) {
 	if !result.IsLocked {
 		t.Error("expected IsLocked=true for COMPLIANCE mode")
 	}
+	if result.Mode != "COMPLIANCE" {
+		t.Errorf("expected COMPLIANCE mode, got %q", result.Mode)
+	}
 	if result.RetentionPeriod.Hours() != 90*24 {
 		t.Errorf("expected 90 days, got %v", result.RetentionPeriod)
 	}
@@ -306,83 +309,6 @@ func TestMapRetentionPolicy_Nil(t *testing.T) {
 	}
 }
 
-func TestParseBucketPolicy_ValidPolicy(t *testing.T) {
-	policy := `{
-		"Version": "2012-10-17",
-		"Statement": [{
-			"Effect": "Allow",
-			"Principal": "*",
-			"Action": "s3:GetObject",
-			"Resource": "arn:aws:s3:::my-bucket/*"
-		}]
-	}`
-	result, err := parseBucketPolicy(policy)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(result) != 1 {
-		t.Fatalf("expected 1 statement, got %d", len(result))
-	}
-	stmt := result[0]
-	if stmt.Effect != "Allow" {
-		t.Errorf("expected 'Allow', got %q", stmt.Effect)
-	}
-	if len(stmt.Principals) != 1 || stmt.Principals[0] != "*" {
-		t.Errorf("expected ['*'], got %v", stmt.Principals)
-	}
-	if len(stmt.Actions) != 1 || stmt.Actions[0] != "s3:GetObject" {
-		t.Errorf("expected ['s3:GetObject'], got %v", stmt.Actions)
-	}
-}
-
-func TestParseBucketPolicy_WithConditions(t *testing.T) {
-	policy := `{
-		"Version": "2012-10-17",
-		"Statement": [{
-			"Effect": "Allow",
-			"Principal": {"AWS": "arn:aws:iam::123:root"},
-			"Action": ["s3:GetObject", "s3:PutObject"],
-			"Resource": "arn:aws:s3:::my-bucket/*",
-			"Condition": {"StringLike": {"s3:prefix": ["home/", "home/user/*"]}}
-		}]
-	}`
-	result, err := parseBucketPolicy(policy)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if len(result) != 1 {
-		t.Fatalf("expected 1 statement, got %d", len(result))
-	}
-	stmt := result[0]
-	if len(stmt.Conditions) == 0 {
-		t.Fatal("expected conditions, got none")
-	}
-	prefixes, ok := stmt.Conditions["StringLike"]["s3:prefix"]
-	if !ok {
-		t.Fatal("expected StringLike/s3:prefix condition")
-	}
-	if len(prefixes) != 2 {
-		t.Errorf("expected 2 prefix values, got %d", len(prefixes))
-	}
-}
-
-func TestParseBucketPolicy_Empty(t *testing.T) {
-	result, err := parseBucketPolicy("")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if result != nil {
-		t.Errorf("expected nil for empty policy, got %v", result)
-	}
-}
-
-func TestParseBucketPolicy_InvalidJSON(t *testing.T) {
-	_, err := parseBucketPolicy("{invalid}")
-	if err == nil {
-		t.Error("expected error for invalid JSON")
-	}
-}
-
 func TestExtractFilterPrefix_WithPrefix(t *testing.T) {
 	filter := &types.LifecycleRuleFilter{Prefix: strPtr("logs/")}
 	result := extractFilterPrefix(filter)
@@ -408,33 +334,6 @@ func TestExtractFilterPrefix_Nil(t *testing.T) {
 	}
 }
 
-func TestFlattenStringOrSlice(t *testing.T) {
-	tests := []struct {
-		name  string
-		input interface{}
-		want  []string
-	}{
-		{"nil", nil, nil},
-		{"string", "*", []string{"*"}},
-		{"slice", []interface{}{"a", "b"}, []string{"a", "b"}},
-		{"map", map[string]interface{}{"AWS": "arn:aws:iam::123:root"}, []string{"arn:aws:iam::123:root"}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := flattenStringOrSlice(tt.input)
-			if len(result) != len(tt.want) {
-				t.Errorf("expected %d items, got %d: %v", len(tt.want), len(result), result)
-				return
-			}
-			for i, v := range result {
-				if v != tt.want[i] {
-					t.Errorf("item %d: expected %q, got %q", i, tt.want[i], v)
-				}
-			}
-		})
-	}
-}
-
 func TestDerefHelpers(t *testing.T) {
 	s := "hello"
 	if derefString(&s) != "hello" {
@@ -550,46 +449,6 @@ func TestMapPublicAccessBlock_AllFalse(t *testing.T) {
 	}
 }
 
-func TestParseBucketPolicy_MultiplePrincipals(t *testing.T) {
-	policy := `{
-		"Version": "2012-10-17",
-		"Statement": [{
-			"Effect": "Deny",
-			"Principal": {"AWS": ["arn:aws:iam::111:root", "arn:aws:iam::222:root"]},
-			"Action": "s3:*",
-			"Resource": ["arn:aws:s3:::b", "arn:aws:s3:::b/*"]
-		}]
-	}`
-	result, err := parseBucketPolicy(policy)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	stmt := result[0]
-	if stmt.Effect != "Deny" {
-		t.Errorf("expected 'Deny', got %q", stmt.Effect)
-	}
-	if len(stmt.Principals) != 2 {
-		t.Errorf("expected 2 principals, got %d", len(stmt.Principals))
-	}
-	if len(stmt.Resources) != 2 {
-		t.Errorf("expected 2 resources, got %d", len(stmt.Resources))
-	}
-}
-
-func TestFlattenConditions_Nil(t *testing.T) {
-	result := flattenConditions(nil)
-	if result != nil {
-		t.Errorf("expected nil, got %v", result)
-	}
-}
-
-func TestFlattenConditions_NonMap(t *testing.T) {
-	result := flattenConditions("not a map")
-	if result != nil {
-		t.Errorf("expected nil for non-map, got %v", result)
-	}
-}
-
 func TestMapEncryption_EmptyDefault(t *testing.T) {
 	rules := []types.ServerSideEncryptionRule{
 		{ApplyServerSideEncryptionByDefault: nil},
@@ -3,13 +3,17 @@ package aws
 import (
 	"context"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
 	"io"
+	"maps"
 	"net/url"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/storage/shared"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 func (s *AWSStorage) ListObjects(ctx context.Context, bucketName string, prefix string) (storage.ObjectList, error) {
@@ -41,30 +45,41 @@ func (s *AWSStorage) ListObjects(ctx context.Context, bucketName string, prefix
 		}
 
 		for _, obj := range page.Contents {
-			o := storage.Object{
-				Key:          derefString(obj.Key),
-				Bucket:       bucketName,
-				Provider:     domain.AWS,
-				Size:         derefInt64(obj.Size),
-				StorageClass: storageClassOrDefault(string(obj.StorageClass)),
-				ETag:         derefString(obj.ETag),
-			}
-			if obj.LastModified != nil {
-				o.LastModified = *obj.LastModified
-			}
-			result.Objects = append(result.Objects, o)
+			result.Objects = append(result.Objects, mapListedObject(bucketName, obj))
 		}
 	}
 
 	return result, nil
 }
 
+func (s *AWSStorage) WalkObjects(ctx context.Context, bucketName string, prefix string, fn func(storage.Object) error) error {
+	s.logger.Debug("Starting AWS WalkObjects operation", "bucket", bucketName, "prefix", prefix)
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: &bucketName,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if err := fn(mapListedObject(bucketName, obj)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (s *AWSStorage) DescribeObject(ctx context.Context, bucketName string, objectKey string) (storage.Object, error) {
 	s.logger.Debug("Starting AWS DescribeObject operation", "bucket", bucketName, "object", objectKey)
 
 	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: &bucketName,
-		Key:    &objectKey,
+		Bucket:       &bucketName,
+		Key:          &objectKey,
+		ChecksumMode: types.ChecksumModeEnabled,
 	})
 	if err != nil {
 		return storage.Object{}, fmt.Errorf("failed to describe S3 object: %w", err)
@@ -84,12 +99,31 @@ func (s *AWSStorage) DescribeObject(ctx context.Context, bucketName string, obje
 		ContentDisposition: derefString(out.ContentDisposition),
 		VersionID:          derefString(out.VersionId),
 		Metadata:           out.Metadata,
+		CRC32:              derefString(out.ChecksumCRC32),
+		CRC32C:             derefString(out.ChecksumCRC32C),
+		SHA256:             derefString(out.ChecksumSHA256),
+		ChecksumType:       string(out.ChecksumType),
 	}
 
 	if out.LastModified != nil {
 		obj.LastModified = *out.LastModified
 	}
 
+	// A composite checksum can only be recomputed knowing the part size, which
+	// is the size of the first part
+	if obj.ChecksumType == storage.ChecksumTypeComposite {
+		part, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:     &bucketName,
+			Key:        &objectKey,
+			VersionId:  out.VersionId,
+			PartNumber: aws.Int32(1),
+		})
+		if err != nil {
+			return storage.Object{}, fmt.Errorf("failed to describe the parts of S3 object: %w", err)
+		}
+		obj.PartSize = derefInt64(part.ContentLength)
+	}
+
 	// Map encryption
 	if out.ServerSideEncryption != "" {
 		obj.Encryption = &storage.Encryption{
@@ -136,6 +170,17 @@ func (s *AWSStorage) UploadObject(ctx context.Context, opts storage.UploadObject
 	if len(opts.Metadata) > 0 {
 		input.Metadata = opts.Metadata
 	}
+	input.CacheControl = optionalString(opts.CacheControl)
+	input.ContentDisposition = optionalString(opts.ContentDisposition)
+	input.ContentEncoding = optionalString(opts.ContentEncoding)
+	input.ContentLanguage = optionalString(opts.ContentLanguage)
+	if opts.ChecksumAlgorithm != "" {
+		algorithm, err := checksumAlgorithm(opts.ChecksumAlgorithm)
+		if err != nil {
+			return err
+		}
+		input.ChecksumAlgorithm = algorithm
+	}
 
 	if _, err := s.client.PutObject(ctx, input); err != nil {
 		return fmt.Errorf("uploading object %s to bucket %s: %w", opts.ObjectKey, opts.BucketName, err)
@@ -143,6 +188,23 @@ func (s *AWSStorage) UploadObject(ctx context.Context, opts storage.UploadObject
 	return nil
 }
 
+// checksumAlgorithm returns the S3 additional checksum for an algorithm. The
+// SDK computes it as the body is sent, and S3 rejects the upload if the data
+// it received does not match.
+func checksumAlgorithm(algorithm string) (types.ChecksumAlgorithm, error) {
+	switch strings.ToUpper(algorithm) {
+	case storage.ChecksumCRC32:
+		return types.ChecksumAlgorithmCrc32, nil
+	case storage.ChecksumCRC32C:
+		return types.ChecksumAlgorithmCrc32c, nil
+	case storage.ChecksumSHA256:
+		return types.ChecksumAlgorithmSha256, nil
+	default:
+		return "", fmt.Errorf("S3 does not support %s checksums on upload (expected %s, %s or %s)",
+			algorithm, storage.ChecksumCRC32, storage.ChecksumCRC32C, storage.ChecksumSHA256)
+	}
+}
+
 func (s *AWSStorage) DeleteObject(ctx context.Context, bucketName, objectKey string) error {
 	s.logger.Debug("Starting AWS DeleteObject operation", "bucket", bucketName, "key", objectKey)
 
@@ -187,3 +249,90 @@ func derefInt64(p *int64) int64 {
 	}
 	return *p
 }
+
+var _ storage.MetadataUpdater = (*AWSStorage)(nil)
+
+// UpdateObjectMetadata copies the object onto itself with the merged metadata,
+// since S3 metadata cannot be changed in place. The copy keeps the object's
+// content headers, storage class and encryption, and gets a new version in a
+// versioned bucket. S3 copies objects of up to 5 GB this way.
+func (s *AWSStorage) UpdateObjectMetadata(ctx context.Context, bucketName, objectKey string, metadata map[string]string) error {
+	s.logger.Debug("Starting AWS UpdateObjectMetadata operation", "bucket", bucketName, "key", objectKey)
+
+	err := s.rewriteObject(ctx, bucketName, objectKey, func(head *s3.HeadObjectOutput, input *s3.CopyObjectInput) {
+		merged := maps.Clone(head.Metadata)
+		if merged == nil {
+			merged = make(map[string]string, len(metadata))
+		}
+		maps.Copy(merged, metadata)
+		input.Metadata = merged
+	})
+	if err != nil {
+		return fmt.Errorf("updating metadata of object %s in bucket %s: %w", objectKey, bucketName, err)
+	}
+	return nil
+}
+
+var _ storage.AttributesSetter = (*AWSStorage)(nil)
+
+// SetObjectAttributes copies the object onto itself with the given headers
+// and metadata, as UpdateObjectMetadata does.
+func (s *AWSStorage) SetObjectAttributes(ctx context.Context, bucketName, objectKey string, attrs storage.ObjectAttributes) error {
+	s.logger.Debug("Starting AWS SetObjectAttributes operation", "bucket", bucketName, "key", objectKey)
+
+	err := s.rewriteObject(ctx, bucketName, objectKey, func(_ *s3.HeadObjectOutput, input *s3.CopyObjectInput) {
+		input.Metadata = attrs.Metadata
+		input.ContentType = optionalString(attrs.ContentType)
+		input.ContentEncoding = optionalString(attrs.ContentEncoding)
+		input.ContentLanguage = optionalString(attrs.ContentLanguage)
+		input.ContentDisposition = optionalString(attrs.ContentDisposition)
+		input.CacheControl = optionalString(attrs.CacheControl)
+	})
+	if err != nil {
+		return fmt.Errorf("setting attributes of object %s in bucket %s: %w", objectKey, bucketName, err)
+	}
+	return nil
+}
+
+// rewriteObject copies the object onto itself, replacing its metadata. The
+// copy starts with the object's current headers, metadata, storage class and
+// encryption, which edit may change. The copy fails if the object changed
+// since it was read.
+func (s *AWSStorage) rewriteObject(ctx context.Context, bucketName, objectKey string, edit func(head *s3.HeadObjectOutput, input *s3.CopyObjectInput)) error {
+	head, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &bucketName,
+		Key:    &objectKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe S3 object: %w", err)
+	}
+
+	copySource := bucketName + "/" + url.PathEscape(objectKey)
+	input := &s3.CopyObjectInput{
+		Bucket:               &bucketName,
+		Key:                  &objectKey,
+		CopySource:           &copySource,
+		CopySourceIfMatch:    head.ETag,
+		MetadataDirective:    types.MetadataDirectiveReplace,
+		Metadata:             head.Metadata,
+		ContentType:          head.ContentType,
+		ContentEncoding:      head.ContentEncoding,
+		ContentLanguage:      head.ContentLanguage,
+		ContentDisposition:   head.ContentDisposition,
+		CacheControl:         head.CacheControl,
+		StorageClass:         types.StorageClass(head.StorageClass),
+		ServerSideEncryption: head.ServerSideEncryption,
+		SSEKMSKeyId:          head.SSEKMSKeyId,
+	}
+	edit(head, input)
+	_, err = s.client.CopyObject(ctx, input)
+	return err
+}
+
+// optionalString returns nil for an empty string, so the SDK omits the field.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
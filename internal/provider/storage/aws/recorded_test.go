@@ -0,0 +1,119 @@
+package aws
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/network/vcr"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Placeholders for the account and bucket the fixtures were recorded against.
+// The bucket must be in vcrRegion, which is part of the recorded URLs.
+const (
+	vcrAccountID = "123456789012"
+	vcrBucket    = "synkronus-vcr-bucket"
+	vcrRegion    = "eu-west-1"
+)
+
+// newRecordedStorage returns a client whose S3 requests are answered from
+// testdata/<fixture>.json. To record, set SYNKRONUS_VCR=record,
+// SYNKRONUS_VCR_AWS_BUCKET and SYNKRONUS_VCR_AWS_ACCOUNT_ID, with credentials
+// from the default chain that can read the bucket's configuration.
+func newRecordedStorage(t *testing.T, fixture string) *AWSStorage {
+	t.Helper()
+
+	sdkCfg := aws.Config{
+		Region:      vcrRegion,
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	}
+	var replacements []string
+	if vcr.Recording() {
+		bucket, accountID := os.Getenv("SYNKRONUS_VCR_AWS_BUCKET"), os.Getenv("SYNKRONUS_VCR_AWS_ACCOUNT_ID")
+		if bucket == "" || accountID == "" {
+			t.Fatal("recording needs SYNKRONUS_VCR_AWS_BUCKET and SYNKRONUS_VCR_AWS_ACCOUNT_ID")
+		}
+		var err error
+		sdkCfg, err = loadSDKConfig(context.Background(), &config.AWSConfig{Region: vcrRegion})
+		if err != nil {
+			t.Fatalf("failed to load AWS config: %v", err)
+		}
+		replacements = []string{bucket, vcrBucket, accountID, vcrAccountID}
+	}
+
+	recorder := vcr.New(t, filepath.Join("testdata", fixture+".json"), sdkCfg.HTTPClient, replacements...)
+	client := s3.NewFromConfig(sdkCfg, func(o *s3.Options) {
+		o.HTTPClient = recorder
+	})
+
+	return &AWSStorage{
+		client: client,
+		region: vcrRegion,
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func TestDescribeBucket_Recorded(t *testing.T) {
+	s := newRecordedStorage(t, "describe_bucket")
+
+	bucket, err := s.DescribeBucket(context.Background(), vcrBucket)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if bucket.Location != vcrRegion {
+		t.Errorf("location = %q, want %q", bucket.Location, vcrRegion)
+	}
+	if bucket.Versioning == nil || !bucket.Versioning.Enabled {
+		t.Error("expected versioning enabled")
+	}
+	if bucket.Encryption == nil || bucket.Encryption.Algorithm != "aws:kms" ||
+		bucket.Encryption.KmsKeyName != "arn:aws:kms:eu-west-1:123456789012:alias/synkronus" {
+		t.Errorf("unexpected encryption: %+v", bucket.Encryption)
+	}
+
+	wantRules := []storage.LifecycleRule{
+		{Action: "Delete", Condition: storage.LifecycleCondition{Age: 365, Prefix: "logs/"}},
+		{Action: "Transition to STANDARD_IA", Condition: storage.LifecycleCondition{Age: 30, Prefix: "logs/"}},
+		{Action: "Transition to GLACIER", Condition: storage.LifecycleCondition{Age: 90, Prefix: "logs/"}},
+		{Action: "Delete", Condition: storage.LifecycleCondition{Age: 30, NumNewerVersions: 2}},
+	}
+	if !slices.EqualFunc(bucket.LifecycleRules, wantRules, func(a, b storage.LifecycleRule) bool {
+		return a.Action == b.Action && a.Condition.Age == b.Condition.Age &&
+			a.Condition.Prefix == b.Condition.Prefix && a.Condition.NumNewerVersions == b.Condition.NumNewerVersions
+	}) {
+		t.Errorf("lifecycle rules = %+v, want %+v", bucket.LifecycleRules, wantRules)
+	}
+
+	if bucket.IAMPolicy == nil || len(bucket.IAMPolicy.Statements) != 2 {
+		t.Fatalf("expected 2 policy statements, got %+v", bucket.IAMPolicy)
+	}
+	deny := bucket.IAMPolicy.Statements[1]
+	if deny.Effect != "Deny" || !slices.Equal(deny.Principals, []string{"*"}) || len(deny.Conditions) == 0 {
+		t.Errorf("expected the conditional deny statement, got %+v", deny)
+	}
+
+	if len(bucket.ACLs) != 1 || bucket.ACLs[0].Role != "FULL_CONTROL" {
+		t.Errorf("expected the owner's full control grant, got %+v", bucket.ACLs)
+	}
+	if bucket.PublicAccessPrevention != shared.PublicAccessEnforced {
+		t.Errorf("public access prevention = %q, want %q", bucket.PublicAccessPrevention, shared.PublicAccessEnforced)
+	}
+	if bucket.Logging == nil || bucket.Logging.LogBucket != vcrBucket+"-logs" || bucket.Logging.LogObjectPrefix != "s3/" {
+		t.Errorf("unexpected logging: %+v", bucket.Logging)
+	}
+	// Tags and object lock are not configured; the 404s must not be errors
+	if bucket.Labels != nil || bucket.RetentionPolicy != nil {
+		t.Errorf("expected no tags or retention policy, got %v and %+v", bucket.Labels, bucket.RetentionPolicy)
+	}
+}
@@ -0,0 +1,97 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"net/http"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+var (
+	_ storage.PostPolicySigner = (*AWSStorage)(nil)
+	_ storage.URLSigner        = (*AWSStorage)(nil)
+)
+
+// SignPostPolicy presigns an S3 POST with the caller's credentials. Policies
+// signed with temporary credentials (SSO, assumed roles) stop working when
+// the credentials expire, even before opts.Expires.
+func (s *AWSStorage) SignPostPolicy(ctx context.Context, opts storage.PostPolicyOptions) (storage.PostPolicy, error) {
+	s.logger.Debug("Starting AWS SignPostPolicy operation", "bucket", opts.BucketName, "prefix", opts.Prefix)
+
+	key := opts.Prefix + "${filename}"
+	conditions := []any{[]any{"starts-with", "$key", opts.Prefix}}
+	if opts.MaxSize > 0 {
+		conditions = append(conditions, []any{"content-length-range", 0, opts.MaxSize})
+	}
+	expires := time.Now().Add(opts.Expires).UTC()
+	req, err := s3.NewPresignClient(s.client).PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: &opts.BucketName,
+		Key:    &key,
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = opts.Expires
+		o.Conditions = conditions
+	})
+	if err != nil {
+		return storage.PostPolicy{}, fmt.Errorf("signing POST policy for bucket %s: %w", opts.BucketName, err)
+	}
+	return storage.PostPolicy{URL: req.URL, Fields: req.Values, Expires: expires}, nil
+}
+
+// SignURL presigns a request for an object with the caller's credentials,
+// which bound the URL's lifetime like they do a POST policy's.
+func (s *AWSStorage) SignURL(ctx context.Context, opts storage.SignedURLOptions) (storage.SignedURL, error) {
+	s.logger.Debug("Starting AWS SignURL operation", "bucket", opts.BucketName, "key", opts.ObjectKey, "method", opts.Method)
+
+	presigner := s3.NewPresignClient(s.client, func(o *s3.PresignOptions) {
+		o.Expires = opts.Expires
+		o.ClientOptions = append(o.ClientOptions, func(o *s3.Options) {
+			switch opts.Style {
+			case storage.URLStylePath:
+				o.UsePathStyle = true
+			case storage.URLStyleVirtualHosted:
+				o.UsePathStyle = false
+			}
+			for name, value := range opts.Headers {
+				o.APIOptions = append(o.APIOptions, smithyhttp.SetHeaderValue(name, value))
+			}
+		})
+	})
+
+	expires := time.Now().Add(opts.Expires).UTC()
+	var req *v4.PresignedHTTPRequest
+	var err error
+	switch strings.ToUpper(opts.Method) {
+	case http.MethodGet:
+		input := &s3.GetObjectInput{Bucket: &opts.BucketName, Key: &opts.ObjectKey}
+		if opts.ResponseContentDisposition != "" {
+			input.ResponseContentDisposition = &opts.ResponseContentDisposition
+		}
+		req, err = presigner.PresignGetObject(ctx, input)
+	case http.MethodPut:
+		req, err = presigner.PresignPutObject(ctx, &s3.PutObjectInput{Bucket: &opts.BucketName, Key: &opts.ObjectKey})
+	case http.MethodHead:
+		req, err = presigner.PresignHeadObject(ctx, &s3.HeadObjectInput{Bucket: &opts.BucketName, Key: &opts.ObjectKey})
+	case http.MethodDelete:
+		req, err = presigner.PresignDeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &opts.BucketName, Key: &opts.ObjectKey})
+	default:
+		return storage.SignedURL{}, fmt.Errorf("cannot sign %s requests", opts.Method)
+	}
+	if err != nil {
+		return storage.SignedURL{}, fmt.Errorf("signing URL for object %s in bucket %s: %w", opts.ObjectKey, opts.BucketName, err)
+	}
+
+	// The host is part of the URL; any other signed header must be sent
+	headers := make(map[string]string)
+	for name, values := range req.SignedHeader {
+		if !strings.EqualFold(name, "Host") && len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+	return storage.SignedURL{URL: req.URL, Method: req.Method, Headers: headers, Expires: expires}, nil
+}
@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSigningTestStorage(t *testing.T) *AWSStorage {
+	t.Helper()
+	s, err := NewAWSStorage(context.Background(), &config.AWSConfig{
+		Region:          "us-east-1",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	}, 0, slog.Default())
+	if err != nil {
+		t.Fatalf("failed to create test storage: %v", err)
+	}
+	return s
+}
+
+func TestSignURL(t *testing.T) {
+	s := newSigningTestStorage(t)
+	signed, err := s.SignURL(context.Background(), storage.SignedURLOptions{
+		BucketName:                 "reports",
+		ObjectKey:                  "2024/q1.pdf",
+		Method:                     "get",
+		Expires:                    time.Hour,
+		ResponseContentDisposition: `attachment; filename="q1.pdf"`,
+		Style:                      storage.URLStylePath,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	u, err := url.Parse(signed.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "s3.us-east-1.amazonaws.com" || !strings.HasPrefix(u.Path, "/reports/2024/q1.pdf") {
+		t.Errorf("expected a path-style URL, got %s", signed.URL)
+	}
+	q := u.Query()
+	if q.Get("response-content-disposition") != `attachment; filename="q1.pdf"` {
+		t.Errorf("expected the disposition override in the query, got %s", signed.URL)
+	}
+	if q.Get("X-Amz-Expires") != "3600" || q.Get("X-Amz-Signature") == "" {
+		t.Errorf("expected a SigV4 signature valid for an hour, got %s", signed.URL)
+	}
+	if signed.Method != "GET" {
+		t.Errorf("expected method GET, got %s", signed.Method)
+	}
+}
+
+func TestSignURL_HeadersMustBeSent(t *testing.T) {
+	s := newSigningTestStorage(t)
+	signed, err := s.SignURL(context.Background(), storage.SignedURLOptions{
+		BucketName: "uploads",
+		ObjectKey:  "a.json",
+		Method:     "PUT",
+		Expires:    time.Minute,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Style:      storage.URLStyleVirtualHosted,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(signed.URL, "https://uploads.s3.us-east-1.amazonaws.com/a.json?") {
+		t.Errorf("expected a virtual-hosted URL, got %s", signed.URL)
+	}
+	if signed.Headers["Content-Type"] != "application/json" {
+		t.Errorf("expected Content-Type among the headers to send, got %v", signed.Headers)
+	}
+	if _, ok := signed.Headers["Host"]; ok {
+		t.Error("expected the host left out of the headers to send")
+	}
+}
+
+func TestSignURL_UnsupportedMethod(t *testing.T) {
+	_, err := newSigningTestStorage(t).SignURL(context.Background(), storage.SignedURLOptions{BucketName: "b", ObjectKey: "k", Method: "POST", Expires: time.Minute})
+	if err == nil {
+		t.Error("expected an error for a POST URL")
+	}
+}
@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"context"
+	"time"
+)
+
+// sqsAPI is the SQS JSON protocol
+var sqsAPI = jsonAPI{name: "SQS", signingName: "sqs", targetPrefix: "AmazonSQS.", version: "1.0"}
+
+// sqsClient makes the few SQS calls TailEvents needs.
+type sqsClient struct {
+	*jsonClient
+}
+
+// newSQSClient returns a client for SQS in region.
+func (s *AWSStorage) newSQSClient(region string) *sqsClient {
+	return &sqsClient{s.newJSONClient(sqsAPI, region)}
+}
+
+// queueURL resolves the URL of the queue named name owned by account.
+func (c *sqsClient) queueURL(ctx context.Context, name, account string) (string, error) {
+	var out struct {
+		QueueURL string `json:"QueueUrl"`
+	}
+	err := c.call(ctx, "GetQueueUrl", map[string]string{"QueueName": name, "QueueOwnerAWSAccountId": account}, &out)
+	return out.QueueURL, err
+}
+
+// sqsMessage is a received SQS message.
+type sqsMessage struct {
+	MessageID string `json:"MessageId"`
+	Body      string `json:"Body"`
+}
+
+// peek receives up to 10 messages from the queue without hiding them from
+// other consumers: with a visibility timeout of 0 they stay available to the
+// queue's own consumers, which remain responsible for deleting them. It waits
+// up to wait for a message to arrive.
+func (c *sqsClient) peek(ctx context.Context, queueURL string, wait time.Duration) ([]sqsMessage, error) {
+	var out struct {
+		Messages []sqsMessage `json:"Messages"`
+	}
+	err := c.call(ctx, "ReceiveMessage", map[string]any{
+		"QueueUrl":            queueURL,
+		"MaxNumberOfMessages": 10,
+		"VisibilityTimeout":   0,
+		"WaitTimeSeconds":     int(wait.Seconds()),
+	}, &out)
+	return out.Messages, err
+}
@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+var _ storage.VersionManager = (*AWSStorage)(nil)
+
+func (s *AWSStorage) WalkObjectVersions(ctx context.Context, bucketName, prefix string, fn func(storage.ObjectVersion) error) error {
+	s.logger.Debug("Starting AWS WalkObjectVersions operation", "bucket", bucketName, "prefix", prefix)
+
+	paginator := s3.NewListObjectVersionsPaginator(s.client, &s3.ListObjectVersionsInput{
+		Bucket: &bucketName,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list S3 object versions: %w", err)
+		}
+		for _, v := range mapVersionPage(page) {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mapVersionPage merges a page's versions and delete markers, which S3 lists
+// apart, back into key order, newest first within a key. Pages follow each
+// other in that order, so the walk as a whole stays in it.
+func mapVersionPage(page *s3.ListObjectVersionsOutput) []storage.ObjectVersion {
+	versions := make([]storage.ObjectVersion, 0, len(page.Versions)+len(page.DeleteMarkers))
+	for _, v := range page.Versions {
+		mapped := storage.ObjectVersion{
+			Key:          derefString(v.Key),
+			VersionID:    derefString(v.VersionId),
+			Size:         derefInt64(v.Size),
+			StorageClass: storageClassOrDefault(string(v.StorageClass)),
+			IsLatest:     derefBool(v.IsLatest),
+		}
+		if v.LastModified != nil {
+			mapped.LastModified = *v.LastModified
+		}
+		versions = append(versions, mapped)
+	}
+	for _, m := range page.DeleteMarkers {
+		mapped := storage.ObjectVersion{
+			Key:          derefString(m.Key),
+			VersionID:    derefString(m.VersionId),
+			IsLatest:     derefBool(m.IsLatest),
+			DeleteMarker: true,
+		}
+		if m.LastModified != nil {
+			mapped.LastModified = *m.LastModified
+		}
+		versions = append(versions, mapped)
+	}
+	slices.SortStableFunc(versions, func(a, b storage.ObjectVersion) int {
+		if c := cmp.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		// Versions written within the same second sort by which is current
+		if a.IsLatest != b.IsLatest {
+			if a.IsLatest {
+				return -1
+			}
+			return 1
+		}
+		return b.LastModified.Compare(a.LastModified)
+	})
+	return versions
+}
+
+func (s *AWSStorage) DeleteObjectVersion(ctx context.Context, bucketName, objectKey, versionID string) error {
+	s.logger.Debug("Starting AWS DeleteObjectVersion operation", "bucket", bucketName, "key", objectKey, "version", versionID)
+
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:    &bucketName,
+		Key:       &objectKey,
+		VersionId: &versionID,
+	}); err != nil {
+		return fmt.Errorf("deleting version %s of object %s from bucket %s: %w", versionID, objectKey, bucketName, err)
+	}
+	return nil
+}
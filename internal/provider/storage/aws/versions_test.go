@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestMapVersionPage(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	page := &s3.ListObjectVersionsOutput{
+		Versions: []types.ObjectVersion{
+			{Key: aws.String("a.txt"), VersionId: aws.String("a1"), Size: aws.Int64(5), LastModified: aws.Time(t0)},
+			{Key: aws.String("b.txt"), VersionId: aws.String("b1"), Size: aws.Int64(7), LastModified: aws.Time(t0), IsLatest: aws.Bool(true)},
+		},
+		DeleteMarkers: []types.DeleteMarkerEntry{
+			{Key: aws.String("a.txt"), VersionId: aws.String("a2"), LastModified: aws.Time(t0.Add(time.Hour)), IsLatest: aws.Bool(true)},
+		},
+	}
+
+	versions := mapVersionPage(page)
+	var ids []string
+	for _, v := range versions {
+		ids = append(ids, v.VersionID)
+	}
+	if len(ids) != 3 || ids[0] != "a2" || ids[1] != "a1" || ids[2] != "b1" {
+		t.Fatalf("expected versions by key, newest first, got %v", ids)
+	}
+	if !versions[0].DeleteMarker || !versions[0].IsLatest {
+		t.Errorf("expected the current delete marker first, got %+v", versions[0])
+	}
+	if versions[1].Size != 5 || versions[1].StorageClass != "STANDARD" {
+		t.Errorf("unexpected version: %+v", versions[1])
+	}
+}
@@ -0,0 +1,48 @@
+package gcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+
+	cloudasset "google.golang.org/api/cloudasset/v1"
+)
+
+func TestBucketFromAsset(t *testing.T) {
+	bucket := bucketFromAsset(&cloudasset.ResourceSearchResult{
+		Name:                   "//storage.googleapis.com/team-logs",
+		AssetType:              bucketAssetType,
+		Project:                "projects/123456789",
+		ParentFullResourceName: "//cloudresourcemanager.googleapis.com/projects/team-prod",
+		Location:               "us-central1",
+		Labels:                 map[string]string{"team": "data"},
+		CreateTime:             "2024-03-01T09:30:00Z",
+	})
+
+	if bucket.Name != "team-logs" || bucket.Provider != domain.GCP {
+		t.Errorf("unexpected bucket: %+v", bucket)
+	}
+	if bucket.Project != "team-prod" {
+		t.Errorf("expected the project ID of the parent, got %q", bucket.Project)
+	}
+	if bucket.Location != "US-CENTRAL1" || bucket.Labels["team"] != "data" {
+		t.Errorf("unexpected location or labels: %+v", bucket)
+	}
+	if !bucket.CreatedAt.Equal(time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected creation time: %v", bucket.CreatedAt)
+	}
+	if bucket.UsageBytes != -1 {
+		t.Errorf("expected unknown usage, got %d", bucket.UsageBytes)
+	}
+}
+
+func TestBucketFromAsset_NoParentFallsBackToProjectNumber(t *testing.T) {
+	bucket := bucketFromAsset(&cloudasset.ResourceSearchResult{
+		Name:    "//storage.googleapis.com/team-logs",
+		Project: "projects/123456789",
+	})
+	if bucket.Project != "123456789" {
+		t.Errorf("expected the project number, got %q", bucket.Project)
+	}
+}
@@ -5,32 +5,59 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/retry"
 	"log/slog"
+	"net/http"
 	"slices"
+	"strconv"
 	"strings"
-	"net/http"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
+	"sync"
 
 	gcpstorage "cloud.google.com/go/storage"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
+	storagev1 "google.golang.org/api/storage/v1"
 )
 
 func (g *GCPStorage) ListBuckets(ctx context.Context) ([]storage.Bucket, error) {
-	g.logger.Debug("Starting GCP ListBuckets operation")
+	g.logger.Debug("Starting GCP ListBuckets operation", "projects", g.projectIDs)
+
+	// Each project is listed concurrently; results are kept in configured project
+	// order. A failed project does not stop the others: their buckets are
+	// returned along with the failures.
+	results := make([][]storage.Bucket, len(g.projectIDs))
+	errs := make([]error, len(g.projectIDs))
+	var wg sync.WaitGroup
+	for i, projectID := range g.projectIDs {
+		wg.Go(func() {
+			buckets, err := g.listProjectBuckets(ctx, projectID)
+			if err != nil && len(g.projectIDs) > 1 {
+				err = fmt.Errorf("project %s: %w", projectID, err)
+			}
+			results[i], errs[i] = buckets, err
+		})
+	}
+	wg.Wait()
+
+	return slices.Concat(results...), errors.Join(errs...)
+}
+
+// listProjectBuckets lists the buckets of a single project, annotated with their usage.
+func (g *GCPStorage) listProjectBuckets(ctx context.Context, projectID string) ([]storage.Bucket, error) {
 	var buckets []storage.Bucket
 
-	// 1. Fetch usage metrics for all buckets first (O(1) API calls)
-	usageMap, err := g.getAllBucketUsages(ctx)
+	// 1. Fetch usage metrics for all buckets first (O(1) API calls, or none if cached)
+	usageMap, err := g.getCachedBucketUsages(ctx, projectID)
 	if err != nil {
 		// Propagate the error if metrics cannot be retrieved. The caller (StorageService) will handle this
 		return nil, fmt.Errorf("failed to retrieve GCP bucket usage metrics: %w", err)
 	}
 
 	// 2. Fetch bucket metadata (O(N) API calls, paginated by SDK)
-	it := g.client.Buckets(ctx, g.projectID)
+	it := g.client.Buckets(ctx, projectID)
 	for {
 		bucketAttrs, err := it.Next()
 		if errors.Is(err, iterator.Done) {
@@ -49,6 +76,7 @@ func (g *GCPStorage) ListBuckets(ctx context.Context) ([]storage.Bucket, error)
 		buckets = append(buckets, storage.Bucket{
 			Name:         bucketAttrs.Name,
 			Provider:     domain.GCP,
+			Project:      projectID,
 			Location:     bucketAttrs.Location,
 			StorageClass: bucketAttrs.StorageClass,
 			CreatedAt:    bucketAttrs.Created,
@@ -65,13 +93,13 @@ func (g *GCPStorage) DescribeBucket(ctx context.Context, bucketName string) (sto
 	g.logger.Debug("Starting GCP DescribeBucket operation", "bucket", bucketName)
 
 	bucketHandle := g.client.Bucket(bucketName)
-	attrs, err := bucketHandle.Attrs(ctx)
-	if err != nil {
-		return storage.Bucket{}, fmt.Errorf("error getting bucket attributes: %w", err)
-	}
 
-	// Fetch supplementary data concurrently — each is best-effort.
+	// Attributes, usage, ACLs and the IAM policy are fetched concurrently. Only
+	// the attributes are required; the rest is best-effort and left empty on
+	// failure. Usage is queried in the bucket's own project, so it waits for the
+	// attributes within the same goroutine.
 	var (
+		attrs     *gcpstorage.BucketAttrs
 		usage     int64 = -1
 		aclRules  []storage.ACLRule
 		iamPolicy *storage.IAMPolicy
@@ -80,7 +108,13 @@ func (g *GCPStorage) DescribeBucket(ctx context.Context, bucketName string) (sto
 	eg, egCtx := errgroup.WithContext(ctx)
 
 	eg.Go(func() error {
-		u, err := g.getSingleBucketUsage(egCtx, bucketName)
+		a, err := bucketHandle.Attrs(egCtx)
+		if err != nil {
+			return fmt.Errorf("error getting bucket attributes: %w", err)
+		}
+		attrs = a
+
+		u, err := g.getSingleBucketUsage(egCtx, metricsProject(attrs, g.projectID), bucketName)
 		if err != nil {
 			logLevel := slog.LevelWarn
 			logMsg := "Failed to retrieve usage metrics due to API error, usage will be reported as N/A"
@@ -100,7 +134,10 @@ func (g *GCPStorage) DescribeBucket(ctx context.Context, bucketName string) (sto
 	eg.Go(func() error {
 		acls, err := g.getACLs(egCtx, bucketHandle)
 		if err != nil {
-			g.logger.Warn("Could not retrieve ACLs for bucket", "bucket", bucketName, "error", err)
+			// A canceled context means the attributes failed, which is the error reported
+			if egCtx.Err() == nil {
+				g.logger.Warn("Could not retrieve ACLs for bucket", "bucket", bucketName, "error", err)
+			}
 			return nil
 		}
 		aclRules = acls
@@ -108,16 +145,20 @@ func (g *GCPStorage) DescribeBucket(ctx context.Context, bucketName string) (sto
 	})
 
 	eg.Go(func() error {
-		iam, err := g.getIAMPolicy(egCtx, bucketHandle)
+		iam, err := g.getIAMPolicy(egCtx, bucketName)
 		if err != nil {
-			g.logger.Warn("Could not retrieve IAM policy for bucket. Requires 'storage.buckets.getIamPolicy' permission.", "bucket", bucketName, "error", err)
+			if egCtx.Err() == nil {
+				g.logger.Warn("Could not retrieve IAM policy for bucket. Requires 'storage.buckets.getIamPolicy' permission.", "bucket", bucketName, "error", err)
+			}
 			return nil
 		}
 		iamPolicy = iam
 		return nil
 	})
 
-	eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return storage.Bucket{}, err
+	}
 
 	details := storage.Bucket{
 		Name:                     attrs.Name,
@@ -130,7 +171,7 @@ func (g *GCPStorage) DescribeBucket(ctx context.Context, bucketName string) (sto
 		UsageBytes:               usage,
 		RequesterPays:            attrs.RequesterPays,
 		Labels:                   attrs.Labels,
-		Autoclass:                &storage.Autoclass{Enabled: attrs.Autoclass.Enabled},
+		Autoclass:                mapAutoclass(attrs.Autoclass),
 		IAMPolicy:                iamPolicy,
 		ACLs:                     aclRules,
 		LifecycleRules:           mapLifecycleRules(attrs.Lifecycle.Rules),
@@ -146,9 +187,23 @@ func (g *GCPStorage) DescribeBucket(ctx context.Context, bucketName string) (sto
 	return details, nil
 }
 
-// getIAMPolicy fetches the bucket's IAM policy (using V3) and maps it to the domain model.
-func (g *GCPStorage) getIAMPolicy(ctx context.Context, bucketHandle *gcpstorage.BucketHandle) (*storage.IAMPolicy, error) {
-	policy, err := bucketHandle.IAM().V3().Policy(ctx)
+// metricsProject returns the project to query usage metrics in. The bucket's own
+// project number is preferred since the bucket may belong to any configured
+// project; the primary project is the fallback.
+func metricsProject(attrs *gcpstorage.BucketAttrs, fallback string) string {
+	if attrs.ProjectNumber != 0 {
+		return strconv.FormatUint(attrs.ProjectNumber, 10)
+	}
+	return fallback
+}
+
+// getIAMPolicy fetches the bucket's IAM policy (using V3) and maps it to the
+// domain model. It goes through the JSON API, as the storage client's IAM
+// handle does not expose the policy's etag.
+func (g *GCPStorage) getIAMPolicy(ctx context.Context, bucketName string) (*storage.IAMPolicy, error) {
+	policy, err := retry.Do(ctx, retry.WithMaxRetries(g.maxRetries), isRetryable, g.logger, func() (*storagev1.Policy, error) {
+		return g.api.Buckets.GetIamPolicy(bucketName).OptionsRequestedPolicyVersion(3).Context(ctx).Do()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get V3 IAM policy: %w", err)
 	}
@@ -179,6 +234,7 @@ func (g *GCPStorage) getIAMPolicy(ctx context.Context, bucketHandle *gcpstorage.
 
 	return &storage.IAMPolicy{
 		Bindings: bindings,
+		Etag:     policy.Etag,
 	}, nil
 }
 
@@ -262,3 +318,21 @@ func (g *GCPStorage) DeleteBucket(ctx context.Context, bucketName string) error
 	}
 	return nil
 }
+
+var _ storage.BucketLabeler = (*GCPStorage)(nil)
+
+func (g *GCPStorage) UpdateBucketLabels(ctx context.Context, bucketName string, labels map[string]string) error {
+	g.logger.Debug("Starting GCP UpdateBucketLabels operation", "bucket", bucketName)
+
+	if len(labels) == 0 {
+		return nil
+	}
+	var update gcpstorage.BucketAttrsToUpdate
+	for k, v := range labels {
+		update.SetLabel(k, v)
+	}
+	if _, err := g.client.Bucket(bucketName).Update(ctx, update); err != nil {
+		return fmt.Errorf("updating labels of bucket %s: %w", bucketName, err)
+	}
+	return nil
+}
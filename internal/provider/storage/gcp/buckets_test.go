@@ -0,0 +1,179 @@
+package gcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	gcpstorage "cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// emptyMetricServer answers every time series query with no data.
+type emptyMetricServer struct {
+	monitoringpb.UnimplementedMetricServiceServer
+}
+
+func (emptyMetricServer) ListTimeSeries(context.Context, *monitoringpb.ListTimeSeriesRequest) (*monitoringpb.ListTimeSeriesResponse, error) {
+	return &monitoringpb.ListTimeSeriesResponse{}, nil
+}
+
+// newDescribeTestStorage returns a GCPStorage whose storage client talks to
+// handler. Monitoring reports no data, so usage is N/A.
+func newDescribeTestStorage(t *testing.T, handler http.Handler) *GCPStorage {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	monitoringpb.RegisterMetricServiceServer(grpcServer, emptyMetricServer{})
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	storageOpts := []option.ClientOption{option.WithEndpoint(srv.URL + "/storage/v1/"), option.WithoutAuthentication()}
+	client, err := gcpstorage.NewClient(context.Background(), storageOpts...)
+	if err != nil {
+		t.Fatalf("failed to create storage client: %v", err)
+	}
+	api, err := storagev1.NewService(context.Background(), storageOpts...)
+	if err != nil {
+		t.Fatalf("failed to create JSON API service: %v", err)
+	}
+	g := &GCPStorage{
+		client:    client,
+		api:       api,
+		projectID: "proj",
+		clientOpts: []option.ClientOption{
+			option.WithEndpoint(lis.Addr().String()),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	t.Cleanup(func() { g.Close() })
+	return g
+}
+
+func TestDescribeBucket_FetchesConcurrently(t *testing.T) {
+	aclStarted := make(chan struct{})
+	iamStarted := make(chan struct{})
+
+	g := newDescribeTestStorage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/acl"):
+			close(aclStarted)
+			io.WriteString(w, `{"items": [{"entity": "allUsers", "role": "READER"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/iam"):
+			close(iamStarted)
+			io.WriteString(w, `{"bindings": [{"role": "roles/storage.admin", "members": ["user:a@example.com"]}]}`)
+		default:
+			// The attributes only answer once the other fetches are in flight,
+			// which never happens if they run after it
+			for _, started := range []chan struct{}{aclStarted, iamStarted} {
+				select {
+				case <-started:
+				case <-time.After(5 * time.Second):
+					http.Error(w, `{"error": {"code": 504, "message": "sub-fetch not started"}}`, http.StatusGatewayTimeout)
+					return
+				}
+			}
+			io.WriteString(w, `{"name": "my-bucket", "location": "US", "storageClass": "STANDARD"}`)
+		}
+	}))
+
+	bucket, err := g.DescribeBucket(context.Background(), "my-bucket")
+	if err != nil {
+		t.Fatalf("DescribeBucket failed: %v", err)
+	}
+	if bucket.Name != "my-bucket" || bucket.Location != "US" {
+		t.Errorf("unexpected attributes: %+v", bucket)
+	}
+	if len(bucket.ACLs) != 1 || bucket.IAMPolicy == nil || len(bucket.IAMPolicy.Bindings) != 1 {
+		t.Errorf("expected ACLs and IAM policy to be filled in, got ACLs %v, IAM %+v", bucket.ACLs, bucket.IAMPolicy)
+	}
+	if bucket.UsageBytes != -1 {
+		t.Errorf("expected usage N/A when monitoring has no data, got %d", bucket.UsageBytes)
+	}
+}
+
+func TestDescribeBucket_PartialFailure(t *testing.T) {
+	g := newDescribeTestStorage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/acl"), strings.HasSuffix(r.URL.Path, "/iam"):
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"error": {"code": 403, "message": "forbidden"}}`)
+		default:
+			io.WriteString(w, `{"name": "my-bucket", "location": "US"}`)
+		}
+	}))
+
+	bucket, err := g.DescribeBucket(context.Background(), "my-bucket")
+	if err != nil {
+		t.Fatalf("expected best-effort fetches not to fail the describe, got: %v", err)
+	}
+	if bucket.ACLs != nil || bucket.IAMPolicy != nil {
+		t.Errorf("expected empty ACLs and IAM policy, got %v, %+v", bucket.ACLs, bucket.IAMPolicy)
+	}
+}
+
+func TestDescribeBucket_AttrsFailure(t *testing.T) {
+	g := newDescribeTestStorage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"error": {"code": 404, "message": "not found"}}`)
+	}))
+
+	_, err := g.DescribeBucket(context.Background(), "missing")
+	if err == nil || !strings.Contains(err.Error(), "error getting bucket attributes") {
+		t.Errorf("expected attributes error, got: %v", err)
+	}
+}
+
+func TestListBuckets_PartialProjects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("project") == "broken" {
+			http.Error(w, `{"error": {"code": 403, "message": "denied"}}`, http.StatusForbidden)
+			return
+		}
+		io.WriteString(w, `{"items": [{"name": "ok-bucket", "location": "US"}]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := gcpstorage.NewClient(context.Background(), option.WithEndpoint(srv.URL+"/storage/v1/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create storage client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	g := &GCPStorage{
+		client:         client,
+		projectID:      "ok",
+		projectIDs:     []string{"ok", "broken"},
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		noUsageMetrics: true,
+	}
+
+	buckets, err := g.ListBuckets(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "project broken") {
+		t.Errorf("expected the failed project in the error, got %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "ok-bucket" || buckets[0].Project != "ok" {
+		t.Errorf("expected the other project's buckets, got %+v", buckets)
+	}
+}
@@ -5,15 +5,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/gcpauth"
+	"github.com/jkleinne/synkronus/internal/provider/registry"
+	"github.com/jkleinne/synkronus/internal/retry"
 	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"sync"
-	"synkronus/internal/config"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/registry"
+	"time"
 
+	"cloud.google.com/go/auth"
 	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
 	gcpstorage "cloud.google.com/go/storage"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func init() {
@@ -28,42 +44,217 @@ func initialize(ctx context.Context, cfg *config.Config, logger *slog.Logger) (s
 	if !config.IsGCPConfigured(cfg) {
 		return nil, fmt.Errorf("GCP configuration missing or incomplete")
 	}
-	return NewGCPStorage(ctx, cfg.GCP.Project, logger)
+	return NewGCPStorage(ctx, cfg.GCP, cfg.MaxRetries(), logger)
 }
 
 type GCPStorage struct {
 	client           *gcpstorage.Client
 	projectID        string
+	projectIDs       []string
+	clientOpts       []option.ClientOption
 	logger           *slog.Logger
 	monitoringClient *monitoring.MetricClient
-	monitoringOnce   sync.Once
-	monitoringErr    error
+	monitoringMu     sync.Mutex
+	metricsCache     *cache.Cache
+	// signer signs URLs and POST policies as the impersonated service
+	// account, if any; otherwise the client finds a key itself
+	signer *gcpauth.BlobSigner
+	// api is the JSON API service, for what the storage client does not
+	// expose (e.g., the etag of a bucket's IAM policy)
+	api *storagev1.Service
+	// maxRetries bounds the retries of the storage and monitoring clients
+	maxRetries int
+	// noUsageMetrics is set for emulators, which have no Monitoring API to
+	// report usage; bucket usage is then left unknown
+	noUsageMetrics bool
 }
 
 var _ storage.Storage = (*GCPStorage)(nil)
 
-func NewGCPStorage(ctx context.Context, projectID string, logger *slog.Logger) (*GCPStorage, error) {
-	client, err := gcpstorage.NewClient(ctx)
+// NewGCPStorage creates a new Cloud Storage client for the projects in cfg,
+// authenticated with the credentials configured in cfg (or Application Default
+// Credentials). Listing fans out across all projects; single-project operations
+// such as bucket creation use the primary project. Calls that fail with a
+// transient error are retried up to maxRetries times.
+func NewGCPStorage(ctx context.Context, cfg *config.GCPConfig, maxRetries int, logger *slog.Logger) (*GCPStorage, error) {
+	clientOpts, err := gcpauth.ClientOptions(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	httpOpts, err := gcpauth.HTTPClientOptions(ctx, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gcpstorage.NewClient(ctx, storageClientOptions(cfg, httpOpts)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP storage client: %w", err)
+	}
+	client.SetRetry(gcpstorage.WithMaxAttempts(maxRetries+1), gcpstorage.WithErrorFunc(isRetryable))
+	api, err := storagev1.NewService(ctx, storageClientOptions(cfg, httpOpts)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GCP storage client: %w", err)
 	}
 
+	signer, err := gcpauth.NewBlobSigner(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usage metrics are served from the on-disk cache when possible; without
+	// one (no home directory) they are always fetched
+	metricsCache, err := cache.Open(metricsCacheName, metricsCacheTTL)
+	if err != nil {
+		logger.Debug("Usage metrics cache unavailable", "error", err)
+	}
+
 	return &GCPStorage{
-		client:    client,
-		projectID: projectID,
-		logger:    logger,
+		client:         client,
+		projectID:      cfg.PrimaryProject(),
+		projectIDs:     cfg.AllProjects(),
+		clientOpts:     clientOpts,
+		logger:         logger,
+		metricsCache:   metricsCache,
+		signer:         signer,
+		api:            api,
+		maxRetries:     maxRetries,
+		noUsageMetrics: isEmulatorEndpoint(cfg.StorageEndpoint),
 	}, nil
 }
 
+// storageClientOptions adds the storage endpoint override to clientOpts. Only the
+// storage client gets it; monitoring keeps using Google's endpoint. Emulators
+// such as fake-gcs-server don't accept credentials, so requests to a plain HTTP
+// or loopback endpoint are sent unauthenticated. Any other endpoint, such as a
+// Private Service Connect one, keeps the credentials.
+func storageClientOptions(cfg *config.GCPConfig, clientOpts []option.ClientOption) []option.ClientOption {
+	if cfg.StorageEndpoint == "" {
+		return clientOpts
+	}
+	endpoint := option.WithEndpoint(cfg.StorageEndpoint)
+	if isEmulatorEndpoint(cfg.StorageEndpoint) {
+		return []option.ClientOption{endpoint, option.WithoutAuthentication()}
+	}
+	return append(slices.Clip(clientOpts), endpoint)
+}
+
+// isEmulatorEndpoint reports whether endpoint points at a local emulator such
+// as fake-gcs-server: one served over plain HTTP or on a loopback host. Google
+// only serves Cloud Storage over HTTPS. The empty endpoint is Google's own.
+func isEmulatorEndpoint(endpoint string) bool {
+	if endpoint == "" {
+		return false
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Scheme, "http") {
+		return true
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func (g *GCPStorage) ProviderName() domain.Provider {
 	return domain.GCP
 }
 
+// RetriesInSDK reports that storage and monitoring calls are retried by their
+// clients.
+func (g *GCPStorage) RetriesInSDK() bool {
+	return true
+}
+
+// isRetryable reports whether err is transient: a throttled or failed JSON API
+// request, an unavailable monitoring (gRPC) endpoint, or a dropped connection.
+func isRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return retry.IsRetryableStatus(apiErr.Code)
+	}
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		switch st.Code() {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Internal:
+			return true
+		}
+		return false
+	}
+	return retry.IsTransientNetworkError(err)
+}
+
+// IsNotFound reports whether err means the bucket or object does not exist.
+func (g *GCPStorage) IsNotFound(err error) bool {
+	if errors.Is(err, gcpstorage.ErrBucketNotExist) || errors.Is(err, gcpstorage.ErrObjectNotExist) {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusNotFound
+	}
+	return status.Code(err) == codes.NotFound
+}
+
+// IsAuthError reports whether err means the credentials are missing, were
+// rejected, or lack permission for the request.
+func (g *GCPStorage) IsAuthError(err error) bool {
+	var authErr *auth.Error
+	if errors.As(err, &authErr) {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusForbidden
+	}
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return true
+	}
+	return false
+}
+
+// getMonitoringClient returns the shared monitoring client, creating it on
+// first use. A failed creation is not cached, so a later call can retry.
 func (g *GCPStorage) getMonitoringClient(ctx context.Context) (*monitoring.MetricClient, error) {
-	g.monitoringOnce.Do(func() {
-		g.monitoringClient, g.monitoringErr = monitoring.NewMetricClient(ctx)
-	})
-	return g.monitoringClient, g.monitoringErr
+	g.monitoringMu.Lock()
+	defer g.monitoringMu.Unlock()
+
+	if g.monitoringClient == nil {
+		client, err := monitoring.NewMetricClient(ctx, g.clientOpts...)
+		if err != nil {
+			return nil, err
+		}
+		// The default retries Unavailable errors until the call times out
+		client.CallOptions.ListTimeSeries = []gax.CallOption{
+			gax.WithTimeout(90 * time.Second),
+			gax.WithRetry(func() gax.Retryer {
+				return &limitedRetryer{
+					retryer: gax.OnErrorFunc(gax.Backoff{Initial: 100 * time.Millisecond, Max: 30 * time.Second, Multiplier: 1.3}, isRetryable),
+					left:    g.maxRetries,
+				}
+			}),
+		}
+		g.monitoringClient = client
+	}
+	return g.monitoringClient, nil
+}
+
+// limitedRetryer stops retrying once left retries are spent.
+type limitedRetryer struct {
+	retryer gax.Retryer
+	left    int
+}
+
+func (r *limitedRetryer) Retry(err error) (time.Duration, bool) {
+	if r.left <= 0 {
+		return 0, false
+	}
+	r.left--
+	return r.retryer.Retry(err)
 }
 
 func (g *GCPStorage) Close() error {
@@ -71,8 +262,11 @@ func (g *GCPStorage) Close() error {
 	if g.client != nil {
 		storageErr = g.client.Close()
 	}
+	g.monitoringMu.Lock()
 	if g.monitoringClient != nil {
 		monitoringErr = g.monitoringClient.Close()
+		g.monitoringClient = nil
 	}
+	g.monitoringMu.Unlock()
 	return errors.Join(storageErr, monitoringErr)
 }
@@ -0,0 +1,146 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"cloud.google.com/go/auth"
+	gcpstorage "cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStorageClientOptions(t *testing.T) {
+	base := []option.ClientOption{option.WithQuotaProject("billing")}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		wantOpts int
+	}{
+		{"no override", "", 1},
+		{"emulator drops credentials", "http://localhost:4443/storage/v1/", 2},
+		{"google endpoint keeps credentials", "https://storage.europe-west1.rep.googleapis.com/storage/v1/", 2},
+		{"private endpoint keeps credentials", "https://storage-psc.p.example.internal/storage/v1/", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := storageClientOptions(&config.GCPConfig{Project: "p", StorageEndpoint: tt.endpoint}, base)
+			if len(opts) != tt.wantOpts {
+				t.Errorf("expected %d options, got %d", tt.wantOpts, len(opts))
+			}
+		})
+	}
+
+	if len(base) != 1 {
+		t.Errorf("base options must not be modified, got %d", len(base))
+	}
+}
+
+func TestIsEmulatorEndpoint(t *testing.T) {
+	for endpoint, want := range map[string]bool{
+		"":                                                   false,
+		"http://localhost:4443/storage/v1/":                  true,
+		"https://storage.googleapis.com/":                    false,
+		"http://fake-gcs:4443/storage/v1/":                   true,
+		"https://localhost:4443/storage/v1/":                 true,
+		"https://127.0.0.1:4443/":                            true,
+		"https://[::1]:4443/":                                true,
+		"https://storage-psc.p.example.internal/storage/v1/": false,
+		"https://gcs.corp.example.com/":                      false,
+	} {
+		if got := isEmulatorEndpoint(endpoint); got != want {
+			t.Errorf("isEmulatorEndpoint(%q) = %v, want %v", endpoint, got, want)
+		}
+	}
+}
+
+func TestGetMonitoringClient_Reused(t *testing.T) {
+	// Dialing is lazy, so no server is needed to create the client
+	g := &GCPStorage{clientOpts: []option.ClientOption{
+		option.WithEndpoint("localhost:1"),
+		option.WithoutAuthentication(),
+	}}
+
+	first, err := g.getMonitoringClient(context.Background())
+	if err != nil {
+		t.Fatalf("getMonitoringClient failed: %v", err)
+	}
+	second, err := g.getMonitoringClient(context.Background())
+	if err != nil {
+		t.Fatalf("getMonitoringClient failed: %v", err)
+	}
+	if first != second {
+		t.Error("expected the monitoring client to be reused across calls")
+	}
+
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if g.monitoringClient != nil {
+		t.Error("expected Close to release the monitoring client")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", &googleapi.Error{Code: 429}, true},
+		{"server error", fmt.Errorf("listing: %w", &googleapi.Error{Code: 503}), true},
+		{"forbidden", &googleapi.Error{Code: 403}, false},
+		{"not found", &googleapi.Error{Code: 404}, false},
+		{"monitoring unavailable", status.Error(codes.Unavailable, "unavailable"), true},
+		{"monitoring permission denied", status.Error(codes.PermissionDenied, "denied"), false},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"other", errors.New("bucket doesn't exist"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantNotFound bool
+		wantAuth     bool
+	}{
+		{"bucket not found", fmt.Errorf("describing: %w", gcpstorage.ErrBucketNotExist), true, false},
+		{"object not found", gcpstorage.ErrObjectNotExist, true, false},
+		{"api not found", &googleapi.Error{Code: 404}, true, false},
+		{"unauthorized", &googleapi.Error{Code: 401}, false, true},
+		{"forbidden", fmt.Errorf("listing: %w", &googleapi.Error{Code: 403}), false, true},
+		{"token refresh failed", &auth.Error{Response: &http.Response{StatusCode: 400}, Body: []byte("invalid_grant")}, false, true},
+		{"monitoring permission denied", status.Error(codes.PermissionDenied, "denied"), false, true},
+		{"monitoring not found", status.Error(codes.NotFound, "missing"), true, false},
+		{"server error", &googleapi.Error{Code: 503}, false, false},
+		{"other", errors.New("boom"), false, false},
+	}
+	g := &GCPStorage{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.IsNotFound(tt.err); got != tt.wantNotFound {
+				t.Errorf("IsNotFound(%v) = %v, want %v", tt.err, got, tt.wantNotFound)
+			}
+			if got := g.IsAuthError(tt.err); got != tt.wantAuth {
+				t.Errorf("IsAuthError(%v) = %v, want %v", tt.err, got, tt.wantAuth)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package gcp
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// xmlAPIHost serves the XML API, which answers plain HTTP requests on objects
+// (downloads, CORS preflights) as well as signed URLs
+const xmlAPIHost = "storage.googleapis.com"
+
+var _ storage.BucketURLResolver = (*GCPStorage)(nil)
+
+// BucketURL returns the bucket's XML API URL, path-style unless virtual-hosted
+// style is asked for. Cloud Storage endpoints are global, so no call is made.
+func (g *GCPStorage) BucketURL(ctx context.Context, bucketName, style string) (string, error) {
+	if style == storage.URLStyleVirtualHosted {
+		return "https://" + bucketName + "." + xmlAPIHost, nil
+	}
+	return "https://" + xmlAPIHost + "/" + bucketName, nil
+}
@@ -0,0 +1,20 @@
+package gcp
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"testing"
+)
+
+func TestBucketURL(t *testing.T) {
+	g := &GCPStorage{}
+	for style, want := range map[string]string{
+		"":                            "https://storage.googleapis.com/data",
+		storage.URLStylePath:          "https://storage.googleapis.com/data",
+		storage.URLStyleVirtualHosted: "https://data.storage.googleapis.com",
+	} {
+		if got, _ := g.BucketURL(context.Background(), "data", style); got != want {
+			t.Errorf("BucketURL(%q) = %q, want %q", style, got, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package gcp
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/gcpauth"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	gcpstorage "cloud.google.com/go/storage"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+const (
+	// tailSubscriptionPrefix names the temporary subscriptions of TailEvents
+	tailSubscriptionPrefix = "synkronus-tail-"
+	// tailSubscriptionTTL makes Pub/Sub delete a subscription left behind by
+	// a process that was killed, once it has gone a day without pulls
+	tailSubscriptionTTL = "86400s"
+	// tailMessageRetention is the shortest retention Pub/Sub allows
+	tailMessageRetention = "600s"
+	tailAckDeadline      = 60
+	tailPullBatch        = 100
+	// tailCleanupTimeout bounds deleting the subscription after the tail stops
+	tailCleanupTimeout = 10 * time.Second
+)
+
+var _ storage.EventTailer = (*GCPStorage)(nil)
+
+// TailEvents streams the object events of bucketName from the Pub/Sub topic of
+// one of its notification configurations. A temporary subscription is created
+// on the topic, so the events still reach every existing subscriber, and
+// deleted when the tail stops.
+func (g *GCPStorage) TailEvents(ctx context.Context, bucketName string, ready func(source string), fn func(storage.ObjectEvent) error) error {
+	notifications, err := g.client.Bucket(bucketName).Notifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification configurations: %w", err)
+	}
+	notification := tailNotification(notifications)
+	if notification == nil {
+		return fmt.Errorf("bucket has no Pub/Sub notification configuration; create one with 'gcloud storage buckets notifications create gs://%s --topic=<topic>'", bucketName)
+	}
+	topic := fmt.Sprintf("projects/%s/topics/%s", notification.TopicProjectID, notification.TopicID)
+
+	clientOpts, err := gcpauth.HTTPClientOptions(ctx, g.clientOpts)
+	if err != nil {
+		return err
+	}
+	svc, err := pubsub.NewService(ctx, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+	subscriptions := svc.Projects.Subscriptions
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	subscription := fmt.Sprintf("projects/%s/subscriptions/%s%s", notification.TopicProjectID, tailSubscriptionPrefix, hex.EncodeToString(suffix))
+	_, err = subscriptions.Create(subscription, &pubsub.Subscription{
+		Topic:                    topic,
+		AckDeadlineSeconds:       tailAckDeadline,
+		MessageRetentionDuration: tailMessageRetention,
+		ExpirationPolicy:         &pubsub.ExpirationPolicy{Ttl: tailSubscriptionTTL},
+		Labels:                   map[string]string{"created-by": "synkronus"},
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tailCleanupTimeout)
+		defer cancel()
+		if _, err := subscriptions.Delete(subscription).Context(cleanupCtx).Do(); err != nil {
+			g.logger.Warn("Failed to delete the temporary Pub/Sub subscription; it expires after a day without use", "subscription", subscription, "error", err)
+		}
+	}()
+	g.logger.Debug("Created temporary Pub/Sub subscription", "subscription", subscription, "topic", topic)
+
+	ready(topic)
+	for {
+		resp, err := subscriptions.Pull(subscription, &pubsub.PullRequest{MaxMessages: tailPullBatch}).Context(ctx).Do()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to pull events from %s: %w", subscription, err)
+		}
+
+		ackIDs := make([]string, 0, len(resp.ReceivedMessages))
+		for _, received := range resp.ReceivedMessages {
+			ackIDs = append(ackIDs, received.AckId)
+			event, ok := notificationEvent(received.Message)
+			// Other buckets may publish to the same topic
+			if !ok || event.Bucket != bucketName {
+				continue
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if len(ackIDs) == 0 {
+			continue
+		}
+		_, err = subscriptions.Acknowledge(subscription, &pubsub.AcknowledgeRequest{AckIds: ackIDs}).Context(ctx).Do()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to acknowledge events: %w", err)
+		}
+	}
+}
+
+// tailNotification picks the notification configuration to tail: the one
+// reporting the most event types (all of them when none are listed), then
+// the one without an object prefix, then the lowest ID.
+func tailNotification(notifications map[string]*gcpstorage.Notification) *gcpstorage.Notification {
+	eventTypes := func(n *gcpstorage.Notification) int {
+		if len(n.EventTypes) == 0 {
+			return 4 // finalize, delete, archive and metadata update
+		}
+		return len(n.EventTypes)
+	}
+	var best *gcpstorage.Notification
+	for _, id := range slices.Sorted(maps.Keys(notifications)) {
+		n := notifications[id]
+		if best == nil || eventTypes(n) > eventTypes(best) ||
+			(eventTypes(n) == eventTypes(best) && n.ObjectNamePrefix == "" && best.ObjectNamePrefix != "") {
+			best = n
+		}
+	}
+	return best
+}
+
+// notificationEvent maps a Cloud Storage notification to an object event. The
+// attributes carry everything but the size, which comes from the JSON_API_V1
+// payload when the notification has one.
+func notificationEvent(msg *pubsub.PubsubMessage) (storage.ObjectEvent, bool) {
+	if msg == nil || msg.Attributes["eventType"] == "" || msg.Attributes["objectId"] == "" {
+		return storage.ObjectEvent{}, false
+	}
+	attrs := msg.Attributes
+	event := storage.ObjectEvent{
+		Type:    notificationEventType(attrs["eventType"]),
+		Name:    attrs["eventType"],
+		Bucket:  attrs["bucketId"],
+		Key:     attrs["objectId"],
+		Version: attrs["objectGeneration"],
+	}
+	event.Time, _ = time.Parse(time.RFC3339Nano, cmp.Or(attrs["eventTime"], msg.PublishTime))
+
+	if event.Type == storage.ObjectCreated && attrs["payloadFormat"] == gcpstorage.JSONPayload {
+		var payload struct {
+			Size string `json:"size"`
+		}
+		if data, err := base64.StdEncoding.DecodeString(msg.Data); err == nil && json.Unmarshal(data, &payload) == nil {
+			event.Size, _ = strconv.ParseInt(payload.Size, 10, 64)
+		}
+	}
+	return event, true
+}
+
+func notificationEventType(eventType string) storage.ObjectEventType {
+	switch eventType {
+	case gcpstorage.ObjectFinalizeEvent:
+		return storage.ObjectCreated
+	case gcpstorage.ObjectDeleteEvent:
+		return storage.ObjectDeleted
+	case gcpstorage.ObjectArchiveEvent:
+		return storage.ObjectArchived
+	case gcpstorage.ObjectMetadataUpdateEvent:
+		return storage.ObjectMetadataUpdated
+	default:
+		return storage.ObjectOther
+	}
+}
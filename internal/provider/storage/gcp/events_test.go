@@ -0,0 +1,63 @@
+package gcp
+
+import (
+	"encoding/base64"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"testing"
+	"time"
+
+	gcpstorage "cloud.google.com/go/storage"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+func TestNotificationEvent(t *testing.T) {
+	msg := &pubsub.PubsubMessage{
+		Attributes: map[string]string{
+			"eventType":        "OBJECT_FINALIZE",
+			"bucketId":         "data",
+			"objectId":         "exports/2024-01-01.csv",
+			"objectGeneration": "1704067200000000",
+			"eventTime":        "2024-01-01T00:00:00.123456Z",
+			"payloadFormat":    "JSON_API_V1",
+		},
+		Data: base64.StdEncoding.EncodeToString([]byte(`{"name": "exports/2024-01-01.csv", "size": "2048"}`)),
+	}
+	event, ok := notificationEvent(msg)
+	if !ok {
+		t.Fatal("expected an event")
+	}
+	want := storage.ObjectEvent{
+		Time:    time.Date(2024, 1, 1, 0, 0, 0, 123456000, time.UTC),
+		Type:    storage.ObjectCreated,
+		Name:    "OBJECT_FINALIZE",
+		Bucket:  "data",
+		Key:     "exports/2024-01-01.csv",
+		Size:    2048,
+		Version: "1704067200000000",
+	}
+	if event != want {
+		t.Errorf("event = %+v, want %+v", event, want)
+	}
+
+	msg.Attributes["eventType"] = "OBJECT_DELETE"
+	if event, _ := notificationEvent(msg); event.Type != storage.ObjectDeleted || event.Size != 0 {
+		t.Errorf("expected a deletion without a size, got %+v", event)
+	}
+	if _, ok := notificationEvent(&pubsub.PubsubMessage{Data: "e30="}); ok {
+		t.Error("expected a message without notification attributes to be skipped")
+	}
+}
+
+func TestTailNotification(t *testing.T) {
+	if got := tailNotification(nil); got != nil {
+		t.Errorf("expected none without notifications, got %+v", got)
+	}
+
+	finalizeOnly := &gcpstorage.Notification{ID: "1", TopicID: "finalize", EventTypes: []string{gcpstorage.ObjectFinalizeEvent}}
+	prefixed := &gcpstorage.Notification{ID: "2", TopicID: "prefixed", ObjectNamePrefix: "logs/"}
+	all := &gcpstorage.Notification{ID: "3", TopicID: "all"}
+	got := tailNotification(map[string]*gcpstorage.Notification{"1": finalizeOnly, "2": prefixed, "3": all})
+	if got != all {
+		t.Errorf("expected the notification for all events without a prefix, got %+v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package gcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	logging "google.golang.org/api/logging/v2"
+)
+
+func TestHistoryFilter(t *testing.T) {
+	filter := historyFilter("logs", `reports/"q1".csv`, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+	for _, want := range []string{
+		`resource.labels.bucket_name="logs"`,
+		`protoPayload.resourceName="projects/_/buckets/logs/objects/reports/\"q1\".csv"`,
+		`timestamp>="2026-09-01T00:00:00Z"`,
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("expected the filter to contain %s, got %s", want, filter)
+		}
+	}
+}
+
+func TestChangeFromEntry(t *testing.T) {
+	change, err := changeFromEntry(&logging.LogEntry{
+		Timestamp: "2026-09-14T08:15:30.123Z",
+		ProtoPayload: []byte(`{"methodName": "storage.objects.delete",
+			"authenticationInfo": {"principalEmail": "alice@example.com"},
+			"requestMetadata": {"callerIp": "203.0.113.7", "callerSuppliedUserAgent": "gsutil"},
+			"status": {"code": 7, "message": "PERMISSION_DENIED"}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := storage.ObjectChange{
+		Time:      time.Date(2026, 9, 14, 8, 15, 30, 123000000, time.UTC),
+		Type:      storage.ChangeDelete,
+		Operation: "storage.objects.delete",
+		Principal: "alice@example.com",
+		SourceIP:  "203.0.113.7",
+		UserAgent: "gsutil",
+		Error:     "PERMISSION_DENIED",
+	}
+	if change != want {
+		t.Errorf("got %+v, want %+v", change, want)
+	}
+}
+
+func TestChangeType(t *testing.T) {
+	for method, want := range map[string]storage.ObjectChangeType{
+		"storage.objects.create":              storage.ChangeWrite,
+		"storage.objects.compose":             storage.ChangeWrite,
+		"storage.objects.delete":              storage.ChangeDelete,
+		"storage.objects.update":              storage.ChangeMetadata,
+		"storage.objectAccessControls.insert": storage.ChangeACL,
+		"storage.setIamPermissions":           storage.ChangeACL,
+		"storage.objects.restore":             storage.ChangeOther,
+	} {
+		if got := changeType(method); got != want {
+			t.Errorf("changeType(%q) = %q, want %q", method, got, want)
+		}
+	}
+}
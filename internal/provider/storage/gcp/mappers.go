@@ -4,8 +4,8 @@ package gcp
 import (
 	"encoding/base64"
 	"fmt"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
 
 	gcpstorage "cloud.google.com/go/storage"
 )
@@ -37,6 +37,15 @@ func mapLifecycleRules(rules []gcpstorage.LifecycleRule) []storage.LifecycleRule
 	return result
 }
 
+// mapAutoclass reports Autoclass as disabled for buckets that have never
+// configured it, for which the API returns no autoclass settings at all.
+func mapAutoclass(a *gcpstorage.Autoclass) *storage.Autoclass {
+	if a == nil {
+		return &storage.Autoclass{Enabled: false}
+	}
+	return &storage.Autoclass{Enabled: a.Enabled}
+}
+
 func mapLogging(l *gcpstorage.BucketLogging) *storage.Logging {
 	if l == nil {
 		return nil
@@ -87,10 +96,14 @@ func mapRetentionPolicy(rp *gcpstorage.RetentionPolicy) *storage.RetentionPolicy
 	if rp == nil {
 		return nil
 	}
-	return &storage.RetentionPolicy{
+	policy := &storage.RetentionPolicy{
 		RetentionPeriod: rp.RetentionPeriod,
 		IsLocked:        rp.IsLocked,
 	}
+	if !rp.EffectiveTime.IsZero() {
+		policy.EffectiveTime = &rp.EffectiveTime
+	}
+	return policy
 }
 
 // Converts the binary MD5 hash provided by GCP SDK into a standard Base64 encoded string
@@ -90,6 +90,15 @@ func TestMapLifecycleRules_AllConditions(t *testing.T) {
 	}
 }
 
+func TestMapAutoclass(t *testing.T) {
+	if got := mapAutoclass(nil); got == nil || got.Enabled {
+		t.Errorf("expected disabled Autoclass for nil input, got %+v", got)
+	}
+	if got := mapAutoclass(&gcpstorage.Autoclass{Enabled: true}); !got.Enabled {
+		t.Error("expected enabled Autoclass")
+	}
+}
+
 func TestMapLogging_Nil(t *testing.T) {
 	if mapLogging(nil) != nil {
 		t.Error("expected nil for nil input")
@@ -204,14 +213,19 @@ func TestMapRetentionPolicy_Unlocked(t *testing.T) {
 }
 
 func TestMapRetentionPolicy_Locked(t *testing.T) {
+	effective := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
 	input := &gcpstorage.RetentionPolicy{
 		RetentionPeriod: 90 * 24 * time.Hour,
 		IsLocked:        true,
+		EffectiveTime:   effective,
 	}
 	result := mapRetentionPolicy(input)
 	if !result.IsLocked {
 		t.Error("expected IsLocked=true")
 	}
+	if result.EffectiveTime == nil || !result.EffectiveTime.Equal(effective) {
+		t.Errorf("expected effective time %v, got %v", effective, result.EffectiveTime)
+	}
 }
 
 func TestFormatMD5_EmptyHash(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 	"math"
 	"time"
 
@@ -16,26 +17,59 @@ import (
 
 const metricTimeWindow = 72 * time.Hour
 
+const (
+	// metricsCacheName is the cache holding each project's bucket usage
+	metricsCacheName = "metrics"
+	// metricsCacheTTL is how long cached usage is served; GCP reports
+	// total_bytes about once a day, so this rarely hides a newer value
+	metricsCacheTTL = 15 * time.Minute
+)
+
 const (
 	storageTotalBytesMetric  = "storage.googleapis.com/storage/v2/total_bytes"
 	metricGroupByBucket      = "resource.labels.bucket_name"
 	metricBucketLabelKey     = "bucket_name"
 	gcpProjectResourceFormat = "projects/%s"
+	metricsCacheKeyPrefix    = "gcp/total_bytes/"
 )
 
 // ErrMetricsNotFound indicates that the usage metrics could not be found within the queried time range
 // This often happens for new buckets that haven't reported metrics yet
 var ErrMetricsNotFound = errors.New("usage metrics not found in the monitoring window")
 
-func (g *GCPStorage) getAllBucketUsages(ctx context.Context) (map[string]int64, error) {
-	g.logger.Debug("Fetching GCP bucket usage metrics via Monitoring API (Aggregated)")
+// getCachedBucketUsages returns the usage of every bucket in the project,
+// served from the metrics cache while it is fresh.
+func (g *GCPStorage) getCachedBucketUsages(ctx context.Context, projectID string) (map[string]int64, error) {
+	if g.noUsageMetrics {
+		return nil, nil
+	}
+	key := metricsCacheKeyPrefix + projectID
+
+	var usageMap map[string]int64
+	if g.metricsCache.Get(ctx, key, &usageMap) {
+		g.logger.Debug("Using cached GCP bucket usage metrics", "project", projectID)
+		return usageMap, nil
+	}
+
+	usageMap, err := g.getAllBucketUsages(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.metricsCache.Put(key, usageMap); err != nil {
+		g.logger.Debug("Failed to cache GCP bucket usage metrics", "project", projectID, "error", err)
+	}
+	return usageMap, nil
+}
+
+func (g *GCPStorage) getAllBucketUsages(ctx context.Context, projectID string) (map[string]int64, error) {
+	g.logger.Debug("Fetching GCP bucket usage metrics via Monitoring API (Aggregated)", "project", projectID)
 	client, err := g.getMonitoringClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create monitoring client: %w", err)
 	}
 
 	filter := fmt.Sprintf(`metric.type="%s"`, storageTotalBytesMetric)
-	req := buildMetricsRequest(g.projectID, filter)
+	req := buildMetricsRequest(projectID, filter)
 
 	usageMap := make(map[string]int64)
 	it := client.ListTimeSeries(ctx, req)
@@ -65,7 +99,10 @@ func (g *GCPStorage) getAllBucketUsages(ctx context.Context) (map[string]int64,
 	return usageMap, nil
 }
 
-func (g *GCPStorage) getSingleBucketUsage(ctx context.Context, bucketName string) (int64, error) {
+func (g *GCPStorage) getSingleBucketUsage(ctx context.Context, projectID, bucketName string) (int64, error) {
+	if g.noUsageMetrics {
+		return -1, ErrMetricsNotFound
+	}
 	g.logger.Debug("Fetching single GCP bucket usage metric via Monitoring API (Aggregated)", "bucket", bucketName)
 	client, err := g.getMonitoringClient(ctx)
 	if err != nil {
@@ -73,7 +110,7 @@ func (g *GCPStorage) getSingleBucketUsage(ctx context.Context, bucketName string
 	}
 
 	filter := fmt.Sprintf(`metric.type="%s" AND %s="%s"`, storageTotalBytesMetric, metricGroupByBucket, bucketName)
-	req := buildMetricsRequest(g.projectID, filter)
+	req := buildMetricsRequest(projectID, filter)
 
 	it := client.ListTimeSeries(ctx, req)
 
@@ -135,3 +172,57 @@ func extractUsageValue(pointValue *monitoringpb.TypedValue) int64 {
 		return 0
 	}
 }
+
+const storageRequestCountMetric = "storage.googleapis.com/api/request_count"
+
+var _ storage.AccessReporter = (*GCPStorage)(nil)
+
+// BucketAccess sums the API requests made to the bucket over window, queried
+// in the bucket's own project. Cloud Monitoring keeps the metric for six
+// weeks, which bounds the window.
+func (g *GCPStorage) BucketAccess(ctx context.Context, bucketName string, window time.Duration) (storage.AccessStats, error) {
+	g.logger.Debug("Fetching GCP bucket request count via Monitoring API", "bucket", bucketName, "window", window)
+	if g.noUsageMetrics {
+		return storage.AccessStats{}, ErrMetricsNotFound
+	}
+	attrs, err := g.client.Bucket(bucketName).Attrs(ctx)
+	if err != nil {
+		return storage.AccessStats{}, fmt.Errorf("error getting bucket attributes: %w", err)
+	}
+	client, err := g.getMonitoringClient(ctx)
+	if err != nil {
+		return storage.AccessStats{}, fmt.Errorf("failed to create monitoring client: %w", err)
+	}
+
+	endTime := time.Now()
+	it := client.ListTimeSeries(ctx, &monitoringpb.ListTimeSeriesRequest{
+		Name:   fmt.Sprintf(gcpProjectResourceFormat, metricsProject(attrs, g.projectID)),
+		Filter: fmt.Sprintf(`metric.type="%s" AND %s="%s"`, storageRequestCountMetric, metricGroupByBucket, bucketName),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(endTime.Add(-window)),
+			EndTime:   timestamppb.New(endTime),
+		},
+		Aggregation: &monitoringpb.Aggregation{
+			AlignmentPeriod:    durationpb.New(window),
+			PerSeriesAligner:   monitoringpb.Aggregation_ALIGN_SUM,
+			CrossSeriesReducer: monitoringpb.Aggregation_REDUCE_SUM,
+			GroupByFields:      []string{metricGroupByBucket},
+		},
+	})
+
+	stats := storage.AccessStats{Window: window}
+	for {
+		resp, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return storage.AccessStats{}, fmt.Errorf("error getting request count for bucket %s: %w", bucketName, err)
+		}
+		for _, point := range resp.GetPoints() {
+			stats.Requests += extractUsageValue(point.GetValue())
+		}
+	}
+	// A bucket nobody requested reports no series at all, which is a count of 0
+	return stats, nil
+}
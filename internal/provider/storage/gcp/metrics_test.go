@@ -1,10 +1,17 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"io"
+	"log/slog"
 	"testing"
+	"time"
 
 	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	gcpstorage "cloud.google.com/go/storage"
+	"google.golang.org/api/option"
 )
 
 func TestExtractUsageValue_NilInput(t *testing.T) {
@@ -72,3 +79,59 @@ func TestBuildMetricsRequest_CorrectFields(t *testing.T) {
 		t.Errorf("expected GroupByFields=[resource.labels.bucket_name], got %v", req.Aggregation.GroupByFields)
 	}
 }
+
+func TestMetricsProject(t *testing.T) {
+	withNumber := &gcpstorage.BucketAttrs{ProjectNumber: 123456789}
+	if got := metricsProject(withNumber, "primary"); got != "123456789" {
+		t.Errorf("expected the bucket's project number, got %q", got)
+	}
+
+	withoutNumber := &gcpstorage.BucketAttrs{}
+	if got := metricsProject(withoutNumber, "primary"); got != "primary" {
+		t.Errorf("expected fallback project, got %q", got)
+	}
+}
+
+func TestGetCachedBucketUsages_ServesFreshEntry(t *testing.T) {
+	metricsCache := cache.New(t.TempDir(), time.Hour)
+	want := map[string]int64{"bucket-a": 42}
+	if err := metricsCache.Put(metricsCacheKeyPrefix+"proj", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// No monitoring client is configured; a cache hit must not need one
+	g := &GCPStorage{
+		clientOpts:   []option.ClientOption{option.WithEndpoint("localhost:1"), option.WithoutAuthentication()},
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		metricsCache: metricsCache,
+	}
+
+	got, err := g.getCachedBucketUsages(context.Background(), "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["bucket-a"] != 42 {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if g.monitoringClient != nil {
+		t.Error("expected the cached entry to be served without the Monitoring API")
+	}
+}
+
+func TestGetCachedBucketUsages_EmulatorSkipsMonitoring(t *testing.T) {
+	g := &GCPStorage{
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		noUsageMetrics: true,
+	}
+
+	got, err := g.getCachedBucketUsages(context.Background(), "proj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no usage, got %v", got)
+	}
+	if g.monitoringClient != nil {
+		t.Error("expected the Monitoring API to be left alone")
+	}
+}
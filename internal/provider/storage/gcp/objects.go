@@ -2,13 +2,18 @@
 package gcp
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"hash"
 	"io"
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/storage/shared"
+	"maps"
+	"strings"
 
 	gcpstorage "cloud.google.com/go/storage"
 	"google.golang.org/api/iterator"
@@ -57,6 +62,24 @@ func (g *GCPStorage) ListObjects(ctx context.Context, bucketName string, prefix
 	return result, nil
 }
 
+func (g *GCPStorage) WalkObjects(ctx context.Context, bucketName string, prefix string, fn func(storage.Object) error) error {
+	g.logger.Debug("Starting GCP WalkObjects operation", "bucket", bucketName, "prefix", prefix)
+
+	it := g.client.Bucket(bucketName).Objects(ctx, &gcpstorage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error iterating objects: %w", err)
+		}
+		if err := fn(mapObjectAttributes(attrs, nil)); err != nil {
+			return err
+		}
+	}
+}
+
 func (g *GCPStorage) DescribeObject(ctx context.Context, bucketName string, objectKey string) (storage.Object, error) {
 	g.logger.Debug("Starting GCP DescribeObject operation", "bucket", bucketName, "object", objectKey)
 
@@ -141,7 +164,10 @@ func (g *GCPStorage) DownloadObject(ctx context.Context, bucketName string, obje
 func (g *GCPStorage) UploadObject(ctx context.Context, opts storage.UploadObjectOptions, reader io.Reader) error {
 	g.logger.Debug("Starting GCP UploadObject operation", "bucket", opts.BucketName, "key", opts.ObjectKey)
 
-	writer := g.client.Bucket(opts.BucketName).Object(opts.ObjectKey).NewWriter(ctx)
+	// Writing the same data again is harmless, so the upload is retried even
+	// without a generation precondition
+	obj := g.client.Bucket(opts.BucketName).Object(opts.ObjectKey).Retryer(gcpstorage.WithPolicy(gcpstorage.RetryAlways))
+	writer := obj.NewWriter(ctx)
 
 	contentType := opts.ContentType
 	if contentType == "" {
@@ -151,6 +177,30 @@ func (g *GCPStorage) UploadObject(ctx context.Context, opts storage.UploadObject
 		writer.ContentType = contentType
 	}
 	writer.Metadata = opts.Metadata
+	writer.CacheControl = opts.CacheControl
+	writer.ContentDisposition = opts.ContentDisposition
+	writer.ContentEncoding = opts.ContentEncoding
+	writer.ContentLanguage = opts.ContentLanguage
+
+	// Cloud Storage computes MD5 and CRC32C for every object. A seekable source
+	// is hashed before the upload, so that Cloud Storage rejects data that does
+	// not match; any other source is hashed as it is sent and compared after
+	var sum hash.Hash
+	if opts.ChecksumAlgorithm != "" {
+		var err error
+		if sum, err = uploadChecksum(opts.ChecksumAlgorithm); err != nil {
+			return err
+		}
+		if seeker, ok := reader.(io.Seeker); ok {
+			if err := hashAhead(seeker, reader, sum); err != nil {
+				return fmt.Errorf("computing the checksum of object %s: %w", opts.ObjectKey, err)
+			}
+			setWriterChecksum(writer, sum.Sum(nil), opts.ChecksumAlgorithm)
+			sum = nil
+		} else {
+			reader = io.TeeReader(reader, sum)
+		}
+	}
 
 	if _, err := io.Copy(writer, reader); err != nil {
 		writer.Close()
@@ -161,9 +211,69 @@ func (g *GCPStorage) UploadObject(ctx context.Context, opts storage.UploadObject
 		return fmt.Errorf("uploading object %s to bucket %s: %w", opts.ObjectKey, opts.BucketName, err)
 	}
 
+	if sum != nil {
+		if remote := writer.Attrs(); !bytes.Equal(sum.Sum(nil), attrsChecksum(remote, opts.ChecksumAlgorithm)) {
+			// Only the generation just written is deleted, not a later upload
+			mismatch := fmt.Errorf("uploaded object %s to bucket %s, but its %s checksum does not match the data sent",
+				opts.ObjectKey, opts.BucketName, strings.ToUpper(opts.ChecksumAlgorithm))
+			if err := obj.If(gcpstorage.Conditions{GenerationMatch: remote.Generation}).Delete(ctx); err != nil {
+				return fmt.Errorf("%w; deleting it failed: %w", mismatch, err)
+			}
+			return fmt.Errorf("%w; it was deleted", mismatch)
+		}
+	}
+
 	return nil
 }
 
+// hashAhead writes the rest of reader to sum, then rewinds reader to where it
+// was.
+func hashAhead(seeker io.Seeker, reader io.Reader, sum hash.Hash) error {
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(sum, reader); err != nil {
+		return err
+	}
+	_, err = seeker.Seek(start, io.SeekStart)
+	return err
+}
+
+// setWriterChecksum makes writer send checksum, so that Cloud Storage rejects
+// the upload if the data it receives does not match.
+func setWriterChecksum(writer *gcpstorage.Writer, checksum []byte, algorithm string) {
+	if strings.ToUpper(algorithm) == storage.ChecksumMD5 {
+		writer.MD5 = checksum
+		return
+	}
+	writer.CRC32C = binary.BigEndian.Uint32(checksum)
+	writer.SendCRC32C = true
+}
+
+// uploadChecksum returns a hash computing one of the checksums Cloud Storage
+// reports for an object.
+func uploadChecksum(algorithm string) (hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case storage.ChecksumCRC32C, storage.ChecksumMD5:
+		return storage.NewChecksum(algorithm)
+	default:
+		return nil, fmt.Errorf("Cloud Storage does not support %s checksums (expected %s or %s)",
+			algorithm, storage.ChecksumCRC32C, storage.ChecksumMD5)
+	}
+}
+
+// attrsChecksum returns the binary checksum of an object for algorithm.
+func attrsChecksum(attrs *gcpstorage.ObjectAttrs, algorithm string) []byte {
+	if attrs == nil {
+		return nil
+	}
+	if strings.ToUpper(algorithm) == storage.ChecksumMD5 {
+		return attrs.MD5
+	}
+	return binary.BigEndian.AppendUint32(nil, attrs.CRC32C)
+}
+
 func (g *GCPStorage) DeleteObject(ctx context.Context, bucketName, objectKey string) error {
 	g.logger.Debug("Starting GCP DeleteObject operation", "bucket", bucketName, "key", objectKey)
 
@@ -190,3 +300,60 @@ func (g *GCPStorage) CopyObject(ctx context.Context, srcBucket, srcKey, destBuck
 	}
 	return nil
 }
+
+var _ storage.MetadataUpdater = (*GCPStorage)(nil)
+
+func (g *GCPStorage) UpdateObjectMetadata(ctx context.Context, bucketName, objectKey string, metadata map[string]string) error {
+	g.logger.Debug("Starting GCP UpdateObjectMetadata operation", "bucket", bucketName, "key", objectKey)
+
+	// An empty map would clear the metadata; a patch merges the given keys into it
+	if len(metadata) == 0 {
+		return nil
+	}
+	_, err := g.client.Bucket(bucketName).Object(objectKey).Update(ctx, gcpstorage.ObjectAttrsToUpdate{Metadata: metadata})
+	if err != nil {
+		return fmt.Errorf("updating metadata of object %s in bucket %s: %w", objectKey, bucketName, err)
+	}
+	return nil
+}
+
+var _ storage.AttributesSetter = (*GCPStorage)(nil)
+
+// SetObjectAttributes patches the object's headers and metadata in a single
+// request. A patch merges metadata keys, so keys to be removed are sent with an
+// empty value, which deletes them.
+func (g *GCPStorage) SetObjectAttributes(ctx context.Context, bucketName, objectKey string, attrs storage.ObjectAttributes) error {
+	g.logger.Debug("Starting GCP SetObjectAttributes operation", "bucket", bucketName, "key", objectKey)
+
+	obj := g.client.Bucket(bucketName).Object(objectKey)
+	current, err := obj.Attrs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to describe GCS object: %w", err)
+	}
+	// The update must find the object as last seen, so a concurrent change is not lost
+	obj = obj.If(gcpstorage.Conditions{MetagenerationMatch: current.Metageneration})
+	metadata := maps.Clone(attrs.Metadata)
+	for key := range current.Metadata {
+		if _, ok := attrs.Metadata[key]; !ok {
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			metadata[key] = ""
+		}
+	}
+
+	update := gcpstorage.ObjectAttrsToUpdate{
+		ContentType:        attrs.ContentType,
+		ContentEncoding:    attrs.ContentEncoding,
+		ContentLanguage:    attrs.ContentLanguage,
+		CacheControl:       attrs.CacheControl,
+		ContentDisposition: attrs.ContentDisposition,
+	}
+	if len(metadata) > 0 {
+		update.Metadata = metadata
+	}
+	if _, err := obj.Update(ctx, update); err != nil {
+		return fmt.Errorf("setting attributes of object %s in bucket %s: %w", objectKey, bucketName, err)
+	}
+	return nil
+}
@@ -1,8 +1,14 @@
 package gcp
 
 import (
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -97,3 +103,109 @@ func TestMapObjectAttributes_AllFieldsMapped(t *testing.T) {
 		t.Errorf("Metadata: expected key=value, got %v", result.Metadata)
 	}
 }
+
+func TestUploadChecksum(t *testing.T) {
+	h, err := uploadChecksum("crc32c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.Write([]byte("123456789"))
+
+	attrs := &gcpstorage.ObjectAttrs{CRC32C: 0xE3069283}
+	if got := attrsChecksum(attrs, storage.ChecksumCRC32C); string(got) != string(h.Sum(nil)) {
+		t.Errorf("checksum = %x, want %x", h.Sum(nil), got)
+	}
+
+	if _, err := uploadChecksum(storage.ChecksumSHA256); err == nil {
+		t.Error("expected an error for a checksum Cloud Storage does not compute")
+	}
+}
+
+func TestSetObjectAttributes_SingleConditionalPatch(t *testing.T) {
+	var patches int
+	var query string
+	var body struct {
+		ContentType string             `json:"contentType"`
+		Metadata    map[string]*string `json:"metadata"`
+	}
+	g := newDescribeTestStorage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPatch {
+			patches++
+			query = r.URL.Query().Get("ifMetagenerationMatch")
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("invalid patch body: %v", err)
+			}
+		}
+		io.WriteString(w, `{"name": "key", "bucket": "bkt", "metageneration": "4", "metadata": {"old": "1", "keep": "2"}}`)
+	}))
+
+	err := g.SetObjectAttributes(context.Background(), "bkt", "key", storage.ObjectAttributes{
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"keep": "3"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patches != 1 {
+		t.Fatalf("expected a single patch, got %d", patches)
+	}
+	if query != "4" {
+		t.Errorf("ifMetagenerationMatch = %q, want 4", query)
+	}
+	if body.ContentType != "text/plain" {
+		t.Errorf("contentType = %q, want text/plain", body.ContentType)
+	}
+	if v, ok := body.Metadata["old"]; !ok || (v != nil && *v != "") {
+		t.Errorf("expected the removed key to be deleted, got metadata %v", body.Metadata)
+	}
+	if v := body.Metadata["keep"]; v == nil || *v != "3" {
+		t.Errorf("expected keep=3 in the patch, got metadata %v", body.Metadata)
+	}
+}
+
+func TestUploadObject_SendsChecksumOfSeekableSource(t *testing.T) {
+	var uploaded string
+	g := newDescribeTestStorage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploaded = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"name": "key", "bucket": "bkt", "generation": "7", "crc32c": "4waSgw=="}`)
+	}))
+
+	opts := storage.UploadObjectOptions{BucketName: "bkt", ObjectKey: "key", ChecksumAlgorithm: "crc32c"}
+	if err := g.UploadObject(context.Background(), opts, bytes.NewReader([]byte("123456789"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(uploaded, `"crc32c":"4waSgw=="`) {
+		t.Errorf("expected the upload to carry the CRC32C checksum, got:\n%s", uploaded)
+	}
+	if !strings.Contains(uploaded, "123456789") {
+		t.Errorf("expected the data to be sent in full after hashing, got:\n%s", uploaded)
+	}
+}
+
+func TestUploadObject_DeletesMismatchedUpload(t *testing.T) {
+	var deleted string
+	g := newDescribeTestStorage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = r.URL.Query().Get("ifGenerationMatch")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"name": "key", "bucket": "bkt", "generation": "7", "crc32c": "AAAAAA=="}`)
+	}))
+
+	// A non-seekable source is only verified once uploaded
+	src := io.MultiReader(strings.NewReader("123456789"))
+	opts := storage.UploadObjectOptions{BucketName: "bkt", ObjectKey: "key", ChecksumAlgorithm: "crc32c"}
+	err := g.UploadObject(context.Background(), opts, src)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	if deleted != "7" {
+		t.Errorf("expected the uploaded generation to be deleted, got ifGenerationMatch=%q", deleted)
+	}
+}
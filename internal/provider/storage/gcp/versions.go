@@ -0,0 +1,84 @@
+package gcp
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"strconv"
+
+	gcpstorage "cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+var _ storage.VersionManager = (*GCPStorage)(nil)
+
+// WalkObjectVersions visits every generation under prefix. Cloud Storage lists
+// the generations of an object oldest first, so each object's are gathered
+// and passed on newest first.
+func (g *GCPStorage) WalkObjectVersions(ctx context.Context, bucketName, prefix string, fn func(storage.ObjectVersion) error) error {
+	g.logger.Debug("Starting GCP WalkObjectVersions operation", "bucket", bucketName, "prefix", prefix)
+
+	var generations []storage.ObjectVersion
+	flush := func() error {
+		slices.SortFunc(generations, func(a, b storage.ObjectVersion) int {
+			return cmp.Compare(generationOf(b), generationOf(a))
+		})
+		for _, v := range generations {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+		generations = generations[:0]
+		return nil
+	}
+
+	it := g.client.Bucket(bucketName).Objects(ctx, &gcpstorage.Query{Prefix: prefix, Versions: true})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return flush()
+		}
+		if err != nil {
+			return fmt.Errorf("error iterating object versions: %w", err)
+		}
+		if len(generations) > 0 && generations[0].Key != attrs.Name {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		generations = append(generations, mapObjectVersion(attrs))
+	}
+}
+
+func mapObjectVersion(attrs *gcpstorage.ObjectAttrs) storage.ObjectVersion {
+	return storage.ObjectVersion{
+		Key:             attrs.Name,
+		VersionID:       strconv.FormatInt(attrs.Generation, 10),
+		Size:            attrs.Size,
+		StorageClass:    attrs.StorageClass,
+		LastModified:    attrs.Created,
+		IsLatest:        attrs.Deleted.IsZero(),
+		NoncurrentSince: attrs.Deleted,
+	}
+}
+
+func generationOf(v storage.ObjectVersion) int64 {
+	generation, _ := strconv.ParseInt(v.VersionID, 10, 64)
+	return generation
+}
+
+func (g *GCPStorage) DeleteObjectVersion(ctx context.Context, bucketName, objectKey, versionID string) error {
+	g.logger.Debug("Starting GCP DeleteObjectVersion operation", "bucket", bucketName, "key", objectKey, "generation", versionID)
+
+	generation, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid generation %q of object %s: %w", versionID, objectKey, err)
+	}
+	if err := g.client.Bucket(bucketName).Object(objectKey).Generation(generation).Delete(ctx); err != nil {
+		return fmt.Errorf("deleting generation %d of object %s from bucket %s: %w", generation, objectKey, bucketName, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package gcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestWalkObjectVersions_NewestFirst(t *testing.T) {
+	g := newDescribeTestStorage(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("versions") != "true" {
+			t.Errorf("expected versions listed, got query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"items": [
+			{"name": "a.txt", "generation": "1", "size": "5", "timeCreated": "2024-01-01T00:00:00Z", "timeDeleted": "2024-02-01T00:00:00Z"},
+			{"name": "a.txt", "generation": "2", "size": "6", "timeCreated": "2024-02-01T00:00:00Z"},
+			{"name": "b.txt", "generation": "3", "size": "7", "timeCreated": "2024-03-01T00:00:00Z"}
+		]}`)
+	}))
+
+	var versions []storage.ObjectVersion
+	err := g.WalkObjectVersions(context.Background(), "data", "", func(v storage.ObjectVersion) error {
+		versions = append(versions, v)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, v := range versions {
+		ids = append(ids, v.Key+"#"+v.VersionID)
+	}
+	if !slices.Equal(ids, []string{"a.txt#2", "a.txt#1", "b.txt#3"}) {
+		t.Fatalf("expected generations by object, newest first, got %v", ids)
+	}
+	if !versions[0].IsLatest || versions[1].IsLatest || versions[1].NoncurrentSince.IsZero() {
+		t.Errorf("expected only the live generation latest, got %+v", versions[:2])
+	}
+}
+
+func TestDeleteObjectVersion_InvalidGeneration(t *testing.T) {
+	g := newDescribeTestStorage(t, http.NotFoundHandler())
+	if err := g.DeleteObjectVersion(context.Background(), "data", "a.txt", "not-a-number"); err == nil {
+		t.Error("expected an error for a generation that is not a number")
+	}
+}
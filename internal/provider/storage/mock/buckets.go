@@ -0,0 +1,134 @@
+package mock
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (m *MockStorage) ListBuckets(ctx context.Context) ([]storage.Bucket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	buckets := make([]storage.Bucket, 0, len(m.buckets))
+	for _, name := range slices.Sorted(maps.Keys(m.buckets)) {
+		buckets = append(buckets, m.buckets[name].snapshot())
+	}
+	return buckets, nil
+}
+
+func (m *MockStorage) DescribeBucket(ctx context.Context, bucketName string) (storage.Bucket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.getBucket(bucketName)
+	if err != nil {
+		return storage.Bucket{}, err
+	}
+	return b.snapshot(), nil
+}
+
+func (m *MockStorage) CreateBucket(ctx context.Context, opts storage.CreateBucketOptions) (storage.CreateBucketResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.buckets[opts.Name]; exists {
+		return storage.CreateBucketResult{}, fmt.Errorf("bucket %s already exists", opts.Name)
+	}
+
+	now := time.Now().UTC()
+	bucket := storage.Bucket{
+		Name:         opts.Name,
+		Provider:     domain.Mock,
+		Location:     opts.Location,
+		StorageClass: opts.StorageClass,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Labels:       maps.Clone(opts.Labels),
+	}
+	if bucket.StorageClass == "" {
+		bucket.StorageClass = "STANDARD"
+	}
+	if opts.Versioning != nil {
+		bucket.Versioning = &storage.Versioning{Enabled: *opts.Versioning}
+	}
+	if opts.UniformBucketLevelAccess != nil {
+		bucket.UniformBucketLevelAccess = &storage.UniformBucketLevelAccess{Enabled: *opts.UniformBucketLevelAccess}
+	}
+	if opts.PublicAccessPrevention != nil {
+		bucket.PublicAccessPrevention = *opts.PublicAccessPrevention
+	}
+
+	m.buckets[opts.Name] = &mockBucket{bucket: bucket, objects: make(map[string]mockObject)}
+	m.logger.Debug("Created mock bucket", "bucket", opts.Name)
+	return storage.CreateBucketResult{}, nil
+}
+
+// DeleteBucket fails for a bucket that still holds objects, as the real providers do.
+func (m *MockStorage) DeleteBucket(ctx context.Context, bucketName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.getBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if len(b.objects) > 0 {
+		return fmt.Errorf("bucket %s is not empty", bucketName)
+	}
+	delete(m.buckets, bucketName)
+	return nil
+}
+
+var _ storage.BucketLabeler = (*MockStorage)(nil)
+
+func (m *MockStorage) UpdateBucketLabels(ctx context.Context, bucketName string, labels map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.getBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if b.bucket.Labels == nil {
+		b.bucket.Labels = make(map[string]string, len(labels))
+	}
+	maps.Copy(b.bucket.Labels, labels)
+	return nil
+}
+
+var _ storage.OrganizationBucketLister = (*MockStorage)(nil)
+
+// ListOrganizationBuckets lists every bucket, as if the organization gave
+// each team its own project (e.g., "acme-web" for the "team: web" label).
+func (m *MockStorage) ListOrganizationBuckets(ctx context.Context, scope string) ([]storage.Bucket, error) {
+	if !strings.HasPrefix(scope, "organizations/") && !strings.HasPrefix(scope, "folders/") {
+		return nil, fmt.Errorf("invalid scope %q: expected organizations/ID or folders/ID", scope)
+	}
+	buckets, err := m.ListBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range buckets {
+		buckets[i].Project = "acme-" + cmp.Or(buckets[i].Labels["team"], "shared")
+	}
+	return buckets, nil
+}
+
+// snapshot returns a copy of the bucket with its current usage, safe to hand
+// out after the lock is released.
+func (b *mockBucket) snapshot() storage.Bucket {
+	bucket := b.bucket
+	bucket.Labels = maps.Clone(b.bucket.Labels)
+	bucket.UsageBytes = 0
+	for _, obj := range b.objects {
+		bucket.UsageBytes += obj.object.Size
+	}
+	return bucket
+}
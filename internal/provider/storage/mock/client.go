@@ -0,0 +1,101 @@
+// Package mock implements storage.Storage in memory. Every client starts from
+// the same fake buckets and objects (see seed.go), so output is reproducible
+// for tests, screenshots and tutorials. Changes last as long as the client.
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/registry"
+	"log/slog"
+	"sync"
+)
+
+func init() {
+	registry.RegisterProvider("mock", registry.Registration[storage.Storage]{
+		ConfigCheck: isConfigured,
+		Initializer: initialize,
+	})
+}
+
+// isConfigured checks if the mock provider is enabled with mock.enabled or --demo.
+func isConfigured(cfg *config.Config) bool {
+	return cfg.Mock != nil && cfg.Mock.Enabled
+}
+
+// initialize creates a mock storage client holding the seed data.
+func initialize(ctx context.Context, cfg *config.Config, logger *slog.Logger) (storage.Storage, error) {
+	if !isConfigured(cfg) {
+		return nil, fmt.Errorf("mock provider is not enabled")
+	}
+	return NewMockStorage(logger), nil
+}
+
+// errNotFound marks a bucket or object that does not exist
+var errNotFound = errors.New("not found")
+
+// MockStorage implements storage.Storage with buckets and objects held in memory.
+type MockStorage struct {
+	mu          sync.Mutex
+	buckets     map[string]*mockBucket
+	subscribers []*subscriber
+	// history is the audit log: the seed writes, then the changes made through this client
+	history []mockChange
+	logger  *slog.Logger
+}
+
+type mockBucket struct {
+	bucket  storage.Bucket
+	objects map[string]mockObject
+}
+
+type mockObject struct {
+	object storage.Object
+	data   []byte
+}
+
+var _ storage.Storage = (*MockStorage)(nil)
+
+// NewMockStorage returns a client holding the seed buckets and objects.
+func NewMockStorage(logger *slog.Logger) *MockStorage {
+	m := &MockStorage{
+		buckets: make(map[string]*mockBucket),
+		logger:  logger,
+	}
+	for _, b := range seedBuckets() {
+		m.buckets[b.bucket.Name] = b
+		m.history = append(m.history, seedHistory(b)...)
+	}
+	return m
+}
+
+func (m *MockStorage) ProviderName() domain.Provider {
+	return domain.Mock
+}
+
+// IsNotFound reports whether err means the bucket or object does not exist.
+func (m *MockStorage) IsNotFound(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
+// IsAuthError is always false: the mock provider needs no credentials.
+func (m *MockStorage) IsAuthError(err error) bool {
+	return false
+}
+
+func (m *MockStorage) Close() error {
+	return nil
+}
+
+// getBucket returns the named bucket. The caller must hold m.mu.
+func (m *MockStorage) getBucket(name string) (*mockBucket, error) {
+	b, ok := m.buckets[name]
+	if !ok {
+		return nil, fmt.Errorf("bucket %s: %w", name, errNotFound)
+	}
+	return b, nil
+}
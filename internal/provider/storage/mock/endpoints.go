@@ -0,0 +1,27 @@
+package mock
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"strings"
+)
+
+var _ storage.BucketURLResolver = (*MockStorage)(nil)
+
+// BucketURL returns the bucket's URL under the made-up mock endpoint.
+func (m *MockStorage) BucketURL(ctx context.Context, bucketName, style string) (string, error) {
+	m.mu.Lock()
+	_, err := m.getBucket(bucketName)
+	m.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	return bucketURL(bucketName, style), nil
+}
+
+func bucketURL(bucketName, style string) string {
+	if style == storage.URLStyleVirtualHosted {
+		return strings.TrimSuffix(strings.Replace(signedURLBase, "://", "://"+bucketName+".", 1), "/")
+	}
+	return signedURLBase + bucketName
+}
@@ -0,0 +1,77 @@
+package mock
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"time"
+)
+
+// eventBuffer bounds the events queued for a slow subscriber; later events
+// are dropped, like notifications lost in transit
+const eventBuffer = 64
+
+var _ storage.EventTailer = (*MockStorage)(nil)
+
+// subscriber receives the events of a bucket for TailEvents.
+type subscriber struct {
+	bucket string
+	events chan storage.ObjectEvent
+}
+
+// TailEvents streams the uploads, copies and deletes made through this client
+// to fn. The mock has no notification service, so only changes made in the
+// same process are seen.
+func (m *MockStorage) TailEvents(ctx context.Context, bucketName string, ready func(source string), fn func(storage.ObjectEvent) error) error {
+	m.mu.Lock()
+	if _, err := m.getBucket(bucketName); err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	sub := &subscriber{bucket: bucketName, events: make(chan storage.ObjectEvent, eventBuffer)}
+	m.subscribers = append(m.subscribers, sub)
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.subscribers = slices.DeleteFunc(m.subscribers, func(s *subscriber) bool { return s == sub })
+	}()
+
+	ready("in-memory notifications of " + bucketName)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-sub.events:
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish queues an event for the subscribers of bucket and records it in
+// the audit log. The caller must hold m.mu.
+func (m *MockStorage) publish(eventType storage.ObjectEventType, bucket string, obj storage.Object) {
+	event := storage.ObjectEvent{
+		Time:   time.Now().UTC(),
+		Type:   eventType,
+		Name:   string(eventType),
+		Bucket: bucket,
+		Key:    obj.Key,
+	}
+	if eventType == storage.ObjectCreated {
+		event.Size = obj.Size
+	}
+	m.record(event)
+	for _, sub := range m.subscribers {
+		if sub.bucket != bucket {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			m.logger.Debug("Dropped mock object event for a slow subscriber", "bucket", bucket, "key", obj.Key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package mock
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"time"
+)
+
+const (
+	// mockPrincipal is who makes the changes through this client
+	mockPrincipal = "demo@example.com"
+	// seedPrincipal is who wrote the objects of the seed data
+	seedPrincipal = "seed@example.com"
+)
+
+// mockChange is a change recorded in the mock's audit log.
+type mockChange struct {
+	bucket, key string
+	change      storage.ObjectChange
+}
+
+var _ storage.ObjectHistoryReader = (*MockStorage)(nil)
+
+// ObjectHistory returns the changes made to the object through this client,
+// after the write of the seed data for seeded objects.
+func (m *MockStorage) ObjectHistory(ctx context.Context, bucketName, objectKey string, since time.Time, limit int) ([]storage.ObjectChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.getBucket(bucketName); err != nil {
+		return nil, err
+	}
+	var changes []storage.ObjectChange
+	for _, c := range m.history {
+		if c.bucket == bucketName && c.key == objectKey && !c.change.Time.Before(since) {
+			changes = append(changes, c.change)
+		}
+	}
+	slices.Reverse(changes)
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+// seedHistory returns the writes of the seed objects of b, oldest first.
+func seedHistory(b *mockBucket) []mockChange {
+	changes := make([]mockChange, 0, len(b.objects))
+	for key, obj := range b.objects {
+		changes = append(changes, mockChange{bucket: b.bucket.Name, key: key, change: storage.ObjectChange{
+			Time:      obj.object.CreatedAt,
+			Type:      storage.ChangeWrite,
+			Operation: "storage.objects.create",
+			Principal: seedPrincipal,
+		}})
+	}
+	slices.SortFunc(changes, func(a, b mockChange) int { return a.change.Time.Compare(b.change.Time) })
+	return changes
+}
+
+// record adds an object event to the audit log. The caller must hold m.mu.
+func (m *MockStorage) record(event storage.ObjectEvent) {
+	change := storage.ObjectChange{Time: event.Time, Type: storage.ChangeOther, Operation: string(event.Type), Principal: mockPrincipal}
+	switch event.Type {
+	case storage.ObjectCreated:
+		change.Type, change.Operation = storage.ChangeWrite, "storage.objects.create"
+	case storage.ObjectDeleted:
+		change.Type, change.Operation = storage.ChangeDelete, "storage.objects.delete"
+	case storage.ObjectMetadataUpdated:
+		change.Type, change.Operation = storage.ChangeMetadata, "storage.objects.update"
+	}
+	m.history = append(m.history, mockChange{bucket: event.Bucket, key: event.Key, change: change})
+}
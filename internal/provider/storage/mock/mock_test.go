@@ -0,0 +1,316 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func newTestStorage() *MockStorage {
+	return NewMockStorage(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestIsConfigured(t *testing.T) {
+	if isConfigured(&config.Config{}) {
+		t.Error("expected the mock provider to be off by default")
+	}
+	if !isConfigured(&config.Config{Mock: &config.MockConfig{Enabled: true}}) {
+		t.Error("expected mock.enabled to turn the mock provider on")
+	}
+}
+
+func TestListBuckets_Deterministic(t *testing.T) {
+	ctx := context.Background()
+	first, err := newTestStorage().ListBuckets(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := newTestStorage().ListBuckets(ctx)
+
+	if len(first) != len(seedSpecs) {
+		t.Fatalf("got %d buckets, want %d", len(first), len(seedSpecs))
+	}
+	for i := range first {
+		if first[i].Name != second[i].Name || !first[i].CreatedAt.Equal(second[i].CreatedAt) || first[i].UsageBytes != second[i].UsageBytes {
+			t.Errorf("bucket %d differs between clients: %+v vs %+v", i, first[i], second[i])
+		}
+		if first[i].UsageBytes <= 0 {
+			t.Errorf("expected %s to have usage", first[i].Name)
+		}
+	}
+	if !slices.IsSortedFunc(first, func(a, b storage.Bucket) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Error("expected buckets sorted by name")
+	}
+}
+
+func TestListObjects_GroupsDirectories(t *testing.T) {
+	list, err := newTestStorage().ListObjects(context.Background(), "acme-data-lake", "raw/2024/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"raw/2024/01/", "raw/2024/02/", "raw/2024/03/"}
+	if !slices.Equal(list.CommonPrefixes, want) {
+		t.Errorf("prefixes = %v, want %v", list.CommonPrefixes, want)
+	}
+	if len(list.Objects) != 0 {
+		t.Errorf("expected no objects directly under the prefix, got %d", len(list.Objects))
+	}
+}
+
+func TestObjectRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+	opts := storage.UploadObjectOptions{BucketName: "acme-web-assets", ObjectKey: "notes/hello.txt"}
+	if err := m.UploadObject(ctx, opts, strings.NewReader("hello")); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	obj, err := m.DescribeObject(ctx, opts.BucketName, opts.ObjectKey)
+	if err != nil {
+		t.Fatalf("describe: %v", err)
+	}
+	if obj.Size != 5 || obj.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected object: size %d, content type %q", obj.Size, obj.ContentType)
+	}
+
+	if err := m.CopyObject(ctx, opts.BucketName, opts.ObjectKey, "acme-backups", "copy.txt"); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	rc, err := m.DownloadObject(ctx, "acme-backups", "copy.txt")
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer rc.Close()
+	data, _ := io.ReadAll(rc)
+	if !bytes.Equal(data, []byte("hello")) {
+		t.Errorf("downloaded %q, want hello", data)
+	}
+
+	if err := m.DeleteObject(ctx, opts.BucketName, opts.ObjectKey); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := m.DescribeObject(ctx, opts.BucketName, opts.ObjectKey); !m.IsNotFound(err) {
+		t.Errorf("expected a not found error after delete, got %v", err)
+	}
+}
+
+func TestBucketLifecycle(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+	if _, err := m.CreateBucket(ctx, storage.CreateBucketOptions{Name: "scratch", Location: "EU"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := m.CreateBucket(ctx, storage.CreateBucketOptions{Name: "scratch", Location: "EU"}); err == nil {
+		t.Error("expected an error creating an existing bucket")
+	}
+	if err := m.DeleteBucket(ctx, "acme-backups"); err == nil {
+		t.Error("expected an error deleting a bucket that holds objects")
+	}
+	if err := m.DeleteBucket(ctx, "scratch"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := m.DescribeBucket(ctx, "scratch"); !m.IsNotFound(err) {
+		t.Errorf("expected a not found error after delete, got %v", err)
+	}
+}
+
+func TestWalkObjects(t *testing.T) {
+	var keys []string
+	err := newTestStorage().WalkObjects(context.Background(), "acme-data-lake", "raw/", func(obj storage.Object) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"raw/2024/01/events.json", "raw/2024/02/events.json", "raw/2024/03/events.json"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+}
+
+func TestUpdateObjectMetadata(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+	err := m.UploadObject(ctx, storage.UploadObjectOptions{BucketName: "acme-backups", ObjectKey: "a.txt", Metadata: map[string]string{"owner": "ops", "team": "infra"}}, strings.NewReader("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.UpdateObjectMetadata(ctx, "acme-backups", "a.txt", map[string]string{"owner": "data"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := m.DescribeObject(ctx, "acme-backups", "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Metadata["owner"] != "data" || obj.Metadata["team"] != "infra" {
+		t.Errorf("expected owner replaced and team kept, got %v", obj.Metadata)
+	}
+
+	if err := m.UpdateObjectMetadata(ctx, "acme-backups", "missing.txt", map[string]string{"owner": "data"}); err == nil {
+		t.Error("expected an error for a missing object")
+	}
+}
+
+func TestUpdateBucketLabels(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+
+	if err := m.UpdateBucketLabels(ctx, "acme-backups", map[string]string{"env": "prod", "team": "ops"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := m.DescribeBucket(ctx, "acme-backups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Labels["env"] != "prod" || b.Labels["team"] != "ops" {
+		t.Errorf("expected env added and team replaced, got %v", b.Labels)
+	}
+
+	if err := m.UpdateBucketLabels(ctx, "missing", map[string]string{"env": "prod"}); err == nil {
+		t.Error("expected an error for a missing bucket")
+	}
+}
+
+func TestListOrganizationBuckets(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+
+	buckets, err := m.ListOrganizationBuckets(ctx, "organizations/123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) == 0 {
+		t.Fatal("expected the seed buckets")
+	}
+	for _, b := range buckets {
+		if b.Project == "" {
+			t.Errorf("expected bucket %s to be annotated with a project", b.Name)
+		}
+	}
+
+	if _, err := m.ListOrganizationBuckets(ctx, "projects/p"); err == nil {
+		t.Error("expected an error for a project scope")
+	}
+}
+
+func TestSetObjectAttributes(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+	err := m.UploadObject(ctx, storage.UploadObjectOptions{BucketName: "acme-backups", ObjectKey: "a.txt", Metadata: map[string]string{"owner": "ops", "team": "infra"}}, strings.NewReader("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attrs := storage.ObjectAttributes{ContentType: "text/html", CacheControl: "no-cache", Metadata: map[string]string{"owner": "web"}}
+	if err := m.SetObjectAttributes(ctx, "acme-backups", "a.txt", attrs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := m.DescribeObject(ctx, "acme-backups", "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.ContentType != "text/html" || obj.CacheControl != "no-cache" {
+		t.Errorf("expected the headers set, got %+v", obj)
+	}
+	if len(obj.Metadata) != 1 || obj.Metadata["owner"] != "web" {
+		t.Errorf("expected the metadata replaced, got %v", obj.Metadata)
+	}
+}
+
+func TestSignPostPolicy(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+	policy, err := m.SignPostPolicy(ctx, storage.PostPolicyOptions{BucketName: "acme-web-assets", Prefix: "incoming/", MaxSize: 1024, Expires: time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Fields["key"] != "incoming/${filename}" || !strings.HasSuffix(policy.URL, "/acme-web-assets") {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	if _, err := m.SignPostPolicy(ctx, storage.PostPolicyOptions{BucketName: "missing", Expires: time.Hour}); !m.IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing bucket, got %v", err)
+	}
+}
+
+func TestSignURL(t *testing.T) {
+	m := newTestStorage()
+	signed, err := m.SignURL(context.Background(), storage.SignedURLOptions{
+		BucketName: "acme-web-assets",
+		ObjectKey:  "css/site.css",
+		Method:     "get",
+		Expires:    time.Hour,
+		Style:      storage.URLStyleVirtualHosted,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(signed.URL, "https://acme-web-assets.") || !strings.Contains(signed.URL, "/css/site.css?") {
+		t.Errorf("expected a virtual-hosted URL for the object, got %s", signed.URL)
+	}
+	if signed.Method != "GET" {
+		t.Errorf("expected method GET, got %s", signed.Method)
+	}
+}
+
+func TestUploadObject_Checksum(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+	opts := storage.UploadObjectOptions{BucketName: "acme-backups", ObjectKey: "a.txt", ChecksumAlgorithm: storage.ChecksumSHA256}
+	if err := m.UploadObject(ctx, opts, strings.NewReader("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := m.DescribeObject(ctx, "acme-backups", "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.SHA256 != "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=" || obj.CRC32C != "mnG7TA==" {
+		t.Errorf("unexpected checksums: SHA256 %q, CRC32C %q", obj.SHA256, obj.CRC32C)
+	}
+	if obj.ChecksumType != storage.ChecksumTypeFullObject {
+		t.Errorf("checksum type = %q, want %s", obj.ChecksumType, storage.ChecksumTypeFullObject)
+	}
+}
+
+func TestObjectHistory(t *testing.T) {
+	ctx := context.Background()
+	m := newTestStorage()
+
+	changes, err := m.ObjectHistory(ctx, "acme-web-assets", "index.html", time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Type != storage.ChangeWrite || changes[0].Principal != seedPrincipal {
+		t.Errorf("expected the seed write, got %+v", changes)
+	}
+
+	if err := m.UploadObject(ctx, storage.UploadObjectOptions{BucketName: "acme-web-assets", ObjectKey: "index.html"}, strings.NewReader("v2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.DeleteObject(ctx, "acme-web-assets", "index.html"); err != nil {
+		t.Fatal(err)
+	}
+	changes, err = m.ObjectHistory(ctx, "acme-web-assets", "index.html", time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 3 || changes[0].Type != storage.ChangeDelete || changes[1].Principal != mockPrincipal || changes[2].Principal != seedPrincipal {
+		t.Errorf("expected the delete, the upload and the seed write, newest first, got %+v", changes)
+	}
+	if changes, _ := m.ObjectHistory(ctx, "acme-web-assets", "index.html", time.Time{}, 1); len(changes) != 1 {
+		t.Errorf("expected the history to be limited, got %+v", changes)
+	}
+	if changes, _ := m.ObjectHistory(ctx, "acme-web-assets", "index.html", time.Now().Add(time.Hour), 10); len(changes) != 0 {
+		t.Errorf("expected no changes after --since, got %+v", changes)
+	}
+}
@@ -0,0 +1,274 @@
+package mock
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"hash/crc32"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ListObjects lists the objects and "directories" directly under prefix, like
+// a listing with the "/" delimiter.
+func (m *MockStorage) ListObjects(ctx context.Context, bucketName string, prefix string) (storage.ObjectList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.getBucket(bucketName)
+	if err != nil {
+		return storage.ObjectList{}, err
+	}
+
+	list := storage.ObjectList{BucketName: bucketName, Prefix: prefix}
+	for _, key := range b.sortedKeys(prefix) {
+		if i := strings.Index(key[len(prefix):], "/"); i >= 0 {
+			dir := key[:len(prefix)+i+1]
+			if !slices.Contains(list.CommonPrefixes, dir) {
+				list.CommonPrefixes = append(list.CommonPrefixes, dir)
+			}
+			continue
+		}
+		list.Objects = append(list.Objects, b.objects[key].snapshot())
+	}
+	return list, nil
+}
+
+func (m *MockStorage) WalkObjects(ctx context.Context, bucketName string, prefix string, fn func(storage.Object) error) error {
+	m.mu.Lock()
+	b, err := m.getBucket(bucketName)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	var objects []storage.Object
+	for _, key := range b.sortedKeys(prefix) {
+		objects = append(objects, b.objects[key].snapshot())
+	}
+	// fn may call back into the client, so it runs without the lock
+	m.mu.Unlock()
+
+	for _, obj := range objects {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockStorage) DescribeObject(ctx context.Context, bucketName string, objectKey string) (storage.Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, err := m.getObject(bucketName, objectKey)
+	if err != nil {
+		return storage.Object{}, err
+	}
+	return obj.snapshot(), nil
+}
+
+func (m *MockStorage) DownloadObject(ctx context.Context, bucketName string, objectKey string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, err := m.getObject(bucketName, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	// The data is never modified in place, so readers can share it
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (m *MockStorage) UploadObject(ctx context.Context, opts storage.UploadObjectOptions, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read upload data: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.getBucket(opts.BucketName)
+	if err != nil {
+		return err
+	}
+	contentType := cmp.Or(opts.ContentType, shared.DetectContentType(opts.ObjectKey))
+	obj := newMockObject(opts.BucketName, opts.ObjectKey, contentType, data, time.Now().UTC(), opts.Metadata)
+	obj.object.CacheControl = opts.CacheControl
+	obj.object.ContentDisposition = opts.ContentDisposition
+	obj.object.ContentEncoding = opts.ContentEncoding
+	obj.object.ContentLanguage = opts.ContentLanguage
+	if opts.ChecksumAlgorithm != "" {
+		if err := obj.addChecksum(opts.ChecksumAlgorithm); err != nil {
+			return err
+		}
+	}
+	b.objects[opts.ObjectKey] = obj
+	m.publish(storage.ObjectCreated, opts.BucketName, b.objects[opts.ObjectKey].object)
+	return nil
+}
+
+func (m *MockStorage) DeleteObject(ctx context.Context, bucketName, objectKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := m.getBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	obj, ok := b.objects[objectKey]
+	if !ok {
+		return fmt.Errorf("object %s/%s: %w", bucketName, objectKey, errNotFound)
+	}
+	delete(b.objects, objectKey)
+	m.publish(storage.ObjectDeleted, bucketName, obj.object)
+	return nil
+}
+
+func (m *MockStorage) CopyObject(ctx context.Context, srcBucket, srcKey, destBucket, destKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	src, err := m.getObject(srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	dest, err := m.getBucket(destBucket)
+	if err != nil {
+		return err
+	}
+	dest.objects[destKey] = newMockObject(destBucket, destKey, src.object.ContentType, src.data, time.Now().UTC(), src.object.Metadata)
+	m.publish(storage.ObjectCreated, destBucket, dest.objects[destKey].object)
+	return nil
+}
+
+var _ storage.MetadataUpdater = (*MockStorage)(nil)
+
+func (m *MockStorage) UpdateObjectMetadata(ctx context.Context, bucketName, objectKey string, metadata map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, err := m.getObject(bucketName, objectKey)
+	if err != nil {
+		return err
+	}
+	if obj.object.Metadata == nil {
+		obj.object.Metadata = make(map[string]string, len(metadata))
+	}
+	maps.Copy(obj.object.Metadata, metadata)
+	obj.object.UpdatedAt = time.Now().UTC()
+	m.buckets[bucketName].objects[objectKey] = obj
+	m.publish(storage.ObjectMetadataUpdated, bucketName, obj.object)
+	return nil
+}
+
+var _ storage.AttributesSetter = (*MockStorage)(nil)
+
+func (m *MockStorage) SetObjectAttributes(ctx context.Context, bucketName, objectKey string, attrs storage.ObjectAttributes) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, err := m.getObject(bucketName, objectKey)
+	if err != nil {
+		return err
+	}
+	obj.object.ContentType = attrs.ContentType
+	obj.object.ContentEncoding = attrs.ContentEncoding
+	obj.object.ContentLanguage = attrs.ContentLanguage
+	obj.object.CacheControl = attrs.CacheControl
+	obj.object.ContentDisposition = attrs.ContentDisposition
+	obj.object.Metadata = maps.Clone(attrs.Metadata)
+	obj.object.UpdatedAt = time.Now().UTC()
+	m.buckets[bucketName].objects[objectKey] = obj
+	m.publish(storage.ObjectMetadataUpdated, bucketName, obj.object)
+	return nil
+}
+
+// getObject returns the object at key in the named bucket. The caller must hold m.mu.
+func (m *MockStorage) getObject(bucketName, objectKey string) (mockObject, error) {
+	b, err := m.getBucket(bucketName)
+	if err != nil {
+		return mockObject{}, err
+	}
+	obj, ok := b.objects[objectKey]
+	if !ok {
+		return mockObject{}, fmt.Errorf("object %s/%s: %w", bucketName, objectKey, errNotFound)
+	}
+	return obj, nil
+}
+
+// sortedKeys returns the keys starting with prefix in lexical order, as the
+// providers list them.
+func (b *mockBucket) sortedKeys(prefix string) []string {
+	var keys []string
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// newMockObject describes data stored at key, with checksums computed from it.
+func newMockObject(bucket, key, contentType string, data []byte, modified time.Time, metadata map[string]string) mockObject {
+	sum := md5.Sum(data)
+	crc := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+	return mockObject{
+		object: storage.Object{
+			Key:          key,
+			Bucket:       bucket,
+			Provider:     domain.Mock,
+			Size:         int64(len(data)),
+			StorageClass: "STANDARD",
+			LastModified: modified,
+			CreatedAt:    modified,
+			UpdatedAt:    modified,
+			ETag:         hex.EncodeToString(sum[:]),
+			ContentType:  contentType,
+			MD5Hash:      base64.StdEncoding.EncodeToString(sum[:]),
+			CRC32C:       base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, crc)),
+			Metadata:     maps.Clone(metadata),
+		},
+		data: data,
+	}
+}
+
+// addChecksum records an S3-style additional checksum of the object's data,
+// as asked for by an upload.
+func (o *mockObject) addChecksum(algorithm string) error {
+	sum, err := storage.ComputeChecksum(bytes.NewReader(o.data), algorithm, 0)
+	if err != nil {
+		return err
+	}
+	switch strings.ToUpper(algorithm) {
+	case storage.ChecksumCRC32:
+		o.object.CRC32 = sum
+	case storage.ChecksumSHA256:
+		o.object.SHA256 = sum
+	}
+	o.object.ChecksumType = storage.ChecksumTypeFullObject
+	return nil
+}
+
+// snapshot returns a copy of the object's description, safe to hand out after
+// the lock is released.
+func (o mockObject) snapshot() storage.Object {
+	obj := o.object
+	obj.Metadata = maps.Clone(o.object.Metadata)
+	return obj
+}
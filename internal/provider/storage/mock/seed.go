@@ -0,0 +1,120 @@
+package mock
+
+import (
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"strings"
+	"time"
+)
+
+// seedTime anchors every seed timestamp, so listings look the same on every run
+var seedTime = time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC)
+
+// seedObject is an object of the seed data; its content is generated to size.
+type seedObject struct {
+	key  string
+	size int
+	// age is how long before seedTime the object was last modified
+	age time.Duration
+}
+
+// seedBucketSpec describes a bucket of the seed data.
+type seedBucketSpec struct {
+	bucket  storage.Bucket
+	objects []seedObject
+}
+
+const day = 24 * time.Hour
+
+var seedSpecs = []seedBucketSpec{
+	{
+		bucket: storage.Bucket{
+			Name:                     "acme-web-assets",
+			Location:                 "US",
+			LocationType:             "multi-region",
+			StorageClass:             "STANDARD",
+			Labels:                   map[string]string{"team": "web", "env": "prod"},
+			UniformBucketLevelAccess: &storage.UniformBucketLevelAccess{Enabled: true},
+			PublicAccessPrevention:   storage.PublicAccessPreventionInherited,
+		},
+		objects: []seedObject{
+			{key: "index.html", size: 4_812, age: 2 * day},
+			{key: "css/site.css", size: 18_430, age: 9 * day},
+			{key: "js/app.js", size: 96_214, age: 2 * day},
+			{key: "images/logo.svg", size: 3_120, age: 120 * day},
+			{key: "images/hero.jpg", size: 248_576, age: 45 * day},
+		},
+	},
+	{
+		bucket: storage.Bucket{
+			Name:                     "acme-data-lake",
+			Location:                 "us-central1",
+			LocationType:             "region",
+			StorageClass:             "NEARLINE",
+			Labels:                   map[string]string{"team": "data", "env": "prod"},
+			Versioning:               &storage.Versioning{Enabled: true},
+			UniformBucketLevelAccess: &storage.UniformBucketLevelAccess{Enabled: true},
+			PublicAccessPrevention:   storage.PublicAccessPreventionEnforced,
+			LifecycleRules: []storage.LifecycleRule{
+				{Action: "SetStorageClass to COLDLINE", Condition: storage.LifecycleCondition{Age: 90}},
+			},
+		},
+		objects: []seedObject{
+			{key: "raw/2024/01/events.json", size: 524_288, age: 40 * day},
+			{key: "raw/2024/02/events.json", size: 611_328, age: 12 * day},
+			{key: "raw/2024/03/events.json", size: 131_072, age: 0},
+			{key: "curated/customers.csv", size: 73_411, age: 3 * day},
+			{key: "curated/orders.csv", size: 209_715, age: 3 * day},
+			{key: "README.md", size: 1_024, age: 200 * day},
+		},
+	},
+	{
+		bucket: storage.Bucket{
+			Name:                   "acme-backups",
+			Location:               "EU",
+			LocationType:           "multi-region",
+			StorageClass:           "COLDLINE",
+			Labels:                 map[string]string{"team": "platform"},
+			PublicAccessPrevention: storage.PublicAccessPreventionEnforced,
+			RetentionPolicy:        &storage.RetentionPolicy{RetentionPeriod: 30 * day},
+			Encryption: &storage.Encryption{
+				KmsKeyName: "projects/acme-platform/locations/europe/keyRings/backups/cryptoKeys/backups-cmek",
+				Algorithm:  shared.EncryptionAES256,
+			},
+		},
+		objects: []seedObject{
+			{key: "db/backup-2024-01.tar.gz", size: 1_048_576, age: 33 * day},
+			{key: "db/backup-2024-02.tar.gz", size: 1_153_434, age: 4 * day},
+			{key: "configs/nginx.conf", size: 2_048, age: 60 * day},
+		},
+	},
+}
+
+// seedBuckets builds the seed buckets with their objects. Each call returns
+// fresh copies, so clients do not share state.
+func seedBuckets() []*mockBucket {
+	buckets := make([]*mockBucket, 0, len(seedSpecs))
+	for i, spec := range seedSpecs {
+		bucket := spec.bucket
+		bucket.Provider = domain.Mock
+		bucket.CreatedAt = seedTime.Add(-time.Duration(365+30*i) * day)
+		bucket.UpdatedAt = bucket.CreatedAt
+
+		b := &mockBucket{bucket: bucket, objects: make(map[string]mockObject, len(spec.objects))}
+		for _, o := range spec.objects {
+			obj := newMockObject(bucket.Name, o.key, shared.DetectContentType(o.key), seedContent(o.key, o.size), seedTime.Add(-o.age), nil)
+			obj.object.StorageClass = bucket.StorageClass
+			b.objects[o.key] = obj
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
+
+// seedContent returns size bytes of readable filler text naming the key.
+func seedContent(key string, size int) []byte {
+	line := fmt.Sprintf("synkronus demo data: %s\n", key)
+	return []byte(strings.Repeat(line, size/len(line)+1)[:size])
+}
@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signedURLBase is the made-up endpoint of signed mock URLs, which no server answers
+const signedURLBase = "https://storage.mock.invalid/"
+
+var _ storage.PostPolicySigner = (*MockStorage)(nil)
+
+// SignPostPolicy returns a policy shaped like a real one, with an unsigned
+// policy document and a placeholder signature.
+func (m *MockStorage) SignPostPolicy(ctx context.Context, opts storage.PostPolicyOptions) (storage.PostPolicy, error) {
+	m.mu.Lock()
+	_, err := m.getBucket(opts.BucketName)
+	m.mu.Unlock()
+	if err != nil {
+		return storage.PostPolicy{}, err
+	}
+
+	expires := time.Now().Add(opts.Expires).UTC()
+	conditions := []any{[]any{"starts-with", "$key", opts.Prefix}}
+	if opts.MaxSize > 0 {
+		conditions = append(conditions, []any{"content-length-range", 0, opts.MaxSize})
+	}
+	doc, err := json.Marshal(map[string]any{
+		"expiration": expires.Format(time.RFC3339),
+		"conditions": conditions,
+	})
+	if err != nil {
+		return storage.PostPolicy{}, err
+	}
+	return storage.PostPolicy{
+		URL: signedURLBase + opts.BucketName,
+		Fields: map[string]string{
+			"key":       opts.Prefix + "${filename}",
+			"policy":    base64.StdEncoding.EncodeToString(doc),
+			"signature": "mock-" + strconv.FormatInt(expires.Unix(), 10),
+		},
+		Expires: expires,
+	}, nil
+}
+
+var _ storage.URLSigner = (*MockStorage)(nil)
+
+// SignURL returns a URL in the shape of a signed one, with a placeholder
+// signature.
+func (m *MockStorage) SignURL(ctx context.Context, opts storage.SignedURLOptions) (storage.SignedURL, error) {
+	m.mu.Lock()
+	_, err := m.getBucket(opts.BucketName)
+	m.mu.Unlock()
+	if err != nil {
+		return storage.SignedURL{}, err
+	}
+
+	expires := time.Now().Add(opts.Expires).UTC()
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires.Unix(), 10)},
+		"signature": {"mock-" + strconv.FormatInt(expires.Unix(), 10)},
+	}
+	if opts.ResponseContentDisposition != "" {
+		query.Set("response-content-disposition", opts.ResponseContentDisposition)
+	}
+	return storage.SignedURL{
+		URL:     storage.ObjectURL(bucketURL(opts.BucketName, opts.Style), opts.ObjectKey) + "?" + query.Encode(),
+		Method:  strings.ToUpper(opts.Method),
+		Headers: opts.Headers,
+		Expires: expires,
+	}, nil
+}
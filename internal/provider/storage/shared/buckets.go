@@ -0,0 +1,105 @@
+package shared
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// BucketLocations lists common locations for new buckets per provider, offered
+// by the create-bucket wizard. Any other valid location can still be typed.
+var BucketLocations = map[string][]string{
+	"gcp":  {"US", "EU", "ASIA", "us-central1", "us-east1", "us-west1", "europe-west1", "europe-west4", "asia-east1", "asia-northeast1"},
+	"aws":  {"us-east-1", "us-east-2", "us-west-1", "us-west-2", "eu-west-1", "eu-central-1", "ap-southeast-1", "ap-northeast-1"},
+	"mock": {"US", "EU", "us-central1", "europe-west1"},
+}
+
+// BucketStorageClasses lists the default storage classes a new bucket can have
+// per provider. S3 sets storage classes per object, so it has none.
+var BucketStorageClasses = map[string][]string{
+	"gcp":  {"STANDARD", "NEARLINE", "COLDLINE", "ARCHIVE"},
+	"mock": {"STANDARD", "NEARLINE", "COLDLINE", "ARCHIVE"},
+}
+
+// ValidateBucketName checks name against the provider's bucket naming rules,
+// so a bad name is reported before any API call. Global uniqueness can only be
+// checked by creating the bucket.
+func ValidateBucketName(provider, name string) error {
+	switch strings.ToLower(provider) {
+	case "gcp":
+		return validateGCSBucketName(name)
+	case "aws":
+		return validateS3BucketName(name)
+	default:
+		return nil
+	}
+}
+
+// See https://cloud.google.com/storage/docs/buckets#naming
+func validateGCSBucketName(name string) error {
+	maxLen := 63
+	if strings.Contains(name, ".") {
+		maxLen = 222
+	}
+	if err := validateBucketNameShape(name, maxLen, "-_."); err != nil {
+		return err
+	}
+	for _, part := range strings.Split(name, ".") {
+		if len(part) == 0 || len(part) > 63 {
+			return fmt.Errorf("each dot-separated part of a bucket name must be 1 to 63 characters long")
+		}
+	}
+	if strings.HasPrefix(name, "goog") {
+		return fmt.Errorf("bucket names cannot begin with \"goog\"")
+	}
+	if strings.Contains(name, "google") || strings.Contains(name, "g00gle") {
+		return fmt.Errorf("bucket names cannot contain \"google\" or close misspellings of it")
+	}
+	return nil
+}
+
+// See https://docs.aws.amazon.com/AmazonS3/latest/userguide/bucketnamingrules.html
+func validateS3BucketName(name string) error {
+	if err := validateBucketNameShape(name, 63, "-."); err != nil {
+		return err
+	}
+	if strings.Contains(name, "..") {
+		return fmt.Errorf("bucket names cannot contain two adjacent periods")
+	}
+	for _, prefix := range []string{"xn--", "sthree-", "amzn-s3-demo-"} {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("bucket names cannot begin with %q", prefix)
+		}
+	}
+	for _, suffix := range []string{"-s3alias", "--ol-s3", ".mrap", "--x-s3"} {
+		if strings.HasSuffix(name, suffix) {
+			return fmt.Errorf("bucket names cannot end with %q", suffix)
+		}
+	}
+	return nil
+}
+
+// validateBucketNameShape checks the rules the providers share: length, lowercase
+// letters and digits plus the allowed punctuation, alphanumeric first and last
+// characters, and not looking like an IP address.
+func validateBucketNameShape(name string, maxLen int, punctuation string) error {
+	if len(name) < 3 || len(name) > maxLen {
+		return fmt.Errorf("bucket names must be 3 to %d characters long, got %d", maxLen, len(name))
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && !strings.ContainsRune(punctuation, r) {
+			return fmt.Errorf("bucket names can only contain lowercase letters, digits and %q, got %q", punctuation, r)
+		}
+	}
+	if !isLowerAlphanumeric(name[0]) || !isLowerAlphanumeric(name[len(name)-1]) {
+		return fmt.Errorf("bucket names must begin and end with a lowercase letter or digit")
+	}
+	if net.ParseIP(name) != nil {
+		return fmt.Errorf("bucket names cannot be IP addresses")
+	}
+	return nil
+}
+
+func isLowerAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
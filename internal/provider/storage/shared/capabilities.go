@@ -11,6 +11,9 @@ var ProviderCapabilities = map[string]map[string]bool{
 		"uniform-access": true,
 	},
 	"aws": {},
+	"mock": {
+		"uniform-access": true,
+	},
 }
 
 // SupportsOption reports whether a provider supports a given create-bucket
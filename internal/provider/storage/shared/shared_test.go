@@ -1,6 +1,9 @@
 package shared
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestDetectContentType(t *testing.T) {
 	tests := []struct {
@@ -25,3 +28,36 @@ func TestDetectContentType(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBucketName(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		bucket   string
+		wantErr  bool
+	}{
+		{"gcp valid", "gcp", "my-bucket_2024", false},
+		{"gcp dotted", "gcp", "assets.example.com", false},
+		{"gcp too short", "gcp", "ab", true},
+		{"gcp uppercase", "gcp", "My-Bucket", true},
+		{"gcp trailing dash", "gcp", "my-bucket-", true},
+		{"gcp goog prefix", "gcp", "googlers-data", true},
+		{"gcp contains google", "gcp", "my-google-bucket", true},
+		{"gcp ip address", "gcp", "192.168.1.1", true},
+		{"aws valid", "AWS", "my-bucket.logs", false},
+		{"aws underscore", "aws", "my_bucket", true},
+		{"aws adjacent periods", "aws", "my..bucket", true},
+		{"aws reserved prefix", "aws", "xn--bucket", true},
+		{"aws reserved suffix", "aws", "my-bucket-s3alias", true},
+		{"aws too long", "aws", strings.Repeat("a", 64), true},
+		{"unknown provider", "azure", "Anything", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBucketName(tt.provider, tt.bucket)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBucketName(%q, %q) error = %v, wantErr %v", tt.provider, tt.bucket, err, tt.wantErr)
+			}
+		})
+	}
+}
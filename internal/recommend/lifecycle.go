@@ -0,0 +1,155 @@
+package recommend
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Lifecycle is a lifecycle configuration in the provider's own format, ready
+// for 'gcloud storage buckets update --lifecycle-file' (GCP) or 'aws s3api
+// put-bucket-lifecycle-configuration --lifecycle-configuration' (AWS).
+type Lifecycle struct {
+	// Rules counts the rules of the configuration
+	Rules int
+	doc   any
+}
+
+// Write writes the configuration as indented JSON.
+func (l Lifecycle) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l.doc)
+}
+
+// SuggestLifecycle turns the transitions recommended in report into a
+// lifecycle configuration, with a rule deleting versions noncurrentDays after
+// they are replaced when the bucket keeps versions (0 leaves it out). Rules
+// are limited to the report's prefix. Autoclass buckets get no transitions,
+// since Cloud Storage rejects them there.
+func SuggestLifecycle(report Report, noncurrentDays int) (Lifecycle, error) {
+	if noncurrentDays < 0 {
+		return Lifecycle{}, fmt.Errorf("noncurrent days must not be negative, got %d", noncurrentDays)
+	}
+	var transitions []Recommendation
+	for _, r := range report.Recommendations {
+		if r.Kind == KindLifecycle {
+			transitions = append(transitions, r)
+		}
+	}
+	// Recommendations are ordered by savings, rules by age
+	slices.SortFunc(transitions, func(a, b Recommendation) int { return cmp.Compare(a.AfterDays, b.AfterDays) })
+	if !report.Versioned {
+		noncurrentDays = 0
+	}
+
+	switch report.Provider {
+	case "aws":
+		return s3Lifecycle(report.Prefix, transitions, noncurrentDays), nil
+	case "gcp", "mock":
+		return gcsLifecycle(report.Prefix, transitions, noncurrentDays), nil
+	default:
+		return Lifecycle{}, fmt.Errorf("lifecycle rules are not supported on %s", report.Provider)
+	}
+}
+
+type gcsRule struct {
+	Action    gcsAction    `json:"action"`
+	Condition gcsCondition `json:"condition"`
+}
+
+type gcsAction struct {
+	Type         string `json:"type"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+type gcsCondition struct {
+	Age                     int      `json:"age,omitempty"`
+	IsLive                  *bool    `json:"isLive,omitempty"`
+	DaysSinceNoncurrentTime int      `json:"daysSinceNoncurrentTime,omitempty"`
+	MatchesStorageClass     []string `json:"matchesStorageClass,omitempty"`
+	MatchesPrefix           []string `json:"matchesPrefix,omitempty"`
+}
+
+func gcsLifecycle(prefix string, transitions []Recommendation, noncurrentDays int) Lifecycle {
+	var matchesPrefix []string
+	if prefix != "" {
+		matchesPrefix = []string{prefix}
+	}
+	rules := []gcsRule{}
+	// Each transition only moves objects from warmer classes, so an object is
+	// never moved back
+	warmer := []string{tiers["gcp"][0].class}
+	for _, t := range transitions {
+		rules = append(rules, gcsRule{
+			Action: gcsAction{Type: "SetStorageClass", StorageClass: t.StorageClass},
+			Condition: gcsCondition{
+				Age:                 t.AfterDays,
+				MatchesStorageClass: append([]string(nil), warmer...),
+				MatchesPrefix:       matchesPrefix,
+			},
+		})
+		warmer = append(warmer, t.StorageClass)
+	}
+	if noncurrentDays > 0 {
+		live := false
+		rules = append(rules, gcsRule{
+			Action:    gcsAction{Type: "Delete"},
+			Condition: gcsCondition{IsLive: &live, DaysSinceNoncurrentTime: noncurrentDays, MatchesPrefix: matchesPrefix},
+		})
+	}
+	return Lifecycle{Rules: len(rules), doc: map[string]any{"rule": rules}}
+}
+
+type s3Rule struct {
+	ID                          string                  `json:"ID"`
+	Status                      string                  `json:"Status"`
+	Filter                      s3Filter                `json:"Filter"`
+	Transitions                 []s3Transition          `json:"Transitions,omitempty"`
+	NoncurrentVersionExpiration *s3NoncurrentExpiration `json:"NoncurrentVersionExpiration,omitempty"`
+}
+
+// s3Filter holds a prefix or, with a size too, both under And
+type s3Filter struct {
+	Prefix *string   `json:"Prefix,omitempty"`
+	And    *s3Filter `json:"And,omitempty"`
+	// ObjectSizeGreaterThan is only set under And
+	ObjectSizeGreaterThan int64 `json:"ObjectSizeGreaterThan,omitempty"`
+}
+
+type s3Transition struct {
+	Days         int    `json:"Days"`
+	StorageClass string `json:"StorageClass"`
+}
+
+type s3NoncurrentExpiration struct {
+	NoncurrentDays int `json:"NoncurrentDays"`
+}
+
+func s3Lifecycle(prefix string, transitions []Recommendation, noncurrentDays int) Lifecycle {
+	rules := []s3Rule{}
+	if len(transitions) > 0 {
+		// Smaller objects are billed as minTieredSize in the colder classes,
+		// as the recommendations assume they stay put
+		rule := s3Rule{
+			ID:     "synkronus-transitions",
+			Status: "Enabled",
+			Filter: s3Filter{And: &s3Filter{Prefix: &prefix, ObjectSizeGreaterThan: minTieredSize - 1}},
+		}
+		for _, t := range transitions {
+			rule.Transitions = append(rule.Transitions, s3Transition{Days: t.AfterDays, StorageClass: t.StorageClass})
+		}
+		rules = append(rules, rule)
+	}
+	if noncurrentDays > 0 {
+		rules = append(rules, s3Rule{
+			ID:                          "synkronus-noncurrent-expiration",
+			Status:                      "Enabled",
+			Filter:                      s3Filter{Prefix: &prefix},
+			NoncurrentVersionExpiration: &s3NoncurrentExpiration{NoncurrentDays: noncurrentDays},
+		})
+	}
+	return Lifecycle{Rules: len(rules), doc: map[string]any{"Rules": rules}}
+}
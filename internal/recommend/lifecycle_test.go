@@ -0,0 +1,86 @@
+package recommend
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSuggestLifecycle_GCS(t *testing.T) {
+	report := Report{
+		Provider:  "gcp",
+		Prefix:    "logs/",
+		Versioned: true,
+		Recommendations: []Recommendation{
+			{Kind: KindLifecycle, StorageClass: "ARCHIVE", AfterDays: 365, MonthlySavings: 2},
+			{Kind: KindAutoclass, MonthlySavings: 3},
+			{Kind: KindLifecycle, StorageClass: "NEARLINE", AfterDays: 30, MonthlySavings: 1},
+		},
+	}
+	lifecycle, err := SuggestLifecycle(report, 90)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lifecycle.Rules != 3 {
+		t.Fatalf("expected 2 transitions and a noncurrent deletion, got %d rules", lifecycle.Rules)
+	}
+
+	var buf bytes.Buffer
+	if err := lifecycle.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Rule []gcsRule `json:"rule"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	first, second, last := doc.Rule[0], doc.Rule[1], doc.Rule[2]
+	if first.Action.StorageClass != "NEARLINE" || first.Condition.Age != 30 || strings.Join(first.Condition.MatchesStorageClass, ",") != "STANDARD" {
+		t.Errorf("expected STANDARD objects moved to NEARLINE at 30 days first, got %+v", first)
+	}
+	if second.Action.StorageClass != "ARCHIVE" || strings.Join(second.Condition.MatchesStorageClass, ",") != "STANDARD,NEARLINE" {
+		t.Errorf("expected STANDARD and NEARLINE objects moved to ARCHIVE, got %+v", second)
+	}
+	if last.Action.Type != "Delete" || last.Condition.IsLive == nil || *last.Condition.IsLive || last.Condition.DaysSinceNoncurrentTime != 90 {
+		t.Errorf("expected noncurrent versions deleted after 90 days, got %+v", last)
+	}
+	for _, r := range doc.Rule {
+		if len(r.Condition.MatchesPrefix) != 1 || r.Condition.MatchesPrefix[0] != "logs/" {
+			t.Errorf("expected every rule limited to logs/, got %+v", r)
+		}
+	}
+}
+
+func TestSuggestLifecycle_S3(t *testing.T) {
+	report := Report{
+		Provider:        "aws",
+		Recommendations: []Recommendation{{Kind: KindLifecycle, StorageClass: "GLACIER_IR", AfterDays: 90}},
+	}
+	lifecycle, err := SuggestLifecycle(report, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lifecycle.Rules != 1 {
+		t.Fatalf("expected no noncurrent rule on an unversioned bucket, got %d rules", lifecycle.Rules)
+	}
+	var buf bytes.Buffer
+	if err := lifecycle.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Rules"`, `"StorageClass": "GLACIER_IR"`, `"Days": 90`, `"ObjectSizeGreaterThan": 131071`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected the configuration to contain %s, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestSuggestLifecycle_Unsupported(t *testing.T) {
+	if _, err := SuggestLifecycle(Report{Provider: "azure"}, 0); err == nil {
+		t.Error("expected an error for a provider without lifecycle rules")
+	}
+	if _, err := SuggestLifecycle(Report{Provider: "gcp"}, -1); err == nil {
+		t.Error("expected an error for negative noncurrent days")
+	}
+}
@@ -0,0 +1,66 @@
+package recommend
+
+// gib is the unit storage is billed in
+const gib = 1 << 30
+
+// Objects smaller than minTieredSize are not moved by Autoclass or
+// Intelligent-Tiering, and are billed as that size in S3's infrequent access
+// classes.
+const minTieredSize = 128 << 10
+
+// tier is a storage class suggested for objects of some age.
+type tier struct {
+	class string
+	// afterDays is the object age the class is suggested from, at least its
+	// minimum storage duration
+	afterDays int
+}
+
+// Storage classes from warmest to coldest. Their prices come from the
+// pricing dataset.
+var tiers = map[string][]tier{
+	"gcp": {
+		{class: "STANDARD"},
+		{class: "NEARLINE", afterDays: 30},
+		{class: "COLDLINE", afterDays: 90},
+		{class: "ARCHIVE", afterDays: 365},
+	},
+	"aws": {
+		{class: "STANDARD"},
+		{class: "STANDARD_IA", afterDays: 30},
+		{class: "GLACIER_IR", afterDays: 90},
+		{class: "DEEP_ARCHIVE", afterDays: 365},
+	},
+}
+
+// Intelligent-Tiering bills objects not read for 30 days as infrequent
+// access, and for 90 days as archive instant access, at the prices of the
+// matching storage classes.
+const (
+	intelligentTieringInfrequent = "STANDARD_IA"
+	intelligentTieringArchive    = "GLACIER_IR"
+)
+
+// classesOf returns provider's storage classes from warmest to coldest. The
+// mock provider uses Cloud Storage's.
+func classesOf(provider string) []tier {
+	if provider == "mock" {
+		return tiers["gcp"]
+	}
+	return tiers[provider]
+}
+
+// price returns the monthly price per GiB of class in the report's region,
+// defaulting to the warmest class for classes without a price (e.g., legacy
+// or unreported ones).
+func (r *Report) price(class string) float64 {
+	if c, ok := r.prices.Class(class); ok {
+		return c.StoragePerGiBMonth
+	}
+	classes := classesOf(r.Provider)
+	if len(classes) == 0 {
+		return 0
+	}
+	c, _ := r.prices.Class(classes[0].class)
+	return c.StoragePerGiBMonth
+}
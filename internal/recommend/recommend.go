@@ -0,0 +1,260 @@
+// Package recommend suggests cheaper storage classes for the objects of a
+// bucket: it groups them by age, weighs in how often the bucket is accessed
+// where the provider reports it, and estimates the monthly savings of
+// lifecycle transitions and of Autoclass or Intelligent-Tiering, at the
+// prices of the pricing dataset.
+package recommend
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/pricing"
+	"slices"
+	"strings"
+	"time"
+)
+
+// accessWindow is the period access metrics are summed over
+const accessWindow = 30 * 24 * time.Hour
+
+// Kinds of recommendation
+const (
+	KindLifecycle          = "lifecycle"
+	KindAutoclass          = "autoclass"
+	KindIntelligentTiering = "intelligent-tiering"
+)
+
+// AgeBand counts the objects whose age (since creation) is at least MinDays
+// and below the next band's.
+type AgeBand struct {
+	Label   string `json:"label" yaml:"label"`
+	MinDays int    `json:"min_days" yaml:"min_days"`
+	Objects int64  `json:"objects" yaml:"objects"`
+	Bytes   int64  `json:"bytes" yaml:"bytes"`
+
+	// Objects still in the warmest class, which transitions would move, and
+	// those of them at least minTieredSize
+	warmObjects, warmBytes           int64
+	largeWarmObjects, largeWarmBytes int64
+}
+
+// Recommendation is a change to the bucket with its estimated savings.
+type Recommendation struct {
+	Kind   string `json:"kind" yaml:"kind"`
+	Action string `json:"action" yaml:"action"`
+	// StorageClass and AfterDays describe a lifecycle transition
+	StorageClass string `json:"storage_class,omitempty" yaml:"storage_class,omitempty"`
+	AfterDays    int    `json:"after_days,omitempty" yaml:"after_days,omitempty"`
+	Objects      int64  `json:"objects" yaml:"objects"`
+	Bytes        int64  `json:"bytes" yaml:"bytes"`
+	// MonthlySavings is in USD, at the list prices of the pricing dataset
+	MonthlySavings float64 `json:"monthly_savings" yaml:"monthly_savings"`
+}
+
+// Report is the analysis of a bucket's objects and what to change.
+type Report struct {
+	Provider     string `json:"provider" yaml:"provider"`
+	Bucket       string `json:"bucket" yaml:"bucket"`
+	Prefix       string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	StorageClass string `json:"storage_class" yaml:"storage_class"`
+	Autoclass    bool   `json:"autoclass" yaml:"autoclass"`
+	Versioned    bool   `json:"versioned" yaml:"versioned"`
+	Objects      int64  `json:"objects" yaml:"objects"`
+	Bytes        int64  `json:"bytes" yaml:"bytes"`
+	// MonthlyCost estimates what the objects cost to store now, in USD
+	MonthlyCost float64   `json:"monthly_cost" yaml:"monthly_cost"`
+	Ages        []AgeBand `json:"ages" yaml:"ages"`
+	// Access is nil when the provider reports no access metrics
+	Access          *storage.AccessStats `json:"access,omitempty" yaml:"access,omitempty"`
+	Recommendations []Recommendation     `json:"recommendations" yaml:"recommendations"`
+	Notes           []string             `json:"notes,omitempty" yaml:"notes,omitempty"`
+	// Pricing is the version of the pricing dataset the estimates use
+	Pricing string `json:"pricing" yaml:"pricing"`
+	// PriceRegion is the region whose prices the estimates use, empty when
+	// there are no prices for the provider
+	PriceRegion string `json:"price_region,omitempty" yaml:"price_region,omitempty"`
+
+	// largeObjects counts the objects at least minTieredSize
+	largeObjects int64
+	prices       pricing.Region
+	tieringFee   float64
+}
+
+// Source is the subset of the storage service an analysis needs.
+type Source interface {
+	DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error)
+	WalkObjects(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.Object) error) error
+	BucketAccess(ctx context.Context, bucketName, providerName string, window time.Duration) (storage.AccessStats, error)
+}
+
+// Analyze walks the objects under prefix and recommends storage class
+// changes. Access metrics are optional: without them the estimates assume
+// aged objects are rarely read.
+func Analyze(ctx context.Context, src Source, prices *pricing.Dataset, provider, bucket, prefix string) (Report, error) {
+	provider = strings.ToLower(provider)
+	b, err := src.DescribeBucket(ctx, bucket, provider)
+	if err != nil {
+		return Report{}, err
+	}
+	report := newReport(provider, b, prefix, prices)
+	now := time.Now()
+	err = src.WalkObjects(ctx, bucket, provider, prefix, func(obj storage.Object) error {
+		report.add(obj, now)
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	if access, err := src.BucketAccess(ctx, bucket, provider, accessWindow); err == nil {
+		report.Access = &access
+	} else {
+		report.Notes = append(report.Notes, "Access metrics are unavailable, so the estimates assume aged objects are rarely read.")
+	}
+	report.recommend(b)
+	return report, nil
+}
+
+func newReport(provider string, b storage.Bucket, prefix string, prices *pricing.Dataset) Report {
+	report := Report{
+		Provider:        provider,
+		Bucket:          b.Name,
+		Prefix:          prefix,
+		StorageClass:    b.StorageClass,
+		Autoclass:       b.Autoclass != nil && b.Autoclass.Enabled,
+		Versioned:       b.Versioning != nil && b.Versioning.Enabled,
+		Recommendations: []Recommendation{},
+		Pricing:         prices.Version,
+		tieringFee:      prices.TieringFee(provider),
+	}
+	if region, name, ok := prices.Region(provider, b.Location); ok {
+		report.prices, report.PriceRegion = region, name
+		if b.Location != "" && !strings.EqualFold(b.Location, name) {
+			report.Notes = append(report.Notes, fmt.Sprintf("There are no prices for %s, so the estimates use those of %s.", b.Location, name))
+		}
+	}
+	// An age band per class: objects old enough for it, but not the next
+	classes := classesOf(provider)
+	for i, t := range classes {
+		if i == 0 {
+			report.Ages = append(report.Ages, AgeBand{Label: fmt.Sprintf("< %d days", classes[1].afterDays)})
+			continue
+		}
+		report.Ages = append(report.Ages, AgeBand{Label: fmt.Sprintf("%d+ days", t.afterDays), MinDays: t.afterDays})
+	}
+	return report
+}
+
+// add counts obj in its age band.
+func (r *Report) add(obj storage.Object, now time.Time) {
+	created := obj.CreatedAt
+	if created.IsZero() {
+		created = obj.LastModified
+	}
+	age := int(now.Sub(created).Hours() / 24)
+	class := cmp.Or(obj.StorageClass, r.StorageClass)
+
+	r.Objects++
+	r.Bytes += obj.Size
+	r.MonthlyCost += float64(obj.Size) / gib * r.price(class)
+	if obj.Size >= minTieredSize {
+		r.largeObjects++
+	}
+	if len(r.Ages) == 0 {
+		return
+	}
+
+	i := len(r.Ages) - 1
+	for i > 0 && age < r.Ages[i].MinDays {
+		i--
+	}
+	band := &r.Ages[i]
+	band.Objects++
+	band.Bytes += obj.Size
+	if r.price(class) == r.price(classesOf(r.Provider)[0].class) {
+		band.warmObjects++
+		band.warmBytes += obj.Size
+		if obj.Size >= minTieredSize {
+			band.largeWarmObjects++
+			band.largeWarmBytes += obj.Size
+		}
+	}
+}
+
+// recommend fills in the recommendations and notes, most savings first.
+func (r *Report) recommend(b storage.Bucket) {
+	classes := classesOf(r.Provider)
+	if len(classes) == 0 || r.PriceRegion == "" {
+		r.Notes = append(r.Notes, fmt.Sprintf("There are no prices for %s, so no savings are estimated.", r.Provider))
+		return
+	}
+	if r.Autoclass {
+		r.Notes = append(r.Notes, "Autoclass is enabled and already moves objects between storage classes.")
+		return
+	}
+	if len(b.LifecycleRules) > 0 {
+		r.Notes = append(r.Notes, fmt.Sprintf("The bucket has %d lifecycle rules; check them against these suggestions.", len(b.LifecycleRules)))
+	}
+	if r.Access != nil && r.Access.Requests > r.Objects {
+		r.Notes = append(r.Notes, fmt.Sprintf("The bucket is busy (%d requests in %d days): colder classes charge for each read, which can outweigh the savings of age-based transitions.",
+			r.Access.Requests, int(r.Access.Window.Hours()/24)))
+	}
+
+	warm := r.price(classes[0].class)
+	// S3's infrequent access classes bill small objects as minTieredSize, so
+	// only the larger ones are worth moving there
+	largeOnly := r.Provider == "aws"
+	var tieredSavings float64
+	var tieredBytes int64
+	for i, band := range r.Ages[1:] {
+		target := classes[i+1]
+		objects, bytes := band.warmObjects, band.warmBytes
+		if largeOnly {
+			objects, bytes = band.largeWarmObjects, band.largeWarmBytes
+		}
+		if bytes > 0 {
+			r.Recommendations = append(r.Recommendations, Recommendation{
+				Kind:           KindLifecycle,
+				Action:         fmt.Sprintf("Transition objects older than %d days to %s", target.afterDays, target.class),
+				StorageClass:   target.class,
+				AfterDays:      target.afterDays,
+				Objects:        objects,
+				Bytes:          bytes,
+				MonthlySavings: float64(bytes) / gib * (warm - r.price(target.class)),
+			})
+		}
+		tieredBytes += band.largeWarmBytes
+		tieredSavings += float64(band.largeWarmBytes) / gib * (warm - r.tieredPrice(target))
+	}
+	tieredSavings -= float64(r.largeObjects) / 1000 * r.tieringFee
+
+	if tieredBytes > 0 && tieredSavings > 0 {
+		rec := Recommendation{Objects: r.largeObjects, Bytes: tieredBytes, MonthlySavings: tieredSavings}
+		switch r.Provider {
+		case "aws":
+			rec.Kind, rec.Action = KindIntelligentTiering, "Store objects in INTELLIGENT_TIERING, which moves them by last access"
+		default:
+			rec.Kind, rec.Action = KindAutoclass, "Enable Autoclass, which moves objects between classes by last access"
+		}
+		r.Recommendations = append(r.Recommendations, rec)
+	}
+	slices.SortStableFunc(r.Recommendations, func(a, b Recommendation) int {
+		return cmp.Compare(b.MonthlySavings, a.MonthlySavings)
+	})
+}
+
+// tieredPrice is what Autoclass or Intelligent-Tiering bills for an object
+// that has not been read since it was old enough for target.
+func (r *Report) tieredPrice(target tier) float64 {
+	if r.Provider != "aws" {
+		return r.price(target.class)
+	}
+	// Intelligent-Tiering stops at archive instant access unless the deeper
+	// tiers are opted into
+	if target.afterDays < 90 {
+		return r.price(intelligentTieringInfrequent)
+	}
+	return r.price(intelligentTieringArchive)
+}
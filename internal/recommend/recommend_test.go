@@ -0,0 +1,167 @@
+package recommend
+
+import (
+	"context"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/pricing"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	bucket  storage.Bucket
+	objects []storage.Object
+	access  *storage.AccessStats
+}
+
+func (f *fakeSource) DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error) {
+	return f.bucket, nil
+}
+
+func (f *fakeSource) WalkObjects(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.Object) error) error {
+	for _, obj := range f.objects {
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeSource) BucketAccess(ctx context.Context, bucketName, providerName string, window time.Duration) (storage.AccessStats, error) {
+	if f.access == nil {
+		return storage.AccessStats{}, errors.New("no metrics")
+	}
+	return *f.access, nil
+}
+
+func daysAgo(days int) time.Time {
+	return time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+}
+
+func TestAnalyze_GCP(t *testing.T) {
+	src := &fakeSource{
+		bucket: storage.Bucket{Name: "archive", StorageClass: "STANDARD"},
+		objects: []storage.Object{
+			{Key: "new", Size: gib, CreatedAt: daysAgo(1)},
+			{Key: "month", Size: gib, CreatedAt: daysAgo(45)},
+			{Key: "old", Size: 10 * gib, CreatedAt: daysAgo(400)},
+			{Key: "old-cold", Size: gib, CreatedAt: daysAgo(400), StorageClass: "ARCHIVE"},
+		},
+	}
+	report, err := Analyze(context.Background(), src, pricing.Bundled(), "GCP", "archive", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Objects != 4 || report.Bytes != 13*gib {
+		t.Errorf("got %d objects of %d bytes, want 4 of %d", report.Objects, report.Bytes, 13*gib)
+	}
+	if got := report.Ages[3]; got.Objects != 2 || got.Bytes != 11*gib {
+		t.Errorf("365+ days band = %+v, want 2 objects of 11 GiB", got)
+	}
+	if len(report.Recommendations) != 3 {
+		t.Fatalf("expected 2 transitions and Autoclass, got %+v", report.Recommendations)
+	}
+	if first := report.Recommendations[0]; first.Kind != KindAutoclass || first.Objects != 4 {
+		t.Errorf("expected Autoclass first, for every object, got %+v", first)
+	}
+	if archive := report.Recommendations[1]; archive.StorageClass != "ARCHIVE" || archive.Objects != 1 || archive.Bytes != 10*gib {
+		t.Errorf("expected the ARCHIVE transition of the one STANDARD object next, got %+v", archive)
+	}
+	for i := 1; i < len(report.Recommendations); i++ {
+		if report.Recommendations[i].MonthlySavings > report.Recommendations[i-1].MonthlySavings {
+			t.Errorf("expected recommendations by savings, got %+v", report.Recommendations)
+		}
+	}
+	if !strings.Contains(strings.Join(report.Notes, " "), "Access metrics are unavailable") {
+		t.Errorf("expected a note about missing access metrics, got %v", report.Notes)
+	}
+}
+
+func TestAnalyze_AWSSkipsSmallObjects(t *testing.T) {
+	src := &fakeSource{
+		bucket: storage.Bucket{Name: "logs", StorageClass: "STANDARD"},
+		objects: []storage.Object{
+			{Key: "small", Size: 1024, LastModified: daysAgo(100)},
+			{Key: "large", Size: gib, LastModified: daysAgo(100)},
+		},
+		access: &storage.AccessStats{Window: accessWindow, Requests: 1},
+	}
+	report, err := Analyze(context.Background(), src, pricing.Bundled(), "aws", "logs", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var kinds []string
+	for _, r := range report.Recommendations {
+		kinds = append(kinds, r.Kind)
+		if r.Objects != 1 || r.Bytes != gib {
+			t.Errorf("expected only the large object counted, got %+v", r)
+		}
+	}
+	if len(kinds) != 2 || kinds[0] != KindLifecycle || kinds[1] != KindIntelligentTiering {
+		t.Errorf("expected a GLACIER_IR transition then Intelligent-Tiering, got %v", kinds)
+	}
+	if report.Access == nil || len(report.Notes) != 0 {
+		t.Errorf("expected access metrics and no notes, got %+v, %v", report.Access, report.Notes)
+	}
+}
+
+func TestAnalyze_Autoclass(t *testing.T) {
+	src := &fakeSource{
+		bucket:  storage.Bucket{Name: "auto", StorageClass: "STANDARD", Autoclass: &storage.Autoclass{Enabled: true}},
+		objects: []storage.Object{{Key: "old", Size: gib, CreatedAt: daysAgo(400)}},
+	}
+	report, err := Analyze(context.Background(), src, pricing.Bundled(), "gcp", "auto", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Recommendations) != 0 {
+		t.Errorf("expected no recommendations for an Autoclass bucket, got %+v", report.Recommendations)
+	}
+	if !strings.Contains(strings.Join(report.Notes, " "), "Autoclass is enabled") {
+		t.Errorf("expected a note about Autoclass, got %v", report.Notes)
+	}
+}
+
+func TestAnalyze_BusyBucket(t *testing.T) {
+	src := &fakeSource{
+		bucket:  storage.Bucket{Name: "hot", StorageClass: "STANDARD"},
+		objects: []storage.Object{{Key: "old", Size: gib, CreatedAt: daysAgo(400)}},
+		access:  &storage.AccessStats{Window: accessWindow, Requests: 5000},
+	}
+	report, err := Analyze(context.Background(), src, pricing.Bundled(), "gcp", "hot", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(strings.Join(report.Notes, " "), "The bucket is busy (5000 requests in 30 days)") {
+		t.Errorf("expected a note about the request rate, got %v", report.Notes)
+	}
+}
+
+func TestAnalyze_RegionPrices(t *testing.T) {
+	src := &fakeSource{
+		bucket:  storage.Bucket{Name: "eu", StorageClass: "STANDARD", Location: "EU"},
+		objects: []storage.Object{{Key: "old", Size: gib, CreatedAt: daysAgo(400)}},
+	}
+	report, err := Analyze(context.Background(), src, pricing.Bundled(), "gcp", "eu", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.PriceRegion != "eu" || report.Pricing == "" {
+		t.Errorf("expected the eu prices of a dataset version, got region %q version %q", report.PriceRegion, report.Pricing)
+	}
+	if report.MonthlyCost != 0.026 {
+		t.Errorf("expected the EU multi-region STANDARD price, got %v", report.MonthlyCost)
+	}
+
+	src.bucket.Location = "ASIA-SOUTH2"
+	report, err = Analyze(context.Background(), src, pricing.Bundled(), "gcp", "eu", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.PriceRegion != "us-central1" || !strings.Contains(strings.Join(report.Notes, " "), "no prices for ASIA-SOUTH2") {
+		t.Errorf("expected the default region's prices with a note, got region %q, notes %v", report.PriceRegion, report.Notes)
+	}
+}
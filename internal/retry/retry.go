@@ -0,0 +1,113 @@
+// Package retry re-runs provider calls that fail with transient errors
+// (throttling, server errors, dropped connections), waiting with exponential
+// backoff and full jitter between attempts.
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// DefaultMaxRetries is how many times a failed call is retried when neither
+// --max-retries nor retry.max_retries is set.
+const DefaultMaxRetries = 3
+
+// MaxMaxRetries is the highest retry count accepted from --max-retries or
+// retry.max_retries.
+const MaxMaxRetries = 10
+
+const (
+	defaultBaseDelay = 500 * time.Millisecond
+	defaultMaxDelay  = 10 * time.Second
+)
+
+// Policy controls how often and how long to wait before retrying a call. The
+// zero Policy never retries.
+type Policy struct {
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles on each
+	// later retry, up to MaxDelay. The actual wait is a random duration up to it.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultPolicy returns the policy used when no retry settings are configured.
+func DefaultPolicy() Policy {
+	return WithMaxRetries(DefaultMaxRetries)
+}
+
+// WithMaxRetries returns the default backoff with n retries.
+func WithMaxRetries(n int) Policy {
+	return Policy{MaxRetries: n, BaseDelay: defaultBaseDelay, MaxDelay: defaultMaxDelay}
+}
+
+// Classifier reports whether err is transient, i.e. whether the call that
+// returned it may succeed if retried.
+type Classifier func(err error) bool
+
+// Do calls fn until it succeeds, fails with an error retryable does not
+// classify as transient, or p.MaxRetries retries are spent. The last error is
+// returned. Waiting between attempts stops early if ctx is done.
+func Do[T any](ctx context.Context, p Policy, retryable Classifier, logger *slog.Logger, fn func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := fn()
+		if err == nil || attempt >= p.MaxRetries || ctx.Err() != nil || !retryable(err) {
+			return result, err
+		}
+
+		delay := p.backoff(attempt)
+		logger.Debug("Retrying after transient error", "attempt", attempt+1, "max_retries", p.MaxRetries, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a random wait of up to BaseDelay*2^attempt, capped at MaxDelay.
+func (p Policy) backoff(attempt int) time.Duration {
+	ceiling := p.MaxDelay
+	if attempt < 32 {
+		if d := p.BaseDelay << attempt; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// IsRetryableStatus reports whether an HTTP status code signals a transient
+// condition: throttling (429), a request timeout (408), or a server error.
+func IsRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code >= 500
+}
+
+// IsTransientNetworkError reports whether err is a network failure worth
+// retrying: a timeout, a reset or refused connection, or a connection closed
+// mid-response. Cancellation of the caller's own context is not transient.
+func IsTransientNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
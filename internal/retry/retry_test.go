@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var (
+	errTransient = errors.New("transient")
+	errPermanent = errors.New("permanent")
+)
+
+func isTestTransient(err error) bool { return errors.Is(err, errTransient) }
+
+func testPolicy(maxRetries int) Policy {
+	return Policy{MaxRetries: maxRetries, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// failing returns a call that fails with errs in turn, then succeeds.
+func failing(calls *int, errs ...error) func() (string, error) {
+	return func() (string, error) {
+		*calls++
+		if *calls <= len(errs) {
+			return "", errs[*calls-1]
+		}
+		return "ok", nil
+	}
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    Policy
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{"succeeds first time", testPolicy(3), nil, nil, 1},
+		{"retries transient errors", testPolicy(3), []error{errTransient, errTransient}, nil, 3},
+		{"gives up after max retries", testPolicy(2), []error{errTransient, errTransient, errTransient}, errTransient, 3},
+		{"does not retry permanent errors", testPolicy(3), []error{errPermanent}, errPermanent, 1},
+		{"zero policy never retries", Policy{}, []error{errTransient}, errTransient, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			got, err := Do(context.Background(), tt.policy, isTestTransient, testLogger(), failing(&calls, tt.errs...))
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != "ok" {
+				t.Errorf("got result %q, want %q", got, "ok")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDo_StopsWaitingWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := Policy{MaxRetries: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		_, err := Do(ctx, policy, isTestTransient, testLogger(), failing(&calls, errTransient, errTransient))
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errTransient) {
+			t.Errorf("expected the last call's error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Do kept waiting after the context was canceled")
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+func TestBackoff_CappedAndJittered(t *testing.T) {
+	p := Policy{MaxRetries: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := range 10 {
+		ceiling := min(p.BaseDelay<<attempt, p.MaxDelay)
+		for range 20 {
+			if d := p.backoff(attempt); d < 0 || d > ceiling {
+				t.Fatalf("backoff(%d) = %s, want within [0, %s]", attempt, d, ceiling)
+			}
+		}
+	}
+	if d := p.backoff(100); d > p.MaxDelay {
+		t.Errorf("backoff for a large attempt = %s, want at most %s", d, p.MaxDelay)
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	for code, want := range map[int]bool{200: false, 400: false, 403: false, 404: false, 408: true, 429: true, 500: true, 503: true} {
+		if got := IsRetryableStatus(code); got != want {
+			t.Errorf("IsRetryableStatus(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestIsTransientNetworkError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"truncated response", fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF), true},
+		{"timeout", &net.DNSError{IsTimeout: true}, true},
+		{"canceled", fmt.Errorf("request: %w", context.Canceled), false},
+		{"deadline exceeded", context.DeadlineExceeded, false},
+		{"other", errors.New("access denied"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientNetworkError(tt.err); got != tt.want {
+				t.Errorf("IsTransientNetworkError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
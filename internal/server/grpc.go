@@ -0,0 +1,359 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	synkronusv1 "github.com/jkleinne/synkronus/api/synkronus/v1"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCServer returns a gRPC server answering the services of
+// api/synkronus/v1 with the same services and jobs as the REST API, with
+// authentication applied.
+func (s *Server) GRPCServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(s.logCalls, s.authenticateCall))
+	synkronusv1.RegisterStorageServiceServer(server, &grpcStorage{s: s})
+	synkronusv1.RegisterSqlServiceServer(server, &grpcSQL{s: s})
+	synkronusv1.RegisterJobServiceServer(server, &grpcJobs{s: s})
+	return server
+}
+
+// ServeGRPC answers gRPC calls on listener until ctx is done, then cancels
+// running jobs and waits briefly for in-flight calls.
+func (s *Server) ServeGRPC(ctx context.Context, listener net.Listener) error {
+	server := s.GRPCServer()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.cancel()
+		return err
+	case <-ctx.Done():
+	}
+
+	s.cancel()
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		server.Stop()
+	}
+	return nil
+}
+
+// authenticateCall rejects calls without the server's token in the
+// "authorization" metadata.
+func (s *Server) authenticateCall(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.deps.Token)) == 1 {
+			return handler(ctx, req)
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+func (s *Server) logCalls(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.deps.Logger.Info("Handled call", "method", info.FullMethod, "code", status.Code(err).String(), "duration", time.Since(start))
+	return resp, err
+}
+
+type grpcStorage struct {
+	synkronusv1.UnimplementedStorageServiceServer
+	s *Server
+}
+
+func (g *grpcStorage) ListBuckets(ctx context.Context, req *synkronusv1.ListBucketsRequest) (*synkronusv1.ListBucketsResponse, error) {
+	providers, err := selectProviders(req.GetProviders(), g.s.deps.Providers.IsConfigured, g.s.deps.Providers.ConfiguredStorageProviders)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	buckets, err := g.s.deps.StorageService.ListAllBuckets(ctx, providers)
+	failures, ok := providerFailures(err)
+	if !ok {
+		return nil, grpcError(err)
+	}
+	resp := &synkronusv1.ListBucketsResponse{Errors: providerErrorsToProto(failures)}
+	for _, bucket := range buckets {
+		resp.Buckets = append(resp.Buckets, bucketToProto(bucket))
+	}
+	return resp, nil
+}
+
+func (g *grpcStorage) DescribeBucket(ctx context.Context, req *synkronusv1.DescribeBucketRequest) (*synkronusv1.Bucket, error) {
+	provider, err := g.s.grpcStorageProvider(req.GetProvider())
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := g.s.deps.StorageService.DescribeBucket(ctx, req.GetBucket(), provider)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return bucketToProto(bucket), nil
+}
+
+func (g *grpcStorage) ListObjects(ctx context.Context, req *synkronusv1.ListObjectsRequest) (*synkronusv1.ListObjectsResponse, error) {
+	provider, err := g.s.grpcStorageProvider(req.GetProvider())
+	if err != nil {
+		return nil, err
+	}
+	list, err := g.s.deps.StorageService.ListObjects(ctx, req.GetBucket(), provider, req.GetPrefix())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &synkronusv1.ListObjectsResponse{Bucket: list.BucketName, Prefix: list.Prefix, CommonPrefixes: list.CommonPrefixes}
+	for _, object := range list.Objects {
+		resp.Objects = append(resp.Objects, objectToProto(object))
+	}
+	return resp, nil
+}
+
+func (g *grpcStorage) DescribeObject(ctx context.Context, req *synkronusv1.DescribeObjectRequest) (*synkronusv1.Object, error) {
+	provider, err := g.s.grpcStorageProvider(req.GetProvider())
+	if err != nil {
+		return nil, err
+	}
+	object, err := g.s.deps.StorageService.DescribeObject(ctx, req.GetBucket(), req.GetKey(), provider)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return objectToProto(object), nil
+}
+
+type grpcSQL struct {
+	synkronusv1.UnimplementedSqlServiceServer
+	s *Server
+}
+
+func (g *grpcSQL) ListInstances(ctx context.Context, req *synkronusv1.ListInstancesRequest) (*synkronusv1.ListInstancesResponse, error) {
+	providers, err := selectProviders(req.GetProviders(), g.s.deps.Providers.IsSqlConfigured, g.s.deps.Providers.ConfiguredSqlProviders)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	instances, err := g.s.deps.SqlService.ListAllInstances(ctx, providers)
+	failures, ok := providerFailures(err)
+	if !ok {
+		return nil, grpcError(err)
+	}
+	resp := &synkronusv1.ListInstancesResponse{Errors: providerErrorsToProto(failures)}
+	for _, instance := range instances {
+		resp.Instances = append(resp.Instances, instanceToProto(instance))
+	}
+	return resp, nil
+}
+
+func (g *grpcSQL) DescribeInstance(ctx context.Context, req *synkronusv1.DescribeInstanceRequest) (*synkronusv1.Instance, error) {
+	provider := strings.ToLower(req.GetProvider())
+	if !g.s.deps.Providers.IsSqlConfigured(provider) {
+		return nil, status.Errorf(codes.InvalidArgument, "SQL provider %q is not supported or not configured", provider)
+	}
+	instance, err := g.s.deps.SqlService.DescribeInstance(ctx, req.GetInstance(), provider)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return instanceToProto(instance), nil
+}
+
+type grpcJobs struct {
+	synkronusv1.UnimplementedJobServiceServer
+	s *Server
+}
+
+func (g *grpcJobs) StartSync(ctx context.Context, req *synkronusv1.StartSyncRequest) (*synkronusv1.Job, error) {
+	opts := service.SyncOptions{
+		Source:      syncTargetFromProto(req.GetSource()),
+		Destination: syncTargetFromProto(req.GetDestination()),
+		Include:     req.GetInclude(),
+		Exclude:     req.GetExclude(),
+	}
+	if err := g.s.validateSync(&opts); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	job := g.s.startSync(opts, "")
+	return jobToProto(job), nil
+}
+
+func (g *grpcJobs) GetJob(ctx context.Context, req *synkronusv1.GetJobRequest) (*synkronusv1.Job, error) {
+	job, ok := g.s.jobs.get(req.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "job %q not found", req.GetId())
+	}
+	return jobToProto(job), nil
+}
+
+func (g *grpcJobs) ListJobs(ctx context.Context, req *synkronusv1.ListJobsRequest) (*synkronusv1.ListJobsResponse, error) {
+	resp := &synkronusv1.ListJobsResponse{}
+	for _, job := range g.s.jobs.list() {
+		resp.Jobs = append(resp.Jobs, jobToProto(job))
+	}
+	return resp, nil
+}
+
+// grpcStorageProvider returns the named storage provider if it is configured,
+// or an InvalidArgument error.
+func (s *Server) grpcStorageProvider(name string) (string, error) {
+	provider := strings.ToLower(name)
+	if !s.deps.Providers.IsConfigured(provider) {
+		return "", status.Errorf(codes.InvalidArgument, "storage provider %q is not supported or not configured", provider)
+	}
+	return provider, nil
+}
+
+// grpcError converts a service error to a gRPC status, as writeServiceError
+// does for HTTP: NotFound for a missing resource, FailedPrecondition when the
+// provider rejected synkronus's credentials.
+func grpcError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, service.ErrAuth):
+		code = codes.FailedPrecondition
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
+
+func providerErrorsToProto(failures []providerFailure) []*synkronusv1.ProviderError {
+	var errs []*synkronusv1.ProviderError
+	for _, f := range failures {
+		errs = append(errs, &synkronusv1.ProviderError{Provider: f.Provider, Error: f.Error})
+	}
+	return errs
+}
+
+func bucketToProto(b storage.Bucket) *synkronusv1.Bucket {
+	bucket := &synkronusv1.Bucket{
+		Name:                   b.Name,
+		Provider:               string(b.Provider),
+		Location:               b.Location,
+		LocationType:           b.LocationType,
+		StorageClass:           b.StorageClass,
+		CreatedAt:              timestamp(b.CreatedAt),
+		UpdatedAt:              timestamp(b.UpdatedAt),
+		UsageBytes:             b.UsageBytes,
+		RequesterPays:          b.RequesterPays,
+		Labels:                 b.Labels,
+		Project:                b.Project,
+		Account:                b.Account,
+		PublicAccessPrevention: b.PublicAccessPrevention,
+	}
+	if b.Versioning != nil {
+		bucket.VersioningEnabled = &b.Versioning.Enabled
+	}
+	if b.UniformBucketLevelAccess != nil {
+		bucket.UniformBucketLevelAccess = &b.UniformBucketLevelAccess.Enabled
+	}
+	return bucket
+}
+
+func objectToProto(o storage.Object) *synkronusv1.Object {
+	return &synkronusv1.Object{
+		Key:          o.Key,
+		Bucket:       o.Bucket,
+		Provider:     string(o.Provider),
+		Size:         o.Size,
+		StorageClass: o.StorageClass,
+		LastModified: timestamp(o.LastModified),
+		Etag:         o.ETag,
+		ContentType:  o.ContentType,
+		Md5Hash:      o.MD5Hash,
+		Generation:   o.Generation,
+		VersionId:    o.VersionID,
+		Metadata:     o.Metadata,
+	}
+}
+
+func instanceToProto(i sql.Instance) *synkronusv1.Instance {
+	return &synkronusv1.Instance{
+		Name:            i.Name,
+		Provider:        string(i.Provider),
+		Region:          i.Region,
+		DatabaseVersion: i.DatabaseVersion,
+		Tier:            i.Tier,
+		State:           i.State,
+		PrimaryAddress:  i.PrimaryAddress,
+		StorageSizeGb:   i.StorageSizeGB,
+		CreatedAt:       timestamp(i.CreatedAt),
+		Project:         i.Project,
+		ConnectionName:  i.ConnectionName,
+		Labels:          i.Labels,
+	}
+}
+
+var jobStatuses = map[JobStatus]synkronusv1.JobStatus{
+	JobRunning:   synkronusv1.JobStatus_JOB_STATUS_RUNNING,
+	JobSucceeded: synkronusv1.JobStatus_JOB_STATUS_SUCCEEDED,
+	JobFailed:    synkronusv1.JobStatus_JOB_STATUS_FAILED,
+}
+
+func jobToProto(j Job) *synkronusv1.Job {
+	job := &synkronusv1.Job{
+		Id:        j.ID,
+		Type:      j.Type,
+		Status:    jobStatuses[j.Status],
+		Error:     j.Error,
+		StartedAt: timestamp(j.StartedAt),
+		Schedule:  j.Schedule,
+	}
+	if j.Sync != nil {
+		job.Sync = &synkronusv1.StartSyncRequest{
+			Source:      syncTargetToProto(j.Sync.Source),
+			Destination: syncTargetToProto(j.Sync.Destination),
+			Include:     j.Sync.Include,
+			Exclude:     j.Sync.Exclude,
+		}
+	}
+	if j.Result != nil {
+		job.Result = &synkronusv1.SyncResult{Copied: int64(j.Result.Copied), Skipped: int64(j.Result.Skipped), Bytes: j.Result.Bytes}
+		for _, f := range j.Result.Failed {
+			job.Result.Failed = append(job.Result.Failed, &synkronusv1.SyncFailure{Key: f.Key, Error: f.Error})
+		}
+	}
+	if j.FinishedAt != nil {
+		job.FinishedAt = timestamp(*j.FinishedAt)
+	}
+	return job
+}
+
+func syncTargetFromProto(t *synkronusv1.SyncTarget) service.SyncTarget {
+	return service.SyncTarget{Provider: t.GetProvider(), Bucket: t.GetBucket(), Prefix: t.GetPrefix()}
+}
+
+func syncTargetToProto(t service.SyncTarget) *synkronusv1.SyncTarget {
+	return &synkronusv1.SyncTarget{Provider: t.Provider, Bucket: t.Bucket, Prefix: t.Prefix}
+}
+
+// timestamp converts t, leaving zero times unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
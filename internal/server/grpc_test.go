@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	synkronusv1 "github.com/jkleinne/synkronus/api/synkronus/v1"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/service"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPCClient returns a connection to a gRPC server backed by the
+// in-memory mock provider.
+func newTestGRPCClient(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f := factory.NewFactory(&config.Config{Mock: &config.MockConfig{Enabled: true}}, logger)
+	storageService := service.NewStorageService(f, logger)
+	t.Cleanup(func() { storageService.Shutdown() })
+
+	srv := New(Deps{
+		StorageService: storageService,
+		SqlService:     service.NewSqlService(f, logger),
+		Providers:      f,
+		Token:          testToken,
+		Logger:         logger,
+	})
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.ServeGRPC(ctx, listener) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func authenticated() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testToken)
+}
+
+func TestGRPC_Authentication(t *testing.T) {
+	client := synkronusv1.NewStorageServiceClient(newTestGRPCClient(t))
+
+	for _, token := range []string{"", "Bearer wrong", testToken} {
+		ctx := context.Background()
+		if token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+		}
+		_, err := client.ListBuckets(ctx, &synkronusv1.ListBucketsRequest{})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("authorization %q = %v, want Unauthenticated", token, err)
+		}
+	}
+}
+
+func TestGRPC_Storage(t *testing.T) {
+	client := synkronusv1.NewStorageServiceClient(newTestGRPCClient(t))
+	ctx := authenticated()
+
+	buckets, err := client.ListBuckets(ctx, &synkronusv1.ListBucketsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets.GetBuckets()) != 3 || len(buckets.GetErrors()) != 0 {
+		t.Errorf("expected the 3 mock buckets and no errors, got %v", buckets)
+	}
+	if _, err := client.ListBuckets(ctx, &synkronusv1.ListBucketsRequest{Providers: []string{"azure"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown provider = %v, want InvalidArgument", err)
+	}
+
+	bucket, err := client.DescribeBucket(ctx, &synkronusv1.DescribeBucketRequest{Provider: "mock", Bucket: "acme-data-lake"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bucket.GetName() != "acme-data-lake" || bucket.GetCreatedAt() == nil {
+		t.Errorf("unexpected bucket: %v", bucket)
+	}
+	if _, err := client.DescribeBucket(ctx, &synkronusv1.DescribeBucketRequest{Provider: "mock", Bucket: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("missing bucket = %v, want NotFound", err)
+	}
+
+	list, err := client.ListObjects(ctx, &synkronusv1.ListObjectsRequest{Provider: "mock", Bucket: "acme-data-lake", Prefix: "raw/2024/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.GetCommonPrefixes()) != 3 {
+		t.Errorf("expected the 3 month prefixes, got %v", list.GetCommonPrefixes())
+	}
+
+	object, err := client.DescribeObject(ctx, &synkronusv1.DescribeObjectRequest{Provider: "mock", Bucket: "acme-data-lake", Key: "raw/2024/02/events.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object.GetKey() != "raw/2024/02/events.json" || object.GetSize() == 0 {
+		t.Errorf("unexpected object: %v", object)
+	}
+}
+
+func TestGRPC_SyncJob(t *testing.T) {
+	client := synkronusv1.NewJobServiceClient(newTestGRPCClient(t))
+	ctx := authenticated()
+
+	job, err := client.StartSync(ctx, &synkronusv1.StartSyncRequest{
+		Source:      &synkronusv1.SyncTarget{Provider: "mock", Bucket: "acme-data-lake", Prefix: "curated/"},
+		Destination: &synkronusv1.SyncTarget{Provider: "mock", Bucket: "acme-backups", Prefix: "curated/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.GetStatus() == synkronusv1.JobStatus_JOB_STATUS_RUNNING && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if job, err = client.GetJob(ctx, &synkronusv1.GetJobRequest{Id: job.GetId()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if job.GetStatus() != synkronusv1.JobStatus_JOB_STATUS_SUCCEEDED || job.GetResult().GetCopied() != 2 || job.GetFinishedAt() == nil {
+		t.Errorf("expected a finished job that copied 2 objects, got %v", job)
+	}
+
+	jobs, err := client.ListJobs(ctx, &synkronusv1.ListJobsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.GetJobs()) != 1 {
+		t.Errorf("expected 1 job in the list, got %d", len(jobs.GetJobs()))
+	}
+
+	if _, err := client.StartSync(ctx, &synkronusv1.StartSyncRequest{Source: &synkronusv1.SyncTarget{Provider: "mock", Bucket: "a"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("sync without a destination = %v, want InvalidArgument", err)
+	}
+	if _, err := client.GetJob(ctx, &synkronusv1.GetJobRequest{Id: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown job = %v, want NotFound", err)
+	}
+}
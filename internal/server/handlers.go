@@ -0,0 +1,325 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/service"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// providerFailure is a provider that failed in a multi-provider listing,
+// reported next to the results of the others.
+type providerFailure struct {
+	Provider string `json:"provider"`
+	Error    string `json:"error"`
+}
+
+// defaultHistoryLimit is how many runs /v1/schedules/{name}/history returns
+// without ?limit=
+const defaultHistoryLimit = 20
+
+type providersResponse struct {
+	Storage []string `json:"storage"`
+	SQL     []string `json:"sql"`
+}
+
+type bucketsResponse struct {
+	Buckets []storage.Bucket  `json:"buckets"`
+	Errors  []providerFailure `json:"errors,omitempty"`
+}
+
+type instancesResponse struct {
+	Instances []sql.Instance    `json:"instances"`
+	Errors    []providerFailure `json:"errors,omitempty"`
+}
+
+type jobsResponse struct {
+	Jobs []Job `json:"jobs"`
+}
+
+type schedulesResponse struct {
+	Schedules []ScheduledJob `json:"schedules"`
+}
+
+func (s *Server) handleProviders(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, providersResponse{
+		Storage: nonNil(s.deps.Providers.ConfiguredStorageProviders()),
+		SQL:     nonNil(s.deps.Providers.ConfiguredSqlProviders()),
+	})
+}
+
+// handleListBuckets lists the buckets of the providers in ?providers=, or of
+// every configured provider. Providers that fail are listed in "errors".
+func (s *Server) handleListBuckets(w http.ResponseWriter, r *http.Request) {
+	providers, err := resolveProviders(r, s.deps.Providers.IsConfigured, s.deps.Providers.ConfiguredStorageProviders)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	buckets, err := s.deps.StorageService.ListAllBuckets(r.Context(), providers)
+	failures, ok := providerFailures(err)
+	if !ok {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bucketsResponse{Buckets: nonNil(buckets), Errors: failures})
+}
+
+func (s *Server) handleDescribeBucket(w http.ResponseWriter, r *http.Request) {
+	provider, ok := s.storageProvider(w, r)
+	if !ok {
+		return
+	}
+	bucket, err := s.deps.StorageService.DescribeBucket(r.Context(), r.PathValue("bucket"), provider)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bucket)
+}
+
+func (s *Server) handleListObjects(w http.ResponseWriter, r *http.Request) {
+	provider, ok := s.storageProvider(w, r)
+	if !ok {
+		return
+	}
+	objects, err := s.deps.StorageService.ListObjects(r.Context(), r.PathValue("bucket"), provider, r.URL.Query().Get("prefix"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, objects)
+}
+
+func (s *Server) handleDescribeObject(w http.ResponseWriter, r *http.Request) {
+	provider, ok := s.storageProvider(w, r)
+	if !ok {
+		return
+	}
+	object, err := s.deps.StorageService.DescribeObject(r.Context(), r.PathValue("bucket"), r.PathValue("key"), provider)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, object)
+}
+
+func (s *Server) handleListInstances(w http.ResponseWriter, r *http.Request) {
+	providers, err := resolveProviders(r, s.deps.Providers.IsSqlConfigured, s.deps.Providers.ConfiguredSqlProviders)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	instances, err := s.deps.SqlService.ListAllInstances(r.Context(), providers)
+	failures, ok := providerFailures(err)
+	if !ok {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, instancesResponse{Instances: nonNil(instances), Errors: failures})
+}
+
+func (s *Server) handleDescribeInstance(w http.ResponseWriter, r *http.Request) {
+	provider := strings.ToLower(r.PathValue("provider"))
+	if !s.deps.Providers.IsSqlConfigured(provider) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("SQL provider %q is not supported or not configured", provider))
+		return
+	}
+	instance, err := s.deps.SqlService.DescribeInstance(r.Context(), r.PathValue("instance"), provider)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, instance)
+}
+
+// handleSubmitSync starts a sync job for the service.SyncOptions in the body
+// and answers with the job, whose progress is then polled at /v1/jobs/{id}.
+func (s *Server) handleSubmitSync(w http.ResponseWriter, r *http.Request) {
+	var opts service.SyncOptions
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&opts); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid sync request: %w", err))
+		return
+	}
+	if err := s.validateSync(&opts); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	job := s.startSync(opts, "")
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, jobsResponse{Jobs: s.jobs.list()})
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %q not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// validateSync checks that both sides of a sync name a bucket of a configured
+// provider and that its filter patterns are well formed, normalizing the
+// provider names.
+func (s *Server) validateSync(opts *service.SyncOptions) error {
+	for _, target := range []*service.SyncTarget{&opts.Source, &opts.Destination} {
+		target.Provider = strings.ToLower(target.Provider)
+		if target.Bucket == "" {
+			return errors.New("invalid sync request: source and destination need a bucket")
+		}
+		if !s.deps.Providers.IsConfigured(target.Provider) {
+			return fmt.Errorf("storage provider %q is not supported or not configured", target.Provider)
+		}
+	}
+	for _, patterns := range [][]string{opts.Include, opts.Exclude} {
+		if err := service.ValidatePatterns(patterns); err != nil {
+			return fmt.Errorf("invalid sync request: %w", err)
+		}
+	}
+	if _, err := storage.ParseContentTypeRules(opts.ContentTypes); err != nil {
+		return fmt.Errorf("invalid sync request: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, schedulesResponse{Schedules: s.scheduledJobs()})
+}
+
+// handleScheduleHistory lists the last ?limit= (default 20) runs of a
+// scheduled job, oldest first, from Deps.History or, without one, from the
+// runs since the server started.
+func (s *Server) handleScheduleHistory(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !slices.ContainsFunc(s.scheduledJobs(), func(j ScheduledJob) bool { return j.Name == name }) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("scheduled job %q not found", name))
+		return
+	}
+	limit := defaultHistoryLimit
+	if param := r.URL.Query().Get("limit"); param != "" {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", param))
+			return
+		}
+		limit = n
+	}
+
+	var runs []Job
+	if s.deps.History != nil {
+		var err error
+		if runs, err = ReadHistory(s.deps.History.path, name, limit); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	} else {
+		runs = slices.DeleteFunc(s.jobs.list(), func(j Job) bool { return j.Schedule != name || j.Status == JobRunning })
+		if limit > 0 && len(runs) > limit {
+			runs = runs[len(runs)-limit:]
+		}
+	}
+	writeJSON(w, http.StatusOK, jobsResponse{Jobs: nonNil(runs)})
+}
+
+// storageProvider returns the configured storage provider named in the path,
+// or answers 400 and returns false.
+func (s *Server) storageProvider(w http.ResponseWriter, r *http.Request) (string, bool) {
+	provider := strings.ToLower(r.PathValue("provider"))
+	if !s.deps.Providers.IsConfigured(provider) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("storage provider %q is not supported or not configured", provider))
+		return "", false
+	}
+	return provider, true
+}
+
+// resolveProviders returns the providers in the comma-separated ?providers=
+// parameter, or all configured providers when it is absent.
+func resolveProviders(r *http.Request, isConfigured func(string) bool, configured func() []string) ([]string, error) {
+	var names []string
+	if param := r.URL.Query().Get("providers"); param != "" {
+		names = strings.Split(param, ",")
+	}
+	return selectProviders(names, isConfigured, configured)
+}
+
+// selectProviders returns the named providers, deduplicated, or all configured
+// providers when names is empty.
+func selectProviders(names []string, isConfigured func(string) bool, configured func() []string) ([]string, error) {
+	if len(names) == 0 {
+		return configured(), nil
+	}
+	var providers []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(providers, name) {
+			continue
+		}
+		if !isConfigured(name) {
+			return nil, fmt.Errorf("provider %q is not supported or not configured", name)
+		}
+		providers = append(providers, name)
+	}
+	return providers, nil
+}
+
+// providerFailures converts the partial failure of a multi-provider listing
+// for the response. It returns false for errors that are not partial failures.
+func providerFailures(err error) ([]providerFailure, bool) {
+	if err == nil {
+		return nil, true
+	}
+	var errs service.ProviderErrors
+	if !errors.As(err, &errs) {
+		return nil, false
+	}
+	failures := make([]providerFailure, len(errs))
+	for i, e := range errs {
+		failures[i] = providerFailure{Provider: e.Provider, Error: e.Err.Error()}
+	}
+	return failures, true
+}
+
+// writeServiceError answers with the status matching a service error: 404 for
+// a missing resource, 502 when the provider rejected synkronus's credentials.
+func writeServiceError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrAuth):
+		status = http.StatusBadGateway
+	}
+	writeError(w, status, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// nonNil returns s, or an empty slice for nil, so lists encode as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
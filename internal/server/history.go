@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// historyFileName is created in the synkronus config directory
+const historyFileName = "jobs.log"
+
+// History appends finished jobs to a file, one JSON object per line, so the
+// runs of scheduled jobs can be reviewed after the server restarts. A nil
+// *History records nothing.
+type History struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewHistory returns a history appending to path.
+func NewHistory(path string) *History {
+	return &History{path: path}
+}
+
+// HistoryPath returns the location of the job history.
+func HistoryPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", config.ConfigDirName, historyFileName), nil
+}
+
+// Record appends a finished job.
+func (h *History) Record(job Job) error {
+	if h == nil {
+		return nil
+	}
+	line, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return fmt.Errorf("failed to create job history directory: %w", err)
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open job history: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write job history: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadHistory returns the last limit jobs of the history at path, oldest
+// first, or all of them if limit is 0. A non-empty schedule keeps only the runs
+// of that scheduled job. A missing history has no jobs, and lines that are not
+// valid jobs are skipped.
+func ReadHistory(path, schedule string, limit int) ([]Job, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job history: %w", err)
+	}
+	defer f.Close()
+
+	var jobs []Job
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var job Job
+		if json.Unmarshal(scanner.Bytes(), &job) != nil || job.ID == "" {
+			continue
+		}
+		if schedule != "" && job.Schedule != schedule {
+			continue
+		}
+		jobs = append(jobs, job)
+		if limit > 0 && len(jobs) > limit {
+			jobs = jobs[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read job history: %w", err)
+	}
+	return jobs, nil
+}
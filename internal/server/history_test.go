@@ -0,0 +1,62 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistory_RecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "jobs.log")
+	history := NewHistory(path)
+
+	for _, job := range []Job{
+		{ID: "1", Type: "sync", Status: JobSucceeded, Schedule: "nightly"},
+		{ID: "2", Type: "sync", Status: JobFailed, Error: "boom"},
+		{ID: "3", Type: "sync", Status: JobSucceeded, Schedule: "nightly"},
+		{ID: "4", Type: "sync", Status: JobSucceeded, Schedule: "hourly"},
+	} {
+		if err := history.Record(job); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	all, err := ReadHistory(path, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[1].Error != "boom" {
+		t.Errorf("expected all 4 jobs, got %+v", all)
+	}
+
+	nightly, _ := ReadHistory(path, "nightly", 0)
+	if len(nightly) != 2 || nightly[0].ID != "1" || nightly[1].ID != "3" {
+		t.Errorf("expected the 2 nightly runs, got %+v", nightly)
+	}
+
+	last, _ := ReadHistory(path, "", 2)
+	if len(last) != 2 || last[0].ID != "3" || last[1].ID != "4" {
+		t.Errorf("expected the last 2 jobs, oldest first, got %+v", last)
+	}
+}
+
+func TestReadHistory_MissingAndCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	if jobs, err := ReadHistory(filepath.Join(dir, "missing.log"), "", 0); err != nil || jobs != nil {
+		t.Errorf("expected no jobs and no error for a missing history, got %v, %v", jobs, err)
+	}
+
+	path := filepath.Join(dir, "jobs.log")
+	os.WriteFile(path, []byte("{\"id\":\"1\",\"status\":\"succeeded\"}\n{\"id\":\"2\",\"sta\n"), 0600)
+	jobs, err := ReadHistory(path, "", 0)
+	if err != nil || len(jobs) != 1 {
+		t.Errorf("expected the truncated line to be skipped, got %v, %v", jobs, err)
+	}
+}
+
+func TestHistory_Nil(t *testing.T) {
+	var history *History
+	if err := history.Record(Job{ID: "1"}); err != nil {
+		t.Errorf("nil history returned %v", err)
+	}
+}
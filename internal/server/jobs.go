@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/jkleinne/synkronus/internal/metrics"
+	"github.com/jkleinne/synkronus/internal/notify"
+	"github.com/jkleinne/synkronus/internal/service"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxFinishedJobs bounds the finished jobs kept for /v1/jobs; older ones are
+// forgotten first. Running jobs are always kept.
+const maxFinishedJobs = 100
+
+// JobStatus is where a job is in its life.
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is a long-running operation submitted to the server. Jobs live in
+// memory and are lost when the server stops.
+type Job struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	Status JobStatus `json:"status"`
+	// Schedule names the scheduled job that started this run, if any
+	Schedule   string               `json:"schedule,omitempty"`
+	Sync       *service.SyncOptions `json:"sync,omitempty"`
+	Result     *service.SyncResult  `json:"result,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	StartedAt  time.Time            `json:"started_at"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+}
+
+// jobStore holds the server's jobs in submission order.
+type jobStore struct {
+	mu   sync.Mutex
+	jobs []*Job
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{}
+}
+
+func (s *jobStore) add(job *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+// update applies fn to the job under the lock, then forgets the oldest
+// finished jobs beyond maxFinishedJobs.
+func (s *jobStore) update(job *Job, fn func(*Job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(job)
+
+	finished := 0
+	for _, j := range s.jobs {
+		if j.Status != JobRunning {
+			finished++
+		}
+	}
+	s.jobs = slices.DeleteFunc(s.jobs, func(j *Job) bool {
+		if finished <= maxFinishedJobs || j.Status == JobRunning {
+			return false
+		}
+		finished--
+		return true
+	})
+}
+
+// get returns a copy of the job with id.
+func (s *jobStore) get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if j.ID == id {
+			return *j, true
+		}
+	}
+	return Job{}, false
+}
+
+// running reports whether a run of the named scheduled job is in progress.
+func (s *jobStore) running(schedule string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.ContainsFunc(s.jobs, func(j *Job) bool {
+		return j.Schedule == schedule && j.Status == JobRunning
+	})
+}
+
+// last returns a copy of the latest run of the named scheduled job.
+func (s *jobStore) last(schedule string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range slices.Backward(s.jobs) {
+		if j.Schedule == schedule {
+			return *j, true
+		}
+	}
+	return Job{}, false
+}
+
+// list returns copies of all jobs, oldest first.
+func (s *jobStore) list() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]Job, len(s.jobs))
+	for i, j := range s.jobs {
+		jobs[i] = *j
+	}
+	return jobs
+}
+
+// startSync runs a sync in the background and returns the job tracking it.
+// schedule names the scheduled job it runs for, or is empty for a sync
+// submitted through the API. Finished jobs are recorded in Deps.History and
+// sent to Deps.Notifier.
+func (s *Server) startSync(opts service.SyncOptions, schedule string) Job {
+	job := &Job{
+		ID:        newJobID(),
+		Type:      "sync",
+		Status:    JobRunning,
+		Schedule:  schedule,
+		Sync:      &opts,
+		StartedAt: time.Now().UTC(),
+	}
+	s.jobs.add(job)
+	submitted := *job
+
+	go func() {
+		logger := s.deps.Logger.With("job", job.ID)
+		if schedule != "" {
+			logger = logger.With("schedule", schedule)
+		}
+		logger.Info("Started sync job", "source", opts.Source.String(), "destination", opts.Destination.String())
+		result, err := s.deps.StorageService.Sync(s.ctx, opts)
+		finished := time.Now().UTC()
+		metrics.ObserveJob("sync", finished.Sub(job.StartedAt), err)
+
+		var done Job
+		s.jobs.update(job, func(j *Job) {
+			j.Result = &result
+			j.FinishedAt = &finished
+			j.Status = JobSucceeded
+			if err != nil {
+				j.Status = JobFailed
+				j.Error = err.Error()
+			}
+			done = *j
+		})
+		if recordErr := s.deps.History.Record(done); recordErr != nil {
+			logger.Warn("Failed to record the job in the history", "error", recordErr)
+		}
+		if err != nil {
+			logger.Warn("Sync job failed", "error", err)
+		} else {
+			logger.Info("Finished sync job", "copied", result.Copied, "skipped", result.Skipped, "bytes", result.Bytes)
+		}
+		// Jobs canceled by a shutdown are still reported
+		s.deps.Notifier.Notify(context.WithoutCancel(s.ctx), notifyEvent(done))
+	}()
+	return submitted
+}
+
+// notifyEvent describes a finished sync job for notifications.
+func notifyEvent(job Job) notify.Event {
+	event := notify.Event{
+		JobID:     job.ID,
+		Job:       job.Schedule,
+		Status:    string(job.Status),
+		Error:     job.Error,
+		StartedAt: job.StartedAt,
+	}
+	if job.FinishedAt != nil {
+		event.FinishedAt = *job.FinishedAt
+	}
+	if job.Sync != nil {
+		event.Source = job.Sync.Source.String()
+		event.Destination = job.Sync.Destination.String()
+	}
+	if job.Result != nil {
+		event.Copied = job.Result.Copied
+		event.Skipped = job.Result.Skipped
+		event.Bytes = job.Result.Bytes
+		failures := make([]notify.Failure, len(job.Result.Failed))
+		for i, f := range job.Result.Failed {
+			failures[i] = notify.Failure{Key: f.Key, Error: f.Error}
+		}
+		event.SetFailures(failures)
+	}
+	return event
+}
+
+// newJobID returns a random identifier for a job.
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
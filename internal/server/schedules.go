@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/schedule"
+	"github.com/jkleinne/synkronus/internal/service"
+	"strings"
+	"time"
+)
+
+// scheduledJob is a job definition with its parsed schedule and next run.
+type scheduledJob struct {
+	def  config.SyncJob
+	when schedule.Schedule
+	// next is the zero time when the schedule never comes due
+	next time.Time
+}
+
+// ScheduledJob describes a scheduled job for /v1/schedules.
+type ScheduledJob struct {
+	config.SyncJob
+	NextRun *time.Time `json:"next_run,omitempty"`
+	// LastRun is the latest run since the server started, if any
+	LastRun *Job `json:"last_run,omitempty"`
+}
+
+// newSchedules parses the schedules of jobs, computing their first runs
+// after now.
+func newSchedules(jobs []config.SyncJob, now time.Time) ([]*scheduledJob, error) {
+	schedules := make([]*scheduledJob, 0, len(jobs))
+	seen := make(map[string]bool)
+	for _, def := range jobs {
+		if seen[def.Name] {
+			return nil, fmt.Errorf("scheduled job %q is defined more than once", def.Name)
+		}
+		seen[def.Name] = true
+		when, err := schedule.Parse(def.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled job %q: %w", def.Name, err)
+		}
+		schedules = append(schedules, &scheduledJob{def: def, when: when, next: when.Next(now)})
+	}
+	return schedules, nil
+}
+
+// RunSchedules starts the jobs of Deps.Jobs whenever their schedules come due,
+// until ctx is done. A run that comes due while the previous run of the same
+// job is still going is skipped. Runs appear in /v1/jobs like submitted syncs.
+func (s *Server) RunSchedules(ctx context.Context) error {
+	if s.schedulesErr != nil {
+		return s.schedulesErr
+	}
+	for _, j := range s.schedules {
+		opts := s.syncOptions(j.def)
+		if err := s.validateSync(&opts); err != nil {
+			return fmt.Errorf("scheduled job %q: %w", j.def.Name, err)
+		}
+		if j.next.IsZero() {
+			s.deps.Logger.Warn("Scheduled job never comes due", "schedule", j.def.Name, "cron", j.def.Schedule)
+		}
+	}
+
+	for {
+		next := s.nextRun()
+		if next.IsZero() {
+			<-ctx.Done()
+			return nil
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		s.runDue(time.Now())
+	}
+}
+
+// nextRun returns the earliest next run of all schedules, or the zero time
+// when none comes due.
+func (s *Server) nextRun() time.Time {
+	s.schedulesMu.Lock()
+	defer s.schedulesMu.Unlock()
+	var next time.Time
+	for _, j := range s.schedules {
+		if !j.next.IsZero() && (next.IsZero() || j.next.Before(next)) {
+			next = j.next
+		}
+	}
+	return next
+}
+
+// runDue starts the jobs whose next run is at or before at, and schedules
+// their following runs.
+func (s *Server) runDue(at time.Time) {
+	s.schedulesMu.Lock()
+	defer s.schedulesMu.Unlock()
+	for _, j := range s.schedules {
+		if j.next.IsZero() || j.next.After(at) {
+			continue
+		}
+		j.next = j.when.Next(at)
+		if s.jobs.running(j.def.Name) {
+			s.deps.Logger.Warn("Skipping scheduled run; the previous run is still going", "schedule", j.def.Name)
+			continue
+		}
+		s.startSync(s.syncOptions(j.def), j.def.Name)
+	}
+}
+
+// scheduledJobs returns the scheduled jobs with their next and last runs.
+func (s *Server) scheduledJobs() []ScheduledJob {
+	s.schedulesMu.Lock()
+	defer s.schedulesMu.Unlock()
+	jobs := make([]ScheduledJob, len(s.schedules))
+	for i, j := range s.schedules {
+		jobs[i] = ScheduledJob{SyncJob: j.def}
+		if !j.next.IsZero() {
+			next := j.next
+			jobs[i].NextRun = &next
+		}
+		if last, ok := s.jobs.last(j.def.Name); ok {
+			jobs[i].LastRun = &last
+		}
+	}
+	return jobs
+}
+
+// syncOptions returns the sync a scheduled job runs.
+func (s *Server) syncOptions(def config.SyncJob) service.SyncOptions {
+	opts := service.SyncOptions{
+		Source:       service.SyncTarget{Provider: strings.ToLower(def.Source.Provider), Bucket: def.Source.Bucket, Prefix: def.Source.Prefix},
+		Destination:  service.SyncTarget{Provider: strings.ToLower(def.Destination.Provider), Bucket: def.Destination.Bucket, Prefix: def.Destination.Prefix},
+		Include:      def.Include,
+		Exclude:      def.Exclude,
+		Verify:       def.Verify,
+		ContentTypes: def.ContentTypes,
+	}
+	if tmpl, ok := s.deps.MetadataTemplates[def.MetadataTemplate]; ok {
+		opts.Template = &storage.ObjectAttributes{
+			ContentType:        tmpl.ContentType,
+			CacheControl:       tmpl.CacheControl,
+			ContentDisposition: tmpl.ContentDisposition,
+			ContentEncoding:    tmpl.ContentEncoding,
+			ContentLanguage:    tmpl.ContentLanguage,
+			Metadata:           tmpl.Metadata,
+		}
+	}
+	return opts
+}
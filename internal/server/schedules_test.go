@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/notify"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/service"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var nightlyJob = config.SyncJob{
+	Name:        "nightly-curated",
+	Schedule:    "0 2 * * *",
+	Source:      config.SyncEndpoint{Provider: "mock", Bucket: "acme-data-lake", Prefix: "curated/"},
+	Destination: config.SyncEndpoint{Provider: "MOCK", Bucket: "acme-backups", Prefix: "curated/"},
+	Exclude:     []string{"customers.*"},
+}
+
+// newScheduledServer returns a server backed by the mock provider that runs
+// jobs and records them in a history file in a temporary directory.
+func newScheduledServer(t *testing.T, jobs ...config.SyncJob) *Server {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f := factory.NewFactory(&config.Config{Mock: &config.MockConfig{Enabled: true}}, logger)
+	storageService := service.NewStorageService(f, logger)
+	t.Cleanup(func() { storageService.Shutdown() })
+
+	srv := New(Deps{
+		StorageService: storageService,
+		SqlService:     service.NewSqlService(f, logger),
+		Providers:      f,
+		Token:          testToken,
+		Logger:         logger,
+		Jobs:           jobs,
+		History:        NewHistory(filepath.Join(t.TempDir(), "jobs.log")),
+	})
+	t.Cleanup(srv.cancel)
+	return srv
+}
+
+// waitForRun waits for the latest run of a scheduled job to finish.
+func waitForRun(t *testing.T, srv *Server, name string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, ok := srv.jobs.last(name); ok && job.Status != JobRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("scheduled job %q did not finish", name)
+	return Job{}
+}
+
+func TestRunDue(t *testing.T) {
+	srv := newScheduledServer(t, nightlyJob)
+	due := srv.schedules[0].next
+
+	srv.runDue(due.Add(-time.Minute))
+	if _, ok := srv.jobs.last(nightlyJob.Name); ok {
+		t.Fatal("job started before its schedule came due")
+	}
+
+	srv.runDue(due)
+	job := waitForRun(t, srv, nightlyJob.Name)
+	if job.Status != JobSucceeded || job.Result.Copied != 1 {
+		t.Errorf("expected a run copying only orders.csv, got %+v", job)
+	}
+	if job.Sync.Destination.Provider != "mock" {
+		t.Errorf("expected the provider name lowercased, got %q", job.Sync.Destination.Provider)
+	}
+	if next := srv.schedules[0].next; !next.Equal(due.Add(24 * time.Hour)) {
+		t.Errorf("next run = %v, want a day after %v", next, due)
+	}
+
+	// The run is recorded just after it is marked finished
+	var runs []Job
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		var err error
+		if runs, err = ReadHistory(srv.deps.History.path, nightlyJob.Name, 0); err != nil {
+			t.Fatal(err)
+		}
+		if len(runs) > 0 {
+			break
+		}
+	}
+	if len(runs) != 1 || runs[0].ID != job.ID {
+		t.Errorf("expected the run in the history, got %+v", runs)
+	}
+}
+
+func TestRunDue_SkipsWhileRunning(t *testing.T) {
+	srv := newScheduledServer(t, nightlyJob)
+	srv.jobs.add(&Job{ID: "earlier", Schedule: nightlyJob.Name, Status: JobRunning})
+
+	srv.runDue(srv.schedules[0].next)
+	if job, _ := srv.jobs.last(nightlyJob.Name); job.ID != "earlier" {
+		t.Errorf("expected the run to be skipped, got %+v", job)
+	}
+}
+
+func TestRunDue_Notifies(t *testing.T) {
+	events := make(chan notify.Event, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	t.Cleanup(hook.Close)
+	srv := newScheduledServer(t, nightlyJob)
+	notifier, err := notify.New(map[string]config.NotifierConfig{"hook": {Type: "webhook", URL: hook.URL}}, srv.deps.Logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.deps.Notifier = notifier
+
+	srv.runDue(srv.schedules[0].next)
+	select {
+	case event := <-events:
+		if event.Job != nightlyJob.Name || event.Status != notify.StatusSucceeded || event.Copied != 1 || event.Bytes == 0 {
+			t.Errorf("unexpected event: %+v", event)
+		}
+		if event.Source != "mock:acme-data-lake/curated/" || event.FinishedAt.IsZero() {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification was sent for the finished job")
+	}
+}
+
+func TestRunSchedules_InvalidJobs(t *testing.T) {
+	unconfigured := nightlyJob
+	unconfigured.Name = "to-azure"
+	unconfigured.Destination.Provider = "azure"
+	badSchedule := nightlyJob
+	badSchedule.Schedule = "sometimes"
+
+	for name, jobs := range map[string][]config.SyncJob{
+		"duplicate":    {nightlyJob, nightlyJob},
+		"unconfigured": {unconfigured},
+		"schedule":     {badSchedule},
+	} {
+		srv := newScheduledServer(t, jobs...)
+		if err := srv.RunSchedules(context.Background()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRunSchedules_StopsWithContext(t *testing.T) {
+	srv := newScheduledServer(t, nightlyJob)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.RunSchedules(ctx) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("RunSchedules returned %v, want nil after the context ends", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunSchedules did not return after the context ended")
+	}
+}
+
+func TestSchedulesEndpoints(t *testing.T) {
+	srv := newScheduledServer(t, nightlyJob)
+	srv.runDue(srv.schedules[0].next)
+	waitForRun(t, srv, nightlyJob.Name)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	var schedules schedulesResponse
+	if status := call(t, ts, http.MethodGet, "/v1/schedules", "", &schedules); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if len(schedules.Schedules) != 1 {
+		t.Fatalf("expected 1 schedule, got %+v", schedules)
+	}
+	got := schedules.Schedules[0]
+	if got.Name != nightlyJob.Name || got.NextRun == nil || got.LastRun == nil || got.LastRun.Status != JobSucceeded {
+		t.Errorf("unexpected schedule: %+v", got)
+	}
+
+	var history jobsResponse
+	if status := call(t, ts, http.MethodGet, "/v1/schedules/"+nightlyJob.Name+"/history?limit=5", "", &history); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if len(history.Jobs) != 1 || history.Jobs[0].Schedule != nightlyJob.Name {
+		t.Errorf("expected the run in the history, got %+v", history)
+	}
+
+	var errResp map[string]string
+	if status := call(t, ts, http.MethodGet, "/v1/schedules/unknown/history", "", &errResp); status != http.StatusNotFound {
+		t.Errorf("unknown schedule = %d, want 404", status)
+	}
+	if status := call(t, ts, http.MethodGet, "/v1/schedules/"+nightlyJob.Name+"/history?limit=many", "", &errResp); status != http.StatusBadRequest || !strings.Contains(errResp["error"], "limit") {
+		t.Errorf("invalid limit = %d %v, want 400", status, errResp)
+	}
+}
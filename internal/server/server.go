@@ -0,0 +1,175 @@
+// Package server exposes the storage and SQL services over a REST API for
+// 'synkronus serve', so dashboards and internal tools can query every
+// configured provider through one endpoint. Requests other than the health
+// check must carry the configured token as "Authorization: Bearer <token>".
+//
+// Routes, all returning JSON:
+//
+//	GET  /healthz
+//	GET  /v1/providers
+//	GET  /v1/storage/buckets?providers=gcp,aws
+//	GET  /v1/storage/buckets/{provider}/{bucket}
+//	GET  /v1/storage/buckets/{provider}/{bucket}/objects?prefix=
+//	GET  /v1/storage/buckets/{provider}/{bucket}/objects/{key...}
+//	GET  /v1/sql/instances?providers=gcp
+//	GET  /v1/sql/instances/{provider}/{instance}
+//	POST /v1/jobs/sync
+//	GET  /v1/jobs
+//	GET  /v1/jobs/{id}
+//	GET  /v1/schedules
+//	GET  /v1/schedules/{name}/history?limit=
+//
+// RunSchedules starts the scheduled sync jobs of Deps.Jobs on their cron
+// schedules, turning the server into a small replication service.
+//
+// The same services and jobs are served over gRPC by ServeGRPC, with the API
+// of api/synkronus/v1.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/notify"
+	"github.com/jkleinne/synkronus/internal/service"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout bounds how long Serve waits for in-flight requests
+const shutdownTimeout = 10 * time.Second
+
+// Deps holds what the server needs to answer requests.
+type Deps struct {
+	StorageService *service.StorageService
+	SqlService     *service.SqlService
+	Providers      service.ProviderLister
+	// Token authenticates API requests; it must not be empty
+	Token  string
+	Logger *slog.Logger
+	// Jobs are run by RunSchedules on their schedules
+	Jobs []config.SyncJob
+	// MetadataTemplates are the templates jobs name in MetadataTemplate
+	MetadataTemplates map[string]config.MetadataTemplate
+	// History records finished jobs; nil keeps them in memory only
+	History *History
+	// Notifier is told about finished sync jobs; nil sends no notifications
+	Notifier *notify.Dispatcher
+}
+
+// Server answers REST API requests with the services of Deps.
+type Server struct {
+	deps Deps
+	jobs *jobStore
+	// ctx bounds the jobs the server runs; cancel stops them on shutdown
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	schedulesMu sync.Mutex
+	schedules   []*scheduledJob
+	// schedulesErr is returned by RunSchedules for invalid Deps.Jobs
+	schedulesErr error
+}
+
+// New returns a server for deps. Jobs submitted to it run until they finish
+// or Serve returns.
+func New(deps Deps) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{deps: deps, jobs: newJobStore(), ctx: ctx, cancel: cancel}
+	s.schedules, s.schedulesErr = newSchedules(deps.Jobs, time.Now())
+	return s
+}
+
+// Handler returns the server's routes, with authentication applied.
+func (s *Server) Handler() http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("GET /v1/providers", s.handleProviders)
+	api.HandleFunc("GET /v1/storage/buckets", s.handleListBuckets)
+	api.HandleFunc("GET /v1/storage/buckets/{provider}/{bucket}", s.handleDescribeBucket)
+	api.HandleFunc("GET /v1/storage/buckets/{provider}/{bucket}/objects", s.handleListObjects)
+	api.HandleFunc("GET /v1/storage/buckets/{provider}/{bucket}/objects/{key...}", s.handleDescribeObject)
+	api.HandleFunc("GET /v1/sql/instances", s.handleListInstances)
+	api.HandleFunc("GET /v1/sql/instances/{provider}/{instance}", s.handleDescribeInstance)
+	api.HandleFunc("POST /v1/jobs/sync", s.handleSubmitSync)
+	api.HandleFunc("GET /v1/jobs", s.handleListJobs)
+	api.HandleFunc("GET /v1/jobs/{id}", s.handleGetJob)
+	api.HandleFunc("GET /v1/schedules", s.handleListSchedules)
+	api.HandleFunc("GET /v1/schedules/{name}/history", s.handleScheduleHistory)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("/", s.authenticate(api))
+	return s.logRequests(mux)
+}
+
+// Serve answers requests on listener until ctx is done, then stops accepting
+// requests, cancels running jobs and waits briefly for in-flight requests.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.cancel()
+		return err
+	case <-ctx.Done():
+	}
+
+	s.cancel()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// authenticate rejects requests without the server's bearer token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.deps.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="synkronus"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder keeps the status code a handler wrote, for the request log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		s.deps.Logger.Info("Handled request", "method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start))
+	})
+}
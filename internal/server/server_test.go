@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/service"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jkleinne/synkronus/internal/provider/storage/mock"
+)
+
+const testToken = "s3cret"
+
+// newTestServer returns a server backed by the in-memory mock provider.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f := factory.NewFactory(&config.Config{Mock: &config.MockConfig{Enabled: true}}, logger)
+	storageService := service.NewStorageService(f, logger)
+	t.Cleanup(func() { storageService.Shutdown() })
+
+	srv := New(Deps{
+		StorageService: storageService,
+		SqlService:     service.NewSqlService(f, logger),
+		Providers:      f,
+		Token:          testToken,
+		Logger:         logger,
+	})
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// call sends an authenticated request and decodes the JSON response into v.
+func call(t *testing.T, ts *httptest.Server, method, path, body string, v any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: invalid JSON response: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestAuthentication(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("healthz without a token = %d, want 200", resp.StatusCode)
+	}
+
+	for _, header := range []string{"", "Bearer wrong", testToken} {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/v1/providers", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q = %d, want 401", header, resp.StatusCode)
+		}
+	}
+}
+
+func TestListBuckets(t *testing.T) {
+	ts := newTestServer(t)
+
+	var got bucketsResponse
+	if status := call(t, ts, http.MethodGet, "/v1/storage/buckets", "", &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if len(got.Buckets) != 3 || len(got.Errors) != 0 {
+		t.Errorf("expected the 3 mock buckets and no errors, got %+v", got)
+	}
+
+	var errResp map[string]string
+	if status := call(t, ts, http.MethodGet, "/v1/storage/buckets?providers=azure", "", &errResp); status != http.StatusBadRequest {
+		t.Errorf("unknown provider = %d, want 400", status)
+	}
+}
+
+func TestDescribeBucket(t *testing.T) {
+	ts := newTestServer(t)
+
+	var bucket storage.Bucket
+	if status := call(t, ts, http.MethodGet, "/v1/storage/buckets/mock/acme-data-lake", "", &bucket); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if bucket.Name != "acme-data-lake" {
+		t.Errorf("described %q, want acme-data-lake", bucket.Name)
+	}
+
+	var errResp map[string]string
+	if status := call(t, ts, http.MethodGet, "/v1/storage/buckets/mock/missing", "", &errResp); status != http.StatusNotFound {
+		t.Errorf("missing bucket = %d, want 404", status)
+	}
+	if errResp["error"] == "" {
+		t.Error("expected an error message")
+	}
+}
+
+func TestObjects(t *testing.T) {
+	ts := newTestServer(t)
+
+	var list storage.ObjectList
+	if status := call(t, ts, http.MethodGet, "/v1/storage/buckets/mock/acme-data-lake/objects?prefix=raw/2024/", "", &list); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if len(list.CommonPrefixes) != 3 {
+		t.Errorf("expected the 3 month prefixes, got %v", list.CommonPrefixes)
+	}
+
+	var obj storage.Object
+	if status := call(t, ts, http.MethodGet, "/v1/storage/buckets/mock/acme-data-lake/objects/raw/2024/02/events.json", "", &obj); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if obj.Key != "raw/2024/02/events.json" {
+		t.Errorf("described %q, want the key with its slashes", obj.Key)
+	}
+}
+
+func TestSyncJob(t *testing.T) {
+	ts := newTestServer(t)
+
+	body := `{"source": {"provider": "mock", "bucket": "acme-data-lake", "prefix": "curated/"},
+		"destination": {"provider": "mock", "bucket": "acme-backups", "prefix": "curated/"}}`
+	var job Job
+	if status := call(t, ts, http.MethodPost, "/v1/jobs/sync", body, &job); status != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", status)
+	}
+	if job.ID == "" || job.Type != "sync" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status == JobRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		call(t, ts, http.MethodGet, "/v1/jobs/"+job.ID, "", &job)
+	}
+	if job.Status != JobSucceeded || job.Result == nil || job.Result.Copied != 2 {
+		t.Errorf("expected a finished job that copied 2 objects, got %+v", job)
+	}
+
+	var jobs jobsResponse
+	call(t, ts, http.MethodGet, "/v1/jobs", "", &jobs)
+	if len(jobs.Jobs) != 1 {
+		t.Errorf("expected 1 job in the list, got %d", len(jobs.Jobs))
+	}
+}
+
+func TestSyncJob_InvalidRequest(t *testing.T) {
+	ts := newTestServer(t)
+
+	for _, body := range []string{
+		`not json`,
+		`{"source": {"provider": "mock", "bucket": "a"}, "destination": {"provider": "mock"}}`,
+		`{"source": {"provider": "azure", "bucket": "a"}, "destination": {"provider": "mock", "bucket": "b"}}`,
+		`{"source": {"provider": "mock", "bucket": "a"}, "destination": {"provider": "mock", "bucket": "b"}, "delete": true}`,
+		`{"source": {"provider": "mock", "bucket": "a"}, "destination": {"provider": "mock", "bucket": "b"}, "include": ["[a-"]}`,
+	} {
+		var errResp map[string]string
+		if status := call(t, ts, http.MethodPost, "/v1/jobs/sync", body, &errResp); status != http.StatusBadRequest {
+			t.Errorf("body %s = %d, want 400", body, status)
+		}
+	}
+
+	var errResp map[string]string
+	if status := call(t, ts, http.MethodGet, "/v1/jobs/unknown", "", &errResp); status != http.StatusNotFound {
+		t.Errorf("unknown job = %d, want 404", status)
+	}
+}
+
+func TestServe_StopsWithContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(Deps{Token: testToken, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, listener) }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/healthz")
+	if err != nil {
+		t.Fatalf("server not answering: %v", err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve returned %v, want nil after the context ends", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the context ended")
+	}
+}
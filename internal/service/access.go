@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BucketAccess counts the requests made to a bucket over window (see
+// storage.AccessReporter).
+func (s *StorageService) BucketAccess(ctx context.Context, bucketName, providerName string, window time.Duration) (storage.AccessStats, error) {
+	ctx, done := observe(ctx, "StorageService.BucketAccess", providerName, attribute.String("bucket", bucketName))
+	s.logger.Debug("Starting BucketAccess operation", "bucket", bucketName, "window", window, "provider", providerName)
+	stats, err := withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.AccessStats, error) {
+		reporter, ok := client.(storage.AccessReporter)
+		if !ok {
+			return storage.AccessStats{}, fmt.Errorf("reporting bucket access on %s: %w", providerName, ErrUnsupported)
+		}
+		stats, err := reporter.BucketAccess(ctx, bucketName, window)
+		if err != nil {
+			return storage.AccessStats{}, fmt.Errorf("getting the access of bucket %q on %s: %w", bucketName, providerName, err)
+		}
+		return stats, nil
+	})
+	done(err)
+	return stats, err
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/audit"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestStorageService_AuditLog_RecordsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	mock := &mockStorage{}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+	svc.UseAuditLog(audit.New(path, ""))
+
+	ctx := context.Background()
+	if err := svc.DeleteObject(ctx, "bucket", "a.txt", "gcp"); err != nil {
+		t.Fatalf("DeleteObject failed: %v", err)
+	}
+	mock.err = errors.New("access denied")
+	if err := svc.DeleteBucket(ctx, "bucket", "gcp"); err == nil {
+		t.Fatal("expected DeleteBucket to fail")
+	}
+	// Reads are not recorded
+	svc.DescribeBucket(ctx, "bucket", "gcp")
+
+	entries, err := audit.Read(path, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.Operation != "delete-object" || e.Resource != "bucket/a.txt" || e.Provider != "gcp" || e.Result != audit.ResultSuccess {
+		t.Errorf("unexpected entry for the delete: %+v", e)
+	}
+	if e := entries[1]; e.Operation != "delete-bucket" || e.Result != audit.ResultFailure || e.Error == "" {
+		t.Errorf("expected the failed delete to be recorded, got %+v", e)
+	}
+}
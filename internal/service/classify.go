@@ -0,0 +1,48 @@
+package service
+
+import "errors"
+
+// ErrNotFound and ErrAuth mark provider errors for a resource that does not
+// exist and for credentials that are missing, rejected or lack permission, so
+// callers can tell them apart with errors.Is without parsing provider errors.
+var (
+	ErrNotFound = errors.New("resource not found")
+	ErrAuth     = errors.New("authentication or authorization failed")
+)
+
+// errorClassifier is implemented by provider clients that can tell which of
+// their errors mean a missing resource or a credentials problem.
+type errorClassifier interface {
+	IsNotFound(err error) bool
+	IsAuthError(err error) bool
+}
+
+// classifiedError keeps the message of err while also matching kind.
+type classifiedError struct {
+	err  error
+	kind error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
+
+// classifyError marks err with ErrAuth or ErrNotFound when client recognizes
+// it. Errors from clients without a classifier are returned unchanged.
+func classifyError(client any, err error) error {
+	c, ok := client.(errorClassifier)
+	if err == nil || !ok {
+		return err
+	}
+	switch {
+	case c.IsAuthError(err):
+		return &classifiedError{err: err, kind: ErrAuth}
+	case c.IsNotFound(err):
+		return &classifiedError{err: err, kind: ErrNotFound}
+	}
+	return err
+}
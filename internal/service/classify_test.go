@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+var (
+	errNoSuchBucket = errors.New("no such bucket")
+	errAccessDenied = errors.New("access denied")
+)
+
+// classifyingStorage recognizes errNoSuchBucket and errAccessDenied.
+type classifyingStorage struct {
+	mockStorage
+}
+
+func (c *classifyingStorage) IsNotFound(err error) bool {
+	return errors.Is(err, errNoSuchBucket)
+}
+
+func (c *classifyingStorage) IsAuthError(err error) bool {
+	return errors.Is(err, errAccessDenied)
+}
+
+func TestStorageService_ClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{"not found", errNoSuchBucket, ErrNotFound},
+		{"auth", errAccessDenied, ErrAuth},
+		{"unclassified", errors.New("boom"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &classifyingStorage{mockStorage{providerName: domain.GCP, err: tt.err}}
+			svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": client}})
+
+			checks := map[string]error{}
+			_, checks["describe"] = svc.DescribeBucket(context.Background(), "logs", "gcp")
+			checks["delete"] = svc.DeleteBucket(context.Background(), "logs", "gcp")
+			_, checks["list"] = svc.ListAllBuckets(context.Background(), []string{"gcp"})
+
+			for call, err := range checks {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("%s: expected the provider error in the chain, got %v", call, err)
+				}
+				for _, kind := range []error{ErrNotFound, ErrAuth} {
+					if got := errors.Is(err, kind); got != (kind == tt.wantKind) {
+						t.Errorf("%s: errors.Is(err, %v) = %v", call, kind, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestClassifyError_KeepsMessage(t *testing.T) {
+	err := classifyError(&classifyingStorage{}, errAccessDenied)
+	if err.Error() != errAccessDenied.Error() {
+		t.Errorf("expected the original message, got %q", err.Error())
+	}
+	if classifyError(&mockStorage{}, errAccessDenied) != errAccessDenied {
+		t.Error("expected errors from clients without a classifier to be returned unchanged")
+	}
+}
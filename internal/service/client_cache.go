@@ -0,0 +1,146 @@
+// File: internal/service/client_cache.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// clientCache holds one provider client per provider name, creating each on
+// first use and keeping it until Shutdown. Provider clients hold connection
+// pools and credentials, so batch commands and long-running callers reuse
+// them instead of paying the setup cost on every call.
+//
+// Clients are built from the factory's current config; callers that switch
+// profile or otherwise change the config call Shutdown so the next call
+// creates clients for the new settings. A client still in use by a call is
+// closed once that call releases it.
+type clientCache[C ProviderClient] struct {
+	create func(ctx context.Context, name string) (C, error)
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry[C]
+}
+
+// cacheEntry guards the creation of a single provider's client, so a slow
+// provider doesn't block lookups for the others.
+type cacheEntry[C ProviderClient] struct {
+	mu     sync.Mutex
+	client C
+	ready  bool
+	// users counts the calls that acquired client and have not released it
+	users int
+	// retired is set by shutdown; the entry's client is closed once unused
+	retired bool
+}
+
+func newClientCache[C ProviderClient](create func(ctx context.Context, name string) (C, error), logger *slog.Logger) *clientCache[C] {
+	return &clientCache[C]{
+		create:  create,
+		logger:  logger,
+		entries: make(map[string]*cacheEntry[C]),
+	}
+}
+
+// acquire returns the cached client for name, creating it if needed, and a
+// func to call once done with it, so that shutdown does not close it in the
+// middle of the call. A failed creation is not cached, so a later call retries.
+func (c *clientCache[C]) acquire(ctx context.Context, name string) (C, func(), error) {
+	key := strings.ToLower(name)
+
+	for {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &cacheEntry[C]{}
+			c.entries[key] = entry
+		}
+		c.mu.Unlock()
+
+		entry.mu.Lock()
+		// Retired while this call waited for it; the cache has a new entry
+		if entry.retired {
+			entry.mu.Unlock()
+			continue
+		}
+		if !entry.ready {
+			client, err := c.create(ctx, name)
+			if err != nil {
+				entry.mu.Unlock()
+				var zero C
+				return zero, nil, err
+			}
+			entry.client, entry.ready = client, true
+		}
+		entry.users++
+		client := entry.client
+		entry.mu.Unlock()
+		return client, sync.OnceFunc(func() { c.release(key, entry) }), nil
+	}
+}
+
+// release ends a use of entry's client, closing the client if the entry was
+// retired and this was its last user.
+func (c *clientCache[C]) release(name string, entry *cacheEntry[C]) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	entry.users--
+	if entry.retired && entry.users == 0 {
+		if err := closeEntry(name, entry); err != nil {
+			c.logger.Debug("Failed to close provider client", "error", err)
+		}
+	}
+}
+
+// shutdown empties the cache and closes every cached client that is not in
+// use; the others are closed when their last user releases them. The cache
+// stays usable; later calls create new clients.
+func (c *clientCache[C]) shutdown() error {
+	c.mu.Lock()
+	entries := c.entries
+	c.entries = make(map[string]*cacheEntry[C])
+	c.mu.Unlock()
+
+	var errs []error
+	for name, entry := range entries {
+		entry.mu.Lock()
+		entry.retired = true
+		if entry.users == 0 {
+			errs = append(errs, closeEntry(name, entry))
+		}
+		entry.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// closeEntry closes entry's client, if it has one. entry.mu must be held.
+func closeEntry[C ProviderClient](name string, entry *cacheEntry[C]) error {
+	if !entry.ready {
+		return nil
+	}
+	err := entry.client.Close()
+	var zero C
+	entry.client, entry.ready = zero, false
+	if err != nil {
+		return fmt.Errorf("closing %s client: %w", name, err)
+	}
+	return nil
+}
+
+// releaseOnClose releases the client serving a reader once the reader is
+// closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestClientCache_ReusesClient(t *testing.T) {
+	calls := 0
+	cache := newClientCache(func(_ context.Context, name string) (*mockClient, error) {
+		calls++
+		return &mockClient{name: name}, nil
+	}, newTestLogger())
+
+	first, _, err := cache.acquire(context.Background(), "gcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _, err := cache.acquire(context.Background(), "GCP")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first != second {
+		t.Error("expected the same client for repeated lookups")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 client creation, got %d", calls)
+	}
+}
+
+func TestClientCache_RetriesAfterError(t *testing.T) {
+	calls := 0
+	cache := newClientCache(func(_ context.Context, name string) (*mockClient, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("transient failure")
+		}
+		return &mockClient{name: name}, nil
+	}, newTestLogger())
+
+	if _, _, err := cache.acquire(context.Background(), "aws"); err == nil {
+		t.Fatal("expected error from first creation, got nil")
+	}
+	client, _, err := cache.acquire(context.Background(), "aws")
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got: %v", err)
+	}
+	if client == nil || calls != 2 {
+		t.Errorf("expected a client after 2 creations, got %v after %d", client, calls)
+	}
+}
+
+func TestClientCache_ConcurrentGetCreatesOnce(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	cache := newClientCache(func(_ context.Context, name string) (*mockClient, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return &mockClient{name: name}, nil
+	}, newTestLogger())
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := cache.acquire(context.Background(), "gcp"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected 1 client creation, got %d", calls)
+	}
+}
+
+func TestClientCache_ShutdownClosesClients(t *testing.T) {
+	var created []*mockClient
+	cache := newClientCache(func(_ context.Context, name string) (*mockClient, error) {
+		client := &mockClient{name: name}
+		created = append(created, client)
+		return client, nil
+	}, newTestLogger())
+
+	for _, name := range []string{"gcp", "aws"} {
+		_, release, err := cache.acquire(context.Background(), name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+	if err := cache.shutdown(); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	for _, client := range created {
+		if !client.closed {
+			t.Errorf("expected %s client to be closed", client.name)
+		}
+	}
+
+	// A lookup after shutdown creates a fresh client
+	client, _, err := cache.acquire(context.Background(), "gcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.closed || len(created) != 3 {
+		t.Error("expected a new open client after shutdown")
+	}
+}
+
+func TestClientCache_ShutdownWaitsForUsers(t *testing.T) {
+	var created []*mockClient
+	cache := newClientCache(func(_ context.Context, name string) (*mockClient, error) {
+		client := &mockClient{name: name}
+		created = append(created, client)
+		return client, nil
+	}, newTestLogger())
+
+	inUse, release, err := cache.acquire(context.Background(), "gcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cache.shutdown(); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if inUse.closed {
+		t.Fatal("a client in use must not be closed by shutdown")
+	}
+
+	// Calls after shutdown get a new client, while the old one is still in use
+	fresh, releaseFresh, err := cache.acquire(context.Background(), "gcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer releaseFresh()
+	if fresh == inUse || len(created) != 2 {
+		t.Error("expected a new client after shutdown")
+	}
+
+	release()
+	release()
+	if !inUse.closed {
+		t.Error("expected the old client to be closed once released")
+	}
+	if fresh.closed {
+		t.Error("the new client must stay open")
+	}
+}
+
+func TestStorageService_DownloadKeepsClientUntilClosed(t *testing.T) {
+	mock := &mockStorage{}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	reader, err := svc.DownloadObject(context.Background(), "my-bucket", "key", "gcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Shutdown(); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if mock.closeCalled {
+		t.Fatal("the client must stay open while the download is read")
+	}
+	reader.Close()
+	if !mock.closeCalled {
+		t.Error("expected the client to be closed once the download is closed")
+	}
+}
+
+func TestStorageService_Shutdown_ClosesCachedClient(t *testing.T) {
+	mock := &mockStorage{}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	for range 2 {
+		if err := svc.DeleteBucket(context.Background(), "my-bucket", "gcp"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if mock.closeCalled {
+		t.Fatal("provider client should stay open between calls")
+	}
+
+	if err := svc.Shutdown(); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if !mock.closeCalled {
+		t.Error("expected Shutdown to close the provider client")
+	}
+}
+
+func TestClientCache_ShutdownReportsCloseErrors(t *testing.T) {
+	cache := newClientCache(func(_ context.Context, name string) (*failingCloser, error) {
+		return &failingCloser{}, nil
+	}, newTestLogger())
+	_, release, err := cache.acquire(context.Background(), "gcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+
+	err = cache.shutdown()
+	if err == nil || !strings.Contains(err.Error(), "closing gcp client") {
+		t.Errorf("expected close error naming the provider, got: %v", err)
+	}
+}
+
+// failingCloser is a provider client whose Close always fails.
+type failingCloser struct{}
+
+func (failingCloser) Close() error { return errors.New("close failed") }
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Reasons an object differs between source and destination
+const (
+	MismatchSize     = "size"
+	MismatchChecksum = "checksum"
+)
+
+// ObjectMismatch is a key present on both sides with different content.
+type ObjectMismatch struct {
+	// Key is relative to the source and destination prefixes
+	Key             string `json:"key" yaml:"key"`
+	Reason          string `json:"reason" yaml:"reason"`
+	SourceSize      int64  `json:"source_size" yaml:"source_size"`
+	DestinationSize int64  `json:"destination_size" yaml:"destination_size"`
+}
+
+// ObjectComparison is the difference between the object inventories of two
+// targets. Keys are relative to each side's prefix and sorted.
+type ObjectComparison struct {
+	Source      SyncTarget `json:"source" yaml:"source"`
+	Destination SyncTarget `json:"destination" yaml:"destination"`
+	// Matched counts the keys with the same content on both sides
+	Matched int `json:"matched" yaml:"matched"`
+	// SizeOnly counts the matched keys whose checksums could not be compared,
+	// because the providers report different kinds (e.g., S3 objects have no
+	// MD5 outside their ETag), so only their sizes were
+	SizeOnly          int              `json:"size_only" yaml:"size_only"`
+	OnlyInSource      []string         `json:"only_in_source" yaml:"only_in_source"`
+	OnlyInDestination []string         `json:"only_in_destination" yaml:"only_in_destination"`
+	Mismatched        []ObjectMismatch `json:"mismatched" yaml:"mismatched"`
+}
+
+// Differs reports whether the two sides hold different objects.
+func (c ObjectComparison) Differs() bool {
+	return len(c.OnlyInSource) > 0 || len(c.OnlyInDestination) > 0 || len(c.Mismatched) > 0
+}
+
+// CompareObjects compares the objects under the source and destination
+// prefixes of opts, as a sync would see them: by relative key, keeping only
+// the keys passing the Include and Exclude patterns. Content is judged by
+// size and the checksums both providers report (MD5, else CRC32C).
+func (s *StorageService) CompareObjects(ctx context.Context, opts SyncOptions) (ObjectComparison, error) {
+	src, dst := opts.Source, opts.Destination
+	ctx, span := telemetry.Start(ctx, "StorageService.CompareObjects",
+		attribute.String("source", src.String()), attribute.String("destination", dst.String()))
+	s.logger.Debug("Starting CompareObjects operation", "source", src.String(), "destination", dst.String())
+
+	result, err := s.compareObjects(ctx, opts)
+	telemetry.End(span, err)
+	return result, err
+}
+
+func (s *StorageService) compareObjects(ctx context.Context, opts SyncOptions) (ObjectComparison, error) {
+	src, dst := opts.Source, opts.Destination
+	result := ObjectComparison{
+		Source:            src,
+		Destination:       dst,
+		OnlyInSource:      []string{},
+		OnlyInDestination: []string{},
+		Mismatched:        []ObjectMismatch{},
+	}
+	for _, patterns := range [][]string{opts.Include, opts.Exclude} {
+		if err := ValidatePatterns(patterns); err != nil {
+			return result, err
+		}
+	}
+
+	existing := make(map[string]storage.Object)
+	err := s.WalkObjects(ctx, dst.Bucket, dst.Provider, dst.Prefix, func(obj storage.Object) error {
+		rel := strings.TrimPrefix(obj.Key, dst.Prefix)
+		if included(rel, opts.Include, opts.Exclude) {
+			existing[rel] = obj
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = s.WalkObjects(ctx, src.Bucket, src.Provider, src.Prefix, func(obj storage.Object) error {
+		rel := strings.TrimPrefix(obj.Key, src.Prefix)
+		if !included(rel, opts.Include, opts.Exclude) {
+			return nil
+		}
+		current, ok := existing[rel]
+		if !ok {
+			result.OnlyInSource = append(result.OnlyInSource, rel)
+			return nil
+		}
+		delete(existing, rel)
+		reason, checked := contentMismatch(obj, current)
+		switch {
+		case reason != "":
+			result.Mismatched = append(result.Mismatched, ObjectMismatch{
+				Key:             rel,
+				Reason:          reason,
+				SourceSize:      obj.Size,
+				DestinationSize: current.Size,
+			})
+		case !checked:
+			result.Matched++
+			result.SizeOnly++
+		default:
+			result.Matched++
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for rel := range existing {
+		result.OnlyInDestination = append(result.OnlyInDestination, rel)
+	}
+	slices.Sort(result.OnlyInSource)
+	slices.Sort(result.OnlyInDestination)
+	slices.SortFunc(result.Mismatched, func(a, b ObjectMismatch) int { return strings.Compare(a.Key, b.Key) })
+	return result, nil
+}
+
+// contentMismatch returns why two objects hold different data, or "" if they
+// appear the same. checked is false when their sizes match but no checksum
+// is reported by both, so the sizes were all that could be compared.
+func contentMismatch(a, b storage.Object) (reason string, checked bool) {
+	if a.Size != b.Size {
+		return MismatchSize, true
+	}
+	switch {
+	case a.MD5Hash != "" && b.MD5Hash != "":
+		if a.MD5Hash != b.MD5Hash {
+			return MismatchChecksum, true
+		}
+	default:
+		// S3 additional checksums only compare when neither side is composite,
+		// since a composite one depends on how the upload was split
+		for _, alg := range []string{storage.ChecksumSHA256, storage.ChecksumCRC32C, storage.ChecksumCRC32} {
+			ca, cb := a.Checksum(alg), b.Checksum(alg)
+			if ca == "" || cb == "" || isComposite(a) || isComposite(b) {
+				continue
+			}
+			if ca != cb {
+				return MismatchChecksum, true
+			}
+			return "", true
+		}
+		return "", false
+	}
+	return "", true
+}
+
+func isComposite(obj storage.Object) bool {
+	return obj.ChecksumType == storage.ChecksumTypeComposite
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestStorageService_CompareObjects(t *testing.T) {
+	svc := newSyncTestService(t)
+	ctx := context.Background()
+	src := SyncTarget{Provider: "dst", Bucket: "acme-data-lake", Prefix: "curated/"}
+	dst := SyncTarget{Provider: "dst", Bucket: "replica"}
+	if _, err := svc.Sync(ctx, SyncOptions{Source: src, Destination: dst}); err != nil {
+		t.Fatal(err)
+	}
+
+	// One object changed, one removed and one extra at the destination
+	if err := svc.UploadObject(ctx, storage.UploadObjectOptions{BucketName: "replica", ObjectKey: "orders.csv"}, "dst", strings.NewReader("changed")); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteObject(ctx, "replica", "customers.csv", "dst"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UploadObject(ctx, storage.UploadObjectOptions{BucketName: "replica", ObjectKey: "extra.txt"}, "dst", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.CompareObjects(ctx, SyncOptions{Source: src, Destination: dst})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Differs() {
+		t.Fatal("expected the locations to differ")
+	}
+	if !slices.Equal(result.OnlyInSource, []string{"customers.csv"}) {
+		t.Errorf("expected customers.csv only in the source, got %v", result.OnlyInSource)
+	}
+	if !slices.Equal(result.OnlyInDestination, []string{"extra.txt"}) {
+		t.Errorf("expected extra.txt only in the destination, got %v", result.OnlyInDestination)
+	}
+	if len(result.Mismatched) != 1 || result.Mismatched[0].Key != "orders.csv" {
+		t.Errorf("expected orders.csv to differ, got %+v", result.Mismatched)
+	}
+
+	// Filters apply to both sides
+	result, err = svc.CompareObjects(ctx, SyncOptions{Source: src, Destination: dst, Exclude: []string{"customers.csv", "orders.csv", "extra.txt"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Differs() {
+		t.Errorf("expected the filtered locations to match, got %+v", result)
+	}
+}
+
+func TestContentMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    storage.Object
+		reason  string
+		checked bool
+	}{
+		{"size", storage.Object{Size: 1}, storage.Object{Size: 2}, MismatchSize, true},
+		{"md5", storage.Object{Size: 1, MD5Hash: "a"}, storage.Object{Size: 1, MD5Hash: "b"}, MismatchChecksum, true},
+		{"crc32c", storage.Object{Size: 1, CRC32C: "a"}, storage.Object{Size: 1, CRC32C: "b"}, MismatchChecksum, true},
+		{"same md5", storage.Object{Size: 1, MD5Hash: "a"}, storage.Object{Size: 1, MD5Hash: "a"}, "", true},
+		{"no common checksum", storage.Object{Size: 1, MD5Hash: "a"}, storage.Object{Size: 1}, "", false},
+		{"sha256", storage.Object{Size: 1, SHA256: "a"}, storage.Object{Size: 1, SHA256: "b"}, MismatchChecksum, true},
+		{"same crc32", storage.Object{Size: 1, CRC32: "a"}, storage.Object{Size: 1, CRC32: "a"}, "", true},
+		{"composite", storage.Object{Size: 1, SHA256: "a-2", ChecksumType: storage.ChecksumTypeComposite}, storage.Object{Size: 1, SHA256: "b"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, checked := contentMismatch(tt.a, tt.b)
+			if reason != tt.reason || checked != tt.checked {
+				t.Errorf("contentMismatch = (%q, %v), want (%q, %v)", reason, checked, tt.reason, tt.checked)
+			}
+		})
+	}
+}
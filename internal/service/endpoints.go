@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BucketURL returns the HTTPS URL of a bucket in style (see
+// storage.BucketURLResolver).
+func (s *StorageService) BucketURL(ctx context.Context, bucketName, providerName, style string) (string, error) {
+	ctx, done := observe(ctx, "StorageService.BucketURL", providerName, attribute.String("bucket", bucketName))
+	s.logger.Debug("Starting BucketURL operation", "bucket", bucketName, "style", style, "provider", providerName)
+	bucketURL, err := withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (string, error) {
+		resolver, ok := client.(storage.BucketURLResolver)
+		if !ok {
+			return "", fmt.Errorf("resolving bucket URLs on %s: %w", providerName, ErrUnsupported)
+		}
+		bucketURL, err := resolver.BucketURL(ctx, bucketName, style)
+		if err != nil {
+			return "", fmt.Errorf("resolving the URL of bucket %q on %s: %w", bucketName, providerName, err)
+		}
+		return bucketURL, nil
+	})
+	done(err)
+	return bucketURL, err
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrUnsupported is returned for an operation the provider does not offer.
+var ErrUnsupported = errors.New("not supported by this provider")
+
+// TailEvents streams the object events of a bucket to fn as the provider's
+// event notifications deliver them, until ctx is done or fn fails (see
+// storage.EventTailer). Events are never cached or retried.
+func (s *StorageService) TailEvents(ctx context.Context, bucketName, providerName string, ready func(source string), fn func(storage.ObjectEvent) error) error {
+	ctx, done := observe(ctx, "StorageService.TailEvents", providerName, attribute.String("bucket", bucketName))
+	s.logger.Debug("Starting TailEvents operation", "bucket", bucketName, "provider", providerName)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
+		tailer, ok := client.(storage.EventTailer)
+		if !ok {
+			return fmt.Errorf("tailing bucket events on %s: %w", providerName, ErrUnsupported)
+		}
+		if err := tailer.TailEvents(ctx, bucketName, ready, fn); err != nil {
+			return fmt.Errorf("tailing events of bucket %q on %s: %w", bucketName, providerName, err)
+		}
+		return nil
+	})
+	done(err)
+	return err
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strings"
 	"sync"
+	"time"
 )
 
 // ProviderClient is the constraint for provider clients that can be closed.
@@ -14,47 +16,150 @@ type ProviderClient interface {
 	io.Closer
 }
 
+// ProviderError is the failure of one provider in a multi-provider call.
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("provider %s: %v", e.Provider, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// ProviderErrors lists the providers that failed in a multi-provider call,
+// in the order the providers were requested. Results from the providers that
+// succeeded are returned alongside it; use errors.As to get the failures.
+type ProviderErrors []*ProviderError
+
+func (e ProviderErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e ProviderErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ErrProviderTimeout is the failure of a provider that did not answer within
+// FanOutLimits.ProviderTimeout.
+var ErrProviderTimeout = errors.New("timed out")
+
+// ErrCanceledByFailure is the failure of a provider that was stopped because
+// another provider failed first, in a call made with WithFailFast.
+var ErrCanceledByFailure = errors.New("canceled after another provider failed")
+
+type failFastKey struct{}
+
+// WithFailFast makes the multi-provider calls made with ctx cancel the
+// providers still running as soon as one fails (e.g., for --fail-fast), rather
+// than wait for their partial results.
+func WithFailFast(ctx context.Context) context.Context {
+	return context.WithValue(ctx, failFastKey{}, true)
+}
+
+// FanOutLimits bound multi-provider calls, so one slow provider does not stall
+// the whole command. The zero value imposes no limits.
+type FanOutLimits struct {
+	// ProviderTimeout bounds each provider's share of the call; 0 means no limit
+	ProviderTimeout time.Duration
+	// Concurrency is how many providers are queried at once; 0 means all
+	Concurrency int
+}
+
 // concurrentFanOut runs listFn concurrently across all providers, collecting
-// results and errors. Partial results are returned alongside any errors.
+// results and errors. Partial results are returned alongside a ProviderErrors
+// naming the providers that failed, including those that exceeded
+// limits.ProviderTimeout. Results a provider returns with its error (e.g., from
+// the projects or accounts that did not fail) are kept. Under WithFailFast the
+// first failure cancels the other providers, which fail with
+// ErrCanceledByFailure.
 func concurrentFanOut[C ProviderClient, T any](
 	ctx context.Context,
 	providerNames []string,
-	getClient func(ctx context.Context, name string) (C, error),
+	limits FanOutLimits,
+	getClient func(ctx context.Context, name string) (C, func(), error),
 	listFn func(ctx context.Context, client C) ([]T, error),
 	logger *slog.Logger,
 ) ([]T, error) {
 	var allResults []T
-	var errs []error
+	failures := make([]*ProviderError, len(providerNames))
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	for _, providerName := range providerNames {
+	concurrency := limits.Concurrency
+	if concurrency <= 0 {
+		concurrency = len(providerNames)
+	}
+	slots := make(chan struct{}, max(concurrency, 1))
+
+	failFast, _ := ctx.Value(failFastKey{}).(bool)
+	fanCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	fail := func(i int, err error) {
+		failures[i] = &ProviderError{Provider: providerNames[i], Err: err}
+		if failFast {
+			cancel(ErrCanceledByFailure)
+		}
+	}
+
+	for i, providerName := range providerNames {
 		wg.Add(1)
 		go func(providerName string) {
 			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
 
-			client, err := getClient(ctx, providerName)
-			if err != nil {
-				logger.Error("Failed to initialize provider client", "provider", providerName, "error", err)
-				mu.Lock()
-				errs = append(errs, fmt.Errorf("provider %s: %w", providerName, err))
-				mu.Unlock()
-				return
+			providerCtx := fanCtx
+			if limits.ProviderTimeout > 0 {
+				var cancel context.CancelFunc
+				providerCtx, cancel = context.WithTimeout(fanCtx, limits.ProviderTimeout)
+				defer cancel()
+			}
+			// A provider that ran out of time, or was stopped because another
+			// failed, reports it as such, rather than whatever error the
+			// canceled request surfaced as
+			timedOut := func(err error) error {
+				if ctx.Err() != nil {
+					return err
+				}
+				if errors.Is(context.Cause(fanCtx), ErrCanceledByFailure) {
+					return ErrCanceledByFailure
+				}
+				if errors.Is(providerCtx.Err(), context.DeadlineExceeded) {
+					return fmt.Errorf("%w after %s", ErrProviderTimeout, limits.ProviderTimeout)
+				}
+				return err
 			}
-			defer client.Close()
 
-			results, err := listFn(ctx, client)
+			client, release, err := getClient(providerCtx, providerName)
 			if err != nil {
-				logger.Error("Failed to list from provider", "provider", providerName, "error", err)
-				mu.Lock()
-				errs = append(errs, fmt.Errorf("provider %s: %w", providerName, err))
-				mu.Unlock()
+				err = timedOut(err)
+				logger.Error("Failed to initialize provider client", "provider", providerName, "error", err)
+				fail(i, err)
 				return
 			}
-
+			defer release()
+			results, err := listFn(providerCtx, client)
 			mu.Lock()
 			allResults = append(allResults, results...)
 			mu.Unlock()
+			if err != nil {
+				err = timedOut(classifyError(client, err))
+				logger.Error("Failed to list from provider", "provider", providerName, "error", err)
+				fail(i, err)
+				return
+			}
 
 			logger.Debug("Successfully fetched results", "provider", providerName, "count", len(results))
 		}(providerName)
@@ -62,5 +167,14 @@ func concurrentFanOut[C ProviderClient, T any](
 
 	wg.Wait()
 
-	return allResults, errors.Join(errs...)
+	var errs ProviderErrors
+	for _, failure := range failures {
+		if failure != nil {
+			errs = append(errs, failure)
+		}
+	}
+	if len(errs) == 0 {
+		return allResults, nil
+	}
+	return allResults, errs
 }
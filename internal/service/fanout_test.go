@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type mockClient struct {
@@ -22,8 +25,9 @@ func TestConcurrentFanOut_AllSucceed(t *testing.T) {
 	results, err := concurrentFanOut(
 		context.Background(),
 		[]string{"a", "b"},
-		func(ctx context.Context, name string) (*mockClient, error) {
-			return &mockClient{name: name}, nil
+		FanOutLimits{},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return &mockClient{name: name}, func() {}, nil
 		},
 		func(ctx context.Context, client *mockClient) ([]string, error) {
 			return []string{client.name + "-1", client.name + "-2"}, nil
@@ -43,8 +47,9 @@ func TestConcurrentFanOut_AllFail(t *testing.T) {
 	results, err := concurrentFanOut(
 		context.Background(),
 		[]string{"a", "b"},
-		func(ctx context.Context, name string) (*mockClient, error) {
-			return nil, fmt.Errorf("init failed for %s", name)
+		FanOutLimits{},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return nil, nil, fmt.Errorf("init failed for %s", name)
 		},
 		func(ctx context.Context, client *mockClient) ([]string, error) {
 			t.Fatal("listFn should not be called when getClient fails")
@@ -62,14 +67,23 @@ func TestConcurrentFanOut_AllFail(t *testing.T) {
 	if !strings.Contains(err.Error(), "provider a") || !strings.Contains(err.Error(), "provider b") {
 		t.Errorf("expected errors for both providers, got: %v", err)
 	}
+
+	var failures ProviderErrors
+	if !errors.As(err, &failures) {
+		t.Fatalf("expected ProviderErrors, got %T", err)
+	}
+	if len(failures) != 2 || failures[0].Provider != "a" || failures[1].Provider != "b" {
+		t.Errorf("expected failures for a and b in request order, got %v", failures)
+	}
 }
 
 func TestConcurrentFanOut_PartialFailure(t *testing.T) {
 	results, err := concurrentFanOut(
 		context.Background(),
 		[]string{"ok", "fail"},
-		func(ctx context.Context, name string) (*mockClient, error) {
-			return &mockClient{name: name}, nil
+		FanOutLimits{},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return &mockClient{name: name}, func() {}, nil
 		},
 		func(ctx context.Context, client *mockClient) ([]string, error) {
 			if client.name == "fail" {
@@ -89,15 +103,47 @@ func TestConcurrentFanOut_PartialFailure(t *testing.T) {
 	if !strings.Contains(err.Error(), "provider fail") {
 		t.Errorf("expected error to mention failing provider, got: %v", err)
 	}
+
+	var failures ProviderErrors
+	if !errors.As(err, &failures) || len(failures) != 1 || failures[0].Provider != "fail" {
+		t.Fatalf("expected a single failure for provider fail, got %v", err)
+	}
+	if failures[0].Err.Error() != "list failed" {
+		t.Errorf("expected the provider's own error, got %v", failures[0].Err)
+	}
+}
+
+func TestConcurrentFanOut_KeepsResultsOfFailedProvider(t *testing.T) {
+	results, err := concurrentFanOut(
+		context.Background(),
+		[]string{"gcp"},
+		FanOutLimits{},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return &mockClient{name: name}, func() {}, nil
+		},
+		func(ctx context.Context, client *mockClient) ([]string, error) {
+			return []string{"project-a-bucket"}, fmt.Errorf("project b: denied")
+		},
+		slog.Default(),
+	)
+
+	var failures ProviderErrors
+	if !errors.As(err, &failures) || len(failures) != 1 {
+		t.Fatalf("expected the provider's failure, got %v", err)
+	}
+	if len(results) != 1 || results[0] != "project-a-bucket" {
+		t.Errorf("expected the results listed before the failure, got %v", results)
+	}
 }
 
 func TestConcurrentFanOut_EmptyProviders(t *testing.T) {
 	results, err := concurrentFanOut(
 		context.Background(),
 		[]string{},
-		func(ctx context.Context, name string) (*mockClient, error) {
+		FanOutLimits{},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
 			t.Fatal("getClient should not be called for empty providers")
-			return nil, nil
+			return nil, nil, nil
 		},
 		func(ctx context.Context, client *mockClient) ([]string, error) {
 			t.Fatal("listFn should not be called for empty providers")
@@ -117,8 +163,9 @@ func TestConcurrentFanOut_SingleProvider(t *testing.T) {
 	results, err := concurrentFanOut(
 		context.Background(),
 		[]string{"only"},
-		func(ctx context.Context, name string) (*mockClient, error) {
-			return &mockClient{name: name}, nil
+		FanOutLimits{},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return &mockClient{name: name}, func() {}, nil
 		},
 		func(ctx context.Context, client *mockClient) ([]string, error) {
 			return []string{"result-1"}, nil
@@ -137,8 +184,9 @@ func TestConcurrentFanOut_ListFnReturnsEmptySlice(t *testing.T) {
 	results, err := concurrentFanOut(
 		context.Background(),
 		[]string{"empty-provider"},
-		func(ctx context.Context, name string) (*mockClient, error) {
-			return &mockClient{name: name}, nil
+		FanOutLimits{},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return &mockClient{name: name}, func() {}, nil
 		},
 		func(ctx context.Context, client *mockClient) ([]string, error) {
 			return []string{}, nil
@@ -152,3 +200,112 @@ func TestConcurrentFanOut_ListFnReturnsEmptySlice(t *testing.T) {
 		t.Errorf("expected 0 results, got %d", len(results))
 	}
 }
+
+func TestConcurrentFanOut_ProviderTimeout(t *testing.T) {
+	results, err := concurrentFanOut(
+		context.Background(),
+		[]string{"fast", "slow"},
+		FanOutLimits{ProviderTimeout: 20 * time.Millisecond},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return &mockClient{name: name}, func() {}, nil
+		},
+		func(ctx context.Context, client *mockClient) ([]string, error) {
+			if client.name == "slow" {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return []string{"fast-1"}, nil
+		},
+		slog.Default(),
+	)
+
+	if len(results) != 1 || results[0] != "fast-1" {
+		t.Errorf("expected the fast provider's results, got %v", results)
+	}
+	var providerErrs ProviderErrors
+	if !errors.As(err, &providerErrs) || len(providerErrs) != 1 || providerErrs[0].Provider != "slow" {
+		t.Fatalf("expected only the slow provider to fail, got %v", err)
+	}
+	if !errors.Is(err, ErrProviderTimeout) || !strings.Contains(err.Error(), "timed out after 20ms") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+}
+
+func TestConcurrentFanOut_FailFastCancelsOtherProviders(t *testing.T) {
+	failed := errors.New("access denied")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := concurrentFanOut(
+			WithFailFast(context.Background()),
+			[]string{"broken", "slow-1", "slow-2"},
+			FanOutLimits{},
+			func(ctx context.Context, name string) (*mockClient, func(), error) {
+				return &mockClient{name: name}, func() {}, nil
+			},
+			func(ctx context.Context, client *mockClient) ([]string, error) {
+				if client.name == "broken" {
+					return nil, failed
+				}
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			slog.Default(),
+		)
+
+		var providerErrs ProviderErrors
+		if !errors.As(err, &providerErrs) || len(providerErrs) != 3 {
+			t.Errorf("expected all three providers to fail, got %v", err)
+			return
+		}
+		if !errors.Is(providerErrs[0], failed) {
+			t.Errorf("expected the broken provider's own error, got %v", providerErrs[0])
+		}
+		for _, pe := range providerErrs[1:] {
+			if !errors.Is(pe, ErrCanceledByFailure) {
+				t.Errorf("expected %s to be canceled by the failure, got %v", pe.Provider, pe.Err)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the remaining providers were not canceled after the first failure")
+	}
+}
+
+func TestConcurrentFanOut_Concurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	results, err := concurrentFanOut(
+		context.Background(),
+		[]string{"a", "b", "c", "d", "e"},
+		FanOutLimits{Concurrency: 2},
+		func(ctx context.Context, name string) (*mockClient, func(), error) {
+			return &mockClient{name: name}, func() {}, nil
+		},
+		func(ctx context.Context, client *mockClient) ([]string, error) {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return []string{client.name}, nil
+		},
+		slog.Default(),
+	)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 5 {
+		t.Errorf("expected 5 results, got %d", len(results))
+	}
+	if peak > 2 {
+		t.Errorf("expected at most 2 providers at once, got %d", peak)
+	}
+}
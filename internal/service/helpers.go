@@ -2,20 +2,23 @@ package service
 
 import "context"
 
-// withClientResult acquires a provider client, calls fn, and ensures the
-// client is closed afterward. Used by service methods that return a value.
-// Error wrapping for the specific operation belongs in fn, not here.
+// withClientResult acquires a provider client and calls fn. Used by service
+// methods that return a value. Clients are owned by the service's client
+// cache, so they are released here rather than closed.
+// Error wrapping for the specific operation belongs in fn, not here; the
+// client only classifies the error fn returns.
 func withClientResult[C ProviderClient, R any](
 	ctx context.Context,
-	getClient func(ctx context.Context, name string) (C, error),
+	getClient func(ctx context.Context, name string) (C, func(), error),
 	providerName string,
 	fn func(client C) (R, error),
 ) (R, error) {
-	client, err := getClient(ctx, providerName)
+	client, release, err := getClient(ctx, providerName)
 	if err != nil {
 		var zero R
 		return zero, err
 	}
-	defer client.Close()
-	return fn(client)
+	defer release()
+	result, err := fn(client)
+	return result, classifyError(client, err)
 }
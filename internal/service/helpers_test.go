@@ -5,17 +5,17 @@ import (
 	"errors"
 	"testing"
 
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 // TestWithClientResult_HappyPath verifies that on success the fn return value
-// is propagated and the client is closed afterward.
+// is propagated and the client is left open for reuse.
 func TestWithClientResult_HappyPath(t *testing.T) {
 	want := storage.Bucket{Name: "test-bucket"}
 	mock := &mockStorage{bucket: want}
 
-	getClient := func(_ context.Context, _ string) (storage.Storage, error) {
-		return mock, nil
+	getClient := func(_ context.Context, _ string) (storage.Storage, func(), error) {
+		return mock, func() {}, nil
 	}
 
 	got, err := withClientResult(context.Background(), getClient, "gcp", func(client storage.Storage) (storage.Bucket, error) {
@@ -28,8 +28,8 @@ func TestWithClientResult_HappyPath(t *testing.T) {
 	if got.Name != want.Name {
 		t.Errorf("got bucket name %q, want %q", got.Name, want.Name)
 	}
-	if !mock.closeCalled {
-		t.Error("expected Close to be called on success")
+	if mock.closeCalled {
+		t.Error("withClientResult should not close the client")
 	}
 }
 
@@ -39,8 +39,8 @@ func TestWithClientResult_ClientError(t *testing.T) {
 	clientErr := errors.New("provider unavailable")
 	fnCalled := false
 
-	getClient := func(_ context.Context, _ string) (storage.Storage, error) {
-		return nil, clientErr
+	getClient := func(_ context.Context, _ string) (storage.Storage, func(), error) {
+		return nil, nil, clientErr
 	}
 
 	_, err := withClientResult(context.Background(), getClient, "gcp", func(_ storage.Storage) (storage.Bucket, error) {
@@ -60,13 +60,13 @@ func TestWithClientResult_ClientError(t *testing.T) {
 }
 
 // TestWithClientResult_FnError verifies that when fn returns an error the
-// client is still closed and the error is propagated.
+// error is propagated.
 func TestWithClientResult_FnError(t *testing.T) {
 	fnErr := errors.New("operation failed")
 	mock := &mockStorage{}
 
-	getClient := func(_ context.Context, _ string) (storage.Storage, error) {
-		return mock, nil
+	getClient := func(_ context.Context, _ string) (storage.Storage, func(), error) {
+		return mock, func() {}, nil
 	}
 
 	_, err := withClientResult(context.Background(), getClient, "gcp", func(_ storage.Storage) (storage.Bucket, error) {
@@ -79,7 +79,4 @@ func TestWithClientResult_FnError(t *testing.T) {
 	if !errors.Is(err, fnErr) {
 		t.Errorf("error chain should wrap fn error; got: %v", err)
 	}
-	if !mock.closeCalled {
-		t.Error("expected Close to be called even when fn returns an error")
-	}
 }
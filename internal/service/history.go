@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ObjectHistory returns the changes made to an object since the given time,
+// from the provider's audit logs (see storage.ObjectHistoryReader).
+func (s *StorageService) ObjectHistory(ctx context.Context, bucketName, objectKey, providerName string, since time.Time, limit int) ([]storage.ObjectChange, error) {
+	ctx, done := observe(ctx, "StorageService.ObjectHistory", providerName, attribute.String("bucket", bucketName))
+	s.logger.Debug("Starting ObjectHistory operation", "bucket", bucketName, "key", objectKey, "provider", providerName)
+	changes, err := withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) ([]storage.ObjectChange, error) {
+		reader, ok := client.(storage.ObjectHistoryReader)
+		if !ok {
+			return nil, fmt.Errorf("reading object history on %s: %w", providerName, ErrUnsupported)
+		}
+		changes, err := reader.ObjectHistory(ctx, bucketName, objectKey, since, limit)
+		if err != nil {
+			return nil, fmt.Errorf("reading the history of %s in bucket %q on %s: %w", objectKey, bucketName, providerName, err)
+		}
+		return changes, nil
+	})
+	done(err)
+	return changes, err
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/metrics"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// observe starts a span for operation on a provider and returns the context
+// for its calls and a function that ends the span and counts the call in the
+// metrics, with the operation's error.
+func observe(ctx context.Context, operation, providerName string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := telemetry.Start(ctx, operation, append(attrs, telemetry.Provider(providerName))...)
+	return ctx, func(err error) {
+		metrics.ObserveCall(providerName, operation, start, err)
+		telemetry.End(span, err)
+	}
+}
+
+// readerSize returns the number of bytes left in r when it knows them (files,
+// in-memory readers), or 0. Uploads are counted from it rather than by wrapping
+// r, which would hide the io.Seeker the AWS SDK needs to sign the request.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case interface{ Stat() (fs.FileInfo, error) }:
+		info, err := v.Stat()
+		if err != nil {
+			return 0
+		}
+		size := info.Size()
+		if seeker, ok := r.(io.Seeker); ok {
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				size -= offset
+			}
+		}
+		return size
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ListOrganizationBuckets lists the buckets of every project under an
+// organization or folder (see storage.OrganizationBucketLister).
+func (s *StorageService) ListOrganizationBuckets(ctx context.Context, providerName, scope string) ([]storage.Bucket, error) {
+	ctx, done := observe(ctx, "StorageService.ListOrganizationBuckets", providerName, attribute.String("scope", scope))
+	s.logger.Debug("Starting ListOrganizationBuckets operation", "scope", scope, "provider", providerName)
+	buckets, err := withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) ([]storage.Bucket, error) {
+		lister, ok := client.(storage.OrganizationBucketLister)
+		if !ok {
+			return nil, fmt.Errorf("listing the buckets of %s on %s: %w", scope, providerName, ErrUnsupported)
+		}
+		buckets, err := lister.ListOrganizationBuckets(ctx, scope)
+		if err != nil {
+			return nil, fmt.Errorf("listing the buckets of %s on %s: %w", scope, providerName, err)
+		}
+		return buckets, nil
+	})
+	done(err)
+	return buckets, err
+}
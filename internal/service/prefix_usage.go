@@ -0,0 +1,99 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PrefixUsage is the size and object count under one prefix.
+type PrefixUsage struct {
+	Prefix  string `json:"prefix" yaml:"prefix"`
+	Objects int64  `json:"objects" yaml:"objects"`
+	Bytes   int64  `json:"bytes" yaml:"bytes"`
+}
+
+// PrefixUsageReport breaks down the usage under a prefix of a bucket by the
+// prefixes Depth levels below it, largest first.
+type PrefixUsageReport struct {
+	Provider string        `json:"provider" yaml:"provider"`
+	Bucket   string        `json:"bucket" yaml:"bucket"`
+	Prefix   string        `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Depth    int           `json:"depth" yaml:"depth"`
+	Prefixes []PrefixUsage `json:"prefixes" yaml:"prefixes"`
+	Objects  int64         `json:"objects" yaml:"objects"`
+	Bytes    int64         `json:"bytes" yaml:"bytes"`
+}
+
+// PrefixUsage walks the objects under prefix and sums their sizes and counts
+// by the first depth "directories" of their keys below prefix. Objects
+// shallower than that count towards the deepest prefix they are in, so an
+// object directly under prefix counts towards prefix itself.
+func (s *StorageService) PrefixUsage(ctx context.Context, bucketName, providerName, prefix string, depth int) (PrefixUsageReport, error) {
+	ctx, span := telemetry.Start(ctx, "StorageService.PrefixUsage",
+		attribute.String("bucket", bucketName), attribute.String("provider", providerName))
+	s.logger.Debug("Starting PrefixUsage operation", "bucket", bucketName, "provider", providerName, "prefix", prefix, "depth", depth)
+
+	report, err := s.prefixUsage(ctx, bucketName, providerName, prefix, depth)
+	telemetry.End(span, err)
+	return report, err
+}
+
+func (s *StorageService) prefixUsage(ctx context.Context, bucketName, providerName, prefix string, depth int) (PrefixUsageReport, error) {
+	if depth <= 0 {
+		return PrefixUsageReport{}, fmt.Errorf("depth must be positive, got %d", depth)
+	}
+	report := PrefixUsageReport{
+		Provider: strings.ToLower(providerName),
+		Bucket:   bucketName,
+		Prefix:   prefix,
+		Depth:    depth,
+		Prefixes: []PrefixUsage{},
+	}
+	usage := make(map[string]*PrefixUsage)
+	err := s.WalkObjects(ctx, bucketName, providerName, prefix, func(obj storage.Object) error {
+		group := prefix + prefixOf(strings.TrimPrefix(obj.Key, prefix), depth)
+		u, ok := usage[group]
+		if !ok {
+			u = &PrefixUsage{Prefix: group}
+			usage[group] = u
+		}
+		u.Objects++
+		u.Bytes += obj.Size
+		report.Objects++
+		report.Bytes += obj.Size
+		return nil
+	})
+	if err != nil {
+		return PrefixUsageReport{}, err
+	}
+
+	for _, u := range usage {
+		report.Prefixes = append(report.Prefixes, *u)
+	}
+	slices.SortFunc(report.Prefixes, func(a, b PrefixUsage) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), cmp.Compare(a.Prefix, b.Prefix))
+	})
+	return report, nil
+}
+
+// prefixOf returns the first depth "directories" of key, each with its
+// trailing slash, or fewer if key has fewer.
+func prefixOf(key string, depth int) string {
+	end := 0
+	for range depth {
+		i := strings.Index(key[end:], "/")
+		if i < 0 {
+			break
+		}
+		end += i + 1
+	}
+	return key[:end]
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestStorageService_PrefixUsage(t *testing.T) {
+	mock := &mockStorage{objects: storage.ObjectList{Objects: []storage.Object{
+		{Key: "raw/2024/a.json", Size: 10},
+		{Key: "raw/2024/b.json", Size: 20},
+		{Key: "raw/2023/a.json", Size: 5},
+		{Key: "raw/readme.txt", Size: 1},
+		{Key: "curated/orders.csv", Size: 100},
+		{Key: "top.txt", Size: 2},
+	}}}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	report, err := svc.PrefixUsage(context.Background(), "lake", "gcp", "", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []PrefixUsage{
+		{Prefix: "curated/", Objects: 1, Bytes: 100},
+		{Prefix: "raw/2024/", Objects: 2, Bytes: 30},
+		{Prefix: "raw/2023/", Objects: 1, Bytes: 5},
+		{Prefix: "", Objects: 1, Bytes: 2},
+		{Prefix: "raw/", Objects: 1, Bytes: 1},
+	}
+	if len(report.Prefixes) != len(want) {
+		t.Fatalf("got %+v, want %+v", report.Prefixes, want)
+	}
+	for i := range want {
+		if report.Prefixes[i] != want[i] {
+			t.Errorf("prefix %d = %+v, want %+v", i, report.Prefixes[i], want[i])
+		}
+	}
+	if report.Objects != 6 || report.Bytes != 138 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+
+	if _, err := svc.PrefixUsage(context.Background(), "lake", "gcp", "", 0); err == nil {
+		t.Error("expected an error for a depth of 0")
+	}
+}
+
+func TestPrefixOf(t *testing.T) {
+	tests := []struct {
+		key   string
+		depth int
+		want  string
+	}{
+		{"a/b/c.txt", 1, "a/"},
+		{"a/b/c.txt", 2, "a/b/"},
+		{"a/b/c.txt", 5, "a/b/"},
+		{"c.txt", 1, ""},
+		{"a/", 1, "a/"},
+	}
+	for _, tt := range tests {
+		if got := prefixOf(tt.key, tt.depth); got != tt.want {
+			t.Errorf("prefixOf(%q, %d) = %q, want %q", tt.key, tt.depth, got, tt.want)
+		}
+	}
+}
@@ -4,8 +4,8 @@ package service
 import (
 	"context"
 
-	"synkronus/internal/domain/sql"
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 // StorageProviderFactory is the interface the StorageService depends on to
@@ -0,0 +1,37 @@
+// File: internal/service/response_cache.go
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/jkleinne/synkronus/internal/cache"
+)
+
+// cachedResult returns the response cached under key if it is still fresh,
+// otherwise it calls fetch and caches the result. Errors are never cached. A
+// nil cache (the response cache is disabled) always calls fetch.
+func cachedResult[R any](ctx context.Context, c *cache.Cache, logger *slog.Logger, key string, fetch func() (R, error)) (R, error) {
+	var cached R
+	if c.Get(ctx, key, &cached) {
+		logger.Debug("Serving cached response", "key", key)
+		return cached, nil
+	}
+
+	result, err := fetch()
+	if err != nil {
+		return result, err
+	}
+	if err := c.Put(key, result); err != nil {
+		logger.Debug("Failed to cache response", "key", key, "error", err)
+	}
+	return result, nil
+}
+
+// responseKey builds a cache key from an operation, the provider it ran
+// against, and its arguments. Provider names are case-insensitive; bucket
+// names and object keys are not.
+func responseKey(op, providerName string, args ...string) string {
+	return strings.Join(append([]string{op, strings.ToLower(providerName)}, args...), "\x1f")
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// newCachedStorageService returns a StorageService over mock with a response
+// cache in a temporary directory.
+func newCachedStorageService(t *testing.T, mock *mockStorage) *StorageService {
+	t.Helper()
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+	svc.UseResponseCache(cache.New(t.TempDir(), time.Minute))
+	return svc
+}
+
+func TestStorageService_ResponseCache_ServesDescribeFromCache(t *testing.T) {
+	mock := &mockStorage{bucket: storage.Bucket{Name: "b", Location: "us-east1"}}
+	svc := newCachedStorageService(t, mock)
+
+	if _, err := svc.DescribeBucket(context.Background(), "b", "gcp"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mock.bucket.Location = "europe-west1"
+
+	got, err := svc.DescribeBucket(context.Background(), "b", "GCP")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Location != "us-east1" {
+		t.Errorf("expected cached location us-east1, got %q", got.Location)
+	}
+
+	fresh, err := svc.DescribeBucket(cache.WithBypass(context.Background()), "b", "gcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fresh.Location != "europe-west1" {
+		t.Errorf("expected bypass to fetch europe-west1, got %q", fresh.Location)
+	}
+}
+
+func TestStorageService_ResponseCache_ErrorsAreNotCached(t *testing.T) {
+	mock := &mockStorage{err: errors.New("throttled")}
+	svc := newCachedStorageService(t, mock)
+
+	if _, err := svc.ListObjects(context.Background(), "b", "gcp", ""); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	mock.err = nil
+	mock.objects = storage.ObjectList{BucketName: "b", Objects: []storage.Object{{Key: "k"}}}
+
+	got, err := svc.ListObjects(context.Background(), "b", "gcp", "")
+	if err != nil {
+		t.Fatalf("expected the failed call not to be cached, got: %v", err)
+	}
+	if len(got.Objects) != 1 {
+		t.Errorf("expected 1 object, got %d", len(got.Objects))
+	}
+}
+
+func TestStorageService_ResponseCache_ChangesInvalidate(t *testing.T) {
+	mock := &mockStorage{
+		providerName: domain.GCP,
+		buckets:      []storage.Bucket{{Name: "old"}},
+	}
+	svc := newCachedStorageService(t, mock)
+
+	if _, err := svc.ListAllBuckets(context.Background(), []string{"gcp"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mock.buckets = nil
+	if err := svc.DeleteBucket(context.Background(), "old", "gcp"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := svc.ListAllBuckets(context.Background(), []string{"gcp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected the delete to invalidate the cached listing, got %v", got)
+	}
+}
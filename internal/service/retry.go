@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jkleinne/synkronus/internal/retry"
+)
+
+// retryClassifier is implemented by provider clients that can tell transient
+// errors (throttling, server errors) from permanent ones.
+type retryClassifier interface {
+	IsRetryable(err error) bool
+}
+
+// sdkRetrier is implemented by provider clients whose SDK retries transient
+// errors on every call, up to retry.max_retries times.
+type sdkRetrier interface {
+	RetriesInSDK() bool
+}
+
+// withRetry calls fn, retrying it under policy while it fails with an error
+// client classifies as transient. Clients without a classifier are retried on
+// network errors only. Only reads are retried: a retried change could apply
+// twice, and an upload's reader cannot be replayed. Clients that retry in
+// their SDK are called once, so the attempts don't multiply.
+func withRetry[R any](ctx context.Context, policy retry.Policy, client any, logger *slog.Logger, fn func() (R, error)) (R, error) {
+	if r, ok := client.(sdkRetrier); ok && r.RetriesInSDK() {
+		return fn()
+	}
+	classify := retry.IsTransientNetworkError
+	if c, ok := client.(retryClassifier); ok {
+		classify = c.IsRetryable
+	}
+	return retry.Do(ctx, policy, classify, logger, fn)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/retry"
+)
+
+var errThrottled = errors.New("throttled")
+
+// flakyStorage fails DescribeBucket and DeleteBucket with err until failures
+// calls have been made, and classifies errThrottled as retryable.
+type flakyStorage struct {
+	mockStorage
+	err      error
+	failures int
+	calls    int
+}
+
+func (f *flakyStorage) DescribeBucket(ctx context.Context, name string) (storage.Bucket, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return storage.Bucket{}, f.err
+	}
+	return f.mockStorage.DescribeBucket(ctx, name)
+}
+
+func (f *flakyStorage) DeleteBucket(context.Context, string) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyStorage) IsRetryable(err error) bool {
+	return errors.Is(err, errThrottled)
+}
+
+func newRetryingStorageService(flaky *flakyStorage, maxRetries int) *StorageService {
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": flaky}})
+	svc.UseRetryPolicy(retry.Policy{MaxRetries: maxRetries, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	return svc
+}
+
+func TestStorageService_Retry(t *testing.T) {
+	permanent := errors.New("access denied")
+	tests := []struct {
+		name       string
+		err        error
+		failures   int
+		maxRetries int
+		wantErr    bool
+		wantCalls  int
+	}{
+		{"retries retryable errors", errThrottled, 2, 3, false, 3},
+		{"gives up after max retries", errThrottled, 5, 2, true, 3},
+		{"does not retry errors the provider classifies as permanent", permanent, 1, 3, true, 1},
+		{"retries disabled", errThrottled, 1, 0, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyStorage{mockStorage: mockStorage{bucket: storage.Bucket{Name: "b"}}, err: tt.err, failures: tt.failures}
+			svc := newRetryingStorageService(flaky, tt.maxRetries)
+
+			_, err := svc.DescribeBucket(context.Background(), "b", "gcp")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("error chain should wrap provider error; got: %v", err)
+			}
+			if flaky.calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", flaky.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestStorageService_Retry_ChangesAreNotRetried(t *testing.T) {
+	flaky := &flakyStorage{err: errThrottled, failures: 1}
+	svc := newRetryingStorageService(flaky, 3)
+
+	if err := svc.DeleteBucket(context.Background(), "b", "gcp"); !errors.Is(err, errThrottled) {
+		t.Errorf("expected the throttled error, got: %v", err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("got %d calls, want 1", flaky.calls)
+	}
+}
+
+// sdkRetryingStorage is a flakyStorage whose SDK retries by itself.
+type sdkRetryingStorage struct {
+	*flakyStorage
+}
+
+func (sdkRetryingStorage) RetriesInSDK() bool { return true }
+
+func TestStorageService_Retry_LeavesSDKRetriesAlone(t *testing.T) {
+	flaky := &flakyStorage{mockStorage: mockStorage{bucket: storage.Bucket{Name: "b"}}, err: errThrottled, failures: 1}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": sdkRetryingStorage{flaky}}})
+	svc.UseRetryPolicy(retry.Policy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	if _, err := svc.DescribeBucket(context.Background(), "b", "gcp"); !errors.Is(err, errThrottled) {
+		t.Errorf("expected the throttled error, got: %v", err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("got %d calls, want 1, as the SDK already retried", flaky.calls)
+	}
+}
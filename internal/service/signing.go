@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SignPostPolicy signs a POST policy for browser uploads to a bucket (see
+// storage.PostPolicySigner).
+func (s *StorageService) SignPostPolicy(ctx context.Context, opts storage.PostPolicyOptions, providerName string) (storage.PostPolicy, error) {
+	ctx, done := observe(ctx, "StorageService.SignPostPolicy", providerName, attribute.String("bucket", opts.BucketName))
+	s.logger.Debug("Starting SignPostPolicy operation", "bucket", opts.BucketName, "prefix", opts.Prefix, "provider", providerName)
+	policy, err := withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.PostPolicy, error) {
+		signer, ok := client.(storage.PostPolicySigner)
+		if !ok {
+			return storage.PostPolicy{}, fmt.Errorf("signing POST policies on %s: %w", providerName, ErrUnsupported)
+		}
+		policy, err := signer.SignPostPolicy(ctx, opts)
+		if err != nil {
+			return storage.PostPolicy{}, fmt.Errorf("signing POST policy for bucket %q on %s: %w", opts.BucketName, providerName, err)
+		}
+		return policy, nil
+	})
+	done(err)
+	return policy, err
+}
+
+// SignURL signs a URL for one kind of request on an object (see
+// storage.URLSigner).
+func (s *StorageService) SignURL(ctx context.Context, opts storage.SignedURLOptions, providerName string) (storage.SignedURL, error) {
+	ctx, done := observe(ctx, "StorageService.SignURL", providerName, attribute.String("bucket", opts.BucketName), attribute.String("object", opts.ObjectKey))
+	s.logger.Debug("Starting SignURL operation", "bucket", opts.BucketName, "key", opts.ObjectKey, "method", opts.Method, "provider", providerName)
+	signed, err := withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.SignedURL, error) {
+		signer, ok := client.(storage.URLSigner)
+		if !ok {
+			return storage.SignedURL{}, fmt.Errorf("signing URLs on %s: %w", providerName, ErrUnsupported)
+		}
+		signed, err := signer.SignURL(ctx, opts)
+		if err != nil {
+			return storage.SignedURL{}, fmt.Errorf("signing URL for object %q in bucket %q on %s: %w", opts.ObjectKey, opts.BucketName, providerName, err)
+		}
+		return signed, nil
+	})
+	done(err)
+	return signed, err
+}
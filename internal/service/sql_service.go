@@ -6,56 +6,108 @@ import (
 	"fmt"
 	"log/slog"
 
-	"synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/retry"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type SqlService struct {
-	providerFactory SqlProviderFactory
-	logger          *slog.Logger
+	clients     *clientCache[sql.SQL]
+	responses   *cache.Cache
+	retryPolicy retry.Policy
+	fanOut      FanOutLimits
+	logger      *slog.Logger
 }
 
 func NewSqlService(providerFactory SqlProviderFactory, logger *slog.Logger) *SqlService {
+	logger = logger.With("service", "SqlService")
 	return &SqlService{
-		providerFactory: providerFactory,
-		logger:          logger.With("service", "SqlService"),
+		clients:     newClientCache(providerFactory.GetSqlProvider, logger),
+		retryPolicy: retry.DefaultPolicy(),
+		logger:      logger,
 	}
 }
 
+// Shutdown closes the provider clients created by the service. Like
+// StorageService.Shutdown, later calls create new clients.
+func (s *SqlService) Shutdown() error {
+	return s.clients.shutdown()
+}
+
+// UseResponseCache makes list and describe calls serve their results from c
+// while they are fresh.
+func (s *SqlService) UseResponseCache(c *cache.Cache) {
+	s.responses = c
+}
+
+// UseRetryPolicy sets how calls are retried after transient provider errors.
+func (s *SqlService) UseRetryPolicy(p retry.Policy) {
+	s.retryPolicy = p
+}
+
+// UseFanOutLimits bounds ListAllInstances.
+func (s *SqlService) UseFanOutLimits(l FanOutLimits) {
+	s.fanOut = l
+}
+
 // ListAllInstances queries all specified providers for SQL instances concurrently
 func (s *SqlService) ListAllInstances(ctx context.Context, providerNames []string) ([]sql.Instance, error) {
 	if len(providerNames) == 0 {
 		return nil, nil
 	}
 
+	ctx, span := telemetry.Start(ctx, "SqlService.ListAllInstances")
 	s.logger.Debug("Starting ListAllInstances operation", "providers", providerNames)
 
-	return concurrentFanOut(
+	instances, err := concurrentFanOut(
 		ctx,
 		providerNames,
-		s.providerFactory.GetSqlProvider,
+		s.fanOut,
+		s.clients.acquire,
 		func(ctx context.Context, client sql.SQL) ([]sql.Instance, error) {
-			return client.ListInstances(ctx)
+			ctx, done := observe(ctx, "SqlService.ListInstances", string(client.ProviderName()))
+			key := responseKey("list-instances", string(client.ProviderName()))
+			instances, err := cachedResult(ctx, s.responses, s.logger, key, func() ([]sql.Instance, error) {
+				return withRetry(ctx, s.retryPolicy, client, s.logger, func() ([]sql.Instance, error) {
+					return client.ListInstances(ctx)
+				})
+			})
+			done(err)
+			return instances, err
 		},
 		s.logger,
 	)
+	telemetry.End(span, err)
+	return instances, err
 }
 
 // DescribeInstance returns detailed information about a specific SQL instance from a single provider
 func (s *SqlService) DescribeInstance(ctx context.Context, instanceName, providerName string) (sql.Instance, error) {
+	ctx, done := observe(ctx, "SqlService.DescribeInstance", providerName, attribute.String("instance", instanceName))
 	s.logger.Debug("Starting DescribeInstance operation", "instance", instanceName, "provider", providerName)
-	return withClientResult(ctx, s.getSqlClient, providerName, func(client sql.SQL) (sql.Instance, error) {
-		instance, err := client.DescribeInstance(ctx, instanceName)
-		if err != nil {
-			return sql.Instance{}, fmt.Errorf("describing SQL instance %q on %s: %w", instanceName, providerName, err)
-		}
-		return instance, nil
+	key := responseKey("describe-instance", providerName, instanceName)
+	instance, err := cachedResult(ctx, s.responses, s.logger, key, func() (sql.Instance, error) {
+		return withClientResult(ctx, s.getSqlClient, providerName, func(client sql.SQL) (sql.Instance, error) {
+			instance, err := withRetry(ctx, s.retryPolicy, client, s.logger, func() (sql.Instance, error) {
+				return client.DescribeInstance(ctx, instanceName)
+			})
+			if err != nil {
+				return sql.Instance{}, fmt.Errorf("describing SQL instance %q on %s: %w", instanceName, providerName, err)
+			}
+			return instance, nil
+		})
 	})
+	done(err)
+	return instance, err
 }
 
-func (s *SqlService) getSqlClient(ctx context.Context, providerName string) (sql.SQL, error) {
-	client, err := s.providerFactory.GetSqlProvider(ctx, providerName)
+func (s *SqlService) getSqlClient(ctx context.Context, providerName string) (sql.SQL, func(), error) {
+	client, release, err := s.clients.acquire(ctx, providerName)
 	if err != nil {
-		return nil, fmt.Errorf("initializing SQL provider %s: %w", providerName, err)
+		return nil, nil, fmt.Errorf("initializing SQL provider %s: %w", providerName, err)
 	}
-	return client, nil
+	return client, release, nil
 }
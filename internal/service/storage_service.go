@@ -3,181 +3,407 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"sync"
 
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/audit"
+	"github.com/jkleinne/synkronus/internal/cache"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/metrics"
+	"github.com/jkleinne/synkronus/internal/retry"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type StorageService struct {
-	providerFactory StorageProviderFactory
-	logger          *slog.Logger
+	clients     *clientCache[storage.Storage]
+	responses   *cache.Cache
+	auditLog    *audit.Log
+	retryPolicy retry.Policy
+	fanOut      FanOutLimits
+	logger      *slog.Logger
 }
 
 func NewStorageService(providerFactory StorageProviderFactory, logger *slog.Logger) *StorageService {
+	logger = logger.With("service", "StorageService")
 	return &StorageService{
-		providerFactory: providerFactory,
-		logger:          logger.With("service", "StorageService"),
+		clients:     newClientCache(providerFactory.GetStorageProvider, logger),
+		retryPolicy: retry.DefaultPolicy(),
+		logger:      logger,
+	}
+}
+
+// Shutdown closes the provider clients created by the service. The service
+// remains usable; later calls create new clients from the factory's current
+// config, which makes Shutdown the way to pick up a config change. Clients
+// still used by calls in flight are closed when those calls are done.
+func (s *StorageService) Shutdown() error {
+	return s.clients.shutdown()
+}
+
+// UseResponseCache makes list and describe calls serve their results from c
+// while they are fresh. Successful changes (create, delete, upload, copy)
+// invalidate c, so a listing after a change reflects it.
+func (s *StorageService) UseResponseCache(c *cache.Cache) {
+	s.responses = c
+}
+
+// UseRetryPolicy sets how list, describe and download calls are retried after
+// transient provider errors. Changes are never retried.
+func (s *StorageService) UseRetryPolicy(p retry.Policy) {
+	s.retryPolicy = p
+}
+
+// UseFanOutLimits bounds calls that query several providers at once, such as
+// ListAllBuckets.
+func (s *StorageService) UseFanOutLimits(l FanOutLimits) {
+	s.fanOut = l
+}
+
+// UseAuditLog records the outcome of every change (create, delete, upload,
+// copy) in l, whether it succeeded or failed.
+func (s *StorageService) UseAuditLog(l *audit.Log) {
+	s.auditLog = l
+}
+
+// recordChange adds a change to the audit log. The change has already been
+// made, so failing to record it is logged rather than returned.
+func (s *StorageService) recordChange(operation, providerName, resource string, err error) {
+	if recordErr := s.auditLog.Record(operation, providerName, resource, err); recordErr != nil {
+		s.logger.Warn("Failed to record change in audit log", "operation", operation, "resource", resource, "error", recordErr)
 	}
 }
 
-// withClient acquires a storage provider client, calls fn, and ensures the
-// client is closed afterward. Used by service methods that return only an error.
+// invalidateResponses drops cached responses after a change. Failing to do
+// so only leaves stale entries until they expire, so the error is logged.
+func (s *StorageService) invalidateResponses() {
+	if err := s.responses.Invalidate(); err != nil {
+		s.logger.Warn("Failed to invalidate response cache", "error", err)
+	}
+}
+
+// withClient acquires a storage provider client and calls fn. Used by service
+// methods that return only an error.
 func (s *StorageService) withClient(ctx context.Context, providerName string, fn func(client storage.Storage) error) error {
-	client, err := s.getStorageClient(ctx, providerName)
+	client, release, err := s.getStorageClient(ctx, providerName)
 	if err != nil {
 		return err
 	}
-	defer client.Close()
-	return fn(client)
+	defer release()
+	return classifyError(client, fn(client))
 }
 
 // --- Bucket Operations ---
 
 func (s *StorageService) ListAllBuckets(ctx context.Context, providerNames []string) ([]storage.Bucket, error) {
+	return s.ListAllBucketsFunc(ctx, providerNames, nil)
+}
+
+// ListAllBucketsFunc is ListAllBuckets, additionally passing each provider's
+// buckets to onProvider as soon as that provider answers, so callers can show
+// results before the slowest provider is done. Calls to onProvider are
+// serialized; it is not called for providers that fail without any buckets.
+func (s *StorageService) ListAllBucketsFunc(ctx context.Context, providerNames []string, onProvider func(providerName string, buckets []storage.Bucket)) ([]storage.Bucket, error) {
 	if len(providerNames) == 0 {
 		return nil, nil
 	}
 
+	ctx, span := telemetry.Start(ctx, "StorageService.ListAllBuckets")
 	s.logger.Debug("Starting ListAllBuckets operation", "providers", providerNames)
 
-	return concurrentFanOut(
+	var mu sync.Mutex
+	buckets, err := concurrentFanOut(
 		ctx,
 		providerNames,
-		s.providerFactory.GetStorageProvider,
+		s.fanOut,
+		s.clients.acquire,
 		func(ctx context.Context, client storage.Storage) ([]storage.Bucket, error) {
-			return client.ListBuckets(ctx)
+			ctx, done := observe(ctx, "StorageService.ListBuckets", string(client.ProviderName()))
+			key := responseKey("list-buckets", string(client.ProviderName()))
+			buckets, err := cachedResult(ctx, s.responses, s.logger, key, func() ([]storage.Bucket, error) {
+				return withRetry(ctx, s.retryPolicy, client, s.logger, func() ([]storage.Bucket, error) {
+					return client.ListBuckets(ctx)
+				})
+			})
+			done(err)
+			if (err == nil || len(buckets) > 0) && onProvider != nil {
+				mu.Lock()
+				onProvider(string(client.ProviderName()), buckets)
+				mu.Unlock()
+			}
+			return buckets, err
 		},
 		s.logger,
 	)
+	telemetry.End(span, err)
+	return buckets, err
 }
 
 func (s *StorageService) DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error) {
+	ctx, done := observe(ctx, "StorageService.DescribeBucket", providerName, attribute.String("bucket", bucketName))
 	s.logger.Debug("Starting DescribeBucket operation", "bucket", bucketName, "provider", providerName)
-	return withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.Bucket, error) {
-		bucket, err := client.DescribeBucket(ctx, bucketName)
-		if err != nil {
-			return storage.Bucket{}, fmt.Errorf("describing bucket %q on %s: %w", bucketName, providerName, err)
-		}
-		return bucket, nil
+	key := responseKey("describe-bucket", providerName, bucketName)
+	bucket, err := cachedResult(ctx, s.responses, s.logger, key, func() (storage.Bucket, error) {
+		return withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.Bucket, error) {
+			bucket, err := withRetry(ctx, s.retryPolicy, client, s.logger, func() (storage.Bucket, error) {
+				return client.DescribeBucket(ctx, bucketName)
+			})
+			if err != nil {
+				return storage.Bucket{}, fmt.Errorf("describing bucket %q on %s: %w", bucketName, providerName, err)
+			}
+			return bucket, nil
+		})
 	})
+	done(err)
+	return bucket, err
 }
 
 func (s *StorageService) CreateBucket(ctx context.Context, opts storage.CreateBucketOptions, providerName string) (storage.CreateBucketResult, error) {
+	ctx, done := observe(ctx, "StorageService.CreateBucket", providerName, attribute.String("bucket", opts.Name))
 	s.logger.Debug("Starting CreateBucket operation", "bucket", opts.Name, "provider", providerName, "location", opts.Location)
-	return withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.CreateBucketResult, error) {
+	result, err := withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.CreateBucketResult, error) {
 		result, err := client.CreateBucket(ctx, opts)
 		if err != nil {
 			return storage.CreateBucketResult{}, fmt.Errorf("creating bucket %q on %s: %w", opts.Name, providerName, err)
 		}
+		s.invalidateResponses()
 		return result, nil
 	})
+	s.recordChange("create-bucket", providerName, opts.Name, err)
+	done(err)
+	return result, err
 }
 
 func (s *StorageService) DeleteBucket(ctx context.Context, bucketName, providerName string) error {
+	ctx, done := observe(ctx, "StorageService.DeleteBucket", providerName, attribute.String("bucket", bucketName))
 	s.logger.Debug("Starting DeleteBucket operation", "bucket", bucketName, "provider", providerName)
-	return s.withClient(ctx, providerName, func(client storage.Storage) error {
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
 		if err := client.DeleteBucket(ctx, bucketName); err != nil {
 			return fmt.Errorf("deleting bucket %q on %s: %w", bucketName, providerName, err)
 		}
+		s.invalidateResponses()
 		return nil
 	})
+	s.recordChange("delete-bucket", providerName, bucketName, err)
+	done(err)
+	return err
 }
 
 // --- Object Operations ---
 
 func (s *StorageService) ListObjects(ctx context.Context, bucketName, providerName, prefix string) (storage.ObjectList, error) {
+	ctx, done := observe(ctx, "StorageService.ListObjects", providerName, attribute.String("bucket", bucketName))
 	s.logger.Debug("Starting ListObjects operation", "bucket", bucketName, "provider", providerName, "prefix", prefix)
-	return withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.ObjectList, error) {
-		objects, err := client.ListObjects(ctx, bucketName, prefix)
-		if err != nil {
-			return storage.ObjectList{}, fmt.Errorf("listing objects in bucket %q on %s: %w", bucketName, providerName, err)
+	key := responseKey("list-objects", providerName, bucketName, prefix)
+	objects, err := cachedResult(ctx, s.responses, s.logger, key, func() (storage.ObjectList, error) {
+		return withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.ObjectList, error) {
+			objects, err := withRetry(ctx, s.retryPolicy, client, s.logger, func() (storage.ObjectList, error) {
+				return client.ListObjects(ctx, bucketName, prefix)
+			})
+			if err != nil {
+				return storage.ObjectList{}, fmt.Errorf("listing objects in bucket %q on %s: %w", bucketName, providerName, err)
+			}
+			return objects, nil
+		})
+	})
+	done(err)
+	return objects, err
+}
+
+// WalkObjects passes every object under prefix to fn as pages arrive from the
+// provider. Walks can cover millions of objects, so they bypass the response
+// cache and never hold more than a page in memory.
+func (s *StorageService) WalkObjects(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.Object) error) error {
+	ctx, done := observe(ctx, "StorageService.WalkObjects", providerName, attribute.String("bucket", bucketName))
+	s.logger.Debug("Starting WalkObjects operation", "bucket", bucketName, "provider", providerName, "prefix", prefix)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
+		if err := client.WalkObjects(ctx, bucketName, prefix, fn); err != nil {
+			return fmt.Errorf("listing objects in bucket %q on %s: %w", bucketName, providerName, err)
 		}
-		return objects, nil
+		return nil
 	})
+	done(err)
+	return err
 }
 
 func (s *StorageService) DescribeObject(ctx context.Context, bucketName, objectKey, providerName string) (storage.Object, error) {
+	ctx, done := observe(ctx, "StorageService.DescribeObject", providerName, attribute.String("bucket", bucketName), attribute.String("object", objectKey))
 	s.logger.Debug("Starting DescribeObject operation", "bucket", bucketName, "object", objectKey, "provider", providerName)
-	return withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.Object, error) {
-		object, err := client.DescribeObject(ctx, bucketName, objectKey)
-		if err != nil {
-			return storage.Object{}, fmt.Errorf("describing object %q in bucket %q on %s: %w", objectKey, bucketName, providerName, err)
-		}
-		return object, nil
+	key := responseKey("describe-object", providerName, bucketName, objectKey)
+	object, err := cachedResult(ctx, s.responses, s.logger, key, func() (storage.Object, error) {
+		return withClientResult(ctx, s.getStorageClient, providerName, func(client storage.Storage) (storage.Object, error) {
+			object, err := withRetry(ctx, s.retryPolicy, client, s.logger, func() (storage.Object, error) {
+				return client.DescribeObject(ctx, bucketName, objectKey)
+			})
+			if err != nil {
+				return storage.Object{}, fmt.Errorf("describing object %q in bucket %q on %s: %w", objectKey, bucketName, providerName, err)
+			}
+			return object, nil
+		})
 	})
+	done(err)
+	return object, err
 }
 
 func (s *StorageService) DownloadObject(ctx context.Context, bucketName, objectKey, providerName string) (io.ReadCloser, error) {
+	// The span covers opening the download; reading it is up to the caller
+	ctx, done := observe(ctx, "StorageService.DownloadObject", providerName, attribute.String("bucket", bucketName), attribute.String("object", objectKey))
 	s.logger.Debug("Starting DownloadObject operation", "bucket", bucketName, "object", objectKey, "provider", providerName)
 
-	client, err := s.getStorageClient(ctx, providerName)
+	// The client stays in use until the caller closes the download
+	client, release, err := s.getStorageClient(ctx, providerName)
 	if err != nil {
+		done(err)
 		return nil, err
 	}
-
-	reader, err := client.DownloadObject(ctx, bucketName, objectKey)
+	reader, err := withRetry(ctx, s.retryPolicy, client, s.logger, func() (io.ReadCloser, error) {
+		return client.DownloadObject(ctx, bucketName, objectKey)
+	})
 	if err != nil {
-		client.Close()
-		return nil, fmt.Errorf("downloading object %q from bucket %q on %s: %w", objectKey, bucketName, providerName, err)
+		release()
+		err = classifyError(client, fmt.Errorf("downloading object %q from bucket %q on %s: %w", objectKey, bucketName, providerName, err))
+		done(err)
+		return nil, err
 	}
-
-	return &readerWithCleanup{ReadCloser: reader, cleanup: client.Close}, nil
+	done(nil)
+	return releaseOnClose{ReadCloser: metrics.CountReadCloser(reader, providerName, metrics.Download), release: release}, nil
 }
 
 func (s *StorageService) UploadObject(ctx context.Context, opts storage.UploadObjectOptions, providerName string, reader io.Reader) error {
+	ctx, done := observe(ctx, "StorageService.UploadObject", providerName, attribute.String("bucket", opts.BucketName), attribute.String("object", opts.ObjectKey))
 	s.logger.Debug("Starting UploadObject operation",
 		"bucket", opts.BucketName, "key", opts.ObjectKey, "provider", providerName)
-	return s.withClient(ctx, providerName, func(client storage.Storage) error {
+	size := readerSize(reader)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
 		if err := client.UploadObject(ctx, opts, reader); err != nil {
 			return fmt.Errorf("uploading object %q to bucket %q on %s: %w", opts.ObjectKey, opts.BucketName, providerName, err)
 		}
+		metrics.AddBytes(providerName, metrics.Upload, size)
+		s.invalidateResponses()
 		return nil
 	})
+	s.recordChange("upload-object", providerName, opts.BucketName+"/"+opts.ObjectKey, err)
+	done(err)
+	return err
 }
 
 func (s *StorageService) DeleteObject(ctx context.Context, bucketName, objectKey, providerName string) error {
+	ctx, done := observe(ctx, "StorageService.DeleteObject", providerName, attribute.String("bucket", bucketName), attribute.String("object", objectKey))
 	s.logger.Debug("Starting DeleteObject operation",
 		"bucket", bucketName, "key", objectKey, "provider", providerName)
-	return s.withClient(ctx, providerName, func(client storage.Storage) error {
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
 		if err := client.DeleteObject(ctx, bucketName, objectKey); err != nil {
 			return fmt.Errorf("deleting object %q from bucket %q on %s: %w", objectKey, bucketName, providerName, err)
 		}
+		s.invalidateResponses()
 		return nil
 	})
+	s.recordChange("delete-object", providerName, bucketName+"/"+objectKey, err)
+	done(err)
+	return err
 }
 
 func (s *StorageService) CopyObject(ctx context.Context, srcBucket, srcKey, destBucket, destKey, providerName string) error {
+	ctx, done := observe(ctx, "StorageService.CopyObject", providerName, attribute.String("bucket", srcBucket), attribute.String("object", srcKey))
 	s.logger.Debug("Starting CopyObject operation",
 		"srcBucket", srcBucket, "srcKey", srcKey,
 		"destBucket", destBucket, "destKey", destKey, "provider", providerName)
-	return s.withClient(ctx, providerName, func(client storage.Storage) error {
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
 		if err := client.CopyObject(ctx, srcBucket, srcKey, destBucket, destKey); err != nil {
 			return fmt.Errorf("copying object %q/%q to %q/%q on %s: %w", srcBucket, srcKey, destBucket, destKey, providerName, err)
 		}
+		s.invalidateResponses()
+		return nil
+	})
+	s.recordChange("copy-object", providerName, srcBucket+"/"+srcKey+" -> "+destBucket+"/"+destKey, err)
+	done(err)
+	return err
+}
+
+// UpdateObjectMetadata sets the given keys of an object's user-defined
+// metadata (see storage.MetadataUpdater).
+func (s *StorageService) UpdateObjectMetadata(ctx context.Context, bucketName, objectKey, providerName string, metadata map[string]string) error {
+	ctx, done := observe(ctx, "StorageService.UpdateObjectMetadata", providerName, attribute.String("bucket", bucketName), attribute.String("object", objectKey))
+	s.logger.Debug("Starting UpdateObjectMetadata operation",
+		"bucket", bucketName, "key", objectKey, "provider", providerName)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
+		updater, ok := client.(storage.MetadataUpdater)
+		if !ok {
+			return fmt.Errorf("updating object metadata on %s: %w", providerName, ErrUnsupported)
+		}
+		if err := updater.UpdateObjectMetadata(ctx, bucketName, objectKey, metadata); err != nil {
+			return fmt.Errorf("updating metadata of object %q in bucket %q on %s: %w", objectKey, bucketName, providerName, err)
+		}
+		s.invalidateResponses()
+		return nil
+	})
+	s.recordChange("update-object-metadata", providerName, bucketName+"/"+objectKey, err)
+	done(err)
+	return err
+}
+
+// UpdateBucketLabels sets the given labels (tags on S3) of a bucket, leaving
+// its other labels as they are (see storage.BucketLabeler).
+func (s *StorageService) UpdateBucketLabels(ctx context.Context, bucketName, providerName string, labels map[string]string) error {
+	ctx, done := observe(ctx, "StorageService.UpdateBucketLabels", providerName, attribute.String("bucket", bucketName))
+	s.logger.Debug("Starting UpdateBucketLabels operation", "bucket", bucketName, "provider", providerName)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
+		labeler, ok := client.(storage.BucketLabeler)
+		if !ok {
+			return fmt.Errorf("updating bucket labels on %s: %w", providerName, ErrUnsupported)
+		}
+		if err := labeler.UpdateBucketLabels(ctx, bucketName, labels); err != nil {
+			return fmt.Errorf("updating labels of bucket %q on %s: %w", bucketName, providerName, err)
+		}
+		s.invalidateResponses()
 		return nil
 	})
+	s.recordChange("update-bucket-labels", providerName, bucketName, err)
+	done(err)
+	return err
 }
 
-// readerWithCleanup wraps an io.ReadCloser to run a cleanup function (e.g., client.Close)
-// when the reader is closed. This ensures the provider client outlives the reader.
-type readerWithCleanup struct {
-	io.ReadCloser
-	cleanup func() error
+// SetObjectAttributes replaces an object's content headers and user-defined
+// metadata (see storage.AttributesSetter).
+func (s *StorageService) SetObjectAttributes(ctx context.Context, bucketName, objectKey, providerName string, attrs storage.ObjectAttributes) error {
+	ctx, done := observe(ctx, "StorageService.SetObjectAttributes", providerName, attribute.String("bucket", bucketName), attribute.String("object", objectKey))
+	s.logger.Debug("Starting SetObjectAttributes operation",
+		"bucket", bucketName, "key", objectKey, "provider", providerName)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
+		setter, ok := client.(storage.AttributesSetter)
+		if !ok {
+			return fmt.Errorf("setting object attributes on %s: %w", providerName, ErrUnsupported)
+		}
+		if err := setter.SetObjectAttributes(ctx, bucketName, objectKey, attrs); err != nil {
+			return fmt.Errorf("setting attributes of object %q in bucket %q on %s: %w", objectKey, bucketName, providerName, err)
+		}
+		s.invalidateResponses()
+		return nil
+	})
+	s.recordChange("set-object-attributes", providerName, bucketName+"/"+objectKey, err)
+	done(err)
+	return err
 }
 
-func (r *readerWithCleanup) Close() error {
-	readErr := r.ReadCloser.Close()
-	cleanupErr := r.cleanup()
-	return errors.Join(readErr, cleanupErr)
+// CheckProvider initializes the client for providerName without calling it, so
+// a dry run fails the same way the change would for an unknown or unconfigured
+// provider.
+func (s *StorageService) CheckProvider(ctx context.Context, providerName string) error {
+	_, release, err := s.getStorageClient(ctx, providerName)
+	if err != nil {
+		return err
+	}
+	release()
+	return nil
 }
 
-func (s *StorageService) getStorageClient(ctx context.Context, providerName string) (storage.Storage, error) {
-	client, err := s.providerFactory.GetStorageProvider(ctx, providerName)
+func (s *StorageService) getStorageClient(ctx context.Context, providerName string) (storage.Storage, func(), error) {
+	client, release, err := s.clients.acquire(ctx, providerName)
 	if err != nil {
-		return nil, fmt.Errorf("initializing storage provider %s: %w", providerName, err)
+		return nil, nil, fmt.Errorf("initializing storage provider %s: %w", providerName, err)
 	}
-	return client, nil
+	return client, release, nil
 }
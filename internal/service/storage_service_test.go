@@ -7,75 +7,14 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"strings"
 	"testing"
 
-	"synkronus/internal/domain"
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
-// --- readerWithCleanup tests ---
-
-func TestReaderWithCleanup_CloseBothSucceed(t *testing.T) {
-	inner := io.NopCloser(strings.NewReader("data"))
-	r := &readerWithCleanup{ReadCloser: inner, cleanup: func() error { return nil }}
-
-	if err := r.Close(); err != nil {
-		t.Errorf("expected nil error, got: %v", err)
-	}
-}
-
-func TestReaderWithCleanup_ReaderCloseError(t *testing.T) {
-	r := &readerWithCleanup{
-		ReadCloser: &errorCloser{readErr: errors.New("reader fail")},
-		cleanup:    func() error { return nil },
-	}
-
-	err := r.Close()
-	if err == nil || !strings.Contains(err.Error(), "reader fail") {
-		t.Errorf("expected 'reader fail' error, got: %v", err)
-	}
-}
-
-func TestReaderWithCleanup_CleanupError(t *testing.T) {
-	inner := io.NopCloser(strings.NewReader("data"))
-	r := &readerWithCleanup{
-		ReadCloser: inner,
-		cleanup:    func() error { return errors.New("cleanup fail") },
-	}
-
-	err := r.Close()
-	if err == nil || !strings.Contains(err.Error(), "cleanup fail") {
-		t.Errorf("expected 'cleanup fail' error, got: %v", err)
-	}
-}
-
-func TestReaderWithCleanup_BothErrors(t *testing.T) {
-	r := &readerWithCleanup{
-		ReadCloser: &errorCloser{readErr: errors.New("reader fail")},
-		cleanup:    func() error { return errors.New("cleanup fail") },
-	}
-
-	err := r.Close()
-	if err == nil {
-		t.Fatal("expected combined error, got nil")
-	}
-	if !strings.Contains(err.Error(), "reader fail") {
-		t.Errorf("error should contain 'reader fail', got: %v", err)
-	}
-	if !strings.Contains(err.Error(), "cleanup fail") {
-		t.Errorf("error should contain 'cleanup fail', got: %v", err)
-	}
-}
-
-// errorCloser is an io.ReadCloser whose Close always returns an error.
-type errorCloser struct {
-	readErr error
-}
-
-func (e *errorCloser) Read(p []byte) (int, error) { return 0, io.EOF }
-func (e *errorCloser) Close() error               { return e.readErr }
-
 // --- Mock types ---
 
 // mockStorageFactory satisfies StorageProviderFactory. If err is set it is
@@ -131,6 +70,18 @@ func (m *mockStorage) ListObjects(_ context.Context, _ string, _ string) (storag
 	return m.objects, m.err
 }
 
+func (m *mockStorage) WalkObjects(_ context.Context, _ string, _ string, fn func(storage.Object) error) error {
+	if m.err != nil {
+		return m.err
+	}
+	for _, obj := range m.objects.Objects {
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *mockStorage) DescribeObject(_ context.Context, _ string, _ string) (storage.Object, error) {
 	return m.object, m.err
 }
@@ -225,8 +176,8 @@ func TestStorageService_DeleteBucket_HappyPath(t *testing.T) {
 	if err := svc.DeleteBucket(context.Background(), "my-bucket", "gcp"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !mock.closeCalled {
-		t.Error("expected Close to be called on the provider client")
+	if mock.closeCalled {
+		t.Error("provider client should stay open until Shutdown")
 	}
 }
 
@@ -276,6 +227,46 @@ func TestStorageService_ListObjects_HappyPath(t *testing.T) {
 	}
 }
 
+func TestStorageService_WalkObjects_VisitsEachObject(t *testing.T) {
+	mock := &mockStorage{objects: storage.ObjectList{
+		BucketName: "my-bucket",
+		Objects:    []storage.Object{{Key: "a.txt"}, {Key: "dir/b.txt"}},
+	}}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	var keys []string
+	err := svc.WalkObjects(context.Background(), "my-bucket", "gcp", "", func(obj storage.Object) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "a.txt" || keys[1] != "dir/b.txt" {
+		t.Errorf("got keys %v, want [a.txt dir/b.txt]", keys)
+	}
+}
+
+func TestStorageService_WalkObjects_CallbackErrorStopsWalk(t *testing.T) {
+	mock := &mockStorage{objects: storage.ObjectList{
+		Objects: []storage.Object{{Key: "a.txt"}, {Key: "b.txt"}},
+	}}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	stopErr := errors.New("stop")
+	calls := 0
+	err := svc.WalkObjects(context.Background(), "my-bucket", "gcp", "", func(storage.Object) error {
+		calls++
+		return stopErr
+	})
+	if !errors.Is(err, stopErr) {
+		t.Errorf("expected callback error to be returned, got: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected walk to stop after first error, got %d calls", calls)
+	}
+}
+
 func TestStorageService_DescribeObject_HappyPath(t *testing.T) {
 	want := storage.Object{Key: "file.txt", Bucket: "my-bucket"}
 	mock := &mockStorage{object: want}
@@ -310,12 +301,12 @@ func TestStorageService_DownloadObject_HappyPath(t *testing.T) {
 		t.Errorf("got content %q, want %q", buf.String(), content)
 	}
 
-	// Close triggers cleanup (client.Close)
+	// Closing the reader leaves the cached provider client open
 	if err := rc.Close(); err != nil {
 		t.Errorf("unexpected close error: %v", err)
 	}
-	if !mock.closeCalled {
-		t.Error("expected provider client Close to be called when reader is closed")
+	if mock.closeCalled {
+		t.Error("provider client should stay open until Shutdown")
 	}
 }
 
@@ -375,3 +366,48 @@ func TestStorageService_ListAllBuckets_PartialFailure(t *testing.T) {
 		t.Errorf("got %d results, want %d (partial success should be preserved)", len(results), len(successBuckets))
 	}
 }
+
+func TestStorageService_ListAllBucketsFunc_ReportsEachProvider(t *testing.T) {
+	svc := newStorageService(&mockStorageFactory{
+		providers: map[string]storage.Storage{
+			"gcp": &mockStorage{providerName: domain.GCP, buckets: []storage.Bucket{{Name: "bucket-a"}, {Name: "bucket-b"}}},
+			"aws": &mockStorage{providerName: domain.AWS, err: errors.New("aws not reachable")},
+		},
+	})
+
+	got := map[string]int{}
+	results, err := svc.ListAllBucketsFunc(context.Background(), []string{"gcp", "aws"}, func(providerName string, buckets []storage.Bucket) {
+		got[providerName] += len(buckets)
+	})
+	if err == nil {
+		t.Fatal("expected a partial error, got nil")
+	}
+	if len(results) != 2 || len(got) != 1 || got[string(domain.GCP)] != 2 {
+		t.Errorf("expected only the gcp buckets to be reported, got %v (results %v)", got, results)
+	}
+}
+
+func TestReaderSize(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString("0123456789")
+	f.Seek(4, io.SeekStart)
+
+	tests := []struct {
+		name   string
+		reader io.Reader
+		want   int64
+	}{
+		{"file from its offset", f, 6},
+		{"in-memory reader", strings.NewReader("hello"), 5},
+		{"unknown size", io.LimitReader(strings.NewReader("hello"), 3), 0},
+	}
+	for _, tt := range tests {
+		if got := readerSize(tt.reader); got != tt.want {
+			t.Errorf("%s: readerSize = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
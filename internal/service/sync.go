@@ -0,0 +1,298 @@
+package service
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SyncTarget is one side of a sync: the objects under Prefix in a bucket.
+type SyncTarget struct {
+	Provider string `json:"provider" yaml:"provider"`
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	Prefix   string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
+
+func (t SyncTarget) String() string {
+	return fmt.Sprintf("%s:%s/%s", t.Provider, t.Bucket, t.Prefix)
+}
+
+// SyncOptions copies the objects under Source.Prefix to the same relative keys
+// under Destination.Prefix.
+type SyncOptions struct {
+	Source      SyncTarget `json:"source" yaml:"source"`
+	Destination SyncTarget `json:"destination" yaml:"destination"`
+	// Include and Exclude hold glob patterns (path.Match syntax) matched against
+	// each key relative to Source.Prefix, or against its last element when the
+	// pattern has no "/" (e.g., "*.tmp"). An object is synced when it matches an
+	// Include pattern, or there are none, and no Exclude pattern.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// Verify compares the source and destination after the transfer, and
+	// fails the sync if any source object is missing or different at the
+	// destination
+	Verify bool `json:"verify,omitempty" yaml:"verify,omitempty"`
+	// ContentTypes sets the content type of the synced objects whose key,
+	// relative to Source.Prefix, matches a pattern. Destination objects with
+	// another content type are synced again even if their data is the same,
+	// and matching objects are streamed through synkronus even within a
+	// provider, so the content type is set.
+	ContentTypes storage.ContentTypeRules `json:"content_types,omitempty" yaml:"content_types,omitempty"`
+	// Template sets content headers and metadata on the synced objects: its
+	// non-empty headers replace the source object's, and its metadata keys
+	// are added to the source's. It takes precedence over ContentTypes. As
+	// with ContentTypes, destination objects without them are synced again.
+	Template *storage.ObjectAttributes `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// SyncResult counts what a sync did. Objects already at the destination with
+// the same size and checksum are skipped.
+type SyncResult struct {
+	Copied  int           `json:"copied" yaml:"copied"`
+	Skipped int           `json:"skipped" yaml:"skipped"`
+	Bytes   int64         `json:"bytes" yaml:"bytes"`
+	Failed  []SyncFailure `json:"failed,omitempty" yaml:"failed,omitempty"`
+	// Verification is the comparison made after the transfer, for SyncOptions.Verify
+	Verification *ObjectComparison `json:"verification,omitempty" yaml:"verification,omitempty"`
+}
+
+// SyncFailure is an object that could not be copied.
+type SyncFailure struct {
+	Key   string `json:"key" yaml:"key"`
+	Error string `json:"error" yaml:"error"`
+}
+
+// Sync copies the source objects that are missing or different at the
+// destination. Objects are copied in place within a provider and streamed
+// through synkronus between providers. A failed object does not stop the
+// sync; it is listed in the result, and the returned error counts the failures.
+// Objects only at the destination are left alone. With opts.Verify, the
+// destination is then compared with the source, and objects still missing or
+// different fail the sync.
+func (s *StorageService) Sync(ctx context.Context, opts SyncOptions) (SyncResult, error) {
+	src, dst := opts.Source, opts.Destination
+	ctx, span := telemetry.Start(ctx, "StorageService.Sync",
+		attribute.String("source", src.String()), attribute.String("destination", dst.String()))
+	s.logger.Debug("Starting Sync operation", "source", src.String(), "destination", dst.String())
+
+	result, err := s.sync(ctx, opts)
+	telemetry.End(span, err)
+	return result, err
+}
+
+func (s *StorageService) sync(ctx context.Context, opts SyncOptions) (SyncResult, error) {
+	src, dst := opts.Source, opts.Destination
+	var result SyncResult
+	for _, patterns := range [][]string{opts.Include, opts.Exclude} {
+		if err := ValidatePatterns(patterns); err != nil {
+			return result, err
+		}
+	}
+	if _, err := storage.ParseContentTypeRules(opts.ContentTypes); err != nil {
+		return result, err
+	}
+	if strings.EqualFold(src.Provider, dst.Provider) && src.Bucket == dst.Bucket &&
+		(strings.HasPrefix(dst.Prefix, src.Prefix) || strings.HasPrefix(src.Prefix, dst.Prefix)) {
+		return result, fmt.Errorf("source %s and destination %s overlap", src, dst)
+	}
+
+	existing := make(map[string]storage.Object)
+	err := s.WalkObjects(ctx, dst.Bucket, dst.Provider, dst.Prefix, func(obj storage.Object) error {
+		existing[strings.TrimPrefix(obj.Key, dst.Prefix)] = obj
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = s.WalkObjects(ctx, src.Bucket, src.Provider, src.Prefix, func(obj storage.Object) error {
+		rel := strings.TrimPrefix(obj.Key, src.Prefix)
+		if !included(rel, opts.Include, opts.Exclude) {
+			return nil
+		}
+		destKey := dst.Prefix + rel
+		overrides := opts.overrides(rel)
+		if current, ok := existing[rel]; ok && sameContent(obj, current) && s.hasAttributes(ctx, dst, current, overrides) {
+			result.Skipped++
+			return nil
+		}
+		if err := s.syncObject(ctx, src, dst, obj, destKey, overrides); err != nil {
+			// Stop on cancellation rather than failing every remaining object
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.Warn("Failed to sync object", "key", obj.Key, "error", err)
+			result.Failed = append(result.Failed, SyncFailure{Key: obj.Key, Error: err.Error()})
+			return nil
+		}
+		result.Copied++
+		result.Bytes += obj.Size
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	var failures, unverified error
+	if len(result.Failed) > 0 {
+		failures = fmt.Errorf("%d of %d objects failed to sync", len(result.Failed), result.Copied+len(result.Failed))
+	}
+	if opts.Verify {
+		comparison, err := s.compareObjects(ctx, opts)
+		if err != nil {
+			return result, errors.Join(failures, fmt.Errorf("verifying the sync: %w", err))
+		}
+		result.Verification = &comparison
+		if missing, different := len(comparison.OnlyInSource), len(comparison.Mismatched); missing > 0 || different > 0 {
+			unverified = fmt.Errorf("verification failed: %d objects missing and %d different at the destination", missing, different)
+		}
+	}
+	return result, errors.Join(failures, unverified)
+}
+
+// overrides returns the content headers and metadata opts sets on the copy
+// of the object at rel, or nil if it sets none.
+func (o SyncOptions) overrides(rel string) *storage.ObjectAttributes {
+	var attrs storage.ObjectAttributes
+	if o.Template != nil {
+		attrs = *o.Template
+	}
+	if attrs.ContentType == "" {
+		attrs.ContentType = o.ContentTypes.Match(rel)
+	}
+	if attrs.ContentType == "" && attrs.CacheControl == "" && attrs.ContentDisposition == "" &&
+		attrs.ContentEncoding == "" && attrs.ContentLanguage == "" && len(attrs.Metadata) == 0 {
+		return nil
+	}
+	return &attrs
+}
+
+// hasAttributes reports whether the destination object current already has
+// the headers and metadata of overrides. Listings of some providers leave
+// headers out, so an object that seems to lack them is described first.
+func (s *StorageService) hasAttributes(ctx context.Context, dst SyncTarget, current storage.Object, overrides *storage.ObjectAttributes) bool {
+	if overrides == nil || applied(current, overrides) {
+		return true
+	}
+	described, err := s.DescribeObject(ctx, dst.Bucket, current.Key, dst.Provider)
+	return err == nil && applied(described, overrides)
+}
+
+// applied reports whether obj has every non-empty header and metadata key of attrs.
+func applied(obj storage.Object, attrs *storage.ObjectAttributes) bool {
+	for _, h := range [][2]string{
+		{attrs.ContentType, obj.ContentType},
+		{attrs.CacheControl, obj.CacheControl},
+		{attrs.ContentDisposition, obj.ContentDisposition},
+		{attrs.ContentEncoding, obj.ContentEncoding},
+		{attrs.ContentLanguage, obj.ContentLanguage},
+	} {
+		if h[0] != "" && h[0] != h[1] {
+			return false
+		}
+	}
+	for k, v := range attrs.Metadata {
+		if obj.Metadata[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// syncObject copies obj from src to destKey at dst, setting the headers and
+// metadata of overrides on the copy. An object without a content type gets
+// the one detected from its key and data.
+func (s *StorageService) syncObject(ctx context.Context, src, dst SyncTarget, obj storage.Object, destKey string, overrides *storage.ObjectAttributes) error {
+	if src.Provider == dst.Provider && (overrides == nil || applied(obj, overrides)) {
+		return s.CopyObject(ctx, src.Bucket, obj.Key, dst.Bucket, destKey, src.Provider)
+	}
+
+	reader, err := s.DownloadObject(ctx, src.Bucket, obj.Key, src.Provider)
+	if err != nil {
+		return err
+	}
+	opts := storage.UploadObjectOptions{
+		BucketName:         dst.Bucket,
+		ObjectKey:          destKey,
+		ContentType:        obj.ContentType,
+		Metadata:           obj.Metadata,
+		CacheControl:       obj.CacheControl,
+		ContentDisposition: obj.ContentDisposition,
+		ContentEncoding:    obj.ContentEncoding,
+		ContentLanguage:    obj.ContentLanguage,
+	}
+	if overrides != nil {
+		opts.ContentType = cmp.Or(overrides.ContentType, opts.ContentType)
+		opts.CacheControl = cmp.Or(overrides.CacheControl, opts.CacheControl)
+		opts.ContentDisposition = cmp.Or(overrides.ContentDisposition, opts.ContentDisposition)
+		opts.ContentEncoding = cmp.Or(overrides.ContentEncoding, opts.ContentEncoding)
+		opts.ContentLanguage = cmp.Or(overrides.ContentLanguage, opts.ContentLanguage)
+		if len(overrides.Metadata) > 0 {
+			opts.Metadata = maps.Clone(obj.Metadata)
+			if opts.Metadata == nil {
+				opts.Metadata = make(map[string]string, len(overrides.Metadata))
+			}
+			maps.Copy(opts.Metadata, overrides.Metadata)
+		}
+	}
+
+	body := bufio.NewReaderSize(reader, storage.SniffLen)
+	if opts.ContentType == "" {
+		// Peek errors surface again when the upload reads the body
+		head, _ := body.Peek(storage.SniffLen)
+		opts.ContentType = storage.DetectContentType(destKey, head)
+	}
+	uploadErr := s.UploadObject(ctx, opts, dst.Provider, body)
+	return errors.Join(uploadErr, reader.Close())
+}
+
+// ValidatePatterns reports the first malformed sync filter pattern.
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid filter pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// included reports whether the relative key rel passes the include and
+// exclude patterns of SyncOptions.
+func included(rel string, include, exclude []string) bool {
+	if matchesAny(rel, exclude) {
+		return false
+	}
+	return len(include) == 0 || matchesAny(rel, include)
+}
+
+func matchesAny(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		name := rel
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sameContent reports whether two objects hold the same data, by the same
+// rule 'storage verify' uses (see contentMismatch): size and, when both
+// providers report one, a checksum. ETags are not compared, since providers
+// compute them differently.
+func sameContent(a, b storage.Object) bool {
+	reason, _ := contentMismatch(a, b)
+	return reason == ""
+}
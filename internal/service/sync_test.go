@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/mock"
+)
+
+// newSyncTestService returns a service whose providers "src" and "dst" are
+// separate in-memory clients, both holding the mock seed data, plus an empty
+// "replica" bucket on dst.
+func newSyncTestService(t *testing.T) *StorageService {
+	t.Helper()
+	dst := mock.NewMockStorage(newTestLogger())
+	if _, err := dst.CreateBucket(context.Background(), storage.CreateBucketOptions{Name: "replica", Location: "US"}); err != nil {
+		t.Fatal(err)
+	}
+	return newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{
+		"src": mock.NewMockStorage(newTestLogger()),
+		"dst": dst,
+	}})
+}
+
+func TestStorageService_Sync_CopiesThenSkips(t *testing.T) {
+	svc := newSyncTestService(t)
+	opts := SyncOptions{
+		Source:      SyncTarget{Provider: "dst", Bucket: "acme-data-lake", Prefix: "curated/"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "replica", Prefix: "copy/"},
+	}
+
+	result, err := svc.Sync(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Copied != 2 || result.Skipped != 0 || result.Bytes == 0 {
+		t.Errorf("first sync = %+v, want 2 objects copied", result)
+	}
+	if _, err := svc.DescribeObject(context.Background(), "replica", "copy/orders.csv", "dst"); err != nil {
+		t.Errorf("expected the object at its relative key: %v", err)
+	}
+
+	result, err = svc.Sync(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Copied != 0 || result.Skipped != 2 {
+		t.Errorf("second sync = %+v, want both objects skipped", result)
+	}
+}
+
+func TestStorageService_Sync_Filters(t *testing.T) {
+	svc := newSyncTestService(t)
+
+	result, err := svc.Sync(context.Background(), SyncOptions{
+		Source:      SyncTarget{Provider: "dst", Bucket: "acme-data-lake"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "replica"},
+		Include:     []string{"raw/2024/*/*", "*.csv"},
+		Exclude:     []string{"raw/2024/01/*", "customers.*"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var keys []string
+	err = svc.WalkObjects(context.Background(), "replica", "dst", "", func(obj storage.Object) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Copied != 3 || len(keys) != 3 {
+		t.Errorf("expected 3 objects copied, got %+v with keys %v", result, keys)
+	}
+	for _, key := range keys {
+		if key == "raw/2024/01/events.json" || key == "curated/customers.csv" {
+			t.Errorf("excluded object %q was copied", key)
+		}
+	}
+
+	_, err = svc.Sync(context.Background(), SyncOptions{
+		Source:      SyncTarget{Provider: "dst", Bucket: "acme-data-lake"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "replica"},
+		Exclude:     []string{"[unclosed"},
+	})
+	if err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
+
+func TestStorageService_Sync_AcrossProviders(t *testing.T) {
+	svc := newSyncTestService(t)
+
+	result, err := svc.Sync(context.Background(), SyncOptions{
+		Source:      SyncTarget{Provider: "src", Bucket: "acme-web-assets", Prefix: "images/"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "replica"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Copied != 2 {
+		t.Errorf("sync = %+v, want 2 objects copied", result)
+	}
+
+	reader, err := svc.DownloadObject(context.Background(), "replica", "logo.svg", "dst")
+	if err != nil {
+		t.Fatalf("expected the copied object: %v", err)
+	}
+	defer reader.Close()
+	data, _ := io.ReadAll(reader)
+	if int64(len(data)) != 3_120 {
+		t.Errorf("copied %d bytes, want 3120", len(data))
+	}
+}
+
+func TestStorageService_Sync_ContentTypes(t *testing.T) {
+	svc := newSyncTestService(t)
+	opts := SyncOptions{
+		Source:      SyncTarget{Provider: "dst", Bucket: "acme-data-lake", Prefix: "curated/"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "replica"},
+	}
+	if _, err := svc.Sync(context.Background(), opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The objects are already there, but with another content type
+	opts.ContentTypes = storage.ContentTypeRules{"*.csv": "application/vnd.ms-excel"}
+	result, err := svc.Sync(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Copied != 2 {
+		t.Errorf("sync = %+v, want both objects copied again", result)
+	}
+	obj, err := svc.DescribeObject(context.Background(), "replica", "orders.csv", "dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.ContentType != "application/vnd.ms-excel" {
+		t.Errorf("content type = %q, want the one set by the rule", obj.ContentType)
+	}
+
+	opts.ContentTypes = storage.ContentTypeRules{"*.csv": "not a type;"}
+	if _, err := svc.Sync(context.Background(), opts); err == nil {
+		t.Error("expected an error for a malformed content type")
+	}
+}
+
+func TestStorageService_Sync_MissingDestination(t *testing.T) {
+	svc := newSyncTestService(t)
+
+	result, err := svc.Sync(context.Background(), SyncOptions{
+		Source:      SyncTarget{Provider: "src", Bucket: "acme-backups"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "no-such-bucket"},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a missing destination bucket")
+	}
+	if result.Copied != 0 {
+		t.Errorf("expected nothing copied, got %+v", result)
+	}
+}
+
+func TestStorageService_Sync_RejectsOverlap(t *testing.T) {
+	svc := newSyncTestService(t)
+
+	_, err := svc.Sync(context.Background(), SyncOptions{
+		Source:      SyncTarget{Provider: "src", Bucket: "acme-data-lake"},
+		Destination: SyncTarget{Provider: "src", Bucket: "acme-data-lake", Prefix: "backup/"},
+	})
+	if err == nil {
+		t.Error("expected an error when the destination is inside the source")
+	}
+
+	_, err = svc.Sync(context.Background(), SyncOptions{
+		Source:      SyncTarget{Provider: "SRC", Bucket: "acme-data-lake"},
+		Destination: SyncTarget{Provider: "src", Bucket: "acme-data-lake", Prefix: "backup/"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "overlap") {
+		t.Errorf("expected providers to be compared regardless of case, got %v", err)
+	}
+}
+
+// lossyStorage reports copies as done without making them.
+type lossyStorage struct {
+	*mock.MockStorage
+}
+
+func (l lossyStorage) CopyObject(context.Context, string, string, string, string) error {
+	return nil
+}
+
+func TestStorageService_Sync_Verify(t *testing.T) {
+	svc := newSyncTestService(t)
+	opts := SyncOptions{
+		Source:      SyncTarget{Provider: "dst", Bucket: "acme-data-lake", Prefix: "curated/"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "replica"},
+		Verify:      true,
+	}
+
+	result, err := svc.Sync(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Verification == nil || result.Verification.Matched != 2 || result.Verification.Differs() {
+		t.Errorf("expected both objects verified, got %+v", result.Verification)
+	}
+}
+
+func TestStorageService_Sync_VerifyFails(t *testing.T) {
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{
+		"lossy": lossyStorage{mock.NewMockStorage(newTestLogger())},
+	}})
+	result, err := svc.Sync(context.Background(), SyncOptions{
+		Source:      SyncTarget{Provider: "lossy", Bucket: "acme-data-lake", Prefix: "curated/"},
+		Destination: SyncTarget{Provider: "lossy", Bucket: "acme-data-lake", Prefix: "copy/"},
+		Verify:      true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "verification failed: 2 objects missing") {
+		t.Fatalf("expected the verification to fail, got %v", err)
+	}
+	if result.Copied != 2 || result.Verification == nil || len(result.Verification.OnlyInSource) != 2 {
+		t.Errorf("expected the missing objects in the result, got %+v", result)
+	}
+}
+
+func TestStorageService_Sync_Template(t *testing.T) {
+	svc := newSyncTestService(t)
+	opts := SyncOptions{
+		Source:      SyncTarget{Provider: "src", Bucket: "acme-web-assets", Prefix: "images/"},
+		Destination: SyncTarget{Provider: "dst", Bucket: "replica"},
+		Template:    &storage.ObjectAttributes{CacheControl: "public, max-age=86400", Metadata: map[string]string{"team": "web"}},
+	}
+
+	if _, err := svc.Sync(context.Background(), opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := svc.DescribeObject(context.Background(), "replica", "logo.svg", "dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.CacheControl != "public, max-age=86400" || obj.Metadata["team"] != "web" {
+		t.Errorf("expected the template applied, got cache control %q and metadata %v", obj.CacheControl, obj.Metadata)
+	}
+
+	result, err := svc.Sync(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Copied != 0 || result.Skipped != 2 {
+		t.Errorf("second sync = %+v, want both objects skipped", result)
+	}
+}
+
+func TestSameContent(t *testing.T) {
+	a := storage.Object{Key: "a", Size: 4, CRC32C: "AAAAAA=="}
+	b := storage.Object{Key: "a", Size: 4, CRC32C: "BBBBBB=="}
+	if sameContent(a, b) {
+		t.Error("expected objects of the same size with different CRC32C checksums to differ")
+	}
+	b.CRC32C = a.CRC32C
+	if !sameContent(a, b) {
+		t.Error("expected objects with the same CRC32C checksum to match")
+	}
+	if sameContent(storage.Object{Size: 4, MD5Hash: "x"}, storage.Object{Size: 4, MD5Hash: "y"}) {
+		t.Error("expected different MD5 checksums to differ")
+	}
+	if !sameContent(storage.Object{Size: 4}, storage.Object{Size: 4}) {
+		t.Error("expected objects without checksums to match by size")
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/telemetry"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStorageService_ListAllBuckets_TracesEachProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{
+		"gcp": &mockStorage{providerName: domain.GCP},
+		"aws": &mockStorage{providerName: domain.AWS, err: errors.New("access denied")},
+	}})
+	svc.ListAllBuckets(context.Background(), []string{"gcp", "aws"})
+
+	var parent sdktrace.ReadOnlySpan
+	children := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "StorageService.ListAllBuckets":
+			parent = span
+		case "StorageService.ListBuckets":
+			for _, a := range span.Attributes() {
+				if a.Key == telemetry.Provider("").Key {
+					children[a.Value.AsString()] = span
+				}
+			}
+		}
+	}
+	if parent == nil {
+		t.Fatal("expected a span for the fan-out")
+	}
+	if len(children) != 2 {
+		t.Fatalf("expected a span per provider, got %v", children)
+	}
+	for provider, span := range children {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("%s span is not a child of the fan-out span", provider)
+		}
+	}
+	if children[string(domain.AWS)].Status().Code != codes.Error {
+		t.Errorf("expected the failed provider's span to record the error, got %v", children[string(domain.AWS)].Status())
+	}
+	if children[string(domain.GCP)].Status().Code == codes.Error {
+		t.Errorf("expected the gcp span to succeed, got %v", children[string(domain.GCP)].Status())
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ObjectVerification is the outcome of comparing an object with local data
+// by the strongest checksum the object reports.
+type ObjectVerification struct {
+	Provider  string `json:"provider" yaml:"provider"`
+	Bucket    string `json:"bucket" yaml:"bucket"`
+	Key       string `json:"key" yaml:"key"`
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	// Composite is set when the object's checksum combines those of the
+	// parts of a multipart upload, which were PartSize bytes each
+	Composite bool   `json:"composite,omitempty" yaml:"composite,omitempty"`
+	PartSize  int64  `json:"part_size,omitempty" yaml:"part_size,omitempty"`
+	Expected  string `json:"expected" yaml:"expected"`
+	Actual    string `json:"actual" yaml:"actual"`
+	Match     bool   `json:"match" yaml:"match"`
+}
+
+// VerifyObject checks that r holds the same data as an object, by computing
+// the checksum the object reports the way the provider did. A composite
+// checksum is recomputed over parts of the object's part size.
+func (s *StorageService) VerifyObject(ctx context.Context, bucketName, objectKey, providerName string, r io.Reader) (ObjectVerification, error) {
+	ctx, done := observe(ctx, "StorageService.VerifyObject", providerName, attribute.String("bucket", bucketName), attribute.String("object", objectKey))
+	s.logger.Debug("Starting VerifyObject operation", "bucket", bucketName, "object", objectKey, "provider", providerName)
+
+	result, err := s.verifyObject(ctx, bucketName, objectKey, providerName, r)
+	done(err)
+	return result, err
+}
+
+func (s *StorageService) verifyObject(ctx context.Context, bucketName, objectKey, providerName string, r io.Reader) (ObjectVerification, error) {
+	obj, err := s.DescribeObject(ctx, bucketName, objectKey, providerName)
+	if err != nil {
+		return ObjectVerification{}, err
+	}
+
+	algorithm, expected := obj.StrongestChecksum()
+	if algorithm == "" {
+		return ObjectVerification{}, fmt.Errorf("object %q in bucket %q on %s reports no checksum to verify against; upload it with --checksum",
+			objectKey, bucketName, providerName)
+	}
+
+	result := ObjectVerification{
+		Provider:  strings.ToLower(providerName),
+		Bucket:    bucketName,
+		Key:       objectKey,
+		Algorithm: algorithm,
+		Composite: obj.ChecksumType == storage.ChecksumTypeComposite,
+		Expected:  expected,
+	}
+	if result.Composite {
+		if obj.PartSize <= 0 {
+			return ObjectVerification{}, fmt.Errorf("object %q in bucket %q on %s has a composite checksum but no known part size",
+				objectKey, bucketName, providerName)
+		}
+		result.PartSize = obj.PartSize
+	}
+
+	result.Actual, err = storage.ComputeChecksum(r, algorithm, result.PartSize)
+	if err != nil {
+		return ObjectVerification{}, fmt.Errorf("computing the %s checksum: %w", algorithm, err)
+	}
+	result.Match = result.Actual == result.Expected
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestStorageService_VerifyObject(t *testing.T) {
+	mock := &mockStorage{object: storage.Object{Key: "a.txt", MD5Hash: "XUFAKrxLKna5cZ2REBfFkg==", CRC32C: "mnG7TA=="}}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	result, err := svc.VerifyObject(context.Background(), "bucket", "a.txt", "gcp", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Algorithm != storage.ChecksumCRC32C {
+		t.Errorf("expected the strongest checksum, CRC32C, got %s", result.Algorithm)
+	}
+	if !result.Match {
+		t.Errorf("expected a match, got %+v", result)
+	}
+
+	result, err = svc.VerifyObject(context.Background(), "bucket", "a.txt", "gcp", strings.NewReader("hullo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Match {
+		t.Errorf("expected a mismatch, got %+v", result)
+	}
+}
+
+func TestStorageService_VerifyObject_Composite(t *testing.T) {
+	want, err := storage.ComputeChecksum(strings.NewReader("hello world"), storage.ChecksumSHA256, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock := &mockStorage{object: storage.Object{Key: "a.txt", SHA256: want, ChecksumType: storage.ChecksumTypeComposite, PartSize: 6}}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	result, err := svc.VerifyObject(context.Background(), "bucket", "a.txt", "gcp", strings.NewReader("hello world"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Composite || result.PartSize != 6 || !result.Match {
+		t.Errorf("expected a matching composite checksum, got %+v", result)
+	}
+}
+
+func TestStorageService_VerifyObject_NoChecksum(t *testing.T) {
+	mock := &mockStorage{object: storage.Object{Key: "a.txt"}}
+	svc := newStorageService(&mockStorageFactory{providers: map[string]storage.Storage{"gcp": mock}})
+
+	if _, err := svc.VerifyObject(context.Background(), "bucket", "a.txt", "gcp", strings.NewReader("hello")); err == nil {
+		t.Error("expected an error for an object reporting no checksum")
+	}
+}
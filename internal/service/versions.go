@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WalkObjectVersions passes every version of the objects under prefix to fn
+// (see storage.VersionManager). Like WalkObjects, it bypasses the response
+// cache.
+func (s *StorageService) WalkObjectVersions(ctx context.Context, bucketName, providerName, prefix string, fn func(storage.ObjectVersion) error) error {
+	ctx, done := observe(ctx, "StorageService.WalkObjectVersions", providerName, attribute.String("bucket", bucketName))
+	s.logger.Debug("Starting WalkObjectVersions operation", "bucket", bucketName, "provider", providerName, "prefix", prefix)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
+		versions, ok := client.(storage.VersionManager)
+		if !ok {
+			return fmt.Errorf("listing object versions on %s: %w", providerName, ErrUnsupported)
+		}
+		if err := versions.WalkObjectVersions(ctx, bucketName, prefix, fn); err != nil {
+			return fmt.Errorf("listing object versions in bucket %q on %s: %w", bucketName, providerName, err)
+		}
+		return nil
+	})
+	done(err)
+	return err
+}
+
+func (s *StorageService) DeleteObjectVersion(ctx context.Context, bucketName, objectKey, versionID, providerName string) error {
+	ctx, done := observe(ctx, "StorageService.DeleteObjectVersion", providerName, attribute.String("bucket", bucketName), attribute.String("object", objectKey))
+	s.logger.Debug("Starting DeleteObjectVersion operation",
+		"bucket", bucketName, "key", objectKey, "version", versionID, "provider", providerName)
+	err := s.withClient(ctx, providerName, func(client storage.Storage) error {
+		versions, ok := client.(storage.VersionManager)
+		if !ok {
+			return fmt.Errorf("deleting object versions on %s: %w", providerName, ErrUnsupported)
+		}
+		if err := versions.DeleteObjectVersion(ctx, bucketName, objectKey, versionID); err != nil {
+			return fmt.Errorf("deleting version %s of object %q from bucket %q on %s: %w", versionID, objectKey, bucketName, providerName, err)
+		}
+		s.invalidateResponses()
+		return nil
+	})
+	s.recordChange("delete-object-version", providerName, bucketName+"/"+objectKey+"#"+versionID, err)
+	done(err)
+	return err
+}
@@ -0,0 +1,337 @@
+// Package snapshot records the configuration of buckets and SQL instances to a
+// file, and reports what changed since a snapshot was taken, so changes made
+// outside synkronus (in a console, by another tool) can be detected.
+package snapshot
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version is the snapshot file format written by Write. Read rejects others.
+const Version = 1
+
+// describeParallel is how many buckets are described at once
+const describeParallel = 8
+
+// volatileFields change without anyone changing the configuration, so Diff
+// ignores them.
+var volatileFields = map[string]bool{
+	"usage_bytes": true,
+	"updated_at":  true,
+}
+
+// Snapshot is the configuration of the buckets and SQL instances of some
+// providers at one point in time.
+type Snapshot struct {
+	Version int       `json:"version" yaml:"version"`
+	TakenAt time.Time `json:"taken_at" yaml:"taken_at"`
+	// StorageProviders and SqlProviders are the providers captured, which
+	// Diff compares against the same providers' current state
+	StorageProviders []string         `json:"storage_providers" yaml:"storage_providers"`
+	SqlProviders     []string         `json:"sql_providers" yaml:"sql_providers"`
+	Buckets          []storage.Bucket `json:"buckets" yaml:"buckets"`
+	Instances        []sql.Instance   `json:"instances" yaml:"instances"`
+}
+
+// StorageSource is the subset of the storage service a snapshot needs.
+type StorageSource interface {
+	ListAllBuckets(ctx context.Context, providerNames []string) ([]storage.Bucket, error)
+	DescribeBucket(ctx context.Context, bucketName, providerName string) (storage.Bucket, error)
+}
+
+// SqlSource is the subset of the SQL service a snapshot needs.
+type SqlSource interface {
+	ListAllInstances(ctx context.Context, providerNames []string) ([]sql.Instance, error)
+}
+
+// Take captures the buckets of storageProviders, each described in full
+// (IAM policy, lifecycle rules, encryption...), and the SQL instances of
+// sqlProviders. Any failure fails the snapshot, since the resources it would
+// miss would later be reported as deleted.
+func Take(ctx context.Context, buckets StorageSource, instances SqlSource, storageProviders, sqlProviders []string) (Snapshot, error) {
+	snap := Snapshot{
+		Version:          Version,
+		TakenAt:          time.Now().UTC(),
+		StorageProviders: normalizeProviders(storageProviders),
+		SqlProviders:     normalizeProviders(sqlProviders),
+	}
+
+	if len(snap.StorageProviders) > 0 {
+		listed, err := buckets.ListAllBuckets(ctx, snap.StorageProviders)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("listing buckets: %w", err)
+		}
+		described, err := describeAll(ctx, buckets, listed)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		snap.Buckets = described
+	}
+
+	if len(snap.SqlProviders) > 0 {
+		listed, err := instances.ListAllInstances(ctx, snap.SqlProviders)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("listing SQL instances: %w", err)
+		}
+		snap.Instances = listed
+	}
+
+	slices.SortFunc(snap.Buckets, func(a, b storage.Bucket) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(snap.Instances, func(a, b sql.Instance) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Name, b.Name))
+	})
+	return snap, nil
+}
+
+// describeAll describes each listed bucket, describeParallel at a time.
+func describeAll(ctx context.Context, source StorageSource, listed []storage.Bucket) ([]storage.Bucket, error) {
+	described := make([]storage.Bucket, len(listed))
+	errs := make([]error, len(listed))
+	sem := make(chan struct{}, describeParallel)
+	var wg sync.WaitGroup
+	for i, b := range listed {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			bucket, err := source.DescribeBucket(ctx, b.Name, strings.ToLower(string(b.Provider)))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			// Listings record which project or account a bucket came from, describes may not
+			bucket.Project = cmp.Or(bucket.Project, b.Project)
+			bucket.Account = cmp.Or(bucket.Account, b.Account)
+			described[i] = bucket
+		}()
+	}
+	wg.Wait()
+	return described, errors.Join(errs...)
+}
+
+func normalizeProviders(providers []string) []string {
+	normalized := make([]string, 0, len(providers))
+	for _, p := range providers {
+		normalized = append(normalized, strings.ToLower(p))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// Write writes snap as indented JSON.
+func Write(w io.Writer, snap Snapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snap)
+}
+
+// Read reads a snapshot written by Write.
+func Read(r io.Reader) (Snapshot, error) {
+	var snap Snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid snapshot file: %w", err)
+	}
+	if snap.Version != Version {
+		return Snapshot{}, fmt.Errorf("unsupported snapshot version %d: expected %d", snap.Version, Version)
+	}
+	return snap, nil
+}
+
+// ChangeKind is how a resource differs from the snapshot.
+type ChangeKind string
+
+const (
+	// ChangeAdded is a resource created since the snapshot
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved is a resource deleted since the snapshot
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified is a resource whose configuration changed
+	ChangeModified ChangeKind = "modified"
+)
+
+// Resource types of a Change
+const (
+	ResourceBucket      = "bucket"
+	ResourceSqlInstance = "sql-instance"
+)
+
+// FieldChange is one configuration value that changed. Fields are named by
+// their JSON path (e.g., "versioning.enabled", "labels.team",
+// "lifecycle_rules[0].condition.age"); a value missing on one side is empty.
+type FieldChange struct {
+	Field  string `json:"field" yaml:"field"`
+	Before string `json:"before" yaml:"before"`
+	After  string `json:"after" yaml:"after"`
+}
+
+// Change is a resource that differs from the snapshot.
+type Change struct {
+	Kind     ChangeKind `json:"kind" yaml:"kind"`
+	Resource string     `json:"resource" yaml:"resource"`
+	Provider string     `json:"provider" yaml:"provider"`
+	Name     string     `json:"name" yaml:"name"`
+	// Fields are the values that changed, for ChangeModified
+	Fields []FieldChange `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// Report is the difference between a snapshot and the current state.
+type Report struct {
+	TakenAt time.Time `json:"taken_at" yaml:"taken_at"`
+	Changes []Change  `json:"changes" yaml:"changes"`
+}
+
+// Diff reports the buckets and instances added, removed or modified in
+// current since saved, ordered by resource type, provider and name. Usage and
+// update times are not compared.
+func Diff(saved, current Snapshot) (Report, error) {
+	report := Report{TakenAt: saved.TakenAt, Changes: []Change{}}
+
+	bucketKey := func(b storage.Bucket) (string, string) { return strings.ToLower(string(b.Provider)), b.Name }
+	changes, err := diffResources(ResourceBucket, saved.Buckets, current.Buckets, bucketKey)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Changes = append(report.Changes, changes...)
+
+	instanceKey := func(i sql.Instance) (string, string) { return strings.ToLower(string(i.Provider)), i.Name }
+	changes, err = diffResources(ResourceSqlInstance, saved.Instances, current.Instances, instanceKey)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Changes = append(report.Changes, changes...)
+	return report, nil
+}
+
+func diffResources[T any](resource string, saved, current []T, key func(T) (string, string)) ([]Change, error) {
+	type id struct{ provider, name string }
+	index := func(items []T) map[id]T {
+		m := make(map[id]T, len(items))
+		for _, item := range items {
+			provider, name := key(item)
+			m[id{provider, name}] = item
+		}
+		return m
+	}
+	before, after := index(saved), index(current)
+
+	ids := slices.Collect(maps.Keys(before))
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			ids = append(ids, k)
+		}
+	}
+	slices.SortFunc(ids, func(a, b id) int {
+		return cmp.Or(cmp.Compare(a.provider, b.provider), cmp.Compare(a.name, b.name))
+	})
+
+	var changes []Change
+	for _, k := range ids {
+		old, inSaved := before[k]
+		cur, inCurrent := after[k]
+		change := Change{Resource: resource, Provider: k.provider, Name: k.name}
+		switch {
+		case !inCurrent:
+			change.Kind = ChangeRemoved
+		case !inSaved:
+			change.Kind = ChangeAdded
+		default:
+			fields, err := diffFields(old, cur)
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			change.Kind = ChangeModified
+			change.Fields = fields
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// diffFields compares two resources value by value, through their JSON form.
+func diffFields(before, after any) ([]FieldChange, error) {
+	old, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := slices.Collect(maps.Keys(old))
+	for f := range cur {
+		if _, ok := old[f]; !ok {
+			fields = append(fields, f)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []FieldChange
+	for _, f := range fields {
+		if old[f] != cur[f] {
+			changes = append(changes, FieldChange{Field: f, Before: old[f], After: cur[f]})
+		}
+	}
+	return changes, nil
+}
+
+// flatten returns the leaf values of v's JSON form by path, leaving out the
+// volatile top-level fields.
+func flatten(v any) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// Numbers are kept as written, so large ones aren't shown in exponent form
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree map[string]any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	for f := range volatileFields {
+		delete(tree, f)
+	}
+	leaves := make(map[string]string)
+	flattenValue(tree, "", leaves)
+	return leaves, nil
+}
+
+func flattenValue(v any, path string, leaves map[string]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if path == "" {
+				flattenValue(child, key, leaves)
+			} else {
+				flattenValue(child, path+"."+key, leaves)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			flattenValue(child, fmt.Sprintf("%s[%d]", path, i), leaves)
+		}
+	case nil:
+	default:
+		leaves[path] = fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,157 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	buckets     []storage.Bucket
+	described   map[string]storage.Bucket
+	instances   []sql.Instance
+	describeErr error
+	providers   []string
+}
+
+func (f *fakeSource) ListAllBuckets(_ context.Context, providers []string) ([]storage.Bucket, error) {
+	f.providers = providers
+	return f.buckets, nil
+}
+
+func (f *fakeSource) DescribeBucket(_ context.Context, name, _ string) (storage.Bucket, error) {
+	if f.describeErr != nil {
+		return storage.Bucket{}, f.describeErr
+	}
+	return f.described[name], nil
+}
+
+func (f *fakeSource) ListAllInstances(context.Context, []string) ([]sql.Instance, error) {
+	return f.instances, nil
+}
+
+func TestTake_DescribesBuckets(t *testing.T) {
+	src := &fakeSource{
+		buckets: []storage.Bucket{
+			{Name: "b", Provider: domain.GCP, Project: "proj"},
+			{Name: "a", Provider: domain.GCP},
+		},
+		described: map[string]storage.Bucket{
+			"a": {Name: "a", Provider: domain.GCP, Versioning: &storage.Versioning{Enabled: true}},
+			"b": {Name: "b", Provider: domain.GCP},
+		},
+		instances: []sql.Instance{{Name: "db", Provider: domain.GCP}},
+	}
+
+	snap, err := Take(context.Background(), src, src, []string{"GCP", "gcp"}, []string{"gcp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(src.providers, []string{"gcp"}) {
+		t.Errorf("expected providers to be normalized, got %v", src.providers)
+	}
+	if len(snap.Buckets) != 2 || snap.Buckets[0].Name != "a" || snap.Buckets[0].Versioning == nil {
+		t.Errorf("expected the described buckets in name order, got %+v", snap.Buckets)
+	}
+	if snap.Buckets[1].Project != "proj" {
+		t.Errorf("expected the listed project to be kept, got %q", snap.Buckets[1].Project)
+	}
+	if len(snap.Instances) != 1 {
+		t.Errorf("expected 1 instance, got %d", len(snap.Instances))
+	}
+}
+
+func TestTake_DescribeFailureFailsSnapshot(t *testing.T) {
+	src := &fakeSource{
+		buckets:     []storage.Bucket{{Name: "a", Provider: domain.GCP}},
+		describeErr: errors.New("access denied"),
+	}
+	if _, err := Take(context.Background(), src, src, []string{"gcp"}, nil); err == nil {
+		t.Error("expected the describe failure to fail the snapshot")
+	}
+}
+
+func TestReadWrite_RoundTrip(t *testing.T) {
+	snap := Snapshot{
+		Version:          Version,
+		TakenAt:          time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		StorageProviders: []string{"aws"},
+		Buckets:          []storage.Bucket{{Name: "a", Provider: domain.AWS, Labels: map[string]string{"team": "data"}}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, snap); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := Diff(snap, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("expected a read snapshot to match the written one, got %+v", report.Changes)
+	}
+}
+
+func TestRead_RejectsOtherVersions(t *testing.T) {
+	if _, err := Read(bytes.NewBufferString(`{"version": 99}`)); err == nil {
+		t.Error("expected an unsupported version to be rejected")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	saved := Snapshot{
+		Buckets: []storage.Bucket{
+			{Name: "kept", Provider: domain.GCP, UsageBytes: 10, Versioning: &storage.Versioning{Enabled: true},
+				SoftDeletePolicy: &storage.SoftDeletePolicy{RetentionDuration: 7 * 24 * time.Hour}},
+			{Name: "gone", Provider: domain.GCP},
+			{Name: "same", Provider: domain.AWS, UsageBytes: 1},
+		},
+		Instances: []sql.Instance{{Name: "db", Provider: domain.GCP, Tier: "db-f1-micro"}},
+	}
+	current := Snapshot{
+		Buckets: []storage.Bucket{
+			{Name: "kept", Provider: domain.GCP, UsageBytes: 20, Labels: map[string]string{"team": "data"},
+				SoftDeletePolicy: &storage.SoftDeletePolicy{RetentionDuration: 30 * 24 * time.Hour}},
+			{Name: "new", Provider: domain.GCP},
+			{Name: "same", Provider: domain.AWS, UsageBytes: 2},
+		},
+		Instances: []sql.Instance{{Name: "db", Provider: domain.GCP, Tier: "db-custom-2-8192"}},
+	}
+
+	report, err := Diff(saved, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range report.Changes {
+		got = append(got, string(c.Kind)+" "+c.Resource+" "+c.Provider+"/"+c.Name)
+	}
+	want := []string{
+		"removed bucket gcp/gone",
+		"modified bucket gcp/kept",
+		"added bucket gcp/new",
+		"modified sql-instance gcp/db",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected changes %v, got %v", want, got)
+	}
+
+	fields := report.Changes[1].Fields
+	wantFields := []FieldChange{
+		{Field: "labels.team", Before: "", After: "data"},
+		{Field: "soft_delete_policy.retention_duration", Before: "604800000000000", After: "2592000000000000"},
+		{Field: "versioning.enabled", Before: "true", After: ""},
+	}
+	if !slices.Equal(fields, wantFields) {
+		t.Errorf("expected fields %+v, got %+v", wantFields, fields)
+	}
+}
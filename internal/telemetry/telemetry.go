@@ -0,0 +1,134 @@
+// Package telemetry traces commands and provider calls with OpenTelemetry.
+// Spans are exported over OTLP/HTTP when SYNKRONUS_OTEL_ENDPOINT is set;
+// otherwise the global no-op tracer makes them free.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EndpointEnv names the OTLP/HTTP collector URL, e.g. http://localhost:4318.
+// The /v1/traces path is added when the URL has none.
+const EndpointEnv = "SYNKRONUS_OTEL_ENDPOINT"
+
+const (
+	tracerName  = "synkronus"
+	serviceName = "synkronus"
+	tracesPath  = "/v1/traces"
+	// flushTimeout bounds the export at shutdown, so an unreachable collector
+	// delays the exit instead of hanging it
+	flushTimeout = 5 * time.Second
+)
+
+// Setup installs an OTLP exporting tracer provider when EndpointEnv is set
+// and returns a function that flushes and stops it. Export failures are logged
+// at debug level rather than printed, so they never mix into command output.
+// Without the variable, Setup does nothing and the returned function is a no-op.
+func Setup(ctx context.Context, logger *slog.Logger) (func() error, error) {
+	endpoint := os.Getenv(EndpointEnv)
+	if endpoint == "" {
+		return func() error { return nil }, nil
+	}
+	endpointURL, err := tracesURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpointURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Debug("Failed to export traces", "endpoint", endpointURL, "error", err)
+	}))
+	logger.Debug("Exporting traces", "endpoint", endpointURL)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// tracesURL validates a collector URL and adds the default traces path.
+func tracesURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid %s %q: must be an http or https URL", EndpointEnv, endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = tracesPath
+	}
+	return u.String(), nil
+}
+
+// Start begins a span called name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Provider tags a span with the cloud provider it calls.
+func Provider(name string) attribute.KeyValue {
+	return attribute.String("synkronus.provider", name)
+}
+
+// HTTPClient is the interface of SDK HTTP clients such as the AWS SDK's.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type tracedClient struct {
+	next HTTPClient
+}
+
+// WrapHTTPClient returns a client that records each request made through c as
+// a span. The Google client libraries trace their own requests, so this is
+// for SDKs that do not, like the AWS SDK.
+func WrapHTTPClient(c HTTPClient) HTTPClient {
+	return tracedClient{next: c}
+}
+
+func (c tracedClient) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := Start(req.Context(), "HTTP "+req.Method,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+		attribute.String("url.path", req.URL.Path),
+	)
+	resp, err := c.next.Do(req.WithContext(ctx))
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 500 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	End(span, err)
+	return resp, err
+}
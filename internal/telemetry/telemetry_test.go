@@ -0,0 +1,149 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useRecorder installs a tracer provider recording spans in memory for the test.
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func TestTracesURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{endpoint: "http://localhost:4318", want: "http://localhost:4318/v1/traces"},
+		{endpoint: "https://collector.example.com/", want: "https://collector.example.com/v1/traces"},
+		{endpoint: "http://localhost:4318/custom/traces", want: "http://localhost:4318/custom/traces"},
+		{endpoint: "localhost:4318", wantErr: true},
+		{endpoint: "grpc://localhost:4317", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := tracesURL(tt.endpoint)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("tracesURL(%q) error = %v, wantErr %v", tt.endpoint, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("tracesURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
+
+func TestSetup_DisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv(EndpointEnv, "")
+	prev := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(); err != nil {
+		t.Errorf("shutdown failed: %v", err)
+	}
+	if otel.GetTracerProvider() != prev {
+		t.Error("expected the global tracer provider to be left alone")
+	}
+}
+
+func TestSetup_ExportsSpans(t *testing.T) {
+	var requests atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+			requests.Add(1)
+		}
+	}))
+	defer collector.Close()
+	t.Setenv(EndpointEnv, collector.URL)
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	shutdown, err := Setup(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	_, span := Start(context.Background(), "synkronus storage list-buckets")
+	span.End()
+	if err := shutdown(); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if requests.Load() == 0 {
+		t.Error("expected the span to be exported to the collector")
+	}
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	recorder := useRecorder(t)
+
+	_, span := Start(context.Background(), "op", Provider("gcp"))
+	End(span, io.ErrUnexpectedEOF)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", spans[0].Status())
+	}
+	if !hasAttribute(spans[0].Attributes(), Provider("gcp")) {
+		t.Errorf("expected provider attribute, got %v", spans[0].Attributes())
+	}
+}
+
+func TestWrapHTTPClient(t *testing.T) {
+	recorder := useRecorder(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/bucket", nil)
+	resp, err := WrapHTTPClient(http.DefaultClient).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name() != "HTTP GET" {
+		t.Errorf("unexpected span name %q", spans[0].Name())
+	}
+	if !hasAttribute(spans[0].Attributes(), attribute.Int("http.response.status_code", http.StatusServiceUnavailable)) {
+		t.Errorf("expected status code attribute, got %v", spans[0].Attributes())
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("expected a 5xx response to mark the span as failed, got %v", spans[0].Status())
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
@@ -9,13 +9,13 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"synkronus/internal/config"
-	"synkronus/internal/domain/sql"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/factory"
-	"synkronus/internal/provider/storage/shared"
-	"synkronus/internal/service"
-	"synkronus/internal/tui/ui"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 )
 
 const defaultTimeout = 30 * time.Second
@@ -6,9 +6,9 @@ import (
 	"strings"
 	"testing"
 
-	"synkronus/internal/config"
-	"synkronus/internal/provider/storage/shared"
-	"synkronus/internal/tui/ui"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 )
 
 func TestMessageTypes(t *testing.T) {
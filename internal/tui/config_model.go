@@ -1,12 +1,10 @@
 package tui
 
 import (
-	"strings"
-
 	tea "github.com/charmbracelet/bubbletea"
 
-	"synkronus/internal/config"
-	"synkronus/internal/tui/ui"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 )
 
 // ConfigModel owns the mutable state and key/message handling for the Config tab.
@@ -87,10 +85,7 @@ func (c *ConfigModel) HandleEditKeys(msg tea.KeyMsg) (ViewUpdate, tea.Cmd) {
 // HandleConfigDeleteConfirm is invoked when Enter is pressed on the ConfigDelete overlay.
 func (c *ConfigModel) HandleConfigDeleteConfirm(cm *config.ConfigManager) (ViewUpdate, tea.Cmd) {
 	c.loading = true
-	provider := c.editKey
-	if idx := strings.IndexByte(provider, '.'); idx >= 0 {
-		provider = provider[:idx]
-	}
+	provider := config.ProviderBlockKey(c.editKey)
 	return ViewUpdate{Overlay: ptrOverlay(OverlayNone)}, removeProviderCmd(cm, provider)
 }
 
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// fuzzyMatch reports whether the characters of pattern appear in s in order,
+// ignoring case (e.g., "lgs2" matches "logs-2024"). An empty pattern matches
+// everything.
+func fuzzyMatch(pattern, s string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// editFilter applies a key typed while a filter is being edited. It returns
+// the new filter and whether editing continues: Enter keeps the filter, Esc
+// clears it, and both end editing.
+func editFilter(filter string, msg tea.KeyMsg) (string, bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		return filter, false
+	case tea.KeyEsc:
+		return "", false
+	case tea.KeyBackspace:
+		if filter == "" {
+			return filter, true
+		}
+		_, size := utf8.DecodeLastRuneInString(filter)
+		return filter[:len(filter)-size], true
+	case tea.KeySpace:
+		return filter + " ", true
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if unicode.IsPrint(r) {
+				filter += string(r)
+			}
+		}
+		return filter, true
+	}
+	return filter, true
+}
+
+// visibleBuckets returns the buckets shown in the list: those of the selected
+// provider whose name matches the filter.
+func (s *StorageModel) visibleBuckets() []storage.Bucket {
+	if s.providerFilter == "" && s.bucketFilter == "" {
+		return s.buckets
+	}
+	var visible []storage.Bucket
+	for _, b := range s.buckets {
+		if s.providerFilter != "" && !strings.EqualFold(string(b.Provider), s.providerFilter) {
+			continue
+		}
+		if fuzzyMatch(s.bucketFilter, b.Name) {
+			visible = append(visible, b)
+		}
+	}
+	return visible
+}
+
+// visibleObjects returns the listing shown, narrowed to the prefixes and
+// objects whose key matches the filter.
+func (s *StorageModel) visibleObjects() storage.ObjectList {
+	if s.objectFilter == "" {
+		return s.objects
+	}
+	visible := s.objects
+	visible.CommonPrefixes = nil
+	visible.Objects = nil
+	for _, p := range s.objects.CommonPrefixes {
+		if fuzzyMatch(s.objectFilter, strings.TrimPrefix(p, s.objects.Prefix)) {
+			visible.CommonPrefixes = append(visible.CommonPrefixes, p)
+		}
+	}
+	for _, o := range s.objects.Objects {
+		if fuzzyMatch(s.objectFilter, strings.TrimPrefix(o.Key, s.objects.Prefix)) {
+			visible.Objects = append(visible.Objects, o)
+		}
+	}
+	return visible
+}
+
+// bucketProviders returns the distinct providers of the loaded buckets, sorted.
+func (s *StorageModel) bucketProviders() []string {
+	var providers []string
+	for _, b := range s.buckets {
+		name := strings.ToLower(string(b.Provider))
+		if !slices.Contains(providers, name) {
+			providers = append(providers, name)
+		}
+	}
+	slices.Sort(providers)
+	return providers
+}
+
+// nextProviderFilter cycles the bucket list through all providers, then each
+// provider on its own.
+func (s *StorageModel) nextProviderFilter() {
+	providers := s.bucketProviders()
+	i := slices.Index(providers, s.providerFilter)
+	if i+1 < len(providers) {
+		s.providerFilter = providers[i+1]
+	} else {
+		s.providerFilter = ""
+	}
+	s.cursor = 0
+	s.scrollOffset = 0
+}
+
+// parentPrefix returns the prefix one "directory" above prefix, e.g.
+// "logs/2024/" becomes "logs/".
+func parentPrefix(prefix string) string {
+	trimmed := strings.TrimSuffix(prefix, "/")
+	i := strings.LastIndex(trimmed, "/")
+	if i < 0 {
+		return ""
+	}
+	return trimmed[:i+1]
+}
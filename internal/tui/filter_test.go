@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"", "anything", true},
+		{"logs", "logs-2024", true},
+		{"lgs24", "logs-2024", true},
+		{"LGS", "logs-2024", true},
+		{"sgol", "logs-2024", false},
+		{"logs-2025", "logs-2024", false},
+		{"é", "café", true},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestEditFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      string
+		msg         tea.KeyMsg
+		wantFilter  string
+		wantEditing bool
+	}{
+		{"typing appends", "lo", runeKey('g'), "log", true},
+		{"space appends", "a", tea.KeyMsg{Type: tea.KeySpace}, "a ", true},
+		{"backspace deletes", "log", tea.KeyMsg{Type: tea.KeyBackspace}, "lo", true},
+		{"backspace on empty", "", tea.KeyMsg{Type: tea.KeyBackspace}, "", true},
+		{"enter keeps filter", "log", tea.KeyMsg{Type: tea.KeyEnter}, "log", false},
+		{"esc clears filter", "log", tea.KeyMsg{Type: tea.KeyEsc}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, editing := editFilter(tt.filter, tt.msg)
+			if filter != tt.wantFilter || editing != tt.wantEditing {
+				t.Errorf("editFilter() = (%q, %v), want (%q, %v)", filter, editing, tt.wantFilter, tt.wantEditing)
+			}
+		})
+	}
+}
+
+func TestVisibleBuckets(t *testing.T) {
+	s := StorageModel{buckets: []storage.Bucket{
+		{Name: "logs-prod", Provider: domain.GCP},
+		{Name: "logs-dev", Provider: domain.AWS},
+		{Name: "assets", Provider: domain.AWS},
+	}}
+
+	if got := s.visibleBuckets(); len(got) != 3 {
+		t.Errorf("expected all buckets without filters, got %v", got)
+	}
+	s.bucketFilter = "lgs"
+	if got := s.visibleBuckets(); len(got) != 2 {
+		t.Errorf("expected the two logs buckets, got %v", got)
+	}
+	s.providerFilter = "aws"
+	if got := s.visibleBuckets(); len(got) != 1 || got[0].Name != "logs-dev" {
+		t.Errorf("expected only logs-dev, got %v", got)
+	}
+}
+
+func TestVisibleObjects_MatchesBelowPrefix(t *testing.T) {
+	s := StorageModel{
+		objects: storage.ObjectList{
+			Prefix:         "data/",
+			CommonPrefixes: []string{"data/archive/", "data/daily/"},
+			Objects:        []storage.Object{{Key: "data/report.csv"}, {Key: "data/readme.md"}},
+		},
+		objectFilter: "ai",
+	}
+
+	got := s.visibleObjects()
+	if len(got.CommonPrefixes) != 2 || len(got.Objects) != 0 {
+		t.Errorf("expected only the entries matching %q below the prefix, got %+v", s.objectFilter, got)
+	}
+	if len(s.objects.Objects) != 2 {
+		t.Error("filtering must not modify the loaded listing")
+	}
+}
+
+func TestNextProviderFilter_Cycles(t *testing.T) {
+	s := StorageModel{buckets: []storage.Bucket{{Provider: domain.GCP}, {Provider: domain.AWS}, {Provider: domain.GCP}}}
+
+	for _, want := range []string{"aws", "gcp", ""} {
+		s.nextProviderFilter()
+		if s.providerFilter != want {
+			t.Errorf("providerFilter = %q, want %q", s.providerFilter, want)
+		}
+	}
+}
+
+func TestParentPrefix(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		"logs/":      "",
+		"logs/2024/": "logs/",
+		"a/b/c/":     "a/b/",
+	}
+	for prefix, want := range tests {
+		if got := parentPrefix(prefix); got != want {
+			t.Errorf("parentPrefix(%q) = %q, want %q", prefix, got, want)
+		}
+	}
+}
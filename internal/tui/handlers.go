@@ -14,13 +14,18 @@ import (
 func (m *Model) handleViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
-	// Global keys
-	switch key {
-	case keyCtrlC, "q":
+	// Global keys; while a list filter is typed, only Ctrl+C is
+	if key == keyCtrlC {
 		return m, tea.Quit
-	case "h":
-		m.overlay = OverlayHelp
-		return m, nil
+	}
+	if !m.storage.filtering {
+		switch key {
+		case "q":
+			return m, tea.Quit
+		case "h":
+			m.overlay = OverlayHelp
+			return m, nil
+		}
 	}
 
 	// View-specific dispatch to sub-models
@@ -8,12 +8,13 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
-	"synkronus/internal/config"
-	"synkronus/internal/domain"
-	domainsql "synkronus/internal/domain/sql"
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/factory"
-	"synkronus/internal/tui/ui"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 )
 
 // newTestModel returns a minimal Model suitable for handler unit tests.
@@ -32,8 +33,11 @@ func newTestModelWithConfig(t *testing.T) Model {
 	}
 	f := factory.NewFactory(&config.Config{}, slog.Default())
 	return NewModel(Deps{
-		ConfigManager: cm,
-		Factory:       f,
+		ConfigManager:  cm,
+		Factory:        f,
+		StorageService: service.NewStorageService(f, slog.Default()),
+		SqlService:     service.NewSqlService(f, slog.Default()),
+		Logger:         slog.Default(),
 	})
 }
 
@@ -1120,3 +1124,74 @@ func TestSyncTextInputToField_DownloadPath(t *testing.T) {
 		t.Errorf("downloadDir = %q, want %q", m.storage.downloadDir, "/tmp/my-downloads")
 	}
 }
+
+// --- filtering and browse start ---
+
+func TestHandleStorageListKeys_FilterNarrowsSelection(t *testing.T) {
+	m := newTestModel()
+	m.viewState = ViewStorageList
+	m.storage.buckets = []storage.Bucket{
+		{Name: "assets", Provider: domain.GCP},
+		{Name: "logs-prod", Provider: domain.GCP},
+	}
+
+	for _, msg := range []tea.KeyMsg{runeKey('/'), runeKey('q'), {Type: tea.KeyBackspace}, runeKey('l'), runeKey('o'), {Type: tea.KeyEnter}} {
+		if _, cmd := m.handleViewKeys(msg); cmd != nil {
+			t.Fatalf("key %q while filtering should not produce a cmd", msg.String())
+		}
+	}
+	if m.storage.bucketFilter != "lo" || m.storage.filtering {
+		t.Fatalf("expected filter %q kept after Enter, got %q (filtering %v)", "lo", m.storage.bucketFilter, m.storage.filtering)
+	}
+
+	_, _ = m.handleStorageListKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.storage.selectedBucket.Name != "logs-prod" {
+		t.Errorf("selectedBucket.Name = %q, want %q", m.storage.selectedBucket.Name, "logs-prod")
+	}
+}
+
+func TestHandleObjectListKeys_BackspaceOpensParentPrefix(t *testing.T) {
+	m := newTestModel()
+	m.viewState = ViewStorageObjectList
+	m.storage.selectedBucket = storage.Bucket{Name: "test-bucket", Provider: domain.GCP}
+	m.storage.objects = storage.ObjectList{Prefix: "logs/2024/"}
+	m.storage.objectFilter = "x"
+
+	_, cmd := m.handleObjectListKeys(tea.KeyMsg{Type: tea.KeyBackspace})
+
+	if cmd == nil || !m.storage.loading {
+		t.Error("expected the parent prefix to be fetched")
+	}
+	if m.storage.objectFilter != "" {
+		t.Errorf("expected the filter to be cleared, got %q", m.storage.objectFilter)
+	}
+}
+
+func TestHandleObjectListKeys_EscClearsFilterFirst(t *testing.T) {
+	m := newTestModel()
+	m.viewState = ViewStorageObjectList
+	m.storage.objectFilter = "x"
+
+	_, _ = m.handleObjectListKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.storage.objectFilter != "" || m.viewState != ViewStorageObjectList {
+		t.Fatalf("expected Esc to clear the filter and stay, got filter %q, view %d", m.storage.objectFilter, m.viewState)
+	}
+	_, _ = m.handleObjectListKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.viewState != ViewStorageBucketDetail {
+		t.Errorf("viewState = %d, want ViewStorageBucketDetail (%d)", m.viewState, ViewStorageBucketDetail)
+	}
+}
+
+func TestNewModel_BrowseStart(t *testing.T) {
+	m := NewModel(Deps{Browse: BrowseStart{Provider: "aws", Bucket: "my-bucket", Prefix: "logs/"}})
+
+	if m.viewState != ViewStorageObjectList || !m.storage.loading {
+		t.Errorf("expected to open on the loading object list, got view %d", m.viewState)
+	}
+	if m.storage.selectedBucket.Name != "my-bucket" || m.storage.selectedBucket.Provider != domain.AWS {
+		t.Errorf("unexpected selected bucket: %+v", m.storage.selectedBucket)
+	}
+	if m.storage.providerFilter != "aws" {
+		t.Errorf("providerFilter = %q, want %q", m.storage.providerFilter, "aws")
+	}
+}
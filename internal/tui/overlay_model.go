@@ -5,8 +5,9 @@ import (
 	"path/filepath"
 	"strings"
 
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/tui/ui"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -239,10 +240,7 @@ func (m *Model) handleConfigDeleteConfirm() (tea.Model, tea.Cmd) {
 	m.overlay = OverlayNone
 	m.config.loading = true
 
-	provider := m.config.editKey
-	if idx := strings.IndexByte(provider, '.'); idx >= 0 {
-		provider = provider[:idx]
-	}
+	provider := config.ProviderBlockKey(m.config.editKey)
 	return m, removeProviderCmd(m.deps.ConfigManager, provider)
 }
 
@@ -5,9 +5,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
-	domainsql "synkronus/internal/domain/sql"
-	"synkronus/internal/service"
-	"synkronus/internal/tui/ui"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
+	"github.com/jkleinne/synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 )
 
 // SqlModel owns the mutable state and key/message handling for the SQL tab.
@@ -6,10 +6,10 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
-	"synkronus/internal/domain/storage"
-	"synkronus/internal/provider/storage/shared"
-	"synkronus/internal/service"
-	"synkronus/internal/tui/ui"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/storage/shared"
+	"github.com/jkleinne/synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 )
 
 // StorageModel owns the mutable state and key/message handling for the Storage tab.
@@ -23,6 +23,14 @@ type StorageModel struct {
 	loading        bool
 	loaded         bool
 
+	// Filter state: providerFilter narrows the bucket list to one provider;
+	// bucketFilter and objectFilter fuzzy-match names and keys. filtering is
+	// set while the filter of the current list is being typed.
+	providerFilter string
+	bucketFilter   string
+	objectFilter   string
+	filtering      bool
+
 	// Create-bucket form fields
 	createName                   string
 	createProvider               string
@@ -54,19 +62,27 @@ type StorageModel struct {
 // HandleListKeys handles keystrokes on the bucket list view.
 // It returns updated root-level fields (viewState, overlay, err) via the returned ViewUpdate.
 func (s *StorageModel) HandleListKeys(msg tea.KeyMsg, svc *service.StorageService, deps *Deps) (ViewUpdate, tea.Cmd) {
+	if s.filtering {
+		s.bucketFilter, s.filtering = editFilter(s.bucketFilter, msg)
+		s.cursor = 0
+		s.scrollOffset = 0
+		return ViewUpdate{}, nil
+	}
+
+	buckets := s.visibleBuckets()
 	key := msg.String()
 	switch key {
 	case "j", keyDown:
-		if len(s.buckets) > 0 {
-			s.cursor = min(s.cursor+1, len(s.buckets)-1)
+		if len(buckets) > 0 {
+			s.cursor = min(s.cursor+1, len(buckets)-1)
 		}
 	case "k", keyUp:
 		if s.cursor > 0 {
 			s.cursor--
 		}
 	case keyEnter:
-		if len(s.buckets) > 0 {
-			s.selectedBucket = s.buckets[s.cursor]
+		if len(buckets) > 0 {
+			s.selectedBucket = buckets[s.cursor]
 			return ViewUpdate{ViewState: ptrViewState(ViewStorageBucketDetail)}, fetchBucketDetailCmd(
 				svc,
 				s.selectedBucket.Name,
@@ -74,10 +90,11 @@ func (s *StorageModel) HandleListKeys(msg tea.KeyMsg, svc *service.StorageServic
 			)
 		}
 	case "o":
-		if len(s.buckets) > 0 {
-			s.selectedBucket = s.buckets[s.cursor]
+		if len(buckets) > 0 {
+			s.selectedBucket = buckets[s.cursor]
 			s.cursor = 0
 			s.scrollOffset = 0
+			s.objectFilter = ""
 			s.loading = true
 			return ViewUpdate{ViewState: ptrViewState(ViewStorageObjectList), ClearErr: true}, fetchObjectsCmd(
 				svc,
@@ -90,8 +107,8 @@ func (s *StorageModel) HandleListKeys(msg tea.KeyMsg, svc *service.StorageServic
 		s.resetCreateForm(deps)
 		return ViewUpdate{Overlay: ptrOverlay(OverlayCreateBucket), FocusTextInput: true, TextInputValue: ptrString("")}, nil
 	case "d":
-		if len(s.buckets) > 0 {
-			s.selectedBucket = s.buckets[s.cursor]
+		if len(buckets) > 0 {
+			s.selectedBucket = buckets[s.cursor]
 			s.deleteKind = deleteTargetBucket
 			s.deleteInput = ""
 			return ViewUpdate{Overlay: ptrOverlay(OverlayDeleteConfirm), FocusTextInput: true, TextInputValue: ptrString("")}, nil
@@ -99,6 +116,15 @@ func (s *StorageModel) HandleListKeys(msg tea.KeyMsg, svc *service.StorageServic
 	case "r":
 		s.loading = true
 		return ViewUpdate{ClearErr: true}, fetchBucketsCmd(svc, deps.Factory)
+	case "/":
+		s.filtering = true
+	case "p":
+		s.nextProviderFilter()
+	case keyEsc:
+		s.bucketFilter = ""
+		s.providerFilter = ""
+		s.cursor = 0
+		s.scrollOffset = 0
 	case keyTab:
 		return ViewUpdate{SwitchTab: ptrTab(TabStorage.Next())}, nil
 	case keyShiftTab:
@@ -117,6 +143,7 @@ func (s *StorageModel) HandleBucketDetailKeys(msg tea.KeyMsg, svc *service.Stora
 		s.loading = true
 		s.cursor = 0
 		s.scrollOffset = 0
+		s.objectFilter = ""
 		return ViewUpdate{ViewState: ptrViewState(ViewStorageObjectList), ClearErr: true}, fetchObjectsCmd(
 			svc,
 			s.selectedBucket.Name,
@@ -139,8 +166,16 @@ func (s *StorageModel) HandleBucketDetailKeys(msg tea.KeyMsg, svc *service.Stora
 
 // HandleObjectListKeys handles keystrokes on the object list view.
 func (s *StorageModel) HandleObjectListKeys(msg tea.KeyMsg, svc *service.StorageService) (ViewUpdate, tea.Cmd) {
+	if s.filtering {
+		s.objectFilter, s.filtering = editFilter(s.objectFilter, msg)
+		s.cursor = 0
+		s.scrollOffset = 0
+		return ViewUpdate{}, nil
+	}
+
+	objects := s.visibleObjects()
 	key := msg.String()
-	totalItems := len(s.objects.Objects) + len(s.objects.CommonPrefixes)
+	totalItems := len(objects.Objects) + len(objects.CommonPrefixes)
 
 	switch key {
 	case "j", keyDown:
@@ -153,12 +188,13 @@ func (s *StorageModel) HandleObjectListKeys(msg tea.KeyMsg, svc *service.Storage
 		}
 	case keyEnter:
 		if totalItems > 0 {
-			prefixCount := len(s.objects.CommonPrefixes)
+			prefixCount := len(objects.CommonPrefixes)
 			if s.cursor < prefixCount {
-				prefix := s.objects.CommonPrefixes[s.cursor]
+				prefix := objects.CommonPrefixes[s.cursor]
 				s.loading = true
 				s.cursor = 0
 				s.scrollOffset = 0
+				s.objectFilter = ""
 				return ViewUpdate{ClearErr: true}, fetchObjectsCmd(
 					svc,
 					s.selectedBucket.Name,
@@ -167,8 +203,8 @@ func (s *StorageModel) HandleObjectListKeys(msg tea.KeyMsg, svc *service.Storage
 				)
 			}
 			objIdx := s.cursor - prefixCount
-			if objIdx < len(s.objects.Objects) {
-				s.selectedObject = s.objects.Objects[objIdx]
+			if objIdx < len(objects.Objects) {
+				s.selectedObject = objects.Objects[objIdx]
 				s.loading = true
 				return ViewUpdate{ViewState: ptrViewState(ViewStorageObjectDetail), ClearErr: true}, fetchObjectDetailCmd(
 					svc,
@@ -180,11 +216,11 @@ func (s *StorageModel) HandleObjectListKeys(msg tea.KeyMsg, svc *service.Storage
 		}
 	case "w":
 		if totalItems > 0 {
-			prefixCount := len(s.objects.CommonPrefixes)
+			prefixCount := len(objects.CommonPrefixes)
 			if s.cursor >= prefixCount {
 				objIdx := s.cursor - prefixCount
-				if objIdx < len(s.objects.Objects) {
-					obj := s.objects.Objects[objIdx]
+				if objIdx < len(objects.Objects) {
+					obj := objects.Objects[objIdx]
 					s.downloadingKey = obj.Key
 					s.downloadDir = defaultDownloadDir
 					return ViewUpdate{
@@ -202,11 +238,11 @@ func (s *StorageModel) HandleObjectListKeys(msg tea.KeyMsg, svc *service.Storage
 		return ViewUpdate{Overlay: ptrOverlay(OverlayUploadObject), FocusTextInput: true, TextInputValue: ptrString("")}, nil
 	case "d":
 		if totalItems > 0 {
-			prefixCount := len(s.objects.CommonPrefixes)
+			prefixCount := len(objects.CommonPrefixes)
 			if s.cursor >= prefixCount {
 				objIdx := s.cursor - prefixCount
-				if objIdx < len(s.objects.Objects) {
-					obj := s.objects.Objects[objIdx]
+				if objIdx < len(objects.Objects) {
+					obj := objects.Objects[objIdx]
 					s.deleteObjectKey = obj.Key
 					s.deleteKind = deleteTargetObject
 					s.deleteInput = ""
@@ -214,9 +250,28 @@ func (s *StorageModel) HandleObjectListKeys(msg tea.KeyMsg, svc *service.Storage
 				}
 			}
 		}
+	case "/":
+		s.filtering = true
+	case "backspace":
+		if s.objects.Prefix != "" {
+			s.loading = true
+			s.cursor = 0
+			s.scrollOffset = 0
+			s.objectFilter = ""
+			return ViewUpdate{ClearErr: true}, fetchObjectsCmd(
+				svc,
+				s.selectedBucket.Name,
+				strings.ToLower(string(s.selectedBucket.Provider)),
+				parentPrefix(s.objects.Prefix),
+			)
+		}
 	case keyEsc:
 		s.cursor = 0
 		s.scrollOffset = 0
+		if s.objectFilter != "" {
+			s.objectFilter = ""
+			return ViewUpdate{}, nil
+		}
 		return ViewUpdate{ViewState: ptrViewState(ViewStorageBucketDetail), ClearErr: true}, nil
 	}
 	return ViewUpdate{}, nil
@@ -373,7 +428,9 @@ func (s *StorageModel) RenderContent(viewState ViewState, spinnerView string, wi
 		if s.loading {
 			return ui.CenterContent(ui.RenderSpinnerView(spinnerView, "Loading buckets..."), width)
 		}
-		return ui.RenderBucketList(s.buckets, s.cursor, s.scrollOffset, width)
+		buckets := s.visibleBuckets()
+		return s.renderFilterBar(s.bucketFilter, s.providerFilter, len(buckets), len(s.buckets), width) +
+			ui.RenderBucketList(buckets, s.cursor, s.scrollOffset, width)
 
 	case ViewStorageBucketDetail:
 		if s.loading {
@@ -389,7 +446,11 @@ func (s *StorageModel) RenderContent(viewState ViewState, spinnerView string, wi
 			}
 			return ui.CenterContent(ui.RenderSpinnerView(spinnerView, spinnerMsg), width)
 		}
-		return ui.RenderObjectList(s.objects, s.cursor, s.scrollOffset, width)
+		objects := s.visibleObjects()
+		shown := len(objects.Objects) + len(objects.CommonPrefixes)
+		total := len(s.objects.Objects) + len(s.objects.CommonPrefixes)
+		return s.renderFilterBar(s.objectFilter, "", shown, total, width) +
+			ui.RenderObjectList(objects, s.cursor, s.scrollOffset, width)
 
 	case ViewStorageObjectDetail:
 		if s.loading {
@@ -406,6 +467,15 @@ func (s *StorageModel) RenderContent(viewState ViewState, spinnerView string, wi
 	}
 }
 
+// renderFilterBar returns the filter line shown above a list while it is
+// filtered, or an empty string.
+func (s *StorageModel) renderFilterBar(filter, provider string, shown, total, width int) string {
+	if !s.filtering && filter == "" && provider == "" {
+		return ""
+	}
+	return ui.RenderFilterBar(filter, provider, s.filtering, shown, total, width) + "\n"
+}
+
 // --- Create-bucket form helpers ---
 
 // resetCreateForm clears all create-bucket form fields and sets provider defaults.
@@ -2,6 +2,7 @@
 package tui
 
 import (
+	"errors"
 	"log/slog"
 	"strings"
 
@@ -9,10 +10,12 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
-	"synkronus/internal/config"
-	"synkronus/internal/provider/factory"
-	"synkronus/internal/service"
-	"synkronus/internal/tui/ui"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/service"
+	"github.com/jkleinne/synkronus/internal/tui/ui"
 )
 
 // Deps holds the external dependencies injected into the TUI from the CLI layer.
@@ -23,6 +26,18 @@ type Deps struct {
 	Config         *config.Config
 	Factory        *factory.Factory
 	Logger         *slog.Logger
+	// Browse opens the storage browser somewhere other than the full bucket list
+	Browse BrowseStart
+}
+
+// BrowseStart is where 'storage browse' opens the TUI. The zero value opens
+// the full bucket list.
+type BrowseStart struct {
+	// Provider narrows the bucket list to one provider
+	Provider string
+	// Bucket opens the bucket's objects under Prefix; it requires Provider
+	Bucket string
+	Prefix string
 }
 
 // Run creates the Bubble Tea model and starts the interactive TUI program.
@@ -71,7 +86,7 @@ func NewModel(deps Deps) Model {
 	ti := textinput.New()
 	ti.CharLimit = textInputCharLimit
 
-	return Model{
+	m := Model{
 		deps: deps,
 
 		viewState: ViewStorageList,
@@ -81,12 +96,27 @@ func NewModel(deps Deps) Model {
 		spinner:   s,
 		textInput: ti,
 	}
+	m.storage.providerFilter = strings.ToLower(deps.Browse.Provider)
+	if deps.Browse.Bucket != "" {
+		m.viewState = ViewStorageObjectList
+		m.storage.selectedBucket = storage.Bucket{
+			Name:     deps.Browse.Bucket,
+			Provider: domain.Provider(strings.ToUpper(deps.Browse.Provider)),
+		}
+		m.storage.loading = true
+	}
+	return m
 }
 
-// Init implements tea.Model. It starts the spinner and triggers the initial bucket fetch.
+// Init implements tea.Model. It starts the spinner and triggers the initial bucket
+// fetch, and the object fetch when the browser opens at a bucket.
 func (m Model) Init() tea.Cmd {
 	m.storage.loading = true
-	return tea.Batch(m.spinner.Tick, fetchBucketsCmd(m.deps.StorageService, m.deps.Factory))
+	cmds := []tea.Cmd{m.spinner.Tick, fetchBucketsCmd(m.deps.StorageService, m.deps.Factory)}
+	if browse := m.deps.Browse; browse.Bucket != "" {
+		cmds = append(cmds, fetchObjectsCmd(m.deps.StorageService, browse.Bucket, strings.ToLower(browse.Provider), browse.Prefix))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update implements tea.Model. It dispatches incoming messages to the appropriate handler.
@@ -296,6 +326,9 @@ func (m *Model) applyViewUpdate(vu ViewUpdate) {
 
 // refreshFactoryConfig reloads the config from disk and updates the factory
 // so provider status checks and queries reflect the latest configuration.
+// Provider clients cached by the services were built from the old config, so
+// they are dropped and recreated on next use; those still used by in-flight
+// commands are closed once the commands are done with them.
 func (m *Model) refreshFactoryConfig() {
 	cfg, err := m.deps.ConfigManager.LoadConfig()
 	if err != nil {
@@ -303,4 +336,8 @@ func (m *Model) refreshFactoryConfig() {
 	}
 	m.deps.Config = cfg
 	m.deps.Factory.UpdateConfig(cfg)
+
+	if err := errors.Join(m.deps.StorageService.Shutdown(), m.deps.SqlService.Shutdown()); err != nil {
+		m.deps.Logger.Debug("Failed to close provider clients after config change", "error", err)
+	}
 }
@@ -202,6 +202,25 @@ func RenderSpinnerView(spinnerFrame, label string) string {
 	return SpinnerStyle.Render(spinnerFrame) + " " + TextSecondaryStyle.Render(label)
 }
 
+// RenderFilterBar renders the line above a filtered list: the fuzzy filter
+// (with a cursor while it is being typed), the provider filter, and how many
+// of the entries are shown.
+func RenderFilterBar(filter, provider string, editing bool, shown, total, termWidth int) string {
+	var parts []string
+	if filter != "" || editing {
+		text := "/" + filter
+		if editing {
+			text += "_"
+		}
+		parts = append(parts, TextPrimaryStyle.Render(text))
+	}
+	if provider != "" {
+		parts = append(parts, TextSecondaryStyle.Render("provider: ")+ProviderStyle.Render(provider))
+	}
+	parts = append(parts, TextDimStyle.Render(fmt.Sprintf("%d of %d", shown, total)))
+	return lipgloss.PlaceHorizontal(termWidth, lipgloss.Center, strings.Join(parts, TextDimStyle.Render("  ·  ")))
+}
+
 // RenderKeyHints renders the bottom keybinding hint bar for a given context.
 func RenderKeyHints(ctx BindingContext, termWidth int) string {
 	hints := FormatHints(ctx)
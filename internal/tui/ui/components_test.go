@@ -197,3 +197,15 @@ func TestRenderTableEmptyHeaders(t *testing.T) {
 		t.Errorf("expected empty string for empty headers, got %q", result)
 	}
 }
+
+func TestRenderFilterBar(t *testing.T) {
+	result := RenderFilterBar("lgs", "aws", true, 2, 10, 80)
+	for _, want := range []string{"/lgs_", "provider: ", "aws", "2 of 10"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("filter bar missing %q: %q", want, result)
+		}
+	}
+	if strings.Contains(RenderFilterBar("", "gcp", false, 3, 3, 80), "/") {
+		t.Error("filter bar should omit the filter when none is set")
+	}
+}
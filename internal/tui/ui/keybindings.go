@@ -33,6 +33,8 @@ var bindings = []Binding{
 	{Key: "o", Description: "Objects", Context: ContextStorageList},
 	{Key: "c", Description: "Create", Context: ContextStorageList},
 	{Key: "d", Description: "Delete", Context: ContextStorageList},
+	{Key: "/", Description: "Filter", Context: ContextStorageList},
+	{Key: "p", Description: "Provider", Context: ContextStorageList},
 	{Key: "j/k", Description: "Navigate", Context: ContextStorageList},
 	{Key: "r", Description: "Refresh", Context: ContextStorageList},
 	{Key: "Tab", Description: "Next Tab", Context: ContextStorageList},
@@ -70,6 +72,8 @@ var bindings = []Binding{
 	{Key: "w", Description: "Download", Context: ContextObjectList},
 	{Key: "u", Description: "Upload", Context: ContextObjectList},
 	{Key: "d", Description: "Delete", Context: ContextObjectList},
+	{Key: "/", Description: "Filter", Context: ContextObjectList},
+	{Key: "Backspace", Description: "Up", Context: ContextObjectList},
 	{Key: "Esc", Description: "Back", Context: ContextObjectList},
 	{Key: "q", Description: "Quit", Context: ContextObjectList},
 
@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
-	domainsql "synkronus/internal/domain/sql"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
 )
 
 // RenderInstanceList renders the SQL instance table with cursor and scroll.
@@ -5,7 +5,7 @@ import (
 	"testing"
 	"time"
 
-	domainsql "synkronus/internal/domain/sql"
+	domainsql "github.com/jkleinne/synkronus/internal/domain/sql"
 )
 
 func TestRenderInstanceListWithData(t *testing.T) {
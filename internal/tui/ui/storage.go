@@ -6,7 +6,7 @@ import (
 	"strings"
 	"time"
 
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 // RenderBucketList renders the bucket table with cursor and scroll.
@@ -6,7 +6,7 @@ import (
 	"testing"
 	"time"
 
-	"synkronus/internal/domain/storage"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
 )
 
 func TestRenderBucketListWithData(t *testing.T) {
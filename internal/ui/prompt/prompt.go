@@ -6,48 +6,141 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Returned by Confirm when the input is not a terminal (e.g., in a CI pipeline),
+// so the command fails instead of waiting for an answer that never comes
+var ErrNonInteractive = errors.New("confirmation required, but input is not a terminal")
+
+// Selects how a destructive operation is confirmed
+type Mode string
+
+const (
+	// Requires typing the resource's name, for operations that are hard to undo
+	ModeTyped Mode = "typed"
+	// Asks a y/N question, for lower-risk operations
+	ModeYesNo Mode = "yes-no"
 )
 
 // Defines the interface for prompting the user for input
 type Prompter interface {
 	// Asks the user for confirmation by requiring them to type a specific expected value
 	Confirm(message string, expectedValue string) (bool, error)
+	// Asks the user a y/N question; anything but "y" or "yes" declines
+	ConfirmYesNo(message string) (bool, error)
+	// Asks the user a free-form question; an empty answer takes defaultValue
+	Ask(question, defaultValue string) (string, error)
 }
 
 // Provides a standard implementation of the Prompter interface using specified input/output streams
 type StandardPrompter struct {
-	reader io.Reader
-	writer io.Writer
+	// Buffered once, so input read ahead by one question is kept for the next
+	reader      *bufio.Reader
+	writer      io.Writer
+	interactive bool
 }
 
 // Creates a new StandardPrompter with the given input and output streams
 func NewStandardPrompter(in io.Reader, out io.Writer) *StandardPrompter {
 	return &StandardPrompter{
-		reader: in,
-		writer: out,
+		reader:      bufio.NewReader(in),
+		writer:      out,
+		interactive: isInteractive(in),
 	}
 }
 
+// Reports whether someone can answer prompts on in: a terminal, or a reader that
+// is not a file, as in tests
+func isInteractive(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok {
+		return true
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
 // Asks the user for confirmation by requiring them to type a specific expected value
 func (p *StandardPrompter) Confirm(message string, expectedValue string) (bool, error) {
 	if expectedValue == "" {
 		return false, fmt.Errorf("expected confirmation value cannot be empty")
 	}
+	if !p.interactive {
+		return false, ErrNonInteractive
+	}
 
 	fmt.Fprintln(p.writer, message)
 	fmt.Fprintf(p.writer, "To confirm, please type the name '%s': ", expectedValue)
 
-	reader := bufio.NewReader(p.reader)
-	input, err := reader.ReadString('\n')
+	input, err := p.readLine()
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
 	if err != nil {
-		if errors.Is(err, io.EOF) {
-			return false, nil
-		}
-		return false, fmt.Errorf("error reading user input: %w", err)
+		return false, err
 	}
+	return input == expectedValue, nil
+}
 
-	cleanedInput := strings.TrimSpace(input)
+// Asks the user a y/N question; anything but "y" or "yes" declines
+func (p *StandardPrompter) ConfirmYesNo(message string) (bool, error) {
+	if !p.interactive {
+		return false, ErrNonInteractive
+	}
 
-	return cleanedInput == expectedValue, nil
+	fmt.Fprintln(p.writer, message)
+	fmt.Fprint(p.writer, "Proceed? [y/N]: ")
+
+	input, err := p.readLine()
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(input) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Asks the user a free-form question; an empty answer takes defaultValue.
+// Returns io.EOF if the input ends first.
+func (p *StandardPrompter) Ask(question, defaultValue string) (string, error) {
+	if !p.interactive {
+		return "", ErrNonInteractive
+	}
+
+	if defaultValue != "" {
+		fmt.Fprintf(p.writer, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(p.writer, "%s: ", question)
+	}
+
+	input, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	if input == "" {
+		return defaultValue, nil
+	}
+	return input, nil
+}
+
+// Reads one line of input, trimmed. Returns io.EOF if the input ends before a
+// full line.
+func (p *StandardPrompter) readLine() (string, error) {
+	input, err := p.reader.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return "", io.EOF
+		}
+		return "", fmt.Errorf("error reading user input: %w", err)
+	}
+	return strings.TrimSpace(input), nil
 }
@@ -2,7 +2,10 @@ package prompt
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"testing"
 )
@@ -110,3 +113,81 @@ type errorReader struct {
 func (e *errorReader) Read(p []byte) (int, error) {
 	return 0, e.err
 }
+
+func TestConfirm_NonTerminalInput_FailsFast(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("creating pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	output := &bytes.Buffer{}
+	p := NewStandardPrompter(r, output)
+
+	// Nothing is ever written to the pipe, so reading it would block
+	confirmed, err := p.Confirm("Delete?", "my-bucket")
+	if !errors.Is(err, ErrNonInteractive) {
+		t.Fatalf("expected ErrNonInteractive, got: %v", err)
+	}
+	if confirmed {
+		t.Error("expected no confirmation from non-terminal input")
+	}
+	if output.Len() != 0 {
+		t.Errorf("expected no prompt to be shown, got %q", output.String())
+	}
+}
+
+func TestConfirmYesNo(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"  yes  \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"yep\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.input), func(t *testing.T) {
+			output := &bytes.Buffer{}
+			p := NewStandardPrompter(strings.NewReader(tt.input), output)
+
+			confirmed, err := p.ConfirmYesNo("Delete object?")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if confirmed != tt.want {
+				t.Errorf("ConfirmYesNo with input %q = %v, want %v", tt.input, confirmed, tt.want)
+			}
+			if !strings.Contains(output.String(), "Delete object?\nProceed? [y/N]: ") {
+				t.Errorf("expected the question with a y/N hint, got %q", output.String())
+			}
+		})
+	}
+}
+
+func TestAsk(t *testing.T) {
+	output := &bytes.Buffer{}
+	p := NewStandardPrompter(strings.NewReader("my-bucket\n\n"), output)
+
+	answer, err := p.Ask("Bucket name", "")
+	if err != nil || answer != "my-bucket" {
+		t.Fatalf("Ask = %q, %v; want my-bucket", answer, err)
+	}
+	// The second line was buffered by the first question and is kept
+	answer, err = p.Ask("Location", "US")
+	if err != nil || answer != "US" {
+		t.Fatalf("Ask with an empty answer = %q, %v; want the default US", answer, err)
+	}
+	if !strings.Contains(output.String(), "Bucket name: ") || !strings.Contains(output.String(), "Location [US]: ") {
+		t.Errorf("unexpected questions: %q", output.String())
+	}
+
+	if _, err := p.Ask("Storage class", "STANDARD"); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF once the input ends, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/provider/factory"
+	"github.com/jkleinne/synkronus/internal/provider/registry"
+	"io"
+	"log/slog"
+	"strings"
+
+	// Registers the storage providers New can create
+	_ "github.com/jkleinne/synkronus/internal/provider"
+)
+
+// Option configures New.
+type Option func(*options)
+
+type options struct {
+	cfg    config.Config
+	logger *slog.Logger
+}
+
+// WithGCP sets the settings of the GCP provider. Project or Projects is
+// required; credentials default to Application Default Credentials.
+func WithGCP(cfg GCPConfig) Option {
+	return func(o *options) {
+		o.cfg.GCP = &cfg
+	}
+}
+
+// WithAWS sets the settings of the AWS provider. Region is required;
+// credentials default to the SDK's default chain.
+func WithAWS(cfg AWSConfig) Option {
+	return func(o *options) {
+		o.cfg.AWS = &cfg
+	}
+}
+
+// WithLogger sets the logger clients report their calls to at debug level and
+// skipped details at warn level. By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// New creates a client for provider. The options must include the provider's
+// settings (WithGCP or WithAWS), except for Mock, which needs none. Close the
+// client when done.
+func New(ctx context.Context, provider Provider, opts ...Option) (Storage, error) {
+	o := options{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if provider == Mock {
+		o.cfg.Mock = &config.MockConfig{Enabled: true}
+	}
+	if err := config.Validate(&o.cfg); err != nil {
+		return nil, err
+	}
+	return factory.NewFactory(&o.cfg, o.logger).GetStorageProvider(ctx, string(provider))
+}
+
+// Providers returns the providers New supports.
+func Providers() []Provider {
+	names := registry.GetSupportedProviders()
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, Provider(strings.ToUpper(name)))
+	}
+	return providers
+}
+
+// errorClassifier is implemented by every provider client New returns.
+type errorClassifier interface {
+	IsNotFound(err error) bool
+	IsAuthError(err error) bool
+}
+
+// IsNotFound reports whether err, returned by client, means the bucket or
+// object does not exist.
+func IsNotFound(client Storage, err error) bool {
+	c, ok := client.(errorClassifier)
+	return ok && err != nil && c.IsNotFound(err)
+}
+
+// IsAuthError reports whether err, returned by client, means the credentials
+// are missing, were rejected or lack permission.
+func IsAuthError(client Storage, err error) bool {
+	c, ok := client.(errorClassifier)
+	return ok && err != nil && c.IsAuthError(err)
+}
@@ -0,0 +1,71 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jkleinne/synkronus/pkg/storage"
+)
+
+func ExampleNew() {
+	ctx := context.Background()
+	client, err := storage.New(ctx, storage.Mock)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	buckets, err := client.ListBuckets(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, b := range buckets {
+		fmt.Println(b.Name, b.Location)
+	}
+	// Output:
+	// acme-backups EU
+	// acme-data-lake us-central1
+	// acme-web-assets US
+}
+
+func ExampleNew_gcp() {
+	ctx := context.Background()
+	client, err := storage.New(ctx, storage.GCP, storage.WithGCP(storage.GCPConfig{
+		Project: "my-project",
+	}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	bucket, err := client.DescribeBucket(ctx, "my-bucket")
+	if storage.IsNotFound(client, err) {
+		fmt.Println("no such bucket")
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(bucket.Location, storage.FormatBytes(bucket.UsageBytes))
+}
+
+func ExampleStorage_ListObjects() {
+	ctx := context.Background()
+	client, err := storage.New(ctx, storage.Mock)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	list, err := client.ListObjects(ctx, "acme-data-lake", "curated/")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, obj := range list.Objects {
+		fmt.Println(obj.Key, storage.FormatBytes(obj.Size))
+	}
+	// Output:
+	// curated/customers.csv 71.7 KB
+	// curated/orders.csv 204.8 KB
+}
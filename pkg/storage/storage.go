@@ -0,0 +1,83 @@
+// Package storage is the Go API of synkronus's storage layer: one interface
+// for buckets and objects on Google Cloud Storage, Amazon S3 and S3-compatible
+// services, and an in-memory provider for tests. It is what the CLI is built
+// on, without the CLI's config file, caching, retries or audit log.
+//
+// Create a client for a provider with New:
+//
+//	client, err := storage.New(ctx, storage.GCP, storage.WithGCP(storage.GCPConfig{Project: "my-project"}))
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//	buckets, err := client.ListBuckets(ctx)
+//
+// The types are aliases of the ones the CLI uses, so the API follows the CLI's
+// behavior and only grows in backward-compatible ways.
+package storage
+
+import (
+	"github.com/jkleinne/synkronus/internal/config"
+	"github.com/jkleinne/synkronus/internal/domain"
+	"github.com/jkleinne/synkronus/internal/domain/storage"
+)
+
+// Storage is a client for one provider's buckets and objects.
+type Storage = storage.Storage
+
+// Provider identifies a storage provider.
+type Provider = domain.Provider
+
+const (
+	GCP Provider = domain.GCP
+	AWS Provider = domain.AWS
+	// Mock is an in-memory provider holding the same sample buckets and
+	// objects on every run. Changes last as long as the client.
+	Mock Provider = domain.Mock
+)
+
+// Bucket and object descriptions returned by Storage.
+type (
+	Bucket                   = storage.Bucket
+	Object                   = storage.Object
+	ObjectList               = storage.ObjectList
+	Autoclass                = storage.Autoclass
+	Versioning               = storage.Versioning
+	Logging                  = storage.Logging
+	SoftDeletePolicy         = storage.SoftDeletePolicy
+	UniformBucketLevelAccess = storage.UniformBucketLevelAccess
+	Encryption               = storage.Encryption
+	RetentionPolicy          = storage.RetentionPolicy
+	IAMPolicy                = storage.IAMPolicy
+	IAMBinding               = storage.IAMBinding
+	IAMCondition             = storage.IAMCondition
+	PolicyStatement          = storage.PolicyStatement
+	ACLRule                  = storage.ACLRule
+	LifecycleRule            = storage.LifecycleRule
+	LifecycleCondition       = storage.LifecycleCondition
+)
+
+// Parameters and results of Storage calls.
+type (
+	CreateBucketOptions = storage.CreateBucketOptions
+	CreateBucketResult  = storage.CreateBucketResult
+	UploadObjectOptions = storage.UploadObjectOptions
+)
+
+// PublicAccessPrevention values for CreateBucketOptions.
+const (
+	PublicAccessPreventionEnforced  = storage.PublicAccessPreventionEnforced
+	PublicAccessPreventionInherited = storage.PublicAccessPreventionInherited
+)
+
+// Provider settings, as in the gcp and aws blocks of the CLI's config file.
+type (
+	GCPConfig = config.GCPConfig
+	AWSConfig = config.AWSConfig
+)
+
+// FormatBytes formats a size in binary units for display (e.g., "1.5 MB"), or
+// "N/A" for a negative size, which Bucket.UsageBytes uses for unknown usage.
+func FormatBytes(bytes int64) string {
+	return storage.FormatBytes(bytes)
+}
@@ -0,0 +1,107 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jkleinne/synkronus/pkg/storage"
+)
+
+func TestNew_Mock(t *testing.T) {
+	client, err := storage.New(context.Background(), storage.Mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	if client.ProviderName() != storage.Mock {
+		t.Errorf("ProviderName() = %q, want %q", client.ProviderName(), storage.Mock)
+	}
+	buckets, err := client.ListBuckets(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buckets) == 0 {
+		t.Error("expected the mock provider's sample buckets")
+	}
+}
+
+func TestNew_MissingSettings(t *testing.T) {
+	for _, provider := range []storage.Provider{storage.GCP, storage.AWS} {
+		if _, err := storage.New(context.Background(), provider); err == nil {
+			t.Errorf("expected an error creating %s without settings", provider)
+		}
+	}
+}
+
+func TestNew_InvalidSettings(t *testing.T) {
+	_, err := storage.New(context.Background(), storage.GCP, storage.WithGCP(storage.GCPConfig{}))
+	if err == nil || !strings.Contains(err.Error(), "gcp.project") {
+		t.Errorf("expected a validation error naming gcp.project, got %v", err)
+	}
+}
+
+func TestNew_UnsupportedProvider(t *testing.T) {
+	if _, err := storage.New(context.Background(), "azure"); err == nil {
+		t.Error("expected an error for an unsupported provider")
+	}
+}
+
+func TestProviders(t *testing.T) {
+	providers := storage.Providers()
+	for _, want := range []storage.Provider{storage.GCP, storage.AWS, storage.Mock} {
+		if !slices.Contains(providers, want) {
+			t.Errorf("Providers() = %v, missing %s", providers, want)
+		}
+	}
+}
+
+func TestObjectRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	client, err := storage.New(ctx, storage.Mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CreateBucket(ctx, storage.CreateBucketOptions{Name: "library-test", Location: "US"}); err != nil {
+		t.Fatalf("CreateBucket: %v", err)
+	}
+	opts := storage.UploadObjectOptions{BucketName: "library-test", ObjectKey: "notes.txt"}
+	if err := client.UploadObject(ctx, opts, strings.NewReader("hello")); err != nil {
+		t.Fatalf("UploadObject: %v", err)
+	}
+
+	reader, err := client.DownloadObject(ctx, "library-test", "notes.txt")
+	if err != nil {
+		t.Fatalf("DownloadObject: %v", err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil || string(data) != "hello" {
+		t.Errorf("downloaded %q (%v), want %q", data, err, "hello")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	ctx := context.Background()
+	client, err := storage.New(ctx, storage.Mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.DescribeBucket(ctx, "does-not-exist")
+	if !storage.IsNotFound(client, err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if storage.IsAuthError(client, err) {
+		t.Error("a missing bucket is not an auth error")
+	}
+	if storage.IsNotFound(client, nil) {
+		t.Error("nil is not a not found error")
+	}
+}